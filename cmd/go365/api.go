@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// apiMethods are the HTTP methods go365 api sends
+var apiMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+var apiCmd = &cobra.Command{
+	Use:   "api [METHOD] PATH",
+	Short: "Make an authenticated Graph request",
+	Long: `Send a request to any Graph endpoint as the signed-in user and print the JSON
+response, for anything go365 has no command for yet. PATH is relative to the
+Graph API version (see --api-version), or a full Graph URL such as a nextLink.
+METHOD defaults to GET, or POST with --input.
+
+--paginate follows @odata.nextLink and prints every page's items as one
+"value" array. Graph's error code and message are reported on failure, and
+the exit code follows them as for other commands.
+
+Endpoints may need permissions beyond the configured scopes; consent to them
+with 'go365 login --scopes'.`,
+	Example: `  go365 api /me
+  go365 api GET '/me/messages?$top=5&$select=subject' --jq '.value[].subject'
+  go365 api POST /me/sendMail --input body.json
+  go365 api /me/contacts --paginate
+  go365 api PATCH /me/messages/AAMk... --input - <<< '{"isRead": true}'`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+		inputPath, _ := cmd.Flags().GetString("input")
+		paginate, _ := cmd.Flags().GetBool("paginate")
+		headers, _ := cmd.Flags().GetStringArray("header")
+
+		method, path := http.MethodGet, args[0]
+		if inputPath != "" {
+			method = http.MethodPost
+		}
+		if len(args) == 2 {
+			method, path = strings.ToUpper(args[0]), args[1]
+		}
+		if !slices.Contains(apiMethods, method) {
+			return fmt.Errorf("unsupported method %q (use %s)", method, strings.Join(apiMethods, ", "))
+		}
+		if !strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "https://") {
+			path = "/" + path
+		}
+		if paginate && method != http.MethodGet {
+			return fmt.Errorf("--paginate only works with GET")
+		}
+
+		format, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains([]string{"", "json", "yaml", "jq"}, format) {
+			return fmt.Errorf("api prints JSON; use --jq or --output json or yaml")
+		}
+
+		contentType := ""
+		var opts []libgo365.RequestOption
+		for _, header := range headers {
+			name, value, ok := strings.Cut(header, ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("invalid header %q (use Name: value)", header)
+			}
+			name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+			if strings.EqualFold(name, "Content-Type") {
+				contentType = value
+				continue
+			}
+			opts = append(opts, libgo365.WithHeader(name, value))
+		}
+
+		var body io.Reader
+		if inputPath != "" {
+			if method == http.MethodGet || method == http.MethodDelete {
+				return fmt.Errorf("--input needs POST, PUT or PATCH")
+			}
+			body = os.Stdin
+			if inputPath != "-" {
+				f, err := os.Open(inputPath)
+				if err != nil {
+					return fmt.Errorf("failed to open %s: %w", inputPath, err)
+				}
+				defer f.Close()
+				body = f
+			}
+			if contentType == "" {
+				contentType = "application/json"
+			}
+		}
+
+		var data []byte
+		if paginate {
+			data, err = fetchAllPages(cmd, client, path, opts)
+		} else {
+			data, err = client.Do(ctx, method, path, contentType, body, opts...)
+		}
+		if err != nil {
+			return err
+		}
+		return writeAPIResponse(cmd, format, data)
+	},
+}
+
+// fetchAllPages gets path and every page after it, and returns their items
+// as one collection
+func fetchAllPages(cmd *cobra.Command, client *libgo365.Client, path string, opts []libgo365.RequestOption) ([]byte, error) {
+	items := []json.RawMessage{}
+	err := client.GetPages(cmd.Context(), path, func(data []byte) error {
+		var page struct {
+			Value []json.RawMessage `json:"value"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("failed to parse page: %w", err)
+		}
+		items = append(items, page.Value...)
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]any{"value": items})
+}
+
+// writeAPIResponse prints a response body: JSON indented or through --jq or
+// YAML, anything else (such as a $value download) as it came
+func writeAPIResponse(cmd *cobra.Command, format string, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if !json.Valid(data) {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	v := json.RawMessage(data)
+	switch format {
+	case "yaml":
+		return output.WriteYAML(os.Stdout, v)
+	case "jq":
+		query, _ := cmd.Flags().GetString("jq")
+		return output.WriteJQ(os.Stdout, query, v)
+	}
+	return output.WriteJSON(os.Stdout, v)
+}
+
+func init() {
+	apiCmd.Flags().String("input", "", "File with the request body, or - for stdin (sent as JSON unless a Content-Type header is given)")
+	apiCmd.Flags().StringArrayP("header", "H", nil, "Extra request header as 'Name: value' (repeatable), e.g. 'ConsistencyLevel: eventual'")
+	apiCmd.Flags().Bool("paginate", false, "Follow @odata.nextLink and print every page's items as one value array")
+
+	requireGraph(apiCmd)
+
+	rootCmd.AddCommand(apiCmd)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var appCmd = &cobra.Command{
+	Use:   "app",
+	Short: "Audit applications",
+	Long:  `Audit third-party applications and the permissions users have consented to.`,
+}
+
+// grantView is a permission grant with the client and resource names resolved
+type grantView struct {
+	*libgo365.OAuth2PermissionGrant
+	ClientDisplayName   string `json:"clientDisplayName,omitempty"`
+	ResourceDisplayName string `json:"resourceDisplayName,omitempty"`
+}
+
+// appGrantsOutput is the JSON output of 'app grants'
+type appGrantsOutput struct {
+	Grants             []*grantView                  `json:"oauth2PermissionGrants"`
+	AppRoleAssignments []*libgo365.AppRoleAssignment `json:"appRoleAssignments,omitempty"`
+	HasMore            bool                          `json:"hasMore"`
+	NextPageToken      string                        `json:"nextPageToken,omitempty"`
+}
+
+var appGrantsCmd = &cobra.Command{
+	Use:   "grants",
+	Short: "List consented app permissions",
+	Long: `List delegated OAuth2 permission grants (user and admin consent) with app names resolved.
+
+Without --user, all delegated grants in the tenant are listed. With --user, the
+user's own consents and their app role assignments are listed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+		var err error
+		userID, _ := cmd.Flags().GetString("user")
+		pageToken, _ := cmd.Flags().GetString("page-token")
+
+		if userID != "" {
+			userID, err = expandEmail(ctx, client, userID)
+			if err != nil {
+				return err
+			}
+		}
+
+		resp, err := client.ListPermissionGrants(ctx, &libgo365.ListPermissionGrantsOptions{
+			UserID:    userID,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list permission grants: %w", err)
+		}
+
+		// Resolve service principal names once per ID
+		names := map[string]string{}
+		resolve := func(id string) string {
+			if name, ok := names[id]; ok {
+				return name
+			}
+			name := id
+			if sp, err := client.GetServicePrincipal(ctx, id); err == nil && sp.DisplayName != "" {
+				name = sp.DisplayName
+			}
+			names[id] = name
+			return name
+		}
+
+		result := &appGrantsOutput{
+			HasMore:       resp.HasMore,
+			NextPageToken: resp.NextPageToken,
+		}
+		for _, grant := range resp.Grants {
+			result.Grants = append(result.Grants, &grantView{
+				OAuth2PermissionGrant: grant,
+				ClientDisplayName:     resolve(grant.ClientID),
+				ResourceDisplayName:   resolve(grant.ResourceID),
+			})
+		}
+
+		if userID != "" {
+			result.AppRoleAssignments, err = client.ListAppRoleAssignments(ctx, userID)
+			if err != nil {
+				return fmt.Errorf("failed to list app role assignments: %w", err)
+			}
+		}
+
+		format, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "json", "yaml", "jq":
+			_, err := renderItem(cmd, result, nil)
+			return err
+		case "table", "template", "quiet", "field":
+			if _, err := renderList(cmd, result.Grants, len(result.Grants), result.NextPageToken, permissionGrantColumns); err != nil {
+				return err
+			}
+			if userID != "" && format == "table" {
+				fmt.Println()
+				_, err = renderList(cmd, result.AppRoleAssignments, len(result.AppRoleAssignments), "", appRoleAssignmentColumns)
+			}
+			return err
+		}
+
+		if len(result.Grants) == 0 {
+			fmt.Println("No delegated permission grants found")
+		} else {
+			fmt.Printf("Delegated permission grants (%d):\n\n", len(result.Grants))
+			for _, g := range result.Grants {
+				consent := "admin consent (all users)"
+				if g.ConsentType == "Principal" {
+					consent = "user consent: " + g.PrincipalID
+				}
+				fmt.Printf("%s -> %s\n", g.ClientDisplayName, g.ResourceDisplayName)
+				fmt.Printf("   Consent: %s\n", consent)
+				fmt.Printf("   Scopes: %s\n", strings.Join(g.Scopes(), ", "))
+				fmt.Println()
+			}
+		}
+
+		if userID != "" {
+			if len(result.AppRoleAssignments) == 0 {
+				fmt.Println("No app role assignments found")
+			} else {
+				fmt.Printf("App role assignments (%d):\n\n", len(result.AppRoleAssignments))
+				for _, a := range result.AppRoleAssignments {
+					fmt.Printf("%s\n", a.ResourceDisplayName)
+					fmt.Printf("   Role ID: %s\n", a.AppRoleID)
+					if a.CreatedDateTime != nil {
+						fmt.Printf("   Assigned: %s\n", a.CreatedDateTime.Format("2006-01-02"))
+					}
+					fmt.Println()
+				}
+			}
+		}
+
+		output.PrintNextPageHint(os.Stdout, result.NextPageToken)
+		return nil
+	},
+}
+
+func init() {
+	requireGraph(appGrantsCmd)
+
+	appGrantsCmd.Flags().String("user", "", "Only grants consented by this user (email or ID)")
+	appGrantsCmd.Flags().String("page-token", "", "Pagination token from previous response")
+	appCmd.AddCommand(appGrantsCmd)
+
+	rootCmd.AddCommand(appCmd)
+}
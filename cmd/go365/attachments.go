@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+var attachmentColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Path: "id"},
+		{Name: "name", Path: "name"},
+		{Name: "type", Path: "contentType"},
+		{Name: "size", Path: "size"},
+		{Name: "inline", Path: "isInline"},
+		{Name: "modified", Path: "lastModifiedDateTime"},
+	},
+	Defaults: []string{"name", "type", "size"},
+}
+
+// openWith hands a URL or file to the system handler. The launcher's own
+// output is discarded so it can't mix with go365's.
+func openWith(target string, isFile bool) error {
+	browser.Stdout = io.Discard
+	browser.Stderr = io.Discard
+	if isFile {
+		return browser.OpenFile(target)
+	}
+	return browser.OpenURL(target)
+}
+
+// openWebLink opens an item's Outlook on the web link in the browser
+func openWebLink(link, what string) error {
+	if link == "" {
+		return fmt.Errorf("%s has no web link", what)
+	}
+	fmt.Fprintf(os.Stderr, "Opening %s\n", link)
+	if err := openWith(link, false); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+	return nil
+}
+
+// attachmentFileName makes an attachment name safe to use as a file name
+func attachmentFileName(a *libgo365.Attachment) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, a.Name)
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		name = "attachment"
+	}
+	if a.ODataType == libgo365.ItemAttachmentType && filepath.Ext(name) == "" {
+		// Attached messages and events download as MIME
+		name += ".eml"
+	}
+	return name
+}
+
+// openAttachments opens files for --attach, typing them by extension. The
+// returned function closes them.
+func openAttachments(paths []string) ([]*libgo365.AttachmentFile, func(), error) {
+	var files []*libgo365.AttachmentFile
+	var opened []*os.File
+	closeAll := func() {
+		for _, f := range opened {
+			f.Close()
+		}
+	}
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+		}
+		opened = append(opened, f)
+		info, err := f.Stat()
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("failed to read attachment: %w", err)
+		}
+		if info.IsDir() {
+			closeAll()
+			return nil, nil, fmt.Errorf("%s is a directory", path)
+		}
+		if info.Size() > libgo365.MaxAttachmentSize {
+			closeAll()
+			return nil, nil, fmt.Errorf("%s is %s; attachments are limited to %s", path, formatBytes(info.Size()), formatBytes(libgo365.MaxAttachmentSize))
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(path))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		files = append(files, &libgo365.AttachmentFile{
+			Name:        filepath.Base(path),
+			ContentType: contentType,
+			Size:        info.Size(),
+			Content:     f,
+		})
+	}
+	return files, closeAll, nil
+}
+
+var mailAttachmentsCmd = &cobra.Command{
+	Use:   "attachments <message-id>",
+	Short: "List or open a message's attachments",
+	Long: `List a message's attachments, numbered from 1.
+
+With --open N, attachment N is downloaded to a temporary file and opened
+with the system's default application.`,
+	Example: `  go365 mail attachments AAMk...
+  go365 mail attachments AAMk... --open 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		messageID, err := resolveRef("messages", args[0])
+		if err != nil {
+			return err
+		}
+		open, _ := cmd.Flags().GetInt("open")
+		if cmd.Flags().Changed("open") && open < 1 {
+			return fmt.Errorf("--open takes an attachment number starting at 1")
+		}
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		attachments, err := client.ListAttachments(ctx, messageID)
+		if err != nil {
+			return fmt.Errorf("failed to list attachments: %w", err)
+		}
+
+		if open > 0 {
+			if open > len(attachments) {
+				return fmt.Errorf("message has %d attachment(s); there is no attachment %d", len(attachments), open)
+			}
+			attachment := attachments[open-1]
+			if attachment.ODataType == libgo365.ReferenceAttachmentType {
+				return fmt.Errorf("%s is a link to a cloud file, not a downloadable attachment", attachment.Name)
+			}
+
+			data, err := client.GetAttachmentContent(ctx, messageID, attachment.ID)
+			if err != nil {
+				return fmt.Errorf("failed to download attachment: %w", err)
+			}
+
+			// A private directory per download keeps the original file name
+			// without colliding with earlier downloads
+			dir, err := os.MkdirTemp("", "go365-attachment-")
+			if err != nil {
+				return fmt.Errorf("failed to create temporary directory: %w", err)
+			}
+			path := filepath.Join(dir, attachmentFileName(attachment))
+			if err := os.WriteFile(path, data, 0600); err != nil {
+				return fmt.Errorf("failed to save attachment: %w", err)
+			}
+
+			fmt.Fprintf(os.Stderr, "Opening %s\n", path)
+			if err := openWith(path, true); err != nil {
+				return fmt.Errorf("failed to open %s: %w", path, err)
+			}
+			return nil
+		}
+
+		if handled, err := renderList(cmd, attachments, len(attachments), "", attachmentColumns); handled {
+			return err
+		}
+
+		if len(attachments) == 0 {
+			fmt.Println("No attachments")
+			return nil
+		}
+		for i, a := range attachments {
+			inline := ""
+			if a.IsInline {
+				inline = output.Dim(" inline")
+			}
+			fmt.Printf("%d. %s (%s, %s)%s\n", i+1, a.Name, a.ContentType, formatBytes(a.Size), inline)
+		}
+		return nil
+	},
+}
+
+func init() {
+	requireGraph(mailAttachmentsCmd)
+	requireScopes([]string{"Mail.Read"}, mailAttachmentsCmd)
+
+	mailAttachmentsCmd.Flags().Int("open", 0, "Download attachment N (from the list) and open it")
+
+	mailCmd.AddCommand(mailAttachmentsCmd)
+}
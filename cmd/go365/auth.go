@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// tokenOutput is an access token with what it decodes to, for --json
+type tokenOutput struct {
+	Token     string                `json:"token"`
+	ExpiresOn time.Time             `json:"expiresOn"`
+	Claims    *libgo365.TokenClaims `json:"claims,omitempty"`
+}
+
+var tokenColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "token", Path: "token"},
+		{Name: "expires", Path: "expiresOn"},
+		{Name: "user", Path: "claims.username"},
+		{Name: "app", Path: "claims.appId"},
+		{Name: "tenant", Path: "claims.tenantId"},
+		{Name: "audience", Path: "claims.audience"},
+		{Name: "scopes", Path: "claims.scopes"},
+		{Name: "roles", Path: "claims.roles"},
+	},
+	Defaults: []string{"expires", "user", "audience", "scopes", "roles"},
+}
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Work with go365's access tokens",
+}
+
+var authTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Print an access token for scripts",
+	Long: `Print an access token for the signed-in account, refreshing it if needed,
+so scripts and other tools can call Graph directly.
+
+The token is for the configured scopes unless --scopes is given. Tokens can
+only be issued for permissions already consented to; in app-only,
+azidentity and managed-identity modes a scope must be a resource's /.default
+scope.
+
+--decode shows who the token was issued to and the scopes (delegated
+permissions) or roles (application permissions) it carries, instead of the
+token itself. --json prints both, with the expiry time.
+
+Anyone holding the token can act as you until it expires. Don't log it.`,
+	Example: `  curl -H "Authorization: Bearer $(go365 auth token)" https://graph.microsoft.com/v1.0/me
+  go365 auth token --decode
+  go365 auth token --scopes Mail.Read --json
+  go365 auth token --scopes https://management.azure.com/.default`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scopes, _ := cmd.Flags().GetStringSlice("scopes")
+		decode, _ := cmd.Flags().GetBool("decode")
+
+		config, err := configMgr.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		auth, err := newAuthenticator(config)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		if !auth.IsAuthenticated(ctx) {
+			return errNotAuthenticated
+		}
+		token, err := auth.GetTokenForScopes(ctx, scopes)
+		if err != nil {
+			return fmt.Errorf("failed to get access token: %w", err)
+		}
+
+		result := &tokenOutput{Token: token.Token, ExpiresOn: token.ExpiresOn}
+		// Personal account tokens are opaque; there is nothing to decode
+		claims, claimsErr := libgo365.ParseTokenClaims(token.Token)
+		if claimsErr == nil {
+			result.Claims = claims
+		}
+
+		if handled, err := renderItem(cmd, result, tokenColumns); handled {
+			return err
+		}
+		if !decode {
+			fmt.Println(token.Token)
+			return nil
+		}
+		if claimsErr != nil {
+			return fmt.Errorf("cannot decode token: %w", claimsErr)
+		}
+
+		display := displaySettings(ctx, nil, config)
+		fmt.Printf("Expires: %s (in %s)\n", display.Format(token.ExpiresOn), time.Until(token.ExpiresOn).Round(time.Minute))
+		if claims.Username != "" {
+			fmt.Printf("User: %s\n", claims.Username)
+		}
+		if claims.AppName != "" {
+			fmt.Printf("App: %s (%s)\n", claims.AppName, claims.AppID)
+		} else if claims.AppID != "" {
+			fmt.Printf("App: %s\n", claims.AppID)
+		}
+		fmt.Printf("Tenant: %s\n", claims.TenantID)
+		fmt.Printf("Audience: %s\n", claims.Audience)
+		if len(claims.Scopes) > 0 {
+			fmt.Printf("Scopes: %s\n", strings.Join(claims.Scopes, " "))
+		}
+		if len(claims.Roles) > 0 {
+			fmt.Printf("Roles: %s\n", strings.Join(claims.Roles, " "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	authTokenCmd.Flags().StringSlice("scopes", nil, "Scopes to request instead of the configured ones (comma-separated or repeated)")
+	authTokenCmd.Flags().Bool("decode", false, "Show the token's user, app, expiry, scopes and roles instead of the token")
+
+	authCmd.AddCommand(authTokenCmd)
+	rootCmd.AddCommand(authCmd)
+}
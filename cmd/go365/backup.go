@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/njt/go365/internal/dateparse"
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var backupStatsColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "saved", Path: "saved"},
+		{Name: "unchanged", Path: "unchanged"},
+		{Name: "removed", Path: "removed"},
+	},
+	Defaults: []string{"saved", "unchanged", "removed"},
+}
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up mail, calendar or OneDrive to local files",
+	Long: `Back up mail, calendar or OneDrive to a local directory.
+
+Each kind goes in its own subdirectory of --out with a manifest.json that
+records what has been saved and where Graph's delta query left off. Running
+the same backup again only fetches what changed, and a backup that was
+interrupted carries on from the last page it finished.
+
+  mail/      One .eml file per message, in a directory per folder
+  calendar/  One .ics file per event, plus Graph's JSON for restoring
+  drive/     Your OneDrive files under files/, in their folders
+
+Use 'go365 restore' to import a backup again.`,
+	Example: `  go365 backup mail --out ./backup
+  go365 backup mail --folder inbox --folder sentitems
+  go365 backup calendar --since "1 year ago"
+  go365 backup drive --out /mnt/backups/onedrive`,
+}
+
+var backupMailCmd = &cobra.Command{
+	Use:   "mail",
+	Short: "Back up messages as .eml files",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		folders, _ := cmd.Flags().GetStringSlice("folder")
+		return runBackup(cmd, libgo365.BackupMail, func(ctx context.Context, opts *libgo365.BackupOptions) (*libgo365.BackupStats, error) {
+			opts.Folders = folders
+			return graphClient.BackupMail(ctx, opts)
+		})
+	},
+}
+
+var backupCalendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "Back up events as .ics files",
+	Long: `Back up the events in a date window as .ics files.
+
+The window defaults to ten years ago until two years from now. Recurring
+events are saved one occurrence at a time. Changing the window starts the
+delta query over.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var start, end time.Time
+		now := time.Now()
+		if since, _ := cmd.Flags().GetString("since"); since != "" {
+			var err error
+			if start, _, err = dateparse.ParseRange(since, now); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+		}
+		if until, _ := cmd.Flags().GetString("until"); until != "" {
+			var err error
+			if _, end, err = dateparse.ParseRange(until, now); err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+		}
+
+		return runBackup(cmd, libgo365.BackupCalendar, func(ctx context.Context, opts *libgo365.BackupOptions) (*libgo365.BackupStats, error) {
+			opts.Start, opts.End = start, end
+			return graphClient.BackupCalendar(ctx, opts)
+		})
+	},
+}
+
+var backupDriveCmd = &cobra.Command{
+	Use:   "drive",
+	Short: "Back up your OneDrive files",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBackup(cmd, libgo365.BackupDrive, func(ctx context.Context, opts *libgo365.BackupOptions) (*libgo365.BackupStats, error) {
+			return graphClient.BackupDrive(ctx, opts)
+		})
+	},
+}
+
+// runBackup runs one kind of backup into its directory under --out, listing
+// each saved item on stderr for people and reporting the totals
+func runBackup(cmd *cobra.Command, kind string, backup func(context.Context, *libgo365.BackupOptions) (*libgo365.BackupStats, error)) error {
+	out, _ := cmd.Flags().GetString("out")
+	dir := filepath.Join(out, kind)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	opts := &libgo365.BackupOptions{Dir: dir}
+	if format == "" {
+		opts.Progress = func(item *libgo365.BackupItem) {
+			fmt.Fprintf(os.Stderr, "  %s\n", item.File)
+		}
+	}
+
+	stats, err := backup(cmd.Context(), opts)
+	if err != nil {
+		return fmt.Errorf("backup stopped (run it again to resume): %w", err)
+	}
+
+	if handled, err := renderItem(cmd, stats, backupStatsColumns); handled {
+		return err
+	}
+	fmt.Printf("Backed up %s to %s: %d saved, %d unchanged, %d removed\n", kind, dir, stats.Saved, stats.Unchanged, stats.Removed)
+	return nil
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Import a backup made with 'go365 backup'",
+	Long: `Import a backup made with 'go365 backup' into the signed-in account.
+
+Restores never overwrite anything. Mail goes into a new folder, with the
+backed-up folder tree recreated inside it; files go into a new OneDrive folder.
+Both are named "Restored YYYY-MM-DD" unless --to is given. Events are added
+to the main calendar, or the calendar ID given with --to.
+
+Graph imports messages as drafts, and restored events have no attendees so
+that no invitations are sent.`,
+	Example: `  go365 restore mail --in ./backup
+  go365 restore calendar --in ./backup --to AAMkCalendar...
+  go365 restore drive --in ./backup --to "Old laptop"`,
+}
+
+var restoreMailCmd = &cobra.Command{
+	Use:   "mail",
+	Short: "Import backed-up messages into a new mail folder",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRestore(cmd, libgo365.BackupMail, graphClient.RestoreMail)
+	},
+}
+
+var restoreCalendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "Recreate backed-up events",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRestore(cmd, libgo365.BackupCalendar, graphClient.RestoreCalendar)
+	},
+}
+
+var restoreDriveCmd = &cobra.Command{
+	Use:   "drive",
+	Short: "Upload backed-up files to a new OneDrive folder",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRestore(cmd, libgo365.BackupDrive, graphClient.RestoreDrive)
+	},
+}
+
+// runRestore confirms and runs one kind of restore from its directory under --in
+func runRestore(cmd *cobra.Command, kind string, restore func(context.Context, *libgo365.RestoreOptions) (*libgo365.BackupStats, error)) error {
+	in, _ := cmd.Flags().GetString("in")
+	to, _ := cmd.Flags().GetString("to")
+	dir := filepath.Join(in, kind)
+
+	manifest, err := libgo365.LoadBackupManifest(dir, kind)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, item := range manifest.Items {
+		if item.File != "" {
+			names = append(names, item.File)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no %s backup found in %s", kind, dir)
+	}
+	if err := confirm(cmd, fmt.Sprintf("Restore %d item(s) from %s", len(names), dir), names); err != nil {
+		return err
+	}
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	opts := &libgo365.RestoreOptions{Dir: dir, Target: to}
+	if format == "" {
+		opts.Progress = func(item *libgo365.BackupItem) {
+			fmt.Fprintf(os.Stderr, "  %s\n", item.File)
+		}
+	}
+
+	stats, err := restore(cmd.Context(), opts)
+	if stats != nil && format == "" {
+		fmt.Printf("Restored %d of %d item(s) from %s\n", stats.Saved, len(names), dir)
+	}
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+
+	if handled, err := renderItem(cmd, stats, backupStatsColumns); handled {
+		return err
+	}
+	return nil
+}
+
+func init() {
+	requireGraph(backupMailCmd, backupCalendarCmd, backupDriveCmd, restoreMailCmd, restoreCalendarCmd, restoreDriveCmd)
+	requireScopes([]string{"Mail.Read"}, backupMailCmd)
+	requireScopes([]string{"Calendars.Read"}, backupCalendarCmd)
+	requireScopes([]string{"Files.Read"}, backupDriveCmd)
+	requireScopes([]string{"Mail.ReadWrite"}, restoreMailCmd)
+	requireScopes([]string{"Calendars.ReadWrite"}, restoreCalendarCmd)
+	requireScopes([]string{"Files.ReadWrite"}, restoreDriveCmd)
+
+	backupCmd.PersistentFlags().String("out", "backup", "Directory to write the backup to")
+	backupMailCmd.Flags().StringSlice("folder", nil, "Mail folder ID or well-known name to back up (repeatable; default every folder and subfolder)")
+	backupCalendarCmd.Flags().String("since", "", "Start of the window to back up, e.g. 2024-01-01 or \"1 year ago\" (default ten years ago)")
+	backupCalendarCmd.Flags().String("until", "", "End of the window to back up (default two years from now)")
+
+	restoreCmd.PersistentFlags().String("in", "backup", "Directory holding the backup")
+	restoreCmd.PersistentFlags().String("to", "", "Mail folder name, calendar ID or OneDrive folder to restore into")
+
+	backupCmd.AddCommand(backupMailCmd, backupCalendarCmd, backupDriveCmd)
+	restoreCmd.AddCommand(restoreMailCmd, restoreCalendarCmd, restoreDriveCmd)
+	rootCmd.AddCommand(backupCmd, restoreCmd)
+}
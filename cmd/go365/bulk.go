@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// maxBulkInputSize bounds how much bulk input is read
+const maxBulkInputSize = 64 << 20
+
+// bulkResult is the output line written for each input operation
+type bulkResult struct {
+	Line   int    `json:"line"`
+	Op     string `json:"op,omitempty"`
+	ID     string `json:"id,omitempty"`
+	OK     bool   `json:"ok"`
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// bulkItem is a parsed input line and its Graph request
+type bulkItem struct {
+	line    int
+	op      *libgo365.BulkOp
+	request *libgo365.BatchRequest
+}
+
+var bulkCmd = &cobra.Command{
+	Use:   "bulk <file|->",
+	Short: "Run operations from JSON lines",
+	Long: `Read one JSON operation per line from a file (or - for stdin), validate them
+all, then run them through Graph's $batch endpoint, 20 to a request.
+
+Each input line produces one JSON result line on stdout, in input order:
+
+  {"line":1,"op":"mail.move","id":"AAMk...","ok":true,"status":201}
+
+Supported operations:
+
+  {"op":"mail.move","id":"...","folder":"archive"}
+  {"op":"mail.copy","id":"...","folder":"<folder-id>"}
+  {"op":"mail.delete","id":"..."}
+  {"op":"mail.permanentDelete","id":"..."}
+  {"op":"mail.markRead","id":"..."}
+  {"op":"mail.markUnread","id":"..."}
+  {"op":"calendar.respond","id":"...","response":"accept","comment":"optional"}
+  {"op":"calendar.delete","id":"..."}
+  {"op":"calendar.cancel","id":"...","comment":"optional"}
+
+Pretty-printed objects are accepted too; results report the line each one
+starts on. If any operation is invalid, nothing is run.`,
+	Example: `  go365 mail list --jq '.value[] | {op: "mail.markRead", id}' | go365 bulk -
+  go365 bulk ops.jsonl --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		input := io.Reader(os.Stdin)
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", args[0], err)
+			}
+			defer f.Close()
+			input = f
+		}
+
+		items, invalid, err := readBulkOps(input)
+		if err != nil {
+			return err
+		}
+
+		out := json.NewEncoder(os.Stdout)
+		if len(invalid) > 0 {
+			for _, result := range invalid {
+				out.Encode(result)
+			}
+			return fmt.Errorf("%d invalid operation(s); nothing was run", len(invalid))
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		if dryRun {
+			for _, item := range items {
+				out.Encode(map[string]interface{}{
+					"line":   item.line,
+					"op":     item.op.Op,
+					"id":     item.op.ID,
+					"method": item.request.Method,
+					"url":    item.request.URL,
+				})
+			}
+			return nil
+		}
+
+		summary := make([]string, len(items))
+		for i, item := range items {
+			summary[i] = item.op.Op + " " + item.op.ID
+		}
+		if err := confirm(cmd, fmt.Sprintf("Run %d bulk operation(s)", len(items)), summary); err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		if err := connectGraph(ctx, commandScopes(cmd)...); err != nil {
+			return err
+		}
+		client := graphClient
+
+		results := runBulk(ctx, client, items, concurrency)
+		failed := 0
+		for _, result := range results {
+			if !result.OK {
+				failed++
+			}
+			out.Encode(result)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed %d of %d operations", failed, len(results))
+		}
+		return nil
+	},
+}
+
+// readBulkOps parses and validates every operation. Input is a stream of JSON
+// objects, normally one per line, though pretty-printed objects (such as --jq
+// output) work too. Invalid operations are returned as failed results so they
+// can all be reported at once.
+func readBulkOps(r io.Reader) ([]*bulkItem, []*bulkResult, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBulkInputSize+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read operations: %w", err)
+	}
+	if len(data) > maxBulkInputSize {
+		return nil, nil, fmt.Errorf("bulk input exceeds %d MiB", maxBulkInputSize>>20)
+	}
+
+	var items []*bulkItem
+	var invalid []*bulkResult
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	for {
+		line := lineAt(data, decoder.InputOffset())
+		op := &libgo365.BulkOp{}
+		err := decoder.Decode(op)
+		if err == io.EOF {
+			break
+		}
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+			// The stream can't be resynchronized after a syntax error
+			invalid = append(invalid, &bulkResult{Line: lineAt(data, decoder.InputOffset()), Error: fmt.Sprintf("invalid JSON: %v", err)})
+			break
+		}
+		if err != nil {
+			invalid = append(invalid, &bulkResult{Line: line, Op: op.Op, ID: op.ID, Error: err.Error()})
+			continue
+		}
+
+		request, err := op.Request()
+		if err != nil {
+			invalid = append(invalid, &bulkResult{Line: line, Op: op.Op, ID: op.ID, Error: err.Error()})
+			continue
+		}
+		items = append(items, &bulkItem{line: line, op: op, request: request})
+	}
+	return items, invalid, nil
+}
+
+// lineAt returns the 1-based line of the first non-space byte at or after offset
+func lineAt(data []byte, offset int64) int {
+	for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n", rune(data[offset])) {
+		offset++
+	}
+	return bytes.Count(data[:min(offset, int64(len(data)))], []byte("\n")) + 1
+}
+
+// runBulk sends the operations in $batch calls, up to concurrency at once, and
+// returns one result per item in input order
+func runBulk(ctx context.Context, client *libgo365.Client, items []*bulkItem, concurrency int) []*bulkResult {
+	results := make([]*bulkResult, len(items))
+	chunks := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range chunks {
+				end := min(start+libgo365.MaxBatchSize, len(items))
+				runBulkChunk(ctx, client, items[start:end], results[start:end])
+			}
+		}()
+	}
+
+	for start := 0; start < len(items); start += libgo365.MaxBatchSize {
+		chunks <- start
+	}
+	close(chunks)
+	wg.Wait()
+
+	return results
+}
+
+// runBulkChunk runs up to MaxBatchSize items in one $batch call. Items Graph
+// throttles are sent again by BatchWithRetry, after their Retry-After, up to
+// --max-retries times while the client's retry budget lasts.
+func runBulkChunk(ctx context.Context, client *libgo365.Client, items []*bulkItem, results []*bulkResult) {
+	requests := make([]*libgo365.BatchRequest, len(items))
+	for i, item := range items {
+		requests[i] = item.request
+		results[i] = &bulkResult{Line: item.line, Op: item.op.Op, ID: item.op.ID}
+	}
+
+	responses, err := client.BatchWithRetry(ctx, requests)
+	for i, result := range results {
+		if err != nil {
+			result.Error = err.Error()
+			result.Status = libgo365.StatusCode(err)
+			continue
+		}
+		result.Status = responses[i].Status
+		if respErr := responses[i].Err(); respErr != nil {
+			result.Error = bulkErrorMessage(respErr)
+			continue
+		}
+		result.OK = true
+	}
+}
+
+// bulkErrorMessage prefers Graph's own message over the raw response body
+func bulkErrorMessage(err error) string {
+	var gerr *libgo365.GraphError
+	if errors.As(err, &gerr) && gerr.Message != "" {
+		if gerr.Code != "" {
+			return gerr.Code + ": " + gerr.Message
+		}
+		return gerr.Message
+	}
+	return err.Error()
+}
+
+func init() {
+	// Connected after validation, so a dry run needs no sign-in
+	requireScopes([]string{"Mail.ReadWrite", "Calendars.ReadWrite"}, bulkCmd)
+	bulkCmd.Flags().Bool("dry-run", false, "Validate and print the Graph requests without running them")
+
+	rootCmd.AddCommand(bulkCmd)
+}
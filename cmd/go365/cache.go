@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// defaultCacheTTL is how long list and get responses are reused. It is short:
+// the cache is for bursts of similar commands, not offline use.
+const defaultCacheTTL = time.Minute
+
+// cacheRevalidate is how long expired entries with an ETag or Last-Modified
+// are kept, so scripts listing the same mail or calendar through the day get
+// a 304 from Graph instead of downloading it again
+const cacheRevalidate = 24 * time.Hour
+
+var (
+	// cacheDisabled and cacheRefresh are set from --no-cache and --refresh
+	cacheDisabled bool
+	cacheRefresh  bool
+)
+
+// setupCache records the cache flags; GO365_NO_CACHE also disables the cache
+func setupCache(noCache, refresh bool) {
+	cacheDisabled = noCache || os.Getenv("GO365_NO_CACHE") != ""
+	cacheRefresh = refresh
+}
+
+// cacheRoot is where cached Graph responses are kept
+func cacheRoot() string {
+	return filepath.Join(configMgr.Dir(), "cache")
+}
+
+// accountKey identifies the tenant, app registration and, when known, the
+// signed-in account for per-account files
+func accountKey(config *libgo365.Config, account string) string {
+	sum := sha256.Sum256([]byte(config.TenantID + "\x00" + config.ClientID + "\x00" + account))
+	return hex.EncodeToString(sum[:8])
+}
+
+// signedInAccount returns the object ID of the user or app tokens are for,
+// or "" when the token can't be read, as with personal accounts' opaque
+// tokens
+func signedInAccount(ctx context.Context, tokens libgo365.TokenProvider) string {
+	token, err := tokens.Token(ctx)
+	if err != nil {
+		return ""
+	}
+	claims, err := libgo365.ParseTokenClaims(token)
+	if err != nil {
+		return ""
+	}
+	if claims.ObjectID != "" {
+		return claims.ObjectID
+	}
+	return claims.Username
+}
+
+// clearAccountCaches deletes cached responses and mailbox settings, so the
+// next account to sign in never sees the last one's. Keys only tell
+// accounts apart when their tokens can be read.
+func clearAccountCaches() {
+	os.RemoveAll(cacheRoot())
+	os.Remove(mailboxSettingsPath())
+}
+
+// newClient creates a Graph client paced by the shared rate limiter, retrying
+// up to --max-retries times, whose GET requests read through the response
+// cache and whose writes are recorded in the history. Cache entries are kept
+// per tenant, app registration and account, so neither a project config
+// pointing elsewhere nor another user signing in sees someone else's data.
+func newClient(ctx context.Context, config *libgo365.Config, tokens libgo365.TokenProvider) (*libgo365.Client, error) {
+	t, err := httpTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	client := libgo365.NewClientWithTokenProvider(ctx, tokens)
+	client.SetTransport(t)
+	client.SetRequestHook(recordWrite)
+	client.SetMaxRetries(maxRetries)
+	if config.APIVersion != "" {
+		// A hand-edited version Graph doesn't have is left at v1.0
+		client.SetAPIVersion(config.APIVersion)
+	}
+	if limiter != nil {
+		client.SetRateLimiter(limiter)
+	}
+	if cacheDisabled {
+		return client, nil
+	}
+
+	cache := libgo365.NewResponseCache(filepath.Join(cacheRoot(), accountKey(config, signedInAccount(ctx, tokens))), defaultCacheTTL)
+	cache.Refresh = cacheRefresh
+	cache.Revalidate = cacheRevalidate
+	client.SetCache(cache)
+	return client, nil
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the response cache",
+	Long:  `List and get responses are cached under ~/.go365/cache for a minute so repeated commands don't hit Graph again. Responses Graph gave an ETag or Last-Modified are kept for a day after that and checked with a conditional request, so an unchanged list isn't downloaded again. Use --refresh to bypass the cache for one command or --no-cache to turn it off.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all cached responses",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := os.RemoveAll(cacheRoot()); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		if handled, err := renderItem(cmd, output.FormatActionResponse(true, "Cache cleared"), nil); handled {
+			return err
+		}
+		fmt.Println("Cache cleared")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Don't read or write the response cache (also GO365_NO_CACHE)")
+	rootCmd.PersistentFlags().Bool("refresh", false, "Fetch fresh data, updating the response cache")
+
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
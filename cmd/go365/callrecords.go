@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/njt/go365/internal/dateparse"
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var callRecordsCmd = &cobra.Command{
+	Use:   "callrecords",
+	Short: "Access Teams call records",
+	Long: `Access Teams call and meeting records for call-quality reporting.
+
+Requires the CallRecords.Read.All application permission.`,
+}
+
+var callRecordsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List call records",
+	Long:  `List call records in the tenant, optionally limited to a date range.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+
+		startStr, _ := cmd.Flags().GetString("start")
+		endStr, _ := cmd.Flags().GetString("end")
+		pageToken, _ := cmd.Flags().GetString("page-token")
+
+		opts := &libgo365.ListCallRecordsOptions{PageToken: pageToken}
+
+		now := time.Now()
+		if startStr != "" {
+			startTime, err := dateparse.Parse(startStr, now)
+			if err != nil {
+				return fmt.Errorf("invalid start date: %w", err)
+			}
+			opts.StartTime = &startTime
+		}
+		if endStr != "" {
+			endTime, err := dateparse.Parse(endStr, now)
+			if err != nil {
+				return fmt.Errorf("invalid end date: %w", err)
+			}
+			opts.EndTime = &endTime
+		}
+
+		resp, err := client.ListCallRecords(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list call records: %w", err)
+		}
+
+		if handled, err := renderList(cmd, resp.Records, resp.Count, resp.NextPageToken, callRecordColumns); handled {
+			return err
+		}
+
+		if len(resp.Records) == 0 {
+			fmt.Println("No call records found")
+			return nil
+		}
+
+		display := displaySettings(ctx, client, config)
+		for _, record := range resp.Records {
+			printCallRecord(record, display)
+			fmt.Println("---")
+		}
+		output.PrintNextPageHint(os.Stdout, resp.NextPageToken)
+
+		return nil
+	},
+}
+
+var callRecordsGetCmd = &cobra.Command{
+	Use:   "get <record-id>",
+	Short: "Get a call record",
+	Long:  `Get a call record with its sessions, segments, and media quality metrics.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+		noSessions, _ := cmd.Flags().GetBool("no-sessions")
+
+		record, err := client.GetCallRecord(ctx, args[0], !noSessions)
+		if err != nil {
+			return fmt.Errorf("failed to get call record: %w", err)
+		}
+
+		if handled, err := renderItem(cmd, record, callRecordColumns); handled {
+			return err
+		}
+
+		display := displaySettings(ctx, client, config)
+		printCallRecord(record, display)
+
+		for _, session := range record.Sessions {
+			fmt.Printf("\nSession %s: %s -> %s", session.ID, callEndpointName(session.Caller), callEndpointName(session.Callee))
+			if len(session.Modalities) > 0 {
+				fmt.Printf(" (%s)", strings.Join(session.Modalities, ", "))
+			}
+			fmt.Println()
+			if session.FailureInfo != nil {
+				fmt.Printf("   Failure: %s (%s)\n", session.FailureInfo.Reason, session.FailureInfo.Stage)
+			}
+			for _, segment := range session.Segments {
+				fmt.Printf("   Segment %s: %s -> %s\n", segment.ID, callEndpointName(segment.Caller), callEndpointName(segment.Callee))
+				if segment.FailureInfo != nil {
+					fmt.Printf("      Failure: %s (%s)\n", segment.FailureInfo.Reason, segment.FailureInfo.Stage)
+				}
+				for _, media := range segment.Media {
+					for _, stream := range media.Streams {
+						fmt.Printf("      %s %s:", media.Label, stream.StreamDirection)
+						if stream.AverageJitter != "" {
+							fmt.Printf(" jitter %s", stream.AverageJitter)
+						}
+						if stream.AverageRoundTripTime != "" {
+							fmt.Printf(" rtt %s", stream.AverageRoundTripTime)
+						}
+						if stream.AveragePacketLossRate != nil {
+							fmt.Printf(" loss %.2f%%", *stream.AveragePacketLossRate*100)
+						}
+						fmt.Println()
+					}
+				}
+			}
+		}
+
+		return nil
+	},
+}
+
+// printCallRecord prints the summary fields of a call record
+func printCallRecord(record *libgo365.CallRecord, display *timeDisplay) {
+	fmt.Printf("ID: %s\n", record.ID)
+	fmt.Printf("Type: %s\n", record.Type)
+	if len(record.Modalities) > 0 {
+		fmt.Printf("Modalities: %s\n", strings.Join(record.Modalities, ", "))
+	}
+	if record.Organizer != nil {
+		fmt.Printf("Organizer: %s\n", record.Organizer.Name())
+	}
+	fmt.Printf("Start: %s\n", formatScheduleTime(record.StartDateTime, display))
+	if record.StartDateTime != nil && record.EndDateTime != nil {
+		fmt.Printf("Duration: %s\n", record.EndDateTime.Sub(*record.StartDateTime).Round(time.Second))
+	}
+	if len(record.Participants) > 0 {
+		names := make([]string, 0, len(record.Participants))
+		for _, p := range record.Participants {
+			names = append(names, p.Name())
+		}
+		fmt.Printf("Participants: %s\n", strings.Join(names, ", "))
+	}
+}
+
+// callEndpointName returns a printable name for a possibly-missing endpoint
+func callEndpointName(e *libgo365.CallEndpoint) string {
+	if e == nil {
+		return "unknown"
+	}
+	return e.Name()
+}
+
+func init() {
+	requireGraph(callRecordsListCmd, callRecordsGetCmd)
+
+	callRecordsListCmd.Flags().String("start", "", "Only calls starting at or after this date (accepts natural language)")
+	callRecordsListCmd.Flags().String("end", "", "Only calls starting before this date")
+	callRecordsListCmd.Flags().String("page-token", "", "Pagination token from previous response")
+	callRecordsCmd.AddCommand(callRecordsListCmd)
+
+	callRecordsGetCmd.Flags().Bool("no-sessions", false, "Skip session and segment details")
+	callRecordsCmd.AddCommand(callRecordsGetCmd)
+
+	rootCmd.AddCommand(callRecordsCmd)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// recentFile stores IDs from recent list commands for shell completion
+	recentFile = "recent.json"
+
+	// maxRecentItems is how many IDs are remembered per kind
+	maxRecentItems = 50
+
+	// completionTimeout bounds Graph calls made while completing
+	completionTimeout = 5 * time.Second
+)
+
+// recentItem is a remembered ID with a label shown as its completion description
+type recentItem struct {
+	ID    string `json:"id"`
+	Label string `json:"label,omitempty"`
+}
+
+// wellKnownMailFolders are folder names Graph accepts in place of folder IDs
+var wellKnownMailFolders = []string{
+	"inbox\tInbox",
+	"sentitems\tSent Items",
+	"drafts\tDrafts",
+	"deleteditems\tDeleted Items",
+	"archive\tArchive",
+	"junkemail\tJunk Email",
+	"outbox\tOutbox",
+}
+
+// loadRecentItems reads the remembered IDs, keyed by kind (messages, events)
+func loadRecentItems() map[string][]recentItem {
+	recent := map[string][]recentItem{}
+	data, err := os.ReadFile(filepath.Join(configMgr.Dir(), recentFile))
+	if err != nil {
+		return recent
+	}
+	json.Unmarshal(data, &recent)
+	return recent
+}
+
+// rememberRecent records listed IDs so completion can offer them later. Errors
+// are ignored: completion hints must never make a command fail.
+func rememberRecent(kind string, items []recentItem) {
+	if len(items) == 0 {
+		return
+	}
+
+	recent := loadRecentItems()
+	merged := append([]recentItem{}, items...)
+	seen := map[string]bool{}
+	for _, item := range items {
+		seen[item.ID] = true
+	}
+	for _, item := range recent[kind] {
+		if !seen[item.ID] {
+			merged = append(merged, item)
+		}
+	}
+	if len(merged) > maxRecentItems {
+		merged = merged[:maxRecentItems]
+	}
+	recent[kind] = merged
+
+	data, err := json.Marshal(recent)
+	if err != nil {
+		return
+	}
+	os.WriteFile(filepath.Join(configMgr.Dir(), recentFile), data, 0600)
+}
+
+// rememberMessages records listed message IDs for completion and %N references
+func rememberMessages(messages []*libgo365.Message) {
+	items := make([]recentItem, 0, len(messages))
+	ids := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		items = append(items, recentItem{ID: msg.ID, Label: msg.Subject})
+		ids = append(ids, msg.ID)
+	}
+	rememberRecent("messages", items)
+	rememberListing("messages", ids)
+}
+
+// rememberEvents records listed event IDs for completion and %N references
+func rememberEvents(events []*libgo365.Event) {
+	items := make([]recentItem, 0, len(events))
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+		label := event.Subject
+		if event.Start != nil && len(event.Start.DateTime) >= 16 {
+			label = event.Start.DateTime[:16] + " " + label
+		}
+		items = append(items, recentItem{ID: event.ID, Label: label})
+	}
+	rememberRecent("events", items)
+	rememberListing("events", ids)
+}
+
+// completeRecent returns a completion function offering remembered IDs of a kind
+// as the first argument
+func completeRecent(kind string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var completions []string
+		for _, item := range loadRecentItems()[kind] {
+			completions = append(completions, item.ID+"\t"+item.Label)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completionClient returns a Graph client for completion, or nil if the user is
+// not logged in. It never prompts.
+func completionClient(ctx context.Context) *libgo365.Client {
+	config, err := configMgr.Load()
+	if err != nil {
+		return nil
+	}
+
+	auth, err := newAuthenticator(config)
+	if err != nil || !auth.IsAuthenticated(ctx) {
+		return nil
+	}
+
+	if _, err := auth.Token(ctx); err != nil {
+		return nil
+	}
+	client, err := newClient(ctx, config, auth)
+	if err != nil {
+		return nil
+	}
+	return client
+}
+
+// completeMailFolders suggests well-known folder names and the user's folders
+func completeMailFolders(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completions := append([]string{}, wellKnownMailFolders...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	if client := completionClient(ctx); client != nil {
+		if folders, err := client.ListMailFolders(ctx); err == nil {
+			for _, folder := range folders {
+				completions = append(completions, folder.ID+"\t"+folder.DisplayName)
+			}
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCalendars suggests the user's calendar IDs, described by name
+func completeCalendars(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	client := completionClient(ctx)
+	if client == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	calendars, err := client.ListCalendars(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := make([]string, 0, len(calendars))
+	for _, cal := range calendars {
+		completions = append(completions, cal.ID+"\t"+cal.Name)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRespond suggests recent event IDs, then the response type
+func completeRespond(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	responses := []string{"accept", "decline", "tentative"}
+
+	// With --all or --ids the only argument is the response type
+	all, _ := cmd.Flags().GetBool("all")
+	ids, _ := cmd.Flags().GetString("ids")
+	if all || ids != "" || len(args) == 1 {
+		if len(args) > 1 || ((all || ids != "") && len(args) > 0) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return responses, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeRecent("events")(cmd, args, toComplete)
+}
+
+func init() {
+	mailListCmd.RegisterFlagCompletionFunc("folder-id", completeMailFolders)
+	mailGetCmd.ValidArgsFunction = completeRecent("messages")
+	mailFlagCmd.ValidArgsFunction = completeRecent("messages")
+	mailDeleteCmd.RegisterFlagCompletionFunc("folder-id", completeMailFolders)
+	mailDeleteCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// Any number of IDs may be given
+		return completeRecent("messages")(cmd, nil, toComplete)
+	}
+
+	for _, cmd := range []*cobra.Command{calendarListCmd, calendarGetCmd, calendarEventsCmd, calendarCreateCmd} {
+		cmd.RegisterFlagCompletionFunc("calendar-id", completeCalendars)
+	}
+	calendarGetCmd.ValidArgsFunction = completeRecent("events")
+	calendarRespondCmd.ValidArgsFunction = completeRespond
+
+	mailSendCmd.RegisterFlagCompletionFunc("body-type", cobra.FixedCompletions([]string{"text", "html"}, cobra.ShellCompDirectiveNoFileComp))
+	mailSendCmd.RegisterFlagCompletionFunc("importance", cobra.FixedCompletions([]string{"low", "normal", "high"}, cobra.ShellCompDirectiveNoFileComp))
+	calendarCreateCmd.RegisterFlagCompletionFunc("show-as", cobra.FixedCompletions([]string{"free", "tentative", "busy", "oof", "workingElsewhere"}, cobra.ShellCompDirectiveNoFileComp))
+	calendarCreateCmd.RegisterFlagCompletionFunc("sensitivity", cobra.FixedCompletions([]string{"normal", "personal", "private", "confidential"}, cobra.ShellCompDirectiveNoFileComp))
+	configSetCmd.RegisterFlagCompletionFunc("auth-mode", cobra.FixedCompletions([]string{libgo365.AuthModeDeviceCode, libgo365.AuthModeAppOnly, libgo365.AuthModeAzureIdentity, libgo365.AuthModeManagedIdentity}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// maxConfirmItems is how many affected items the confirmation prompt lists
+const maxConfirmItems = 10
+
+// errAborted is returned when the user declines a confirmation prompt
+var errAborted = errors.New("aborted")
+
+// confirm asks before a destructive or bulk operation, showing the summary and
+// the affected items. It only prompts in a terminal: with --yes, or when input
+// or output is redirected, it returns nil so scripts keep working.
+func confirm(cmd *cobra.Command, summary string, items []string) error {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes || !isInteractive() {
+		return nil
+	}
+	return promptConfirm(os.Stdin, os.Stderr, summary, items)
+}
+
+// promptConfirm writes the prompt to w and reads the answer from r
+func promptConfirm(r io.Reader, w io.Writer, summary string, items []string) error {
+	fmt.Fprintf(w, "%s:\n", summary)
+	for i, item := range items {
+		if i == maxConfirmItems {
+			fmt.Fprintf(w, "  ... and %d more\n", len(items)-maxConfirmItems)
+			break
+		}
+		fmt.Fprintf(w, "  - %s\n", item)
+	}
+	fmt.Fprint(w, "Proceed? [y/N] ")
+
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errAborted
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "Skip confirmation prompts for destructive and bulk operations")
+}
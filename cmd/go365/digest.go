@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/njt/go365/internal/dateparse"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// digestPreviewLength caps message previews in the Markdown digest
+const digestPreviewLength = 200
+
+// digest is a one-day briefing. Sections that fail to load are reported in
+// Errors rather than failing the whole digest.
+type digest struct {
+	Date          string              `json:"date"`
+	Agenda        []*libgo365.Event   `json:"agenda"`
+	Pending       []*libgo365.Event   `json:"pendingInvitations"`
+	ImportantMail []*libgo365.Message `json:"unreadImportantMail"`
+	Mentions      []*libgo365.Message `json:"mentions"`
+	Errors        map[string]string   `json:"errors,omitempty"`
+}
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Print a daily briefing",
+	Long: `Print a briefing for one day: the agenda, pending invitations, unread
+high-importance mail, and unread messages that @mention you.
+
+The default output is Markdown, ready to paste into chat or hand to an LLM.
+Use --json (or --output yaml) for structured output.`,
+	Example: `  go365 digest
+  go365 digest --for tomorrow
+  go365 digest --json | llm "summarize my day"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dayStr, _ := cmd.Flags().GetString("for")
+		top, _ := cmd.Flags().GetInt("top")
+
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+
+		day, err := dateparse.Parse(dayStr, time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid --for date: %w", err)
+		}
+		day = dateparse.StartOfDay(day)
+
+		result := buildDigest(ctx, client, day, top)
+
+		if handled, err := renderItem(cmd, result, nil); handled {
+			return err
+		}
+
+		writeDigestMarkdown(os.Stdout, result, day, displaySettings(ctx, client, config))
+		return nil
+	},
+}
+
+// buildDigest loads every section of the digest for day
+func buildDigest(ctx context.Context, client *libgo365.Client, day time.Time, top int) *digest {
+	result := &digest{
+		Date:          day.Format("2006-01-02"),
+		Agenda:        []*libgo365.Event{},
+		Pending:       []*libgo365.Event{},
+		ImportantMail: []*libgo365.Message{},
+		Mentions:      []*libgo365.Message{},
+		Errors:        map[string]string{},
+	}
+
+	agenda, err := client.CalendarView(ctx, &libgo365.CalendarViewOptions{
+		StartDateTime: dateparse.FormatISO8601(day),
+		EndDateTime:   dateparse.FormatISO8601(dateparse.AddDays(day, 1)),
+		Top:           top,
+	})
+	if err != nil {
+		result.Errors["agenda"] = err.Error()
+	} else {
+		for _, event := range agenda.Events {
+			if !event.IsCancelled {
+				result.Agenda = append(result.Agenda, event)
+			}
+		}
+	}
+
+	// Same filter as calendar pending: future invitations we haven't answered
+	now := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	pending, err := client.ListEvents(ctx, &libgo365.ListEventsOptions{
+		Filter:  fmt.Sprintf("(responseStatus/response eq 'notResponded' or responseStatus/response eq 'none') and isOrganizer eq false and start/dateTime ge '%s'", now),
+		OrderBy: "start/dateTime",
+		Top:     top,
+	})
+	if err != nil {
+		result.Errors["pendingInvitations"] = err.Error()
+	} else {
+		result.Pending = pending.Events
+	}
+
+	important, err := client.ListMessages(ctx, &libgo365.ListMessagesOptions{
+		FolderID: "inbox",
+		Filter:   "isRead eq false and importance eq 'high'",
+		Top:      top,
+	})
+	if err != nil {
+		result.Errors["unreadImportantMail"] = err.Error()
+	} else {
+		result.ImportantMail = important
+	}
+
+	mentions, err := client.ListUnreadMentions(ctx, top)
+	if err != nil {
+		result.Errors["mentions"] = err.Error()
+	} else {
+		result.Mentions = mentions
+	}
+
+	if len(result.Errors) == 0 {
+		result.Errors = nil
+	}
+	return result
+}
+
+// writeDigestMarkdown renders the digest as Markdown, with times shown per display
+func writeDigestMarkdown(w io.Writer, d *digest, day time.Time, display *timeDisplay) {
+	fmt.Fprintf(w, "# Briefing for %s\n", day.Format("Monday 2 January 2006"))
+
+	fmt.Fprintf(w, "\n## Agenda (%d)\n\n", len(d.Agenda))
+	writeDigestError(w, d, "agenda")
+	if len(d.Agenda) == 0 && d.Errors["agenda"] == "" {
+		fmt.Fprintln(w, "_Nothing scheduled._")
+	}
+	for _, event := range d.Agenda {
+		fmt.Fprintf(w, "- %s **%s**", digestEventTime(event, display, false), markdownEscape(event.Subject))
+		if event.Location != nil && event.Location.DisplayName != "" {
+			fmt.Fprintf(w, " · %s", markdownEscape(event.Location.DisplayName))
+		}
+		if event.ResponseStatus != nil && event.ResponseStatus.Response == libgo365.ResponseTentativelyAccepted {
+			fmt.Fprint(w, " _(tentative)_")
+		}
+		if event.OnlineMeeting != nil && event.OnlineMeeting.JoinUrl != "" {
+			fmt.Fprintf(w, " · [Join](%s)", event.OnlineMeeting.JoinUrl)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "\n## Pending invitations (%d)\n\n", len(d.Pending))
+	writeDigestError(w, d, "pendingInvitations")
+	if len(d.Pending) == 0 && d.Errors["pendingInvitations"] == "" {
+		fmt.Fprintln(w, "_None._")
+	}
+	for _, event := range d.Pending {
+		fmt.Fprintf(w, "- %s **%s**", digestEventTime(event, display, true), markdownEscape(event.Subject))
+		if event.Organizer != nil && event.Organizer.EmailAddress != nil {
+			fmt.Fprintf(w, " from %s", markdownEscape(digestSender(event.Organizer)))
+		}
+		fmt.Fprintln(w)
+	}
+
+	writeDigestMessages(w, d, "Unread important mail", "unreadImportantMail", d.ImportantMail, display)
+	writeDigestMessages(w, d, "Mentions", "mentions", d.Mentions, display)
+}
+
+// writeDigestMessages renders a section of messages with short previews
+func writeDigestMessages(w io.Writer, d *digest, title, key string, messages []*libgo365.Message, display *timeDisplay) {
+	fmt.Fprintf(w, "\n## %s (%d)\n\n", title, len(messages))
+	writeDigestError(w, d, key)
+	if len(messages) == 0 && d.Errors[key] == "" {
+		fmt.Fprintln(w, "_None._")
+	}
+	for _, msg := range messages {
+		fmt.Fprintf(w, "- **%s**", markdownEscape(msg.Subject))
+		if msg.From != nil {
+			fmt.Fprintf(w, " from %s", markdownEscape(digestSender(msg.From)))
+		}
+		if msg.ReceivedDateTime != nil {
+			fmt.Fprintf(w, " (%s)", msg.ReceivedDateTime.In(display.loc).Format("Mon "+display.clockLayout()))
+		}
+		fmt.Fprintln(w)
+		if preview := strings.Join(strings.Fields(msg.BodyPreview), " "); preview != "" {
+			if len([]rune(preview)) > digestPreviewLength {
+				preview = string([]rune(preview)[:digestPreviewLength]) + "…"
+			}
+			fmt.Fprintf(w, "  > %s\n", markdownEscape(preview))
+		}
+	}
+}
+
+// writeDigestError notes a section that couldn't be loaded
+func writeDigestError(w io.Writer, d *digest, key string) {
+	if msg := d.Errors[key]; msg != "" {
+		fmt.Fprintf(w, "_Unavailable: %s_\n", markdownEscape(msg))
+	}
+}
+
+// digestEventTime formats an event's time span; withDate adds the day
+func digestEventTime(event *libgo365.Event, display *timeDisplay, withDate bool) string {
+	start, ok := eventStartTime(event)
+	if !ok {
+		return ""
+	}
+	start = start.In(display.loc)
+	if event.IsAllDay {
+		if withDate {
+			return start.Format("Mon 2 Jan") + " (all day)"
+		}
+		return "All day"
+	}
+
+	layout := display.clockLayout()
+	if withDate {
+		layout = "Mon 2 Jan " + layout
+	}
+	when := start.Format(layout)
+	if end, ok := eventStartTime(&libgo365.Event{Start: event.End}); ok {
+		when += "–" + display.Clock(end)
+	}
+	return when
+}
+
+// digestSender returns a recipient's name, or address if it has none
+func digestSender(r *libgo365.Recipient) string {
+	if r == nil || r.EmailAddress == nil {
+		return ""
+	}
+	if r.EmailAddress.Name != "" {
+		return r.EmailAddress.Name
+	}
+	return r.EmailAddress.Address
+}
+
+// markdownEscape keeps user text from being read as Markdown emphasis or links
+var markdownEscape = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`,
+).Replace
+
+func init() {
+	requireGraph(digestCmd)
+
+	digestCmd.Flags().String("for", "today", "Day to brief (e.g., today, tomorrow, monday, 2026-01-20)")
+	digestCmd.Flags().Int("top", 25, "Maximum items per section")
+
+	rootCmd.AddCommand(digestCmd)
+}
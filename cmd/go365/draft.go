@@ -0,0 +1,416 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// draftColumns are message columns with defaults suited to unsent mail
+var draftColumns = &output.TableSpec{
+	Columns:  messageColumns.Columns,
+	Defaults: []string{"to", "subject", "attachments"},
+}
+
+var mailDraftCmd = &cobra.Command{
+	Use:   "draft",
+	Short: "Build up messages in Drafts before sending them",
+	Long: `Create a draft, then change its recipients, subject or body and add or
+remove attachments over as many commands as it takes, and send it when it
+is ready. Drafts also show up in Outlook, so they can be finished there.`,
+	Example: `  go365 mail draft create --to alex@contoso.com --subject "Q3 report" --body "Draft numbers"
+  go365 mail draft update AAMk... --attach report.pdf --body "Final numbers attached."
+  go365 mail draft send AAMk...`,
+}
+
+var mailDraftCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a draft message",
+	Long: `Save a new message in Drafts and print its ID. Every flag is optional, so
+a draft can start out empty. Files given with --attach over 3 MB are
+uploaded in chunks.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		message, err := draftMessage(cmd)
+		if err != nil {
+			return err
+		}
+
+		attachPaths, _ := cmd.Flags().GetStringArray("attach")
+		files, closeFiles, err := openAttachments(attachPaths)
+		if err != nil {
+			return err
+		}
+		defer closeFiles()
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		draft, err := client.CreateDraft(ctx, message)
+		if err != nil {
+			return fmt.Errorf("failed to create draft: %w", err)
+		}
+		for _, f := range files {
+			if err := client.AddAttachment(ctx, draft.ID, f); err != nil {
+				return fmt.Errorf("failed to attach %s to draft %s: %w", f.Name, draft.ID, err)
+			}
+		}
+		draft.HasAttachments = draft.HasAttachments || len(files) > 0
+
+		if handled, err := renderItem(cmd, draft, draftColumns); handled {
+			return err
+		}
+
+		fmt.Printf("Created draft %s\n", draft.ID)
+		return nil
+	},
+}
+
+var mailDraftUpdateCmd = &cobra.Command{
+	Use:   "update <draft-id>",
+	Short: "Change a draft message",
+	Long: `Change the fields given on a draft; the rest stay as they are. --to, --cc
+and --bcc replace the recipients, and an empty value removes them all.
+
+--attach adds a file and --remove-attachment removes the attachment with
+that name. Both can be repeated.`,
+	Example: `  go365 mail draft update AAMk... --subject "Q3 report (final)" --cc ""
+  go365 mail draft update AAMk... --remove-attachment draft.pdf --attach final.pdf`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		draftID, err := resolveRef("messages", args[0])
+		if err != nil {
+			return err
+		}
+
+		// Graph takes an empty list to remove recipients, which a Message
+		// would leave out, so the changes go as a map
+		changes := map[string]interface{}{}
+		if cmd.Flags().Changed("subject") {
+			changes["subject"], _ = cmd.Flags().GetString("subject")
+		}
+		if cmd.Flags().Changed("importance") {
+			value, _ := cmd.Flags().GetString("importance")
+			importance, err := libgo365.ParseImportance(value)
+			if err != nil {
+				return fmt.Errorf("invalid --importance: %w", err)
+			}
+			changes["importance"] = importance
+		}
+		for flag, property := range map[string]string{"to": "toRecipients", "cc": "ccRecipients", "bcc": "bccRecipients"} {
+			if cmd.Flags().Changed(flag) {
+				addresses, _ := cmd.Flags().GetString(flag)
+				changes[property] = append([]*libgo365.Recipient{}, parseRecipients(addresses)...)
+			}
+		}
+		if cmd.Flags().Changed("body") {
+			body, _ := cmd.Flags().GetString("body")
+			bodyType, _ := cmd.Flags().GetString("body-type")
+			contentType, err := libgo365.ParseBodyType(bodyType)
+			if err != nil {
+				return fmt.Errorf("invalid --body-type: %w", err)
+			}
+			changes["body"] = &libgo365.ItemBody{ContentType: string(contentType), Content: body}
+		} else if cmd.Flags().Changed("body-type") {
+			return fmt.Errorf("--body-type needs --body")
+		}
+
+		attachPaths, _ := cmd.Flags().GetStringArray("attach")
+		remove, _ := cmd.Flags().GetStringArray("remove-attachment")
+		if len(changes) == 0 && len(attachPaths) == 0 && len(remove) == 0 {
+			return fmt.Errorf("nothing to change; see 'go365 mail draft update --help'")
+		}
+		files, closeFiles, err := openAttachments(attachPaths)
+		if err != nil {
+			return err
+		}
+		defer closeFiles()
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		if len(remove) > 0 {
+			attachments, err := client.ListAttachments(ctx, draftID)
+			if err != nil {
+				return fmt.Errorf("failed to list attachments: %w", err)
+			}
+			for _, name := range remove {
+				found := false
+				for _, attachment := range attachments {
+					if attachment.Name != name {
+						continue
+					}
+					if err := client.DeleteAttachment(ctx, draftID, attachment.ID); err != nil {
+						return fmt.Errorf("failed to remove %s: %w", name, err)
+					}
+					found = true
+				}
+				if !found {
+					return fmt.Errorf("draft has no attachment called %s", name)
+				}
+			}
+		}
+		for _, f := range files {
+			if err := client.AddAttachment(ctx, draftID, f); err != nil {
+				return fmt.Errorf("failed to attach %s: %w", f.Name, err)
+			}
+		}
+		if len(changes) > 0 {
+			if _, err := client.UpdateDraft(ctx, draftID, changes); err != nil {
+				return fmt.Errorf("failed to update draft: %w", err)
+			}
+		}
+
+		result := "Updated draft " + draftID
+		if handled, err := renderItem(cmd, output.FormatActionResponse(true, result), nil); handled {
+			return err
+		}
+
+		fmt.Println(result)
+		return nil
+	},
+}
+
+var mailDraftListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List draft messages",
+	Long:  `List the messages in Drafts, most recent first. Refer to them as %N in other draft commands.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		top, _ := cmd.Flags().GetInt("top")
+		pageToken, _ := cmd.Flags().GetString("page-token")
+		all, _ := cmd.Flags().GetBool("all")
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		opts := &libgo365.ListMessagesOptions{
+			FolderID:  "drafts",
+			Top:       top,
+			PageToken: pageToken,
+			Select:    listProperties(cmd, draftColumns, []string{"id", "subject", "toRecipients", "hasAttachments"}),
+		}
+		var drafts []*libgo365.Message
+		count, nextPageToken := 0, ""
+		if all {
+			items, err := fetchAllItems(cmd, client.MessageIterator(opts))
+			if err != nil {
+				return fmt.Errorf("failed to list drafts: %w", err)
+			}
+			drafts, count = items, len(items)
+		} else {
+			resp, err := client.ListMessagesWithPagination(ctx, opts)
+			if err != nil {
+				return fmt.Errorf("failed to list drafts: %w", err)
+			}
+			drafts, count, nextPageToken = resp.Messages, resp.Count, resp.NextPageToken
+		}
+		rememberMessages(drafts)
+
+		if handled, err := renderList(cmd, drafts, count, nextPageToken, draftColumns); handled {
+			return err
+		}
+
+		if len(drafts) == 0 {
+			fmt.Println("No drafts found")
+			return nil
+		}
+		for i, draft := range drafts {
+			subject := draft.Subject
+			if subject == "" {
+				subject = "(no subject)"
+			}
+			fmt.Printf("Ref: %%%d\n", i+1)
+			fmt.Printf("ID: %s\n", draft.ID)
+			fmt.Printf("Subject: %s\n", subject)
+			if to := recipientList(draft.ToRecipients); to != "" {
+				fmt.Printf("To: %s\n", to)
+			}
+			if draft.HasAttachments {
+				fmt.Println("Attachments: yes")
+			}
+			fmt.Println("---")
+		}
+		output.PrintNextPageHint(os.Stdout, nextPageToken)
+		return nil
+	},
+}
+
+var mailDraftShowCmd = &cobra.Command{
+	Use:   "show <draft-id>",
+	Short: "Show a draft message and its attachments",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		draftID, err := resolveRef("messages", args[0])
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		draft, err := client.GetMessage(ctx, draftID)
+		if err != nil {
+			return fmt.Errorf("failed to get draft: %w", err)
+		}
+		if draft.HasAttachments {
+			if draft.Attachments, err = client.ListAttachments(ctx, draftID); err != nil {
+				return fmt.Errorf("failed to list attachments: %w", err)
+			}
+		}
+
+		if handled, err := renderItem(cmd, draft, draftColumns); handled {
+			return err
+		}
+
+		fmt.Printf("ID: %s\n", draft.ID)
+		if !draft.IsDraft {
+			fmt.Println("Status: sent (no longer a draft)")
+		}
+		fmt.Printf("Subject: %s\n", draft.Subject)
+		for _, field := range []struct {
+			label      string
+			recipients []*libgo365.Recipient
+		}{{"To", draft.ToRecipients}, {"Cc", draft.CcRecipients}, {"Bcc", draft.BccRecipients}} {
+			if len(field.recipients) > 0 {
+				fmt.Printf("%s: %s\n", field.label, recipientList(field.recipients))
+			}
+		}
+		for i, attachment := range draft.Attachments {
+			fmt.Printf("Attachment %d: %s (%s)\n", i+1, attachment.Name, formatBytes(attachment.Size))
+		}
+		if draft.Body != nil && strings.TrimSpace(draft.Body.Content) != "" {
+			fmt.Printf("\nBody (%s):\n", draft.Body.ContentType)
+			fmt.Println(terminalBody(draft.Body.ContentType, draft.Body.Content))
+		}
+		return nil
+	},
+}
+
+var mailDraftSendCmd = &cobra.Command{
+	Use:   "send <draft-id>",
+	Short: "Send a draft message",
+	Long:  `Send a draft as it stands. It is then saved to Sent Items.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		draftID, err := resolveRef("messages", args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := graphClient.SendDraft(cmd.Context(), draftID); err != nil {
+			return fmt.Errorf("failed to send draft: %w", err)
+		}
+
+		result := "Sent draft " + draftID
+		if handled, err := renderItem(cmd, output.FormatActionResponse(true, result), nil); handled {
+			return err
+		}
+
+		fmt.Println(result)
+		return nil
+	},
+}
+
+var mailDraftDeleteCmd = &cobra.Command{
+	Use:   "delete <draft-id...>",
+	Short: "Delete draft messages",
+	Long:  `Delete drafts. Like other deleted mail, they are moved to Deleted Items.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		draftIDs, err := resolveRefs("messages", splitArgs(args))
+		if err != nil {
+			return err
+		}
+		if err := confirm(cmd, fmt.Sprintf("Delete %d draft(s)", len(draftIDs)), draftIDs); err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		outcomes := newActionLog(cmd)
+		failed := 0
+		for _, id := range draftIDs {
+			if err := client.DeleteMessage(ctx, id); err != nil {
+				outcomes.Failed(id, err, "Failed to delete %s: %v", id, err)
+				failed++
+				continue
+			}
+			outcomes.Done(id, "Deleted draft %s", id)
+		}
+		if err := outcomes.Render(); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to delete %d of %d drafts", failed, len(draftIDs))
+		}
+		return nil
+	},
+}
+
+// draftMessage builds a new draft from the message flags
+func draftMessage(cmd *cobra.Command) (*libgo365.Message, error) {
+	subject, _ := cmd.Flags().GetString("subject")
+	to, _ := cmd.Flags().GetString("to")
+	cc, _ := cmd.Flags().GetString("cc")
+	bcc, _ := cmd.Flags().GetString("bcc")
+	body, _ := cmd.Flags().GetString("body")
+	bodyType, _ := cmd.Flags().GetString("body-type")
+
+	contentType, err := libgo365.ParseBodyType(bodyType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --body-type: %w", err)
+	}
+	message := &libgo365.Message{
+		Subject:       subject,
+		ToRecipients:  parseRecipients(to),
+		CcRecipients:  parseRecipients(cc),
+		BccRecipients: parseRecipients(bcc),
+	}
+	if body != "" {
+		message.Body = &libgo365.ItemBody{ContentType: string(contentType), Content: body}
+	}
+	if value, _ := cmd.Flags().GetString("importance"); value != "" {
+		if message.Importance, err = libgo365.ParseImportance(value); err != nil {
+			return nil, fmt.Errorf("invalid --importance: %w", err)
+		}
+	}
+	return message, nil
+}
+
+// addDraftFlags adds the message flags shared by draft create and update
+func addDraftFlags(cmd *cobra.Command) {
+	cmd.Flags().String("subject", "", "Email subject")
+	cmd.Flags().String("to", "", "Recipient email address(es), comma-separated")
+	cmd.Flags().String("cc", "", "CC recipient email address(es), comma-separated")
+	cmd.Flags().String("bcc", "", "BCC recipient email address(es), comma-separated")
+	cmd.Flags().String("body", "", "Email body content")
+	cmd.Flags().String("body-type", "Text", "Body content type (Text or HTML)")
+	cmd.Flags().String("importance", "", "Importance (low, normal, high)")
+	cmd.Flags().StringArray("attach", nil, "Attach a file (repeatable); files over 3 MB are uploaded in chunks")
+}
+
+func init() {
+	draftCmds := []*cobra.Command{mailDraftCreateCmd, mailDraftUpdateCmd, mailDraftListCmd, mailDraftShowCmd, mailDraftSendCmd, mailDraftDeleteCmd}
+	requireGraph(draftCmds...)
+	requireScopes([]string{"Mail.ReadWrite"}, mailDraftCreateCmd, mailDraftUpdateCmd, mailDraftListCmd, mailDraftShowCmd, mailDraftDeleteCmd)
+	requireScopes([]string{"Mail.Send"}, mailDraftSendCmd)
+
+	addDraftFlags(mailDraftCreateCmd)
+	addDraftFlags(mailDraftUpdateCmd)
+	mailDraftUpdateCmd.Flags().StringArray("remove-attachment", nil, "Remove the attachment with this name (repeatable)")
+
+	mailDraftListCmd.Flags().Int("top", 0, "Number of drafts to retrieve (default: 100)")
+	mailDraftListCmd.Flags().String("page-token", "", "Continue from previous response (cursor-based pagination)")
+	addSelectFlags(mailDraftListCmd, "message")
+	addAllFlags(mailDraftListCmd)
+
+	mailDraftCmd.AddCommand(draftCmds...)
+	mailCmd.AddCommand(mailDraftCmd)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/njt/go365/libgo365"
+)
+
+// Exit codes returned by go365 so scripts can branch on the kind of failure.
+// Keep the table in README.md in sync.
+const (
+	exitOK               = 0
+	exitError            = 1 // Any other failure, including usage errors
+	exitNotAuthenticated = 2 // Not logged in, or Graph rejected the token (401)
+	exitNotFound         = 3 // Graph returned 404
+	exitThrottled        = 4 // Graph returned 429, or go365 is backing off after repeated ones
+	exitPermissionDenied = 5 // Graph returned 403
+)
+
+// errNotAuthenticated is returned by commands run before `go365 login`
+var errNotAuthenticated = errors.New("not authenticated. Please run 'go365 login' first")
+
+// exitCode maps a command error to the documented exit code
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, errNotAuthenticated) {
+		return exitNotAuthenticated
+	}
+
+	switch {
+	case errors.Is(err, libgo365.ErrUnauthorized):
+		return exitNotAuthenticated
+	case errors.Is(err, libgo365.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, libgo365.ErrThrottled):
+		// Including while backing off after repeated 429s
+		return exitThrottled
+	case errors.Is(err, libgo365.ErrForbidden):
+		return exitPermissionDenied
+	}
+	return exitError
+}
+
+// exitCodeNames name the exit codes in JSON errors
+var exitCodeNames = map[int]string{
+	exitError:            "error",
+	exitNotAuthenticated: "notAuthenticated",
+	exitNotFound:         "notFound",
+	exitThrottled:        "throttled",
+	exitPermissionDenied: "permissionDenied",
+}
+
+// jsonErrors is set when JSON output was requested, so failures are reported
+// as JSON on stderr instead of cobra's "Error: ..." line
+var jsonErrors bool
+
+// errorReport is the JSON form of a failed command
+type errorReport struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code            string `json:"code"`     // Name of the exit code, e.g. notFound
+	Message         string `json:"message"`  // Graph's message when it gave one, else the error text
+	ExitCode        int    `json:"exitCode"` // Same as the process exit status
+	Status          int    `json:"status,omitempty"`
+	GraphCode       string `json:"graphCode,omitempty"` // e.g. ErrorItemNotFound
+	RequestID       string `json:"requestId,omitempty"`
+	ClientRequestID string `json:"clientRequestId,omitempty"` // client-request-id go365 sent
+	Date            string `json:"date,omitempty"`            // Graph's time of the failure
+	RetryAfter      int    `json:"retryAfter,omitempty"`      // Seconds to wait before retrying
+}
+
+// writeJSONError reports err as a single-line JSON object
+func writeJSONError(w io.Writer, err error) error {
+	code := exitCode(err)
+	detail := errorDetail{
+		Code:     exitCodeNames[code],
+		Message:  err.Error(),
+		ExitCode: code,
+	}
+
+	var gerr *libgo365.GraphError
+	if errors.As(err, &gerr) {
+		detail.Status = gerr.StatusCode
+		detail.GraphCode = gerr.Code
+		detail.RequestID = gerr.RequestID
+		detail.ClientRequestID = gerr.ClientRequestID
+		detail.Date = gerr.Date
+		if gerr.Message != "" {
+			detail.Message = gerr.Message
+		}
+		if gerr.RetryAfter > 0 {
+			// Round up so waiting the reported time is always enough
+			detail.RetryAfter = int((gerr.RetryAfter + time.Second - 1) / time.Second)
+		}
+	}
+
+	var backoff *libgo365.BackoffError
+	if errors.As(err, &backoff) {
+		detail.RetryAfter = int((time.Until(backoff.Until) + time.Second - 1) / time.Second)
+	}
+
+	return json.NewEncoder(w).Encode(errorReport{Error: detail})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var mailExportCmd = &cobra.Command{
+	Use:   "export <message-id>",
+	Short: "Save a message as an .eml file",
+	Long: `Save a message's original MIME content as an .eml file, which other mail
+clients can open or import. The content is streamed to disk as it downloads.
+
+Without --out the file is named after the message's subject; --out - writes
+to stdout.`,
+	Example: `  go365 mail export %1
+  go365 mail export AAMk... --file-format eml --out message.eml
+  go365 mail export %3 --out - | grep -i '^received:'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		messageID, err := resolveRef("messages", args[0])
+		if err != nil {
+			return err
+		}
+		format, _ := cmd.Flags().GetString("file-format")
+		if !strings.EqualFold(format, "eml") {
+			return fmt.Errorf("unsupported file format %q; mail export writes eml", format)
+		}
+		outputPath, _ := cmd.Flags().GetString("out")
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		if outputPath == "-" {
+			if _, err := client.DownloadMessageMIME(ctx, messageID, os.Stdout); err != nil {
+				return fmt.Errorf("failed to export message: %w", err)
+			}
+			return nil
+		}
+
+		if outputPath == "" {
+			msg, err := client.GetMessage(ctx, messageID)
+			if err != nil {
+				return fmt.Errorf("failed to get message: %w", err)
+			}
+			outputPath = libgo365.SafeFileName(msg.Subject, "message") + ".eml"
+		}
+
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		defer file.Close()
+
+		n, err := client.DownloadMessageMIME(ctx, messageID, file)
+		if err == nil {
+			err = file.Close()
+		}
+		if err != nil {
+			os.Remove(outputPath) // Clean up partial file
+			return fmt.Errorf("failed to export message: %w", err)
+		}
+
+		fmt.Printf("Exported: %s (%s)\n", outputPath, formatBytes(n))
+		return nil
+	},
+}
+
+var mailExportFolderCmd = &cobra.Command{
+	Use:   "export-folder <folder>",
+	Short: "Save a mail folder as an mbox file",
+	Long: `Save every message in a mail folder, oldest first, as one mbox file that
+mail clients such as Thunderbird and mutt can import. The folder is given as
+for 'mail move'. Subfolders aren't included.
+
+Messages are downloaded --concurrency at a time and written in folder order.
+Without --out the file is named after the folder; --out - writes to stdout.`,
+	Example: `  go365 mail export-folder inbox --file-format mbox --out backup.mbox
+  go365 mail export-folder "Receipts" --concurrency 8`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("file-format")
+		if !strings.EqualFold(format, "mbox") {
+			return fmt.Errorf("unsupported file format %q; mail export-folder writes mbox", format)
+		}
+		outputPath, _ := cmd.Flags().GetString("out")
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		folderID, err := resolveMailFolder(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		folder, err := client.GetMailFolder(ctx, folderID)
+		if err != nil {
+			return fmt.Errorf("failed to find folder %s: %w", args[0], err)
+		}
+
+		out := os.Stdout
+		if outputPath != "-" {
+			if outputPath == "" {
+				outputPath = libgo365.SafeFileName(folder.DisplayName, "folder") + ".mbox"
+			}
+			out, err = os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create file: %w", err)
+			}
+			defer out.Close()
+		}
+
+		opts := &libgo365.MboxExportOptions{Concurrency: concurrency}
+		progress := isTerminal(os.Stderr)
+		if progress {
+			opts.Progress = func(exported int) {
+				fmt.Fprintf(os.Stderr, "\rExported %d of %d messages...", exported, folder.TotalItemCount)
+			}
+		}
+		n, err := client.ExportMailFolderMbox(ctx, folder.ID, out, opts)
+		if progress {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+		if err == nil && out != os.Stdout {
+			err = out.Close()
+		}
+		if err != nil {
+			if out != os.Stdout {
+				os.Remove(outputPath) // Clean up partial file
+			}
+			return fmt.Errorf("failed to export %s after %d messages: %w", folder.DisplayName, n, err)
+		}
+
+		if out == os.Stdout {
+			fmt.Fprintf(os.Stderr, "Exported %d messages from %s\n", n, folder.DisplayName)
+			return nil
+		}
+		fmt.Printf("Exported %d messages from %s to %s\n", n, folder.DisplayName, outputPath)
+		return nil
+	},
+}
+
+func init() {
+	requireGraph(mailExportCmd, mailExportFolderCmd)
+	requireScopes([]string{"Mail.Read"}, mailExportCmd, mailExportFolderCmd)
+
+	mailExportCmd.Flags().String("file-format", "eml", "File format: eml")
+	mailExportCmd.Flags().String("out", "", "Output file path, or - for stdout (default: the subject with .eml)")
+	mailExportCmd.RegisterFlagCompletionFunc("file-format", cobra.FixedCompletions([]string{"eml"}, cobra.ShellCompDirectiveNoFileComp))
+	mailExportCmd.ValidArgsFunction = completeRecent("messages")
+	mailCmd.AddCommand(mailExportCmd)
+
+	mailExportFolderCmd.Flags().String("file-format", "mbox", "File format: mbox")
+	mailExportFolderCmd.Flags().String("out", "", "Output file path, or - for stdout (default: the folder name with .mbox)")
+	mailExportFolderCmd.RegisterFlagCompletionFunc("file-format", cobra.FixedCompletions([]string{"mbox"}, cobra.ShellCompDirectiveNoFileComp))
+	mailExportFolderCmd.ValidArgsFunction = completeMailFolders
+	mailCmd.AddCommand(mailExportFolderCmd)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var mailForwardCmd = &cobra.Command{
+	Use:   "forward <message-id>",
+	Short: "Forward an email message",
+	Long: `Forward a message to one or more recipients, with an optional comment above
+the original.
+
+The original's attachments go with it. --no-attachments drops them: the
+forward is drafted, the attachments are removed from the draft, and the
+draft is sent. Pictures embedded in the body are kept. If that fails
+partway, the draft is left in Drafts.`,
+	Example: `  go365 mail forward %1 --to alex@contoso.com --comment "FYI"
+  go365 mail forward %2 --to alex@contoso.com,megan@contoso.com --no-attachments`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		comment, _ := cmd.Flags().GetString("comment")
+		noAttachments, _ := cmd.Flags().GetBool("no-attachments")
+
+		recipients := parseRecipients(to)
+		if len(recipients) == 0 {
+			return fmt.Errorf("to is required")
+		}
+
+		messageID, err := resolveRef("messages", args[0])
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		client := graphClient
+		result := "Forwarded to " + recipientList(recipients)
+
+		if !noAttachments {
+			if err := client.ForwardMessage(ctx, messageID, comment, recipients); err != nil {
+				return fmt.Errorf("failed to forward message: %w", err)
+			}
+		} else {
+			draft, err := client.CreateForward(ctx, messageID, comment, recipients)
+			if err != nil {
+				return fmt.Errorf("failed to create forward: %w", err)
+			}
+			attachments, err := client.ListAttachments(ctx, draft.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list attachments (draft %s is in Drafts): %w", draft.ID, err)
+			}
+			removed := 0
+			for _, attachment := range attachments {
+				if attachment.IsInline {
+					continue
+				}
+				if err := client.DeleteAttachment(ctx, draft.ID, attachment.ID); err != nil {
+					return fmt.Errorf("failed to remove %s (draft %s is in Drafts): %w", attachment.Name, draft.ID, err)
+				}
+				removed++
+			}
+			if err := client.SendDraft(ctx, draft.ID); err != nil {
+				return fmt.Errorf("failed to send forward (draft %s is in Drafts): %w", draft.ID, err)
+			}
+			result += fmt.Sprintf(" without attachments (%d removed)", removed)
+		}
+
+		if handled, err := renderItem(cmd, output.FormatActionResponse(true, result), nil); handled {
+			return err
+		}
+
+		fmt.Println(result)
+		return nil
+	},
+}
+
+func init() {
+	requireGraph(mailForwardCmd)
+	// --no-attachments edits a draft before sending it
+	requireScopes([]string{"Mail.ReadWrite", "Mail.Send"}, mailForwardCmd)
+
+	mailForwardCmd.Flags().String("to", "", "Recipient email address(es), comma-separated (required)")
+	mailForwardCmd.Flags().String("comment", "", "Text to put above the forwarded message")
+	mailForwardCmd.Flags().Bool("no-attachments", false, "Leave out the original's attachments")
+
+	mailCmd.AddCommand(mailForwardCmd)
+}
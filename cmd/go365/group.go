@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Look up groups and distribution lists",
+	Long:  `Resolve distribution lists and mail-enabled groups.`,
+}
+
+var groupExpandCmd = &cobra.Command{
+	Use:   "expand <group>",
+	Short: "List who a group email reaches",
+	Long: `Expand a distribution list or mail-enabled group to the addresses it delivers to.
+
+Nested groups are expanded transitively and each address is listed once. The
+group can be given by email address (or short name) or object ID.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+		var err error
+		includeGroups, _ := cmd.Flags().GetBool("include-groups")
+
+		groupRef := args[0]
+		if !looksLikeObjectID(groupRef) {
+			groupRef, err = expandEmail(ctx, client, groupRef)
+			if err != nil {
+				return err
+			}
+		}
+
+		group, err := client.FindGroup(ctx, groupRef)
+		if err != nil {
+			return fmt.Errorf("failed to find group: %w", err)
+		}
+
+		members, err := client.ListGroupTransitiveMembers(ctx, group.ID)
+		if err != nil {
+			return fmt.Errorf("failed to expand group: %w", err)
+		}
+
+		// Nested groups are already expanded; keep each recipient once
+		seen := map[string]bool{}
+		recipients := make([]*libgo365.DirectoryObject, 0, len(members))
+		for _, member := range members {
+			if member.Kind() == "group" && !includeGroups {
+				continue
+			}
+			if seen[member.ID] {
+				continue
+			}
+			seen[member.ID] = true
+			recipients = append(recipients, member)
+		}
+
+		if handled, err := renderList(cmd, recipients, len(recipients), "", directoryObjectColumns); handled {
+			return err
+		}
+
+		if len(recipients) == 0 {
+			fmt.Printf("No members found in %s\n", group.DisplayName)
+			return nil
+		}
+
+		fmt.Printf("%s (%d recipients):\n\n", group.DisplayName, len(recipients))
+		for _, r := range recipients {
+			address := r.Mail
+			if address == "" {
+				address = r.UserPrincipalName
+			}
+			if address == "" {
+				address = "(no address)"
+			}
+			kind := ""
+			if r.Kind() != "user" {
+				kind = fmt.Sprintf(" [%s]", r.Kind())
+			}
+			fmt.Printf("%s <%s>%s\n", r.DisplayName, address, kind)
+		}
+
+		return nil
+	},
+}
+
+// looksLikeObjectID reports whether s has the shape of a directory object GUID
+func looksLikeObjectID(s string) bool {
+	return len(s) == 36 && strings.Count(s, "-") == 4
+}
+
+func init() {
+	requireGraph(groupExpandCmd)
+
+	groupExpandCmd.Flags().Bool("include-groups", false, "Also list the nested groups themselves")
+	groupCmd.AddCommand(groupExpandCmd)
+
+	rootCmd.AddCommand(groupCmd)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// historyFile is the append-only record of commands that changed data
+const historyFile = "history.jsonl"
+
+// historyEntry is one command run that sent at least one write to Graph
+type historyEntry struct {
+	Time     time.Time         `json:"time"`
+	Command  string            `json:"command"`
+	Args     []string          `json:"args,omitempty"`
+	Requests []*historyRequest `json:"requests"`
+	ExitCode int               `json:"exitCode"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// historyRequest is a write request sent to Graph
+type historyRequest struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+var historyColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "time", Path: "time"},
+		{Name: "command", Path: "command"},
+		{Name: "args", Path: "args"},
+		{Name: "exit", Path: "exitCode"},
+		{Name: "error", Path: "error"},
+	},
+	Defaults: []string{"time", "command", "args", "exit"},
+}
+
+// pending collects the current command's writes; bulk workers add to it
+// concurrently
+var pending struct {
+	sync.Mutex
+	entry historyEntry
+}
+
+// startHistory notes the command being run, for the entry saveHistory writes
+func startHistory(cmd *cobra.Command, args []string) {
+	pending.Lock()
+	defer pending.Unlock()
+	pending.entry = historyEntry{Time: time.Now(), Command: cmd.CommandPath(), Args: args}
+}
+
+// recordWrite is the clients' request hook. Reads are left out, as is the
+// $batch envelope since its sub-requests are recorded individually.
+func recordWrite(r *libgo365.RequestRecord) {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead || (r.Path == "/$batch" && !r.Batched) {
+		return
+	}
+	pending.Lock()
+	defer pending.Unlock()
+	pending.entry.Requests = append(pending.entry.Requests, &historyRequest{
+		Method:    r.Method,
+		Path:      r.Path,
+		Status:    r.Status,
+		RequestID: r.RequestID,
+	})
+}
+
+// saveHistory appends the command to the history if it sent any writes.
+// Failing to record is reported but doesn't change the command's outcome.
+func saveHistory(err error) {
+	pending.Lock()
+	entry := pending.entry
+	pending.Unlock()
+	if len(entry.Requests) == 0 {
+		return
+	}
+
+	entry.ExitCode = exitCode(err)
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	line, jerr := json.Marshal(entry)
+	if jerr != nil {
+		return
+	}
+
+	f, ferr := os.OpenFile(historyPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if ferr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not record history: %v\n", ferr)
+		return
+	}
+	defer f.Close()
+	// One write per entry keeps concurrent runs' lines whole
+	f.Write(append(line, '\n'))
+}
+
+func historyPath() string {
+	return filepath.Join(configMgr.Dir(), historyFile)
+}
+
+// loadHistory reads every recorded entry, oldest first
+func loadHistory() ([]*historyEntry, error) {
+	f, err := os.Open(historyPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []*historyEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var entry historyEntry
+		// Skip a line cut short by a crash rather than losing the rest
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, &entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show what go365 has changed",
+	Long: `Show recent commands that changed data, such as sending, deleting,
+responding, or uploading. Each entry lists the Graph write requests sent, with
+their status and request ID, and the command's exit code.
+
+The history is kept in ~/.go365/history.jsonl. go365 only ever appends to it.`,
+	Example: `  go365 history
+  go365 history --limit 50 --command "mail delete"
+  go365 history --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		command, _ := cmd.Flags().GetString("command")
+
+		entries, err := loadHistory()
+		if err != nil {
+			return err
+		}
+		if command != "" {
+			var matched []*historyEntry
+			for _, entry := range entries {
+				if strings.Contains(entry.Command, command) {
+					matched = append(matched, entry)
+				}
+			}
+			entries = matched
+		}
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		if handled, err := renderList(cmd, entries, len(entries), "", historyColumns); handled {
+			return err
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No history recorded")
+			return nil
+		}
+
+		var config *libgo365.Config
+		if loaded, err := configMgr.Load(); err == nil {
+			config = loaded
+		}
+		display := displaySettings(context.Background(), nil, config)
+		for _, entry := range entries {
+			line := strings.TrimSpace(entry.Command + " " + strings.Join(entry.Args, " "))
+			status := output.Green("ok")
+			if entry.ExitCode != 0 {
+				status = output.Red(fmt.Sprintf("exit %d", entry.ExitCode))
+			}
+			fmt.Printf("%s  %s  %s\n", output.Dim(display.Format(entry.Time)), line, status)
+			for _, r := range entry.Requests {
+				fmt.Printf("    %s %s %d\n", r.Method, r.Path, r.Status)
+			}
+			if entry.Error != "" {
+				fmt.Printf("    %s\n", entry.Error)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().Int("limit", 20, "Show only the most recent N entries (0 for all)")
+	historyCmd.Flags().String("command", "", "Only show commands containing this text (e.g. \"mail send\")")
+
+	rootCmd.AddCommand(historyCmd)
+}
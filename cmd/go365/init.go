@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// publicClientID is Microsoft's "Microsoft Graph Command Line Tools" app, a
+// public client most tenants can consent to without registering their own
+// app. See https://learn.microsoft.com/graph/powershell/authentication-commands
+const publicClientID = "14d82eec-204b-4c2f-b7e8-296a70dab67e"
+
+// publicClientTenant signs in with any work or school account when using the
+// public client
+const publicClientTenant = "organizations"
+
+// scopePreset is a named set of delegated permissions offered by init
+type scopePreset struct {
+	name   string
+	desc   string
+	scopes []string
+}
+
+// scopePresets are offered in order. The first uses whatever permissions the
+// app registration was granted; the others request scopes explicitly, which
+// the public client needs.
+var scopePresets = []*scopePreset{
+	{
+		name:   "app",
+		desc:   "Permissions configured on the app registration (.default)",
+		scopes: []string{"https://graph.microsoft.com/.default"},
+	},
+	{
+		name:   "read",
+		desc:   "Read mail, calendars, contacts and your profile",
+		scopes: []string{"User.Read", "Mail.Read", "Calendars.Read", "Contacts.Read", "MailboxSettings.Read"},
+	},
+	{
+		name: "standard",
+		desc: "Read and write mail and calendars, send mail",
+		scopes: []string{"User.Read", "Mail.ReadWrite", "Mail.Send", "Calendars.ReadWrite",
+			"Contacts.Read", "MailboxSettings.ReadWrite", "People.Read"},
+	},
+	{
+		name: "full",
+		desc: "Standard plus Teams, groups and the directory",
+		scopes: []string{"User.Read", "Mail.ReadWrite", "Mail.Send", "Calendars.ReadWrite",
+			"Contacts.Read", "MailboxSettings.ReadWrite", "People.Read", "User.ReadBasic.All",
+			"Group.ReadWrite.All", "Team.ReadBasic.All", "Channel.ReadBasic.All",
+			"ChannelMessage.Send", "Chat.ReadWrite"},
+	},
+}
+
+// guidPattern matches an Azure AD application or tenant ID
+var guidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// findScopePreset returns the preset with the given name, or nil
+func findScopePreset(name string) *scopePreset {
+	for _, p := range scopePresets {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// validTenant accepts a tenant GUID, a domain, or one of the multi-tenant
+// authorities
+func validTenant(tenant string) bool {
+	switch tenant {
+	case "common", "organizations", "consumers":
+		return true
+	}
+	return guidPattern.MatchString(tenant) || strings.Contains(tenant, ".")
+}
+
+// prompter asks questions on w and reads answers from r
+type prompter struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+// ask prompts for a value, returning def when the answer is empty
+func (p *prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.w, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.w, "%s: ", question)
+	}
+	answer, err := p.r.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", fmt.Errorf("no answer: %w", err)
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// askValid repeats the question until valid accepts the answer
+func (p *prompter) askValid(question, def, hint string, valid func(string) bool) (string, error) {
+	for {
+		answer, err := p.ask(question, def)
+		if err != nil {
+			return "", err
+		}
+		if valid(answer) {
+			return answer, nil
+		}
+		fmt.Fprintln(p.w, hint)
+	}
+}
+
+// askYes asks a yes/no question defaulting to yes
+func (p *prompter) askYes(question string) (bool, error) {
+	answer, err := p.ask(question+" [Y/n]", "")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "", "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set up go365 step by step",
+	Long: `Walk through first-time setup: choose your own app registration or the
+public Microsoft Graph Command Line Tools client, pick a permission preset,
+sign in, and check that a Graph call works.
+
+Answers can be given as flags to skip their questions, so init also works
+without a terminal.`,
+	Example: `  go365 init
+  go365 init --public-client --scopes standard
+  go365 init --tenant-id contoso.onmicrosoft.com --client-id <app-id> --scopes app`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := configMgr.LoadGlobal()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		tenantID, _ := cmd.Flags().GetString("tenant-id")
+		clientID, _ := cmd.Flags().GetString("client-id")
+		public, _ := cmd.Flags().GetBool("public-client")
+		presetName, _ := cmd.Flags().GetString("scopes")
+		skipLogin, _ := cmd.Flags().GetBool("skip-login")
+
+		if public && clientID != "" {
+			return fmt.Errorf("--public-client and --client-id can't be combined")
+		}
+		if presetName != "" && findScopePreset(presetName) == nil {
+			return fmt.Errorf("unknown scope preset %q (use app, read, standard, or full)", presetName)
+		}
+
+		interactive := isInteractive()
+		p := &prompter{r: bufio.NewReader(os.Stdin), w: os.Stderr}
+		needsAnswer := func(flag string) error {
+			if interactive {
+				return nil
+			}
+			return fmt.Errorf("--%s is required when not running in a terminal", flag)
+		}
+
+		// 1. Which app registration signs in
+		if !public && clientID == "" {
+			if err := needsAnswer("client-id or --public-client"); err != nil {
+				return err
+			}
+			chosen, err := pickIDs("Which app should go365 sign in with?", []*pickItem{
+				{id: "own", title: "My own app registration", desc: "Enter the tenant and client IDs from Entra ID"},
+				{id: "public", title: "Microsoft Graph Command Line Tools", desc: "Public client, no registration needed"},
+			}, false)
+			if err != nil {
+				return err
+			}
+			if len(chosen) == 0 {
+				return fmt.Errorf("selection cancelled")
+			}
+			public = chosen[0] == "public"
+		}
+
+		if public {
+			clientID = publicClientID
+			if tenantID == "" {
+				tenantID = publicClientTenant
+			}
+		}
+
+		if tenantID == "" {
+			if err := needsAnswer("tenant-id"); err != nil {
+				return err
+			}
+			if tenantID, err = p.askValid("Tenant ID or domain", config.TenantID,
+				"Enter a tenant GUID, a domain like contoso.onmicrosoft.com, or organizations", validTenant); err != nil {
+				return err
+			}
+		} else if !validTenant(tenantID) {
+			return fmt.Errorf("invalid tenant %q: use a tenant GUID, a domain, or organizations", tenantID)
+		}
+
+		if clientID == "" {
+			def := config.ClientID
+			if def == publicClientID {
+				def = ""
+			}
+			if clientID, err = p.askValid("Application (client) ID", def,
+				"Enter the GUID shown on the app registration's overview page", guidPattern.MatchString); err != nil {
+				return err
+			}
+		} else if !guidPattern.MatchString(clientID) {
+			return fmt.Errorf("invalid client ID %q: expected a GUID", clientID)
+		}
+
+		// 2. Which permissions to request. The public client has nothing
+		// preconfigured, so .default would grant almost nothing.
+		preset := findScopePreset(presetName)
+		if preset == nil {
+			if !interactive {
+				preset = findScopePreset("app")
+				if public {
+					preset = findScopePreset("standard")
+				}
+			} else {
+				var items []*pickItem
+				for _, sp := range scopePresets {
+					if public && sp.name == "app" {
+						continue
+					}
+					items = append(items, &pickItem{id: sp.name, title: sp.name, desc: sp.desc})
+				}
+				chosen, err := pickIDs("Which permissions should go365 request?", items, false)
+				if err != nil {
+					return err
+				}
+				if len(chosen) == 0 {
+					return fmt.Errorf("selection cancelled")
+				}
+				preset = findScopePreset(chosen[0])
+			}
+		}
+
+		changed := config.TenantID != tenantID || config.ClientID != clientID ||
+			strings.Join(config.Scopes, " ") != strings.Join(preset.scopes, " ")
+		config.TenantID = tenantID
+		config.ClientID = clientID
+		config.Scopes = preset.scopes
+		if err := configMgr.Save(config); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("Configuration saved (%s permissions)\n", preset.name)
+
+		if path := configMgr.ProjectConfigPath(); path != "" {
+			fmt.Fprintf(os.Stderr, "Note: %s overrides these settings in this directory\n", path)
+		}
+
+		if skipLogin {
+			fmt.Println("Run 'go365 login' to sign in")
+			return nil
+		}
+
+		// 3. Sign in, reusing an existing session when nothing changed
+		auth, err := newAuthenticator(config)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		if changed || !auth.IsAuthenticated(ctx) {
+			if interactive {
+				ok, err := p.askYes("Sign in now?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Println("Run 'go365 login' to sign in")
+					return nil
+				}
+			}
+			if err := auth.LoginWithDeviceCode(ctx); err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+		}
+
+		// 4. Prove the token works
+		if _, err := auth.Token(ctx); err != nil {
+			return fmt.Errorf("failed to get access token: %w", err)
+		}
+		client, err := newClient(ctx, config, auth)
+		if err != nil {
+			return err
+		}
+		user, err := client.GetMyProfile(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Signed in, but the test call to Graph failed.")
+			if errors.Is(err, libgo365.ErrForbidden) {
+				fmt.Fprintln(os.Stderr, "The app may lack User.Read or need admin consent in your tenant.")
+			}
+			return fmt.Errorf("failed to read your profile: %w", err)
+		}
+
+		fmt.Printf("Signed in as %s (%s)\n", user.DisplayName, user.UserPrincipalName)
+		fmt.Println("Try 'go365 mail list' or 'go365 calendar list'")
+		return nil
+	},
+}
+
+func init() {
+	initCmd.Flags().String("tenant-id", "", "Azure AD tenant ID or domain")
+	initCmd.Flags().String("client-id", "", "Application (client) ID of your app registration")
+	initCmd.Flags().Bool("public-client", false, "Use the Microsoft Graph Command Line Tools public client")
+	initCmd.Flags().String("scopes", "", "Permission preset: app, read, standard, or full")
+	initCmd.Flags().Bool("skip-login", false, "Save the configuration without signing in")
+
+	rootCmd.AddCommand(initCmd)
+}
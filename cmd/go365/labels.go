@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var labelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "Discover sensitivity labels",
+	Long:  `Discover the information protection sensitivity labels available for files and emails.`,
+}
+
+var labelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sensitivity labels",
+	Long:  `List the sensitivity labels available to the user, including their IDs for use when applying labels.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+		userID, _ := cmd.Flags().GetString("user")
+		format, _ := cmd.Flags().GetString("content-format")
+
+		var opts *libgo365.ListSensitivityLabelsOptions
+		if userID != "" {
+			expanded, err := expandEmail(ctx, client, userID)
+			if err != nil {
+				return err
+			}
+			opts = &libgo365.ListSensitivityLabelsOptions{UserID: expanded}
+		}
+
+		labels, err := client.ListSensitivityLabels(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list sensitivity labels: %w", err)
+		}
+
+		// Filter to labels applicable to the requested content format
+		if format != "" {
+			filtered := make([]*libgo365.SensitivityLabel, 0, len(labels))
+			for _, label := range labels {
+				if label.SupportsFormat(format) {
+					filtered = append(filtered, label)
+				}
+			}
+			labels = filtered
+		}
+
+		if handled, err := renderList(cmd, labels, len(labels), "", labelColumns); handled {
+			return err
+		}
+
+		if len(labels) == 0 {
+			fmt.Println("No sensitivity labels found")
+			return nil
+		}
+
+		for _, label := range labels {
+			name := label.Name
+			if label.Parent != nil && label.Parent.Name != "" {
+				name = label.Parent.Name + " / " + name
+			}
+			fmt.Printf("ID: %s\n", label.ID)
+			fmt.Printf("Name: %s\n", name)
+			fmt.Printf("Sensitivity: %d\n", label.Sensitivity)
+			if len(label.ContentFormats) > 0 {
+				fmt.Printf("Formats: %s\n", strings.Join(label.ContentFormats, ", "))
+			}
+			if label.HasProtection {
+				fmt.Printf("Protection: true\n")
+			}
+			if !label.IsActive {
+				fmt.Printf("Active: false\n")
+			}
+			if label.Description != "" {
+				fmt.Printf("Description: %s\n", label.Description)
+			}
+			fmt.Println("---")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	requireGraph(labelsListCmd)
+
+	labelsListCmd.Flags().String("user", "", "List labels available to another user (email or ID)")
+	labelsListCmd.Flags().String("content-format", "", "Only labels applicable to this content format (e.g., file, email)")
+	labelsCmd.AddCommand(labelsListCmd)
+
+	rootCmd.AddCommand(labelsCmd)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/njt/go365/internal/logfile"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// logFileName is the log written under ~/.go365/logs
+	logFileName = "go365.log"
+
+	// defaultLogLevel logs commands and Graph request summaries to the file
+	defaultLogLevel = "debug"
+
+	// defaultLogMaxSize is the log size in MB at which it is rotated
+	defaultLogMaxSize = 10
+
+	// defaultLogMaxFiles is how many rotated logs are kept
+	defaultLogMaxFiles = 5
+)
+
+// logLevels are the accepted log_level values
+var logLevels = []string{"off", "info", "debug", "trace"}
+
+var (
+	// logFile is the open log file, closed by finishLogging
+	logFile *logfile.Writer
+
+	// commandStart is when the command began, for its logged duration
+	commandStart time.Time
+)
+
+// logVerbosity returns the stderr log verbosity given with -v, raised to
+// full request dumps by --debug or GO365_DEBUG
+func logVerbosity(cmd *cobra.Command) int {
+	verbosity, _ := cmd.Flags().GetCount("verbose")
+	if debug, _ := cmd.Flags().GetBool("debug"); debug || os.Getenv("GO365_DEBUG") != "" {
+		verbosity = max(verbosity, 2)
+	}
+	return verbosity
+}
+
+// setupLogging installs the default slog logger. On stderr it logs warnings
+// by default, request summaries with -v and redacted request and response
+// dumps with -vv. Independently, the configured level is logged as JSON lines
+// to ~/.go365/logs/go365.log for looking into failures afterwards.
+func setupLogging(cmd *cobra.Command, args []string, verbosity int) {
+	level := slog.LevelWarn
+	switch {
+	case verbosity >= 2:
+		level = libgo365.LevelTrace
+	case verbosity == 1:
+		level = slog.LevelDebug
+	}
+
+	replaceLevel := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.LevelKey && a.Value.Any() == libgo365.LevelTrace {
+			a.Value = slog.StringValue("TRACE")
+		}
+		return a
+	}
+	handlers := []slog.Handler{slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: replaceLevel,
+	})}
+
+	// Shell completion runs on every tab press; keep it out of the log
+	if fileLevel, ok := logFileLevel(); ok && !strings.HasPrefix(cmd.Name(), cobra.ShellCompRequestCmd) {
+		if w, err := openLogFile(); err == nil {
+			logFile = w
+			handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+				Level: fileLevel,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					// JSON would otherwise write durations as nanoseconds
+					if a.Value.Kind() == slog.KindDuration {
+						a.Value = slog.StringValue(a.Value.Duration().String())
+					}
+					return replaceLevel(groups, a)
+				},
+			})
+			// Concurrent runs share the file, so tag each line with the process
+			handlers = append(handlers, handler.WithAttrs([]slog.Attr{slog.Int("pid", os.Getpid())}))
+		}
+	}
+	slog.SetDefault(slog.New(teeHandler(handlers)))
+
+	commandStart = time.Now()
+	var flags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) { flags = append(flags, f.Name) })
+	// Flag values are left out: they can hold message bodies or secrets
+	slog.Info("command started", "command", cmd.CommandPath(), "args", args, "flags", flags)
+}
+
+// finishLogging logs the command's outcome and closes the log file
+func finishLogging(err error) {
+	if commandStart.IsZero() {
+		return
+	}
+	attrs := []any{"duration", time.Since(commandStart).Round(time.Millisecond), "exit_code", exitCode(err)}
+	if err != nil {
+		attrs = append(attrs, "error", err.Error())
+	}
+	slog.Info("command finished", attrs...)
+
+	if logFile != nil {
+		logFile.Close()
+		logFile = nil
+	}
+}
+
+// logFileLevel returns the configured file log level, or false when file
+// logging is off
+func logFileLevel() (slog.Level, bool) {
+	name := defaultLogLevel
+	if config, err := configMgr.Load(); err == nil && config.LogLevel != "" {
+		name = config.LogLevel
+	}
+	switch name {
+	case "info":
+		return slog.LevelInfo, true
+	case "debug":
+		return slog.LevelDebug, true
+	case "trace":
+		return libgo365.LevelTrace, true
+	}
+	return 0, false
+}
+
+// openLogFile opens the rotating log with the configured limits
+func openLogFile() (*logfile.Writer, error) {
+	maxSize, maxFiles := defaultLogMaxSize, defaultLogMaxFiles
+	if config, err := configMgr.Load(); err == nil {
+		if config.LogMaxSize > 0 {
+			maxSize = config.LogMaxSize
+		}
+		if config.LogMaxFiles > 0 {
+			maxFiles = config.LogMaxFiles
+		}
+	}
+	return logfile.Open(logFilePath(), int64(maxSize)<<20, maxFiles)
+}
+
+func logFilePath() string {
+	return filepath.Join(configMgr.Dir(), "logs", logFileName)
+}
+
+// validLogLevel checks a log_level value for config set
+func validLogLevel(level string) error {
+	if !slices.Contains(logLevels, level) {
+		return fmt.Errorf("unsupported log level %q (use %s)", level, strings.Join(logLevels, ", "))
+	}
+	return nil
+}
+
+// teeHandler sends each record to every handler that accepts its level
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range t {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(teeHandler, len(t))
+	for i, h := range t {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	out := make(teeHandler, len(t))
+	for i, h := range t {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+func init() {
+	rootCmd.PersistentFlags().CountP("verbose", "v", "Log HTTP requests to stderr (-v summaries, -vv redacted headers and bodies)")
+	rootCmd.PersistentFlags().Bool("debug", false, "Log every HTTP request and response to stderr with secrets redacted, as -vv does (also GO365_DEBUG)")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/njt/go365/internal/dateparse"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var mailFlagCmd = &cobra.Command{
+	Use:   "flag <message-id...>",
+	Short: "Flag messages for follow-up",
+	Long: `Flag messages for follow-up, optionally with a due date, or mark the
+follow-up complete or clear it.
+
+--due and --start accept the same dates as calendar commands, including
+business days: "in 3 business days", "next business day". Business days skip
+the weekend and holidays set with 'go365 config set --weekend/--holidays'.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dueStr, _ := cmd.Flags().GetString("due")
+		startStr, _ := cmd.Flags().GetString("start")
+		complete, _ := cmd.Flags().GetBool("complete")
+		clear, _ := cmd.Flags().GetBool("clear")
+
+		if complete && clear {
+			return fmt.Errorf("--complete and --clear are mutually exclusive")
+		}
+		if (complete || clear) && (dueStr != "" || startStr != "") {
+			return fmt.Errorf("--due and --start only apply when flagging")
+		}
+
+		flag := &libgo365.FollowupFlag{FlagStatus: libgo365.FlagStatusFlagged}
+		switch {
+		case complete:
+			flag.FlagStatus = libgo365.FlagStatusComplete
+		case clear:
+			flag.FlagStatus = libgo365.FlagStatusNotFlagged
+		case dueStr != "":
+			// Graph requires a start date alongside a due date
+			now := time.Now()
+			due, err := dateparse.ParseStrict(dueStr, now)
+			if err != nil {
+				return fmt.Errorf("invalid --due: %w", err)
+			}
+			start := dateparse.StartOfDay(now)
+			if startStr != "" {
+				if start, err = dateparse.ParseStrict(startStr, now); err != nil {
+					return fmt.Errorf("invalid --start: %w", err)
+				}
+			}
+			if due.Before(start) {
+				return fmt.Errorf("--due is before the start date")
+			}
+			flag.StartDateTime = flagTime(start)
+			flag.DueDateTime = flagTime(due)
+		case startStr != "":
+			return fmt.Errorf("--start requires --due")
+		}
+
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+
+		messageIDs, err := resolveRefs("messages", splitArgs(args))
+		if err != nil {
+			return err
+		}
+
+		action := "Flagged"
+		switch flag.FlagStatus {
+		case libgo365.FlagStatusComplete:
+			action = "Completed"
+		case libgo365.FlagStatusNotFlagged:
+			action = "Cleared flag on"
+		}
+		due := ""
+		if flag.DueDateTime != nil {
+			t, _ := graphTime(flag.DueDateTime)
+			due = ", due " + displaySettings(ctx, client, config).Format(t)
+		}
+
+		outcomes := newActionLog(cmd)
+		failed := 0
+		for _, id := range messageIDs {
+			if err := client.FlagMessage(ctx, id, flag); err != nil {
+				outcomes.Failed(id, err, "Failed to flag %s: %v", id, err)
+				failed++
+				continue
+			}
+			outcomes.Done(id, "%s message %s%s", action, id, due)
+		}
+		if err := outcomes.Render(); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to flag %d of %d messages", failed, len(messageIDs))
+		}
+		return nil
+	},
+}
+
+// flagTime converts a local time for a follow-up flag, sent in UTC
+func flagTime(t time.Time) *libgo365.DateTimeTimeZone {
+	return &libgo365.DateTimeTimeZone{
+		DateTime: t.UTC().Format("2006-01-02T15:04:05"),
+		TimeZone: "UTC",
+	}
+}
+
+func init() {
+	requireGraph(mailFlagCmd)
+	requireScopes([]string{"Mail.ReadWrite"}, mailFlagCmd)
+
+	mailFlagCmd.Flags().String("due", "", "Due date, e.g. 'in 3 business days', friday, 2025-01-20")
+	mailFlagCmd.Flags().String("start", "", "Start date for the follow-up (default: today; needs --due)")
+	mailFlagCmd.Flags().Bool("complete", false, "Mark the follow-up complete")
+	mailFlagCmd.Flags().Bool("clear", false, "Remove the flag")
+
+	mailCmd.AddCommand(mailFlagCmd)
+}
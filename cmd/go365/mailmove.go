@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var mailMoveCmd = &cobra.Command{
+	Use:   "move <message-id...>",
+	Short: "Move messages to another folder",
+	Long: `Move messages to a mail folder, given by a well-known name such as archive
+or junkemail, the name of one of your top-level folders, or a folder ID.
+Several messages go 20 to a $batch call.`,
+	Example: `  go365 mail move %1 %2 --to-folder archive
+  go365 mail move AAMk... --to-folder "Receipts"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMailTransfer(cmd, args, false)
+	},
+}
+
+var mailCopyCmd = &cobra.Command{
+	Use:   "copy <message-id...>",
+	Short: "Copy messages to another folder",
+	Long: `Copy messages to a mail folder, given as for 'mail move'. The originals
+stay where they are.`,
+	Example: `  go365 mail copy %1 --to-folder "Receipts"`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMailTransfer(cmd, args, true)
+	},
+}
+
+// runMailTransfer moves, or with copying copies, the messages in args to
+// --to-folder
+func runMailTransfer(cmd *cobra.Command, args []string, copying bool) error {
+	folder, _ := cmd.Flags().GetString("to-folder")
+	if folder == "" {
+		return fmt.Errorf("--to-folder is required")
+	}
+
+	messageIDs, err := resolveRefs("messages", splitArgs(args))
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	client := graphClient
+
+	destinationID, err := resolveMailFolder(ctx, client, folder)
+	if err != nil {
+		return err
+	}
+
+	verb, prompt, done := "move", "Move", "Moved"
+	transfer := client.MoveMessages
+	if copying {
+		verb, prompt, done = "copy", "Copy", "Copied"
+		transfer = client.CopyMessages
+	}
+	if len(messageIDs) > 1 {
+		if err := confirm(cmd, fmt.Sprintf("%s %d message(s) to %s", prompt, len(messageIDs), folder), messageIDs); err != nil {
+			return err
+		}
+	}
+
+	errs, err := transfer(ctx, messageIDs, destinationID)
+	if err != nil {
+		return fmt.Errorf("failed to %s messages: %w", verb, err)
+	}
+	outcomes := newActionLog(cmd)
+	failed := 0
+	for i, id := range messageIDs {
+		if err := errs[i]; err != nil {
+			outcomes.Failed(id, err, "Failed to %s %s: %v", verb, id, err)
+			failed++
+			continue
+		}
+		outcomes.Done(id, "%s message %s to %s", done, id, folder)
+	}
+	if err := outcomes.Render(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d of %d messages", verb, failed, len(messageIDs))
+	}
+	return nil
+}
+
+// resolveMailFolder turns a well-known folder name, a top-level folder's
+// display name or a folder ID into something Graph takes as a folder ID.
+// Names are matched ignoring case; anything else is taken to be an ID.
+func resolveMailFolder(ctx context.Context, client *libgo365.Client, folder string) (string, error) {
+	for _, known := range wellKnownMailFolders {
+		name, _, _ := strings.Cut(known, "\t")
+		if strings.EqualFold(folder, name) {
+			return name, nil
+		}
+	}
+
+	folders, err := client.ListMailFolders(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list mail folders: %w", err)
+	}
+	for _, f := range folders {
+		if strings.EqualFold(f.DisplayName, folder) {
+			return f.ID, nil
+		}
+	}
+	return folder, nil
+}
+
+func init() {
+	requireGraph(mailMoveCmd, mailCopyCmd)
+	requireScopes([]string{"Mail.ReadWrite"}, mailMoveCmd, mailCopyCmd)
+
+	for _, cmd := range []*cobra.Command{mailMoveCmd, mailCopyCmd} {
+		cmd.Flags().String("to-folder", "", "Destination folder: a well-known name such as archive, a folder name, or an ID (required)")
+		cmd.RegisterFlagCompletionFunc("to-folder", completeMailFolders)
+		cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeRecent("messages")(cmd, nil, toComplete)
+		}
+		mailCmd.AddCommand(cmd)
+	}
+}
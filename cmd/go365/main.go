@@ -1,12 +1,18 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/x/term"
 	"github.com/njt/go365/internal/dateparse"
 	"github.com/njt/go365/internal/output"
 	"github.com/njt/go365/internal/plugin"
@@ -27,6 +33,51 @@ var (
 			}
 			return nil
 		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			tenant, _ := cmd.Flags().GetString("tenant")
+			setupTenant(tenant)
+			version, _ := cmd.Flags().GetString("api-version")
+			if err := setupAPIVersion(version); err != nil {
+				return err
+			}
+			setupNetwork()
+
+			if format, err := outputFormat(cmd); err == nil && (format == "json" || format == "jq") {
+				// Report failures as JSON on stderr; main writes them
+				jsonErrors = true
+				cmd.Root().SilenceErrors = true
+			}
+
+			noColor, _ := cmd.Flags().GetBool("no-color")
+			output.SetColor(output.ShouldColor(os.Stdout, noColor))
+
+			setupLogging(cmd, args, logVerbosity(cmd))
+			startHistory(cmd, args)
+
+			noCache, _ := cmd.Flags().GetBool("no-cache")
+			refresh, _ := cmd.Flags().GetBool("refresh")
+			setupCache(noCache, refresh)
+
+			absoluteTimes, _ = cmd.Flags().GetBool("absolute")
+			setupDateSettings()
+
+			workers, _ := cmd.Flags().GetInt("concurrency")
+			maxRPS, _ := cmd.Flags().GetFloat64("max-rps")
+			retries, _ := cmd.Flags().GetInt("max-retries")
+			if err := setupThrottling(workers, maxRPS, retries); err != nil {
+				return err
+			}
+
+			if needsGraph(cmd) {
+				if err := connectGraph(cmd.Context(), commandScopes(cmd)...); err != nil {
+					return err
+				}
+			}
+
+			noPager, _ := cmd.Flags().GetBool("no-pager")
+			setupPager(cmd, noPager)
+			return nil
+		},
 		SilenceUsage:  true,
 		SilenceErrors: false,
 	}
@@ -39,6 +90,22 @@ func init() {
 		fmt.Fprintf(os.Stderr, "Error initializing config manager: %v\n", err)
 		os.Exit(1)
 	}
+	plugin.InstallDir = filepath.Join(configMgr.Dir(), "plugins")
+
+	requireGraph(
+		mailListCmd, mailGetCmd, mailSendCmd, mailDeleteCmd,
+		calendarListCmd, calendarGetCmd, calendarCalendarsCmd, calendarEventsCmd, calendarRespondCmd,
+		calendarPendingCmd, calendarFreeBusyCmd, calendarFindTimeCmd, calendarCreateCmd,
+		driveCmd, driveLsCmd, driveInfoCmd, driveCatCmd, driveGetCmd, driveFindCmd,
+	)
+	requireScopes([]string{"Mail.Read"}, mailListCmd, mailGetCmd)
+	// Large --attach files go through a draft and an upload session
+	requireScopes([]string{"Mail.ReadWrite", "Mail.Send"}, mailSendCmd)
+	requireScopes([]string{"Mail.ReadWrite"}, mailDeleteCmd)
+	requireScopes([]string{"Calendars.Read"}, calendarListCmd, calendarGetCmd, calendarCalendarsCmd, calendarEventsCmd,
+		calendarPendingCmd, calendarFreeBusyCmd, calendarFindTimeCmd)
+	requireScopes([]string{"Calendars.ReadWrite"}, calendarRespondCmd, calendarCreateCmd)
+	requireScopes([]string{"Files.Read"}, driveCmd, driveLsCmd, driveInfoCmd, driveCatCmd, driveGetCmd, driveFindCmd)
 
 	rootCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(logoutCmd)
@@ -49,38 +116,134 @@ func init() {
 	rootCmd.AddCommand(calendarCmd)
 }
 
+// loginEvent is a line of 'login --json' output: "pending" with the device
+// code while go365 waits for the user, then "authenticated"
+type loginEvent struct {
+	Status string `json:"status"`
+	*libgo365.DeviceCode
+	Account string `json:"account,omitempty"`
+}
+
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Authenticate with Microsoft 365",
-	Long:  `Authenticate with Microsoft 365 using device code flow`,
+	Long: `Authenticate with Microsoft 365 using device code flow.
+
+With --browser, go365 opens the sign-in page in the system browser and
+listens on localhost for the redirect (authorization code flow with PKCE).
+The app registration needs the "Mobile and desktop applications" redirect
+URI http://localhost for this.
+
+When the configured scopes are listed one by one rather than .default,
+commands ask for consent to any further permissions they need the first
+time they run. --scopes grants extra ones up front, which scripts and other
+non-interactive runs need.
+
+In app-only mode ('go365 config set --auth-mode app-only') there is no user
+to sign in; login checks that the certificate or client secret gets a token.
+In azidentity mode it checks that the Azure CLI or environment credentials
+get one, and in managed-identity mode that the Azure resource go365 runs on
+has an identity.
+
+With --json, login prints one JSON object per line instead: the device code,
+verification URL and expiry as soon as they are issued ("status":"pending"),
+then the signed-in account once the user has finished ("status":
+"authenticated"). Wrappers and chat bots can show the code to the user while
+go365 waits.`,
+	Example: `  go365 login
+  go365 login --browser
+  go365 login --json | jq -r 'select(.status == "pending") | .message'`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
+		format, err := outputFormat(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
+			return err
+		}
+		// One compact line per event, so readers can act on the code early
+		var events *json.Encoder
+		if format == "json" {
+			events = json.NewEncoder(os.Stdout)
+		}
+		done := func(account, message string) error {
+			clearAccountCaches()
+			if events != nil {
+				return events.Encode(&loginEvent{Status: "authenticated", Account: account})
+			}
+			fmt.Println(message)
+			return nil
 		}
 
-		if config.ClientID == "" || config.TenantID == "" {
-			return fmt.Errorf("client ID and tenant ID must be configured. Use 'go365 config set' to configure")
+		config, err := configMgr.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
+		// Azure credentials bring their own app and tenant
+		borrowed := config.AuthMode == libgo365.AuthModeAzureIdentity || config.AuthMode == libgo365.AuthModeManagedIdentity
+		if !borrowed && (config.ClientID == "" || config.TenantID == "") {
+			return fmt.Errorf("client ID and tenant ID must be configured. Run 'go365 init' or use 'go365 config set' to configure")
 		}
 
-		auth, err := libgo365.NewAuthenticator(authConfig)
+		auth, err := newAuthenticator(config)
 		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
+			return err
 		}
 
 		ctx := context.Background()
-		if err := auth.LoginWithDeviceCode(ctx); err != nil {
+		if auth.ManagedIdentity() {
+			info, err := auth.GetUserInfo(ctx)
+			if err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+			appID, _ := info["appId"].(string)
+			return done(appID, "Using managed identity "+appID)
+		}
+		if auth.AzureIdentity() {
+			// There is nothing to sign in to; check the credentials work
+			info, err := auth.GetUserInfo(ctx)
+			if err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+			name, _ := info["username"].(string)
+			if name == "" {
+				name, _ = info["appId"].(string)
+			}
+			return done(name, "Using Azure credentials for "+name)
+		}
+		if auth.AppOnly() {
+			if _, err := auth.GetAccessToken(ctx); err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+			return done(config.ClientID, fmt.Sprintf("Successfully authenticated as app %s (app-only)", config.ClientID))
+		}
+		if scopes, _ := cmd.Flags().GetStringSlice("scopes"); len(scopes) > 0 && !auth.AddScopes(scopes...) {
+			return fmt.Errorf("--scopes needs the configured scopes listed one by one, not .default; set them with 'go365 init' or in config.json")
+		}
+		switch browser, _ := cmd.Flags().GetBool("browser"); {
+		case browser:
+			err = auth.LoginWithBrowser(ctx, func(url string) error {
+				fmt.Fprintf(os.Stderr, "Opening your browser to sign in. If it doesn't open, visit:\n%s\n", url)
+				if err := openWith(url, false); err != nil {
+					fmt.Fprintf(os.Stderr, "Could not open a browser: %v\n", err)
+				}
+				// The sign-in can still finish from the printed URL
+				return nil
+			})
+		case events != nil:
+			err = auth.LoginWithDeviceCodeFunc(ctx, func(code *libgo365.DeviceCode) error {
+				return events.Encode(&loginEvent{Status: "pending", DeviceCode: code})
+			})
+		default:
+			err = auth.LoginWithDeviceCode(ctx)
+		}
+		if err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		fmt.Println("Successfully authenticated!")
-		return nil
+		var account string
+		if info, err := auth.GetUserInfo(ctx); err == nil {
+			account, _ = info["username"].(string)
+		}
+		return done(account, "Successfully authenticated!")
 	},
 }
 
@@ -94,76 +257,161 @@ var logoutCmd = &cobra.Command{
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
+		auth, err := newAuthenticator(config)
+		if err != nil {
+			return err
 		}
 
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
+		if auth.ManagedIdentity() {
+			return fmt.Errorf("go365 holds no managed identity credentials; switch modes with 'go365 config set --auth-mode'")
+		}
+		if auth.AzureIdentity() {
+			return fmt.Errorf("go365 holds no Azure identity credentials; run 'az logout' to sign out of the Azure CLI")
 		}
 
 		ctx := context.Background()
 		if err := auth.Logout(ctx); err != nil {
 			return fmt.Errorf("logout failed: %w", err)
 		}
+		clearAccountCaches()
 
+		if handled, err := renderItem(cmd, output.FormatActionResponse(true, "Logged out"), nil); handled {
+			return err
+		}
 		fmt.Println("Successfully logged out!")
 		return nil
 	},
 }
 
+// statusOutput is the authentication status plus what only the CLI knows
+type statusOutput struct {
+	*libgo365.AuthStatus
+	TenantName    string `json:"tenantName,omitempty"`
+	Certificate   string `json:"certificate,omitempty"`
+	ResponseCache string `json:"responseCache,omitempty"`
+}
+
+var statusColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "authenticated", Path: "authenticated"},
+		{Name: "mode", Path: "mode"},
+		{Name: "type", Path: "accountType"},
+		{Name: "user", Path: "username"},
+		{Name: "app", Path: "appId"},
+		{Name: "tenant", Path: "tenantId"},
+		{Name: "tenant-name", Path: "tenantName"},
+		{Name: "cloud", Path: "cloud"},
+		{Name: "expires", Path: "expiresOn"},
+		{Name: "scopes", Path: "scopes"},
+		{Name: "roles", Path: "roles"},
+	},
+	Defaults: []string{"authenticated", "mode", "user", "tenant-name", "expires"},
+}
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show authentication status",
-	Long:  `Display current authentication status and user information`,
+	Long: `Display the current authentication status: who you are signed in as, the
+kind of account, the tenant and cloud, when the access token expires, the
+scopes or roles it carries, and where tokens and responses are cached.
+
+Everything but the tenant name comes from the token cache and the token
+itself; the name needs one Graph call and is left out if that fails.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := configMgr.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
+		auth, err := newAuthenticator(config)
 		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
+			return err
 		}
 
 		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			fmt.Println("Status: Not authenticated")
-			return nil
+		result := &statusOutput{
+			AuthStatus:  auth.Status(ctx),
+			Certificate: auth.CertificateThumbprint(),
+		}
+		if !cacheDisabled && result.Authenticated {
+			result.ResponseCache = filepath.Join(cacheRoot(), accountKey(config, signedInAccount(ctx, auth)))
+		}
+		if result.Authenticated && result.AccountType != libgo365.AccountPersonal {
+			if client, err := newClient(ctx, config, auth); err == nil {
+				if org, err := client.GetOrganization(ctx); err == nil {
+					result.TenantName = org.DisplayName
+				}
+			}
 		}
 
-		fmt.Println("Status: Authenticated")
-
-		// Try to get user info from Graph API
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
+		if handled, err := renderItem(cmd, result, statusColumns); handled {
 			return err
 		}
 
-		client := libgo365.NewClient(ctx, accessToken)
-		userInfo, err := client.GetMe(ctx)
-		if err != nil {
-			fmt.Printf("Warning: Could not retrieve user info: %v\n", err)
+		st := result.AuthStatus
+		switch {
+		case st.Authenticated:
+			fmt.Println("Status: Authenticated")
+		case st.Error != "":
+			fmt.Println("Status: Not authenticated")
+			fmt.Printf("Error: %s\n", st.Error)
+		default:
+			fmt.Println("Status: Not authenticated")
+		}
+		fmt.Printf("Mode: %s\n", st.Mode)
+		if !st.Authenticated {
+			switch st.Mode {
+			case libgo365.AuthModeAzureIdentity:
+				fmt.Println("No Azure credentials found; run 'az login' or set AZURE_* environment variables")
+			case libgo365.AuthModeManagedIdentity:
+				fmt.Println("No managed identity found; go365 must run on an Azure resource with an identity assigned")
+			}
 			return nil
 		}
 
-		if displayName, ok := userInfo["displayName"].(string); ok {
-			fmt.Printf("User: %s\n", displayName)
+		if st.Username != "" {
+			fmt.Printf("Account: %s (%s)\n", st.Username, st.AccountType)
+		} else {
+			fmt.Printf("Account: %s\n", st.AccountType)
 		}
-		if userPrincipalName, ok := userInfo["userPrincipalName"].(string); ok {
-			fmt.Printf("Email: %s\n", userPrincipalName)
+		if st.AppName != "" {
+			fmt.Printf("App: %s (%s)\n", st.AppName, st.AppID)
+		} else if st.AppID != "" {
+			fmt.Printf("App: %s\n", st.AppID)
+		}
+		if result.TenantName != "" {
+			fmt.Printf("Tenant: %s (%s)\n", result.TenantName, st.TenantID)
+		} else if st.TenantID != "" {
+			fmt.Printf("Tenant: %s\n", st.TenantID)
+		}
+		if st.Environment != "" {
+			cloud := st.Cloud
+			if cloud == "" {
+				cloud = "unknown"
+			}
+			fmt.Printf("Cloud: %s (%s)\n", cloud, st.Environment)
+		}
+		if !st.ExpiresOn.IsZero() {
+			display := displaySettings(ctx, nil, config)
+			fmt.Printf("Token expires: %s (in %s)\n", display.Format(st.ExpiresOn), time.Until(st.ExpiresOn).Round(time.Minute))
+		}
+		if len(st.Scopes) > 0 {
+			fmt.Printf("Scopes: %s\n", strings.Join(st.Scopes, " "))
+		}
+		if len(st.Roles) > 0 {
+			fmt.Printf("Roles: %s\n", strings.Join(st.Roles, " "))
+		}
+		if result.Certificate != "" {
+			fmt.Printf("Certificate: %s\n", result.Certificate)
+		}
+		if st.TokenCache != "" {
+			fmt.Printf("Token cache: %s\n", st.TokenCache)
+		}
+		if result.ResponseCache != "" {
+			fmt.Printf("Response cache: %s\n", result.ResponseCache)
+		} else {
+			fmt.Println("Response cache: disabled")
 		}
-
 		return nil
 	},
 }
@@ -174,12 +422,19 @@ var configCmd = &cobra.Command{
 	Long:  `Manage go365 configuration settings`,
 }
 
+// configSetOutput is config set's result, with the thumbprint of a newly set
+// certificate to register on the app
+type configSetOutput struct {
+	*output.ActionResponse
+	CertificateThumbprint string `json:"certificateThumbprint,omitempty"`
+}
+
 var configSetCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Set configuration values",
 	Long:  `Set configuration values like tenant ID, client ID, timezone, etc.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
+		config, err := configMgr.LoadGlobal()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -188,25 +443,362 @@ var configSetCmd = &cobra.Command{
 		clientID, _ := cmd.Flags().GetString("client-id")
 		timezone, _ := cmd.Flags().GetString("timezone")
 
+		// "text" resets the default to human-readable output
+		if cmd.Flags().Changed("output") {
+			outputDefault, _ := cmd.Flags().GetString("output")
+			switch {
+			case outputDefault == "text":
+				config.Output = ""
+			case slices.Contains(outputFormats, outputDefault):
+				config.Output = outputDefault
+			default:
+				return fmt.Errorf("unsupported output format %q (use json, yaml, table, or text)", outputDefault)
+			}
+		}
+
 		if tenantID != "" {
 			config.TenantID = tenantID
 		}
 		if clientID != "" {
 			config.ClientID = clientID
 		}
-		if timezone != "" {
+		// "auto" goes back to following the mailbox settings
+		switch {
+		case timezone == "auto":
+			config.TimeZone = ""
+		case timezone != "":
+			if _, err := libgo365.LoadLocation(timezone); err != nil {
+				return err
+			}
 			config.TimeZone = timezone
 		}
+		if cmd.Flags().Changed("time-format") {
+			timeFormat, _ := cmd.Flags().GetString("time-format")
+			switch {
+			case timeFormat == "auto":
+				config.TimeFormat = ""
+			case slices.Contains(timeFormats, timeFormat):
+				config.TimeFormat = timeFormat
+			default:
+				return fmt.Errorf("unsupported time format %q (use 12h, 24h, or auto)", timeFormat)
+			}
+		}
+		if cmd.Flags().Changed("log-level") {
+			level, _ := cmd.Flags().GetString("log-level")
+			if err := validLogLevel(level); err != nil {
+				return err
+			}
+			config.LogLevel = level
+		}
+		if cmd.Flags().Changed("log-max-size") {
+			size, _ := cmd.Flags().GetInt("log-max-size")
+			if size < 1 {
+				return fmt.Errorf("--log-max-size must be at least 1 (MB)")
+			}
+			config.LogMaxSize = size
+		}
+		if cmd.Flags().Changed("log-max-files") {
+			files, _ := cmd.Flags().GetInt("log-max-files")
+			if files < 1 {
+				return fmt.Errorf("--log-max-files must be at least 1")
+			}
+			config.LogMaxFiles = files
+		}
+		if cmd.Flags().Changed("date-order") {
+			order, _ := cmd.Flags().GetString("date-order")
+			switch order {
+			case "auto":
+				config.DateOrder = ""
+			case dateparse.OrderDMY, dateparse.OrderMDY:
+				config.DateOrder = order
+			default:
+				return fmt.Errorf("unsupported date order %q (use dmy, mdy, or auto)", order)
+			}
+		}
+		if cmd.Flags().Changed("week-start") {
+			weekStart, _ := cmd.Flags().GetString("week-start")
+			days, err := dateparse.ParseWeekdays(weekStart)
+			if err != nil {
+				return err
+			}
+			switch {
+			case len(days) == 0:
+				config.WeekStart = ""
+			case len(days) == 1:
+				config.WeekStart = strings.ToLower(days[0].String())
+			default:
+				return fmt.Errorf("--week-start takes a single weekday")
+			}
+		}
+		if cmd.Flags().Changed("weekend") {
+			weekendFlag, _ := cmd.Flags().GetString("weekend")
+			weekend, err := dateparse.ParseWeekdays(weekendFlag)
+			if err != nil {
+				return err
+			}
+			config.Weekend = nil
+			for _, day := range weekend {
+				config.Weekend = append(config.Weekend, strings.ToLower(day.String()))
+			}
+		}
+		if cmd.Flags().Changed("holidays") {
+			holidays, _ := cmd.Flags().GetString("holidays")
+			config.Holidays = nil
+			for _, day := range strings.Split(holidays, ",") {
+				day = strings.TrimSpace(day)
+				if day == "" || day == "none" {
+					continue
+				}
+				if _, err := time.Parse("2006-01-02", day); err != nil {
+					return fmt.Errorf("invalid holiday %q (use YYYY-MM-DD)", day)
+				}
+				config.Holidays = append(config.Holidays, day)
+			}
+		}
+		if cmd.Flags().Changed("api-version") {
+			version, _ := cmd.Flags().GetString("api-version")
+			switch {
+			case version == libgo365.APIVersionV1:
+				config.APIVersion = ""
+			case slices.Contains(libgo365.APIVersions, version):
+				config.APIVersion = version
+			default:
+				return fmt.Errorf("unsupported API version %q (use v1.0 or beta)", version)
+			}
+		}
+		if cmd.Flags().Changed("plugin-handshake") {
+			handshake, _ := cmd.Flags().GetString("plugin-handshake")
+			switch handshake {
+			case "env":
+				config.PluginHandshake = ""
+			case libgo365.HandshakeStdin:
+				config.PluginHandshake = handshake
+			default:
+				return fmt.Errorf("unsupported plugin handshake %q (use env or stdin)", handshake)
+			}
+		}
+		if cmd.Flags().Changed("ca-file") {
+			caFile, _ := cmd.Flags().GetString("ca-file")
+			if caFile != "" {
+				abs, err := filepath.Abs(caFile)
+				if err != nil {
+					return fmt.Errorf("invalid --ca-file: %w", err)
+				}
+				caFile = abs
+			}
+			config.CAFile = caFile
+		}
+		network := cmd.Flags().Changed("ca-file")
+		for _, setting := range []struct {
+			flag  string
+			field *string
+		}{
+			{"proxy", &config.ProxyURL},
+			{"min-tls-version", &config.MinTLSVersion},
+			{"dial-timeout", &config.DialTimeout},
+			{"response-timeout", &config.ResponseTimeout},
+		} {
+			if cmd.Flags().Changed(setting.flag) {
+				*setting.field, _ = cmd.Flags().GetString(setting.flag)
+				network = true
+			}
+		}
+		if network {
+			// Catch a bad proxy URL, CA file or timeout now rather than on
+			// every command after
+			opts, err := config.TransportOptions()
+			if err != nil {
+				return err
+			}
+			if _, err := libgo365.NewTransport(opts); err != nil {
+				return err
+			}
+		}
+
+		if cmd.Flags().Changed("auth-mode") {
+			mode, _ := cmd.Flags().GetString("auth-mode")
+			switch mode {
+			case libgo365.AuthModeDeviceCode:
+				config.AuthMode = ""
+			case libgo365.AuthModeAppOnly, libgo365.AuthModeAzureIdentity, libgo365.AuthModeManagedIdentity:
+				config.AuthMode = mode
+			default:
+				return fmt.Errorf("unsupported auth mode %q (use device-code, app-only, azidentity or managed-identity)", mode)
+			}
+		}
+		if cmd.Flags().Changed("managed-identity-client-id") {
+			config.ManagedIdentityClientID, _ = cmd.Flags().GetString("managed-identity-client-id")
+		}
+
+		// The secret is never taken as an argument, where it would end up
+		// in shell history and the process list
+		if setSecret, _ := cmd.Flags().GetBool("client-secret"); setSecret {
+			secret, err := readSecret("Client secret (empty to remove): ")
+			if err != nil {
+				return fmt.Errorf("failed to read client secret: %w", err)
+			}
+			config.ClientSecret = secret
+		}
+
+		if cmd.Flags().Changed("cert-path") {
+			certPath, _ := cmd.Flags().GetString("cert-path")
+			if certPath != "" {
+				// Commands run from any directory
+				abs, err := filepath.Abs(certPath)
+				if err != nil {
+					return fmt.Errorf("invalid --cert-path: %w", err)
+				}
+				certPath = abs
+			}
+			config.CertPath = certPath
+		}
+		if setPassword, _ := cmd.Flags().GetBool("cert-password"); setPassword {
+			password, err := readSecret("Certificate password (empty to remove): ")
+			if err != nil {
+				return fmt.Errorf("failed to read certificate password: %w", err)
+			}
+			config.CertPassword = password
+		}
+		result := &configSetOutput{ActionResponse: output.FormatActionResponse(true, "Configuration saved")}
+		certChanged := cmd.Flags().Changed("cert-path") || cmd.Flags().Changed("cert-password")
+		if certChanged && config.CertPath != "" {
+			// Catch a wrong path or password now rather than at the next
+			// sign-in; other settings can change while the file is away
+			certs, _, err := libgo365.LoadCertificate(config.CertPath, config.CertPassword)
+			if err != nil {
+				return err
+			}
+			result.CertificateThumbprint = libgo365.CertificateThumbprint(certs[0])
+		}
 
 		if err := configMgr.Save(config); err != nil {
 			return fmt.Errorf("failed to save config: %w", err)
 		}
 
+		if handled, err := renderItem(cmd, result, nil); handled {
+			return err
+		}
+		if result.CertificateThumbprint != "" {
+			fmt.Printf("Certificate thumbprint: %s\n", result.CertificateThumbprint)
+		}
 		fmt.Println("Configuration saved successfully!")
 		return nil
 	},
 }
 
+// readSecret prompts for a value without echoing it, or reads a single line
+// from stdin when it is not a terminal
+func readSecret(prompt string) (string, error) {
+	if !isTerminal(os.Stdin) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		return strings.TrimSpace(line), nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(secret)), nil
+}
+
+// configOutput is the effective configuration as shown by config show, with
+// secrets reduced to whether they are set
+type configOutput struct {
+	TenantID                string   `json:"tenantId,omitempty"`
+	ClientID                string   `json:"clientId,omitempty"`
+	Scopes                  []string `json:"scopes,omitempty"`
+	TimeZone                string   `json:"timezone,omitempty"`
+	TimeFormat              string   `json:"timeFormat,omitempty"`
+	ClientSecretSet         bool     `json:"clientSecretSet"`
+	Certificate             string   `json:"certificate,omitempty"`
+	CertPasswordSet         bool     `json:"certPasswordSet"`
+	AuthMode                string   `json:"authMode"`
+	ManagedIdentityClientID string   `json:"managedIdentityClientId,omitempty"`
+	Calendar                string   `json:"calendar,omitempty"`
+	Output                  string   `json:"output,omitempty"`
+	Mailbox                 string   `json:"mailbox,omitempty"`
+	DateOrder               string   `json:"dateOrder,omitempty"`
+	WeekStart               string   `json:"weekStart,omitempty"`
+	Weekend                 []string `json:"weekend,omitempty"`
+	Holidays                []string `json:"holidays,omitempty"`
+	APIVersion              string   `json:"apiVersion,omitempty"`
+	PluginHandshake         string   `json:"pluginHandshake,omitempty"`
+	Proxy                   string   `json:"proxy,omitempty"`
+	CAFile                  string   `json:"caFile,omitempty"`
+	MinTLSVersion           string   `json:"minTlsVersion,omitempty"`
+	DialTimeout             string   `json:"dialTimeout,omitempty"`
+	ResponseTimeout         string   `json:"responseTimeout,omitempty"`
+	LogLevel                string   `json:"logLevel"`
+	LogFile                 string   `json:"logFile,omitempty"`
+	ProjectConfig           string   `json:"projectConfig,omitempty"`
+}
+
+var configColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "tenant", Path: "tenantId"},
+		{Name: "client", Path: "clientId"},
+		{Name: "auth-mode", Path: "authMode"},
+		{Name: "timezone", Path: "timezone"},
+		{Name: "time-format", Path: "timeFormat"},
+		{Name: "output", Path: "output"},
+		{Name: "mailbox", Path: "mailbox"},
+		{Name: "api-version", Path: "apiVersion"},
+		{Name: "proxy", Path: "proxy"},
+		{Name: "log-level", Path: "logLevel"},
+		{Name: "project-config", Path: "projectConfig"},
+	},
+	Defaults: []string{"tenant", "client", "auth-mode", "timezone", "output"},
+}
+
+// newConfigOutput builds config show's result from the loaded configuration
+func newConfigOutput(config *libgo365.Config) *configOutput {
+	result := &configOutput{
+		TenantID:                config.TenantID,
+		ClientID:                config.ClientID,
+		Scopes:                  config.Scopes,
+		TimeZone:                config.TimeZone,
+		TimeFormat:              config.TimeFormat,
+		ClientSecretSet:         config.ClientSecret != "",
+		Certificate:             config.CertPath,
+		CertPasswordSet:         config.CertPassword != "",
+		AuthMode:                config.AuthMode,
+		ManagedIdentityClientID: config.ManagedIdentityClientID,
+		Calendar:                config.Calendar,
+		Output:                  config.Output,
+		Mailbox:                 config.Mailbox,
+		DateOrder:               config.DateOrder,
+		WeekStart:               config.WeekStart,
+		Weekend:                 config.Weekend,
+		Holidays:                config.Holidays,
+		APIVersion:              config.APIVersion,
+		PluginHandshake:         config.PluginHandshake,
+		CAFile:                  config.CAFile,
+		MinTLSVersion:           config.MinTLSVersion,
+		DialTimeout:             config.DialTimeout,
+		ResponseTimeout:         config.ResponseTimeout,
+		LogLevel:                config.LogLevel,
+		ProjectConfig:           configMgr.ProjectConfigPath(),
+	}
+	if result.AuthMode == "" {
+		result.AuthMode = libgo365.AuthModeDeviceCode
+	}
+	if config.ProxyURL != "" {
+		result.Proxy = redactProxy(config.ProxyURL)
+	}
+	if result.LogLevel == "" {
+		result.LogLevel = defaultLogLevel
+	}
+	if result.LogLevel != "off" {
+		result.LogFile = logFilePath()
+	}
+	return result
+}
+
 var configShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show current configuration",
@@ -217,6 +809,10 @@ var configShowCmd = &cobra.Command{
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		if handled, err := renderItem(cmd, newConfigOutput(config), configColumns); handled {
+			return err
+		}
+
 		fmt.Printf("Tenant ID: %s\n", config.TenantID)
 		fmt.Printf("Client ID: %s\n", config.ClientID)
 		fmt.Printf("Scopes: %v\n", config.Scopes)
@@ -225,6 +821,81 @@ var configShowCmd = &cobra.Command{
 		} else {
 			fmt.Printf("Timezone: (using mailbox settings)\n")
 		}
+		if config.TimeFormat != "" {
+			fmt.Printf("Time format: %s\n", config.TimeFormat)
+		} else {
+			fmt.Printf("Time format: (using mailbox settings)\n")
+		}
+		if config.ClientSecret != "" {
+			fmt.Printf("Client secret: (set, encrypted)\n")
+		}
+		if config.CertPath != "" {
+			fmt.Printf("Certificate: %s\n", config.CertPath)
+		}
+		if config.CertPassword != "" {
+			fmt.Printf("Certificate password: (set, encrypted)\n")
+		}
+		if config.AuthMode != "" {
+			fmt.Printf("Auth mode: %s\n", config.AuthMode)
+		} else {
+			fmt.Printf("Auth mode: device-code\n")
+		}
+		if config.ManagedIdentityClientID != "" {
+			fmt.Printf("Managed identity: %s\n", config.ManagedIdentityClientID)
+		}
+		if config.Calendar != "" {
+			fmt.Printf("Calendar: %s\n", config.Calendar)
+		}
+		if config.Output != "" {
+			fmt.Printf("Output: %s\n", config.Output)
+		}
+		if config.Mailbox != "" {
+			fmt.Printf("Mailbox: %s\n", config.Mailbox)
+		}
+		if config.DateOrder != "" {
+			fmt.Printf("Date order: %s\n", config.DateOrder)
+		}
+		if config.WeekStart != "" {
+			fmt.Printf("Week start: %s\n", config.WeekStart)
+		}
+		if len(config.Weekend) > 0 {
+			fmt.Printf("Weekend: %s\n", strings.Join(config.Weekend, ", "))
+		}
+		if len(config.Holidays) > 0 {
+			fmt.Printf("Holidays: %s\n", strings.Join(config.Holidays, ", "))
+		}
+		if config.APIVersion != "" {
+			fmt.Printf("API version: %s\n", config.APIVersion)
+		}
+		if config.PluginHandshake != "" {
+			fmt.Printf("Plugin handshake: %s\n", config.PluginHandshake)
+		}
+		if config.ProxyURL != "" {
+			fmt.Printf("Proxy: %s\n", redactProxy(config.ProxyURL))
+		}
+		if config.CAFile != "" {
+			fmt.Printf("CA file: %s\n", config.CAFile)
+		}
+		if config.MinTLSVersion != "" {
+			fmt.Printf("Minimum TLS version: %s\n", config.MinTLSVersion)
+		}
+		if config.DialTimeout != "" {
+			fmt.Printf("Dial timeout: %s\n", config.DialTimeout)
+		}
+		if config.ResponseTimeout != "" {
+			fmt.Printf("Response timeout: %s\n", config.ResponseTimeout)
+		}
+		if level := config.LogLevel; level == "off" {
+			fmt.Printf("Log: off\n")
+		} else {
+			if level == "" {
+				level = defaultLogLevel
+			}
+			fmt.Printf("Log: %s (%s)\n", logFilePath(), level)
+		}
+		if path := configMgr.ProjectConfigPath(); path != "" {
+			fmt.Printf("Project config: %s\n", path)
+		}
 
 		return nil
 	},
@@ -233,7 +904,7 @@ var configShowCmd = &cobra.Command{
 var pluginsCmd = &cobra.Command{
 	Use:   "plugins",
 	Short: "List available plugins",
-	Long:  `List all available go365-* plugins in PATH`,
+	Long:  `List all available go365-* plugins in PATH and ~/.go365/plugins`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		plugins, err := plugin.ListPlugins()
 		if err != nil {
@@ -245,9 +916,16 @@ var pluginsCmd = &cobra.Command{
 			return nil
 		}
 
+		// Installed plugins show the release they came from
+		installed, _ := plugin.NewInstaller(plugin.InstallDir).LoadManifest()
+
 		fmt.Println("Available plugins:")
 		for _, p := range plugins {
-			fmt.Printf("  - %s\n", p)
+			if entry, ok := installed[p]; ok && entry.Version != "" {
+				fmt.Printf("  - %s (%s)\n", p, entry.Version)
+			} else {
+				fmt.Printf("  - %s\n", p)
+			}
 		}
 
 		return nil
@@ -255,9 +933,35 @@ var pluginsCmd = &cobra.Command{
 }
 
 func init() {
+	loginCmd.Flags().Bool("browser", false, "Sign in through the system browser instead of a device code")
+	loginCmd.Flags().StringSlice("scopes", nil, "Extra scopes to consent to along with the configured ones, e.g. Mail.Send")
+
 	configSetCmd.Flags().String("tenant-id", "", "Azure AD tenant ID")
 	configSetCmd.Flags().String("client-id", "", "Azure AD client ID")
-	configSetCmd.Flags().String("timezone", "", "Default IANA timezone (e.g., Pacific/Auckland)")
+	configSetCmd.Flags().Bool("client-secret", false, "Prompt for the client secret (stored encrypted)")
+	configSetCmd.Flags().String("auth-mode", "", "How to sign in: device-code (default), app-only, which uses the certificate or client secret and needs no user, azidentity, which reuses 'az login' or AZURE_* environment credentials, or managed-identity on Azure VMs, containers and functions")
+	configSetCmd.Flags().String("managed-identity-client-id", "", "Client ID of a user-assigned managed identity (empty for the system-assigned one)")
+	configSetCmd.Flags().String("cert-path", "", "PEM or PFX certificate with its private key for app-only sign-in (empty to remove)")
+	configSetCmd.Flags().Bool("cert-password", false, "Prompt for the certificate's password (stored encrypted)")
+	configSetCmd.Flags().String("timezone", "", "Default IANA timezone (e.g., Pacific/Auckland), or auto to follow the mailbox")
+	configSetCmd.Flags().String("time-format", "", "Clock for displayed times: 12h, 24h, or auto to follow the mailbox")
+	configSetCmd.Flags().String("output", "", "Default output format: json, yaml, table, or text to reset")
+	configSetCmd.Flags().String("log-level", "", "File log level: off, info, debug (default), or trace")
+	configSetCmd.Flags().Int("log-max-size", 0, fmt.Sprintf("Rotate the log file at this size in MB (default %d)", defaultLogMaxSize))
+	configSetCmd.Flags().Int("log-max-files", 0, fmt.Sprintf("Rotated log files to keep (default %d)", defaultLogMaxFiles))
+	configSetCmd.Flags().String("date-order", "", "How to read numeric dates like 03/04/2025: dmy, mdy, or auto to reject ambiguous ones")
+	configSetCmd.Flags().String("week-start", "", "First day of the week for 'this week' and week grouping, e.g. sunday (empty for Monday)")
+	configSetCmd.Flags().String("weekend", "", "Non-working weekdays for business-day dates, e.g. sat,sun or fri,sat (empty for the default)")
+	configSetCmd.Flags().String("holidays", "", "Non-working dates as YYYY-MM-DD, comma-separated (replaces the list; none to clear)")
+	configSetCmd.Flags().String("api-version", "", "Graph API version commands call: v1.0 (default) or beta")
+	configSetCmd.RegisterFlagCompletionFunc("api-version", cobra.FixedCompletions(libgo365.APIVersions, cobra.ShellCompDirectiveNoFileComp))
+	configSetCmd.Flags().String("plugin-handshake", "", "How plugins receive the session: env (default) or stdin")
+	configSetCmd.Flags().String("proxy", "", "Proxy URL for all requests, e.g. http://proxy:8080 (empty to use HTTPS_PROXY)")
+	configSetCmd.Flags().String("ca-file", "", "PEM file of extra CA certificates to trust, e.g. a TLS-inspecting proxy's root (empty to remove)")
+	configSetCmd.Flags().String("min-tls-version", "", "Minimum TLS version: 1.2 or 1.3 (empty for the default, 1.2)")
+	configSetCmd.RegisterFlagCompletionFunc("min-tls-version", cobra.FixedCompletions([]string{"1.2", "1.3"}, cobra.ShellCompDirectiveNoFileComp))
+	configSetCmd.Flags().String("dial-timeout", "", "How long to wait to connect, e.g. 10s (empty for 30s)")
+	configSetCmd.Flags().String("response-timeout", "", "How long to wait for Graph to start responding, e.g. 2m (empty for no limit)")
 
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configShowCmd)
@@ -274,128 +978,163 @@ var mailListCmd = &cobra.Command{
 	Short: "List email messages",
 	Long:  `List email messages from the authenticated user's mailbox`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+		var err error
 
 		// Get options from flags
 		folderID, _ := cmd.Flags().GetString("folder-id")
 		top, _ := cmd.Flags().GetInt("top")
 		skip, _ := cmd.Flags().GetInt("skip")
 		pageToken, _ := cmd.Flags().GetString("page-token")
-		jsonOutput, _ := cmd.Flags().GetBool("json")
+		userID, _ := cmd.Flags().GetString("user")
+		all, _ := cmd.Flags().GetBool("all")
 		// --markdown is accepted but is a no-op for list (no body content)
 
+		if all && skip > 0 {
+			return fmt.Errorf("--all cannot be combined with --skip")
+		}
+		if userID == "" {
+			userID = config.Mailbox
+		}
+		if userID != "" {
+			userID, err = expandEmail(ctx, client, userID)
+			if err != nil {
+				return err
+			}
+		}
+
 		opts := &libgo365.ListMessagesOptions{
 			FolderID:  folderID,
 			Top:       top,
 			Skip:      skip,
 			PageToken: pageToken,
+			UserID:    userID,
+			Select:    listProperties(cmd, messageColumns, messageListProperties),
 		}
 
-		resp, err := client.ListMessagesWithPagination(ctx, opts)
-		if err != nil {
-			return fmt.Errorf("failed to list messages: %w", err)
+		// --since takes the start of a period and --until its end, so
+		// "--since 'last week'" includes all of last week
+		now := time.Now()
+		if since, _ := cmd.Flags().GetString("since"); since != "" {
+			start, _, err := dateparse.ParseRange(since, now)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			opts.StartTime = &start
 		}
-
-		if jsonOutput {
-			// JSON output matching Graph API structure
-			listResp := output.FormatListResponse(resp.Messages, resp.Count, resp.NextPageToken)
-			return output.WriteJSON(os.Stdout, listResp)
+		if until, _ := cmd.Flags().GetString("until"); until != "" {
+			_, end, err := dateparse.ParseRange(until, now)
+			if err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+			opts.EndTime = &end
+		}
+		if opts.StartTime != nil || opts.EndTime != nil {
+			// Graph requires the ordered property to lead the filter
+			opts.OrderBy = "receivedDateTime desc"
 		}
 
-		// Human-readable output
-		if len(resp.Messages) == 0 {
-			fmt.Println("No messages found")
+		// Human-readable output is printed as messages arrive
+		if format, err := outputFormat(cmd); err == nil && format == "" {
+			display := displaySettings(ctx, client, config)
+			var messages []*libgo365.Message
+			show := func(msg *libgo365.Message) error {
+				messages = append(messages, msg)
+				printMessageSummary(len(messages), msg, display)
+				return nil
+			}
+
+			nextPageToken := ""
+			if all {
+				err = fetchAll(cmd, client.MessageIterator(opts), show)
+			} else {
+				var resp *libgo365.ListMessagesResponse
+				if resp, err = client.StreamMessages(ctx, opts, show); err == nil {
+					nextPageToken = resp.NextPageToken
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to list messages: %w", err)
+			}
+			rememberMessages(messages)
+
+			if len(messages) == 0 {
+				fmt.Println("No messages found")
+				return nil
+			}
+
+			// Print pagination hint if there are more results
+			output.PrintNextPageHint(os.Stdout, nextPageToken)
 			return nil
 		}
 
-		for _, msg := range resp.Messages {
-			fmt.Printf("ID: %s\n", msg.ID)
-			fmt.Printf("Subject: %s\n", msg.Subject)
-			if msg.From != nil && msg.From.EmailAddress != nil {
-				fmt.Printf("From: %s <%s>\n", msg.From.EmailAddress.Name, msg.From.EmailAddress.Address)
-			}
-			if msg.ReceivedDateTime != nil {
-				fmt.Printf("Received: %s\n", msg.ReceivedDateTime.Format(time.RFC3339))
+		if all {
+			messages, err := fetchAllItems(cmd, client.MessageIterator(opts))
+			if err != nil {
+				return fmt.Errorf("failed to list messages: %w", err)
 			}
-			fmt.Println("---")
+			rememberMessages(messages)
+			_, err = renderList(cmd, messages, len(messages), "", messageColumns)
+			return err
 		}
 
-		// Print pagination hint if there are more results
-		output.PrintNextPageHint(os.Stdout, resp.NextPageToken)
+		resp, err := client.ListMessagesWithPagination(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list messages: %w", err)
+		}
+		rememberMessages(resp.Messages)
 
-		return nil
+		_, err = renderList(cmd, resp.Messages, resp.Count, resp.NextPageToken, messageColumns)
+		return err
 	},
 }
 
+// printMessageSummary prints one mail list entry; ref is its %N reference
+func printMessageSummary(ref int, msg *libgo365.Message, display *timeDisplay) {
+	subject := msg.Subject
+	if !msg.IsRead {
+		subject = output.Bold(subject)
+	}
+	fmt.Printf("Ref: %%%d\n", ref)
+	fmt.Printf("ID: %s\n", msg.ID)
+	fmt.Printf("Subject: %s\n", subject)
+	if msg.From != nil && msg.From.EmailAddress != nil {
+		fmt.Printf("From: %s <%s>\n", msg.From.EmailAddress.Name, msg.From.EmailAddress.Address)
+	}
+	if msg.ReceivedDateTime != nil {
+		fmt.Printf("Received: %s\n", display.When(*msg.ReceivedDateTime))
+	}
+	fmt.Println("---")
+}
+
+// messageListProperties are the message fields mail list prints
+var messageListProperties = []string{"id", "subject", "from", "receivedDateTime", "isRead"}
+
 var mailGetCmd = &cobra.Command{
 	Use:   "get <message-id>",
 	Short: "Get a specific email message",
 	Long:  `Retrieve and display a specific email message by ID`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		messageID := args[0]
-
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
+		messageID, err := resolveRef("messages", args[0])
 		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
+			return err
 		}
 
-		client := libgo365.NewClient(ctx, accessToken)
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
 
 		message, err := client.GetMessage(ctx, messageID)
 		if err != nil {
 			return fmt.Errorf("failed to get message: %w", err)
 		}
 
+		if web, _ := cmd.Flags().GetBool("web"); web {
+			return openWebLink(message.WebLink, "message")
+		}
+
 		// Get output format flags
-		jsonOutput, _ := cmd.Flags().GetBool("json")
 		markdownOutput, _ := cmd.Flags().GetBool("markdown")
 
 		// Convert body to markdown if requested and body is HTML
@@ -404,8 +1143,8 @@ var mailGetCmd = &cobra.Command{
 			message.Body.ContentType = "Markdown"
 		}
 
-		if jsonOutput {
-			return output.WriteJSON(os.Stdout, message)
+		if handled, err := renderItem(cmd, message, messageColumns); handled {
+			return err
 		}
 
 		// Human-readable output
@@ -427,11 +1166,11 @@ var mailGetCmd = &cobra.Command{
 			fmt.Println()
 		}
 		if message.ReceivedDateTime != nil {
-			fmt.Printf("Received: %s\n", message.ReceivedDateTime.Format(time.RFC3339))
+			fmt.Printf("Received: %s\n", displaySettings(ctx, client, config).Format(*message.ReceivedDateTime))
 		}
 		if message.Body != nil {
 			fmt.Printf("\nBody (%s):\n", message.Body.ContentType)
-			fmt.Println(message.Body.Content)
+			fmt.Println(terminalBody(message.Body.ContentType, message.Body.Content))
 		}
 
 		return nil
@@ -441,35 +1180,17 @@ var mailGetCmd = &cobra.Command{
 var mailSendCmd = &cobra.Command{
 	Use:   "send",
 	Short: "Send an email message",
-	Long:  `Send an email message as the authenticated user`,
+	Long: `Send an email message as the authenticated user.
+
+--attach adds a file and can be repeated. Small attachments are sent with
+the message. If they add up to more than 3 MB, the message is saved as a
+draft, the files are uploaded to it in chunks (up to 150 MB each), and the
+draft is sent. Messages sent that way are always saved to Sent Items.`,
+	Example: `  go365 mail send --to alex@contoso.com --subject "Q3 report" --body "Attached." --attach report.pdf
+  go365 mail send --to alex@contoso.com --subject Photos --body "From the offsite" --attach a.jpg --attach b.jpg`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
 
 		// Get required flags
 		subject, _ := cmd.Flags().GetString("subject")
@@ -489,51 +1210,158 @@ var mailSendCmd = &cobra.Command{
 		if body == "" {
 			return fmt.Errorf("body is required")
 		}
-
-		// Parse recipients
-		parseRecipients := func(addresses string) []*libgo365.Recipient {
-			if addresses == "" {
-				return nil
-			}
-			addrs := strings.Split(addresses, ",")
-			recipients := make([]*libgo365.Recipient, 0, len(addrs))
-			for _, addr := range addrs {
-				addr = strings.TrimSpace(addr)
-				if addr != "" {
-					recipients = append(recipients, &libgo365.Recipient{
-						EmailAddress: &libgo365.EmailAddress{
-							Address: addr,
-						},
-					})
-				}
+		contentType, err := libgo365.ParseBodyType(bodyType)
+		if err != nil {
+			return fmt.Errorf("invalid --body-type: %w", err)
+		}
+		var importance libgo365.Importance
+		if value, _ := cmd.Flags().GetString("importance"); value != "" {
+			if importance, err = libgo365.ParseImportance(value); err != nil {
+				return fmt.Errorf("invalid --importance: %w", err)
 			}
-			return recipients
 		}
 
 		message := &libgo365.Message{
 			Subject: subject,
 			Body: &libgo365.ItemBody{
-				ContentType: bodyType,
+				ContentType: string(contentType),
 				Content:     body,
 			},
 			ToRecipients:  parseRecipients(to),
 			CcRecipients:  parseRecipients(cc),
 			BccRecipients: parseRecipients(bcc),
+			Importance:    importance,
+		}
+
+		// Scheduled send: Exchange holds the message in the Outbox
+		result := "Message sent successfully"
+		if sendAtStr, _ := cmd.Flags().GetString("send-at"); sendAtStr != "" {
+			now := time.Now()
+			sendAt, err := dateparse.ParseStrict(sendAtStr, now)
+			if err != nil {
+				return fmt.Errorf("invalid --send-at: %w", err)
+			}
+			if !sendAt.After(now) {
+				return fmt.Errorf("--send-at must be in the future")
+			}
+			message.DeferSend(sendAt)
+			result = "Message scheduled for " + displaySettings(ctx, client, config).Format(sendAt)
+		}
+
+		attachPaths, _ := cmd.Flags().GetStringArray("attach")
+		files, closeFiles, err := openAttachments(attachPaths)
+		if err != nil {
+			return err
 		}
+		defer closeFiles()
 
-		err = client.SendMail(ctx, message, saveToSentItems)
+		if len(files) > 0 {
+			err = client.SendMailWithAttachments(ctx, message, files, saveToSentItems)
+		} else {
+			err = client.SendMail(ctx, message, saveToSentItems)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
 
-		jsonOutput, _ := cmd.Flags().GetBool("json")
 		// --markdown is accepted but is a no-op for send
+		if handled, err := renderItem(cmd, output.FormatActionResponse(true, result), nil); handled {
+			return err
+		}
+
+		fmt.Println(result + "!")
+		return nil
+	},
+}
 
-		if jsonOutput {
-			return output.WriteJSON(os.Stdout, output.FormatActionResponse(true, "Message sent successfully"))
+// parseRecipients turns comma-separated addresses into recipients
+func parseRecipients(addresses string) []*libgo365.Recipient {
+	if addresses == "" {
+		return nil
+	}
+	addrs := strings.Split(addresses, ",")
+	recipients := make([]*libgo365.Recipient, 0, len(addrs))
+	for _, addr := range addrs {
+		addr = strings.TrimSpace(addr)
+		if addr != "" {
+			recipients = append(recipients, &libgo365.Recipient{
+				EmailAddress: &libgo365.EmailAddress{
+					Address: addr,
+				},
+			})
+		}
+	}
+	return recipients
+}
+
+var mailDeleteCmd = &cobra.Command{
+	Use:   "delete [message-id...]",
+	Short: "Delete email messages",
+	Long: `Delete one or more messages (they are moved to Deleted Items).
+With --permanent they are deleted outright and can't be recovered from
+the mailbox.
+
+Run in a terminal without message IDs to pick from recent messages in the
+folder given by --folder-id (default: inbox); the chosen IDs are printed so the
+command can be repeated non-interactively.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !isInteractive() {
+			return fmt.Errorf("at least one message ID is required")
+		}
+
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+
+		messageIDs, err := resolveRefs("messages", splitArgs(args))
+		if err != nil {
+			return err
+		}
+		if len(messageIDs) == 0 {
+			folderID, _ := cmd.Flags().GetString("folder-id")
+			messageIDs, err = pickMessages(ctx, client, folderID, displaySettings(ctx, client, config))
+			if err != nil {
+				return err
+			}
+			if len(messageIDs) == 0 {
+				fmt.Println("No messages selected")
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Selected: go365 mail delete %s\n", strings.Join(messageIDs, " "))
+		}
+
+		permanent, _ := cmd.Flags().GetBool("permanent")
+		summary := fmt.Sprintf("Delete %d message(s)", len(messageIDs))
+		deleteMessages, done := client.DeleteMessages, "Deleted"
+		if permanent {
+			summary = fmt.Sprintf("Permanently delete %d message(s); this can't be undone", len(messageIDs))
+			deleteMessages, done = client.PermanentlyDeleteMessages, "Permanently deleted"
+		}
+		if err := confirm(cmd, summary, messageIDs); err != nil {
+			return err
+		}
+
+		// Several messages go 20 to a $batch call
+		errs, err := deleteMessages(ctx, messageIDs)
+		if err != nil {
+			return fmt.Errorf("failed to delete messages: %w", err)
+		}
+		outcomes := newActionLog(cmd)
+		failed := 0
+		for i, id := range messageIDs {
+			if err := errs[i]; err != nil {
+				outcomes.Failed(id, err, "Failed to delete %s: %v", id, err)
+				failed++
+				continue
+			}
+			outcomes.Done(id, "%s message %s", done, id)
+		}
+		if err := outcomes.Render(); err != nil {
+			return err
 		}
 
-		fmt.Println("Message sent successfully!")
+		if failed > 0 {
+			return fmt.Errorf("failed to delete %d of %d messages", failed, len(messageIDs))
+		}
 		return nil
 	},
 }
@@ -544,12 +1372,11 @@ func init() {
 	mailListCmd.Flags().Int("top", 0, "Number of messages to retrieve (default: 100)")
 	mailListCmd.Flags().Int("skip", 0, "Skip first N messages (offset-based pagination)")
 	mailListCmd.Flags().String("page-token", "", "Continue from previous response (cursor-based pagination)")
-	mailListCmd.Flags().Bool("json", false, "Output as JSON")
-	mailListCmd.Flags().Bool("markdown", false, "Convert HTML body to Markdown (no-op for list)")
-
-	// mail get flags
-	mailGetCmd.Flags().Bool("json", false, "Output as JSON")
-	mailGetCmd.Flags().Bool("markdown", false, "Convert HTML body to Markdown")
+	mailListCmd.Flags().String("user", "", "Read another user's mailbox (email or ID)")
+	mailListCmd.Flags().String("since", "", "Only messages received from the start of this date or period (e.g. yesterday, 'last week')")
+	mailListCmd.Flags().String("until", "", "Only messages received before the end of this date or period")
+	addSelectFlags(mailListCmd, "message")
+	addAllFlags(mailListCmd)
 
 	// mail send flags
 	mailSendCmd.Flags().String("subject", "", "Email subject (required)")
@@ -558,13 +1385,22 @@ func init() {
 	mailSendCmd.Flags().String("body-type", "Text", "Body content type (Text or HTML)")
 	mailSendCmd.Flags().String("cc", "", "CC recipient email address(es), comma-separated")
 	mailSendCmd.Flags().String("bcc", "", "BCC recipient email address(es), comma-separated")
+	mailSendCmd.Flags().String("importance", "", "Importance (low, normal, high)")
 	mailSendCmd.Flags().Bool("save-to-sent-items", true, "Save message to sent items")
-	mailSendCmd.Flags().Bool("json", false, "Output as JSON")
-	mailSendCmd.Flags().Bool("markdown", false, "No-op for send command (accepted for consistency)")
+	mailSendCmd.Flags().String("send-at", "", "Schedule the send, e.g. 'next business day 9am' or 'tomorrow 8:30am'")
+	mailSendCmd.Flags().StringArray("attach", nil, "Attach a file (repeatable); files over 3 MB are uploaded in chunks")
+
+	// mail get flags
+	mailGetCmd.Flags().Bool("web", false, "Open the message in Outlook on the web instead of printing it")
+
+	// mail delete flags
+	mailDeleteCmd.Flags().String("folder-id", "inbox", "Folder to pick messages from when no IDs are given")
+	mailDeleteCmd.Flags().Bool("permanent", false, "Delete outright instead of moving to Deleted Items")
 
 	mailCmd.AddCommand(mailListCmd)
 	mailCmd.AddCommand(mailGetCmd)
 	mailCmd.AddCommand(mailSendCmd)
+	mailCmd.AddCommand(mailDeleteCmd)
 }
 
 var calendarCmd = &cobra.Command{
@@ -578,46 +1414,36 @@ var calendarListCmd = &cobra.Command{
 	Short: "List calendar events",
 	Long:  `List calendar events for a time range. Defaults to today. Accepts natural language dates.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+		var err error
 
 		// Get options from flags
 		startStr, _ := cmd.Flags().GetString("start")
 		endStr, _ := cmd.Flags().GetString("end")
 		days, _ := cmd.Flags().GetInt("days")
+		rangeStr, _ := cmd.Flags().GetString("range")
 		calendarID, _ := cmd.Flags().GetString("calendar-id")
 		allCalendars, _ := cmd.Flags().GetBool("all-calendars")
 		top, _ := cmd.Flags().GetInt("top")
 		pageToken, _ := cmd.Flags().GetString("page-token")
-		jsonOutput, _ := cmd.Flags().GetBool("json")
 		userID, _ := cmd.Flags().GetString("user")
+		all, _ := cmd.Flags().GetBool("all")
 		// --markdown is accepted but is a no-op for list (no body content)
 
+		if all && allCalendars {
+			return fmt.Errorf("--all cannot be combined with --all-calendars")
+		}
+
+		// Fall back to the configured mailbox and calendar; a configured
+		// calendar only applies to the default mailbox
+		if userID == "" {
+			userID = config.Mailbox
+			if calendarID == "" && !allCalendars {
+				calendarID = config.Calendar
+			}
+		}
+
 		// Expand short name to full email if needed
 		if userID != "" {
 			userID, err = expandEmail(ctx, client, userID)
@@ -626,31 +1452,43 @@ var calendarListCmd = &cobra.Command{
 			}
 		}
 
-		// Parse start date (default: today)
+		// --range gives both ends; otherwise start defaults to today and
+		// end to one day after start
 		now := time.Now()
-		var startTime time.Time
-		if startStr == "" {
-			startTime = dateparse.StartOfDay(now)
+		if rangeStr != "" && (startStr != "" || endStr != "" || days > 0) {
+			return fmt.Errorf("--range cannot be combined with --start, --end or --days")
+		}
+		if rangeStr == "" && startStr == "" {
+			rangeStr = "today"
+		}
+
+		var startTime, endTime time.Time
+		if rangeStr != "" {
+			startTime, endTime, err = dateparse.ParseRange(rangeStr, now)
+			if err != nil {
+				return fmt.Errorf("invalid --range: %w", err)
+			}
 		} else {
 			startTime, err = dateparse.Parse(startStr, now)
 			if err != nil {
 				return fmt.Errorf("invalid start date: %w", err)
 			}
+			endTime = dateparse.AddDays(startTime, 1)
 		}
 
-		// Parse end date
-		var endTime time.Time
 		if days > 0 {
-			// --days takes precedence
+			// --days takes precedence over --end
 			endTime = dateparse.AddDays(startTime, days)
 		} else if endStr != "" {
 			endTime, err = dateparse.Parse(endStr, now)
 			if err != nil {
 				return fmt.Errorf("invalid end date: %w", err)
 			}
-		} else {
-			// Default: 1 day from start
-			endTime = dateparse.AddDays(startTime, 1)
+		}
+
+		display, tz, err := eventDisplay(cmd, client, config)
+		if err != nil {
+			return err
 		}
 
 		opts := &libgo365.CalendarViewOptions{
@@ -658,104 +1496,143 @@ var calendarListCmd = &cobra.Command{
 			EndDateTime:   dateparse.FormatISO8601(endTime),
 			CalendarID:    calendarID,
 			AllCalendars:  allCalendars,
+			Concurrency:   concurrency,
 			Top:           top,
 			PageToken:     pageToken,
 			UserID:        userID,
-		}
+			Select:        listProperties(cmd, eventColumns, eventListProperties),
+			TimeZone:      tz,
+		}
+
+		// Human-readable output is printed as events arrive. Listings longer
+		// than a week are grouped under week headings; --all-calendars output
+		// is per calendar, not in time order.
+		if format, err := outputFormat(cmd); err == nil && format == "" {
+			byWeek := endTime.Sub(startTime) > 7*24*time.Hour && !allCalendars
+			var events []*libgo365.Event
+			var week time.Time
+			show := func(event *libgo365.Event) error {
+				if start, ok := graphTime(event.Start); ok && byWeek {
+					if weekStart := dateparse.StartOfWeek(start.In(display.loc)); !weekStart.Equal(week) {
+						week = weekStart
+						fmt.Println(output.Bold("Week of " + week.Format("Mon 2 Jan 2006")))
+						fmt.Println()
+					}
+				}
+				events = append(events, event)
+				printEventSummary(len(events), event, display)
+				return nil
+			}
 
-		resp, err := client.CalendarView(ctx, opts)
-		if err != nil {
-			return fmt.Errorf("failed to list events: %w", err)
-		}
+			nextPageToken := ""
+			if all {
+				var it *libgo365.PageIterator[libgo365.Event]
+				if it, err = client.CalendarViewIterator(opts); err == nil {
+					err = fetchAll(cmd, it, show)
+				}
+			} else {
+				var resp *libgo365.CalendarViewResponse
+				if resp, err = client.StreamCalendarView(ctx, opts, show); err == nil {
+					nextPageToken = resp.NextPageToken
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to list events: %w", err)
+			}
+			rememberEvents(events)
 
-		if jsonOutput {
-			// JSON output matching Graph API structure
-			listResp := output.FormatListResponse(resp.Events, resp.Count, resp.NextPageToken)
-			return output.WriteJSON(os.Stdout, listResp)
-		}
+			if len(events) == 0 {
+				fmt.Println("No events found")
+				return nil
+			}
 
-		// Human-readable output
-		if len(resp.Events) == 0 {
-			fmt.Println("No events found")
+			// Print pagination hint if there are more results
+			output.PrintNextPageHint(os.Stdout, nextPageToken)
 			return nil
 		}
 
-		displayTZ := getDisplayTimezone(config)
-		for _, event := range resp.Events {
-			fmt.Printf("ID: %s\n", event.ID)
-			fmt.Printf("Subject: %s\n", event.Subject)
-			if event.Start != nil {
-				fmt.Printf("Start: %s\n", formatDateTime(event.Start, displayTZ))
-			}
-			if event.End != nil {
-				fmt.Printf("End: %s\n", formatDateTime(event.End, displayTZ))
-			}
-			if event.IsAllDay {
-				fmt.Printf("AllDay: true\n")
-			}
-			if event.Location != nil && event.Location.DisplayName != "" {
-				fmt.Printf("Location: %s\n", event.Location.DisplayName)
-			}
-			if event.Organizer != nil && event.Organizer.EmailAddress != nil {
-				fmt.Printf("Organizer: %s <%s>\n", event.Organizer.EmailAddress.Name, event.Organizer.EmailAddress.Address)
-			}
-			if event.ResponseStatus != nil && event.ResponseStatus.Response != "" {
-				fmt.Printf("Response: %s\n", event.ResponseStatus.Response)
+		if all {
+			it, err := client.CalendarViewIterator(opts)
+			if err != nil {
+				return fmt.Errorf("failed to list events: %w", err)
 			}
-			if event.CalendarID != "" {
-				fmt.Printf("Calendar: %s\n", event.CalendarID)
+			events, err := fetchAllItems(cmd, it)
+			if err != nil {
+				return fmt.Errorf("failed to list events: %w", err)
 			}
-			fmt.Println("---")
+			rememberEvents(events)
+			_, err = renderList(cmd, events, len(events), "", eventColumns)
+			return err
 		}
 
-		// Print pagination hint if there are more results
-		output.PrintNextPageHint(os.Stdout, resp.NextPageToken)
+		resp, err := client.CalendarView(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list events: %w", err)
+		}
+		rememberEvents(resp.Events)
 
-		return nil
+		_, err = renderList(cmd, resp.Events, resp.Count, resp.NextPageToken, eventColumns)
+		return err
 	},
 }
 
+// printEventSummary prints one calendar list entry; ref is its %N reference
+func printEventSummary(ref int, event *libgo365.Event, display *timeDisplay) {
+	fmt.Printf("Ref: %%%d\n", ref)
+	fmt.Printf("ID: %s\n", event.ID)
+	fmt.Printf("Subject: %s\n", eventSubject(event))
+	if event.Start != nil {
+		fmt.Printf("Start: %s\n", eventStart(event, display))
+	}
+	if event.End != nil {
+		fmt.Printf("End: %s\n", formatDateTime(event.End, display))
+	}
+	if event.IsAllDay {
+		fmt.Printf("AllDay: true\n")
+	}
+	if event.Location != nil && event.Location.DisplayName != "" {
+		fmt.Printf("Location: %s\n", event.Location.DisplayName)
+	}
+	if event.Organizer != nil && event.Organizer.EmailAddress != nil {
+		fmt.Printf("Organizer: %s <%s>\n", event.Organizer.EmailAddress.Name, event.Organizer.EmailAddress.Address)
+	}
+	if event.ResponseStatus != nil && event.ResponseStatus.Response != "" {
+		fmt.Printf("Response: %s\n", output.Status(string(event.ResponseStatus.Response)))
+	}
+	if event.CalendarID != "" {
+		fmt.Printf("Calendar: %s\n", event.CalendarID)
+	}
+	fmt.Println("---")
+}
+
+// eventListProperties are the event fields calendar list prints
+var eventListProperties = []string{"id", "subject", "start", "end", "isAllDay", "location", "organizer", "responseStatus"}
+
 var calendarGetCmd = &cobra.Command{
 	Use:   "get <event-id>",
 	Short: "Get a specific calendar event",
 	Long:  `Retrieve and display a specific calendar event by ID`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		eventID := args[0]
-
-		config, err := configMgr.Load()
+		eventID, err := resolveRef("events", args[0])
 		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
+			return err
 		}
 
-		client := libgo365.NewClient(ctx, accessToken)
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
 
 		calendarID, _ := cmd.Flags().GetString("calendar-id")
-		jsonOutput, _ := cmd.Flags().GetBool("json")
 		markdownOutput, _ := cmd.Flags().GetBool("markdown")
 		userID, _ := cmd.Flags().GetString("user")
 
+		if userID == "" {
+			userID = config.Mailbox
+			if calendarID == "" {
+				calendarID = config.Calendar
+			}
+		}
+
 		// Expand short name to full email if needed
 		if userID != "" {
 			userID, err = expandEmail(ctx, client, userID)
@@ -764,38 +1641,50 @@ var calendarGetCmd = &cobra.Command{
 			}
 		}
 
+		display, tz, err := eventDisplay(cmd, client, config)
+		if err != nil {
+			return err
+		}
+
 		event, err := client.GetEventWithOptions(ctx, &libgo365.GetEventOptions{
 			EventID:    eventID,
 			CalendarID: calendarID,
 			UserID:     userID,
+			TimeZone:   tz,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to get event: %w", err)
 		}
 
+		if web, _ := cmd.Flags().GetBool("web"); web {
+			return openWebLink(event.WebLink, "event")
+		}
+
 		// Convert body to markdown if requested and body is HTML
 		if markdownOutput && event.Body != nil && strings.EqualFold(event.Body.ContentType, "HTML") {
 			event.Body.Content = output.HTMLToMarkdown(event.Body.Content)
 			event.Body.ContentType = "Markdown"
 		}
 
-		if jsonOutput {
-			return output.WriteJSON(os.Stdout, event)
+		if handled, err := renderItem(cmd, event, eventColumns); handled {
+			return err
 		}
 
 		// Human-readable output
-		displayTZ := getDisplayTimezone(config)
 		fmt.Printf("ID: %s\n", event.ID)
 		fmt.Printf("Subject: %s\n", event.Subject)
 		if event.Start != nil {
-			fmt.Printf("Start: %s\n", formatDateTime(event.Start, displayTZ))
+			fmt.Printf("Start: %s\n", formatDateTime(event.Start, display))
 		}
 		if event.End != nil {
-			fmt.Printf("End: %s\n", formatDateTime(event.End, displayTZ))
+			fmt.Printf("End: %s\n", formatDateTime(event.End, display))
 		}
 		if event.IsAllDay {
 			fmt.Printf("AllDay: true\n")
 		}
+		if repeats := event.Recurrence.String(); repeats != "" {
+			fmt.Printf("Repeats: %s\n", repeats)
+		}
 		if event.Location != nil && event.Location.DisplayName != "" {
 			fmt.Printf("Location: %s\n", event.Location.DisplayName)
 		}
@@ -803,7 +1692,7 @@ var calendarGetCmd = &cobra.Command{
 			fmt.Printf("Organizer: %s <%s>\n", event.Organizer.EmailAddress.Name, event.Organizer.EmailAddress.Address)
 		}
 		if event.ResponseStatus != nil && event.ResponseStatus.Response != "" {
-			fmt.Printf("Response: %s\n", event.ResponseStatus.Response)
+			fmt.Printf("Response: %s\n", output.Status(string(event.ResponseStatus.Response)))
 		}
 
 		// Attendees
@@ -813,7 +1702,7 @@ var calendarGetCmd = &cobra.Command{
 				if att.EmailAddress != nil {
 					status := ""
 					if att.Status != nil {
-						status = att.Status.Response
+						status = string(att.Status.Response)
 					}
 					fmt.Printf("  - %s <%s> [%s] (%s)\n", att.EmailAddress.Name, att.EmailAddress.Address, att.Type, status)
 				}
@@ -827,7 +1716,7 @@ var calendarGetCmd = &cobra.Command{
 
 		// Body
 		if event.Body != nil && event.Body.Content != "" {
-			fmt.Printf("\nBody (%s):\n%s\n", event.Body.ContentType, event.Body.Content)
+			fmt.Printf("\nBody (%s):\n%s\n", event.Body.ContentType, terminalBody(event.Body.ContentType, event.Body.Content))
 		}
 
 		return nil
@@ -839,43 +1728,16 @@ var calendarCalendarsCmd = &cobra.Command{
 	Short: "List available calendars",
 	Long:  `List all calendars available to the authenticated user`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
-		jsonOutput, _ := cmd.Flags().GetBool("json")
+		ctx := cmd.Context()
+		client := graphClient
 
 		calendars, err := client.ListCalendars(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to list calendars: %w", err)
 		}
 
-		if jsonOutput {
-			listResp := output.FormatListResponse(calendars, len(calendars), "")
-			return output.WriteJSON(os.Stdout, listResp)
+		if handled, err := renderList(cmd, calendars, len(calendars), "", calendarColumns); handled {
+			return err
 		}
 
 		if len(calendars) == 0 {
@@ -902,53 +1764,43 @@ var calendarEventsCmd = &cobra.Command{
 	Short: "List raw calendar events",
 	Long:  `List raw events including series masters for recurring events. Unlike 'list', this doesn't expand recurring events into occurrences.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
 
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
+		calendarID, _ := cmd.Flags().GetString("calendar-id")
+		top, _ := cmd.Flags().GetInt("top")
+		pageToken, _ := cmd.Flags().GetString("page-token")
+		if calendarID == "" {
+			calendarID = config.Calendar
 		}
 
-		accessToken, err := auth.GetAccessToken(ctx)
+		display, tz, err := eventDisplay(cmd, client, config)
 		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
+			return err
 		}
 
-		client := libgo365.NewClient(ctx, accessToken)
-
-		calendarID, _ := cmd.Flags().GetString("calendar-id")
-		top, _ := cmd.Flags().GetInt("top")
-		pageToken, _ := cmd.Flags().GetString("page-token")
-		jsonOutput, _ := cmd.Flags().GetBool("json")
-
 		opts := &libgo365.ListEventsOptions{
 			CalendarID: calendarID,
 			Top:        top,
 			PageToken:  pageToken,
+			Select:     listProperties(cmd, eventColumns, rawEventProperties),
+			TimeZone:   tz,
 		}
 
-		resp, err := client.ListEvents(ctx, opts)
+		resp := &libgo365.ListEventsResponse{}
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			resp.Events, err = fetchAllItems(cmd, client.EventIterator(opts))
+			resp.Count = len(resp.Events)
+		} else {
+			resp, err = client.ListEvents(ctx, opts)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to list events: %w", err)
 		}
+		rememberEvents(resp.Events)
 
-		if jsonOutput {
-			listResp := output.FormatListResponse(resp.Events, resp.Count, resp.NextPageToken)
-			return output.WriteJSON(os.Stdout, listResp)
+		if handled, err := renderList(cmd, resp.Events, resp.Count, resp.NextPageToken, eventColumns); handled {
+			return err
 		}
 
 		if len(resp.Events) == 0 {
@@ -956,15 +1808,18 @@ var calendarEventsCmd = &cobra.Command{
 			return nil
 		}
 
-		displayTZ := getDisplayTimezone(config)
-		for _, event := range resp.Events {
+		for i, event := range resp.Events {
+			fmt.Printf("Ref: %%%d\n", i+1)
 			fmt.Printf("ID: %s\n", event.ID)
-			fmt.Printf("Subject: %s\n", event.Subject)
+			fmt.Printf("Subject: %s\n", eventSubject(event))
 			if event.Start != nil {
-				fmt.Printf("Start: %s\n", formatDateTime(event.Start, displayTZ))
+				fmt.Printf("Start: %s\n", eventStart(event, display))
 			}
 			if event.End != nil {
-				fmt.Printf("End: %s\n", formatDateTime(event.End, displayTZ))
+				fmt.Printf("End: %s\n", formatDateTime(event.End, display))
+			}
+			if repeats := event.Recurrence.String(); repeats != "" {
+				fmt.Printf("Repeats: %s\n", repeats)
 			}
 			fmt.Println("---")
 		}
@@ -974,39 +1829,30 @@ var calendarEventsCmd = &cobra.Command{
 	},
 }
 
+// rawEventProperties are the event fields calendar events prints
+var rawEventProperties = []string{"id", "subject", "start", "end", "isAllDay", "recurrence", "responseStatus"}
+
+// eventSubject returns the subject for human-readable output, dimmed when the
+// invitation was declined
+func eventSubject(event *libgo365.Event) string {
+	if event.ResponseStatus != nil && event.ResponseStatus.Response == libgo365.ResponseDeclined {
+		return output.Dim(event.Subject)
+	}
+	return event.Subject
+}
+
 var calendarRespondCmd = &cobra.Command{
 	Use:   "respond <event-id> <accept|decline|tentative>",
 	Short: "Respond to a calendar invitation",
-	Long:  `Accept, decline, or tentatively accept a calendar invitation.`,
-	Args:  cobra.RangeArgs(1, 2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
+	Long: `Accept, decline, or tentatively accept a calendar invitation.
 
-		client := libgo365.NewClient(ctx, accessToken)
+Run in a terminal without an event ID to pick from your pending invitations;
+the chosen IDs are printed so the command can be repeated non-interactively.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+		var err error
 
 		respondAll, _ := cmd.Flags().GetBool("all")
 		idsStr, _ := cmd.Flags().GetString("ids")
@@ -1029,8 +1875,15 @@ var calendarRespondCmd = &cobra.Command{
 			if err != nil {
 				return fmt.Errorf("failed to list pending events: %w", err)
 			}
+			var subjects []string
 			for _, e := range resp.Events {
 				eventIDs = append(eventIDs, e.ID)
+				subjects = append(subjects, e.Subject)
+			}
+			if len(eventIDs) > 0 {
+				if err := confirm(cmd, fmt.Sprintf("Respond '%s' to %d pending invitation(s)", response, len(eventIDs)), subjects); err != nil {
+					return err
+				}
 			}
 		} else if idsStr != "" {
 			if len(args) < 1 {
@@ -1044,12 +1897,46 @@ var calendarRespondCmd = &cobra.Command{
 					eventIDs = append(eventIDs, p)
 				}
 			}
-		} else {
-			if len(args) < 2 {
-				return fmt.Errorf("usage: calendar respond <event-id> <accept|decline|tentative>")
+			if len(eventIDs) > 1 {
+				if err := confirm(cmd, fmt.Sprintf("Respond '%s' to %d event(s)", response, len(eventIDs)), eventIDs); err != nil {
+					return err
+				}
 			}
+		} else if len(args) == 2 {
 			eventIDs = []string{args[0]}
 			response = args[1]
+		} else if isInteractive() {
+			// A lone argument is either the response type or the event ID
+			if len(args) == 1 {
+				switch args[0] {
+				case "accept", "decline", "tentative":
+					response = args[0]
+				default:
+					eventIDs = []string{args[0]}
+				}
+			}
+			if len(eventIDs) == 0 {
+				eventIDs, err = pickPendingEvents(ctx, client, displaySettings(ctx, client, config))
+				if err != nil {
+					return err
+				}
+			}
+			if response == "" && len(eventIDs) > 0 {
+				response, err = pickResponse()
+				if err != nil {
+					return err
+				}
+			}
+			if len(eventIDs) > 0 {
+				fmt.Fprintf(os.Stderr, "Selected: go365 calendar respond --ids %s %s\n", strings.Join(eventIDs, ","), response)
+			}
+		} else {
+			return fmt.Errorf("usage: calendar respond <event-id> <accept|decline|tentative>")
+		}
+
+		eventIDs, err = resolveRefs("events", eventIDs)
+		if err != nil {
+			return err
 		}
 
 		if len(eventIDs) == 0 {
@@ -1057,16 +1944,21 @@ var calendarRespondCmd = &cobra.Command{
 			return nil
 		}
 
-		for _, eventID := range eventIDs {
-			err := client.RespondToEvent(ctx, eventID, response, message)
-			if err != nil {
-				fmt.Printf("Failed to respond to %s: %v\n", eventID, err)
+		// Several invitations go 20 to a $batch call
+		errs, err := client.RespondToEvents(ctx, eventIDs, response, message)
+		if err != nil {
+			return fmt.Errorf("failed to respond: %w", err)
+		}
+		outcomes := newActionLog(cmd)
+		for i, eventID := range eventIDs {
+			if err := errs[i]; err != nil {
+				outcomes.Failed(eventID, err, "%s %s: %v", output.Red("Failed to respond to"), eventID, err)
 				continue
 			}
-			fmt.Printf("Responded '%s' to event %s\n", response, eventID)
+			outcomes.Done(eventID, "Responded '%s' to event %s", response, eventID)
 		}
 
-		return nil
+		return outcomes.Render()
 	},
 }
 
@@ -1075,34 +1967,8 @@ var calendarPendingCmd = &cobra.Command{
 	Short: "List pending invitations",
 	Long:  `List calendar invitations awaiting your response.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
-		jsonOutput, _ := cmd.Flags().GetBool("json")
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
 		includePast, _ := cmd.Flags().GetBool("include-past")
 
 		// Filter for events where responseStatus is notResponded or none, excluding events we organized
@@ -1121,10 +1987,10 @@ var calendarPendingCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to list events: %w", err)
 		}
+		rememberEvents(resp.Events)
 
-		if jsonOutput {
-			listResp := output.FormatListResponse(resp.Events, resp.Count, resp.NextPageToken)
-			return output.WriteJSON(os.Stdout, listResp)
+		if handled, err := renderList(cmd, resp.Events, resp.Count, resp.NextPageToken, eventColumns); handled {
+			return err
 		}
 
 		if len(resp.Events) == 0 {
@@ -1134,12 +2000,12 @@ var calendarPendingCmd = &cobra.Command{
 
 		fmt.Printf("%d pending invitation(s):\n\n", len(resp.Events))
 
-		displayTZ := getDisplayTimezone(config)
+		display := displaySettings(ctx, client, config)
 		for i, event := range resp.Events {
 			fmt.Printf("%d. %s\n", i+1, event.Subject)
 			fmt.Printf("   ID: %s\n", event.ID)
 			if event.Start != nil {
-				fmt.Printf("   When: %s\n", formatDateTime(event.Start, displayTZ))
+				fmt.Printf("   When: %s\n", eventStart(event, display))
 			}
 			if event.Organizer != nil && event.Organizer.EmailAddress != nil {
 				fmt.Printf("   From: %s\n", event.Organizer.EmailAddress.Address)
@@ -1157,33 +2023,8 @@ var calendarFreeBusyCmd = &cobra.Command{
 	Long:  `Check free/busy status for one or more users. Works for anyone in your organization.`,
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
 
 		// Parse emails from args (may be comma-separated or multiple args)
 		var emails []string
@@ -1198,14 +2039,13 @@ var calendarFreeBusyCmd = &cobra.Command{
 		}
 
 		// Expand short names to full emails
-		emails, err = expandEmails(ctx, client, emails)
+		emails, err := expandEmails(ctx, client, emails)
 		if err != nil {
 			return err
 		}
 
 		startStr, _ := cmd.Flags().GetString("start")
 		endStr, _ := cmd.Flags().GetString("end")
-		jsonOutput, _ := cmd.Flags().GetBool("json")
 
 		now := time.Now()
 		var startTime, endTime time.Time
@@ -1233,11 +2073,11 @@ var calendarFreeBusyCmd = &cobra.Command{
 			return fmt.Errorf("failed to get schedule: %w", err)
 		}
 
-		if jsonOutput {
-			return output.WriteJSON(os.Stdout, resp)
+		if handled, err := renderItem(cmd, resp, nil); handled {
+			return err
 		}
 
-		displayTZ := getDisplayTimezone(config)
+		display := displaySettings(ctx, client, config)
 		for _, schedule := range resp.Value {
 			fmt.Printf("%s:\n", schedule.ScheduleId)
 			if schedule.Error != nil {
@@ -1249,9 +2089,10 @@ var calendarFreeBusyCmd = &cobra.Command{
 				continue
 			}
 			for _, item := range schedule.ScheduleItems {
-				startDT := formatDateTime(item.Start, displayTZ)
-				endDT := formatDateTime(item.End, displayTZ)
-				fmt.Printf("  %s: %s - %s\n", strings.ToUpper(item.Status[:1])+item.Status[1:], startDT, endDT)
+				startDT := formatDateTime(item.Start, display)
+				endDT := formatDateTime(item.End, display)
+				status := output.Status(strings.ToUpper(string(item.Status[:1])) + string(item.Status[1:]))
+				fmt.Printf("  %s: %s - %s\n", status, startDT, endDT)
 			}
 			fmt.Println()
 		}
@@ -1265,40 +2106,14 @@ var calendarFindTimeCmd = &cobra.Command{
 	Short: "Find available meeting times",
 	Long:  `Find available meeting times across attendees' calendars.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
 
 		attendeesStr, _ := cmd.Flags().GetString("attendees")
 		durationStr, _ := cmd.Flags().GetString("duration")
 		startStr, _ := cmd.Flags().GetString("start")
 		endStr, _ := cmd.Flags().GetString("end")
 		maxResults, _ := cmd.Flags().GetInt("max-results")
-		jsonOutput, _ := cmd.Flags().GetBool("json")
 
 		if attendeesStr == "" {
 			return fmt.Errorf("--attendees is required")
@@ -1310,7 +2125,7 @@ var calendarFindTimeCmd = &cobra.Command{
 		}
 
 		// Expand short names to full emails
-		attendees, err = expandEmails(ctx, client, attendees)
+		attendees, err := expandEmails(ctx, client, attendees)
 		if err != nil {
 			return err
 		}
@@ -1318,7 +2133,10 @@ var calendarFindTimeCmd = &cobra.Command{
 		// Parse duration (default 30m)
 		duration := 30
 		if durationStr != "" {
-			d, err := time.ParseDuration(durationStr)
+			if dateparse.IsAllDay(durationStr) {
+				return fmt.Errorf("--duration \"all day\" only applies to calendar create")
+			}
+			d, err := dateparse.ParseDuration(durationStr)
 			if err != nil {
 				return fmt.Errorf("invalid duration: %w", err)
 			}
@@ -1331,7 +2149,7 @@ var calendarFindTimeCmd = &cobra.Command{
 		if startStr == "" {
 			startTime = now.Add(24 * time.Hour) // tomorrow
 		} else {
-			startTime, err = dateparse.Parse(startStr, now)
+			startTime, err = dateparse.ParseStrict(startStr, now)
 			if err != nil {
 				return fmt.Errorf("invalid start time: %w", err)
 			}
@@ -1340,7 +2158,7 @@ var calendarFindTimeCmd = &cobra.Command{
 		if endStr == "" {
 			endTime = startTime.Add(7 * 24 * time.Hour) // +7 days
 		} else {
-			endTime, err = dateparse.Parse(endStr, now)
+			endTime, err = dateparse.ParseStrict(endStr, now)
 			if err != nil {
 				return fmt.Errorf("invalid end time: %w", err)
 			}
@@ -1363,8 +2181,8 @@ var calendarFindTimeCmd = &cobra.Command{
 			return fmt.Errorf("failed to find meeting times: %w", err)
 		}
 
-		if jsonOutput {
-			return output.WriteJSON(os.Stdout, resp)
+		if handled, err := renderItem(cmd, resp, nil); handled {
+			return err
 		}
 
 		if len(resp.Suggestions) == 0 {
@@ -1377,13 +2195,13 @@ var calendarFindTimeCmd = &cobra.Command{
 
 		fmt.Printf("Found %d available slots for %dm meeting:\n\n", len(resp.Suggestions), duration)
 
-		displayTZ := getDisplayTimezone(config)
+		display := displaySettings(ctx, client, config)
 		for i, suggestion := range resp.Suggestions {
 			slot := suggestion.MeetingTimeSlot
 			if slot == nil || slot.Start == nil {
 				continue
 			}
-			fmt.Printf("%d. %s - %s\n", i+1, formatDateTime(slot.Start, displayTZ), formatDateTime(slot.End, displayTZ))
+			fmt.Printf("%d. %s - %s\n", i+1, formatDateTime(slot.Start, display), formatDateTime(slot.End, display))
 			for _, avail := range suggestion.AttendeeAvailability {
 				if avail.Attendee != nil && avail.Attendee.EmailAddress != nil {
 					fmt.Printf("   %s: %s\n", avail.Attendee.EmailAddress.Address, avail.Availability)
@@ -1399,38 +2217,17 @@ var calendarFindTimeCmd = &cobra.Command{
 var calendarCreateCmd = &cobra.Command{
 	Use:   "create <subject>",
 	Short: "Create a calendar event",
-	Long:  `Create a new calendar event with subject, time, and optional attendees.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Create a new calendar event with subject, time, and optional attendees.
+
+--start and --end are parsed strictly: ISO dates, "tomorrow 3pm", "next
+friday 09:30", "dec 25 9am", "in 2 hours". Anything else is rejected, with a
+suggestion for likely typos, rather than guessed at.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		subject := args[0]
 
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
 
 		// Parse flags
 		startStr, _ := cmd.Flags().GetString("start")
@@ -1442,8 +2239,10 @@ var calendarCreateCmd = &cobra.Command{
 		online, _ := cmd.Flags().GetBool("online")
 		allDay, _ := cmd.Flags().GetBool("all-day")
 		calendarID, _ := cmd.Flags().GetString("calendar-id")
-		jsonOutput, _ := cmd.Flags().GetBool("json")
 		tzFlag, _ := cmd.Flags().GetString("timezone")
+		if calendarID == "" {
+			calendarID = config.Calendar
+		}
 
 		if startStr == "" {
 			return fmt.Errorf("--start is required")
@@ -1460,17 +2259,34 @@ var calendarCreateCmd = &cobra.Command{
 		}
 
 		now := time.Now()
-		startTime, err := dateparse.Parse(startStr, now)
+		startTime, err := dateparse.ParseStrict(startStr, now)
 		if err != nil {
 			return fmt.Errorf("invalid start time: %w", err)
 		}
 
+		var showAs libgo365.FreeBusyStatus
+		if value, _ := cmd.Flags().GetString("show-as"); value != "" {
+			if showAs, err = libgo365.ParseFreeBusyStatus(value); err != nil {
+				return fmt.Errorf("invalid --show-as: %w", err)
+			}
+		}
+		var sensitivity libgo365.Sensitivity
+		if value, _ := cmd.Flags().GetString("sensitivity"); value != "" {
+			if sensitivity, err = libgo365.ParseSensitivity(value); err != nil {
+				return fmt.Errorf("invalid --sensitivity: %w", err)
+			}
+		}
+
 		var endTime time.Time
 		if endStr != "" {
-			endTime, err = dateparse.Parse(endStr, now)
+			endTime, err = dateparse.ParseStrict(endStr, now)
 			if err != nil {
 				return fmt.Errorf("invalid end time: %w", err)
 			}
+		} else if dateparse.IsAllDay(durationStr) {
+			allDay = true
+			startTime = dateparse.StartOfDay(startTime)
+			endTime = dateparse.AddDays(startTime, 1)
 		} else if durationStr != "" {
 			duration, err := dateparse.ParseDuration(durationStr)
 			if err != nil {
@@ -1486,6 +2302,8 @@ var calendarCreateCmd = &cobra.Command{
 			Subject:         subject,
 			IsAllDay:        allDay,
 			IsOnlineMeeting: online,
+			ShowAs:          showAs,
+			Sensitivity:     sensitivity,
 			Start: &libgo365.DateTimeTimeZone{
 				DateTime: startTime.Format("2006-01-02T15:04:05"),
 				TimeZone: tz,
@@ -1502,7 +2320,7 @@ var calendarCreateCmd = &cobra.Command{
 
 		if body != "" {
 			event.Body = &libgo365.ItemBody{
-				ContentType: "Text",
+				ContentType: string(libgo365.BodyText),
 				Content:     body,
 			}
 		}
@@ -1521,7 +2339,7 @@ var calendarCreateCmd = &cobra.Command{
 				if email != "" {
 					event.Attendees = append(event.Attendees, &libgo365.Attendee{
 						EmailAddress: &libgo365.EmailAddress{Address: email},
-						Type:         "required",
+						Type:         libgo365.AttendeeRequired,
 					})
 				}
 			}
@@ -1532,18 +2350,18 @@ var calendarCreateCmd = &cobra.Command{
 			return fmt.Errorf("failed to create event: %w", err)
 		}
 
-		if jsonOutput {
-			return output.WriteJSON(os.Stdout, created)
+		if handled, err := renderItem(cmd, created, eventColumns); handled {
+			return err
 		}
 
-		displayTZ := getDisplayTimezone(config)
+		display := displaySettings(ctx, client, config)
 		fmt.Printf("Created event: %s\n", created.Subject)
 		fmt.Printf("ID: %s\n", created.ID)
 		if created.Start != nil {
-			fmt.Printf("Start: %s\n", formatDateTime(created.Start, displayTZ))
+			fmt.Printf("Start: %s\n", formatDateTime(created.Start, display))
 		}
 		if created.End != nil {
-			fmt.Printf("End: %s\n", formatDateTime(created.End, displayTZ))
+			fmt.Printf("End: %s\n", formatDateTime(created.End, display))
 		}
 		if created.OnlineMeeting != nil && created.OnlineMeeting.JoinUrl != "" {
 			fmt.Printf("Teams Link: %s\n", created.OnlineMeeting.JoinUrl)
@@ -1558,34 +2376,35 @@ func init() {
 	calendarListCmd.Flags().String("start", "", "Start date/time (default: today, accepts natural language)")
 	calendarListCmd.Flags().String("end", "", "End date/time (default: start + 1 day)")
 	calendarListCmd.Flags().Int("days", 0, "Number of days from start (overrides --end)")
+	calendarListCmd.Flags().String("range", "", "Period to list, e.g. 'this week', 'next month', Q2, 'last 7 days' (instead of --start/--end)")
 	calendarListCmd.Flags().String("calendar-id", "", "Query specific calendar (default: primary)")
 	calendarListCmd.Flags().Bool("all-calendars", false, "Query all user's calendars")
 	calendarListCmd.Flags().Int("top", 0, "Limit number of results")
 	calendarListCmd.Flags().String("page-token", "", "Pagination token from previous response")
-	calendarListCmd.Flags().Bool("json", false, "Output as JSON")
-	calendarListCmd.Flags().Bool("markdown", false, "Convert HTML body to Markdown (no-op for list)")
 	calendarListCmd.Flags().String("user", "", "View another user's calendar (email or ID)")
+	calendarListCmd.Flags().String("timezone", "", "Zone to show event times in, also used for JSON output (IANA or Windows name; default: GO365_TIMEZONE, the config, then the mailbox setting)")
+	addSelectFlags(calendarListCmd, "event")
+	addAllFlags(calendarListCmd)
 
 	// calendar get flags
 	calendarGetCmd.Flags().String("calendar-id", "", "Calendar containing the event (default: primary)")
-	calendarGetCmd.Flags().Bool("json", false, "Output as JSON")
-	calendarGetCmd.Flags().Bool("markdown", false, "Convert HTML body to Markdown")
 	calendarGetCmd.Flags().String("user", "", "View another user's calendar event (email or ID)")
+	calendarGetCmd.Flags().String("timezone", "", "Zone to show event times in, also used for JSON output (IANA or Windows name; default: GO365_TIMEZONE, the config, then the mailbox setting)")
+	calendarGetCmd.Flags().Bool("web", false, "Open the event in Outlook on the web instead of printing it")
 
 	calendarCmd.AddCommand(calendarListCmd)
 	calendarCmd.AddCommand(calendarGetCmd)
 
 	// calendar calendars flags
-	calendarCalendarsCmd.Flags().Bool("json", false, "Output as JSON")
-	calendarCalendarsCmd.Flags().Bool("markdown", false, "Convert HTML to Markdown (no-op)")
 	calendarCmd.AddCommand(calendarCalendarsCmd)
 
 	// calendar events flags
 	calendarEventsCmd.Flags().String("calendar-id", "", "Query specific calendar")
 	calendarEventsCmd.Flags().Int("top", 0, "Limit number of results")
 	calendarEventsCmd.Flags().String("page-token", "", "Pagination token")
-	calendarEventsCmd.Flags().Bool("json", false, "Output as JSON")
-	calendarEventsCmd.Flags().Bool("markdown", false, "Convert HTML to Markdown (no-op for list)")
+	calendarEventsCmd.Flags().String("timezone", "", "Zone to show event times in, also used for JSON output (IANA or Windows name; default: GO365_TIMEZONE, the config, then the mailbox setting)")
+	addSelectFlags(calendarEventsCmd, "event")
+	addAllFlags(calendarEventsCmd)
 	calendarCmd.AddCommand(calendarEventsCmd)
 
 	// calendar respond flags
@@ -1595,105 +2414,70 @@ func init() {
 	calendarCmd.AddCommand(calendarRespondCmd)
 
 	// calendar pending flags
-	calendarPendingCmd.Flags().Bool("json", false, "Output as JSON")
-	calendarPendingCmd.Flags().Bool("markdown", false, "Convert HTML to Markdown (no-op)")
 	calendarPendingCmd.Flags().Bool("include-past", false, "Include past events")
 	calendarCmd.AddCommand(calendarPendingCmd)
 
 	// calendar free-busy flags
 	calendarFreeBusyCmd.Flags().String("start", "", "Start date/time (default: now)")
 	calendarFreeBusyCmd.Flags().String("end", "", "End date/time (default: start + 1 day)")
-	calendarFreeBusyCmd.Flags().Bool("json", false, "Output as JSON")
-	calendarFreeBusyCmd.Flags().Bool("markdown", false, "Convert HTML to Markdown (no-op)")
 	calendarCmd.AddCommand(calendarFreeBusyCmd)
 
 	// calendar find-time flags
 	calendarFindTimeCmd.Flags().String("attendees", "", "Comma-separated email addresses (required)")
-	calendarFindTimeCmd.Flags().String("duration", "30m", "Meeting duration (e.g., 30m, 1h)")
+	calendarFindTimeCmd.Flags().String("duration", "30m", "Meeting duration (e.g., 30m, 1h30, \"90 minutes\")")
 	calendarFindTimeCmd.Flags().String("start", "", "Search window start (default: tomorrow)")
 	calendarFindTimeCmd.Flags().String("end", "", "Search window end (default: start + 7 days)")
 	calendarFindTimeCmd.Flags().Int("max-results", 5, "Maximum suggestions to return")
-	calendarFindTimeCmd.Flags().Bool("json", false, "Output as JSON")
-	calendarFindTimeCmd.Flags().Bool("markdown", false, "Convert HTML to Markdown (no-op)")
 	calendarCmd.AddCommand(calendarFindTimeCmd)
 
 	// calendar create flags
-	calendarCreateCmd.Flags().String("start", "", "Start date/time (required, accepts natural language)")
+	calendarCreateCmd.Flags().String("start", "", "Start date/time (required), e.g. 'tomorrow 3pm', 2025-01-20T15:00")
 	calendarCreateCmd.Flags().String("end", "", "End date/time")
-	calendarCreateCmd.Flags().String("duration", "", "Duration (e.g., 30m, 1h) - alternative to --end")
+	calendarCreateCmd.Flags().String("duration", "", "Duration (e.g., 30m, 1h30, \"2 days\", \"all day\") - alternative to --end")
 	calendarCreateCmd.Flags().String("attendees", "", "Comma-separated email addresses")
 	calendarCreateCmd.Flags().String("location", "", "Location")
 	calendarCreateCmd.Flags().String("body", "", "Description/agenda")
 	calendarCreateCmd.Flags().Bool("online", false, "Generate Teams meeting link")
 	calendarCreateCmd.Flags().Bool("all-day", false, "All-day event")
+	calendarCreateCmd.Flags().String("show-as", "", "Show the time as free, tentative, busy, oof or workingElsewhere (default busy)")
+	calendarCreateCmd.Flags().String("sensitivity", "", "Sensitivity (normal, personal, private, confidential)")
 	calendarCreateCmd.Flags().String("calendar-id", "", "Target calendar")
 	calendarCreateCmd.Flags().String("timezone", "", "IANA timezone (e.g., Pacific/Auckland) - defaults to mailbox setting")
-	calendarCreateCmd.Flags().Bool("json", false, "Output as JSON")
-	calendarCreateCmd.Flags().Bool("markdown", false, "Convert HTML to Markdown (no-op)")
 	calendarCmd.AddCommand(calendarCreateCmd)
 }
 
-// getDisplayTimezone returns the timezone for displaying times.
-// Checks: GO365_TIMEZONE env, TZ env, config, then falls back to system local.
-func getDisplayTimezone(config *libgo365.Config) string {
-	if tz := os.Getenv("GO365_TIMEZONE"); tz != "" {
-		return tz
-	}
-	if tz := os.Getenv("TZ"); tz != "" {
-		return tz
-	}
-	if config != nil && config.TimeZone != "" {
-		return config.TimeZone
-	}
-	// Fall back to system local - try to get IANA name
-	return time.Local.String()
-}
-
-// formatDateTime formats a DateTimeTimeZone for display, converting to local time
-// and showing the original timezone if different.
-// Example: "Tue 21 Jan 2026 09:00 AEDT (12:00 Pacific/Auckland)"
-func formatDateTime(dt *libgo365.DateTimeTimeZone, localTZ string) string {
+// formatDateTime formats a DateTimeTimeZone for display in the user's zone,
+// adding the event's own time when it was set in a different zone.
+// Example: "Tue 21 Jan 2026 09:00 AEDT (11:00 Pacific/Auckland)"
+func formatDateTime(dt *libgo365.DateTimeTimeZone, display *timeDisplay) string {
 	if dt == nil {
 		return ""
 	}
 
 	// Parse the datetime in its original timezone
-	origLoc, err := time.LoadLocation(dt.TimeZone)
-	if err != nil {
+	origLoc, err := libgo365.LoadLocation(dt.TimeZone)
+	if err != nil || len(dt.DateTime) < 19 {
 		// Fall back to just showing what we have
 		return fmt.Sprintf("%s (%s)", dt.DateTime, dt.TimeZone)
 	}
 
-	// Parse the datetime string (Graph API format: 2025-12-27T16:00:00.0000000)
+	// Graph API format: 2025-12-27T16:00:00.0000000
 	t, err := time.ParseInLocation("2006-01-02T15:04:05", dt.DateTime[:19], origLoc)
 	if err != nil {
-		// Try without truncation
-		t, err = time.ParseInLocation("2006-01-02T15:04:05.0000000", dt.DateTime, origLoc)
-		if err != nil {
-			return fmt.Sprintf("%s (%s)", dt.DateTime, dt.TimeZone)
-		}
-	}
-
-	// Load local timezone for conversion
-	localLoc, err := time.LoadLocation(localTZ)
-	if err != nil {
-		localLoc = time.Local
+		return fmt.Sprintf("%s (%s)", dt.DateTime, dt.TimeZone)
 	}
 
-	// Convert to local time
-	localTime := t.In(localLoc)
+	localStr := display.Format(t)
 
-	// Format local time
-	localStr := localTime.Format("Mon 2 Jan 2006 15:04 MST")
-
-	// If same timezone, just show local
-	if dt.TimeZone == localTZ || t.Equal(localTime) {
+	// Graph reports times in UTC unless asked otherwise, which says nothing
+	// about the event; only a zone the organizer chose is worth repeating
+	_, origOffset := t.Zone()
+	_, localOffset := t.In(display.loc).Zone()
+	if dt.TimeZone == display.tz || origLoc == time.UTC || origOffset == localOffset {
 		return localStr
 	}
 
-	// Show local time with original in parentheses
-	origStr := t.Format("15:04")
-	return fmt.Sprintf("%s (%s %s)", localStr, origStr, dt.TimeZone)
+	return fmt.Sprintf("%s (%s %s)", localStr, t.Format(display.clockLayout()), dt.TimeZone)
 }
 
 // expandEmail expands a short name (without @) to a full email using the current user's domain.
@@ -1799,34 +2583,8 @@ var driveCmd = &cobra.Command{
 	Long:  `List, download, upload, and manage files in OneDrive.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Default: show drive info
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
-		jsonOutput, _ := cmd.Flags().GetBool("json")
+		ctx := cmd.Context()
+		client := graphClient
 		userID, _ := cmd.Flags().GetString("user")
 
 		var driveOpts *libgo365.GetDriveOptions
@@ -1843,8 +2601,8 @@ var driveCmd = &cobra.Command{
 			return fmt.Errorf("failed to get drive info: %w", err)
 		}
 
-		if jsonOutput {
-			return output.WriteJSON(os.Stdout, drive)
+		if handled, err := renderItem(cmd, drive, nil); handled {
+			return err
 		}
 
 		fmt.Printf("Drive: %s\n", drive.Name)
@@ -1869,34 +2627,8 @@ var driveLsCmd = &cobra.Command{
 	Short: "List folder contents",
 	Long:  `List files and folders. Defaults to root. Use / for root or /path/to/folder.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
-		jsonOutput, _ := cmd.Flags().GetBool("json")
+		ctx := cmd.Context()
+		client := graphClient
 		userID, _ := cmd.Flags().GetString("user")
 
 		path := "/"
@@ -1904,7 +2636,9 @@ var driveLsCmd = &cobra.Command{
 			path = args[0]
 		}
 
-		opts := &libgo365.ListItemsOptions{}
+		opts := &libgo365.ListItemsOptions{
+			Select: listProperties(cmd, driveItemColumns, driveItemProperties),
+		}
 		if userID != "" {
 			expanded, err := expandEmail(ctx, client, userID)
 			if err != nil {
@@ -1913,14 +2647,20 @@ var driveLsCmd = &cobra.Command{
 			opts.UserID = expanded
 		}
 
-		resp, err := client.ListItems(ctx, path, opts)
+		resp := &libgo365.ListItemsResponse{}
+		var err error
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			resp.Items, err = fetchAllItems(cmd, client.DriveItemIterator(path, opts))
+			resp.Count = len(resp.Items)
+		} else {
+			resp, err = client.ListItems(ctx, path, opts)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to list items: %w", err)
 		}
 
-		if jsonOutput {
-			listResp := output.FormatListResponse(resp.Items, resp.Count, resp.NextPageToken)
-			return output.WriteJSON(os.Stdout, listResp)
+		if handled, err := renderList(cmd, resp.Items, resp.Count, resp.NextPageToken, driveItemColumns); handled {
+			return err
 		}
 
 		if len(resp.Items) == 0 {
@@ -1945,45 +2685,25 @@ var driveLsCmd = &cobra.Command{
 			}
 			fmt.Printf("%s  %-30s  %s  %8s  %s\n", mode, name, modified, size, item.ID)
 		}
+		if resp.HasMore {
+			fmt.Fprintln(os.Stderr, "More items not shown; use --all to list them")
+		}
 
 		return nil
 	},
 }
 
+// driveItemProperties are the item fields drive ls prints
+var driveItemProperties = []string{"id", "name", "folder", "size", "lastModifiedDateTime"}
+
 var driveInfoCmd = &cobra.Command{
 	Use:   "info <path-or-id>",
 	Short: "Get item metadata",
 	Long:  `Display detailed metadata for a file or folder.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
-		jsonOutput, _ := cmd.Flags().GetBool("json")
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
 		userID, _ := cmd.Flags().GetString("user")
 
 		var opts *libgo365.GetItemOptions
@@ -2000,8 +2720,8 @@ var driveInfoCmd = &cobra.Command{
 			return fmt.Errorf("failed to get item: %w", err)
 		}
 
-		if jsonOutput {
-			return output.WriteJSON(os.Stdout, item)
+		if handled, err := renderItem(cmd, item, driveItemColumns); handled {
+			return err
 		}
 
 		fmt.Printf("ID: %s\n", item.ID)
@@ -2018,11 +2738,12 @@ var driveInfoCmd = &cobra.Command{
 				fmt.Printf("MIME: %s\n", item.File.MimeType)
 			}
 		}
+		display := displaySettings(ctx, client, config)
 		if item.CreatedDateTime != nil {
-			fmt.Printf("Created: %s\n", item.CreatedDateTime.Format(time.RFC3339))
+			fmt.Printf("Created: %s\n", display.Format(*item.CreatedDateTime))
 		}
 		if item.LastModifiedDateTime != nil {
-			fmt.Printf("Modified: %s\n", item.LastModifiedDateTime.Format(time.RFC3339))
+			fmt.Printf("Modified: %s\n", display.Format(*item.LastModifiedDateTime))
 		}
 		if item.ParentReference != nil && item.ParentReference.Path != "" {
 			fmt.Printf("Path: %s\n", item.ParentReference.Path)
@@ -2039,33 +2760,8 @@ var driveCatCmd = &cobra.Command{
 	Long:  `Download and output file contents to stdout. Useful for piping.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
+		ctx := cmd.Context()
+		client := graphClient
 		userID, _ := cmd.Flags().GetString("user")
 
 		var opts *libgo365.GetItemOptions
@@ -2077,7 +2773,7 @@ var driveCatCmd = &cobra.Command{
 			opts = &libgo365.GetItemOptions{UserID: expanded}
 		}
 
-		err = client.DownloadItem(ctx, args[0], os.Stdout, opts)
+		err := client.DownloadItem(ctx, args[0], os.Stdout, opts)
 		if err != nil {
 			return fmt.Errorf("failed to download: %w", err)
 		}
@@ -2086,41 +2782,34 @@ var driveCatCmd = &cobra.Command{
 	},
 }
 
+// downloadResult is what drive get reports for a downloaded file
+type downloadResult struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+var downloadResultColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Path: "id"},
+		{Name: "name", Path: "name"},
+		{Name: "path", Path: "path"},
+		{Name: "size", Path: "size"},
+	},
+	Defaults: []string{"path", "size"},
+}
+
 var driveGetCmd = &cobra.Command{
 	Use:   "get <path-or-id>",
 	Short: "Download file to local filesystem",
-	Long:  `Download a file to the current directory or specified output path.`,
+	Long:  `Download a file to the current directory, or to the path given with --out.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
+		ctx := cmd.Context()
+		client := graphClient
 		userID, _ := cmd.Flags().GetString("user")
-		outputPath, _ := cmd.Flags().GetString("output")
+		outputPath, _ := cmd.Flags().GetString("out")
 
 		var opts *libgo365.GetItemOptions
 		if userID != "" {
@@ -2157,6 +2846,10 @@ var driveGetCmd = &cobra.Command{
 			return fmt.Errorf("failed to download: %w", err)
 		}
 
+		result := &downloadResult{ID: item.ID, Name: item.Name, Path: outputPath, Size: item.Size}
+		if handled, err := renderItem(cmd, result, downloadResultColumns); handled {
+			return err
+		}
 		fmt.Printf("Downloaded: %s (%s)\n", outputPath, formatBytes(item.Size))
 		return nil
 	},
@@ -2168,34 +2861,8 @@ var driveFindCmd = &cobra.Command{
 	Long:  `Search for files and folders matching a query.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configMgr.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		authConfig := libgo365.AuthConfig{
-			TenantID: config.TenantID,
-			ClientID: config.ClientID,
-			Scopes:   config.Scopes,
-		}
-
-		auth, err := libgo365.NewAuthenticator(authConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create authenticator: %w", err)
-		}
-
-		ctx := context.Background()
-		if !auth.IsAuthenticated(ctx) {
-			return fmt.Errorf("not authenticated. Please run 'go365 login' first")
-		}
-
-		accessToken, err := auth.GetAccessToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %w", err)
-		}
-
-		client := libgo365.NewClient(ctx, accessToken)
-		jsonOutput, _ := cmd.Flags().GetBool("json")
+		ctx := cmd.Context()
+		client := graphClient
 		userID, _ := cmd.Flags().GetString("user")
 
 		opts := &libgo365.ListItemsOptions{}
@@ -2212,9 +2879,8 @@ var driveFindCmd = &cobra.Command{
 			return fmt.Errorf("failed to search: %w", err)
 		}
 
-		if jsonOutput {
-			listResp := output.FormatListResponse(resp.Items, resp.Count, resp.NextPageToken)
-			return output.WriteJSON(os.Stdout, listResp)
+		if handled, err := renderList(cmd, resp.Items, resp.Count, resp.NextPageToken, driveItemColumns); handled {
+			return err
 		}
 
 		if len(resp.Items) == 0 {
@@ -2242,15 +2908,14 @@ var driveFindCmd = &cobra.Command{
 }
 
 func init() {
-	driveCmd.Flags().Bool("json", false, "Output as JSON")
 	driveCmd.Flags().String("user", "", "Access another user's OneDrive")
 	driveCmd.Flags().String("site", "", "Access SharePoint site drive")
 
-	driveLsCmd.Flags().Bool("json", false, "Output as JSON")
 	driveLsCmd.Flags().String("user", "", "Access another user's OneDrive")
+	addSelectFlags(driveLsCmd, "item")
+	addAllFlags(driveLsCmd)
 	driveCmd.AddCommand(driveLsCmd)
 
-	driveInfoCmd.Flags().Bool("json", false, "Output as JSON")
 	driveInfoCmd.Flags().String("user", "", "Access another user's OneDrive")
 	driveCmd.AddCommand(driveInfoCmd)
 
@@ -2258,10 +2923,9 @@ func init() {
 	driveCmd.AddCommand(driveCatCmd)
 
 	driveGetCmd.Flags().String("user", "", "Access another user's OneDrive")
-	driveGetCmd.Flags().StringP("output", "o", "", "Output file path (default: original filename)")
+	driveGetCmd.Flags().String("out", "", "Output file path (default: original filename)")
 	driveCmd.AddCommand(driveGetCmd)
 
-	driveFindCmd.Flags().Bool("json", false, "Output as JSON")
 	driveFindCmd.Flags().String("user", "", "Access another user's OneDrive")
 	driveCmd.AddCommand(driveFindCmd)
 
@@ -2283,15 +2947,30 @@ func main() {
 
 		// If not a known command and not a flag, try plugin
 		if !isKnownCmd && cmdName != "" && !strings.HasPrefix(cmdName, "-") {
-			if err := plugin.ExecutePlugin(cmdName, os.Args[2:]); err == nil {
-				return
+			// Only build the plugin context (which may refresh the token) for
+			// names that resolve to a plugin
+			if _, err := plugin.FindPlugin(cmdName); err == nil {
+				if err := plugin.ExecutePluginWithOptions(cmdName, os.Args[2:], pluginExecOptions()); err == nil {
+					return
+				}
 			}
 			// If plugin fails, fall through to normal cobra execution
 			// which will show the "unknown command" error
 		}
 	}
 
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	if needsPluginCommands(os.Args[1:]) {
+		registerPluginCommands()
+	}
+
+	err := rootCmd.Execute()
+	finishLogging(err)
+	saveHistory(err)
+	closePager()
+	if err != nil {
+		if jsonErrors {
+			writeJSONError(os.Stderr, err)
+		}
+		os.Exit(exitCode(err))
 	}
 }
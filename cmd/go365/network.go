@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/njt/go365/libgo365"
+)
+
+// networkEnv maps the environment variables that override the network
+// settings for a run to the config fields they set
+var networkEnv = []struct {
+	name  string
+	field func(*libgo365.Config) *string
+}{
+	{"GO365_PROXY", func(c *libgo365.Config) *string { return &c.ProxyURL }},
+	{"GO365_CA_FILE", func(c *libgo365.Config) *string { return &c.CAFile }},
+	{"GO365_MIN_TLS_VERSION", func(c *libgo365.Config) *string { return &c.MinTLSVersion }},
+	{"GO365_DIAL_TIMEOUT", func(c *libgo365.Config) *string { return &c.DialTimeout }},
+	{"GO365_RESPONSE_TIMEOUT", func(c *libgo365.Config) *string { return &c.ResponseTimeout }},
+}
+
+// setupNetwork applies the GO365_PROXY and similar environment variables over
+// the configured network settings
+func setupNetwork() {
+	set := false
+	for _, env := range networkEnv {
+		if value := os.Getenv(env.name); value != "" {
+			*env.field(runOverrides) = value
+			set = true
+		}
+	}
+	if set {
+		configMgr.SetOverride(runOverrides)
+	}
+}
+
+// transport is the HTTP transport built by httpTransport, shared by the
+// Graph client and the authenticator so they pool connections
+var transport *http.Transport
+
+// httpTransport returns the transport for config's proxy, CA file, TLS and
+// timeout settings. Only the first call builds it; every config loaded in a
+// run has the same network settings.
+func httpTransport(config *libgo365.Config) (*http.Transport, error) {
+	if transport != nil {
+		return transport, nil
+	}
+	opts, err := config.TransportOptions()
+	if err != nil {
+		return nil, err
+	}
+	t, err := libgo365.NewTransport(opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	transport = t
+	return transport, nil
+}
+
+// redactProxy hides the password in a proxy URL for display
+func redactProxy(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return proxy
+	}
+	return u.Redacted()
+}
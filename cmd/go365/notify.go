@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/njt/go365/internal/dateparse"
+	"github.com/njt/go365/internal/notify"
+	"github.com/njt/go365/libgo365"
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Show desktop notifications for new mail and upcoming meetings",
+	Long: `Watch your mailbox and calendar and raise native desktop notifications.
+
+New mail is tracked with a Graph delta query, so each poll only fetches
+messages that arrived since the last one. Meetings are announced --lead before
+they start. Where the desktop supports it (notify-send on Linux), notifications
+have Open and Join buttons.
+
+Runs until interrupted; start it in the background or from your session's
+autostart, for example:
+
+  go365 notify --mail --calendar &`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		watchMail, _ := cmd.Flags().GetBool("mail")
+		watchCalendar, _ := cmd.Flags().GetBool("calendar")
+		folderID, _ := cmd.Flags().GetString("folder-id")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		lead, _ := cmd.Flags().GetDuration("lead")
+
+		// Neither flag means both
+		if !watchMail && !watchCalendar {
+			watchMail, watchCalendar = true, true
+		}
+		if interval < 10*time.Second {
+			return fmt.Errorf("--interval must be at least 10s")
+		}
+
+		config, err := configMgr.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		auth, err := newAuthenticator(config)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if !auth.IsAuthenticated(ctx) {
+			return errNotAuthenticated
+		}
+
+		// Notifications outlive the terminal; keep the browser launcher quiet
+		browser.Stdout = io.Discard
+		browser.Stderr = io.Discard
+
+		w := &notifyWatcher{
+			folderID: folderID,
+			lead:     lead,
+			started:  time.Now(),
+			notified: map[string]bool{},
+			display:  displaySettings(ctx, nil, config),
+		}
+
+		// Tokens expire while the watcher runs; auth refreshes them silently.
+		// Each poll must see what is there now, not a cached copy.
+		client, err := newClient(ctx, config, auth)
+		if err != nil {
+			return err
+		}
+		client.SetCache(nil)
+
+		fmt.Fprintf(os.Stderr, "Watching for notifications every %s (Ctrl+C to stop)\n", interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if watchMail {
+				if err := w.pollMail(ctx, client); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to check mail: %v\n", err)
+				}
+			}
+			if watchCalendar {
+				if err := w.pollCalendar(ctx, client); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to check calendar: %v\n", err)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// notifyWatcher holds the state carried between polls
+type notifyWatcher struct {
+	folderID  string
+	lead      time.Duration
+	started   time.Time
+	deltaLink string
+	notified  map[string]bool // Event occurrences already announced
+	display   *timeDisplay
+}
+
+// pollMail notifies for messages received since the previous poll
+func (w *notifyWatcher) pollMail(ctx context.Context, client *libgo365.Client) error {
+	delta, err := client.MessagesDelta(ctx, w.folderID, w.deltaLink, w.started)
+	if err != nil {
+		// A stale delta link can't be resumed; start over from now
+		if w.deltaLink != "" && errors.Is(err, libgo365.ErrGone) {
+			w.deltaLink = ""
+			w.started = time.Now()
+		}
+		return err
+	}
+
+	// The first round only establishes where to start
+	first := w.deltaLink == ""
+	w.deltaLink = delta.DeltaLink
+	if first {
+		return nil
+	}
+
+	for _, msg := range delta.Messages {
+		if msg.IsRead {
+			continue
+		}
+		from := "New message"
+		if msg.From != nil && msg.From.EmailAddress != nil {
+			from = msg.From.EmailAddress.Name
+			if from == "" {
+				from = msg.From.EmailAddress.Address
+			}
+		}
+		n := &notify.Notification{
+			Title:  from,
+			Body:   msg.Subject,
+			Urgent: msg.Importance == libgo365.ImportanceHigh,
+		}
+		if msg.WebLink != "" {
+			n.Actions = []notify.Action{{Key: "open", Label: "Open"}}
+		}
+		slog.Debug("notifying new mail", "id", msg.ID, "subject", msg.Subject)
+		sendNotification(ctx, n, map[string]string{"open": msg.WebLink})
+	}
+	return nil
+}
+
+// pollCalendar notifies once for each meeting starting within the lead time
+func (w *notifyWatcher) pollCalendar(ctx context.Context, client *libgo365.Client) error {
+	now := time.Now()
+	resp, err := client.CalendarView(ctx, &libgo365.CalendarViewOptions{
+		StartDateTime: dateparse.FormatISO8601(now),
+		EndDateTime:   dateparse.FormatISO8601(now.Add(w.lead)),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, event := range resp.Events {
+		if event.IsAllDay || event.IsCancelled || (event.ResponseStatus != nil && event.ResponseStatus.Response == libgo365.ResponseDeclined) {
+			continue
+		}
+		start, ok := eventStartTime(event)
+		if !ok || start.Before(now) {
+			continue
+		}
+
+		// Recurring occurrences share an ID with the series start, so key on both
+		key := event.ID + "@" + start.UTC().Format(time.RFC3339)
+		if w.notified[key] {
+			continue
+		}
+		w.notified[key] = true
+
+		body := fmt.Sprintf("Starts at %s (in %d min)", w.display.Clock(start), int(time.Until(start).Round(time.Minute).Minutes()))
+		if event.Location != nil && event.Location.DisplayName != "" {
+			body += " · " + event.Location.DisplayName
+		}
+
+		n := &notify.Notification{Title: event.Subject, Body: body, Urgent: true}
+		urls := map[string]string{"open": event.WebLink}
+		if event.OnlineMeeting != nil && event.OnlineMeeting.JoinUrl != "" {
+			n.Actions = append(n.Actions, notify.Action{Key: "join", Label: "Join"})
+			urls["join"] = event.OnlineMeeting.JoinUrl
+		}
+		if event.WebLink != "" {
+			n.Actions = append(n.Actions, notify.Action{Key: "open", Label: "Open"})
+		}
+		slog.Debug("notifying upcoming event", "id", event.ID, "subject", event.Subject, "start", start)
+		sendNotification(ctx, n, urls)
+	}
+	return nil
+}
+
+// sendNotification shows n without blocking the watcher and opens the URL for
+// whichever action the user picks
+func sendNotification(ctx context.Context, n *notify.Notification, urls map[string]string) {
+	go func() {
+		action, err := notify.Send(ctx, n)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return
+		}
+		if url := urls[action]; url != "" {
+			browser.OpenURL(url)
+		}
+	}()
+}
+
+// eventStartTime parses an event's start in its own time zone
+func eventStartTime(event *libgo365.Event) (time.Time, bool) {
+	return graphTime(event.Start)
+}
+
+func init() {
+	notifyCmd.Flags().Bool("mail", false, "Notify for new mail")
+	notifyCmd.Flags().Bool("calendar", false, "Notify for upcoming meetings")
+	notifyCmd.Flags().String("folder-id", "inbox", "Mail folder to watch")
+	notifyCmd.Flags().Duration("interval", time.Minute, "How often to check")
+	notifyCmd.Flags().Duration("lead", 5*time.Minute, "How long before a meeting to notify")
+
+	notifyCmd.RegisterFlagCompletionFunc("folder-id", completeMailFolders)
+	rootCmd.AddCommand(notifyCmd)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var orgContactsCmd = &cobra.Command{
+	Use:   "orgcontacts",
+	Short: "Look up organizational contacts",
+	Long:  `List and search directory (GAL-only) contacts such as external partners synced into the directory.`,
+}
+
+var orgContactsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List organizational contacts",
+	Long:  `List organizational contacts in the directory.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+
+		top, _ := cmd.Flags().GetInt("top")
+		pageToken, _ := cmd.Flags().GetString("page-token")
+
+		resp, err := client.ListOrgContacts(ctx, &libgo365.ListOrgContactsOptions{
+			Top:       top,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list org contacts: %w", err)
+		}
+
+		return printOrgContacts(cmd, resp)
+	},
+}
+
+var orgContactsSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search organizational contacts",
+	Long:  `Search organizational contacts whose display name, first name, last name, or email starts with the query.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+
+		top, _ := cmd.Flags().GetInt("top")
+		pageToken, _ := cmd.Flags().GetString("page-token")
+
+		resp, err := client.ListOrgContacts(ctx, &libgo365.ListOrgContactsOptions{
+			Query:     args[0],
+			Top:       top,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to search org contacts: %w", err)
+		}
+
+		return printOrgContacts(cmd, resp)
+	},
+}
+
+// printOrgContacts prints org contacts in the selected output format or as
+// human-readable blocks
+func printOrgContacts(cmd *cobra.Command, resp *libgo365.ListOrgContactsResponse) error {
+	if handled, err := renderList(cmd, resp.Contacts, resp.Count, resp.NextPageToken, orgContactColumns); handled {
+		return err
+	}
+
+	if len(resp.Contacts) == 0 {
+		fmt.Println("No contacts found")
+		return nil
+	}
+
+	for _, contact := range resp.Contacts {
+		fmt.Printf("ID: %s\n", contact.ID)
+		fmt.Printf("Name: %s\n", contact.DisplayName)
+		if contact.Mail != "" {
+			fmt.Printf("Email: %s\n", contact.Mail)
+		}
+		if contact.CompanyName != "" {
+			fmt.Printf("Company: %s\n", contact.CompanyName)
+		}
+		if contact.JobTitle != "" {
+			fmt.Printf("Title: %s\n", contact.JobTitle)
+		}
+		for _, phone := range contact.Phones {
+			if phone.Number != "" {
+				fmt.Printf("Phone (%s): %s\n", phone.Type, phone.Number)
+			}
+		}
+		fmt.Println("---")
+	}
+
+	output.PrintNextPageHint(os.Stdout, resp.NextPageToken)
+	return nil
+}
+
+func init() {
+	requireGraph(orgContactsListCmd, orgContactsSearchCmd)
+
+	orgContactsListCmd.Flags().Int("top", 0, "Limit number of results")
+	orgContactsListCmd.Flags().String("page-token", "", "Pagination token from previous response")
+	orgContactsCmd.AddCommand(orgContactsListCmd)
+
+	orgContactsSearchCmd.Flags().Int("top", 0, "Limit number of results")
+	orgContactsSearchCmd.Flags().String("page-token", "", "Pagination token from previous response")
+	orgContactsCmd.AddCommand(orgContactsSearchCmd)
+
+	rootCmd.AddCommand(orgContactsCmd)
+}
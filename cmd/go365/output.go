@@ -0,0 +1,512 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// outputFormat returns the structured output format selected with --output
+// (or --json, "template" for --format, "jq" for --jq, "quiet" for --quiet,
+// "field" for --field), or "" for the command's human-readable output.
+func outputFormat(cmd *cobra.Command) (string, error) {
+	format, _ := cmd.Flags().GetString("output")
+	tmpl, _ := cmd.Flags().GetString("format")
+	if field, _ := cmd.Flags().GetString("field"); field != "" {
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		query, _ := cmd.Flags().GetString("jq")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if format != "" || tmpl != "" || query != "" || jsonOutput || quiet {
+			return "", fmt.Errorf("--field cannot be combined with other output flags")
+		}
+		return "field", nil
+	}
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		query, _ := cmd.Flags().GetString("jq")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if format != "" || tmpl != "" || query != "" || jsonOutput {
+			return "", fmt.Errorf("--quiet cannot be combined with other output flags")
+		}
+		return "quiet", nil
+	}
+	if query, _ := cmd.Flags().GetString("jq"); query != "" {
+		if tmpl != "" {
+			return "", fmt.Errorf("--jq cannot be combined with --format")
+		}
+		if format != "" && format != "json" {
+			return "", fmt.Errorf("--jq cannot be combined with --output %s", format)
+		}
+		return "jq", nil
+	}
+	if tmpl != "" {
+		if format != "" && format != "template" {
+			return "", fmt.Errorf("--format cannot be combined with --output %s", format)
+		}
+		return "template", nil
+	}
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput && format == "" {
+		format = "json"
+	}
+	if format == "text" {
+		// Explicitly human-readable, overriding any configured default
+		return "", nil
+	}
+	if format == "" && configMgr != nil {
+		// Fall back to the configured default, which a project .go365.yaml can set
+		if config, err := configMgr.Load(); err == nil {
+			format = config.Output
+		}
+	}
+
+	if format != "" && !slices.Contains(outputFormats, format) {
+		return "", fmt.Errorf("unsupported output format %q (use json, yaml, table, or text)", format)
+	}
+	return format, nil
+}
+
+// terminalBody returns a message or event body for human-readable output.
+// Markdown bodies (from --markdown) are styled and wrapped to the terminal
+// when stdout is one; anything else is returned as is.
+func terminalBody(contentType, content string) string {
+	tty := terminalStdout()
+	if !strings.EqualFold(contentType, "Markdown") || tty == nil {
+		return content
+	}
+	return output.RenderMarkdown(content, output.TerminalWidth(tty))
+}
+
+// renderList writes a list in the structured format selected on the command
+// line. It returns false when none was selected and the caller should print
+// its human-readable output instead.
+func renderList(cmd *cobra.Command, items any, count int, nextPageToken string, spec *output.TableSpec) (bool, error) {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return true, err
+	}
+
+	switch format {
+	case "json":
+		listResp := output.FormatListResponse(items, count, nextPageToken)
+		return true, output.WriteJSON(os.Stdout, listResp)
+	case "yaml":
+		listResp := output.FormatListResponse(items, count, nextPageToken)
+		return true, output.WriteYAML(os.Stdout, listResp)
+	case "jq":
+		query, _ := cmd.Flags().GetString("jq")
+		listResp := output.FormatListResponse(items, count, nextPageToken)
+		return true, output.WriteJQ(os.Stdout, query, listResp)
+	case "table":
+		columns, _ := cmd.Flags().GetString("columns")
+		if err := output.WriteTable(os.Stdout, items, spec.SelectColumns(output.ParseColumns(columns))); err != nil {
+			return true, err
+		}
+		output.PrintNextPageHint(os.Stdout, nextPageToken)
+		return true, nil
+	case "template":
+		return true, executeTemplate(cmd, items)
+	case "quiet":
+		return true, output.WriteIDs(os.Stdout, items)
+	case "field":
+		field, _ := cmd.Flags().GetString("field")
+		return true, output.WriteField(os.Stdout, items, field)
+	}
+
+	return false, nil
+}
+
+// listProperties returns the properties a list command should request
+// ($select) for the selected output: human lists fetch the fields they print,
+// tables their columns, --quiet and --field only what they show. JSON, YAML,
+// --jq and --format can use any field, so they (and --full) get everything,
+// unless --select names the ones wanted; those are added to the others.
+func listProperties(cmd *cobra.Command, spec *output.TableSpec, human []string) []string {
+	if full, _ := cmd.Flags().GetBool("full"); full {
+		return nil
+	}
+
+	var props []string
+	format, _ := outputFormat(cmd)
+	switch format {
+	case "":
+		props = human
+	case "table":
+		columns, _ := cmd.Flags().GetString("columns")
+		props = output.Properties(spec.SelectColumns(output.ParseColumns(columns)))
+	case "quiet":
+		props = []string{"id"}
+	case "field":
+		field, _ := cmd.Flags().GetString("field")
+		props = output.Properties([]output.Column{{Path: field}})
+	}
+
+	selected, _ := cmd.Flags().GetStringSlice("select")
+	if len(selected) == 0 {
+		return props
+	}
+	merged := []string{"id"}
+	for _, prop := range append(props, selected...) {
+		if prop = strings.TrimSpace(prop); prop != "" && !slices.Contains(merged, prop) {
+			merged = append(merged, prop)
+		}
+	}
+	return merged
+}
+
+// addSelectFlags adds --full and --select to a list command that requests
+// only the properties it shows
+func addSelectFlags(cmd *cobra.Command, noun string) {
+	cmd.Flags().Bool("full", false, fmt.Sprintf("Fetch every %s property, not just those shown", noun))
+	cmd.Flags().StringSlice("select", nil, fmt.Sprintf("Fetch only these %s properties (comma-separated) besides those shown; JSON output then has just these and id", noun))
+	cmd.MarkFlagsMutuallyExclusive("full", "select")
+}
+
+// renderItem writes a single value (a get result or action response) in the
+// selected structured format. Like renderList, it returns false when the
+// caller should print its human-readable output.
+func renderItem(cmd *cobra.Command, v any, spec *output.TableSpec) (bool, error) {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return true, err
+	}
+
+	switch format {
+	case "json":
+		return true, output.WriteJSON(os.Stdout, v)
+	case "yaml":
+		return true, output.WriteYAML(os.Stdout, v)
+	case "jq":
+		query, _ := cmd.Flags().GetString("jq")
+		return true, output.WriteJQ(os.Stdout, query, v)
+	case "table":
+		columns, _ := cmd.Flags().GetString("columns")
+		return true, output.WriteTable(os.Stdout, v, spec.SelectColumns(output.ParseColumns(columns)))
+	case "template":
+		return true, executeTemplate(cmd, v)
+	case "quiet":
+		return true, output.WriteIDs(os.Stdout, v)
+	case "field":
+		field, _ := cmd.Flags().GetString("field")
+		return true, output.WriteField(os.Stdout, v, field)
+	}
+
+	return false, nil
+}
+
+// actionResult is the outcome for one item of a command that acts on several
+type actionResult struct {
+	ID      string `json:"id,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+var actionResultColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Path: "id"},
+		{Name: "success", Path: "success"},
+		{Name: "error", Path: "error"},
+	},
+	Defaults: []string{"id", "success", "error"},
+}
+
+// actionLog reports per-item outcomes. Human-readable output prints each line
+// as it happens; structured output collects them into one list written by
+// Render, so stdout stays parseable.
+type actionLog struct {
+	cmd     *cobra.Command
+	human   bool
+	results []*actionResult
+}
+
+func newActionLog(cmd *cobra.Command) *actionLog {
+	format, _ := outputFormat(cmd)
+	return &actionLog{cmd: cmd, human: format == ""}
+}
+
+// Done records a success, printing the formatted line for humans
+func (l *actionLog) Done(id, format string, args ...any) {
+	l.results = append(l.results, &actionResult{ID: id, Success: true})
+	if l.human {
+		fmt.Printf(format+"\n", args...)
+	}
+}
+
+// Failed records a failure, printing the formatted line for humans
+func (l *actionLog) Failed(id string, err error, format string, args ...any) {
+	l.results = append(l.results, &actionResult{ID: id, Error: err.Error()})
+	if l.human {
+		fmt.Printf(format+"\n", args...)
+	}
+}
+
+// Render writes the collected results in the structured format, if any
+func (l *actionLog) Render() error {
+	if l.human {
+		return nil
+	}
+	_, err := renderList(l.cmd, l.results, len(l.results), "", actionResultColumns)
+	return err
+}
+
+// executeTemplate renders v with the --format template, once per item for lists
+func executeTemplate(cmd *cobra.Command, v any) error {
+	text, _ := cmd.Flags().GetString("format")
+	tmpl, err := output.ParseTemplate(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(os.Stdout, v)
+}
+
+// Table columns per resource. Column names not listed here can still be used
+// with --columns as dotted JSON paths (e.g. --columns body.contentType).
+var (
+	messageColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "subject", Path: "subject"},
+			{Name: "from", Path: "from.emailAddress.address"},
+			{Name: "to", Path: "toRecipients.emailAddress.address"},
+			{Name: "received", Path: "receivedDateTime"},
+			{Name: "read", Path: "isRead"},
+			{Name: "attachments", Path: "hasAttachments"},
+			{Name: "importance", Path: "importance"},
+			{Name: "preview", Path: "bodyPreview"},
+		},
+		Defaults: []string{"received", "from", "subject"},
+	}
+
+	eventColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "subject", Path: "subject"},
+			{Name: "start", Path: "start.dateTime"},
+			{Name: "end", Path: "end.dateTime"},
+			{Name: "location", Path: "location.displayName"},
+			{Name: "organizer", Path: "organizer.emailAddress.address"},
+			{Name: "response", Path: "responseStatus.response"},
+			{Name: "allday", Path: "isAllDay"},
+			{Name: "online", Path: "isOnlineMeeting"},
+			{Name: "type", Path: "type"},
+			{Name: "repeats", Path: "recurrence.pattern.type"},
+		},
+		Defaults: []string{"start", "end", "subject", "location"},
+	}
+
+	calendarColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "name", Path: "name"},
+			{Name: "owner", Path: "owner.address"},
+		},
+		Defaults: []string{"name", "owner", "id"},
+	}
+
+	driveItemColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "name", Path: "name"},
+			{Name: "size", Path: "size"},
+			{Name: "modified", Path: "lastModifiedDateTime"},
+			{Name: "created", Path: "createdDateTime"},
+			{Name: "type", Path: "file.mimeType"},
+			{Name: "children", Path: "folder.childCount"},
+			{Name: "path", Path: "parentReference.path"},
+			{Name: "url", Path: "webUrl"},
+		},
+		Defaults: []string{"name", "size", "modified"},
+	}
+
+	teamColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "name", Path: "displayName"},
+			{Name: "description", Path: "description"},
+			{Name: "visibility", Path: "visibility"},
+			{Name: "archived", Path: "isArchived"},
+		},
+		Defaults: []string{"name", "visibility", "id"},
+	}
+
+	teamMemberColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "name", Path: "displayName"},
+			{Name: "email", Path: "email"},
+			{Name: "roles", Path: "roles"},
+			{Name: "user", Path: "userId"},
+		},
+		Defaults: []string{"name", "email", "roles"},
+	}
+
+	teamAppColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "name", Path: "teamsAppDefinition.displayName"},
+			{Name: "version", Path: "teamsAppDefinition.version"},
+			{Name: "app", Path: "teamsAppDefinition.teamsAppId"},
+			{Name: "description", Path: "teamsAppDefinition.description"},
+		},
+		Defaults: []string{"name", "version", "app"},
+	}
+
+	shiftColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "user", Path: "userId"},
+			{Name: "name", Path: "sharedShift.displayName"},
+			{Name: "start", Path: "sharedShift.startDateTime"},
+			{Name: "end", Path: "sharedShift.endDateTime"},
+			{Name: "notes", Path: "sharedShift.notes"},
+			{Name: "theme", Path: "sharedShift.theme"},
+		},
+		Defaults: []string{"start", "end", "user", "name"},
+	}
+
+	openShiftColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "name", Path: "sharedOpenShift.displayName"},
+			{Name: "start", Path: "sharedOpenShift.startDateTime"},
+			{Name: "end", Path: "sharedOpenShift.endDateTime"},
+			{Name: "slots", Path: "sharedOpenShift.openSlotCount"},
+			{Name: "notes", Path: "sharedOpenShift.notes"},
+		},
+		Defaults: []string{"start", "end", "slots", "name"},
+	}
+
+	timeOffColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "user", Path: "userId"},
+			{Name: "reason", Path: "sharedTimeOff.timeOffReasonId"},
+			{Name: "start", Path: "sharedTimeOff.startDateTime"},
+			{Name: "end", Path: "sharedTimeOff.endDateTime"},
+		},
+		Defaults: []string{"start", "end", "user", "reason"},
+	}
+
+	roleColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "name", Path: "displayName"},
+			{Name: "description", Path: "description"},
+			{Name: "template", Path: "roleTemplateId"},
+		},
+		Defaults: []string{"name", "id"},
+	}
+
+	directoryObjectColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "name", Path: "displayName"},
+			{Name: "type", Path: "@odata.type"},
+			{Name: "mail", Path: "mail"},
+			{Name: "upn", Path: "userPrincipalName"},
+			{Name: "app", Path: "appId"},
+		},
+		Defaults: []string{"name", "mail", "type"},
+	}
+
+	roleMembershipColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "role", Path: "role.displayName"},
+			{Name: "id", Path: "role.id"},
+			{Name: "members", Path: "members.displayName"},
+		},
+		Defaults: []string{"role", "members"},
+	}
+
+	orgContactColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "name", Path: "displayName"},
+			{Name: "email", Path: "mail"},
+			{Name: "company", Path: "companyName"},
+			{Name: "title", Path: "jobTitle"},
+			{Name: "department", Path: "department"},
+			{Name: "phone", Path: "phones.number"},
+		},
+		Defaults: []string{"name", "email", "company"},
+	}
+
+	labelColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "name", Path: "name"},
+			{Name: "parent", Path: "parent.name"},
+			{Name: "sensitivity", Path: "sensitivity"},
+			{Name: "formats", Path: "contentFormats"},
+			{Name: "protection", Path: "hasProtection"},
+			{Name: "active", Path: "isActive"},
+			{Name: "description", Path: "description"},
+		},
+		Defaults: []string{"name", "sensitivity", "formats", "id"},
+	}
+
+	permissionGrantColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "app", Path: "clientDisplayName"},
+			{Name: "resource", Path: "resourceDisplayName"},
+			{Name: "consent", Path: "consentType"},
+			{Name: "principal", Path: "principalId"},
+			{Name: "scopes", Path: "scope"},
+		},
+		Defaults: []string{"app", "resource", "consent", "scopes"},
+	}
+
+	appRoleAssignmentColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "resource", Path: "resourceDisplayName"},
+			{Name: "role", Path: "appRoleId"},
+			{Name: "principal", Path: "principalDisplayName"},
+			{Name: "assigned", Path: "createdDateTime"},
+		},
+		Defaults: []string{"resource", "role", "assigned"},
+	}
+
+	callRecordColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "id", Path: "id"},
+			{Name: "type", Path: "type"},
+			{Name: "modalities", Path: "modalities"},
+			{Name: "start", Path: "startDateTime"},
+			{Name: "end", Path: "endDateTime"},
+			{Name: "organizer", Path: "organizer.user.displayName"},
+			{Name: "participants", Path: "participants.user.displayName"},
+		},
+		Defaults: []string{"start", "type", "organizer", "id"},
+	}
+
+	pluginColumns = &output.TableSpec{
+		Columns: []output.Column{
+			{Name: "name", Path: "name"},
+			{Name: "version", Path: "version"},
+			{Name: "source", Path: "source"},
+			{Name: "asset", Path: "asset"},
+			{Name: "sha256", Path: "sha256"},
+			{Name: "installed", Path: "installedAt"},
+		},
+		Defaults: []string{"name", "version", "source"},
+	}
+)
+
+// outputFormats are the values accepted by --output and `config set --output`
+var outputFormats = []string{"json", "yaml", "table"}
+
+func init() {
+	rootCmd.PersistentFlags().Bool("json", false, "Output as JSON (same as --output json)")
+	rootCmd.PersistentFlags().Bool("markdown", false, "Convert HTML bodies to Markdown")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output (also disabled by NO_COLOR)")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Print only IDs, one per line, with no decoration")
+	rootCmd.PersistentFlags().String("output", "", "Output format: json, yaml, table, or text (default: the configured output, else text)")
+	rootCmd.PersistentFlags().String("columns", "", "Comma-separated columns for table output (names or dotted JSON paths)")
+	rootCmd.PersistentFlags().String("jq", "", "Filter the JSON output with a jq expression, e.g. '.value[].subject'")
+	rootCmd.PersistentFlags().String("field", "", "Print only this field (a name or dotted JSON path, e.g. onlineMeeting.joinUrl), one line per item")
+	rootCmd.PersistentFlags().String("format", "", "Format each item with a Go template, e.g. '{{.subject}} ({{.from.emailAddress.address}})'")
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultPager is used when neither GO365_PAGER nor PAGER is set
+const defaultPager = "less"
+
+var (
+	// pagedCommands can print more than a screenful. Commands that prompt,
+	// pick or run interactively are deliberately left out.
+	pagedCommands = map[*cobra.Command]bool{}
+
+	// pagerCmd is the running pager, with pagerTTY the terminal it writes to
+	// in place of go365's own stdout
+	pagerCmd *exec.Cmd
+	pagerTTY *os.File
+)
+
+// setupPager starts the pager and points os.Stdout at it when cmd prints
+// human-readable or table output to a terminal. Failing to start it is not an
+// error; output just goes to the terminal directly.
+func setupPager(cmd *cobra.Command, noPager bool) {
+	if noPager || !pagedCommands[cmd] || !isTerminal(os.Stdout) {
+		return
+	}
+	if format, err := outputFormat(cmd); err != nil || (format != "" && format != "table") {
+		return
+	}
+
+	name := os.Getenv("GO365_PAGER")
+	if name == "" {
+		name = os.Getenv("PAGER")
+	}
+	if name == "" {
+		name = defaultPager
+	}
+	args := strings.Fields(name)
+	if len(args) == 0 || args[0] == "cat" {
+		return
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return
+	}
+	pager := exec.Command(args[0], args[1:]...)
+	pager.Stdin = r
+	pager.Stdout = os.Stdout
+	pager.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		// Quit if it fits on one screen, pass colors through, and leave the
+		// output on the screen afterwards, as git does
+		pager.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := pager.Start(); err != nil {
+		r.Close()
+		w.Close()
+		return
+	}
+	r.Close()
+
+	pagerCmd = pager
+	pagerTTY = os.Stdout
+	os.Stdout = w
+}
+
+// closePager flushes output to the pager and waits for the user to quit it
+func closePager() {
+	if pagerCmd == nil {
+		return
+	}
+	os.Stdout.Close()
+	pagerCmd.Wait()
+	os.Stdout = pagerTTY
+	pagerCmd, pagerTTY = nil, nil
+}
+
+// terminalStdout returns the terminal behind stdout, or nil when output is
+// redirected. While paging that's the pager's terminal, not the pipe to it.
+func terminalStdout() *os.File {
+	if pagerTTY != nil {
+		return pagerTTY
+	}
+	if isTerminal(os.Stdout) {
+		return os.Stdout
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().Bool("no-pager", false, "Don't pipe long output through $PAGER")
+
+	for _, cmd := range []*cobra.Command{
+		mailListCmd, mailGetCmd,
+		calendarListCmd, calendarGetCmd, calendarEventsCmd, calendarPendingCmd, calendarCalendarsCmd,
+		driveLsCmd, driveFindCmd,
+		digestCmd, historyCmd,
+	} {
+		pagedCommands[cmd] = true
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// defaultMaxItems caps --all, so that listing a huge mailbox or folder by
+// mistake doesn't run for an hour
+const defaultMaxItems = 5000
+
+// progressEvery is how many items --all fetches between progress updates
+const progressEvery = 100
+
+// addAllFlags adds --all and --max-items to a list command
+func addAllFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all", false, "Fetch every page instead of one (--top sets the page size)")
+	cmd.Flags().Int("max-items", defaultMaxItems, "Most items --all fetches (0 for no limit)")
+}
+
+// fetchAll calls fn with each item from it, following every page up to
+// --max-items, and says on stderr if it stopped there with more to come.
+// Human-readable output shows the items as they arrive; for structured
+// output, a count on stderr shows that it is still going.
+func fetchAll[T any](cmd *cobra.Command, it *libgo365.PageIterator[T], fn func(*T) error) error {
+	limit, _ := cmd.Flags().GetInt("max-items")
+	if limit < 0 {
+		return fmt.Errorf("--max-items can't be negative")
+	}
+	format, _ := outputFormat(cmd)
+	progress := format != "" && isTerminal(os.Stderr)
+
+	fetched, capped := 0, false
+	err := it.Iterate(cmd.Context(), func(item *T) error {
+		if limit > 0 && fetched == limit {
+			capped = true
+			return libgo365.ErrStopIteration
+		}
+		fetched++
+		if progress && fetched%progressEvery == 0 {
+			fmt.Fprintf(os.Stderr, "\rFetched %d items...", fetched)
+		}
+		return fn(item)
+	})
+	if progress && fetched >= progressEvery {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+	if err != nil {
+		return err
+	}
+	if capped {
+		fmt.Fprintf(os.Stderr, "Stopped after %d items; raise --max-items to fetch more\n", limit)
+	}
+	return nil
+}
+
+// fetchAllItems collects the items fetchAll goes through
+func fetchAllItems[T any](cmd *cobra.Command, it *libgo365.PageIterator[T]) ([]*T, error) {
+	items := []*T{}
+	err := fetchAll(cmd, it, func(item *T) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
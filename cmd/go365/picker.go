@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-isatty"
+	"github.com/njt/go365/libgo365"
+)
+
+// pickerHeight is the number of terminal lines the inline picker uses
+const pickerHeight = 20
+
+// pickItem is a candidate offered by the interactive picker
+type pickItem struct {
+	id       string
+	title    string
+	desc     string
+	selected bool
+}
+
+func (i *pickItem) Title() string {
+	if i.selected {
+		return "[x] " + i.title
+	}
+	return i.title
+}
+
+func (i *pickItem) Description() string { return i.desc }
+func (i *pickItem) FilterValue() string { return i.title + " " + i.desc }
+
+// isInteractive reports whether both stdin and stdout are terminals, so a
+// picker can be shown instead of failing on a missing argument.
+func isInteractive() bool {
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// pickerModel is a filterable list; with multi, space toggles items
+type pickerModel struct {
+	list      list.Model
+	multi     bool
+	chosen    []string
+	cancelled bool
+}
+
+func (m *pickerModel) Init() tea.Cmd { return nil }
+
+func (m *pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetWidth(msg.Width)
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.cancelled = true
+			return m, tea.Quit
+		}
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "esc", "q":
+			if m.list.FilterState() == list.FilterApplied {
+				break
+			}
+			m.cancelled = true
+			return m, tea.Quit
+		case " ":
+			if item, ok := m.list.SelectedItem().(*pickItem); ok && m.multi {
+				item.selected = !item.selected
+				m.list.CursorDown()
+			}
+			return m, nil
+		case "enter":
+			for _, li := range m.list.Items() {
+				if item := li.(*pickItem); item.selected {
+					m.chosen = append(m.chosen, item.id)
+				}
+			}
+			// Nothing toggled: take the highlighted item
+			if len(m.chosen) == 0 {
+				if item, ok := m.list.SelectedItem().(*pickItem); ok {
+					m.chosen = []string{item.id}
+				}
+			}
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m *pickerModel) View() string {
+	if m.chosen != nil || m.cancelled {
+		return ""
+	}
+	return m.list.View()
+}
+
+// pickIDs shows a fuzzy-filterable list of candidates on stderr and returns the
+// IDs of the chosen items. With multi, space toggles items and enter confirms.
+func pickIDs(title string, items []*pickItem, multi bool) ([]string, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	listItems := make([]list.Item, len(items))
+	for i, item := range items {
+		listItems[i] = item
+	}
+
+	l := list.New(listItems, list.NewDefaultDelegate(), 80, pickerHeight)
+	l.Title = title
+	l.KeyMap.Quit.SetEnabled(false)
+	l.SetStatusBarItemName("item", "items")
+	if multi {
+		l.AdditionalShortHelpKeys = func() []key.Binding {
+			return []key.Binding{key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle"))}
+		}
+	}
+
+	model := &pickerModel{list: l, multi: multi}
+	if _, err := tea.NewProgram(model, tea.WithOutput(os.Stderr)).Run(); err != nil {
+		return nil, fmt.Errorf("picker failed: %w", err)
+	}
+	if model.cancelled {
+		return nil, fmt.Errorf("selection cancelled")
+	}
+	return model.chosen, nil
+}
+
+// pickPendingEvents offers pending invitations for selection
+func pickPendingEvents(ctx context.Context, client *libgo365.Client, display *timeDisplay) ([]string, error) {
+	resp, err := client.ListEvents(ctx, &libgo365.ListEventsOptions{
+		Filter: "responseStatus/response eq 'notResponded' or responseStatus/response eq 'none'",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending events: %w", err)
+	}
+
+	items := make([]*pickItem, 0, len(resp.Events))
+	for _, e := range resp.Events {
+		organizer := ""
+		if e.Organizer != nil && e.Organizer.EmailAddress != nil {
+			organizer = "  " + e.Organizer.EmailAddress.Name
+		}
+		items = append(items, &pickItem{id: e.ID, title: e.Subject, desc: eventWhen(e, display) + organizer})
+	}
+	return pickIDs("Pending invitations (space to toggle, enter to confirm)", items, true)
+}
+
+// pickResponse asks for accept, decline or tentative
+func pickResponse() (string, error) {
+	items := []*pickItem{
+		{id: "accept", title: "accept"},
+		{id: "tentative", title: "tentative"},
+		{id: "decline", title: "decline"},
+	}
+	chosen, err := pickIDs("Response", items, false)
+	if err != nil || len(chosen) == 0 {
+		return "", err
+	}
+	return chosen[0], nil
+}
+
+// pickMessages offers recent messages from a folder for selection
+func pickMessages(ctx context.Context, client *libgo365.Client, folderID string, display *timeDisplay) ([]string, error) {
+	resp, err := client.ListMessagesWithPagination(ctx, &libgo365.ListMessagesOptions{
+		FolderID: folderID,
+		Top:      tuiInboxSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	items := make([]*pickItem, 0, len(resp.Messages))
+	for _, msg := range resp.Messages {
+		item := messageItem{msg, display}
+		items = append(items, &pickItem{id: msg.ID, title: item.Title(), desc: item.Description()})
+	}
+	return pickIDs("Messages (space to toggle, enter to confirm)", items, true)
+}
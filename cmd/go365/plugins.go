@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/internal/plugin"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// pluginContext builds the protocol v2 context for a plugin from the current
+// config and session. A plugin still runs when the user isn't logged in; it
+// just gets no access token.
+func pluginContext(ctx context.Context, config *libgo365.Config) *libgo365.PluginContext {
+	pc := &libgo365.PluginContext{
+		Protocol: libgo365.PluginProtocolVersion,
+		GraphURL: libgo365.GraphBaseURL(cmp.Or(config.APIVersion, libgo365.APIVersionV1)),
+		Profile:  "default",
+		Output:   config.Output,
+		NoColor:  !output.ShouldColor(os.Stdout, false),
+	}
+	if path := configMgr.ProjectConfigPath(); path != "" {
+		pc.Profile = path
+	}
+
+	auth, err := newAuthenticator(config)
+	if err != nil || !auth.IsAuthenticated(ctx) {
+		return pc
+	}
+	if accessToken, err := auth.GetAccessToken(ctx); err == nil {
+		pc.AccessToken = accessToken
+	}
+	return pc
+}
+
+// pluginExecOptions passes the plugin context as environment variables, or as
+// a JSON line on stdin when plugin_handshake is "stdin"
+func pluginExecOptions() *plugin.ExecOptions {
+	config, err := configMgr.Load()
+	if err != nil {
+		// Run the plugin the old way rather than not at all
+		return nil
+	}
+
+	pc := pluginContext(context.Background(), config)
+	if config.PluginHandshake != libgo365.HandshakeStdin {
+		return &plugin.ExecOptions{Env: pc.Environ(true)}
+	}
+
+	var handshake bytes.Buffer
+	if err := pc.WriteHandshake(&handshake); err != nil {
+		return nil
+	}
+	env := append(pc.Environ(false), libgo365.EnvPluginHandshake+"="+libgo365.HandshakeStdin)
+	return &plugin.ExecOptions{Env: env, Handshake: handshake.Bytes()}
+}
+
+// pluginDescribeFile caches plugin --go365-describe output
+const pluginDescribeFile = "plugin-describe.json"
+
+// needsPluginCommands reports whether args are a help or completion request,
+// the only times plugin commands are registered with cobra. Running a plugin
+// is handled in main before cobra sees the arguments.
+func needsPluginCommands(args []string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	switch args[0] {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd, "-h", "--help":
+		return true
+	}
+	return false
+}
+
+// registerPluginCommands adds a command to rootCmd for each plugin so it shows
+// in help and completes like a built-in. Plugins that answer --go365-describe
+// contribute their subcommands and flags.
+func registerPluginCommands() {
+	names, err := plugin.ListPlugins()
+	if err != nil {
+		return
+	}
+	slices.Sort(names)
+
+	cache := plugin.LoadDescribeCache(filepath.Join(configMgr.Dir(), pluginDescribeFile))
+	defer cache.Save()
+
+	ctx := context.Background()
+	for _, name := range names {
+		if cmd, _, err := rootCmd.Find([]string{name}); err == nil && cmd != rootCmd {
+			// Built-in commands take precedence
+			continue
+		}
+		path, err := plugin.FindPlugin(name)
+		if err != nil {
+			continue
+		}
+		rootCmd.AddCommand(pluginCommand(name, path, cache.Describe(ctx, path)))
+	}
+}
+
+// pluginCommand builds the cobra command standing in for a plugin
+func pluginCommand(name, path string, desc *plugin.Description) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: "[plugin] go365-" + name,
+		Long:  fmt.Sprintf("Provided by the plugin %s.", path),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return plugin.ExecutePluginWithOptions(name, os.Args[2:], pluginExecOptions())
+		},
+		DisableFlagParsing: desc == nil,
+	}
+	if desc == nil {
+		return cmd
+	}
+
+	if desc.Short != "" {
+		cmd.Short = "[plugin] " + desc.Short
+	}
+	if desc.Version != "" {
+		cmd.Long += "\nVersion: " + desc.Version
+	}
+	addPluginFlags(cmd, desc.Flags)
+
+	for _, sub := range desc.Commands {
+		subCmd := &cobra.Command{
+			Use:   sub.Name,
+			Short: sub.Short,
+			RunE:  cmd.RunE,
+		}
+		addPluginFlags(subCmd, sub.Flags)
+		if desc.Completion {
+			subCmd.ValidArgsFunction = forwardCompletion(name, sub.Name)
+		}
+		cmd.AddCommand(subCmd)
+	}
+	if desc.Completion {
+		cmd.ValidArgsFunction = forwardCompletion(name, "")
+	}
+	return cmd
+}
+
+// addPluginFlags declares a plugin's flags so cobra can show and complete them
+func addPluginFlags(cmd *cobra.Command, flags []plugin.Flag) {
+	for _, f := range flags {
+		if cmd.Flags().Lookup(f.Name) != nil || (f.Shorthand != "" && cmd.Flags().ShorthandLookup(f.Shorthand) != nil) {
+			continue
+		}
+		if f.Type == "bool" {
+			cmd.Flags().BoolP(f.Name, f.Shorthand, false, f.Usage)
+		} else {
+			cmd.Flags().StringP(f.Name, f.Shorthand, "", f.Usage)
+		}
+	}
+}
+
+// forwardCompletion asks a plugin that speaks cobra's completion protocol to
+// complete its own arguments
+func forwardCompletion(name, sub string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		path, err := plugin.FindPlugin(name)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+
+		compArgs := []string{cobra.ShellCompRequestCmd}
+		if sub != "" {
+			compArgs = append(compArgs, sub)
+		}
+		compArgs = append(compArgs, args...)
+		compArgs = append(compArgs, toComplete)
+
+		opts := pluginExecOptions()
+		var stdout bytes.Buffer
+		c := exec.CommandContext(ctx, path, compArgs...)
+		c.Stdout = &stdout
+		if opts != nil {
+			c.Env = append(os.Environ(), opts.Env...)
+			if opts.Handshake != nil {
+				c.Stdin = bytes.NewReader(opts.Handshake)
+			}
+		}
+		if err := c.Run(); err != nil {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		return parseCompletion(stdout.String())
+	}
+}
+
+// parseCompletion reads cobra __complete output: one completion per line and
+// a final ":<directive>" line
+func parseCompletion(out string) ([]string, cobra.ShellCompDirective) {
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	directive := cobra.ShellCompDirectiveDefault
+	if n := len(lines); n > 0 && strings.HasPrefix(lines[n-1], ":") {
+		if d, err := strconv.Atoi(lines[n-1][1:]); err == nil {
+			directive = cobra.ShellCompDirective(d)
+		}
+		lines = lines[:n-1]
+	}
+
+	var completions []string
+	for _, line := range lines {
+		if line != "" {
+			completions = append(completions, line)
+		}
+	}
+	return completions, directive
+}
+
+var pluginsInstallCmd = &cobra.Command{
+	Use:   "install <owner/repo|url>",
+	Short: "Install a plugin from a release",
+	Long: `Download a plugin binary into ~/.go365/plugins and record it in the plugin manifest.
+
+The source is a GitHub repository (owner/repo or its URL), whose latest release
+(or --version) must publish an asset for this platform and its SHA-256 in a
+checksums file or .sha256 sidecar, or a direct download URL with a .sha256
+sidecar or --sha256.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		version, _ := cmd.Flags().GetString("version")
+		sha, _ := cmd.Flags().GetString("sha256")
+
+		installed, err := plugin.NewInstaller(plugin.InstallDir).Install(context.Background(), args[0], plugin.InstallOptions{
+			Name:    name,
+			Version: version,
+			SHA256:  sha,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to install plugin: %w", err)
+		}
+
+		if handled, err := renderItem(cmd, installed, pluginColumns); handled {
+			return err
+		}
+
+		if installed.Version != "" {
+			fmt.Printf("Installed %s %s\n", installed.Name, installed.Version)
+		} else {
+			fmt.Printf("Installed %s\n", installed.Name)
+		}
+		fmt.Printf("Run it with: go365 %s\n", installed.Name)
+		return nil
+	},
+}
+
+var pluginsUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [name...]",
+	Short: "Upgrade installed plugins",
+	Long:  `Reinstall plugins from their recorded source when a newer release is available. With no names, every installed plugin is checked.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		installer := plugin.NewInstaller(plugin.InstallDir)
+
+		names := args
+		if len(names) == 0 {
+			installed, err := installer.Installed()
+			if err != nil {
+				return err
+			}
+			for _, p := range installed {
+				names = append(names, p.Name)
+			}
+		}
+		if len(names) == 0 {
+			fmt.Println("No installed plugins")
+			return nil
+		}
+
+		ctx := context.Background()
+		failed := 0
+		for _, name := range names {
+			updated, changed, err := installer.Upgrade(ctx, name)
+			if err != nil {
+				fmt.Printf("Failed to upgrade %s: %v\n", name, err)
+				failed++
+				continue
+			}
+			if !changed {
+				fmt.Printf("%s is up to date %s\n", name, output.Dim(updated.Version))
+				continue
+			}
+			fmt.Printf("Upgraded %s %s\n", name, updated.Version)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to upgrade %d of %d plugins", failed, len(names))
+		}
+		return nil
+	},
+}
+
+var pluginsRemoveCmd = &cobra.Command{
+	Use:     "remove <name...>",
+	Aliases: []string{"uninstall"},
+	Short:   "Remove installed plugins",
+	Long:    `Delete plugins installed with go365 plugins install. Plugins found elsewhere in PATH are not touched.`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := confirm(cmd, fmt.Sprintf("Remove %d plugin(s)", len(args)), args); err != nil {
+			return err
+		}
+
+		installer := plugin.NewInstaller(plugin.InstallDir)
+		failed := 0
+		for _, name := range args {
+			if err := installer.Remove(name); err != nil {
+				fmt.Printf("Failed to remove %s: %v\n", name, err)
+				failed++
+				continue
+			}
+			fmt.Printf("Removed %s\n", name)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to remove %d of %d plugins", failed, len(args))
+		}
+		return nil
+	},
+}
+
+func init() {
+	pluginsInstallCmd.Flags().String("name", "", "Plugin name (default: derived from the source)")
+	pluginsInstallCmd.Flags().String("version", "", "Release tag to install (default: latest)")
+	pluginsInstallCmd.Flags().String("sha256", "", "Expected SHA-256 of the downloaded asset")
+
+	pluginsCmd.AddCommand(pluginsInstallCmd)
+	pluginsCmd.AddCommand(pluginsUpgradeCmd)
+	pluginsCmd.AddCommand(pluginsRemoveCmd)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// lastListingFile stores the IDs of the most recent listing of each kind, in
+// the order they were shown, so %N can stand in for the Nth one
+const lastListingFile = "last-listing.json"
+
+// isRef reports whether arg is an ordinal reference such as %3
+func isRef(arg string) bool {
+	return strings.HasPrefix(arg, "%")
+}
+
+// rememberListing replaces the last listing of a kind. Like rememberRecent, it
+// never makes a command fail.
+func rememberListing(kind string, ids []string) {
+	path := filepath.Join(configMgr.Dir(), lastListingFile)
+	listings := map[string][]string{}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &listings)
+	}
+	listings[kind] = ids
+
+	data, err := json.Marshal(listings)
+	if err != nil {
+		return
+	}
+	os.WriteFile(path, data, 0600)
+}
+
+// resolveRefs replaces %N references in ids with the Nth ID from the last
+// listing of a kind (messages, events). Other values are passed through.
+func resolveRefs(kind string, ids []string) ([]string, error) {
+	var listing []string
+	loaded := false
+
+	resolved := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !isRef(id) {
+			resolved = append(resolved, id)
+			continue
+		}
+
+		if !loaded {
+			listings := map[string][]string{}
+			if data, err := os.ReadFile(filepath.Join(configMgr.Dir(), lastListingFile)); err == nil {
+				json.Unmarshal(data, &listings)
+			}
+			listing = listings[kind]
+			loaded = true
+		}
+
+		n, err := strconv.Atoi(id[1:])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid reference %q (use %%1 for the first item listed)", id)
+		}
+		if len(listing) == 0 {
+			return nil, fmt.Errorf("no %s have been listed yet for %s to refer to", kind, id)
+		}
+		if n > len(listing) {
+			return nil, fmt.Errorf("%s is out of range: the last %s listing had %d item(s)", id, kind, len(listing))
+		}
+		resolved = append(resolved, listing[n-1])
+	}
+	return resolved, nil
+}
+
+// resolveRef resolves a single ID that may be a %N reference
+func resolveRef(kind, id string) (string, error) {
+	resolved, err := resolveRefs(kind, []string{id})
+	if err != nil {
+		return "", err
+	}
+	return resolved[0], nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var mailReplyCmd = &cobra.Command{
+	Use:   "reply <message-id>",
+	Short: "Reply to an email message",
+	Long: `Reply to the sender of a message, or to everyone on it with --all.
+
+The reply is drafted by Exchange, so it has the "RE:" subject and the
+headers that keep it in the same conversation. Your --body goes above the
+quoted original. In a text reply the original is quoted with "> ", converted
+from HTML to Markdown first if need be; in an HTML reply it is quoted the
+way Outlook quotes it.
+
+If the reply can't be sent, the draft is left in Drafts.`,
+	Example: `  go365 mail reply %1 --body "Thanks, see you then."
+  go365 mail reply AAMkAG... --all --body "<p>Agreed.</p>" --body-type HTML`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		body, _ := cmd.Flags().GetString("body")
+		bodyType, _ := cmd.Flags().GetString("body-type")
+		all, _ := cmd.Flags().GetBool("all")
+
+		if body == "" {
+			return fmt.Errorf("body is required")
+		}
+		contentType, err := libgo365.ParseBodyType(bodyType)
+		if err != nil {
+			return fmt.Errorf("invalid --body-type: %w", err)
+		}
+
+		messageID, err := resolveRef("messages", args[0])
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+
+		draft, err := client.CreateReply(ctx, messageID, all)
+		if err != nil {
+			return fmt.Errorf("failed to create reply: %w", err)
+		}
+
+		content := prependHTML(draft.Body, body)
+		if contentType == libgo365.BodyText {
+			original, err := client.GetMessage(ctx, messageID)
+			if err != nil {
+				return fmt.Errorf("failed to get message: %w", err)
+			}
+			content = body + "\n\n" + quoteMessage(original, displaySettings(ctx, client, config))
+		}
+
+		changes := &libgo365.Message{Body: &libgo365.ItemBody{ContentType: string(contentType), Content: content}}
+		if _, err := client.UpdateMessage(ctx, draft.ID, changes); err != nil {
+			return fmt.Errorf("failed to write reply (draft %s is in Drafts): %w", draft.ID, err)
+		}
+		if err := client.SendDraft(ctx, draft.ID); err != nil {
+			return fmt.Errorf("failed to send reply (draft %s is in Drafts): %w", draft.ID, err)
+		}
+
+		result := "Reply sent to " + recipientList(draft.ToRecipients, draft.CcRecipients)
+		if handled, err := renderItem(cmd, output.FormatActionResponse(true, result), nil); handled {
+			return err
+		}
+
+		fmt.Println(result)
+		return nil
+	},
+}
+
+// quoteMessage quotes a message for a text reply, with an attribution line
+// and each line of its body prefixed with "> "
+func quoteMessage(msg *libgo365.Message, display *timeDisplay) string {
+	var b strings.Builder
+	sender := "someone"
+	if msg.From != nil && msg.From.EmailAddress != nil {
+		sender = msg.From.EmailAddress.Name
+		if sender == "" {
+			sender = msg.From.EmailAddress.Address
+		}
+	}
+	if msg.ReceivedDateTime != nil {
+		fmt.Fprintf(&b, "On %s, %s wrote:\n", display.Format(*msg.ReceivedDateTime), sender)
+	} else {
+		fmt.Fprintf(&b, "%s wrote:\n", sender)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(bodyText(msg.Body)), "\n") {
+		line = strings.TrimRight(line, " \r")
+		if line == "" {
+			b.WriteString(">\n")
+			continue
+		}
+		b.WriteString("> " + line + "\n")
+	}
+	return b.String()
+}
+
+// prependHTML puts content at the top of an HTML body, inside its <body>
+// element when it has one
+func prependHTML(body *libgo365.ItemBody, content string) string {
+	if body == nil {
+		return content
+	}
+	lower := strings.ToLower(body.Content)
+	if start := strings.Index(lower, "<body"); start >= 0 {
+		if end := strings.IndexByte(lower[start:], '>'); end >= 0 {
+			at := start + end + 1
+			return body.Content[:at] + content + body.Content[at:]
+		}
+	}
+	return content + body.Content
+}
+
+// recipientList names the addresses a message goes to, comma-separated
+func recipientList(lists ...[]*libgo365.Recipient) string {
+	var addresses []string
+	for _, recipients := range lists {
+		for _, r := range recipients {
+			if r.EmailAddress != nil {
+				addresses = append(addresses, r.EmailAddress.Address)
+			}
+		}
+	}
+	return strings.Join(addresses, ", ")
+}
+
+func init() {
+	requireGraph(mailReplyCmd)
+	// The reply is drafted, then sent
+	requireScopes([]string{"Mail.ReadWrite", "Mail.Send"}, mailReplyCmd)
+
+	mailReplyCmd.Flags().String("body", "", "Reply text, placed above the quoted original (required)")
+	mailReplyCmd.Flags().String("body-type", "Text", "Body content type (Text or HTML)")
+	mailReplyCmd.Flags().Bool("all", false, "Reply to the sender and all other recipients")
+
+	mailCmd.AddCommand(mailReplyCmd)
+}
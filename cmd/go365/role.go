@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var roleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "Audit directory roles",
+	Long:  `List Azure AD directory roles and who holds them.`,
+}
+
+var roleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List activated directory roles",
+	Long:  `List the directory roles activated in the tenant (roles are activated when first assigned).`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+
+		roles, err := client.ListDirectoryRoles(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list directory roles: %w", err)
+		}
+
+		if handled, err := renderList(cmd, roles, len(roles), "", roleColumns); handled {
+			return err
+		}
+
+		if len(roles) == 0 {
+			fmt.Println("No directory roles found")
+			return nil
+		}
+
+		for _, role := range roles {
+			fmt.Printf("ID: %s\n", role.ID)
+			fmt.Printf("Name: %s\n", role.DisplayName)
+			fmt.Printf("Template ID: %s\n", role.RoleTemplateID)
+			if role.Description != "" {
+				fmt.Printf("Description: %s\n", role.Description)
+			}
+			fmt.Println("---")
+		}
+
+		return nil
+	},
+}
+
+// roleMembership pairs a role with its members for --all JSON output
+type roleMembership struct {
+	Role    *libgo365.DirectoryRole     `json:"role"`
+	Members []*libgo365.DirectoryObject `json:"members"`
+}
+
+var roleMembersCmd = &cobra.Command{
+	Use:   "members [role]",
+	Short: "List members of a directory role",
+	Long: `List who holds a directory role. The role can be given by display name
+(e.g., "Global Administrator"), role ID, or role template ID. Use --all to list
+the members of every activated role.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		if !all && len(args) == 0 {
+			return fmt.Errorf("role name or ID required (or use --all)")
+		}
+
+		ctx := cmd.Context()
+		client := graphClient
+		var err error
+
+		var roles []*libgo365.DirectoryRole
+		if all {
+			roles, err = client.ListDirectoryRoles(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list directory roles: %w", err)
+			}
+		} else {
+			role, err := client.FindDirectoryRole(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			roles = []*libgo365.DirectoryRole{role}
+		}
+
+		var results []*roleMembership
+		for _, role := range roles {
+			members, err := client.ListDirectoryRoleMembers(ctx, role.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list members of %s: %w", role.DisplayName, err)
+			}
+			results = append(results, &roleMembership{Role: role, Members: members})
+		}
+
+		if !all {
+			if handled, err := renderList(cmd, results[0].Members, len(results[0].Members), "", directoryObjectColumns); handled {
+				return err
+			}
+		} else if handled, err := renderList(cmd, results, len(results), "", roleMembershipColumns); handled {
+			return err
+		}
+
+		for _, r := range results {
+			fmt.Printf("%s (%d member(s)):\n", r.Role.DisplayName, len(r.Members))
+			for _, m := range r.Members {
+				principal := m.UserPrincipalName
+				if principal == "" {
+					principal = m.AppID
+				}
+				fmt.Printf("  - %-30s  %-40s  %s\n", m.DisplayName, principal, m.Kind())
+			}
+			fmt.Println()
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	requireGraph(roleListCmd, roleMembersCmd)
+
+	roleCmd.AddCommand(roleListCmd)
+
+	roleMembersCmd.Flags().Bool("all", false, "List members of every activated role")
+	roleCmd.AddCommand(roleMembersCmd)
+
+	rootCmd.AddCommand(roleCmd)
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var ruleColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Path: "id"},
+		{Name: "name", Path: "displayName"},
+		{Name: "sequence", Path: "sequence"},
+		{Name: "enabled", Path: "isEnabled"},
+		{Name: "error", Path: "hasError"},
+		{Name: "readonly", Path: "isReadOnly"},
+	},
+	Defaults: []string{"sequence", "name", "enabled"},
+}
+
+var mailRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage Inbox rules",
+	Long: `List, create, turn on and off, and delete the rules Exchange applies to
+messages as they arrive in the Inbox. Rules are referred to by ID or by
+name.`,
+}
+
+var mailRulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Inbox rules",
+	Long:  `List Inbox rules in the order they run, with their conditions and actions.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+
+		rules, err := client.ListMessageRules(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list rules: %w", err)
+		}
+		sort.SliceStable(rules, func(i, j int) bool { return rules[i].Sequence < rules[j].Sequence })
+
+		if handled, err := renderList(cmd, rules, len(rules), "", ruleColumns); handled {
+			return err
+		}
+
+		if len(rules) == 0 {
+			fmt.Println("No rules found")
+			return nil
+		}
+
+		// Show folder names rather than IDs where they are top-level folders
+		folderNames := map[string]string{}
+		if folders, err := client.ListMailFolders(ctx); err == nil {
+			for _, f := range folders {
+				folderNames[f.ID] = f.DisplayName
+			}
+		}
+		for _, rule := range rules {
+			status := "enabled"
+			switch {
+			case rule.HasError:
+				status = "error (check it in Outlook)"
+			case !rule.IsEnabled:
+				status = "disabled"
+			}
+			fmt.Printf("ID: %s\n", rule.ID)
+			fmt.Printf("Name: %s\n", rule.DisplayName)
+			fmt.Printf("Sequence: %d\n", rule.Sequence)
+			fmt.Printf("Status: %s\n", status)
+			if when := describeRulePredicates(rule.Conditions); when != "" {
+				fmt.Printf("When: %s\n", when)
+			}
+			if except := describeRulePredicates(rule.Exceptions); except != "" {
+				fmt.Printf("Except: %s\n", except)
+			}
+			if then := describeRuleActions(rule.Actions, folderNames); then != "" {
+				fmt.Printf("Then: %s\n", then)
+			}
+			fmt.Println("---")
+		}
+		return nil
+	},
+}
+
+var mailRulesCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an Inbox rule",
+	Long: `Create an Inbox rule from at least one action and any number of conditions,
+all of which must match. Without conditions the rule applies to every
+message. Text conditions match if any of their comma-separated values
+appear, ignoring case.
+
+The rule goes after the existing ones unless --sequence is given. Folders
+are given as for 'mail move'.`,
+	Example: `  go365 mail rules create --name Newsletters --sender-contains newsletter@,digest@ --move-to Newsletters --stop
+  go365 mail rules create --name "Boss" --from boss@contoso.com --importance high --categories "Red category"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			return fmt.Errorf("--name is required")
+		}
+
+		flags := cmd.Flags()
+		from, _ := flags.GetString("from")
+		conditions := &libgo365.MessageRulePredicates{FromAddresses: parseRecipients(from)}
+		conditions.SenderContains, _ = flags.GetStringSlice("sender-contains")
+		conditions.SubjectContains, _ = flags.GetStringSlice("subject-contains")
+		conditions.BodyContains, _ = flags.GetStringSlice("body-contains")
+		conditions.HasAttachments, _ = flags.GetBool("has-attachments")
+
+		actions := &libgo365.MessageRuleActions{}
+		actions.MarkAsRead, _ = flags.GetBool("mark-read")
+		actions.Delete, _ = flags.GetBool("delete")
+		actions.AssignCategories, _ = flags.GetStringSlice("categories")
+		actions.StopProcessingRules, _ = flags.GetBool("stop")
+		forwardTo, _ := flags.GetString("forward-to")
+		actions.ForwardTo = parseRecipients(forwardTo)
+		if value, _ := flags.GetString("importance"); value != "" {
+			importance, err := libgo365.ParseImportance(value)
+			if err != nil {
+				return fmt.Errorf("invalid --importance: %w", err)
+			}
+			actions.MarkImportance = importance
+		}
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		// Rules take folder IDs, not the well-known names
+		moveTo, _ := flags.GetString("move-to")
+		copyTo, _ := flags.GetString("copy-to")
+		var err error
+		if actions.MoveToFolder, err = ruleFolderID(ctx, client, moveTo); err != nil {
+			return err
+		}
+		if actions.CopyToFolder, err = ruleFolderID(ctx, client, copyTo); err != nil {
+			return err
+		}
+		if actions.MoveToFolder == "" && actions.CopyToFolder == "" && !actions.MarkAsRead && !actions.Delete &&
+			actions.MarkImportance == "" && len(actions.AssignCategories) == 0 && len(actions.ForwardTo) == 0 {
+			return fmt.Errorf("a rule needs an action, such as --move-to, --mark-read or --delete")
+		}
+
+		rule := &libgo365.MessageRule{
+			DisplayName: name,
+			IsEnabled:   true,
+			Actions:     actions,
+		}
+		rule.Sequence, _ = flags.GetInt("sequence")
+		if disabled, _ := flags.GetBool("disabled"); disabled {
+			rule.IsEnabled = false
+		}
+		if describeRulePredicates(conditions) != "" {
+			rule.Conditions = conditions
+		}
+
+		created, err := client.CreateMessageRule(ctx, rule)
+		if err != nil {
+			return fmt.Errorf("failed to create rule: %w", err)
+		}
+
+		if handled, err := renderItem(cmd, created, ruleColumns); handled {
+			return err
+		}
+
+		fmt.Printf("Created rule %q (%s), sequence %d\n", created.DisplayName, created.ID, created.Sequence)
+		return nil
+	},
+}
+
+var mailRulesEnableCmd = &cobra.Command{
+	Use:   "enable <rule...>",
+	Short: "Turn Inbox rules on",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setRulesEnabled(cmd, args, true)
+	},
+}
+
+var mailRulesDisableCmd = &cobra.Command{
+	Use:   "disable <rule...>",
+	Short: "Turn Inbox rules off, keeping them",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setRulesEnabled(cmd, args, false)
+	},
+}
+
+var mailRulesDeleteCmd = &cobra.Command{
+	Use:   "delete <rule...>",
+	Short: "Delete Inbox rules",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+
+		rules, err := resolveRules(ctx, client, splitArgs(args))
+		if err != nil {
+			return err
+		}
+		names := make([]string, len(rules))
+		for i, rule := range rules {
+			names[i] = rule.DisplayName
+		}
+		if err := confirm(cmd, fmt.Sprintf("Delete %d rule(s)", len(rules)), names); err != nil {
+			return err
+		}
+
+		outcomes := newActionLog(cmd)
+		failed := 0
+		for _, rule := range rules {
+			if err := client.DeleteMessageRule(ctx, rule.ID); err != nil {
+				outcomes.Failed(rule.ID, err, "Failed to delete %q: %v", rule.DisplayName, err)
+				failed++
+				continue
+			}
+			outcomes.Done(rule.ID, "Deleted rule %q", rule.DisplayName)
+		}
+		if err := outcomes.Render(); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to delete %d of %d rules", failed, len(rules))
+		}
+		return nil
+	},
+}
+
+// setRulesEnabled turns the rules named in args on or off
+func setRulesEnabled(cmd *cobra.Command, args []string, enabled bool) error {
+	ctx := cmd.Context()
+	client := graphClient
+
+	rules, err := resolveRules(ctx, client, splitArgs(args))
+	if err != nil {
+		return err
+	}
+
+	verb, done := "disable", "Disabled"
+	if enabled {
+		verb, done = "enable", "Enabled"
+	}
+	outcomes := newActionLog(cmd)
+	failed := 0
+	for _, rule := range rules {
+		if _, err := client.SetMessageRuleEnabled(ctx, rule.ID, enabled); err != nil {
+			outcomes.Failed(rule.ID, err, "Failed to %s %q: %v", verb, rule.DisplayName, err)
+			failed++
+			continue
+		}
+		outcomes.Done(rule.ID, "%s rule %q", done, rule.DisplayName)
+	}
+	if err := outcomes.Render(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d of %d rules", verb, failed, len(rules))
+	}
+	return nil
+}
+
+// resolveRules finds the rules given by ID or by name, ignoring case. A
+// name shared by several rules is an error rather than a guess.
+func resolveRules(ctx context.Context, client *libgo365.Client, refs []string) ([]*libgo365.MessageRule, error) {
+	all, err := client.ListMessageRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+
+	rules := make([]*libgo365.MessageRule, 0, len(refs))
+	for _, ref := range refs {
+		var matches []*libgo365.MessageRule
+		for _, rule := range all {
+			if rule.ID == ref {
+				matches = []*libgo365.MessageRule{rule}
+				break
+			}
+			if strings.EqualFold(rule.DisplayName, ref) {
+				matches = append(matches, rule)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("no rule has the ID or name %q; see 'go365 mail rules list'", ref)
+		case 1:
+			rules = append(rules, matches[0])
+		default:
+			return nil, fmt.Errorf("%d rules are called %q; use the rule's ID", len(matches), ref)
+		}
+	}
+	return rules, nil
+}
+
+// ruleFolderID looks up the ID of a folder given as for mail move, or
+// returns "" for no folder
+func ruleFolderID(ctx context.Context, client *libgo365.Client, folder string) (string, error) {
+	if folder == "" {
+		return "", nil
+	}
+	resolved, err := resolveMailFolder(ctx, client, folder)
+	if err != nil {
+		return "", err
+	}
+	f, err := client.GetMailFolder(ctx, resolved)
+	if err != nil {
+		return "", fmt.Errorf("failed to find folder %s: %w", folder, err)
+	}
+	return f.ID, nil
+}
+
+// describeRulePredicates summarizes a rule's conditions or exceptions
+func describeRulePredicates(p *libgo365.MessageRulePredicates) string {
+	if p == nil {
+		return ""
+	}
+	var parts []string
+	list := func(label string, values []string) {
+		if len(values) > 0 {
+			parts = append(parts, fmt.Sprintf("%s %q", label, strings.Join(values, `" or "`)))
+		}
+	}
+	if len(p.FromAddresses) > 0 {
+		parts = append(parts, "from "+recipientList(p.FromAddresses))
+	}
+	if len(p.SentToAddresses) > 0 {
+		parts = append(parts, "sent to "+recipientList(p.SentToAddresses))
+	}
+	list("sender contains", p.SenderContains)
+	list("recipient contains", p.RecipientContains)
+	list("subject contains", p.SubjectContains)
+	list("body contains", p.BodyContains)
+	list("subject or body contains", p.BodyOrSubjectContains)
+	list("header contains", p.HeaderContains)
+	list("category", p.Categories)
+	if p.Importance != "" {
+		parts = append(parts, "importance "+string(p.Importance))
+	}
+	for _, flag := range []struct {
+		set  bool
+		text string
+	}{
+		{p.HasAttachments, "has attachments"},
+		{p.SentToMe, "sent to me"},
+		{p.SentOnlyToMe, "sent only to me"},
+		{p.SentCcMe, "I'm on Cc"},
+		{p.SentToOrCcMe, "sent to me or I'm on Cc"},
+		{p.NotSentToMe, "not sent to me"},
+		{p.IsAutomaticReply, "is an automatic reply"},
+		{p.IsMeetingRequest, "is a meeting request"},
+	} {
+		if flag.set {
+			parts = append(parts, flag.text)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeRuleActions summarizes a rule's actions, naming folders found
+// in folderNames
+func describeRuleActions(a *libgo365.MessageRuleActions, folderNames map[string]string) string {
+	if a == nil {
+		return ""
+	}
+	folder := func(id string) string {
+		if name, ok := folderNames[id]; ok {
+			return name
+		}
+		return id
+	}
+	var parts []string
+	if a.MoveToFolder != "" {
+		parts = append(parts, "move to "+folder(a.MoveToFolder))
+	}
+	if a.CopyToFolder != "" {
+		parts = append(parts, "copy to "+folder(a.CopyToFolder))
+	}
+	if a.Delete {
+		parts = append(parts, "delete")
+	}
+	if a.PermanentDelete {
+		parts = append(parts, "delete permanently")
+	}
+	if a.MarkAsRead {
+		parts = append(parts, "mark read")
+	}
+	if a.MarkImportance != "" {
+		parts = append(parts, "mark importance "+string(a.MarkImportance))
+	}
+	if len(a.AssignCategories) > 0 {
+		parts = append(parts, "categorize as "+strings.Join(a.AssignCategories, ", "))
+	}
+	if len(a.ForwardTo) > 0 {
+		parts = append(parts, "forward to "+recipientList(a.ForwardTo))
+	}
+	if len(a.ForwardAsAttachment) > 0 {
+		parts = append(parts, "forward as attachment to "+recipientList(a.ForwardAsAttachment))
+	}
+	if len(a.RedirectTo) > 0 {
+		parts = append(parts, "redirect to "+recipientList(a.RedirectTo))
+	}
+	if a.StopProcessingRules {
+		parts = append(parts, "stop processing more rules")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	ruleCmds := []*cobra.Command{mailRulesListCmd, mailRulesCreateCmd, mailRulesEnableCmd, mailRulesDisableCmd, mailRulesDeleteCmd}
+	requireGraph(ruleCmds...)
+	requireScopes([]string{"MailboxSettings.Read"}, mailRulesListCmd)
+	requireScopes([]string{"MailboxSettings.ReadWrite"}, mailRulesCreateCmd, mailRulesEnableCmd, mailRulesDisableCmd, mailRulesDeleteCmd)
+
+	flags := mailRulesCreateCmd.Flags()
+	flags.String("name", "", "Rule name (required)")
+	flags.Int("sequence", 0, "Position among the rules, lowest first (default: after the last)")
+	flags.Bool("disabled", false, "Create the rule turned off")
+	// Conditions
+	flags.String("from", "", "Only messages from these addresses, comma-separated")
+	flags.StringSlice("sender-contains", nil, "Only messages whose sender contains one of these")
+	flags.StringSlice("subject-contains", nil, "Only messages whose subject contains one of these")
+	flags.StringSlice("body-contains", nil, "Only messages whose body contains one of these")
+	flags.Bool("has-attachments", false, "Only messages with attachments")
+	// Actions
+	flags.String("move-to", "", "Move matching messages to this folder")
+	flags.String("copy-to", "", "Copy matching messages to this folder")
+	flags.Bool("mark-read", false, "Mark matching messages read")
+	flags.Bool("delete", false, "Move matching messages to Deleted Items")
+	flags.String("importance", "", "Set the importance of matching messages (low, normal, high)")
+	flags.StringSlice("categories", nil, "Assign these categories to matching messages")
+	flags.String("forward-to", "", "Forward matching messages to these addresses, comma-separated")
+	flags.Bool("stop", false, "Don't run later rules on matching messages")
+	mailRulesCreateCmd.RegisterFlagCompletionFunc("move-to", completeMailFolders)
+	mailRulesCreateCmd.RegisterFlagCompletionFunc("copy-to", completeMailFolders)
+
+	mailRulesCmd.AddCommand(ruleCmds...)
+	mailCmd.AddCommand(mailRulesCmd)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// graphAnnotation marks commands that need a signed-in Graph client before
+// their RunE starts
+const graphAnnotation = "go365:graph"
+
+// scopesAnnotation lists the delegated permissions a command needs,
+// space-separated
+const scopesAnnotation = "go365:scopes"
+
+// The shared session, set up by connectGraph. Every command in a run uses the
+// same client, so they share its connections, cache and rate limiter.
+var (
+	graphConfig *libgo365.Config
+	graphAuth   *libgo365.Authenticator
+	graphClient *libgo365.Client
+)
+
+// tenantOverride is the tenant from --tenant or GO365_TENANT, used instead
+// of the configured one for this run and signed in to with its own token
+// cache
+var tenantOverride string
+
+// runOverrides holds the settings given for this run on the command line,
+// applied over every config loaded
+var runOverrides = &libgo365.Config{}
+
+// setupTenant records the tenant override and applies it to every config
+// loaded from now on
+func setupTenant(tenant string) {
+	tenantOverride = cmp.Or(tenant, os.Getenv("GO365_TENANT"))
+	if tenantOverride != "" {
+		runOverrides.TenantID = tenantOverride
+		configMgr.SetOverride(runOverrides)
+	}
+}
+
+// setupAPIVersion applies --api-version or GO365_API_VERSION over the
+// configured Graph API version
+func setupAPIVersion(version string) error {
+	version = cmp.Or(version, os.Getenv("GO365_API_VERSION"))
+	if version == "" {
+		return nil
+	}
+	if !slices.Contains(libgo365.APIVersions, version) {
+		return fmt.Errorf("unsupported --api-version %q (use v1.0 or beta)", version)
+	}
+	runOverrides.APIVersion = version
+	configMgr.SetOverride(runOverrides)
+	return nil
+}
+
+// requireGraph marks commands so the root PersistentPreRunE connects to Graph
+// for them
+func requireGraph(cmds ...*cobra.Command) {
+	for _, cmd := range cmds {
+		if cmd.Annotations == nil {
+			cmd.Annotations = map[string]string{}
+		}
+		cmd.Annotations[graphAnnotation] = "true"
+	}
+}
+
+// needsGraph reports whether cmd was marked with requireGraph
+func needsGraph(cmd *cobra.Command) bool {
+	return cmd.Annotations[graphAnnotation] == "true"
+}
+
+// requireScopes records the Graph permissions cmds need. When the config
+// lists scopes one by one rather than .default, connectGraph adds them to
+// the token request and the user is asked to consent to any that are new.
+func requireScopes(scopes []string, cmds ...*cobra.Command) {
+	for _, cmd := range cmds {
+		if cmd.Annotations == nil {
+			cmd.Annotations = map[string]string{}
+		}
+		cmd.Annotations[scopesAnnotation] = strings.Join(scopes, " ")
+	}
+}
+
+// commandScopes returns the scopes cmd was given with requireScopes
+func commandScopes(cmd *cobra.Command) []string {
+	return strings.Fields(cmd.Annotations[scopesAnnotation])
+}
+
+// newAuthenticator returns an authenticator for the configured app and auth
+// mode
+func newAuthenticator(config *libgo365.Config) (*libgo365.Authenticator, error) {
+	authConfig := libgo365.AuthConfig{
+		TenantID: config.TenantID,
+		ClientID: config.ClientID,
+		Scopes:   config.Scopes,
+	}
+	t, err := httpTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	// Sign-in requests go through the same proxy and CAs as Graph ones
+	opts := []libgo365.AuthOption{libgo365.WithHTTPClient(&http.Client{Transport: t})}
+	switch config.AuthMode {
+	case "", libgo365.AuthModeDeviceCode:
+	case libgo365.AuthModeAppOnly:
+		if config.ClientSecret == "" && config.CertPath == "" {
+			return nil, fmt.Errorf("app-only authentication needs a certificate or client secret; set one with 'go365 config set --cert-path' or '--client-secret'")
+		}
+		authConfig.ClientSecret = config.ClientSecret
+		authConfig.CertificatePath = config.CertPath
+		authConfig.CertificatePassword = config.CertPassword
+	case libgo365.AuthModeAzureIdentity:
+		auth, err := libgo365.NewAzureIdentityAuthenticator(authConfig, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create authenticator: %w", err)
+		}
+		return auth, nil
+	case libgo365.AuthModeManagedIdentity:
+		auth, err := libgo365.NewManagedIdentityAuthenticator(config.ManagedIdentityClientID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create authenticator: %w", err)
+		}
+		return auth, nil
+	default:
+		return nil, fmt.Errorf("unsupported auth mode %q (use device-code, app-only, azidentity or managed-identity)", config.AuthMode)
+	}
+
+	if tenantOverride != "" {
+		store, err := libgo365.NewTenantTokenCache(tenantOverride)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, libgo365.WithTokenStore(store))
+	}
+	// Consent to newly needed scopes can only be asked for interactively
+	if isTerminal(os.Stdin) {
+		opts = append(opts, libgo365.WithIncrementalConsent(os.Stderr))
+	}
+	auth, err := libgo365.NewAuthenticator(authConfig, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authenticator: %w", err)
+	}
+	return auth, nil
+}
+
+// connectGraph loads the config, checks the user is signed in and builds the
+// shared client. Only the first call does any work. Commands that can do
+// something useful offline, such as bulk --dry-run, call it themselves
+// instead of using requireGraph. scopes are added to the configured ones
+// (see requireScopes).
+func connectGraph(ctx context.Context, scopes ...string) error {
+	if graphClient != nil {
+		return nil
+	}
+
+	config, err := configMgr.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	auth, err := newAuthenticator(config)
+	if err != nil {
+		return err
+	}
+	if !auth.IsAuthenticated(ctx) {
+		return errNotAuthenticated
+	}
+	auth.AddScopes(scopes...)
+
+	// Fail early rather than on the first request; the client gets a fresh
+	// token from auth whenever this one is about to expire
+	if _, err := auth.Token(ctx); err != nil {
+		var consentErr *libgo365.ConsentRequiredError
+		if errors.As(err, &consentErr) {
+			return fmt.Errorf("this command needs permissions you haven't consented to; run 'go365 login --scopes %s'", strings.Join(consentErr.Scopes, ","))
+		}
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	client, err := newClient(ctx, config, auth)
+	if err != nil {
+		return err
+	}
+	graphConfig = config
+	graphAuth = auth
+	graphClient = client
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().String("api-version", "", "Graph API version to call for this command: v1.0 or beta (also GO365_API_VERSION; default: the configured one, else v1.0)")
+	rootCmd.RegisterFlagCompletionFunc("api-version", cobra.FixedCompletions(libgo365.APIVersions, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.PersistentFlags().String("tenant", "", "Tenant ID or domain to use instead of the configured one for this command, signed in to separately (also GO365_TENANT)")
+}
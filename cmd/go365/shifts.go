@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/njt/go365/internal/dateparse"
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// scheduleItemTypes lists the values accepted by the schedule --type flag
+var scheduleItemTypes = []string{"shifts", "open-shifts", "time-off"}
+
+var teamsScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Access a team's Shifts schedule",
+	Long:  `Export and bulk-edit shifts, open shifts, and time off in a team's Shifts schedule.`,
+}
+
+var teamsScheduleShowCmd = &cobra.Command{
+	Use:   "show <team-id>",
+	Short: "Show schedule settings",
+	Long:  `Display a team's Shifts schedule settings.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+
+		schedule, err := client.GetTeamSchedule(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get schedule: %w", err)
+		}
+
+		if handled, err := renderItem(cmd, schedule, nil); handled {
+			return err
+		}
+
+		fmt.Printf("ID: %s\n", schedule.ID)
+		fmt.Printf("Enabled: %t\n", schedule.Enabled)
+		fmt.Printf("TimeZone: %s\n", schedule.TimeZone)
+		fmt.Printf("Provision Status: %s\n", schedule.ProvisionStatus)
+		fmt.Printf("Open Shifts: %t\n", schedule.OpenShiftsEnabled)
+		fmt.Printf("Swap Requests: %t\n", schedule.SwapShiftsRequestsEnabled)
+		fmt.Printf("Time Off Requests: %t\n", schedule.TimeOffRequestsEnabled)
+
+		return nil
+	},
+}
+
+var teamsScheduleListCmd = &cobra.Command{
+	Use:   "list <team-id>",
+	Short: "List shifts, open shifts, or time off",
+	Long: `List items from a team's Shifts schedule. Use --type to choose shifts (default),
+open-shifts, or time-off. The --json output can be edited and fed back to 'schedule import'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+
+		itemType, _ := cmd.Flags().GetString("type")
+		startStr, _ := cmd.Flags().GetString("start")
+		endStr, _ := cmd.Flags().GetString("end")
+		top, _ := cmd.Flags().GetInt("top")
+		pageToken, _ := cmd.Flags().GetString("page-token")
+
+		opts := &libgo365.ListScheduleItemsOptions{
+			Top:       top,
+			PageToken: pageToken,
+		}
+
+		now := time.Now()
+		if startStr != "" {
+			startTime, err := dateparse.Parse(startStr, now)
+			if err != nil {
+				return fmt.Errorf("invalid start date: %w", err)
+			}
+			opts.StartTime = &startTime
+		}
+		if endStr != "" {
+			endTime, err := dateparse.Parse(endStr, now)
+			if err != nil {
+				return fmt.Errorf("invalid end date: %w", err)
+			}
+			opts.EndTime = &endTime
+		}
+
+		display := displaySettings(ctx, client, config)
+
+		switch itemType {
+		case "shifts":
+			resp, err := client.ListShifts(ctx, teamID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to list shifts: %w", err)
+			}
+
+			if handled, err := renderList(cmd, resp.Shifts, resp.Count, resp.NextPageToken, shiftColumns); handled {
+				return err
+			}
+
+			if len(resp.Shifts) == 0 {
+				fmt.Println("No shifts found")
+				return nil
+			}
+
+			for _, shift := range resp.Shifts {
+				fmt.Printf("ID: %s\n", shift.ID)
+				fmt.Printf("User: %s\n", shift.UserID)
+				printShiftItem(shift.SharedShift, display)
+				if shift.DraftShift != nil {
+					fmt.Printf("Draft: true\n")
+				}
+				fmt.Println("---")
+			}
+			output.PrintNextPageHint(os.Stdout, resp.NextPageToken)
+
+		case "open-shifts":
+			resp, err := client.ListOpenShifts(ctx, teamID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to list open shifts: %w", err)
+			}
+
+			if handled, err := renderList(cmd, resp.OpenShifts, resp.Count, resp.NextPageToken, openShiftColumns); handled {
+				return err
+			}
+
+			if len(resp.OpenShifts) == 0 {
+				fmt.Println("No open shifts found")
+				return nil
+			}
+
+			for _, openShift := range resp.OpenShifts {
+				fmt.Printf("ID: %s\n", openShift.ID)
+				if openShift.SharedOpenShift != nil {
+					printShiftItem(&openShift.SharedOpenShift.ShiftItem, display)
+					fmt.Printf("Open Slots: %d\n", openShift.SharedOpenShift.OpenSlotCount)
+				}
+				fmt.Println("---")
+			}
+			output.PrintNextPageHint(os.Stdout, resp.NextPageToken)
+
+		case "time-off":
+			resp, err := client.ListTimesOff(ctx, teamID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to list time off: %w", err)
+			}
+
+			if handled, err := renderList(cmd, resp.TimesOff, resp.Count, resp.NextPageToken, timeOffColumns); handled {
+				return err
+			}
+
+			if len(resp.TimesOff) == 0 {
+				fmt.Println("No time off found")
+				return nil
+			}
+
+			for _, timeOff := range resp.TimesOff {
+				fmt.Printf("ID: %s\n", timeOff.ID)
+				fmt.Printf("User: %s\n", timeOff.UserID)
+				if item := timeOff.SharedTimeOff; item != nil {
+					fmt.Printf("Reason: %s\n", item.TimeOffReasonID)
+					fmt.Printf("Start: %s\n", formatScheduleTime(item.StartDateTime, display))
+					fmt.Printf("End: %s\n", formatScheduleTime(item.EndDateTime, display))
+				}
+				fmt.Println("---")
+			}
+			output.PrintNextPageHint(os.Stdout, resp.NextPageToken)
+
+		default:
+			return fmt.Errorf("invalid type: %s (must be one of %v)", itemType, scheduleItemTypes)
+		}
+
+		return nil
+	},
+}
+
+var teamsScheduleImportCmd = &cobra.Command{
+	Use:   "import <team-id>",
+	Short: "Bulk create or update schedule items",
+	Long: `Create or update shifts, open shifts, or time off from a JSON file.
+
+The file may contain a JSON array of items or the output of 'schedule list --json'.
+Items with an "id" replace the existing item; items without one are created.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		itemType, _ := cmd.Flags().GetString("type")
+		file, _ := cmd.Flags().GetString("file")
+
+		if file == "" {
+			return fmt.Errorf("--file is required (use - for stdin)")
+		}
+
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		// Accept either a bare array or a list response ({"value": [...]})
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			var list struct {
+				Value []json.RawMessage `json:"value"`
+			}
+			if err := json.Unmarshal(data, &list); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", file, err)
+			}
+			items = list.Value
+		}
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		if err := confirm(cmd, fmt.Sprintf("Import %d %s item(s) into team %s (items with an id are replaced)", len(items), itemType, teamID), nil); err != nil {
+			return err
+		}
+
+		outcomes := newActionLog(cmd)
+		failed := 0
+		for i, raw := range items {
+			var id string
+			var err error
+
+			switch itemType {
+			case "shifts":
+				var shift libgo365.Shift
+				if err = json.Unmarshal(raw, &shift); err == nil {
+					var saved *libgo365.Shift
+					if saved, err = client.SaveShift(ctx, teamID, &shift); err == nil {
+						id = saved.ID
+					}
+				}
+			case "open-shifts":
+				var openShift libgo365.OpenShift
+				if err = json.Unmarshal(raw, &openShift); err == nil {
+					var saved *libgo365.OpenShift
+					if saved, err = client.SaveOpenShift(ctx, teamID, &openShift); err == nil {
+						id = saved.ID
+					}
+				}
+			case "time-off":
+				var timeOff libgo365.TimeOff
+				if err = json.Unmarshal(raw, &timeOff); err == nil {
+					var saved *libgo365.TimeOff
+					if saved, err = client.SaveTimeOff(ctx, teamID, &timeOff); err == nil {
+						id = saved.ID
+					}
+				}
+			default:
+				return fmt.Errorf("invalid type: %s (must be one of %v)", itemType, scheduleItemTypes)
+			}
+
+			if err != nil {
+				// Results stay in input order, so a failed item is found by position
+				outcomes.Failed("", err, "Item %d: failed: %v", i+1, err)
+				failed++
+				continue
+			}
+			outcomes.Done(id, "Item %d: saved %s", i+1, id)
+		}
+
+		if outcomes.human {
+			fmt.Printf("\nImported %d of %d item(s)\n", len(items)-failed, len(items))
+		} else if err := outcomes.Render(); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d item(s) failed to import", failed)
+		}
+		return nil
+	},
+}
+
+var teamsScheduleDeleteCmd = &cobra.Command{
+	Use:   "delete <team-id> <item-id>...",
+	Short: "Delete schedule items",
+	Long:  `Delete one or more shifts, open shifts, or time off entries by ID.`,
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		ctx := cmd.Context()
+		client := graphClient
+		itemType, _ := cmd.Flags().GetString("type")
+
+		var deleteFn func(context.Context, string, string) error
+		switch itemType {
+		case "shifts":
+			deleteFn = client.DeleteShift
+		case "open-shifts":
+			deleteFn = client.DeleteOpenShift
+		case "time-off":
+			deleteFn = client.DeleteTimeOff
+		default:
+			return fmt.Errorf("invalid type: %s (must be one of %v)", itemType, scheduleItemTypes)
+		}
+
+		if err := confirm(cmd, fmt.Sprintf("Delete %d %s item(s) from team %s", len(args)-1, itemType, teamID), args[1:]); err != nil {
+			return err
+		}
+
+		outcomes := newActionLog(cmd)
+		failed := 0
+		for _, id := range args[1:] {
+			if err := deleteFn(ctx, teamID, id); err != nil {
+				outcomes.Failed(id, err, "Failed to delete %s: %v", id, err)
+				failed++
+				continue
+			}
+			outcomes.Done(id, "Deleted %s", id)
+		}
+		if err := outcomes.Render(); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to delete %d item(s)", failed)
+		}
+		return nil
+	},
+}
+
+// printShiftItem prints the human-readable details of a shift
+func printShiftItem(item *libgo365.ShiftItem, display *timeDisplay) {
+	if item == nil {
+		return
+	}
+	if item.DisplayName != "" {
+		fmt.Printf("Name: %s\n", item.DisplayName)
+	}
+	fmt.Printf("Start: %s\n", formatScheduleTime(item.StartDateTime, display))
+	fmt.Printf("End: %s\n", formatScheduleTime(item.EndDateTime, display))
+	if item.Notes != "" {
+		fmt.Printf("Notes: %s\n", item.Notes)
+	}
+}
+
+// formatScheduleTime formats a UTC schedule timestamp in the display timezone
+func formatScheduleTime(t *time.Time, display *timeDisplay) string {
+	if t == nil {
+		return ""
+	}
+	return display.Format(*t)
+}
+
+func init() {
+	requireGraph(teamsScheduleShowCmd, teamsScheduleListCmd, teamsScheduleImportCmd, teamsScheduleDeleteCmd)
+
+	teamsScheduleCmd.AddCommand(teamsScheduleShowCmd)
+
+	teamsScheduleListCmd.Flags().String("type", "shifts", "Item type (shifts, open-shifts, time-off)")
+	teamsScheduleListCmd.Flags().String("start", "", "Only items starting at or after this date (accepts natural language)")
+	teamsScheduleListCmd.Flags().String("end", "", "Only items ending at or before this date")
+	teamsScheduleListCmd.Flags().Int("top", 0, "Limit number of results")
+	teamsScheduleListCmd.Flags().String("page-token", "", "Pagination token from previous response")
+	teamsScheduleCmd.AddCommand(teamsScheduleListCmd)
+
+	teamsScheduleImportCmd.Flags().String("type", "shifts", "Item type (shifts, open-shifts, time-off)")
+	teamsScheduleImportCmd.Flags().String("file", "", "JSON file to import (use - for stdin)")
+	teamsScheduleCmd.AddCommand(teamsScheduleImportCmd)
+
+	teamsScheduleDeleteCmd.Flags().String("type", "shifts", "Item type (shifts, open-shifts, time-off)")
+	teamsScheduleCmd.AddCommand(teamsScheduleDeleteCmd)
+
+	teamsCmd.AddCommand(teamsScheduleCmd)
+}
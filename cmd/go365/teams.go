@@ -0,0 +1,469 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+var teamsCmd = &cobra.Command{
+	Use:   "teams",
+	Short: "Manage Microsoft Teams",
+	Long:  `Create, archive, and manage membership and apps of Microsoft Teams teams.`,
+}
+
+var teamsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List joined teams",
+	Long:  `List the teams the authenticated user (or another user) is a member of.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+		userID, _ := cmd.Flags().GetString("user")
+
+		var opts *libgo365.ListTeamsOptions
+		if userID != "" {
+			expanded, err := expandEmail(ctx, client, userID)
+			if err != nil {
+				return err
+			}
+			opts = &libgo365.ListTeamsOptions{UserID: expanded}
+		}
+
+		teams, err := client.ListTeams(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list teams: %w", err)
+		}
+
+		if handled, err := renderList(cmd, teams, len(teams), "", teamColumns); handled {
+			return err
+		}
+
+		if len(teams) == 0 {
+			fmt.Println("No teams found")
+			return nil
+		}
+
+		for _, team := range teams {
+			fmt.Printf("ID: %s\n", team.ID)
+			fmt.Printf("Name: %s\n", team.DisplayName)
+			if team.Description != "" {
+				fmt.Printf("Description: %s\n", team.Description)
+			}
+			if team.IsArchived {
+				fmt.Printf("Archived: true\n")
+			}
+			fmt.Println("---")
+		}
+
+		return nil
+	},
+}
+
+var teamsCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a team",
+	Long:  `Create a new team from a template. Team provisioning is asynchronous; by default the command waits until the team is ready.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := graphClient
+
+		name, _ := cmd.Flags().GetString("name")
+		description, _ := cmd.Flags().GetString("description")
+		template, _ := cmd.Flags().GetString("template")
+		visibility, _ := cmd.Flags().GetString("visibility")
+		noWait, _ := cmd.Flags().GetBool("no-wait")
+
+		if name == "" {
+			return fmt.Errorf("--name is required")
+		}
+
+		op, err := client.CreateTeam(ctx, &libgo365.CreateTeamOptions{
+			DisplayName: name,
+			Description: description,
+			Template:    template,
+			Visibility:  visibility,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create team: %w", err)
+		}
+
+		if !noWait && op.Location != "" {
+			if format, _ := outputFormat(cmd); format == "" {
+				fmt.Fprintln(os.Stderr, "Provisioning team (this can take a minute)...")
+			}
+			op, err = client.WaitForTeamsOperation(ctx, op, 0)
+			if err != nil {
+				return fmt.Errorf("team provisioning failed: %w", err)
+			}
+		}
+
+		if handled, err := renderItem(cmd, op, nil); handled {
+			return err
+		}
+
+		if op.IsDone() {
+			fmt.Printf("Created team: %s\n", name)
+		} else {
+			fmt.Printf("Team provisioning started: %s\n", name)
+		}
+		fmt.Printf("ID: %s\n", op.TargetResourceID)
+		fmt.Printf("Status: %s\n", output.Status(op.Status))
+
+		return nil
+	},
+}
+
+var teamsArchiveCmd = &cobra.Command{
+	Use:   "archive <team-id>",
+	Short: "Archive a team",
+	Long:  `Archive a team, making it read-only. Use --unarchive to restore an archived team.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		ctx := cmd.Context()
+		client := graphClient
+		var err error
+
+		unarchive, _ := cmd.Flags().GetBool("unarchive")
+		readOnlySite, _ := cmd.Flags().GetBool("read-only-site")
+		noWait, _ := cmd.Flags().GetBool("no-wait")
+
+		if !unarchive {
+			if err := confirm(cmd, "Archive team (it becomes read-only)", []string{teamID}); err != nil {
+				return err
+			}
+		}
+
+		var op *libgo365.TeamsAsyncOperation
+		verb, action := "archive", "Archived"
+		if unarchive {
+			verb, action = "unarchive", "Unarchived"
+			op, err = client.UnarchiveTeam(ctx, teamID)
+		} else {
+			op, err = client.ArchiveTeam(ctx, teamID, readOnlySite)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s team: %w", verb, err)
+		}
+
+		if !noWait && op.Location != "" {
+			op, err = client.WaitForTeamsOperation(ctx, op, 0)
+			if err != nil {
+				return err
+			}
+		}
+
+		if handled, err := renderItem(cmd, op, nil); handled {
+			return err
+		}
+
+		if op.IsDone() {
+			fmt.Printf("%s team %s\n", action, teamID)
+		} else {
+			fmt.Printf("%s request accepted for team %s (status: %s)\n", action, teamID, output.Status(op.Status))
+		}
+		return nil
+	},
+}
+
+var teamsMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Manage team members",
+	Long:  `List, add, and remove members of a team.`,
+}
+
+var teamsMembersListCmd = &cobra.Command{
+	Use:   "list <team-id>",
+	Short: "List team members",
+	Long:  `List the members and owners of a team.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		members, err := client.ListTeamMembers(ctx, teamID)
+		if err != nil {
+			return fmt.Errorf("failed to list members: %w", err)
+		}
+
+		if handled, err := renderList(cmd, members, len(members), "", teamMemberColumns); handled {
+			return err
+		}
+
+		if len(members) == 0 {
+			fmt.Println("No members found")
+			return nil
+		}
+
+		for _, m := range members {
+			role := "member"
+			if len(m.Roles) > 0 {
+				role = strings.Join(m.Roles, ",")
+			}
+			fmt.Printf("%-30s  %-40s  %s\n", m.DisplayName, m.Email, role)
+		}
+
+		return nil
+	},
+}
+
+var teamsMembersAddCmd = &cobra.Command{
+	Use:   "add <team-id> <users>",
+	Short: "Add members to a team",
+	Long:  `Add one or more users (comma-separated emails or IDs) to a team. Use --owner to add them as owners.`,
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		ctx := cmd.Context()
+		client := graphClient
+		owner, _ := cmd.Flags().GetBool("owner")
+
+		users, err := expandEmails(ctx, client, splitArgs(args[1:]))
+		if err != nil {
+			return err
+		}
+
+		outcomes := newActionLog(cmd)
+		failed := 0
+		for _, user := range users {
+			_, err := client.AddTeamMember(ctx, teamID, &libgo365.AddTeamMemberOptions{
+				UserID: user,
+				Owner:  owner,
+			})
+			if err != nil {
+				outcomes.Failed(user, err, "Failed to add %s: %v", user, err)
+				failed++
+				continue
+			}
+			outcomes.Done(user, "Added %s to team %s", user, teamID)
+		}
+
+		if err := outcomes.Render(); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to add %d of %d member(s)", failed, len(users))
+		}
+		return nil
+	},
+}
+
+var teamsMembersRemoveCmd = &cobra.Command{
+	Use:   "remove <team-id> <users>",
+	Short: "Remove members from a team",
+	Long:  `Remove one or more users (comma-separated emails, user IDs, or membership IDs) from a team.`,
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		users := splitArgs(args[1:])
+		if err := confirm(cmd, fmt.Sprintf("Remove %d member(s) from team %s", len(users), teamID), users); err != nil {
+			return err
+		}
+
+		outcomes := newActionLog(cmd)
+		failed := 0
+		for _, user := range users {
+			member, err := client.FindTeamMember(ctx, teamID, user)
+			if err != nil {
+				outcomes.Failed(user, err, "Failed to remove %s: %v", user, err)
+				failed++
+				continue
+			}
+			if err := client.RemoveTeamMember(ctx, teamID, member.ID); err != nil {
+				outcomes.Failed(user, err, "Failed to remove %s: %v", user, err)
+				failed++
+				continue
+			}
+			outcomes.Done(user, "Removed %s from team %s", user, teamID)
+		}
+
+		if err := outcomes.Render(); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to remove %d of %d member(s)", failed, len(users))
+		}
+		return nil
+	},
+}
+
+var teamsAppsCmd = &cobra.Command{
+	Use:   "apps <team-id>",
+	Short: "List and manage apps installed in a team",
+	Long: `List the apps installed in a team. Use the install and remove subcommands to
+manage installations by app catalog ID.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		ctx := cmd.Context()
+		client := graphClient
+
+		apps, err := client.ListTeamApps(ctx, teamID)
+		if err != nil {
+			return fmt.Errorf("failed to list installed apps: %w", err)
+		}
+
+		if handled, err := renderList(cmd, apps, len(apps), "", teamAppColumns); handled {
+			return err
+		}
+
+		if len(apps) == 0 {
+			fmt.Println("No apps installed")
+			return nil
+		}
+
+		for _, app := range apps {
+			def := app.TeamsAppDefinition
+			if def == nil {
+				def = &libgo365.TeamsAppDefinition{}
+			}
+			fmt.Printf("%-30s  %-10s  %-36s  %s\n", def.DisplayName, def.Version, def.TeamsAppID, app.ID)
+		}
+
+		return nil
+	},
+}
+
+var teamsAppsInstallCmd = &cobra.Command{
+	Use:   "install <team-id> <app-ids>",
+	Short: "Install apps in a team",
+	Long:  `Install one or more apps (comma-separated app catalog IDs) in a team.`,
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		ctx := cmd.Context()
+		client := graphClient
+		appIDs := splitArgs(args[1:])
+
+		outcomes := newActionLog(cmd)
+		failed := 0
+		for _, appID := range appIDs {
+			if err := client.InstallTeamApp(ctx, teamID, appID); err != nil {
+				outcomes.Failed(appID, err, "Failed to install %s: %v", appID, err)
+				failed++
+				continue
+			}
+			outcomes.Done(appID, "Installed %s in team %s", appID, teamID)
+		}
+
+		if err := outcomes.Render(); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to install %d of %d app(s)", failed, len(appIDs))
+		}
+		return nil
+	},
+}
+
+var teamsAppsRemoveCmd = &cobra.Command{
+	Use:   "remove <team-id> <apps>",
+	Short: "Remove apps from a team",
+	Long:  `Uninstall one or more apps (comma-separated app IDs, installation IDs, or names) from a team.`,
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		ctx := cmd.Context()
+		client := graphClient
+		apps := splitArgs(args[1:])
+		if err := confirm(cmd, fmt.Sprintf("Remove %d app(s) from team %s", len(apps), teamID), apps); err != nil {
+			return err
+		}
+
+		outcomes := newActionLog(cmd)
+		failed := 0
+		for _, app := range apps {
+			installation, err := client.FindTeamApp(ctx, teamID, app)
+			if err != nil {
+				outcomes.Failed(app, err, "Failed to remove %s: %v", app, err)
+				failed++
+				continue
+			}
+			if err := client.RemoveTeamApp(ctx, teamID, installation.ID); err != nil {
+				outcomes.Failed(app, err, "Failed to remove %s: %v", app, err)
+				failed++
+				continue
+			}
+			outcomes.Done(app, "Removed %s from team %s", app, teamID)
+		}
+
+		if err := outcomes.Render(); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to remove %d of %d app(s)", failed, len(apps))
+		}
+		return nil
+	},
+}
+
+// splitArgs splits comma-separated arguments into a flat list of trimmed values
+func splitArgs(args []string) []string {
+	var values []string
+	for _, arg := range args {
+		for _, p := range strings.Split(arg, ",") {
+			p = strings.TrimSpace(p)
+			if p != "" {
+				values = append(values, p)
+			}
+		}
+	}
+	return values
+}
+
+func init() {
+	requireGraph(
+		teamsListCmd, teamsCreateCmd, teamsArchiveCmd,
+		teamsMembersListCmd, teamsMembersAddCmd, teamsMembersRemoveCmd,
+		teamsAppsCmd, teamsAppsInstallCmd, teamsAppsRemoveCmd,
+	)
+
+	teamsListCmd.Flags().String("user", "", "List another user's teams (email or ID)")
+	teamsCmd.AddCommand(teamsListCmd)
+
+	teamsCreateCmd.Flags().String("name", "", "Team display name (required)")
+	teamsCreateCmd.Flags().String("description", "", "Team description")
+	teamsCreateCmd.Flags().String("template", "standard", "Team template ID (e.g., standard, educationClass)")
+	teamsCreateCmd.Flags().String("visibility", "", "Team visibility (private or public)")
+	teamsCreateCmd.Flags().Bool("no-wait", false, "Return immediately instead of waiting for provisioning")
+	teamsCmd.AddCommand(teamsCreateCmd)
+
+	teamsArchiveCmd.Flags().Bool("unarchive", false, "Restore an archived team")
+	teamsArchiveCmd.Flags().Bool("read-only-site", false, "Also make the team's SharePoint site read-only for members")
+	teamsArchiveCmd.Flags().Bool("no-wait", false, "Return immediately instead of waiting for the operation")
+	teamsCmd.AddCommand(teamsArchiveCmd)
+
+	teamsMembersCmd.AddCommand(teamsMembersListCmd)
+
+	teamsMembersAddCmd.Flags().Bool("owner", false, "Add as team owner")
+	teamsMembersCmd.AddCommand(teamsMembersAddCmd)
+
+	teamsMembersCmd.AddCommand(teamsMembersRemoveCmd)
+	teamsCmd.AddCommand(teamsMembersCmd)
+
+	teamsAppsCmd.AddCommand(teamsAppsInstallCmd)
+	teamsAppsCmd.AddCommand(teamsAppsRemoveCmd)
+	teamsCmd.AddCommand(teamsAppsCmd)
+
+	rootCmd.AddCommand(teamsCmd)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/njt/go365/libgo365"
+)
+
+// defaultConcurrency is how many requests bulk and multi-calendar commands
+// keep in flight at once
+const defaultConcurrency = 4
+
+var (
+	// concurrency is set from --concurrency
+	concurrency = defaultConcurrency
+
+	// maxRetries is set from --max-retries
+	maxRetries = libgo365.DefaultMaxRetries
+
+	// limiter is shared by every client the command creates, so parallel
+	// workers back off together when Graph throttles one of them
+	limiter *libgo365.RateLimiter
+)
+
+// setupThrottling records --concurrency, --max-rps and --max-retries
+func setupThrottling(workers int, maxRPS float64, retries int) error {
+	if workers < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if maxRPS < 0 {
+		return fmt.Errorf("--max-rps can't be negative")
+	}
+	if retries < 0 {
+		return fmt.Errorf("--max-retries can't be negative")
+	}
+	concurrency = workers
+	maxRetries = retries
+	limiter = libgo365.NewRateLimiter(maxRPS)
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().Int("concurrency", defaultConcurrency, "Requests in flight at once for bulk, mail export-folder and multi-calendar commands")
+	rootCmd.PersistentFlags().Float64("max-rps", 0, "Limit requests per second to Graph (0 for no limit; 429s always slow down)")
+	rootCmd.PersistentFlags().Int("max-retries", libgo365.DefaultMaxRetries, "Retries for each request Graph throttles or fails with 502/503/504 (0 to fail at once)")
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/njt/go365/internal/dateparse"
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// mailboxSettingsTTL is how long the mailbox's zone and clock preferences are
+// reused before asking Graph again
+const mailboxSettingsTTL = 24 * time.Hour
+
+// timeFormats are the accepted --time-format values
+var timeFormats = []string{"12h", "24h"}
+
+// absoluteTimes is set from --absolute to show full timestamps where lists
+// would otherwise say "2h ago"
+var absoluteTimes bool
+
+// timeDisplay formats times for human-readable output in the user's time zone
+// and clock style
+type timeDisplay struct {
+	tz       string // Zone name as configured, to recognize events already in it
+	loc      *time.Location
+	hour12   bool
+	relative bool
+}
+
+// clockLayout is the time-of-day layout for the configured clock
+func (d *timeDisplay) clockLayout() string {
+	if d.hour12 {
+		return "3:04 PM"
+	}
+	return "15:04"
+}
+
+// Format renders t as a full date and time, e.g. "Tue 21 Jan 2026 09:00 NZDT"
+func (d *timeDisplay) Format(t time.Time) string {
+	return t.In(d.loc).Format("Mon 2 Jan 2006 " + d.clockLayout() + " MST")
+}
+
+// When renders t relative to now ("2h ago", "in 35m") for scanning lists,
+// falling back to Format for times more than a week away or with --absolute
+func (d *timeDisplay) When(t time.Time) string {
+	if d.relative {
+		if rel := output.RelativeTime(t, time.Now()); rel != "" {
+			return rel
+		}
+	}
+	return d.Format(t)
+}
+
+// Clock renders just the time of day of t
+func (d *timeDisplay) Clock(t time.Time) string {
+	return t.In(d.loc).Format(d.clockLayout())
+}
+
+// cachedMailboxSettings is the on-disk copy of the display-related mailbox
+// settings, one entry per account
+type cachedMailboxSettings struct {
+	Settings  *libgo365.MailboxSettings `json:"settings"`
+	FetchedAt time.Time                 `json:"fetchedAt"`
+}
+
+func mailboxSettingsPath() string {
+	return filepath.Join(configMgr.Dir(), "mailbox-settings.json")
+}
+
+// mailboxDisplaySettings returns the mailbox's time zone and formats, from a
+// day-old copy when there is one so most commands don't need an extra request.
+// It returns nil when the settings can't be read.
+func mailboxDisplaySettings(ctx context.Context, client *libgo365.Client, config *libgo365.Config) *libgo365.MailboxSettings {
+	account := ""
+	if graphAuth != nil {
+		account = signedInAccount(ctx, graphAuth)
+	}
+	key := accountKey(config, account)
+	cache := map[string]*cachedMailboxSettings{}
+	if data, err := os.ReadFile(mailboxSettingsPath()); err == nil {
+		json.Unmarshal(data, &cache)
+	}
+	if entry, ok := cache[key]; ok && entry.Settings != nil && time.Since(entry.FetchedAt) < mailboxSettingsTTL {
+		return entry.Settings
+	}
+
+	if client == nil {
+		return nil
+	}
+	settings, err := client.GetMailboxSettings(ctx)
+	if err != nil {
+		return nil
+	}
+
+	cache[key] = &cachedMailboxSettings{Settings: settings, FetchedAt: time.Now()}
+	if data, err := json.MarshalIndent(cache, "", "  "); err == nil {
+		os.WriteFile(mailboxSettingsPath(), data, 0600)
+	}
+	return settings
+}
+
+// displaySettings works out how to show times. The zone comes from
+// GO365_TIMEZONE, the config, the mailbox settings, then the system; the
+// clock from the config's time_format, then the mailbox's Outlook setting,
+// then 24-hour. client may be nil to skip asking Graph.
+func displaySettings(ctx context.Context, client *libgo365.Client, config *libgo365.Config) *timeDisplay {
+	tz := os.Getenv("GO365_TIMEZONE")
+	if tz == "" && config != nil {
+		tz = config.TimeZone
+	}
+	format := ""
+	if config != nil {
+		format = config.TimeFormat
+	}
+
+	if (tz == "" || format == "") && config != nil {
+		if settings := mailboxDisplaySettings(ctx, client, config); settings != nil {
+			if tz == "" {
+				tz = settings.TimeZone
+			}
+			if format == "" && settings.TimeFormat != "" {
+				// Outlook formats use .NET patterns: "h:mm tt" vs "HH:mm"
+				format = "24h"
+				if strings.Contains(settings.TimeFormat, "tt") {
+					format = "12h"
+				}
+			}
+		}
+	}
+
+	d := &timeDisplay{tz: tz, loc: time.Local, hour12: format == "12h", relative: !absoluteTimes}
+	if tz != "" {
+		if loc, err := libgo365.LoadLocation(tz); err == nil {
+			d.loc = loc
+		}
+	} else {
+		d.tz = time.Local.String()
+	}
+	return d
+}
+
+// eventDisplay works out how a calendar command shows times, as
+// displaySettings does but with --timezone first. It also returns the zone to
+// ask Graph for event times in, so that JSON output is in the zone shown; ""
+// for the system zone, whose name isn't known, leaves them in UTC.
+func eventDisplay(cmd *cobra.Command, client *libgo365.Client, config *libgo365.Config) (*timeDisplay, string, error) {
+	display := displaySettings(cmd.Context(), client, config)
+	if tz, _ := cmd.Flags().GetString("timezone"); tz != "" {
+		loc, err := libgo365.LoadLocation(tz)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid --timezone %q: %w", tz, err)
+		}
+		display.tz, display.loc = tz, loc
+	}
+	if display.loc == time.Local {
+		return display, "", nil
+	}
+	return display, display.tz, nil
+}
+
+// graphTime parses a Graph date and time in the zone it was given in
+func graphTime(dt *libgo365.DateTimeTimeZone) (time.Time, bool) {
+	if dt == nil || len(dt.DateTime) < 19 {
+		return time.Time{}, false
+	}
+	loc, err := libgo365.LoadLocation(dt.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	// Graph API format: 2025-12-27T16:00:00.0000000
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", dt.DateTime[:19], loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// eventStart renders an event's start for a list: relative unless it's an
+// all-day event, where "in 14h" would only count down to midnight
+func eventStart(e *libgo365.Event, display *timeDisplay) string {
+	if t, ok := graphTime(e.Start); ok && !e.IsAllDay {
+		return display.When(t)
+	}
+	return formatDateTime(e.Start, display)
+}
+
+// setupDateSettings applies the configured date order, week start, weekend
+// and holidays to dateparse, so "03/04", "this week" and "in 3 business days"
+// follow them. Entries that don't parse are logged and ignored rather than
+// blocking every command.
+func setupDateSettings() {
+	config, err := configMgr.Load()
+	if err != nil {
+		return
+	}
+
+	switch config.DateOrder {
+	case "", dateparse.OrderDMY, dateparse.OrderMDY:
+		dateparse.DateOrder = config.DateOrder
+	default:
+		slog.Warn("ignoring configured date order", "date_order", config.DateOrder)
+	}
+
+	if config.WeekStart != "" {
+		days, err := dateparse.ParseWeekdays(config.WeekStart)
+		if err != nil || len(days) != 1 {
+			slog.Warn("ignoring configured week start", "week_start", config.WeekStart)
+		} else {
+			dateparse.WeekStart = days[0]
+		}
+	}
+
+	if len(config.Weekend) > 0 {
+		weekend, err := dateparse.ParseWeekdays(strings.Join(config.Weekend, ","))
+		if err != nil {
+			slog.Warn("ignoring configured weekend", "error", err)
+		} else {
+			dateparse.Business.Weekend = weekend
+		}
+	}
+
+	for _, day := range config.Holidays {
+		holiday, err := time.ParseInLocation("2006-01-02", day, time.Local)
+		if err != nil {
+			slog.Warn("ignoring configured holiday", "holiday", day)
+			continue
+		}
+		dateparse.Business.Holidays = append(dateparse.Business.Holidays, holiday)
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().Bool("absolute", false, "Show full timestamps instead of relative times like \"2h ago\" in lists")
+}
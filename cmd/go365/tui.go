@@ -0,0 +1,671 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/njt/go365/internal/dateparse"
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// tuiInboxSize is how many inbox messages the TUI loads
+	tuiInboxSize = 50
+
+	// tuiAgendaDays is how far ahead the agenda pane looks
+	tuiAgendaDays = 7
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse mail and calendar interactively",
+	Long: `Open a full-screen terminal interface with an inbox pane and an agenda pane.
+
+Keys:
+  tab        switch between inbox and agenda
+  enter      open the selected message or event
+  /          filter the list
+  esc        go back
+  q          quit
+
+Inbox:       a archive, r reply, R reply all
+Agenda:      y accept, n decline, t tentative, o join online meeting
+Both:        ctrl+r refresh`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := configMgr.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		auth, err := newAuthenticator(config)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		if !auth.IsAuthenticated(ctx) {
+			return errNotAuthenticated
+		}
+
+		if _, err := auth.Token(ctx); err != nil {
+			return fmt.Errorf("failed to get access token: %w", err)
+		}
+
+		// The TUI stays open for hours; auth refreshes the token as it expires
+		client, err := newClient(ctx, config, auth)
+		if err != nil {
+			return err
+		}
+		// ctrl+r must show what is there now, not a cached copy
+		client.SetCache(nil)
+
+		// Keep the browser launcher from writing over the screen
+		browser.Stdout = io.Discard
+		browser.Stderr = io.Discard
+
+		program := tea.NewProgram(newTUIModel(ctx, client, displaySettings(ctx, client, config)), tea.WithAltScreen())
+		if _, err := program.Run(); err != nil {
+			return fmt.Errorf("tui failed: %w", err)
+		}
+		return nil
+	},
+}
+
+// tuiPane identifies which list the TUI is showing
+type tuiPane int
+
+const (
+	inboxPane tuiPane = iota
+	agendaPane
+)
+
+// tuiMode is what the active pane is doing
+type tuiMode int
+
+const (
+	modeList tuiMode = iota
+	modeDetail
+	modeReply
+)
+
+var (
+	tuiTabStyle       = lipgloss.NewStyle().Padding(0, 1)
+	tuiActiveTabStyle = tuiTabStyle.Bold(true).Reverse(true)
+	tuiStatusStyle    = lipgloss.NewStyle().Faint(true)
+	tuiErrorStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// messageItem adapts a message to the list component
+type messageItem struct {
+	msg     *libgo365.Message
+	display *timeDisplay
+}
+
+func (i messageItem) Title() string {
+	title := i.msg.Subject
+	if title == "" {
+		title = "(no subject)"
+	}
+	if !i.msg.IsRead {
+		title = "● " + title
+	}
+	return title
+}
+
+func (i messageItem) Description() string {
+	from := ""
+	if i.msg.From != nil && i.msg.From.EmailAddress != nil {
+		from = i.msg.From.EmailAddress.Name
+		if from == "" {
+			from = i.msg.From.EmailAddress.Address
+		}
+	}
+	if i.msg.ReceivedDateTime != nil {
+		return fmt.Sprintf("%s  %s", i.display.When(*i.msg.ReceivedDateTime), from)
+	}
+	return from
+}
+
+func (i messageItem) FilterValue() string { return i.msg.Subject + " " + i.Description() }
+
+// eventItem adapts an event to the list component
+type eventItem struct {
+	event   *libgo365.Event
+	display *timeDisplay
+}
+
+func (i eventItem) Title() string {
+	title := i.event.Subject
+	if title == "" {
+		title = "(no subject)"
+	}
+	return title
+}
+
+func (i eventItem) Description() string {
+	desc := eventWhen(i.event, i.display)
+	if i.event.Location != nil && i.event.Location.DisplayName != "" {
+		desc += "  @ " + i.event.Location.DisplayName
+	}
+	if i.event.ResponseStatus != nil && i.event.ResponseStatus.Response != "" && i.event.ResponseStatus.Response != libgo365.ResponseNone {
+		desc += "  [" + string(i.event.ResponseStatus.Response) + "]"
+	}
+	return desc
+}
+
+func (i eventItem) FilterValue() string { return i.event.Subject + " " + i.Description() }
+
+// eventWhen formats an event's start and end for display
+func eventWhen(e *libgo365.Event, display *timeDisplay) string {
+	start, ok := graphTime(e.Start)
+	if !ok {
+		return ""
+	}
+	if e.IsAllDay {
+		// All-day events run midnight to midnight in their own zone
+		return start.Format("Mon 2 Jan 2006") + " (all day)"
+	}
+	when := display.Format(start)
+	if end, ok := graphTime(e.End); ok {
+		start, end = start.In(display.loc), end.In(display.loc)
+		if start.YearDay() == end.YearDay() && start.Year() == end.Year() {
+			return when + " – " + display.Clock(end)
+		}
+		return when + " – " + display.Format(end)
+	}
+	return when
+}
+
+// Messages passed back from background Graph calls
+type (
+	inboxLoadedMsg  []*libgo365.Message
+	agendaLoadedMsg []*libgo365.Event
+	detailMsg       string
+	tuiStatusMsg    string
+	tuiErrMsg       struct{ err error }
+
+	// itemRemovedMsg drops the list item with the given ID after an archive
+	itemRemovedMsg string
+
+	// respondedMsg reports a meeting response and triggers an agenda reload
+	respondedMsg string
+)
+
+// tuiModel is the bubbletea model for `go365 tui`
+type tuiModel struct {
+	ctx     context.Context
+	client  *libgo365.Client
+	display *timeDisplay
+
+	pane     tuiPane
+	mode     tuiMode
+	replyAll bool
+
+	inbox  list.Model
+	agenda list.Model
+	detail viewport.Model
+	reply  textinput.Model
+
+	status string
+	err    error
+}
+
+func newTUIModel(ctx context.Context, client *libgo365.Client, display *timeDisplay) *tuiModel {
+	newList := func(title string, help ...key.Binding) list.Model {
+		l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+		l.Title = title
+		l.SetShowTitle(false)
+		l.SetStatusBarItemName("item", "items")
+		// q and esc are handled by the model so they can go back instead of quitting
+		l.KeyMap.Quit.SetEnabled(false)
+		l.AdditionalShortHelpKeys = func() []key.Binding { return help }
+		return l
+	}
+
+	reply := textinput.New()
+	reply.Placeholder = "Type your reply and press enter (esc to cancel)"
+	reply.CharLimit = 0
+
+	return &tuiModel{
+		ctx:     ctx,
+		client:  client,
+		display: display,
+		inbox: newList("Inbox",
+			key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "archive")),
+			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reply")),
+		),
+		agenda: newList("Agenda",
+			key.NewBinding(key.WithKeys("y"), key.WithHelp("y/n/t", "respond")),
+			key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "join")),
+		),
+		detail: viewport.New(0, 0),
+		reply:  reply,
+		status: "Loading…",
+	}
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return tea.Batch(m.loadInbox(), m.loadAgenda())
+}
+
+func (m *tuiModel) loadInbox() tea.Cmd {
+	return func() tea.Msg {
+		resp, err := m.client.ListMessagesWithPagination(m.ctx, &libgo365.ListMessagesOptions{
+			FolderID: "inbox",
+			Top:      tuiInboxSize,
+		})
+		if err != nil {
+			return tuiErrMsg{fmt.Errorf("failed to list messages: %w", err)}
+		}
+		return inboxLoadedMsg(resp.Messages)
+	}
+}
+
+func (m *tuiModel) loadAgenda() tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		resp, err := m.client.CalendarView(m.ctx, &libgo365.CalendarViewOptions{
+			StartDateTime: dateparse.FormatISO8601(now),
+			EndDateTime:   dateparse.FormatISO8601(now.AddDate(0, 0, tuiAgendaDays)),
+		})
+		if err != nil {
+			return tuiErrMsg{fmt.Errorf("failed to list events: %w", err)}
+		}
+		return agendaLoadedMsg(resp.Events)
+	}
+}
+
+func (m *tuiModel) openMessage(msg *libgo365.Message) tea.Cmd {
+	return func() tea.Msg {
+		full, err := m.client.GetMessage(m.ctx, msg.ID)
+		if err != nil {
+			return tuiErrMsg{fmt.Errorf("failed to get message: %w", err)}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Subject: %s\n", full.Subject)
+		if full.From != nil && full.From.EmailAddress != nil {
+			fmt.Fprintf(&b, "From:    %s <%s>\n", full.From.EmailAddress.Name, full.From.EmailAddress.Address)
+		}
+		if len(full.ToRecipients) > 0 {
+			var to []string
+			for _, r := range full.ToRecipients {
+				if r.EmailAddress != nil {
+					to = append(to, r.EmailAddress.Address)
+				}
+			}
+			fmt.Fprintf(&b, "To:      %s\n", strings.Join(to, ", "))
+		}
+		if full.ReceivedDateTime != nil {
+			fmt.Fprintf(&b, "Date:    %s\n", m.display.Format(*full.ReceivedDateTime))
+		}
+		b.WriteString("\n")
+		b.WriteString(bodyText(full.Body))
+		return detailMsg(b.String())
+	}
+}
+
+// eventDetail renders an event from the agenda for the detail view
+func eventDetail(e *libgo365.Event, display *timeDisplay) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Subject:   %s\n", e.Subject)
+	fmt.Fprintf(&b, "When:      %s\n", eventWhen(e, display))
+	if e.Location != nil && e.Location.DisplayName != "" {
+		fmt.Fprintf(&b, "Location:  %s\n", e.Location.DisplayName)
+	}
+	if e.Organizer != nil && e.Organizer.EmailAddress != nil {
+		fmt.Fprintf(&b, "Organizer: %s <%s>\n", e.Organizer.EmailAddress.Name, e.Organizer.EmailAddress.Address)
+	}
+	if e.ResponseStatus != nil && e.ResponseStatus.Response != "" {
+		fmt.Fprintf(&b, "Response:  %s\n", e.ResponseStatus.Response)
+	}
+	if e.OnlineMeeting != nil && e.OnlineMeeting.JoinUrl != "" {
+		fmt.Fprintf(&b, "Join:      %s\n", e.OnlineMeeting.JoinUrl)
+	}
+	if len(e.Attendees) > 0 {
+		b.WriteString("Attendees:\n")
+		for _, a := range e.Attendees {
+			if a.EmailAddress == nil {
+				continue
+			}
+			response := ""
+			if a.Status != nil {
+				response = string(a.Status.Response)
+			}
+			fmt.Fprintf(&b, "  - %s <%s> %s\n", a.EmailAddress.Name, a.EmailAddress.Address, response)
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(bodyText(e.Body))
+	return b.String()
+}
+
+// bodyText returns an item body as readable text, converting HTML to Markdown
+func bodyText(body *libgo365.ItemBody) string {
+	if body == nil {
+		return ""
+	}
+	if strings.EqualFold(body.ContentType, "HTML") {
+		return output.HTMLToMarkdown(body.Content)
+	}
+	return body.Content
+}
+
+func (m *tuiModel) archive(msg *libgo365.Message) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := m.client.MoveMessage(m.ctx, msg.ID, "archive"); err != nil {
+			return tuiErrMsg{fmt.Errorf("failed to archive message: %w", err)}
+		}
+		return itemRemovedMsg(msg.ID)
+	}
+}
+
+func (m *tuiModel) sendReply(msg *libgo365.Message, comment string, replyAll bool) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.client.ReplyToMessage(m.ctx, msg.ID, comment, replyAll); err != nil {
+			return tuiErrMsg{fmt.Errorf("failed to send reply: %w", err)}
+		}
+		return tuiStatusMsg(fmt.Sprintf("Replied to %q", msg.Subject))
+	}
+}
+
+func (m *tuiModel) respond(e *libgo365.Event, response string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.client.RespondToEvent(m.ctx, e.ID, response, ""); err != nil {
+			return tuiErrMsg{fmt.Errorf("failed to respond to event: %w", err)}
+		}
+		return respondedMsg(fmt.Sprintf("Sent %s for %q", response, e.Subject))
+	}
+}
+
+func join(e *libgo365.Event) tea.Cmd {
+	return func() tea.Msg {
+		if e.OnlineMeeting == nil || e.OnlineMeeting.JoinUrl == "" {
+			return tuiErrMsg{fmt.Errorf("%q has no online meeting link", e.Subject)}
+		}
+		if err := browser.OpenURL(e.OnlineMeeting.JoinUrl); err != nil {
+			return tuiErrMsg{fmt.Errorf("failed to open meeting link: %w", err)}
+		}
+		return tuiStatusMsg(fmt.Sprintf("Opened meeting link for %q", e.Subject))
+	}
+}
+
+// activeList returns the list for the current pane
+func (m *tuiModel) activeList() *list.Model {
+	if m.pane == agendaPane {
+		return &m.agenda
+	}
+	return &m.inbox
+}
+
+func (m *tuiModel) selectedMessage() *libgo365.Message {
+	if item, ok := m.inbox.SelectedItem().(messageItem); ok {
+		return item.msg
+	}
+	return nil
+}
+
+func (m *tuiModel) selectedEvent() *libgo365.Event {
+	if item, ok := m.agenda.SelectedItem().(eventItem); ok {
+		return item.event
+	}
+	return nil
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// One line for the tabs, one for the status line
+		bodyHeight := msg.Height - 2
+		m.inbox.SetSize(msg.Width, bodyHeight)
+		m.agenda.SetSize(msg.Width, bodyHeight)
+		m.detail.Width = msg.Width
+		m.detail.Height = bodyHeight - 1
+		m.reply.Width = msg.Width - 4
+		return m, nil
+
+	case inboxLoadedMsg:
+		items := make([]list.Item, 0, len(msg))
+		for _, message := range msg {
+			items = append(items, messageItem{message, m.display})
+		}
+		m.status = fmt.Sprintf("%d messages", len(msg))
+		return m, m.inbox.SetItems(items)
+
+	case agendaLoadedMsg:
+		items := make([]list.Item, 0, len(msg))
+		for _, event := range msg {
+			items = append(items, eventItem{event, m.display})
+		}
+		m.status = fmt.Sprintf("%d events in the next %d days", len(msg), tuiAgendaDays)
+		return m, m.agenda.SetItems(items)
+
+	case detailMsg:
+		m.detail.SetContent(string(msg))
+		m.detail.GotoTop()
+		m.mode = modeDetail
+		m.status = ""
+		return m, nil
+
+	case itemRemovedMsg:
+		for i, item := range m.inbox.Items() {
+			if mi, ok := item.(messageItem); ok && mi.msg.ID == string(msg) {
+				m.inbox.RemoveItem(i)
+				break
+			}
+		}
+		m.mode = modeList
+		m.status = "Archived"
+		return m, nil
+
+	case tuiStatusMsg:
+		m.status, m.err = string(msg), nil
+		return m, nil
+
+	case respondedMsg:
+		m.status, m.err = string(msg), nil
+		return m, m.loadAgenda()
+
+	case tuiErrMsg:
+		m.err = msg.err
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case modeReply:
+			return m.updateReply(msg)
+		case modeDetail:
+			return m.updateDetail(msg)
+		default:
+			return m.updateList(msg)
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.mode == modeReply {
+		m.reply, cmd = m.reply.Update(msg)
+		return m, cmd
+	}
+	*m.activeList(), cmd = m.activeList().Update(msg)
+	return m, cmd
+}
+
+func (m *tuiModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// While filtering, every key belongs to the filter input
+	if m.activeList().FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		*m.activeList(), cmd = m.activeList().Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "tab", "shift+tab":
+		if m.pane == inboxPane {
+			m.pane = agendaPane
+		} else {
+			m.pane = inboxPane
+		}
+		m.err = nil
+		return m, nil
+	case "ctrl+r":
+		m.status = "Refreshing…"
+		if m.pane == agendaPane {
+			return m, m.loadAgenda()
+		}
+		return m, m.loadInbox()
+	case "enter":
+		return m, m.open()
+	}
+
+	if cmd, ok := m.action(msg.String()); ok {
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	*m.activeList(), cmd = m.activeList().Update(msg)
+	return m, cmd
+}
+
+func (m *tuiModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "backspace":
+		m.mode = modeList
+		return m, nil
+	}
+
+	if cmd, ok := m.action(msg.String()); ok {
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.detail, cmd = m.detail.Update(msg)
+	return m, cmd
+}
+
+func (m *tuiModel) updateReply(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeDetail
+		m.reply.Blur()
+		return m, nil
+	case "enter":
+		comment := strings.TrimSpace(m.reply.Value())
+		if comment == "" {
+			return m, nil
+		}
+		message := m.selectedMessage()
+		m.mode = modeDetail
+		m.reply.Blur()
+		m.reply.Reset()
+		if message == nil {
+			return m, nil
+		}
+		m.status = "Sending reply…"
+		return m, m.sendReply(message, comment, m.replyAll)
+	}
+
+	var cmd tea.Cmd
+	m.reply, cmd = m.reply.Update(msg)
+	return m, cmd
+}
+
+// open shows the selected message or event in the detail view
+func (m *tuiModel) open() tea.Cmd {
+	if m.pane == agendaPane {
+		if e := m.selectedEvent(); e != nil {
+			return func() tea.Msg { return detailMsg(eventDetail(e, m.display)) }
+		}
+		return nil
+	}
+	if message := m.selectedMessage(); message != nil {
+		m.status = "Loading…"
+		return m.openMessage(message)
+	}
+	return nil
+}
+
+// action runs the pane-specific key bindings shared by the list and detail views
+func (m *tuiModel) action(key string) (tea.Cmd, bool) {
+	if m.pane == inboxPane {
+		message := m.selectedMessage()
+		if message == nil {
+			return nil, false
+		}
+		switch key {
+		case "a":
+			m.status = "Archiving…"
+			return m.archive(message), true
+		case "r", "R":
+			m.replyAll = key == "R"
+			m.mode = modeReply
+			return m.reply.Focus(), true
+		}
+		return nil, false
+	}
+
+	event := m.selectedEvent()
+	if event == nil {
+		return nil, false
+	}
+	switch key {
+	case "y":
+		return m.respond(event, "accept"), true
+	case "n":
+		return m.respond(event, "decline"), true
+	case "t":
+		return m.respond(event, "tentative"), true
+	case "o":
+		return join(event), true
+	}
+	return nil, false
+}
+
+func (m *tuiModel) View() string {
+	inboxTab, agendaTab := tuiTabStyle, tuiActiveTabStyle
+	if m.pane == inboxPane {
+		inboxTab, agendaTab = tuiActiveTabStyle, tuiTabStyle
+	}
+	tabs := inboxTab.Render("Inbox") + agendaTab.Render("Agenda")
+
+	var body string
+	switch m.mode {
+	case modeDetail:
+		body = m.detail.View() + "\n" + tuiStatusStyle.Render("esc back • ↑/↓ scroll")
+	case modeReply:
+		replyTo := "Reply"
+		if m.replyAll {
+			replyTo = "Reply all"
+		}
+		body = m.detail.View() + "\n" + replyTo + ": " + m.reply.View()
+	default:
+		body = m.activeList().View()
+	}
+
+	status := tuiStatusStyle.Render(m.status)
+	if m.err != nil {
+		status = tuiErrorStyle.Render(m.err.Error())
+	}
+
+	return tabs + "\n" + body + "\n" + status
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+// version is set at release time with -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
+// currentVersion returns the release version, falling back to the module
+// version recorded by go install
+func currentVersion() string {
+	if version != "dev" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return version
+}
+
+// isDevBuild reports whether v is from a local build rather than a release
+func isDevBuild(v string) bool {
+	return v == "dev" || strings.HasSuffix(v, "+dirty")
+}
+
+// versionInfo is the output of go365 version
+type versionInfo struct {
+	Version         string `json:"version"`
+	GoVersion       string `json:"goVersion"`
+	Platform        string `json:"platform"`
+	Latest          string `json:"latest,omitempty"`
+	UpdateAvailable bool   `json:"updateAvailable,omitempty"`
+}
+
+var versionColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "version", Path: "version"},
+		{Name: "go", Path: "goVersion"},
+		{Name: "platform", Path: "platform"},
+		{Name: "latest", Path: "latest"},
+		{Name: "update", Path: "updateAvailable"},
+	},
+	Defaults: []string{"version", "platform", "latest"},
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the go365 version",
+	Long:  `Show the go365 version. With --check, also look up the latest release.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		check, _ := cmd.Flags().GetBool("check")
+
+		info := &versionInfo{
+			Version:   currentVersion(),
+			GoVersion: runtime.Version(),
+			Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		}
+		if check {
+			rel, err := selfupdate.New().Latest(context.Background(), "")
+			if err != nil {
+				return fmt.Errorf("failed to check for updates: %w", err)
+			}
+			info.Latest = rel.Version
+			info.UpdateAvailable = !isDevBuild(info.Version) && selfupdate.Newer(rel.Version, info.Version)
+		}
+
+		if handled, err := renderItem(cmd, info, versionColumns); handled {
+			return err
+		}
+
+		fmt.Printf("go365 %s (%s, %s)\n", info.Version, info.Platform, info.GoVersion)
+		switch {
+		case !check:
+		case info.UpdateAvailable:
+			fmt.Printf("Update available: %s (run 'go365 upgrade')\n", info.Latest)
+		case isDevBuild(info.Version):
+			fmt.Printf("Latest release: %s (this is a development build)\n", info.Latest)
+		default:
+			fmt.Println("You are running the latest release")
+		}
+		return nil
+	},
+}
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Replace go365 with the latest release",
+	Long: `Download the latest go365 release for this platform, verify it against the
+release's signed checksums, and replace the running binary.
+
+Installs managed by a package manager (Homebrew, apt/dnf, Nix, Scoop, ...)
+are left alone; upgrade those with the package manager instead.`,
+	Example: `  go365 upgrade
+  go365 upgrade --version v1.4.0`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, _ := cmd.Flags().GetString("version")
+		force, _ := cmd.Flags().GetBool("force")
+
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to find the go365 binary: %w", err)
+		}
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+		if manager := selfupdate.ManagedBy(exe); manager != "" {
+			return fmt.Errorf("%s is managed by %s; upgrade it with that instead", exe, manager)
+		}
+
+		current := currentVersion()
+		if isDevBuild(current) && !force {
+			return fmt.Errorf("this is a development build; pass --force to replace it with a release")
+		}
+
+		ctx := context.Background()
+		updater := selfupdate.New()
+		rel, err := updater.Latest(ctx, target)
+		if err != nil {
+			return err
+		}
+		if target == "" && !isDevBuild(current) && !selfupdate.Newer(rel.Version, current) && !force {
+			fmt.Printf("go365 %s is already the latest release\n", current)
+			return nil
+		}
+
+		if err := confirm(cmd, fmt.Sprintf("Replace go365 %s with %s", current, rel.Version), []string{exe}); err != nil {
+			return err
+		}
+
+		binary, err := updater.Download(ctx, rel)
+		if err != nil {
+			return err
+		}
+		if selfupdate.PublicKey == "" {
+			fmt.Fprintln(os.Stderr, "Warning: this build has no release signing key; only the checksum was verified")
+		}
+		if err := selfupdate.Replace(exe, binary); err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				return fmt.Errorf("%w (try again with permission to write %s)", err, filepath.Dir(exe))
+			}
+			return err
+		}
+
+		fmt.Printf("Upgraded go365 %s to %s\n", current, rel.Version)
+		return nil
+	},
+}
+
+func init() {
+	versionCmd.Flags().Bool("check", false, "Check whether a newer release is available")
+
+	upgradeCmd.Flags().String("version", "", "Install this release tag instead of the latest")
+	upgradeCmd.Flags().Bool("force", false, "Reinstall even if up to date or running a development build")
+
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(upgradeCmd)
+}
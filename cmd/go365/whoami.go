@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/njt/go365/internal/output"
+	"github.com/njt/go365/libgo365"
+	"github.com/spf13/cobra"
+)
+
+// whoamiOutput is the signed-in user's profile plus details from the token
+type whoamiOutput struct {
+	*libgo365.User
+	TenantID string `json:"tenantId,omitempty"`
+	HasPhoto bool   `json:"hasPhoto"`
+}
+
+var whoamiColumns = &output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Path: "id"},
+		{Name: "name", Path: "displayName"},
+		{Name: "upn", Path: "userPrincipalName"},
+		{Name: "mail", Path: "mail"},
+		{Name: "title", Path: "jobTitle"},
+		{Name: "department", Path: "department"},
+		{Name: "tenant", Path: "tenantId"},
+		{Name: "photo", Path: "hasPhoto"},
+	},
+	Defaults: []string{"name", "upn", "title", "tenant"},
+}
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the signed-in user",
+	Long:  `Show the signed-in user's display name, UPN, ID, tenant, job title, and whether they have a profile photo.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		config, client := graphConfig, graphClient
+		if graphAuth.AppOnly() || graphAuth.ManagedIdentity() {
+			return fmt.Errorf("app-only authentication has no signed-in user; run 'go365 status' to see the app")
+		}
+
+		user, err := client.GetMyProfile(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get profile: %w", err)
+		}
+
+		result := &whoamiOutput{User: user}
+
+		// The tenant comes from the cached account; fall back to the configured one
+		if info, err := graphAuth.GetUserInfo(ctx); err == nil {
+			result.TenantID, _ = info["tenantId"].(string)
+		}
+		if result.TenantID == "" {
+			result.TenantID = config.TenantID
+		}
+
+		result.HasPhoto, err = client.HasMyPhoto(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check profile photo: %w", err)
+		}
+
+		if handled, err := renderItem(cmd, result, whoamiColumns); handled {
+			return err
+		}
+
+		fmt.Printf("Name: %s\n", user.DisplayName)
+		fmt.Printf("UPN: %s\n", user.UserPrincipalName)
+		fmt.Printf("ID: %s\n", user.ID)
+		fmt.Printf("Tenant: %s\n", result.TenantID)
+		if user.JobTitle != "" {
+			fmt.Printf("Job Title: %s\n", user.JobTitle)
+		}
+		if user.Department != "" {
+			fmt.Printf("Department: %s\n", user.Department)
+		}
+		if user.Mail != "" && user.Mail != user.UserPrincipalName {
+			fmt.Printf("Mail: %s\n", user.Mail)
+		}
+		photo := "no"
+		if result.HasPhoto {
+			photo = "yes"
+		}
+		fmt.Printf("Photo: %s\n", photo)
+
+		return nil
+	},
+}
+
+func init() {
+	requireGraph(whoamiCmd)
+	requireScopes([]string{"User.Read"}, whoamiCmd)
+
+	rootCmd.AddCommand(whoamiCmd)
+}
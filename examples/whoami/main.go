@@ -12,17 +12,50 @@ import (
 func main() {
 	ctx := context.Background()
 
+	// When run by go365 as a plugin, use the session it hands over
+	client, err := pluginClient(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	// Get current user information
+	userInfo, err := client.GetMe(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting user info: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Pretty print user information
+	prettyJSON, err := json.MarshalIndent(userInfo, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Current User Information:")
+	fmt.Println(string(prettyJSON))
+}
+
+// pluginClient uses the plugin context from go365 if there is one, and
+// otherwise logs in with the shared config and token cache itself
+func pluginClient(ctx context.Context) (*libgo365.Client, error) {
+	if pc, err := libgo365.LoadPluginContext(); err == nil {
+		if pc.AccessToken == "" {
+			return nil, fmt.Errorf("Not authenticated. Please run 'go365 login' first.")
+		}
+		return pc.NewClient(ctx), nil
+	}
+
 	// Load configuration
 	configMgr, err := libgo365.NewConfigManager()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("Error loading config: %w", err)
 	}
 
 	config, err := configMgr.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("Error loading config: %w", err)
 	}
 
 	// Create authenticator
@@ -34,34 +67,15 @@ func main() {
 
 	auth, err := libgo365.NewAuthenticator(authConfig)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating authenticator: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("Error creating authenticator: %w", err)
 	}
 
 	// Get access token
 	accessToken, err := auth.GetAccessToken(ctx)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Not authenticated. Please run 'go365 login' first.\n")
-		os.Exit(1)
+		return nil, fmt.Errorf("Not authenticated. Please run 'go365 login' first.")
 	}
 
 	// Create Microsoft Graph client
-	client := libgo365.NewClient(ctx, accessToken)
-
-	// Get current user information
-	userInfo, err := client.GetMe(ctx)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting user info: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Pretty print user information
-	prettyJSON, err := json.MarshalIndent(userInfo, "", "  ")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
-		os.Exit(1)
-	}
-
-	fmt.Println("Current User Information:")
-	fmt.Println(string(prettyJSON))
+	return libgo365.NewClient(ctx, accessToken), nil
 }
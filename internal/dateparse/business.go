@@ -0,0 +1,107 @@
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BusinessCalendar says which days count as business days
+type BusinessCalendar struct {
+	Weekend  []time.Weekday
+	Holidays []time.Time // Only the date is used
+}
+
+// Business is the calendar used by AddBusinessDays and by Parse for
+// "next business day" and "in 3 business days". The CLI replaces it with the
+// configured weekend and holidays.
+var Business = &BusinessCalendar{
+	Weekend: []time.Weekday{time.Saturday, time.Sunday},
+}
+
+var businessDayRe = regexp.MustCompile(`^(?:(next|last|previous) business day|in (\d+) business days?|(\d+) business days? ago)$`)
+
+// IsBusinessDay reports whether t falls on neither a weekend day nor a holiday
+func (c *BusinessCalendar) IsBusinessDay(t time.Time) bool {
+	if slices.Contains(c.Weekend, t.Weekday()) {
+		return false
+	}
+	for _, holiday := range c.Holidays {
+		if holiday.Year() == t.Year() && holiday.YearDay() == t.YearDay() {
+			return false
+		}
+	}
+	return true
+}
+
+// AddBusinessDays moves t forward (or back, for negative n) by n business
+// days, keeping its time of day. Zero returns t unchanged even on a weekend.
+func (c *BusinessCalendar) AddBusinessDays(t time.Time, n int) time.Time {
+	if len(c.Weekend) >= 7 {
+		// No business days at all; avoid looping forever
+		return t
+	}
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if c.IsBusinessDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// AddBusinessDays moves t by n business days on the Business calendar
+func AddBusinessDays(t time.Time, n int) time.Time {
+	return Business.AddBusinessDays(t, n)
+}
+
+// parseBusinessDay handles "next business day", "last/previous business
+// day", "in N business days" and "N business days ago", returning midnight
+// on that day
+func parseBusinessDay(text string, ref time.Time) (time.Time, bool) {
+	m := businessDayRe.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	today := StartOfDay(ref)
+	switch {
+	case m[1] == "next":
+		return AddBusinessDays(today, 1), true
+	case m[1] != "":
+		return AddBusinessDays(today, -1), true
+	case m[2] != "":
+		n, _ := strconv.Atoi(m[2])
+		return AddBusinessDays(today, n), true
+	default:
+		n, _ := strconv.Atoi(m[3])
+		return AddBusinessDays(today, -n), true
+	}
+}
+
+// ParseWeekdays parses a comma-separated list of weekday names (full or
+// three letters), as used for the configured weekend
+func ParseWeekdays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		day, ok := lookupWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
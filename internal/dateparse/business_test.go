@@ -0,0 +1,86 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddBusinessDays(t *testing.T) {
+	// Friday 17 January 2025, 9:00
+	friday := time.Date(2025, 1, 17, 9, 0, 0, 0, time.UTC)
+	cal := &BusinessCalendar{Weekend: []time.Weekday{time.Saturday, time.Sunday}}
+
+	tests := []struct {
+		n    int
+		want time.Time
+	}{
+		{0, friday},
+		{1, time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)},
+		{3, time.Date(2025, 1, 22, 9, 0, 0, 0, time.UTC)},
+		{-1, time.Date(2025, 1, 16, 9, 0, 0, 0, time.UTC)},
+		{-5, time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := cal.AddBusinessDays(friday, tt.n); !got.Equal(tt.want) {
+			t.Errorf("AddBusinessDays(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+
+	// A Friday/Saturday weekend with Monday the 20th as a holiday
+	cal = &BusinessCalendar{
+		Weekend:  []time.Weekday{time.Friday, time.Saturday},
+		Holidays: []time.Time{time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	if got, want := cal.AddBusinessDays(friday, 2), time.Date(2025, 1, 21, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("AddBusinessDays with holiday = %v, want %v", got, want)
+	}
+
+	// Every day a weekend day must not loop forever
+	cal = &BusinessCalendar{Weekend: []time.Weekday{0, 1, 2, 3, 4, 5, 6}}
+	if got := cal.AddBusinessDays(friday, 1); !got.Equal(friday) {
+		t.Errorf("expected no movement without business days, got %v", got)
+	}
+}
+
+func TestParseBusinessDays(t *testing.T) {
+	// Friday 17 January 2025
+	ref := time.Date(2025, 1, 17, 10, 0, 0, 0, time.UTC)
+	at := func(d, hour int) time.Time {
+		return time.Date(2025, 1, d, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"next business day", at(20, 0)},
+		{"next business day 9am", at(20, 9)},
+		{"in 3 business days", at(22, 0)},
+		{"in 1 business day at noon", at(20, 12)},
+		{"previous business day", at(16, 0)},
+		{"2 business days ago", at(15, 0)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input, ref)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseWeekdays(t *testing.T) {
+	days, err := ParseWeekdays("Fri, saturday,fri")
+	if err != nil {
+		t.Fatalf("ParseWeekdays failed: %v", err)
+	}
+	if len(days) != 2 || days[0] != time.Friday || days[1] != time.Saturday {
+		t.Errorf("expected [Friday Saturday], got %v", days)
+	}
+
+	if _, err := ParseWeekdays("sat,funday"); err == nil {
+		t.Error("expected an error for an unknown weekday")
+	}
+}
@@ -10,9 +10,15 @@ import (
 
 // Parse parses a date string which can be:
 // - Natural language: "today", "tomorrow", "next week", "last month", etc.
+// - A day and time of day: "monday 3pm", "next friday 09:30", "tomorrow at noon"
+// - Numeric dates: "03/04/2025", "3.4.25" (day/month order from DateOrder)
 // - ISO 8601 date: "2025-01-15"
 // - ISO 8601 datetime: "2025-01-15T09:00:00"
 //
+// Input the deterministic grammar doesn't cover goes to a permissive
+// natural-language parser, which may quietly fall back to ref for typos; see
+// ParseStrict.
+//
 // The reference time is used for relative expressions (e.g., "tomorrow" is relative to ref).
 // If ref is zero, time.Now() is used.
 func Parse(s string, ref time.Time) (time.Time, error) {
@@ -24,23 +30,16 @@ func Parse(s string, ref time.Time) (time.Time, error) {
 		ref = time.Now()
 	}
 
-	// Try ISO 8601 datetime first
-	if t, err := time.Parse(time.RFC3339, s); err == nil {
-		return t, nil
-	}
-
-	// Try ISO 8601 datetime without timezone
-	if t, err := time.ParseInLocation("2006-01-02T15:04:05", s, ref.Location()); err == nil {
-		return t, nil
+	t, ok, err := parseKnown(s, ref)
+	if err != nil {
+		return time.Time{}, err
 	}
-
-	// Try ISO 8601 date only (midnight local time)
-	if t, err := time.ParseInLocation("2006-01-02", s, ref.Location()); err == nil {
+	if ok {
 		return t, nil
 	}
 
 	// Try natural language parsing with future direction (for "next week", etc.)
-	t, err := naturaldate.Parse(s, ref, naturaldate.WithDirection(naturaldate.Future))
+	t, err = naturaldate.Parse(s, ref, naturaldate.WithDirection(naturaldate.Future))
 	if err != nil {
 		return time.Time{}, fmt.Errorf("could not parse date %q: %w", s, err)
 	}
@@ -97,8 +96,3 @@ func AddDays(t time.Time, days int) time.Time {
 func FormatISO8601(t time.Time) string {
 	return t.Format(time.RFC3339)
 }
-
-// ParseDuration parses a duration string like "30m", "1h", "90m"
-func ParseDuration(s string) (time.Duration, error) {
-	return time.ParseDuration(s)
-}
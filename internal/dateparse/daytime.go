@@ -0,0 +1,205 @@
+package dateparse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	clockRe   = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))? ?(am|pm)?$`)
+	weekdayRe = regexp.MustCompile(`^(?:(this|next|last) )?([a-z]+)$`)
+)
+
+// parseDayTime handles the common "day plus time of day" inputs without the
+// third-party parser, so their meaning is fixed:
+//   - Days: "today", "tomorrow", "yesterday", business days ("next business
+//     day", "in 3 business days"; see BusinessCalendar), or a weekday name
+//     (full or three letters), a month and day ("dec 25", "25 december
+//     2025"), or a numeric date such as 03/04/2025 (see DateOrder). "friday" and "next friday" are both the first Friday
+//     after today, never today itself; "last friday" is the most recent one
+//     before today; "this friday" is the one in the current week (see
+//     WeekStart), even if it has passed.
+//   - Times: "3pm", "3:30 pm", "09:30", "15:00", "noon", "midnight". A time
+//     without am/pm needs minutes, so "3" alone is not a time.
+//
+// Either part may be left out (a missing day is today, a missing time is
+// midnight) and they may be joined by "at", in either order: "monday 3pm",
+// "next friday 09:30", "tomorrow at noon", "3pm tomorrow".
+func parseDayTime(s string, ref time.Time) (time.Time, bool) {
+	text := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	text = strings.Replace(text, " at ", " ", 1)
+	text = strings.TrimPrefix(text, "at ")
+
+	// Try each split point, with the day first and then the time first
+	words := strings.Fields(text)
+	for i := 0; i <= len(words); i++ {
+		first, second := strings.Join(words[:i], " "), strings.Join(words[i:], " ")
+		if day, ok := parseDay(first, ref); ok {
+			if hour, minute, ok := parseClock(second); ok {
+				return atClock(day, hour, minute), true
+			}
+		}
+		if hour, minute, ok := parseClock(first); ok {
+			if day, ok := parseDay(second, ref); ok {
+				return atClock(day, hour, minute), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseDay returns midnight on the day named by text, or today for ""
+func parseDay(text string, ref time.Time) (time.Time, bool) {
+	today := StartOfDay(ref)
+	switch text {
+	case "", "today":
+		return today, true
+	case "tomorrow":
+		return AddDays(today, 1), true
+	case "yesterday":
+		return AddDays(today, -1), true
+	}
+	if day, ok := parseBusinessDay(text, ref); ok {
+		return day, true
+	}
+	if day, ok, err := parseNumericDate(text, ref); ok {
+		return day, err == nil
+	}
+	if day, ok := parseMonthDay(text, ref); ok {
+		return day, true
+	}
+
+	m := weekdayRe.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, false
+	}
+	weekday, ok := lookupWeekday(m[2])
+	if !ok {
+		return time.Time{}, false
+	}
+
+	switch m[1] {
+	case "last":
+		behind := (int(today.Weekday()) - int(weekday) + 7) % 7
+		if behind == 0 {
+			behind = 7
+		}
+		return AddDays(today, -behind), true
+	case "this":
+		return AddDays(today, weekOffset(weekday)-weekOffset(today.Weekday())), true
+	default:
+		ahead := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if ahead == 0 {
+			ahead = 7
+		}
+		return AddDays(today, ahead), true
+	}
+}
+
+// parseMonthDay reads "dec 25", "25 december" or either with a year ("dec 25
+// 2025"). Without a year the date is the next one on or after today.
+func parseMonthDay(text string, ref time.Time) (time.Time, bool) {
+	words := strings.Fields(text)
+	if len(words) < 2 || len(words) > 3 {
+		return time.Time{}, false
+	}
+
+	monthWord, dayWord := words[0], words[1]
+	if _, err := strconv.Atoi(monthWord); err == nil {
+		monthWord, dayWord = dayWord, monthWord
+	}
+	month, ok := lookupMonth(monthWord)
+	if !ok {
+		return time.Time{}, false
+	}
+	day, err := strconv.Atoi(strings.TrimRight(dayWord, "stndrh"))
+	if err != nil || len(dayWord) > 4 {
+		return time.Time{}, false
+	}
+
+	year := ref.Year()
+	if len(words) == 3 {
+		if len(words[2]) != 4 {
+			return time.Time{}, false
+		}
+		if year, err = strconv.Atoi(words[2]); err != nil {
+			return time.Time{}, false
+		}
+	}
+
+	t, err := makeDate(text, year, int(month), day, ref)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if len(words) == 2 && t.Before(StartOfDay(ref)) {
+		t = t.AddDate(1, 0, 0)
+	}
+	return t, true
+}
+
+// lookupMonth matches a full or three-letter month name
+func lookupMonth(name string) (time.Month, bool) {
+	for month := time.January; month <= time.December; month++ {
+		full := strings.ToLower(month.String())
+		if name == full || (len(name) == 3 && strings.HasPrefix(full, name)) {
+			return month, true
+		}
+	}
+	return 0, false
+}
+
+// lookupWeekday matches a full or three-letter weekday name
+func lookupWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || (len(name) == 3 && strings.HasPrefix(full, name)) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses a time of day; "" is midnight
+func parseClock(text string) (int, int, bool) {
+	switch text {
+	case "", "midnight":
+		return 0, 0, true
+	case "noon", "midday":
+		return 12, 0, true
+	}
+
+	m := clockRe.FindStringSubmatch(text)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if minute > 59 {
+		return 0, 0, false
+	}
+
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, 0, false
+		}
+	}
+	return hour, minute, true
+}
+
+func atClock(day time.Time, hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
@@ -0,0 +1,105 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDayTime(t *testing.T) {
+	// Wednesday 15 January 2025, 10:00
+	ref := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	at := func(d, hour, minute int) time.Time {
+		return time.Date(2025, 1, d, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"monday 3pm", at(20, 15, 0)},
+		{"Mon 3 PM", at(20, 15, 0)},
+		{"next friday 09:30", at(17, 9, 30)},
+		{"friday at 3:45pm", at(17, 15, 45)},
+		{"wednesday 9am", at(22, 9, 0)},
+		{"this wednesday 9am", at(15, 9, 0)},
+		{"this monday", at(13, 0, 0)},
+		{"this sunday noon", at(19, 12, 0)},
+		{"last friday", at(10, 0, 0)},
+		{"last wednesday 17:00", at(8, 17, 0)},
+		{"tomorrow at noon", at(16, 12, 0)},
+		{"today 12am", at(15, 0, 0)},
+		{"today 12pm", at(15, 12, 0)},
+		{"tomorrow midnight", at(16, 0, 0)},
+		{"3pm tomorrow", at(16, 15, 0)},
+		{"at 14:30", at(15, 14, 30)},
+		{"3pm", at(15, 15, 0)},
+		{"yesterday", at(14, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input, ref)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseThisWeekdaySundayStart(t *testing.T) {
+	WeekStart = time.Sunday
+	defer func() { WeekStart = time.Monday }()
+
+	// Wednesday 15 January 2025; the week began on Sunday the 12th
+	ref := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	for input, day := range map[string]int{"this sunday": 12, "this saturday": 18, "this monday": 13} {
+		got, err := Parse(input, ref)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", input, err)
+		}
+		if got.Day() != day {
+			t.Errorf("Parse(%q) = %v, want the %dth", input, got, day)
+		}
+	}
+}
+
+func TestParseDayTimeRejects(t *testing.T) {
+	ref := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	for _, input := range []string{"monday 3", "friday 13pm", "tomorrow 24:00", "9:75", "funday 3pm", "next week"} {
+		if _, ok := parseDayTime(input, ref); ok {
+			t.Errorf("parseDayTime(%q) should not match", input)
+		}
+	}
+}
+
+func TestParseMonthDay(t *testing.T) {
+	// Wednesday 15 January 2025, 10:00
+	ref := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"dec 25", time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)},
+		{"25 December", time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)},
+		{"december 25th 3pm", time.Date(2025, 12, 25, 15, 0, 0, 0, time.UTC)},
+		{"jan 15", time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"jan 3", time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)}, // Already past
+		{"march 1st 2024", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input, ref)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationPartRe = regexp.MustCompile(`^(\d+(?:\.\d+)?) ?([a-z]*)`)
+
+// durationUnits maps the unit words ParseDuration accepts to their length
+var durationUnits = map[string]time.Duration{
+	"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour, "wks": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+}
+
+// IsAllDay reports whether a duration string asks for a whole day ("all day")
+func IsAllDay(s string) bool {
+	switch strings.ToLower(strings.Join(strings.Fields(s), " ")) {
+	case "all day", "all-day", "allday":
+		return true
+	}
+	return false
+}
+
+// ParseDuration parses a positive duration. Besides Go's own syntax ("30m",
+// "1h30m") it accepts words and spaces ("90 minutes", "1 hour 30 min",
+// "1.5 hours", "2 days"), "1h30" where the trailing number is minutes, and
+// "all day" (24 hours; see IsAllDay).
+func ParseDuration(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(strings.TrimSpace(s)); err == nil {
+		if d <= 0 {
+			return 0, fmt.Errorf("duration %q must be positive", s)
+		}
+		return d, nil
+	}
+	if IsAllDay(s) {
+		return 24 * time.Hour, nil
+	}
+
+	text := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if text == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var total time.Duration
+	var last time.Duration
+	for text != "" {
+		m := durationPartRe.FindStringSubmatch(text)
+		if m == nil {
+			return 0, fmt.Errorf("could not parse duration %q (try 30m, 1h30, or \"90 minutes\")", s)
+		}
+		text = strings.TrimPrefix(strings.TrimSpace(text[len(m[0]):]), "and ")
+		text = strings.TrimPrefix(text, ", ")
+
+		value, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("could not parse duration %q", s)
+		}
+
+		unit, ok := durationUnits[m[2]]
+		switch {
+		case m[2] == "" && last == time.Hour && text == "":
+			// "1h30": a trailing bare number after hours is minutes
+			unit = time.Minute
+		case !ok:
+			return 0, fmt.Errorf("could not parse duration %q (unknown unit %q)", s, m[2])
+		}
+		total += time.Duration(value * float64(unit))
+		last = unit
+	}
+
+	if total <= 0 {
+		return 0, fmt.Errorf("duration %q must be positive", s)
+	}
+	return total, nil
+}
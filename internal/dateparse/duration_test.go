@@ -0,0 +1,55 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"30m", 30 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"90 minutes", 90 * time.Minute},
+		{"90mins", 90 * time.Minute},
+		{"1h30", 90 * time.Minute},
+		{"1 hour 30 minutes", 90 * time.Minute},
+		{"1 hr and 15 min", 75 * time.Minute},
+		{"1h, 15m", 75 * time.Minute},
+		{"1.5 hours", 90 * time.Minute},
+		{"2 days", 48 * time.Hour},
+		{"2d", 48 * time.Hour},
+		{"1 week", 7 * 24 * time.Hour},
+		{"All Day", 24 * time.Hour},
+		{"45 Seconds", 45 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseDurationErrors(t *testing.T) {
+	for _, input := range []string{"", "90", "soon", "1 fortnight", "0m", "-1h", "1h 30 45"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) expected an error", input)
+		}
+	}
+}
+
+func TestIsAllDay(t *testing.T) {
+	for input, want := range map[string]bool{"all day": true, "All-Day": true, "allday": true, "1 day": false, "24h": false} {
+		if got := IsAllDay(input); got != want {
+			t.Errorf("IsAllDay(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package dateparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Day-month orders for numeric dates such as 03/04/2025
+const (
+	OrderDMY = "dmy"
+	OrderMDY = "mdy"
+)
+
+// DateOrder says how to read numeric dates whose day and month could be
+// swapped: OrderDMY, OrderMDY, or "" to reject them as ambiguous. The CLI
+// sets it from the date_order config.
+var DateOrder = ""
+
+// ErrAmbiguousDate is returned for numeric dates like 03/04/2025 that read
+// differently day-first and month-first when DateOrder is not set
+var ErrAmbiguousDate = errors.New("ambiguous date")
+
+var (
+	// 3/4, 03/04/2025, 3-4-25, 3.4.2025
+	numericDateRe = regexp.MustCompile(`^(\d{1,2})([/.-])(\d{1,2})(?:([/.-])(\d{2}|\d{4}))?$`)
+	// 2025/04/03 is always year, month, day
+	yearFirstRe = regexp.MustCompile(`^(\d{4})[/.](\d{1,2})[/.](\d{1,2})$`)
+)
+
+// parseNumericDate reads a numeric date, returning midnight on that day. ok is
+// false when text isn't a numeric date at all.
+func parseNumericDate(text string, ref time.Time) (day time.Time, ok bool, err error) {
+	if m := yearFirstRe.FindStringSubmatch(text); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		d, _ := strconv.Atoi(m[3])
+		day, err := makeDate(text, year, month, d, ref)
+		return day, true, err
+	}
+
+	m := numericDateRe.FindStringSubmatch(text)
+	if m == nil || (m[4] != "" && m[4] != m[2]) {
+		return time.Time{}, false, nil
+	}
+
+	first, _ := strconv.Atoi(m[1])
+	second, _ := strconv.Atoi(m[3])
+	year := ref.Year()
+	if m[5] != "" {
+		year, _ = strconv.Atoi(m[5])
+		if len(m[5]) == 2 {
+			year += 2000
+		}
+	}
+
+	order := DateOrder
+	switch {
+	case first == second:
+		order = OrderDMY // Same either way
+	case first > 12:
+		order = OrderDMY
+	case second > 12:
+		order = OrderMDY
+	case order == "":
+		return time.Time{}, true, fmt.Errorf("%w %q: it could be %s or %s; set 'go365 config set --date-order dmy' (or mdy), or use YYYY-MM-DD",
+			ErrAmbiguousDate, text,
+			time.Date(year, time.Month(second), first, 0, 0, 0, 0, ref.Location()).Format("2 January"),
+			time.Date(year, time.Month(first), second, 0, 0, 0, 0, ref.Location()).Format("January 2"))
+	}
+
+	if order == OrderMDY {
+		first, second = second, first
+	}
+	day, err = makeDate(text, year, second, first, ref)
+	return day, true, err
+}
+
+// makeDate builds a date, rejecting days that don't exist (31/02)
+func makeDate(text string, year, month, day int, ref time.Time) (time.Time, error) {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, ref.Location())
+	if month < 1 || month > 12 || t.Day() != day {
+		return time.Time{}, fmt.Errorf("invalid date %q", text)
+	}
+	return t, nil
+}
@@ -0,0 +1,70 @@
+package dateparse
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseNumericDate(t *testing.T) {
+	ref := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		order string
+		input string
+		want  time.Time
+	}{
+		{OrderDMY, "03/04/2025", date(2025, 4, 3)},
+		{OrderMDY, "03/04/2025", date(2025, 3, 4)},
+		{OrderDMY, "3.4.25", date(2025, 4, 3)},
+		{OrderMDY, "3-4", date(2025, 3, 4)},
+		{"", "25/12/2025", date(2025, 12, 25)},
+		{"", "12/25/2025", date(2025, 12, 25)},
+		{"", "04/04/2025", date(2025, 4, 4)},
+		{"", "2025/04/03", date(2025, 4, 3)},
+	}
+
+	for _, tt := range tests {
+		DateOrder = tt.order
+		got, err := Parse(tt.input, ref)
+		if err != nil {
+			t.Errorf("Parse(%q) with order %q failed: %v", tt.input, tt.order, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) with order %q = %v, want %v", tt.input, tt.order, got, tt.want)
+		}
+	}
+	DateOrder = ""
+
+	// A time of day can follow
+	DateOrder = OrderDMY
+	defer func() { DateOrder = "" }()
+	got, err := Parse("03/04/2025 3pm", ref)
+	if err != nil || !got.Equal(time.Date(2025, 4, 3, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("Parse with time = %v, %v", got, err)
+	}
+}
+
+func TestParseNumericDateErrors(t *testing.T) {
+	ref := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	DateOrder = ""
+	if _, err := Parse("03/04/2025", ref); !errors.Is(err, ErrAmbiguousDate) {
+		t.Errorf("expected ErrAmbiguousDate, got %v", err)
+	}
+	if _, _, err := ParseRange("03/04/2025 10am", ref); !errors.Is(err, ErrAmbiguousDate) {
+		t.Errorf("expected ParseRange to report ErrAmbiguousDate, got %v", err)
+	}
+
+	DateOrder = OrderDMY
+	defer func() { DateOrder = "" }()
+	for _, input := range []string{"31/02/2025", "13/13/2025", "3/4-2025"} {
+		if _, err := Parse(input, ref); err == nil {
+			t.Errorf("Parse(%q) expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package dateparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	relativeRangeRe = regexp.MustCompile(`^(this|last|next) (day|week|month|quarter|year)$`)
+	countRangeRe    = regexp.MustCompile(`^(last|past|next) (\d+) (day|week|month)s?$`)
+	quarterRe       = regexp.MustCompile(`^q([1-4])(?: (\d{4}))?$`)
+)
+
+// ParseRange parses a period into its start and its (exclusive) end. It
+// understands:
+//   - Days: "today", "yesterday", "tomorrow", or any date Parse accepts
+//   - "this/last/next week|month|quarter|year" (weeks start on WeekStart)
+//   - "last/next N days|weeks|months", counted in whole days and including
+//     today, so "last 7 days" is today and the six days before it
+//   - Quarters: "Q2" (this year) or "Q2 2025"
+//   - Month names: "march" (this year) or "march 2025"
+//   - Explicit spans: "2025-01-06..2025-01-10", where the end day is included
+//
+// If ref is zero, time.Now() is used.
+func ParseRange(s string, ref time.Time) (time.Time, time.Time, error) {
+	if ref.IsZero() {
+		ref = time.Now()
+	}
+
+	text := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if text == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("empty date range")
+	}
+
+	if from, to, ok := strings.Cut(text, ".."); ok {
+		start, _, err := ParseRange(from, ref)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		_, end, err := ParseRange(to, ref)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if !end.After(start) {
+			return time.Time{}, time.Time{}, fmt.Errorf("date range %q ends before it starts", s)
+		}
+		return start, end, nil
+	}
+
+	today := StartOfDay(ref)
+	switch text {
+	case "today":
+		return today, AddDays(today, 1), nil
+	case "yesterday":
+		return AddDays(today, -1), today, nil
+	case "tomorrow":
+		return AddDays(today, 1), AddDays(today, 2), nil
+	}
+
+	if m := relativeRangeRe.FindStringSubmatch(text); m != nil {
+		offset := map[string]int{"this": 0, "last": -1, "next": 1}[m[1]]
+		start, end := periodOf(m[2], today)
+		for ; offset < 0; offset++ {
+			start, end = periodOf(m[2], AddDays(start, -1))
+		}
+		for ; offset > 0; offset-- {
+			start, end = periodOf(m[2], end)
+		}
+		return start, end, nil
+	}
+
+	if m := countRangeRe.FindStringSubmatch(text); m != nil {
+		n, err := strconv.Atoi(m[2])
+		if err != nil || n < 1 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid count in date range %q", s)
+		}
+		if m[1] == "next" {
+			return today, addUnits(today, m[3], n), nil
+		}
+		end := AddDays(today, 1)
+		return addUnits(end, m[3], -n), end, nil
+	}
+
+	if m := quarterRe.FindStringSubmatch(text); m != nil {
+		quarter, _ := strconv.Atoi(m[1])
+		year := ref.Year()
+		if m[2] != "" {
+			year, _ = strconv.Atoi(m[2])
+		}
+		start := time.Date(year, time.Month(3*quarter-2), 1, 0, 0, 0, 0, ref.Location())
+		return start, start.AddDate(0, 3, 0), nil
+	}
+
+	if start, ok := parseMonth(text, ref); ok {
+		return start, start.AddDate(0, 1, 0), nil
+	}
+
+	// Anything else is a single day
+	day, err := Parse(s, ref)
+	if errors.Is(err, ErrAmbiguousDate) {
+		return time.Time{}, time.Time{}, err
+	}
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("could not parse date range %q", s)
+	}
+	day = StartOfDay(day)
+	return day, AddDays(day, 1), nil
+}
+
+// WeekStart is the first day of the week for "this week", "next week" and
+// StartOfWeek. The CLI sets it from the week_start config.
+var WeekStart = time.Monday
+
+// StartOfWeek returns midnight on the WeekStart day on or before t
+func StartOfWeek(t time.Time) time.Time {
+	day := StartOfDay(t)
+	return AddDays(day, -weekOffset(day.Weekday()))
+}
+
+// weekOffset is how many days into the week (from WeekStart) a weekday falls
+func weekOffset(day time.Weekday) int {
+	return (int(day) - int(WeekStart) + 7) % 7
+}
+
+// periodOf returns the day, week, month, quarter or year containing t
+func periodOf(unit string, t time.Time) (time.Time, time.Time) {
+	day := StartOfDay(t)
+	switch unit {
+	case "week":
+		start := StartOfWeek(day)
+		return start, AddDays(start, 7)
+	case "month":
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(0, 1, 0)
+	case "quarter":
+		month := time.Month((int(day.Month())-1)/3*3 + 1)
+		start := time.Date(day.Year(), month, 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(0, 3, 0)
+	case "year":
+		start := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(1, 0, 0)
+	default:
+		return day, AddDays(day, 1)
+	}
+}
+
+// addUnits adds n days, weeks or months to t
+func addUnits(t time.Time, unit string, n int) time.Time {
+	switch unit {
+	case "week":
+		return AddDays(t, 7*n)
+	case "month":
+		return t.AddDate(0, n, 0)
+	default:
+		return AddDays(t, n)
+	}
+}
+
+// parseMonth recognizes "march" or "march 2025" (full or three-letter names)
+// and returns the first day of that month
+func parseMonth(text string, ref time.Time) (time.Time, bool) {
+	name, yearText, _ := strings.Cut(text, " ")
+	year := ref.Year()
+	if yearText != "" {
+		y, err := strconv.Atoi(yearText)
+		if err != nil || len(yearText) != 4 {
+			return time.Time{}, false
+		}
+		year = y
+	}
+
+	month, ok := lookupMonth(name)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Date(year, month, 1, 0, 0, 0, 0, ref.Location()), true
+}
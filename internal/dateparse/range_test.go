@@ -0,0 +1,97 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRange(t *testing.T) {
+	// Wednesday 15 January 2025
+	ref := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		input      string
+		start, end time.Time
+	}{
+		{"today", day(2025, 1, 15), day(2025, 1, 16)},
+		{"Yesterday", day(2025, 1, 14), day(2025, 1, 15)},
+		{"tomorrow", day(2025, 1, 16), day(2025, 1, 17)},
+		{"this week", day(2025, 1, 13), day(2025, 1, 20)},
+		{"last week", day(2025, 1, 6), day(2025, 1, 13)},
+		{"next  week", day(2025, 1, 20), day(2025, 1, 27)},
+		{"this month", day(2025, 1, 1), day(2025, 2, 1)},
+		{"next month", day(2025, 2, 1), day(2025, 3, 1)},
+		{"last month", day(2024, 12, 1), day(2025, 1, 1)},
+		{"this quarter", day(2025, 1, 1), day(2025, 4, 1)},
+		{"last quarter", day(2024, 10, 1), day(2025, 1, 1)},
+		{"next year", day(2026, 1, 1), day(2027, 1, 1)},
+		{"Q2", day(2025, 4, 1), day(2025, 7, 1)},
+		{"q4 2024", day(2024, 10, 1), day(2025, 1, 1)},
+		{"last 7 days", day(2025, 1, 9), day(2025, 1, 16)},
+		{"past 2 weeks", day(2025, 1, 2), day(2025, 1, 16)},
+		{"next 3 days", day(2025, 1, 15), day(2025, 1, 18)},
+		{"last 1 month", day(2024, 12, 16), day(2025, 1, 16)},
+		{"march", day(2025, 3, 1), day(2025, 4, 1)},
+		{"Dec 2024", day(2024, 12, 1), day(2025, 1, 1)},
+		{"2025-02-03", day(2025, 2, 3), day(2025, 2, 4)},
+		{"2025-01-06..2025-01-10", day(2025, 1, 6), day(2025, 1, 11)},
+		{"today..next week", day(2025, 1, 15), day(2025, 1, 27)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			start, end, err := ParseRange(tt.input, ref)
+			if err != nil {
+				t.Fatalf("ParseRange(%q) failed: %v", tt.input, err)
+			}
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("ParseRange(%q) = %v - %v, want %v - %v", tt.input, start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestParseRangeErrors(t *testing.T) {
+	ref := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	for _, input := range []string{"", "last 0 days", "2025-02-01..2025-01-01", "whenever-ish"} {
+		if _, _, err := ParseRange(input, ref); err == nil {
+			t.Errorf("ParseRange(%q) expected an error", input)
+		}
+	}
+}
+
+func TestParseRangeSundayWeek(t *testing.T) {
+	WeekStart = time.Sunday
+	defer func() { WeekStart = time.Monday }()
+
+	// Wednesday 15 January 2025
+	ref := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	start, end, err := ParseRange("this week", ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC); !start.Equal(want) || !end.Equal(want.AddDate(0, 0, 7)) {
+		t.Errorf("this week = %v - %v, want a week from %v", start, end, want)
+	}
+
+	start, _, _ = ParseRange("next week", ref)
+	if want := time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("next week starts %v, want %v", start, want)
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	sunday := time.Date(2025, 1, 19, 18, 0, 0, 0, time.UTC)
+	if got, want := StartOfWeek(sunday), time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("StartOfWeek (Monday start) = %v, want %v", got, want)
+	}
+
+	WeekStart = time.Saturday
+	defer func() { WeekStart = time.Monday }()
+	if got, want := StartOfWeek(sunday), time.Date(2025, 1, 18, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("StartOfWeek (Saturday start) = %v, want %v", got, want)
+	}
+}
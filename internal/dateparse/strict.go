@@ -0,0 +1,201 @@
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var offsetRe = regexp.MustCompile(`^(?:in (\d+|an?) ([a-z]+)|(\d+|an?) ([a-z]+) (from now|ago))$`)
+
+// offsetUnits are the units accepted by "in 3 days" and "2 hours ago"
+var offsetUnits = map[string]string{
+	"minute": "minute", "minutes": "minute", "min": "minute", "mins": "minute",
+	"hour": "hour", "hours": "hour", "hr": "hour", "hrs": "hour",
+	"day": "day", "days": "day",
+	"week": "week", "weeks": "week",
+	"month": "month", "months": "month",
+}
+
+// strictWords are the words the strict grammar understands, used to suggest
+// corrections for typos
+var strictWords = []string{
+	"today", "tomorrow", "yesterday", "now", "noon", "midday", "midnight",
+	"next", "last", "this", "previous", "in", "ago", "from", "at",
+	"business", "day", "days", "week", "weeks", "month", "months",
+	"hour", "hours", "minute", "minutes",
+	"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+	"january", "february", "march", "april", "may", "june", "july",
+	"august", "september", "october", "november", "december",
+}
+
+// ParseStrict is Parse without the natural-language fallback. It accepts the
+// ISO forms, numeric dates, the day and time grammar ("monday 3pm", "next
+// business day", "dec 25 9am"), "now" and offsets ("in 3 days", "2 hours
+// ago"). Anything else is an error, with a suggested correction when a word
+// looks like a typo. Use it where a misread date would do damage, such as
+// creating events or scheduling mail, rather than silently landing on today.
+func ParseStrict(s string, ref time.Time) (time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return time.Time{}, fmt.Errorf("empty date string")
+	}
+	if ref.IsZero() {
+		ref = time.Now()
+	}
+
+	t, ok, err := parseKnown(s, ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ok {
+		return t, nil
+	}
+
+	hint := `try "tomorrow 3pm", "next friday 09:30", "in 2 days" or 2025-01-20T15:00:00`
+	if corrected, changed := suggestCorrection(s); changed {
+		hint = fmt.Sprintf("did you mean %q?", corrected)
+	}
+	return time.Time{}, fmt.Errorf("could not parse date %q (%s)", s, hint)
+}
+
+// parseKnown tries every deterministic form. ok is false when none matched;
+// err is set for input that matched but is invalid, such as an ambiguous
+// numeric date.
+func parseKnown(s string, ref time.Time) (time.Time, bool, error) {
+	s = strings.TrimSpace(s)
+
+	// ISO 8601 datetime, with or without a zone, or a date (midnight local)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05", s, ref.Location()); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04", s, ref.Location()); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, ref.Location()); err == nil {
+		return t, true, nil
+	}
+
+	// Numeric dates that are ambiguous or don't exist must not fall through
+	// to the permissive parser
+	for _, word := range strings.Fields(s) {
+		if _, ok, err := parseNumericDate(word, ref); ok && err != nil {
+			return time.Time{}, false, err
+		}
+	}
+
+	// Days, weekdays and times of day are parsed here for predictable results
+	if t, ok := parseDayTime(s, ref); ok {
+		return t, true, nil
+	}
+	if t, ok := parseOffset(s, ref); ok {
+		return t, true, nil
+	}
+	return time.Time{}, false, nil
+}
+
+// parseOffset handles "now", "in 3 days", "in an hour", "2 weeks from now"
+// and "5 minutes ago", keeping the time of day
+func parseOffset(s string, ref time.Time) (time.Time, bool) {
+	text := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if text == "now" {
+		return ref, true
+	}
+
+	m := offsetRe.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, false
+	}
+	count, unitWord, sign := m[1], m[2], 1
+	if count == "" {
+		count, unitWord = m[3], m[4]
+		if m[5] == "ago" {
+			sign = -1
+		}
+	}
+
+	n := 1
+	if count != "a" && count != "an" {
+		n, _ = strconv.Atoi(count)
+	}
+	n *= sign
+
+	switch offsetUnits[unitWord] {
+	case "minute":
+		return ref.Add(time.Duration(n) * time.Minute), true
+	case "hour":
+		return ref.Add(time.Duration(n) * time.Hour), true
+	case "day":
+		return ref.AddDate(0, 0, n), true
+	case "week":
+		return ref.AddDate(0, 0, 7*n), true
+	case "month":
+		return ref.AddDate(0, n, 0), true
+	}
+	return time.Time{}, false
+}
+
+// suggestCorrection replaces each unknown word with the closest known word
+// within two edits, reporting whether anything changed
+func suggestCorrection(s string) (string, bool) {
+	words := strings.Fields(strings.ToLower(s))
+	changed := false
+	for i, word := range words {
+		if _, err := strconv.Atoi(word); err == nil || knownWord(word) {
+			continue
+		}
+		if _, _, ok := parseClock(word); ok {
+			continue
+		}
+
+		best, bestDistance := "", 3
+		for _, candidate := range strictWords {
+			if d := editDistance(word, candidate); d < bestDistance {
+				best, bestDistance = candidate, d
+			}
+		}
+		if best != "" {
+			words[i] = best
+			changed = true
+		}
+	}
+	return strings.Join(words, " "), changed
+}
+
+func knownWord(word string) bool {
+	for _, known := range strictWords {
+		if word == known {
+			return true
+		}
+	}
+	if _, ok := lookupWeekday(word); ok {
+		return true
+	}
+	_, ok := lookupMonth(word)
+	return ok
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,95 @@
+package dateparse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStrict(t *testing.T) {
+	// Wednesday 15 January 2025, 10:00
+	ref := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2025-01-20", time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{"2025-01-20T15:00", time.Date(2025, 1, 20, 15, 0, 0, 0, time.UTC)},
+		{"2025-01-20T15:00:00Z", time.Date(2025, 1, 20, 15, 0, 0, 0, time.UTC)},
+		{"tomorrow 3pm", time.Date(2025, 1, 16, 15, 0, 0, 0, time.UTC)},
+		{"next friday 09:30", time.Date(2025, 1, 17, 9, 30, 0, 0, time.UTC)},
+		{"next business day", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"dec 25 9am", time.Date(2025, 12, 25, 9, 0, 0, 0, time.UTC)},
+		{"now", ref},
+		{"in 3 days", time.Date(2025, 1, 18, 10, 0, 0, 0, time.UTC)},
+		{"in an hour", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"2 weeks from now", time.Date(2025, 1, 29, 10, 0, 0, 0, time.UTC)},
+		{"30 minutes ago", time.Date(2025, 1, 15, 9, 30, 0, 0, time.UTC)},
+		{"in 1 month", time.Date(2025, 2, 15, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseStrict(tt.input, ref)
+			if err != nil {
+				t.Fatalf("ParseStrict(%q) failed: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseStrict(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStrictErrors(t *testing.T) {
+	ref := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		wantMsg string
+	}{
+		{"tomorow", `did you mean "tomorrow"?`},
+		{"frday 3pm", `did you mean "friday 3pm"?`},
+		{"nxt monday", `did you mean "next monday"?`},
+		{"blah", `try "tomorrow 3pm"`},
+		{"in 3 fortnights", `try "tomorrow 3pm"`},
+		{"", "empty date string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseStrict(tt.input, ref)
+			if err == nil {
+				t.Fatalf("ParseStrict(%q) should fail", tt.input)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("ParseStrict(%q) error = %q, want it to contain %q", tt.input, err, tt.wantMsg)
+			}
+		})
+	}
+
+	if _, err := ParseStrict("03/04/2025", ref); !errors.Is(err, ErrAmbiguousDate) {
+		t.Errorf("ParseStrict(03/04/2025) error = %v, want ErrAmbiguousDate", err)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"friday", "friday", 0},
+		{"frday", "friday", 1},
+		{"tomorow", "tomorrow", 1},
+		{"nxt", "next", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Package logfile writes a log file that is rotated by size, keeping a fixed
+// number of older files alongside it (go365.log.1, go365.log.2, ...).
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Writer appends to a log file, rotating it once it would grow past MaxSize.
+// Several processes may share one file: each appends whole lines, and the
+// size is re-read when the file is opened.
+type Writer struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Open opens (creating if needed) the log file at path. Files past maxSize
+// bytes are rotated, and maxFiles older files are kept.
+func Open(path string, maxSize int64, maxFiles int) (*Writer, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("log file size limit must be positive")
+	}
+	if maxFiles < 1 {
+		maxFiles = 1
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	w := &Writer{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	if w.size >= maxSize {
+		if err := w.rotate(); err != nil {
+			w.file.Close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Write appends p, rotating first if it would take the file past the limit
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+// rotate shifts each older file up one (dropping the oldest), moves the
+// current file to .1 and starts a new one
+func (w *Writer) rotate() error {
+	w.file.Close()
+
+	os.Remove(fmt.Sprintf("%s.%d", w.path, w.maxFiles))
+	for i := w.maxFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", w.path, i), fmt.Sprintf("%s.%d", w.path, i+1))
+	}
+	// Another process may have rotated already; a missing file is fine
+	if err := os.Rename(w.path, w.path+".1"); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	return w.open()
+}
@@ -0,0 +1,76 @@
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriterRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "go365.log")
+
+	w, err := Open(path, 20, 2)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, line := range []string{"first line\n", "second line\n", "third line\n", "fourth line\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	w.Close()
+
+	want := map[string]string{
+		path:        "fourth line\n",
+		path + ".1": "third line\n",
+		path + ".2": "second line\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("Expected %s: %v", filepath.Base(name), err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), data, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("Expected only two rotated files to be kept")
+	}
+}
+
+func TestOpenAppendsAndRotatesFullFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go365.log")
+
+	w, _ := Open(path, 100, 1)
+	w.Write([]byte("earlier run\n"))
+	w.Close()
+
+	// A second process appends to the same file
+	w, _ = Open(path, 100, 1)
+	w.Write([]byte("later run\n"))
+	w.Close()
+	data, _ := os.ReadFile(path)
+	if string(data) != "earlier run\nlater run\n" {
+		t.Errorf("Expected appended runs, got %q", data)
+	}
+
+	// A file already at the limit is rotated on open
+	os.WriteFile(path, []byte(strings.Repeat("x", 100)), 0600)
+	w, _ = Open(path, 100, 1)
+	w.Write([]byte("fresh\n"))
+	w.Close()
+	data, _ = os.ReadFile(path)
+	if string(data) != "fresh\n" {
+		t.Errorf("Expected a fresh file after rotation, got %q", data)
+	}
+}
+
+func TestWriteAfterClose(t *testing.T) {
+	w, _ := Open(filepath.Join(t.TempDir(), "go365.log"), 100, 1)
+	w.Close()
+	if _, err := w.Write([]byte("late\n")); err == nil {
+		t.Error("Expected an error writing to a closed log")
+	}
+}
@@ -0,0 +1,78 @@
+// Package notify raises native desktop notifications using the platform's
+// own tools: notify-send on Linux and osascript on macOS.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Action is a button on a notification
+type Action struct {
+	Key   string
+	Label string
+}
+
+// Notification is a desktop notification
+type Notification struct {
+	Title   string
+	Body    string
+	Urgent  bool
+	Actions []Action
+}
+
+// Send shows n and, if it has actions and the platform supports them, waits
+// for the user and returns the chosen action's key. An empty key means the
+// notification was dismissed or actions aren't supported.
+func Send(ctx context.Context, n *Notification) (string, error) {
+	name, args, err := command(runtime.GOOS, n)
+	if err != nil {
+		return "", err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to show notification: %w", err)
+	}
+	if len(n.Actions) == 0 || name != "notify-send" {
+		return "", nil
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// command builds the notifier invocation for an OS
+func command(goos string, n *Notification) (string, []string, error) {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		args := []string{"--app-name=go365"}
+		if n.Urgent {
+			args = append(args, "--urgency=critical")
+		}
+		for _, a := range n.Actions {
+			args = append(args, "--action="+a.Key+"="+a.Label)
+		}
+		if len(n.Actions) > 0 {
+			// Block until the user picks an action; its key is printed
+			args = append(args, "--wait")
+		}
+		return "notify-send", append(args, n.Title, n.Body), nil
+
+	case "darwin":
+		// Notification Center notifications from osascript can't carry actions.
+		// AppleScript strings share Go's \" and \\ escapes but not \n.
+		body := strings.ReplaceAll(n.Body, "\n", " ")
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(body), strconv.Quote(n.Title))
+		if n.Urgent {
+			script += ` sound name "default"`
+		}
+		return "osascript", []string{"-e", script}, nil
+	}
+	return "", nil, fmt.Errorf("desktop notifications are not supported on %s", goos)
+}
@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCommandLinux(t *testing.T) {
+	n := &Notification{
+		Title:   "Standup",
+		Body:    "Starts in 5 minutes",
+		Urgent:  true,
+		Actions: []Action{{Key: "join", Label: "Join"}, {Key: "open", Label: "Open"}},
+	}
+
+	name, args, err := command("linux", n)
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if name != "notify-send" {
+		t.Errorf("Expected notify-send, got %s", name)
+	}
+	for _, want := range []string{"--urgency=critical", "--action=join=Join", "--action=open=Open", "--wait"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("Expected %s in %v", want, args)
+		}
+	}
+	if args[len(args)-2] != "Standup" || args[len(args)-1] != "Starts in 5 minutes" {
+		t.Errorf("Expected title and body last, got %v", args)
+	}
+
+	// No actions: don't block
+	_, args, _ = command("linux", &Notification{Title: "Mail", Body: "Hello"})
+	if slices.Contains(args, "--wait") {
+		t.Errorf("Did not expect --wait without actions: %v", args)
+	}
+}
+
+func TestCommandDarwin(t *testing.T) {
+	name, args, err := command("darwin", &Notification{Title: `Say "hi"`, Body: "Body"})
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if name != "osascript" || len(args) != 2 {
+		t.Fatalf("Unexpected command %s %v", name, args)
+	}
+	if !strings.Contains(args[1], `with title "Say \"hi\""`) {
+		t.Errorf("Expected quoted title, got %s", args[1])
+	}
+}
+
+func TestCommandUnsupported(t *testing.T) {
+	if _, _, err := command("plan9", &Notification{}); err == nil {
+		t.Error("Expected error for unsupported OS")
+	}
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ANSI escape sequences used for styling human-readable output.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiItalic = "\x1b[3m"
+	ansiUnder  = "\x1b[4m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// colorEnabled controls whether the style helpers emit escape sequences. It is
+// off by default so library callers and tests get plain text.
+var colorEnabled bool
+
+// ShouldColor reports whether output written to f should be styled: color is
+// used only on terminals, and never when noColor is set, NO_COLOR is set
+// (https://no-color.org) or TERM is "dumb".
+func ShouldColor(f *os.File, noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// SetColor turns styling on or off.
+func SetColor(enabled bool) {
+	colorEnabled = enabled
+}
+
+func style(code, s string) string {
+	if !colorEnabled || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// Bold emphasizes s (e.g. unread messages).
+func Bold(s string) string { return style(ansiBold, s) }
+
+// Dim de-emphasizes s (e.g. declined events).
+func Dim(s string) string { return style(ansiDim, s) }
+
+// Italic styles emphasized text.
+func Italic(s string) string { return style(ansiItalic, s) }
+
+// Underline styles s as a link.
+func Underline(s string) string { return style(ansiUnder, s) }
+
+// Red styles s as an error or negative state.
+func Red(s string) string { return style(ansiRed, s) }
+
+// Green styles s as a success or positive state.
+func Green(s string) string { return style(ansiGreen, s) }
+
+// Yellow styles s as a pending or tentative state.
+func Yellow(s string) string { return style(ansiYellow, s) }
+
+// Status colors a Graph status or response value by what it means: green for
+// accepted/succeeded/free, red for declined/failed/busy, yellow for states still
+// waiting on someone. Unknown values are returned unchanged.
+func Status(s string) string {
+	switch strings.ToLower(s) {
+	case "accepted", "organizer", "succeeded", "completed", "free":
+		return Green(s)
+	case "declined", "failed", "busy", "oof":
+		return Red(s)
+	case "tentativelyaccepted", "tentative", "notresponded", "none", "notstarted",
+		"inprogress", "pending", "workingelsewhere":
+		return Yellow(s)
+	default:
+		return s
+	}
+}
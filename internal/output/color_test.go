@@ -0,0 +1,54 @@
+package output
+
+import (
+	"os"
+	"testing"
+)
+
+func TestStyleDisabledByDefault(t *testing.T) {
+	SetColor(false)
+	if Bold("x") != "x" || Status("declined") != "declined" {
+		t.Error("Expected plain text when color is disabled")
+	}
+}
+
+func TestStatusColors(t *testing.T) {
+	SetColor(true)
+	defer SetColor(false)
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"accepted", ansiGreen + "accepted" + ansiReset},
+		{"Declined", ansiRed + "Declined" + ansiReset},
+		{"tentativelyAccepted", ansiYellow + "tentativelyAccepted" + ansiReset},
+		{"somethingElse", "somethingElse"},
+	}
+	for _, tt := range tests {
+		if got := Status(tt.in); got != tt.want {
+			t.Errorf("Status(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if Bold("") != "" {
+		t.Error("Expected empty strings to stay empty")
+	}
+}
+
+func TestShouldColor(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if ShouldColor(f, false) {
+		t.Error("Expected no color for a regular file")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if ShouldColor(os.Stdout, false) {
+		t.Error("Expected NO_COLOR to disable color")
+	}
+}
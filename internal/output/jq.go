@@ -0,0 +1,53 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/itchyny/gojq"
+)
+
+// WriteJQ applies a jq query to the JSON form of v and writes each result.
+// String results are written raw (like jq -r); other results as JSON.
+func WriteJQ(w io.Writer, query string, v any) error {
+	parsed, err := gojq.Parse(query)
+	if err != nil {
+		return fmt.Errorf("invalid jq query: %w", err)
+	}
+	code, err := gojq.Compile(parsed)
+	if err != nil {
+		return fmt.Errorf("invalid jq query: %w", err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	var input any
+	if err := json.Unmarshal(data, &input); err != nil {
+		return fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	iter := code.Run(input)
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if err, ok := result.(error); ok {
+			if haltErr, ok := err.(*gojq.HaltError); ok && haltErr.Value() == nil {
+				return nil
+			}
+			return fmt.Errorf("jq: %w", err)
+		}
+
+		if s, ok := result.(string); ok {
+			fmt.Fprintln(w, s)
+			continue
+		}
+		if err := WriteJSON(w, result); err != nil {
+			return err
+		}
+	}
+}
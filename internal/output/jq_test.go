@@ -0,0 +1,47 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteJQ(t *testing.T) {
+	resp := FormatListResponse([]map[string]any{
+		{"subject": "Hello", "isRead": true, "size": 10},
+		{"subject": "Report", "isRead": false, "size": 20},
+	}, 2, "next")
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"raw strings", ".value[].subject", "Hello\nReport\n"},
+		{"filter", ".value[] | select(.isRead | not) | .subject", "Report\n"},
+		{"numbers", "[.value[].size] | add", "30\n"},
+		{"objects", ".value[0] | {subject}", "{\n  \"subject\": \"Hello\"\n}\n"},
+		{"top level", ".nextPageToken", "next\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteJQ(&buf, tt.query, resp); err != nil {
+				t.Fatalf("WriteJQ failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestWriteJQErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJQ(&buf, ".value[", nil); err == nil {
+		t.Error("Expected parse error")
+	}
+	if err := WriteJQ(&buf, `error("boom")`, nil); err == nil {
+		t.Error("Expected runtime error")
+	}
+}
@@ -0,0 +1,224 @@
+package output
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// defaultWidth is used when the terminal width can't be determined
+const defaultWidth = 80
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listPattern     = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	rulePattern     = regexp.MustCompile(`^\s*([-*_])(\s*([-*_]))+\s*$`)
+	imagePattern    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]*)(?:\s+"[^"]*")?\)`)
+	linkPattern     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]*)(?:\s+"[^"]*")?\)`)
+	autolinkPattern = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	codeSpanPattern = regexp.MustCompile("`([^`]+)`")
+	strongPattern   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	emphasisPattern = regexp.MustCompile(`(^|[^\w*])\*([^*\s][^*]*)\*|(^|[^\w_])_([^_\s][^_]*)_`)
+	escapePattern   = regexp.MustCompile(`\\([\\` + "`" + `*_{}\[\]()#+\-.!|>~])`)
+)
+
+// TerminalWidth returns the width of the terminal f is attached to, or a
+// default when it isn't a terminal
+func TerminalWidth(f *os.File) int {
+	if w, _, err := term.GetSize(f.Fd()); err == nil && w > 0 {
+		return w
+	}
+	return defaultWidth
+}
+
+// RenderMarkdown formats Markdown (as produced by HTMLToMarkdown) for reading
+// in a terminal: headings and emphasis are styled, links show their target,
+// lists and quotes are indented, and text is wrapped to width. Styling follows
+// SetColor; the layout is applied either way.
+func RenderMarkdown(md string, width int) string {
+	if width <= 0 {
+		width = defaultWidth
+	}
+
+	var out []string
+	var para []string
+	paraPrefix, paraIndent := "", ""
+	broken := false
+
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		out = append(out, wrapBlock(renderInline(strings.Join(para, " ")), width, paraPrefix, paraIndent)...)
+		para = nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			// Fenced code is shown verbatim, indented and dimmed
+			flush()
+			fence := trimmed[:3]
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				out = append(out, "    "+Dim(lines[i]))
+			}
+			out = append(out, "")
+
+		case trimmed == "":
+			flush()
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+
+		case headingPattern.MatchString(trimmed):
+			flush()
+			m := headingPattern.FindStringSubmatch(trimmed)
+			text := renderInline(m[2])
+			if len(m[1]) == 1 {
+				text = strings.ToUpper(text)
+			}
+			out = append(out, wrapBlock(Bold(text), width, "", "")...)
+
+		case rulePattern.MatchString(trimmed) && len(strings.ReplaceAll(trimmed, " ", "")) >= 3:
+			flush()
+			out = append(out, Dim(strings.Repeat("─", min(width, 40))))
+
+		case strings.HasPrefix(trimmed, ">"):
+			// Consecutive quote lines form one wrapped block
+			flush()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"))
+				if text != "" {
+					quote = append(quote, text)
+				}
+			}
+			i--
+			bar := Dim("│ ")
+			out = append(out, wrapBlock(renderInline(strings.Join(quote, " ")), width, bar, bar)...)
+
+		case strings.HasPrefix(trimmed, "|"):
+			// Tables are left as written; reflowing them would break alignment
+			flush()
+			out = append(out, renderInline(line))
+
+		case listPattern.MatchString(line):
+			flush()
+			m := listPattern.FindStringSubmatch(line)
+			indent := strings.Repeat(" ", len(strings.ReplaceAll(m[1], "\t", "    ")))
+			marker := m[2]
+			if !isDigit(marker[0]) {
+				marker = "•"
+			}
+			para = []string{m[3]}
+			paraPrefix = indent + marker + " "
+			paraIndent = indent + strings.Repeat(" ", ansi.StringWidth(marker)+1)
+
+		default:
+			if len(para) == 0 && !broken {
+				paraPrefix, paraIndent = "", ""
+			}
+			// Hard breaks (two trailing spaces or a backslash) end the line;
+			// the next one continues on the same indent
+			broken = strings.HasSuffix(line, "  ") || strings.HasSuffix(trimmed, "\\")
+			para = append(para, strings.TrimSuffix(trimmed, "\\"))
+			if broken {
+				flush()
+				paraPrefix = paraIndent
+			}
+			continue
+		}
+		broken = false
+	}
+	flush()
+
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return strings.Join(out, "\n")
+}
+
+// wrapBlock wraps text to width, starting the first line with prefix and the
+// rest with indent
+func wrapBlock(text string, width int, prefix, indent string) []string {
+	limit := width - max(ansi.StringWidth(prefix), ansi.StringWidth(indent))
+	if limit < 20 {
+		limit = 20
+	}
+	lines := strings.Split(ansi.Wrap(text, limit, ""), "\n")
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = prefix + line
+		} else {
+			lines[i] = indent + line
+		}
+	}
+	return lines
+}
+
+// renderInline styles code spans, emphasis and links within a line
+func renderInline(s string) string {
+	// Code spans are protected from the other rules
+	var codes []string
+	s = codeSpanPattern.ReplaceAllStringFunc(s, func(m string) string {
+		codes = append(codes, Yellow(codeSpanPattern.FindStringSubmatch(m)[1]))
+		return "\x00c" + strconv.Itoa(len(codes)-1) + "\x01"
+	})
+
+	// Escaped punctuation is hidden from the rules and restored afterwards
+	var escapes []string
+	s = escapePattern.ReplaceAllStringFunc(s, func(m string) string {
+		escapes = append(escapes, m[1:])
+		return "\x00e" + strconv.Itoa(len(escapes)-1) + "\x01"
+	})
+
+	s = imagePattern.ReplaceAllStringFunc(s, func(m string) string {
+		alt := imagePattern.FindStringSubmatch(m)[1]
+		if alt == "" {
+			alt = "image"
+		}
+		return Dim("[" + alt + "]")
+	})
+	s = autolinkPattern.ReplaceAllStringFunc(s, func(m string) string {
+		return Underline(strings.TrimPrefix(autolinkPattern.FindStringSubmatch(m)[1], "mailto:"))
+	})
+	s = linkPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := linkPattern.FindStringSubmatch(m)
+		text, target := sub[1], sub[2]
+		if target == "" || text == target || "mailto:"+text == target {
+			return Underline(text)
+		}
+		return Underline(text) + " " + Dim("<"+target+">")
+	})
+	s = strongPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := strongPattern.FindStringSubmatch(m)
+		return Bold(sub[1] + sub[2])
+	})
+	s = emphasisPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := emphasisPattern.FindStringSubmatch(m)
+		if sub[2] != "" {
+			return sub[1] + Italic(sub[2])
+		}
+		return sub[3] + Italic(sub[4])
+	})
+
+	for i, e := range escapes {
+		s = strings.Replace(s, "\x00e"+strconv.Itoa(i)+"\x01", e, 1)
+	}
+	for i, c := range codes {
+		s = strings.Replace(s, "\x00c"+strconv.Itoa(i)+"\x01", c, 1)
+	}
+	return s
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
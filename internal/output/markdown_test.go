@@ -0,0 +1,79 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderMarkdownLayout(t *testing.T) {
+	SetColor(false)
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"heading", "# Weekly update\n\nAll good.", "WEEKLY UPDATE\n\nAll good."},
+		{"subheading", "## Next steps ##", "Next steps"},
+		{"paragraph joined", "first line\nsecond line", "first line second line"},
+		{"hard break", "Regards,  \nAlice", "Regards,\nAlice"},
+		{"backslash break", "Regards,\\\nAlice", "Regards,\nAlice"},
+		{"bullets", "- one\n* two\n  - nested", "• one\n• two\n  • nested"},
+		{"numbered", "1. first\n2. second", "1. first\n2. second"},
+		{"link", "See [the doc](https://example.com/doc).", "See the doc <https://example.com/doc>."},
+		{"bare link", "[https://example.com](https://example.com)", "https://example.com"},
+		{"mailto", "[bob@example.com](mailto:bob@example.com)", "bob@example.com"},
+		{"autolink", "<https://example.com>", "https://example.com"},
+		{"image", "![logo](cid:image001.png)", "[logo]"},
+		{"emphasis", "**bold** and *italic* and _also_", "bold and italic and also"},
+		{"escapes", `5 \* 3 \_not italic\_`, "5 * 3 _not italic_"},
+		{"code span", "run `go365 *login*`", "run go365 *login*"},
+		{"quote", "> quoted\n> text", "│ quoted text"},
+		{"rule", "---", strings.Repeat("─", 40)},
+		{"code block", "```\nx := 1\n```", "    x := 1"},
+		{"blank runs", "a\n\n\n\nb\n\n", "a\n\nb"},
+	}
+
+	for _, tt := range tests {
+		if got := RenderMarkdown(tt.in, 80); got != tt.want {
+			t.Errorf("%s: RenderMarkdown(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMarkdownWraps(t *testing.T) {
+	SetColor(true)
+	defer SetColor(false)
+
+	md := "- " + strings.Repeat("**word** ", 30) + "\n\n" + strings.Repeat("plain ", 30)
+	got := RenderMarkdown(md, 40)
+
+	lines := strings.Split(got, "\n")
+	if len(lines) < 4 {
+		t.Fatalf("Expected wrapped output, got %q", got)
+	}
+	for _, line := range lines {
+		if w := ansi.StringWidth(line); w > 40 {
+			t.Errorf("Line wider than 40 columns (%d): %q", w, line)
+		}
+	}
+	if !strings.HasPrefix(lines[0], "• ") || !strings.HasPrefix(lines[1], "  ") {
+		t.Errorf("Expected a hanging indent for the list item, got %q", lines[:2])
+	}
+	if !strings.Contains(got, ansiBold+"word"+ansiReset) {
+		t.Errorf("Expected bold styling, got %q", got)
+	}
+}
+
+func TestRenderMarkdownStyles(t *testing.T) {
+	SetColor(true)
+	defer SetColor(false)
+
+	got := RenderMarkdown("## Title\n\nSee [docs](https://example.com)", 80)
+	want := ansiBold + "Title" + ansiReset + "\n\nSee " + ansiUnder + "docs" + ansiReset + " " + ansiDim + "<https://example.com>" + ansiReset
+	if got != want {
+		t.Errorf("RenderMarkdown = %q, want %q", got, want)
+	}
+}
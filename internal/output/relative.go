@@ -0,0 +1,48 @@
+package output
+
+import (
+	"fmt"
+	"time"
+)
+
+// relativeLimit is how far from now a time can be and still read well as
+// "3d ago"; beyond it callers should show the date
+const relativeLimit = 7 * 24 * time.Hour
+
+// RelativeTime describes t relative to now, e.g. "2h ago", "in 35m" or
+// "just now". It returns "" when t is a week or more away.
+func RelativeTime(t, now time.Time) string {
+	d := t.Sub(now)
+	future := d > 0
+	if !future {
+		d = -d
+	}
+	if d >= relativeLimit {
+		return ""
+	}
+	if d < time.Minute {
+		return "just now"
+	}
+
+	var span string
+	d = d.Round(time.Minute)
+	switch {
+	case d < time.Hour:
+		span = fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 2*time.Hour:
+		// "1h" would hide nearly an hour, so keep the minutes
+		span = fmt.Sprintf("%dh", int(d/time.Hour))
+		if m := int(d % time.Hour / time.Minute); m > 0 {
+			span += fmt.Sprintf(" %dm", m)
+		}
+	case d.Round(time.Hour) < 24*time.Hour:
+		span = fmt.Sprintf("%dh", int(d.Round(time.Hour)/time.Hour))
+	default:
+		span = fmt.Sprintf("%dd", int(d.Round(24*time.Hour)/(24*time.Hour)))
+	}
+
+	if future {
+		return "in " + span
+	}
+	return span + " ago"
+}
@@ -0,0 +1,35 @@
+package output
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2026, 1, 21, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		offset time.Duration
+		want   string
+	}{
+		{0, "just now"},
+		{-30 * time.Second, "just now"},
+		{-5 * time.Minute, "5m ago"},
+		{35 * time.Minute, "in 35m"},
+		{59*time.Minute + 40*time.Second, "in 1h"},
+		{-80 * time.Minute, "1h 20m ago"},
+		{-2 * time.Hour, "2h ago"},
+		{5*time.Hour + 40*time.Minute, "in 6h"},
+		{-23*time.Hour - 50*time.Minute, "1d ago"},
+		{-3 * 24 * time.Hour, "3d ago"},
+		{6*24*time.Hour + 23*time.Hour, "in 7d"},
+		{7 * 24 * time.Hour, ""},
+		{-30 * 24 * time.Hour, ""},
+	}
+
+	for _, tt := range tests {
+		if got := RelativeTime(now.Add(tt.offset), now); got != tt.want {
+			t.Errorf("RelativeTime(now%+v) = %q, want %q", tt.offset, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// maxCellWidth is the maximum number of characters shown in a table cell.
+const maxCellWidth = 60
+
+// Column describes a table column: the name used with --columns and a dotted
+// path into the item's JSON representation (e.g. "from.emailAddress.address").
+type Column struct {
+	Name string
+	Path string
+}
+
+// TableSpec describes the columns available for a resource and which of them
+// are shown by default.
+type TableSpec struct {
+	Columns  []Column
+	Defaults []string
+}
+
+// SelectColumns resolves column names against the spec. Names the spec does not
+// know are used as JSON paths directly. With no names, the defaults are returned.
+func (s *TableSpec) SelectColumns(names []string) []Column {
+	if len(names) == 0 && s != nil {
+		names = s.Defaults
+	}
+	if len(names) == 0 {
+		names = []string{"id"}
+	}
+
+	columns := make([]Column, 0, len(names))
+	for _, name := range names {
+		col := Column{Name: name, Path: name}
+		if s != nil {
+			for _, c := range s.Columns {
+				if strings.EqualFold(c.Name, name) {
+					col = c
+					break
+				}
+			}
+		}
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// Properties returns the top-level properties the columns read, with id
+// first, for requesting only those fields ($select) from Graph.
+func Properties(columns []Column) []string {
+	props := []string{"id"}
+	for _, col := range columns {
+		prop, _, _ := strings.Cut(col.Path, ".")
+		if prop != "" && !slices.Contains(props, prop) {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
+
+// ParseColumns splits a comma-separated --columns value into column names.
+func ParseColumns(spec string) []string {
+	var names []string
+	for _, name := range strings.Split(spec, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// WriteTable writes items (a slice, or a single value) as an aligned table
+// with a header row.
+func WriteTable(w io.Writer, items any, columns []Column) error {
+	rows, err := toRows(items)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = strings.ToUpper(col.Name)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			cells[i] = truncateCell(formatCell(lookupPath(row, strings.Split(col.Path, "."))))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// WriteIDs writes the id of each item (a slice, or a single value) on its own
+// line, for --quiet output. Items without an id are skipped.
+func WriteIDs(w io.Writer, items any) error {
+	rows, err := toRows(items)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if id := formatCell(lookupPath(row, []string{"id"})); id != "" {
+			if _, err := fmt.Fprintln(w, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteField writes the value at a dotted path (as accepted by --columns) for
+// each item, one per line, for --field output. A single item's string value
+// is written verbatim so multi-line fields survive; list entries are
+// flattened to one line each, with an empty line where the field is missing.
+func WriteField(w io.Writer, items any, path string) error {
+	rows, err := toRows(items)
+	if err != nil {
+		return err
+	}
+
+	segments := strings.Split(path, ".")
+	if reflect.ValueOf(items).Kind() != reflect.Slice {
+		var value any
+		if len(rows) > 0 {
+			value = lookupPath(rows[0], segments)
+		}
+		if value == nil {
+			return fmt.Errorf("field %q not found", path)
+		}
+		text, ok := value.(string)
+		if !ok {
+			text = formatCell(value)
+		}
+		_, err := fmt.Fprintln(w, text)
+		return err
+	}
+
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(w, formatCell(lookupPath(row, segments))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toRows converts items to their generic JSON form, one entry per row.
+func toRows(items any) ([]any, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode table rows: %w", err)
+	}
+
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to decode table rows: %w", err)
+	}
+
+	switch v := generic.(type) {
+	case []any:
+		return v, nil
+	case nil:
+		return nil, nil
+	default:
+		return []any{v}, nil
+	}
+}
+
+// lookupPath walks a dotted path through decoded JSON. When an array is reached
+// and the next segment is not an index, the rest of the path is applied to every
+// element.
+func lookupPath(v any, path []string) any {
+	if len(path) == 0 {
+		return v
+	}
+
+	switch node := v.(type) {
+	case map[string]any:
+		// Prefer the longest matching key so names containing dots
+		// (e.g. "@odata.type") can be addressed
+		for n := len(path); n > 0; n-- {
+			name := strings.Join(path[:n], ".")
+			for key, child := range node {
+				if strings.EqualFold(key, name) {
+					return lookupPath(child, path[n:])
+				}
+			}
+		}
+		return nil
+	case []any:
+		if i, err := strconv.Atoi(path[0]); err == nil {
+			if i < 0 || i >= len(node) {
+				return nil
+			}
+			return lookupPath(node[i], path[1:])
+		}
+		values := make([]any, 0, len(node))
+		for _, elem := range node {
+			if child := lookupPath(elem, path); child != nil {
+				values = append(values, child)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// formatCell renders a decoded JSON value as single-line cell text.
+func formatCell(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.Join(strings.Fields(val), " ")
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case []any:
+		parts := make([]string, 0, len(val))
+		for _, elem := range val {
+			parts = append(parts, formatCell(elem))
+		}
+		return strings.Join(parts, ", ")
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
+
+// truncateCell shortens cell text to maxCellWidth characters.
+func truncateCell(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxCellWidth {
+		return s
+	}
+	return string(runes[:maxCellWidth-1]) + "…"
+}
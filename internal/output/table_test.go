@@ -0,0 +1,173 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type tableTestMessage struct {
+	ID      string `json:"id"`
+	Subject string `json:"subject"`
+	From    struct {
+		EmailAddress struct {
+			Address string `json:"address"`
+		} `json:"emailAddress"`
+	} `json:"from"`
+	To []map[string]string `json:"toRecipients"`
+}
+
+var tableTestSpec = &TableSpec{
+	Columns: []Column{
+		{Name: "id", Path: "id"},
+		{Name: "subject", Path: "subject"},
+		{Name: "from", Path: "from.emailAddress.address"},
+		{Name: "to", Path: "toRecipients.address"},
+	},
+	Defaults: []string{"subject", "from"},
+}
+
+func TestSelectColumns(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string // paths
+	}{
+		{"defaults", nil, []string{"subject", "from.emailAddress.address"}},
+		{"alias", []string{"FROM", "id"}, []string{"from.emailAddress.address", "id"}},
+		{"raw path", []string{"body.contentType"}, []string{"body.contentType"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns := tableTestSpec.SelectColumns(tt.names)
+			if len(columns) != len(tt.want) {
+				t.Fatalf("Expected %d columns, got %d", len(tt.want), len(columns))
+			}
+			for i, col := range columns {
+				if col.Path != tt.want[i] {
+					t.Errorf("Column %d: expected path %s, got %s", i, tt.want[i], col.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestProperties(t *testing.T) {
+	columns := []Column{
+		{Name: "received", Path: "receivedDateTime"},
+		{Name: "from", Path: "from.emailAddress.address"},
+		{Name: "name", Path: "from.emailAddress.name"},
+		{Name: "id", Path: "id"},
+	}
+	got := Properties(columns)
+	if strings.Join(got, "|") != "id|receivedDateTime|from" {
+		t.Errorf("Unexpected properties: %v", got)
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	got := ParseColumns(" subject, from,,received ")
+	if strings.Join(got, "|") != "subject|from|received" {
+		t.Errorf("Unexpected columns: %v", got)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	msg := tableTestMessage{ID: "1", Subject: "Quarterly\nreport"}
+	msg.From.EmailAddress.Address = "jane@example.com"
+	msg.To = []map[string]string{{"address": "a@example.com"}, {"address": "b@example.com"}}
+	other := tableTestMessage{ID: "2", Subject: strings.Repeat("x", 100)}
+
+	var buf bytes.Buffer
+	columns := tableTestSpec.SelectColumns([]string{"id", "subject", "from", "to"})
+	if err := WriteTable(&buf, []tableTestMessage{msg, other}, columns); err != nil {
+		t.Fatalf("WriteTable failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[0], "SUBJECT") {
+		t.Errorf("Unexpected header: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "Quarterly report") {
+		t.Errorf("Expected newline collapsed in subject: %s", lines[1])
+	}
+	if !strings.Contains(lines[1], "a@example.com, b@example.com") {
+		t.Errorf("Expected array values joined: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], strings.Repeat("x", maxCellWidth-1)+"…") {
+		t.Errorf("Expected long subject truncated: %s", lines[2])
+	}
+
+	// Columns should line up
+	if strings.Index(lines[0], "SUBJECT") != strings.Index(lines[1], "Quarterly") {
+		t.Errorf("Columns not aligned:\n%s", buf.String())
+	}
+}
+
+func TestWriteTableSingleItem(t *testing.T) {
+	var buf bytes.Buffer
+	item := map[string]any{"id": "x", "size": 1024, "@odata.type": "#microsoft.graph.user"}
+	err := WriteTable(&buf, item, []Column{{Name: "id", Path: "id"}, {Name: "size", Path: "size"}, {Name: "type", Path: "@odata.type"}})
+	if err != nil {
+		t.Fatalf("WriteTable failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "1024") || !strings.Contains(buf.String(), "#microsoft.graph.user") {
+		t.Errorf("Expected single row with dotted key, got %s", buf.String())
+	}
+}
+
+func TestWriteIDs(t *testing.T) {
+	var buf bytes.Buffer
+	items := []map[string]any{{"id": "a", "subject": "x"}, {"subject": "no id"}, {"id": "b"}}
+	if err := WriteIDs(&buf, items); err != nil {
+		t.Fatalf("WriteIDs failed: %v", err)
+	}
+	if buf.String() != "a\nb\n" {
+		t.Errorf("Expected one ID per line, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteIDs(&buf, map[string]string{"id": "single"}); err != nil {
+		t.Fatalf("WriteIDs failed: %v", err)
+	}
+	if buf.String() != "single\n" {
+		t.Errorf("Expected single ID, got %q", buf.String())
+	}
+}
+
+func TestWriteField(t *testing.T) {
+	var buf bytes.Buffer
+	items := []map[string]any{
+		{"subject": "first", "onlineMeeting": map[string]any{"joinUrl": "https://teams/1"}},
+		{"subject": "no meeting"},
+		{"subject": "third", "onlineMeeting": map[string]any{"joinUrl": "https://teams/3"}},
+	}
+	if err := WriteField(&buf, items, "onlineMeeting.joinUrl"); err != nil {
+		t.Fatalf("WriteField failed: %v", err)
+	}
+	if buf.String() != "https://teams/1\n\nhttps://teams/3\n" {
+		t.Errorf("Expected one value per item, got %q", buf.String())
+	}
+
+	buf.Reset()
+	single := map[string]any{"body": map[string]any{"content": "line one\nline two"}, "isRead": true}
+	if err := WriteField(&buf, single, "body.content"); err != nil {
+		t.Fatalf("WriteField failed: %v", err)
+	}
+	if buf.String() != "line one\nline two\n" {
+		t.Errorf("Expected verbatim string, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteField(&buf, single, "isread"); err != nil || buf.String() != "true\n" {
+		t.Errorf("Expected case-insensitive bool field, got %q, %v", buf.String(), err)
+	}
+
+	if err := WriteField(&buf, single, "onlineMeeting.joinUrl"); err == nil {
+		t.Error("Expected an error for a missing field on a single item")
+	}
+}
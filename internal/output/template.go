@@ -0,0 +1,76 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are the helper functions available in --format templates.
+var templateFuncs = template.FuncMap{
+	"join": func(sep string, v any) string {
+		list, ok := v.([]any)
+		if !ok {
+			return formatCell(v)
+		}
+		parts := make([]string, 0, len(list))
+		for _, elem := range list {
+			parts = append(parts, formatCell(elem))
+		}
+		return strings.Join(parts, sep)
+	},
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"truncate": func(n int, v any) string {
+		s := []rune(formatCell(v))
+		if n <= 0 || len(s) <= n {
+			return string(s)
+		}
+		return string(s[:n-1]) + "…"
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// Template renders items with a Go text/template. Field names follow the JSON
+// output, e.g. {{.subject}} or {{.from.emailAddress.address}}.
+type Template struct {
+	tmpl *template.Template
+}
+
+// ParseTemplate parses a --format template.
+func ParseTemplate(text string) (*Template, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Execute renders the template once per item (or once for a single value),
+// ending each rendering with a newline.
+func (t *Template) Execute(w io.Writer, items any) error {
+	rows, err := toRows(items)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var buf bytes.Buffer
+		if err := t.tmpl.Execute(&buf, row); err != nil {
+			return fmt.Errorf("failed to render format template: %w", err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
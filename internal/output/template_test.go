@@ -0,0 +1,60 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTemplateExecute(t *testing.T) {
+	items := []map[string]any{
+		{"subject": "Hello", "from": map[string]any{"emailAddress": map[string]any{"address": "jane@example.com"}}, "tags": []string{"a", "b"}},
+		{"subject": "A very long subject line", "from": map[string]any{"emailAddress": map[string]any{"address": "bob@example.com"}}},
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{"fields", "{{.subject}} ({{.from.emailAddress.address}})", "Hello (jane@example.com)\nA very long subject line (bob@example.com)\n"},
+		{"truncate", "{{truncate 6 .subject}}", "Hello\nA ver…\n"},
+		{"join", `{{join ";" .tags}}`, "a;b\n\n"},
+		{"trailing newline kept", "{{.subject}}\n", "Hello\nA very long subject line\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.format)
+			if err != nil {
+				t.Fatalf("ParseTemplate failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, items); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestTemplateSingleItem(t *testing.T) {
+	tmpl, err := ParseTemplate("{{.name}}: {{json .size}}")
+	if err != nil {
+		t.Fatalf("ParseTemplate failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]any{"name": "report.pdf", "size": 2048}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if buf.String() != "report.pdf: 2048\n" {
+		t.Errorf("Unexpected output: %q", buf.String())
+	}
+}
+
+func TestParseTemplateInvalid(t *testing.T) {
+	if _, err := ParseTemplate("{{.subject"); err == nil {
+		t.Error("Expected error for unterminated action")
+	}
+}
@@ -0,0 +1,48 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteYAML writes a value as YAML to the writer. The value is encoded to JSON
+// first so field names and omitted fields match the JSON output exactly.
+func WriteYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+
+	// JSON is valid YAML; decoding into a node keeps the field order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	resetStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+// resetStyle clears the flow and quoting styles inherited from the JSON source
+// so the node is written in block style. Strings are re-encoded so values such
+// as "yes" or "123" stay quoted and are not read back as booleans or numbers.
+func resetStyle(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		value := node.Value
+		node.Encode(value)
+		return
+	}
+
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
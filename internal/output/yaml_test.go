@@ -0,0 +1,48 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteYAML(t *testing.T) {
+	type item struct {
+		Subject string   `json:"subject"`
+		Tags    []string `json:"tags,omitempty"`
+		Skipped string   `json:"skipped,omitempty"`
+		Count   int      `json:"count"`
+		Note    string   `json:"note"`
+	}
+
+	var buf bytes.Buffer
+	resp := FormatListResponse([]item{{Subject: "Hello: world", Tags: []string{"a", "b"}, Count: 2, Note: "yes"}}, 1, "")
+	if err := WriteYAML(&buf, resp); err != nil {
+		t.Fatalf("WriteYAML failed: %v", err)
+	}
+
+	want := `value:
+  - subject: 'Hello: world'
+    tags:
+      - a
+      - b
+    count: 2
+    note: "yes"
+'@odata.count': 1
+hasMore: false
+`
+	if buf.String() != want {
+		t.Errorf("Unexpected YAML:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteYAMLKeepsStringTypes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteYAML(&buf, map[string]any{"id": "123", "flag": "true", "size": 123}); err != nil {
+		t.Fatalf("WriteYAML failed: %v", err)
+	}
+
+	want := "flag: \"true\"\nid: \"123\"\nsize: 123\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected YAML:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,122 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// DescribeFlag is the argument plugins are run with to advertise their commands
+const DescribeFlag = "--go365-describe"
+
+// describeTimeout bounds how long a plugin may take to describe itself
+const describeTimeout = 2 * time.Second
+
+// Flag is a flag a plugin accepts
+type Flag struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Usage     string `json:"usage,omitempty"`
+	Type      string `json:"type,omitempty"` // "bool" for switches; anything else takes a value
+}
+
+// Command is a plugin subcommand
+type Command struct {
+	Name  string `json:"name"`
+	Short string `json:"short,omitempty"`
+	Flags []Flag `json:"flags,omitempty"`
+}
+
+// Description is what a plugin prints in response to DescribeFlag
+type Description struct {
+	Name     string    `json:"name"`
+	Short    string    `json:"short,omitempty"`
+	Version  string    `json:"version,omitempty"`
+	Commands []Command `json:"commands,omitempty"`
+	Flags    []Flag    `json:"flags,omitempty"`
+
+	// Completion is true when the plugin answers cobra's __complete protocol,
+	// so go365 forwards completion requests to it
+	Completion bool `json:"completion,omitempty"`
+}
+
+// Describe runs a plugin with DescribeFlag and parses its description. Plugins
+// that don't support it return an error. The plugin gets no session context
+// and no stdin.
+func Describe(ctx context.Context, path string) (*Description, error) {
+	ctx, cancel := context.WithTimeout(ctx, describeTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, DescribeFlag)
+	cmd.Stdout = &stdout
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to describe %s: %w", path, err)
+	}
+
+	var desc Description
+	if err := json.Unmarshal(stdout.Bytes(), &desc); err != nil {
+		return nil, fmt.Errorf("%s does not support %s", path, DescribeFlag)
+	}
+	return &desc, nil
+}
+
+// describeCacheEntry remembers a description for one build of a plugin. A nil
+// Description records that the plugin doesn't describe itself.
+type describeCacheEntry struct {
+	ModTime     time.Time    `json:"modTime"`
+	Size        int64        `json:"size"`
+	Description *Description `json:"description,omitempty"`
+}
+
+// DescribeCache keeps plugin descriptions in a file so each plugin binary is
+// only run with DescribeFlag once
+type DescribeCache struct {
+	path    string
+	entries map[string]*describeCacheEntry
+	dirty   bool
+}
+
+// LoadDescribeCache reads the cache at path; a missing or corrupt file starts
+// an empty cache
+func LoadDescribeCache(path string) *DescribeCache {
+	c := &DescribeCache{path: path, entries: map[string]*describeCacheEntry{}}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &c.entries)
+	}
+	return c
+}
+
+// Describe returns the plugin's description, running it only if the binary
+// changed since it was cached. It returns nil for plugins without one.
+func (c *DescribeCache) Describe(ctx context.Context, path string) *Description {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if entry, ok := c.entries[path]; ok && entry.ModTime.Equal(info.ModTime()) && entry.Size == info.Size() {
+		return entry.Description
+	}
+
+	desc, _ := Describe(ctx, path)
+	c.entries[path] = &describeCacheEntry{ModTime: info.ModTime(), Size: info.Size(), Description: desc}
+	c.dirty = true
+	return desc
+}
+
+// Save writes the cache if anything changed
+func (c *DescribeCache) Save() error {
+	if !c.dirty {
+		return nil
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode plugin descriptions: %w", err)
+	}
+	return os.WriteFile(c.path, data, 0600)
+}
@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	described := filepath.Join(tmpDir, "go365-described")
+	script := "#!/bin/sh\n[ \"$1\" = \"--go365-describe\" ] && echo '{\"name\":\"described\",\"short\":\"Does things\",\"commands\":[{\"name\":\"list\",\"flags\":[{\"name\":\"all\",\"type\":\"bool\"}]}]}'\n"
+	if err := os.WriteFile(described, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create test plugin: %v", err)
+	}
+
+	legacy := filepath.Join(tmpDir, "go365-legacy")
+	if err := os.WriteFile(legacy, []byte("#!/bin/sh\necho hello\n"), 0755); err != nil {
+		t.Fatalf("Failed to create test plugin: %v", err)
+	}
+
+	desc, err := Describe(context.Background(), described)
+	if err != nil {
+		t.Fatalf("Describe failed: %v", err)
+	}
+	if desc.Short != "Does things" || len(desc.Commands) != 1 || desc.Commands[0].Flags[0].Type != "bool" {
+		t.Errorf("Unexpected description: %+v", desc)
+	}
+
+	if _, err := Describe(context.Background(), legacy); err == nil || !strings.Contains(err.Error(), DescribeFlag) {
+		t.Errorf("Expected unsupported error for legacy plugin, got %v", err)
+	}
+}
+
+func TestDescribeCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	counter := filepath.Join(tmpDir, "runs")
+
+	// The plugin appends to a file each time it is run
+	path := filepath.Join(tmpDir, "go365-counted")
+	script := "#!/bin/sh\necho run >> " + counter + "\necho '{\"name\":\"counted\"}'\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create test plugin: %v", err)
+	}
+
+	cachePath := filepath.Join(tmpDir, "describe.json")
+	cache := LoadDescribeCache(cachePath)
+	if desc := cache.Describe(context.Background(), path); desc == nil || desc.Name != "counted" {
+		t.Fatalf("Unexpected description: %+v", desc)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// A fresh cache from disk must not run the plugin again
+	if desc := LoadDescribeCache(cachePath).Describe(context.Background(), path); desc == nil {
+		t.Fatal("Expected cached description")
+	}
+	runs, _ := os.ReadFile(counter)
+	if n := strings.Count(string(runs), "run"); n != 1 {
+		t.Errorf("Expected plugin to run once, ran %d times", n)
+	}
+}
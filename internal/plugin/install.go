@@ -0,0 +1,311 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/njt/go365/internal/release"
+)
+
+// ManifestFile records the plugins installed into InstallDir
+const ManifestFile = "manifest.json"
+
+// GitHubAPIURL is the API used to resolve owner/repo release sources
+var GitHubAPIURL = "https://api.github.com"
+
+// InstalledPlugin is a manifest entry for a plugin installed by go365
+type InstalledPlugin struct {
+	Name        string    `json:"name"`
+	Source      string    `json:"source"`            // owner/repo or download URL
+	Version     string    `json:"version,omitempty"` // Release tag, empty for URL installs
+	Asset       string    `json:"asset"`
+	SHA256      string    `json:"sha256"`
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+// Installer downloads release binaries into a plugin directory
+type Installer struct {
+	Dir        string
+	HTTPClient *http.Client
+}
+
+// NewInstaller creates an installer for dir
+func NewInstaller(dir string) *Installer {
+	return &Installer{Dir: dir, HTTPClient: http.DefaultClient}
+}
+
+// LoadManifest reads the installed plugins, keyed by name
+func (in *Installer) LoadManifest() (map[string]*InstalledPlugin, error) {
+	manifest := map[string]*InstalledPlugin{}
+	data, err := os.ReadFile(filepath.Join(in.Dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func (in *Installer) saveManifest(manifest map[string]*InstalledPlugin) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plugin manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(in.Dir, ManifestFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write plugin manifest: %w", err)
+	}
+	return nil
+}
+
+// Installed returns the installed plugins sorted by name
+func (in *Installer) Installed() ([]*InstalledPlugin, error) {
+	manifest, err := in.LoadManifest()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*InstalledPlugin, 0, len(manifest))
+	for _, p := range manifest {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// InstallOptions controls a single install
+type InstallOptions struct {
+	Name    string // Plugin name; derived from the source when empty
+	Version string // Release tag; latest when empty
+	SHA256  string // Expected checksum; required for URLs without a published .sha256
+}
+
+// resolved is a download ready to fetch and verify
+type resolved struct {
+	name, version, asset, url, sha256 string
+}
+
+// Install downloads a plugin from a GitHub repository (owner/repo or its URL)
+// or a direct download URL, verifies its checksum, and records it
+func (in *Installer) Install(ctx context.Context, source string, opts InstallOptions) (*InstalledPlugin, error) {
+	res, err := in.resolve(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := release.Fetch(ctx, in.HTTPClient, res.url)
+	if err != nil {
+		return nil, err
+	}
+	if err := release.VerifyChecksum(res.asset, data, res.sha256); err != nil {
+		return nil, err
+	}
+
+	binary, err := release.ExtractBinary(res.asset, data, binaryName(res.name))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(in.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create plugin directory: %w", err)
+	}
+	target := filepath.Join(in.Dir, binaryName(res.name))
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, binary, 0755); err != nil {
+		return nil, fmt.Errorf("failed to write plugin: %w", err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to install plugin: %w", err)
+	}
+
+	manifest, err := in.LoadManifest()
+	if err != nil {
+		return nil, err
+	}
+	installed := &InstalledPlugin{
+		Name:        res.name,
+		Source:      source,
+		Version:     res.version,
+		Asset:       res.asset,
+		SHA256:      strings.ToLower(res.sha256),
+		InstalledAt: time.Now().UTC(),
+	}
+	manifest[res.name] = installed
+	if err := in.saveManifest(manifest); err != nil {
+		return nil, err
+	}
+	return installed, nil
+}
+
+// Upgrade reinstalls a plugin from its recorded source if a newer release (or,
+// for URL installs, different content) is available. It reports whether the
+// plugin changed.
+func (in *Installer) Upgrade(ctx context.Context, name string) (*InstalledPlugin, bool, error) {
+	manifest, err := in.LoadManifest()
+	if err != nil {
+		return nil, false, err
+	}
+	current, ok := manifest[name]
+	if !ok {
+		return nil, false, fmt.Errorf("plugin '%s' was not installed with go365 plugins install", name)
+	}
+
+	res, err := in.resolve(ctx, current.Source, InstallOptions{Name: name})
+	if err != nil {
+		return nil, false, err
+	}
+	if res.version == current.Version && strings.EqualFold(res.sha256, current.SHA256) {
+		return current, false, nil
+	}
+
+	updated, err := in.Install(ctx, current.Source, InstallOptions{Name: name, Version: res.version, SHA256: res.sha256})
+	if err != nil {
+		return nil, false, err
+	}
+	return updated, true, nil
+}
+
+// Remove deletes an installed plugin and its manifest entry
+func (in *Installer) Remove(name string) error {
+	manifest, err := in.LoadManifest()
+	if err != nil {
+		return err
+	}
+	if _, ok := manifest[name]; !ok {
+		return fmt.Errorf("plugin '%s' was not installed with go365 plugins install", name)
+	}
+
+	if err := os.Remove(filepath.Join(in.Dir, binaryName(name))); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove plugin: %w", err)
+	}
+	delete(manifest, name)
+	return in.saveManifest(manifest)
+}
+
+// resolve works out what to download for a source and the checksum to expect
+func (in *Installer) resolve(ctx context.Context, source string, opts InstallOptions) (*resolved, error) {
+	if repo, ok := githubRepo(source); ok {
+		return in.resolveRelease(ctx, repo, opts)
+	}
+	if strings.HasPrefix(source, "http://") {
+		// The checksum comes from the same host, so it would prove nothing
+		return nil, fmt.Errorf("plugin source %q must use https://", source)
+	}
+	if !strings.HasPrefix(source, "https://") {
+		return nil, fmt.Errorf("unsupported plugin source %q (use owner/repo or an https:// download URL)", source)
+	}
+
+	res := &resolved{url: source, asset: path.Base(source), name: opts.Name, sha256: opts.SHA256}
+	if res.name == "" {
+		res.name = nameFromAsset(res.asset)
+	}
+	if res.sha256 == "" {
+		sumFile, err := release.Fetch(ctx, in.HTTPClient, source+".sha256")
+		if err != nil {
+			return nil, fmt.Errorf("no checksum published at %s.sha256; pass --sha256", source)
+		}
+		fields := strings.Fields(string(sumFile))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("empty checksum file at %s.sha256", source)
+		}
+		res.sha256 = fields[0]
+	}
+	return res, nil
+}
+
+// resolveRelease picks the asset for this platform from a GitHub release and
+// finds its checksum in the release's checksums file
+func (in *Installer) resolveRelease(ctx context.Context, repo string, opts InstallOptions) (*resolved, error) {
+	rel, err := release.Get(ctx, in.HTTPClient, GitHubAPIURL, repo, opts.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find release for %s: %w", repo, err)
+	}
+
+	res := &resolved{name: opts.Name, version: rel.TagName}
+	if res.name == "" {
+		res.name = strings.TrimPrefix(path.Base(repo), "go365-")
+	}
+
+	var checksumsURL string
+	sidecars := map[string]string{}
+	for _, a := range rel.Assets {
+		lower := strings.ToLower(a.Name)
+		switch {
+		case strings.HasSuffix(lower, ".sha256"):
+			sidecars[strings.TrimSuffix(a.Name, path.Ext(a.Name))] = a.URL
+		case strings.Contains(lower, "checksums") || lower == "sha256sums":
+			checksumsURL = a.URL
+		case res.url == "" && release.MatchesPlatform(lower):
+			res.asset, res.url = a.Name, a.URL
+		}
+	}
+	if res.url == "" {
+		return nil, fmt.Errorf("release %s of %s has no asset for %s/%s", rel.TagName, repo, runtime.GOOS, runtime.GOARCH)
+	}
+
+	if opts.SHA256 != "" {
+		res.sha256 = opts.SHA256
+		return res, nil
+	}
+	if url, ok := sidecars[res.asset]; ok {
+		sumFile, err := release.Fetch(ctx, in.HTTPClient, url)
+		if err != nil {
+			return nil, err
+		}
+		if fields := strings.Fields(string(sumFile)); len(fields) > 0 {
+			res.sha256 = fields[0]
+			return res, nil
+		}
+	}
+	if checksumsURL != "" {
+		sums, err := release.Fetch(ctx, in.HTTPClient, checksumsURL)
+		if err != nil {
+			return nil, err
+		}
+		if sum, ok := release.FindChecksum(sums, res.asset); ok {
+			res.sha256 = sum
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("release %s of %s publishes no checksum for %s; pass --sha256", rel.TagName, repo, res.asset)
+}
+
+// githubRepo recognizes owner/repo and https://github.com/owner/repo sources
+func githubRepo(source string) (string, bool) {
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(source, "https://github.com/"), ".git")
+	parts := strings.Split(strings.Trim(trimmed, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.Contains(parts[0], ":") {
+		return "", false
+	}
+	return parts[0] + "/" + parts[1], true
+}
+
+// nameFromAsset derives a plugin name from a file such as
+// go365-foo_linux_amd64.tar.gz
+func nameFromAsset(asset string) string {
+	name := strings.TrimPrefix(asset, "go365-")
+	if i := strings.IndexAny(name, "_."); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// binaryName is the executable file name for a plugin
+func binaryName(name string) string {
+	if runtime.GOOS == "windows" {
+		return "go365-" + name + ".exe"
+	}
+	return "go365-" + name
+}
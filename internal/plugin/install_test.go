@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/njt/go365/internal/release/releasetest"
+)
+
+// fakeRelease serves a GitHub-style release of go365-hello for this platform
+func fakeRelease(t *testing.T, tag string, binary []byte, checksum string) *httptest.Server {
+	t.Helper()
+
+	archive := releasetest.Asset{
+		Name: fmt.Sprintf("go365-hello_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH),
+		Data: releasetest.TarGz(binaryName("hello"), binary),
+	}
+	sums := releasetest.Checksums(archive)
+	if checksum != "" {
+		sums = []byte(fmt.Sprintf("%s  %s\n", checksum, archive.Name))
+	}
+	return releasetest.NewServer("example/go365-hello", tag,
+		archive,
+		releasetest.Asset{Name: "go365-hello_plan9_mips.tar.gz", Data: []byte("other")},
+		releasetest.Asset{Name: "checksums.txt", Data: sums})
+}
+
+func TestInstallUpgradeRemove(t *testing.T) {
+	server := fakeRelease(t, "v1.0.0", []byte("#!/bin/sh\necho hello\n"), "")
+	defer server.Close()
+
+	oldAPI := GitHubAPIURL
+	GitHubAPIURL = server.URL
+	defer func() { GitHubAPIURL = oldAPI }()
+
+	installer := NewInstaller(t.TempDir())
+	ctx := context.Background()
+
+	installed, err := installer.Install(ctx, "example/go365-hello", InstallOptions{})
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if installed.Name != "hello" || installed.Version != "v1.0.0" {
+		t.Errorf("Unexpected manifest entry: %+v", installed)
+	}
+
+	data, err := os.ReadFile(filepath.Join(installer.Dir, binaryName("hello")))
+	if err != nil {
+		t.Fatalf("Plugin binary not written: %v", err)
+	}
+	if !strings.Contains(string(data), "echo hello") {
+		t.Errorf("Unexpected plugin contents: %q", data)
+	}
+
+	// Same release: nothing to do
+	if _, changed, err := installer.Upgrade(ctx, "hello"); err != nil || changed {
+		t.Errorf("Expected no upgrade, got changed=%v err=%v", changed, err)
+	}
+
+	if err := installer.Remove("hello"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(installer.Dir, binaryName("hello"))); !os.IsNotExist(err) {
+		t.Error("Expected plugin binary to be removed")
+	}
+	manifest, _ := installer.LoadManifest()
+	if len(manifest) != 0 {
+		t.Errorf("Expected empty manifest, got %v", manifest)
+	}
+}
+
+func TestInstallChecksumMismatch(t *testing.T) {
+	server := fakeRelease(t, "v1.0.0", []byte("binary"), strings.Repeat("0", 64))
+	defer server.Close()
+
+	oldAPI := GitHubAPIURL
+	GitHubAPIURL = server.URL
+	defer func() { GitHubAPIURL = oldAPI }()
+
+	installer := NewInstaller(t.TempDir())
+	_, err := installer.Install(context.Background(), "example/go365-hello", InstallOptions{})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Expected checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(installer.Dir, binaryName("hello"))); !os.IsNotExist(err) {
+		t.Error("Plugin must not be installed when the checksum fails")
+	}
+}
+
+func TestInstallRequiresHTTPS(t *testing.T) {
+	installer := NewInstaller(t.TempDir())
+	_, err := installer.Install(context.Background(), "http://example.com/go365-foo_linux_amd64", InstallOptions{})
+	if err == nil || !strings.Contains(err.Error(), "must use https://") {
+		t.Fatalf("Expected http:// sources to be refused, got %v", err)
+	}
+}
+
+func TestGitHubRepo(t *testing.T) {
+	tests := []struct {
+		source string
+		repo   string
+		ok     bool
+	}{
+		{"owner/go365-foo", "owner/go365-foo", true},
+		{"https://github.com/owner/go365-foo", "owner/go365-foo", true},
+		{"https://github.com/owner/go365-foo.git", "owner/go365-foo", true},
+		{"https://example.com/go365-foo_linux_amd64", "", false},
+		{"foo", "", false},
+	}
+	for _, tt := range tests {
+		repo, ok := githubRepo(tt.source)
+		if repo != tt.repo || ok != tt.ok {
+			t.Errorf("githubRepo(%q) = %q, %v; want %q, %v", tt.source, repo, ok, tt.repo, tt.ok)
+		}
+	}
+}
+
+func TestFindPluginInstallDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go365-installed"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+
+	oldDir := InstallDir
+	InstallDir = dir
+	defer func() { InstallDir = oldDir }()
+
+	if _, err := FindPlugin("installed"); err != nil {
+		t.Errorf("Expected plugin in InstallDir to be found: %v", err)
+	}
+}
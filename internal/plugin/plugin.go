@@ -2,14 +2,25 @@ package plugin
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
-// FindPlugin looks for a go365-* plugin in the PATH
+// InstallDir holds plugins installed by go365 plugins install. It is searched
+// before PATH; empty disables it.
+var InstallDir string
+
+// FindPlugin looks for a go365-* plugin in InstallDir, then the PATH
 func FindPlugin(name string) (string, error) {
 	pluginName := "go365-" + name
+	if InstallDir != "" {
+		if path, err := exec.LookPath(filepath.Join(InstallDir, pluginName)); err == nil {
+			return path, nil
+		}
+	}
 	path, err := exec.LookPath(pluginName)
 	if err != nil {
 		return "", fmt.Errorf("plugin '%s' not found in PATH", pluginName)
@@ -17,8 +28,24 @@ func FindPlugin(name string) (string, error) {
 	return path, nil
 }
 
+// ExecOptions carries the protocol v2 context handed to a plugin
+type ExecOptions struct {
+	// Env is added to the plugin's environment
+	Env []string
+
+	// Handshake, if set, is written to the plugin's stdin ahead of go365's own
+	// stdin. The plugin no longer sees a terminal on stdin in this mode.
+	Handshake []byte
+}
+
 // ExecutePlugin runs a go365-* plugin with the given arguments
 func ExecutePlugin(name string, args []string) error {
+	return ExecutePluginWithOptions(name, args, nil)
+}
+
+// ExecutePluginWithOptions runs a go365-* plugin, passing it the environment
+// and stdin handshake in opts
+func ExecutePluginWithOptions(name string, args []string, opts *ExecOptions) error {
 	pluginPath, err := FindPlugin(name)
 	if err != nil {
 		return err
@@ -29,17 +56,52 @@ func ExecutePlugin(name string, args []string) error {
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
-	return cmd.Run()
+	if opts == nil {
+		return cmd.Run()
+	}
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
+	if len(opts.Handshake) == 0 {
+		return cmd.Run()
+	}
+
+	// Feed stdin through a pipe we don't wait on, so a plugin that never reads
+	// past the handshake doesn't leave go365 blocked on the terminal
+	cmd.Stdin = nil
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open plugin stdin: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if _, err := stdin.Write(opts.Handshake); err != nil {
+		stdin.Close()
+		cmd.Wait()
+		return fmt.Errorf("failed to send plugin handshake: %w", err)
+	}
+	go func() {
+		io.Copy(stdin, os.Stdin)
+		stdin.Close()
+	}()
+
+	return cmd.Wait()
 }
 
-// ListPlugins returns a list of available go365-* plugins in PATH
+// ListPlugins returns a list of available go365-* plugins in InstallDir and PATH
 func ListPlugins() ([]string, error) {
-	pathEnv := os.Getenv("PATH")
-	if pathEnv == "" {
+	var paths []string
+	if InstallDir != "" {
+		paths = append(paths, InstallDir)
+	}
+	if pathEnv := os.Getenv("PATH"); pathEnv != "" {
+		paths = append(paths, strings.Split(pathEnv, string(os.PathListSeparator))...)
+	}
+	if len(paths) == 0 {
 		return nil, nil
 	}
 
-	paths := strings.Split(pathEnv, string(os.PathListSeparator))
 	plugins := make(map[string]bool)
 
 	for _, dir := range paths {
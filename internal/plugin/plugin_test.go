@@ -110,3 +110,35 @@ func TestListPluginsEmptyPath(t *testing.T) {
 		t.Errorf("Expected empty plugin list, got %d plugins", len(plugins))
 	}
 }
+
+func TestExecutePluginWithOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	outPath := filepath.Join(tmpDir, "out")
+
+	// The plugin records its environment and the first line of stdin
+	pluginPath := filepath.Join(tmpDir, "go365-ctxplugin")
+	script := "#!/bin/sh\nread line\necho \"$GO365_TEST_VALUE $line\" > " + outPath + "\n"
+	if err := os.WriteFile(pluginPath, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create test plugin: %v", err)
+	}
+
+	oldPath := os.Getenv("PATH")
+	os.Setenv("PATH", tmpDir+string(os.PathListSeparator)+oldPath)
+	defer os.Setenv("PATH", oldPath)
+
+	err := ExecutePluginWithOptions("ctxplugin", nil, &ExecOptions{
+		Env:       []string{"GO365_TEST_VALUE=from-env"},
+		Handshake: []byte("{\"protocol\":2}\n"),
+	})
+	if err != nil {
+		t.Fatalf("ExecutePluginWithOptions failed: %v", err)
+	}
+
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("Plugin did not run: %v", err)
+	}
+	if want := "from-env {\"protocol\":2}\n"; string(got) != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
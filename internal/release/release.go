@@ -0,0 +1,177 @@
+// Package release downloads GitHub release assets and unpacks the binaries
+// inside them, for the plugin installer and self-update.
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// MaxSize bounds downloads and the binaries extracted from them, so a bad or
+// hostile release can't fill memory
+const MaxSize = 200 << 20
+
+// Release is the part of a GitHub release the installers use
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Get looks up the latest release of repo (owner/name) through the GitHub API
+// at apiURL, or the one tagged version when given
+func Get(ctx context.Context, client *http.Client, apiURL, repo, version string) (*Release, error) {
+	endpoint := apiURL + "/repos/" + repo + "/releases/latest"
+	if version != "" {
+		endpoint = apiURL + "/repos/" + repo + "/releases/tags/" + version
+	}
+	data, err := Fetch(ctx, client, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var rel Release
+	if err := json.Unmarshal(data, &rel); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &rel, nil
+}
+
+// Fetch downloads url, failing if it is larger than MaxSize
+func Fetch(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download of %s failed with status %d", url, resp.StatusCode)
+	}
+	data, err := readLimited(resp.Body, url, MaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("download of %s failed: %w", url, err)
+	}
+	return data, nil
+}
+
+// readLimited reads r to the end, failing if it holds more than limit bytes
+func readLimited(r io.Reader, name string, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, limit)
+	}
+	return data, nil
+}
+
+// VerifyChecksum checks data against the hex SHA-256 want
+func VerifyChecksum(asset string, data []byte, want string) error {
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, want) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", asset, want, got)
+	}
+	return nil
+}
+
+// FindChecksum looks up asset in sha256sum-style output
+func FindChecksum(sums []byte, asset string) (string, bool) {
+	for _, line := range strings.Split(string(sums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset {
+			return fields[0], true
+		}
+	}
+	return "", false
+}
+
+// MatchesPlatform reports whether a lower-cased asset name targets this OS and
+// architecture
+func MatchesPlatform(name string) bool {
+	if !strings.Contains(name, runtime.GOOS) {
+		return false
+	}
+	arches := []string{runtime.GOARCH}
+	switch runtime.GOARCH {
+	case "amd64":
+		arches = append(arches, "x86_64")
+	case "arm64":
+		arches = append(arches, "aarch64")
+	}
+	for _, arch := range arches {
+		if strings.Contains(name, arch) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractBinary returns the executable named want from a downloaded asset,
+// which may be the binary itself or a .tar.gz/.zip containing it
+func ExtractBinary(asset string, data []byte, want string) ([]byte, error) {
+	lower := strings.ToLower(asset)
+
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", asset, err)
+		}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", asset, err)
+			}
+			if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == want {
+				return readLimited(tr, want, MaxSize)
+			}
+		}
+		return nil, fmt.Errorf("%s does not contain %s", asset, want)
+
+	case strings.HasSuffix(lower, ".zip"):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", asset, err)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) == want {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, fmt.Errorf("failed to read %s: %w", asset, err)
+				}
+				defer rc.Close()
+				return readLimited(rc, want, MaxSize)
+			}
+		}
+		return nil, fmt.Errorf("%s does not contain %s", asset, want)
+	}
+
+	return data, nil
+}
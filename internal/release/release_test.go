@@ -0,0 +1,94 @@
+package release
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/njt/go365/internal/release/releasetest"
+)
+
+func TestGetAndFetch(t *testing.T) {
+	server := releasetest.NewServer("example/tool", "v1.0.0", releasetest.Asset{Name: "tool_linux_amd64", Data: []byte("binary")})
+	defer server.Close()
+	ctx := context.Background()
+
+	rel, err := Get(ctx, http.DefaultClient, server.URL, "example/tool", "")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if rel.TagName != "v1.0.0" || len(rel.Assets) != 1 || rel.Assets[0].Name != "tool_linux_amd64" {
+		t.Fatalf("Unexpected release %+v", rel)
+	}
+
+	data, err := Fetch(ctx, http.DefaultClient, rel.Assets[0].URL)
+	if err != nil || string(data) != "binary" {
+		t.Errorf("Fetch = %q, %v", data, err)
+	}
+	if _, err := Fetch(ctx, http.DefaultClient, server.URL+"/download/missing"); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("Expected a 404 error, got %v", err)
+	}
+}
+
+func TestReadLimited(t *testing.T) {
+	if data, err := readLimited(strings.NewReader("12345"), "asset", 5); err != nil || string(data) != "12345" {
+		t.Errorf("readLimited at the limit = %q, %v", data, err)
+	}
+	if _, err := readLimited(strings.NewReader("123456"), "asset", 5); err == nil || !strings.Contains(err.Error(), "larger than 5 bytes") {
+		t.Errorf("Expected a size error, got %v", err)
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	f, _ := zw.Create("tool/tool.exe")
+	f.Write([]byte("from zip"))
+	zw.Close()
+
+	tests := []struct {
+		asset string
+		data  []byte
+		want  string
+	}{
+		{"tool_linux_amd64.tar.gz", releasetest.TarGz("dist/tool", []byte("from tar")), "from tar"},
+		{"tool_windows_amd64.zip", zipped.Bytes(), "from zip"},
+		{"tool_linux_amd64", []byte("plain"), "plain"},
+	}
+	for _, tt := range tests {
+		want := "tool"
+		if strings.HasSuffix(tt.asset, ".zip") {
+			want = "tool.exe"
+		}
+		got, err := ExtractBinary(tt.asset, tt.data, want)
+		if err != nil || string(got) != tt.want {
+			t.Errorf("ExtractBinary(%s) = %q, %v; want %q", tt.asset, got, err, tt.want)
+		}
+	}
+
+	if _, err := ExtractBinary("tool.tar.gz", releasetest.TarGz("other", nil), "tool"); err == nil || !strings.Contains(err.Error(), "does not contain tool") {
+		t.Errorf("Expected a missing binary error, got %v", err)
+	}
+}
+
+func TestChecksums(t *testing.T) {
+	asset := releasetest.Asset{Name: "tool.tar.gz", Data: []byte("archive")}
+	sums := append([]byte("0000  other.tar.gz\n"), releasetest.Checksums(asset)...)
+
+	want, ok := FindChecksum(sums, asset.Name)
+	if !ok || want != releasetest.Checksum(asset.Data) {
+		t.Fatalf("FindChecksum = %q, %v", want, ok)
+	}
+	if err := VerifyChecksum(asset.Name, asset.Data, strings.ToUpper(want)); err != nil {
+		t.Errorf("VerifyChecksum failed: %v", err)
+	}
+	if err := VerifyChecksum(asset.Name, []byte("tampered"), want); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+	if _, ok := FindChecksum(sums, "missing"); ok {
+		t.Error("Expected no checksum for a missing asset")
+	}
+}
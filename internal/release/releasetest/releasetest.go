@@ -0,0 +1,74 @@
+// Package releasetest serves fake GitHub releases for testing code that
+// installs from them.
+package releasetest
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// Asset is a file attached to a fake release
+type Asset struct {
+	Name string
+	Data []byte
+}
+
+// NewServer serves tag as the latest release of repo through a GitHub-style
+// API, with each asset downloadable from /download/<name>. Point the code
+// under test's API URL at the server, and close it when done.
+func NewServer(repo, tag string, assets ...Asset) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/"+repo+"/releases/latest" || r.URL.Path == "/repos/"+repo+"/releases/tags/"+tag {
+			var listed []string
+			for _, a := range assets {
+				listed = append(listed, fmt.Sprintf(`{"name":%q,"browser_download_url":"%s/download/%s"}`, a.Name, server.URL, a.Name))
+			}
+			fmt.Fprintf(w, `{"tag_name":%q,"assets":[%s]}`, tag, strings.Join(listed, ","))
+			return
+		}
+		for _, a := range assets {
+			if r.URL.Path == "/download/"+a.Name {
+				w.Write(a.Data)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+	return server
+}
+
+// TarGz archives one executable file as a .tar.gz
+func TarGz(name string, data []byte) []byte {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(data)), Typeflag: tar.TypeReg})
+	tw.Write(data)
+	tw.Close()
+	gz.Close()
+	return archive.Bytes()
+}
+
+// Checksum is the hex SHA-256 of data
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Checksums lists the SHA-256 of each asset in sha256sum format, as a
+// release's checksums.txt does
+func Checksums(assets ...Asset) []byte {
+	var sums bytes.Buffer
+	for _, a := range assets {
+		fmt.Fprintf(&sums, "%s  %s\n", Checksum(a.Data), a.Name)
+	}
+	return sums.Bytes()
+}
@@ -0,0 +1,268 @@
+// Package selfupdate checks GitHub releases for a newer go365 and replaces the
+// running binary with a verified download.
+package selfupdate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/njt/go365/internal/release"
+)
+
+// Repo is the GitHub repository releases are published to
+const Repo = "njt/go365"
+
+// ChecksumsAsset lists the SHA-256 of every release artifact, and
+// SignatureAsset is a base64 ed25519 signature of it
+const (
+	ChecksumsAsset = "checksums.txt"
+	SignatureAsset = "checksums.txt.sig"
+)
+
+// GitHubAPIURL is the API used to look up releases
+var GitHubAPIURL = "https://api.github.com"
+
+// PublicKey is the base64 ed25519 key release checksums are signed with. It is
+// set at build time with -ldflags; when empty, only checksums are verified.
+var PublicKey = ""
+
+// Release is a go365 release with the artifact for this platform
+type Release struct {
+	Version      string `json:"version"`
+	Asset        string `json:"asset,omitempty"`
+	URL          string `json:"url,omitempty"`
+	ChecksumsURL string `json:"-"`
+	SignatureURL string `json:"-"`
+}
+
+// Updater looks up and installs releases
+type Updater struct {
+	Repo       string
+	HTTPClient *http.Client
+}
+
+// New creates an updater for the go365 repository
+func New() *Updater {
+	return &Updater{Repo: Repo, HTTPClient: http.DefaultClient}
+}
+
+// Latest returns the newest release, or the one tagged version when given
+func (u *Updater) Latest(ctx context.Context, version string) (*Release, error) {
+	rel, err := release.Get(ctx, u.HTTPClient, GitHubAPIURL, u.Repo, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find release: %w", err)
+	}
+
+	result := &Release{Version: rel.TagName}
+	for _, a := range rel.Assets {
+		switch lower := strings.ToLower(a.Name); {
+		case a.Name == ChecksumsAsset:
+			result.ChecksumsURL = a.URL
+		case a.Name == SignatureAsset:
+			result.SignatureURL = a.URL
+		case result.URL == "" && strings.HasPrefix(lower, "go365") && release.MatchesPlatform(lower) &&
+			!strings.HasSuffix(lower, ".sha256") && !strings.HasSuffix(lower, ".sig"):
+			result.Asset, result.URL = a.Name, a.URL
+		}
+	}
+	return result, nil
+}
+
+// Download fetches the release artifact, verifies it against the signed
+// checksums, and returns the go365 executable inside it
+func (u *Updater) Download(ctx context.Context, rel *Release) ([]byte, error) {
+	if rel.URL == "" {
+		return nil, fmt.Errorf("release %s has no build for %s/%s", rel.Version, runtime.GOOS, runtime.GOARCH)
+	}
+	if rel.ChecksumsURL == "" {
+		return nil, fmt.Errorf("release %s publishes no %s", rel.Version, ChecksumsAsset)
+	}
+
+	sums, err := release.Fetch(ctx, u.HTTPClient, rel.ChecksumsURL)
+	if err != nil {
+		return nil, err
+	}
+	if PublicKey != "" {
+		if rel.SignatureURL == "" {
+			return nil, fmt.Errorf("release %s is not signed", rel.Version)
+		}
+		sig, err := release.Fetch(ctx, u.HTTPClient, rel.SignatureURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := VerifySignature(PublicKey, sums, sig); err != nil {
+			return nil, fmt.Errorf("release %s: %w", rel.Version, err)
+		}
+	}
+
+	want, ok := release.FindChecksum(sums, rel.Asset)
+	if !ok {
+		return nil, fmt.Errorf("%s has no checksum for %s", ChecksumsAsset, rel.Asset)
+	}
+
+	data, err := release.Fetch(ctx, u.HTTPClient, rel.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err := release.VerifyChecksum(rel.Asset, data, want); err != nil {
+		return nil, err
+	}
+
+	return release.ExtractBinary(rel.Asset, data, binaryName())
+}
+
+// VerifySignature checks a base64 ed25519 signature of data against a base64
+// public key
+func VerifySignature(publicKey string, data, signature []byte) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("malformed checksum signature")
+	}
+	if !ed25519.Verify(key, data, sig) {
+		return fmt.Errorf("checksum signature does not match")
+	}
+	return nil
+}
+
+// binaryName is the executable file name inside release archives
+func binaryName() string {
+	if runtime.GOOS == "windows" {
+		return "go365.exe"
+	}
+	return "go365"
+}
+
+// Replace swaps the executable at exe for binary. The new file is written next
+// to it and renamed over it, so a failed upgrade leaves the old binary intact.
+func Replace(exe string, binary []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", exe, err)
+	}
+
+	tmp := exe + ".new"
+	if err := os.WriteFile(tmp, binary, info.Mode().Perm()|0100); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+
+	// Windows can't overwrite a running executable, but it can rename it
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			os.Remove(tmp)
+			return fmt.Errorf("failed to move aside %s: %w", exe, err)
+		}
+	}
+
+	if err := os.Rename(tmp, exe); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+	return nil
+}
+
+// managedPrefixes are install locations owned by package managers
+var managedPrefixes = []struct {
+	prefix, manager string
+}{
+	{"/usr/bin/", "the system package manager"},
+	{"/usr/sbin/", "the system package manager"},
+	{"/bin/", "the system package manager"},
+	{"/nix/store/", "Nix"},
+	{"/snap/", "Snap"},
+	{"/usr/local/Cellar/", "Homebrew"},
+	{"/opt/homebrew/", "Homebrew"},
+	{"/home/linuxbrew/.linuxbrew/", "Homebrew"},
+	{"/var/lib/flatpak/", "Flatpak"},
+}
+
+// ManagedBy returns the package manager that owns exe, or "" if go365 may
+// replace it itself. exe should have symlinks resolved.
+func ManagedBy(exe string) string {
+	slashed := filepath.ToSlash(exe)
+	for _, m := range managedPrefixes {
+		if strings.HasPrefix(slashed, m.prefix) {
+			return m.manager
+		}
+	}
+
+	lower := strings.ToLower(slashed)
+	switch {
+	case strings.Contains(lower, "/scoop/apps/"):
+		return "Scoop"
+	case strings.Contains(lower, "/chocolatey/"):
+		return "Chocolatey"
+	case strings.Contains(lower, "/winget/"):
+		return "winget"
+	}
+	return ""
+}
+
+// Newer reports whether version a is newer than b. Versions are compared as
+// dotted numbers with an optional leading v; pre-release suffixes sort before
+// the release.
+func Newer(a, b string) bool {
+	return compareVersions(a, b) > 0
+}
+
+func compareVersions(a, b string) int {
+	aNum, aPre := splitVersion(a)
+	bNum, bPre := splitVersion(b)
+	for i := 0; i < max(len(aNum), len(bNum)); i++ {
+		var x, y int
+		if i < len(aNum) {
+			x = aNum[i]
+		}
+		if i < len(bNum) {
+			y = bNum[i]
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre > bPre:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func splitVersion(v string) ([]int, string) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	var pre string
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v, pre = v[:i], v[i+1:]
+	}
+	var nums []int
+	for _, part := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(part)
+		nums = append(nums, n)
+	}
+	return nums, pre
+}
@@ -0,0 +1,189 @@
+package selfupdate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/njt/go365/internal/release/releasetest"
+)
+
+// fakeRelease serves a GitHub-style go365 release for this platform. When
+// key is set, the checksums file is signed with it.
+func fakeRelease(t *testing.T, tag string, binary []byte, key ed25519.PrivateKey) *httptest.Server {
+	t.Helper()
+
+	archive := releasetest.Asset{
+		Name: fmt.Sprintf("go365_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH),
+		Data: releasetest.TarGz("go365/"+binaryName(), binary),
+	}
+	checksums := releasetest.Checksums(archive)
+	assets := []releasetest.Asset{
+		{Name: "go365_plan9_mips.tar.gz", Data: []byte("other")},
+		archive,
+		{Name: ChecksumsAsset, Data: checksums},
+	}
+	if key != nil {
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, checksums))
+		assets = append(assets, releasetest.Asset{Name: SignatureAsset, Data: []byte(sig)})
+	}
+	return releasetest.NewServer(Repo, tag, assets...)
+}
+
+func TestLatestAndDownload(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	binary := []byte("new go365")
+	server := fakeRelease(t, "v1.2.0", binary, priv)
+	defer server.Close()
+
+	oldAPI, oldKey := GitHubAPIURL, PublicKey
+	GitHubAPIURL = server.URL
+	PublicKey = base64.StdEncoding.EncodeToString(pub)
+	defer func() { GitHubAPIURL, PublicKey = oldAPI, oldKey }()
+
+	updater := New()
+	ctx := context.Background()
+
+	rel, err := updater.Latest(ctx, "")
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if rel.Version != "v1.2.0" || !strings.Contains(rel.Asset, runtime.GOOS) {
+		t.Errorf("Unexpected release: %+v", rel)
+	}
+
+	got, err := updater.Download(ctx, rel)
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if !bytes.Equal(got, binary) {
+		t.Errorf("Expected the binary from the archive, got %q", got)
+	}
+
+	// A different key must reject the release
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	PublicKey = base64.StdEncoding.EncodeToString(otherPub)
+	if _, err := updater.Download(ctx, rel); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("Expected a signature error, got %v", err)
+	}
+}
+
+func TestDownloadUnsigned(t *testing.T) {
+	server := fakeRelease(t, "v1.2.0", []byte("new go365"), nil)
+	defer server.Close()
+
+	oldAPI, oldKey := GitHubAPIURL, PublicKey
+	GitHubAPIURL = server.URL
+	defer func() { GitHubAPIURL, PublicKey = oldAPI, oldKey }()
+
+	updater := New()
+	ctx := context.Background()
+	rel, err := updater.Latest(ctx, "")
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+
+	// Without a built-in key the checksum alone is enough
+	PublicKey = ""
+	if _, err := updater.Download(ctx, rel); err != nil {
+		t.Errorf("Download failed: %v", err)
+	}
+
+	// With a key, a missing signature is refused
+	pub, _, _ := ed25519.GenerateKey(nil)
+	PublicKey = base64.StdEncoding.EncodeToString(pub)
+	rel.SignatureURL = ""
+	if _, err := updater.Download(ctx, rel); err == nil {
+		t.Error("Expected an unsigned release to be refused")
+	}
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	server := fakeRelease(t, "v1.2.0", []byte("new go365"), nil)
+	defer server.Close()
+
+	oldAPI := GitHubAPIURL
+	GitHubAPIURL = server.URL
+	defer func() { GitHubAPIURL = oldAPI }()
+
+	updater := New()
+	rel, err := updater.Latest(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	rel.URL = server.URL + "/download/checksums.txt"
+	if _, err := updater.Download(context.Background(), rel); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), binaryName())
+	if err := os.WriteFile(exe, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(exe, []byte("new")); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "new" {
+		t.Errorf("Expected the new binary, got %q", data)
+	}
+	if info, _ := os.Stat(exe); runtime.GOOS != "windows" && info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Replaced binary is not executable: %v", info.Mode())
+	}
+	if _, err := os.Stat(exe + ".new"); !os.IsNotExist(err) {
+		t.Error("Temporary file left behind")
+	}
+}
+
+func TestManagedBy(t *testing.T) {
+	tests := []struct {
+		exe  string
+		want string
+	}{
+		{"/usr/bin/go365", "the system package manager"},
+		{"/opt/homebrew/Cellar/go365/1.0/bin/go365", "Homebrew"},
+		{"/nix/store/abc-go365/bin/go365", "Nix"},
+		{"C:/Users/me/scoop/apps/go365/current/go365.exe", "Scoop"},
+		{"/home/me/go/bin/go365", ""},
+		{"/usr/local/bin/go365", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ManagedBy(tt.exe); got != tt.want {
+			t.Errorf("ManagedBy(%q) = %q, want %q", tt.exe, got, tt.want)
+		}
+	}
+}
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"v1.2.0", "v1.1.9", true},
+		{"v1.10.0", "v1.9.0", true},
+		{"1.2.0", "v1.2.0", false},
+		{"v1.2", "v1.2.0", false},
+		{"v1.2.0", "v1.2.0-rc.1", true},
+		{"v1.2.0-rc.2", "v1.2.0-rc.1", true},
+		{"v1.2.0-rc.1", "v1.2.0", false},
+		{"v1.1.0", "v1.2.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := Newer(tt.a, tt.b); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
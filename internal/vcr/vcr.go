@@ -0,0 +1,309 @@
+// Package vcr records Graph responses into fixture files ("cassettes") and
+// replays them, so client code can be tested against real responses without
+// credentials. Recorded cassettes are scrubbed of tokens, email addresses and
+// all but a few response headers.
+package vcr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mode says whether a Recorder replays a cassette or records a new one
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette and never touches the network
+	ModeReplay Mode = iota
+	// ModeRecord sends requests on and saves the responses
+	ModeRecord
+)
+
+// Cassette is the file format: the interactions in the order they happened
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is what a recorded request is matched on
+type Request struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	JSON   json.RawMessage `json:"json,omitempty"` // Body, when it is JSON
+	Body   string          `json:"body,omitempty"` // Body, otherwise
+}
+
+// Response is a recorded response
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	JSON    json.RawMessage   `json:"json,omitempty"` // Body, when it is JSON
+	Body    string            `json:"body,omitempty"` // Body, otherwise
+}
+
+// keptHeaders are the response headers saved in cassettes; the rest can
+// identify the tenant or the machine that recorded them
+var keptHeaders = []string{"Content-Type", "Location", "Retry-After", "ETag", "Preference-Applied"}
+
+var (
+	secretFields = regexp.MustCompile(`("(?i:access_token|refresh_token|id_token|client_secret|password)"\s*:\s*)"[^"]*"`)
+	emailRe      = regexp.MustCompile(`[A-Za-z0-9._+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+)
+
+// Recorder is an http.RoundTripper that records or replays a cassette
+type Recorder struct {
+	// Scrub is applied to URLs and bodies when recording, after the built-in
+	// scrubbing, to remove anything else sensitive such as names
+	Scrub func(string) string
+
+	path string
+	mode Mode
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+	emails   map[string]string
+}
+
+// New returns a Recorder for the cassette at path. In ModeReplay the cassette
+// is loaded; in ModeRecord requests go to base (http.DefaultTransport if nil)
+// and Save writes them out.
+func New(path string, mode Mode, base http.RoundTripper) (*Recorder, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, base: base, emails: map[string]string{}}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// replay answers with the first unused interaction matching the method, path,
+// query and body. The host is ignored, so v1.0 and beta paths still differ
+// but a cassette can be replayed against any base URL.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	want := requestKey(req.Method, req.URL.String(), body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || requestKey(in.Request.Method, in.Request.URL, in.Request.body()) != want {
+			continue
+		}
+		r.used[i] = true
+
+		resp := &http.Response{
+			StatusCode: in.Response.Status,
+			Status:     fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{},
+			Request:    req,
+		}
+		for name, value := range in.Response.Headers {
+			resp.Header.Set(name, value)
+		}
+		data := in.Response.body()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		resp.ContentLength = int64(len(data))
+		return resp, nil
+	}
+	return nil, fmt.Errorf("vcr: no recorded response for %s %s in %s", req.Method, req.URL, r.path)
+}
+
+// record sends the request on and keeps a scrubbed copy of the exchange
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		// Save the decoded body; replays are sent uncompressed
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, err
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = int64(len(data))
+		resp.Uncompressed = true
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	in := &Interaction{
+		Request:  Request{Method: req.Method, URL: r.scrubURL(req.URL)},
+		Response: Response{Status: resp.StatusCode, Headers: map[string]string{}},
+	}
+	in.Request.JSON, in.Request.Body = splitBody(r.scrub(string(body)))
+	in.Response.JSON, in.Response.Body = splitBody(r.scrub(string(data)))
+	for _, name := range keptHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			in.Response.Headers[name] = r.scrub(value)
+		}
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	return resp, nil
+}
+
+// Save writes the recorded cassette. It does nothing when replaying.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(&r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// scrub removes tokens and replaces email addresses with stable placeholders,
+// so the same address always becomes the same userN@example.com
+func (r *Recorder) scrub(s string) string {
+	s = secretFields.ReplaceAllString(s, `$1"[REDACTED]"`)
+	s = emailRe.ReplaceAllStringFunc(s, func(email string) string {
+		key := strings.ToLower(email)
+		if strings.HasSuffix(key, "@example.com") {
+			return email
+		}
+		if _, ok := r.emails[key]; !ok {
+			r.emails[key] = fmt.Sprintf("user%d@example.com", len(r.emails)+1)
+		}
+		return r.emails[key]
+	})
+	if r.Scrub != nil {
+		s = r.Scrub(s)
+	}
+	return s
+}
+
+// scrubURL scrubs the decoded path and query values, so addresses inside
+// quoted or escaped filters are caught too
+func (r *Recorder) scrubURL(u *url.URL) string {
+	scrubbed := *u
+	scrubbed.Path = r.scrub(u.Path)
+	scrubbed.RawPath = ""
+	query := u.Query()
+	for name, values := range query {
+		for i := range values {
+			values[i] = r.scrub(values[i])
+		}
+		query[name] = values
+	}
+	scrubbed.RawQuery = query.Encode()
+	return scrubbed.String()
+}
+
+func (req Request) body() []byte {
+	if len(req.JSON) > 0 {
+		return req.JSON
+	}
+	return []byte(req.Body)
+}
+
+func (resp Response) body() []byte {
+	if len(resp.JSON) > 0 {
+		return resp.JSON
+	}
+	return []byte(resp.Body)
+}
+
+// splitBody stores JSON bodies as JSON, so cassettes stay readable, and
+// anything else as a string
+func splitBody(s string) (json.RawMessage, string) {
+	if s == "" {
+		return nil, ""
+	}
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s), ""
+	}
+	return nil, s
+}
+
+// readBody returns the request body, leaving it readable for the transport
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// requestKey identifies a request for matching: method, path, sorted query
+// and compacted JSON body
+func requestKey(method, rawURL string, body []byte) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return method + " " + rawURL
+	}
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) != nil {
+		compact.Reset()
+		compact.Write(body)
+	}
+	return method + " " + u.Path + "?" + u.Query().Encode() + " " + compact.String()
+}
@@ -0,0 +1,134 @@
+package vcr
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("request-id", "secret-request-id")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		io.WriteString(zw, `{"value": [{"id": "msg1", "from": {"emailAddress": {"address": "Jane.Doe@contoso.com"}}}], "access_token": "abc123"}`)
+		zw.Close()
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "mail.json")
+	rec, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	rec.Scrub = func(s string) string { return strings.ReplaceAll(s, "contoso", "tenant") }
+
+	client := &http.Client{Transport: rec}
+	req, _ := http.NewRequest("GET", server.URL+"/users/jane.doe@contoso.com/messages?$filter=from/emailAddress/address+eq+'jane.doe@contoso.com'", nil)
+	req.Header.Set("Authorization", "Bearer live-token")
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Recorded request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "Jane.Doe@contoso.com") {
+		t.Errorf("The caller should get the real response while recording, got %s", body)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cassette not written: %v", err)
+	}
+	cassette := string(data)
+	for _, secret := range []string{"contoso", "Jane", "abc123", "live-token", "secret-request-id"} {
+		if strings.Contains(cassette, secret) {
+			t.Errorf("Cassette contains %q:\n%s", secret, cassette)
+		}
+	}
+	if !strings.Contains(cassette, `"address": "user1@example.com"`) {
+		t.Errorf("Expected a placeholder address in the cassette:\n%s", cassette)
+	}
+
+	// The same address in the URL and body maps to the same placeholder
+	rec, err = New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("New (replay) failed: %v", err)
+	}
+	client = &http.Client{Transport: rec}
+	resp, err = client.Get("https://graph.microsoft.com/users/user1@example.com/messages?$filter=from/emailAddress/address+eq+'user1@example.com'")
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"id": "msg1"`) {
+		t.Errorf("Unexpected replay %d %s", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected the Content-Type header to be replayed, got %q", resp.Header.Get("Content-Type"))
+	}
+}
+
+func TestReplayMatching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	os.WriteFile(path, []byte(`{
+		"interactions": [
+			{"request": {"method": "GET", "url": "https://graph.microsoft.com/v1.0/me?$select=id,mail&$top=1"}, "response": {"status": 200, "json": {"id": "first"}}},
+			{"request": {"method": "GET", "url": "https://graph.microsoft.com/v1.0/me?$select=id,mail&$top=1"}, "response": {"status": 200, "json": {"id": "second"}}},
+			{"request": {"method": "POST", "url": "https://graph.microsoft.com/v1.0/me/sendMail", "json": {"message": {"subject": "Hi"}}}, "response": {"status": 202}}
+		]
+	}`), 0644)
+
+	rec, err := New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	client := &http.Client{Transport: rec}
+
+	// Query order doesn't matter, and repeats get the next recording
+	for _, want := range []string{"first", "second"} {
+		resp, err := client.Get("http://127.0.0.1/v1.0/me?$top=1&$select=id,mail")
+		if err != nil {
+			t.Fatalf("Replay failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected %s, got %s", want, body)
+		}
+	}
+	if _, err := client.Get("http://127.0.0.1/v1.0/me?$top=1&$select=id,mail"); err == nil {
+		t.Error("Expected an error once the recordings are used up")
+	}
+
+	// JSON bodies are compared without whitespace
+	resp, err := client.Post("http://127.0.0.1/v1.0/me/sendMail", "application/json", strings.NewReader(`{"message":{"subject":"Hi"}}`))
+	if err != nil {
+		t.Fatalf("Replay of POST failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected 202, got %d", resp.StatusCode)
+	}
+
+	if _, err := client.Post("http://127.0.0.1/v1.0/me/sendMail", "application/json", strings.NewReader(`{"message":{"subject":"Other"}}`)); err == nil {
+		t.Error("Expected an error for a body that was not recorded")
+	}
+}
+
+func TestNewMissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil); err == nil {
+		t.Error("Expected an error for a missing cassette")
+	}
+}
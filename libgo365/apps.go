@@ -0,0 +1,146 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// OAuth2PermissionGrant represents a delegated permission grant (user or admin consent)
+type OAuth2PermissionGrant struct {
+	ID          string `json:"id,omitempty"`
+	ClientID    string `json:"clientId,omitempty"`    // Object ID of the client service principal
+	ConsentType string `json:"consentType,omitempty"` // AllPrincipals (admin consent) or Principal (user consent)
+	PrincipalID string `json:"principalId,omitempty"` // User ID when consentType is Principal
+	ResourceID  string `json:"resourceId,omitempty"`  // Object ID of the resource service principal (e.g., Microsoft Graph)
+	Scope       string `json:"scope,omitempty"`       // Space-separated delegated scopes
+}
+
+// Scopes returns the granted scopes as a slice
+func (g *OAuth2PermissionGrant) Scopes() []string {
+	return strings.Fields(g.Scope)
+}
+
+// OAuth2PermissionGrantList represents a list of permission grants returned by Graph API
+type OAuth2PermissionGrantList struct {
+	Value    []*OAuth2PermissionGrant `json:"value"`
+	NextLink string                   `json:"@odata.nextLink,omitempty"`
+}
+
+// AppRoleAssignment represents an application role assigned to a user, group, or service principal
+type AppRoleAssignment struct {
+	ID                   string     `json:"id,omitempty"`
+	AppRoleID            string     `json:"appRoleId,omitempty"` // All zeros means default access
+	PrincipalDisplayName string     `json:"principalDisplayName,omitempty"`
+	PrincipalID          string     `json:"principalId,omitempty"`
+	PrincipalType        string     `json:"principalType,omitempty"` // User, Group, ServicePrincipal
+	ResourceDisplayName  string     `json:"resourceDisplayName,omitempty"`
+	ResourceID           string     `json:"resourceId,omitempty"`
+	CreatedDateTime      *time.Time `json:"createdDateTime,omitempty"`
+}
+
+// AppRoleAssignmentList represents a list of app role assignments returned by Graph API
+type AppRoleAssignmentList struct {
+	Value    []*AppRoleAssignment `json:"value"`
+	NextLink string               `json:"@odata.nextLink,omitempty"`
+}
+
+// ServicePrincipal represents an application instance in the tenant
+type ServicePrincipal struct {
+	ID                     string `json:"id,omitempty"`
+	AppID                  string `json:"appId,omitempty"`
+	DisplayName            string `json:"displayName,omitempty"`
+	PublisherName          string `json:"publisherName,omitempty"`
+	AppOwnerOrganizationID string `json:"appOwnerOrganizationId,omitempty"`
+}
+
+// ListPermissionGrantsOptions represents options for listing delegated permission grants
+type ListPermissionGrantsOptions struct {
+	UserID    string // Only grants consented by this user (default: all grants in the tenant)
+	PageToken string
+}
+
+// ListPermissionGrantsResponse represents the response from ListPermissionGrants with pagination info
+type ListPermissionGrantsResponse struct {
+	Grants        []*OAuth2PermissionGrant
+	Count         int
+	HasMore       bool
+	NextPageToken string
+}
+
+// ListPermissionGrants retrieves delegated OAuth2 permission grants
+func (c *Client) ListPermissionGrants(ctx context.Context, opts *ListPermissionGrantsOptions) (*ListPermissionGrantsResponse, error) {
+	path := "/oauth2PermissionGrants"
+	params := url.Values{}
+	if opts != nil {
+		if opts.UserID != "" {
+			path = fmt.Sprintf("/users/%s/oauth2PermissionGrants", opts.UserID)
+		}
+		if opts.PageToken != "" {
+			params.Set("$skiptoken", opts.PageToken)
+		}
+	}
+
+	fullPath := path
+	if len(params) > 0 {
+		fullPath = path + "?" + params.Encode()
+	}
+
+	data, err := c.Get(ctx, fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var grantList OAuth2PermissionGrantList
+	if err := json.Unmarshal(data, &grantList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal permission grants: %w", err)
+	}
+
+	return &ListPermissionGrantsResponse{
+		Grants:        grantList.Value,
+		Count:         len(grantList.Value),
+		HasMore:       grantList.NextLink != "",
+		NextPageToken: ExtractPageToken(grantList.NextLink),
+	}, nil
+}
+
+// ListAppRoleAssignments retrieves the app roles assigned to a user
+func (c *Client) ListAppRoleAssignments(ctx context.Context, userID string) ([]*AppRoleAssignment, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	data, err := c.Get(ctx, fmt.Sprintf("/users/%s/appRoleAssignments", userID))
+	if err != nil {
+		return nil, err
+	}
+
+	var assignmentList AppRoleAssignmentList
+	if err := json.Unmarshal(data, &assignmentList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal app role assignments: %w", err)
+	}
+
+	return assignmentList.Value, nil
+}
+
+// GetServicePrincipal retrieves a service principal by object ID
+func (c *Client) GetServicePrincipal(ctx context.Context, id string) (*ServicePrincipal, error) {
+	if id == "" {
+		return nil, fmt.Errorf("service principal ID is required")
+	}
+
+	data, err := c.Get(ctx, fmt.Sprintf("/servicePrincipals/%s?$select=id,appId,displayName,publisherName,appOwnerOrganizationId", id))
+	if err != nil {
+		return nil, err
+	}
+
+	var sp ServicePrincipal
+	if err := json.Unmarshal(data, &sp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal service principal: %w", err)
+	}
+
+	return &sp, nil
+}
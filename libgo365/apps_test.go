@@ -0,0 +1,108 @@
+package libgo365
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListPermissionGrants(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       *ListPermissionGrantsOptions
+		expectPath string
+	}{
+		{"tenant", nil, "/oauth2PermissionGrants"},
+		{"user", &ListPermissionGrantsOptions{UserID: "user1"}, "/users/user1/oauth2PermissionGrants"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.expectPath {
+					t.Errorf("Expected path %s, got %s", tt.expectPath, r.URL.Path)
+				}
+				w.Write([]byte(`{"value": [
+					{"id": "g1", "clientId": "sp1", "consentType": "Principal", "principalId": "user1", "resourceId": "graph", "scope": " User.Read Mail.Read "}
+				]}`))
+			}))
+			defer server.Close()
+
+			client := &Client{
+				httpClient: server.Client(),
+				baseURL:    server.URL,
+				tokens:     StaticToken("test-token"),
+			}
+
+			resp, err := client.ListPermissionGrants(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("ListPermissionGrants failed: %v", err)
+			}
+			if len(resp.Grants) != 1 {
+				t.Fatalf("Expected 1 grant, got %d", len(resp.Grants))
+			}
+			scopes := resp.Grants[0].Scopes()
+			if len(scopes) != 2 || scopes[0] != "User.Read" || scopes[1] != "Mail.Read" {
+				t.Errorf("Unexpected scopes: %v", scopes)
+			}
+		})
+	}
+}
+
+func TestListAppRoleAssignments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/user1/appRoleAssignments" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"value": [
+			{"id": "a1", "appRoleId": "00000000-0000-0000-0000-000000000000", "principalType": "User", "resourceDisplayName": "Salesforce"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	assignments, err := client.ListAppRoleAssignments(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("ListAppRoleAssignments failed: %v", err)
+	}
+	if len(assignments) != 1 || assignments[0].ResourceDisplayName != "Salesforce" {
+		t.Errorf("Unexpected assignments: %+v", assignments)
+	}
+
+	if _, err := client.ListAppRoleAssignments(context.Background(), ""); err == nil {
+		t.Error("Expected error for empty user ID")
+	}
+}
+
+func TestGetServicePrincipal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicePrincipals/sp1" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("$select") == "" {
+			t.Error("Expected $select parameter")
+		}
+		w.Write([]byte(`{"id": "sp1", "appId": "app1", "displayName": "Contoso Notes", "publisherName": "Contoso"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	sp, err := client.GetServicePrincipal(context.Background(), "sp1")
+	if err != nil {
+		t.Fatalf("GetServicePrincipal failed: %v", err)
+	}
+	if sp.DisplayName != "Contoso Notes" {
+		t.Errorf("Expected 'Contoso Notes', got '%s'", sp.DisplayName)
+	}
+}
@@ -0,0 +1,305 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attachment types reported in an attachment's @odata.type
+const (
+	FileAttachmentType      = "#microsoft.graph.fileAttachment"
+	ItemAttachmentType      = "#microsoft.graph.itemAttachment"
+	ReferenceAttachmentType = "#microsoft.graph.referenceAttachment"
+)
+
+// Attachment represents a file, item or reference attachment on a message
+type Attachment struct {
+	ODataType            string     `json:"@odata.type,omitempty"`
+	ID                   string     `json:"id,omitempty"`
+	Name                 string     `json:"name,omitempty"`
+	ContentType          string     `json:"contentType,omitempty"`
+	Size                 int64      `json:"size,omitempty"`
+	IsInline             bool       `json:"isInline,omitempty"`
+	LastModifiedDateTime *time.Time `json:"lastModifiedDateTime,omitempty"`
+	ContentBytes         []byte     `json:"contentBytes,omitempty"` // File attachments only; base64 in JSON
+}
+
+// AttachmentList represents a list of attachments returned by Graph API
+type AttachmentList struct {
+	Value []*Attachment `json:"value"`
+}
+
+// ListAttachments retrieves a message's attachments without their content
+func (c *Client) ListAttachments(ctx context.Context, messageID string) ([]*Attachment, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("message ID is required")
+	}
+
+	params := url.Values{}
+	params.Set("$select", "id,name,contentType,size,isInline,lastModifiedDateTime")
+
+	data, err := c.Get(ctx, fmt.Sprintf("/me/messages/%s/attachments?%s", messageID, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var list AttachmentList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attachments: %w", err)
+	}
+
+	return list.Value, nil
+}
+
+// GetAttachmentContent downloads an attachment's raw content. Item attachments
+// (attached messages or events) come back as MIME.
+func (c *Client) GetAttachmentContent(ctx context.Context, messageID, attachmentID string) ([]byte, error) {
+	if messageID == "" || attachmentID == "" {
+		return nil, fmt.Errorf("message ID and attachment ID are required")
+	}
+
+	return c.uncached().Get(ctx, fmt.Sprintf("/me/messages/%s/attachments/%s/$value", messageID, attachmentID))
+}
+
+// DeleteAttachment removes an attachment from a draft message
+func (c *Client) DeleteAttachment(ctx context.Context, messageID, attachmentID string) error {
+	if messageID == "" || attachmentID == "" {
+		return fmt.Errorf("message ID and attachment ID are required")
+	}
+
+	return c.Delete(ctx, fmt.Sprintf("/me/messages/%s/attachments/%s", messageID, attachmentID))
+}
+
+const (
+	// MaxInlineAttachmentSize is the most attachment content sent inline as
+	// base64 in a message; Graph rejects requests over 4 MB, and bigger
+	// files go through an upload session
+	MaxInlineAttachmentSize = 3 << 20
+
+	// MaxAttachmentSize is the largest file Outlook accepts as an attachment
+	MaxAttachmentSize = 150 << 20
+)
+
+// attachmentChunkSize is how much of a file each upload session request
+// carries. Outlook takes at most 4 MB per request. Tests shorten it.
+var attachmentChunkSize int64 = 3 << 20
+
+// AttachmentFile is a file to attach to a message
+type AttachmentFile struct {
+	Name        string
+	ContentType string // Defaults to application/octet-stream
+	Size        int64
+	Content     io.ReaderAt
+}
+
+// NewFileAttachment returns an attachment carrying content inline
+func NewFileAttachment(name, contentType string, content []byte) *Attachment {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &Attachment{
+		ODataType:    FileAttachmentType,
+		Name:         name,
+		ContentType:  contentType,
+		Size:         int64(len(content)),
+		ContentBytes: content,
+	}
+}
+
+// inline reads the whole file into an attachment
+func (f *AttachmentFile) inline() (*Attachment, error) {
+	content, err := io.ReadAll(io.NewSectionReader(f.Content, 0, f.Size))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	return NewFileAttachment(f.Name, f.ContentType, content), nil
+}
+
+// SendMailWithAttachments sends message with files attached. If the files
+// add up to MaxInlineAttachmentSize or less they go inline with sendMail.
+// Otherwise the message is saved as a draft, each file is attached (through
+// an upload session if it is over MaxInlineAttachmentSize) and the draft is
+// sent; a message sent that way is always saved to Sent Items.
+func (c *Client) SendMailWithAttachments(ctx context.Context, message *Message, files []*AttachmentFile, saveToSentItems bool) error {
+	if message == nil {
+		return fmt.Errorf("message is required")
+	}
+
+	var total int64
+	for _, f := range files {
+		if f.Size > MaxAttachmentSize {
+			return fmt.Errorf("%s is %d bytes; Outlook attachments are limited to %d", f.Name, f.Size, MaxAttachmentSize)
+		}
+		total += f.Size
+	}
+	if total <= MaxInlineAttachmentSize {
+		withFiles := *message
+		for _, f := range files {
+			attachment, err := f.inline()
+			if err != nil {
+				return err
+			}
+			withFiles.Attachments = append(withFiles.Attachments, attachment)
+		}
+		return c.SendMail(ctx, &withFiles, saveToSentItems)
+	}
+
+	draft, err := c.CreateDraft(ctx, message)
+	if err != nil {
+		return fmt.Errorf("failed to create draft: %w", err)
+	}
+	for _, f := range files {
+		if err := c.AddAttachment(ctx, draft.ID, f); err != nil {
+			return err
+		}
+	}
+
+	return c.SendDraft(ctx, draft.ID)
+}
+
+// AddAttachment attaches a file to a draft message, through an upload
+// session if it is over MaxInlineAttachmentSize
+func (c *Client) AddAttachment(ctx context.Context, messageID string, file *AttachmentFile) error {
+	if messageID == "" {
+		return fmt.Errorf("message ID is required")
+	}
+	if file.Size <= MaxInlineAttachmentSize {
+		attachment, err := file.inline()
+		if err != nil {
+			return err
+		}
+		if _, err := c.Post(ctx, fmt.Sprintf("/me/messages/%s/attachments", messageID), attachment); err != nil {
+			return fmt.Errorf("failed to attach %s: %w", file.Name, err)
+		}
+		return nil
+	}
+
+	session, err := c.CreateAttachmentUploadSession(ctx, messageID, file)
+	if err != nil {
+		return fmt.Errorf("failed to start upload of %s: %w", file.Name, err)
+	}
+	if err := c.UploadAttachment(ctx, session, file); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// UploadSession is an Outlook upload session for a large attachment
+type UploadSession struct {
+	UploadURL          string     `json:"uploadUrl"`
+	ExpirationDateTime *time.Time `json:"expirationDateTime,omitempty"`
+
+	// NextExpectedRanges are the byte ranges still to upload, such as
+	// "3145728-"; UploadAttachment keeps it current
+	NextExpectedRanges []string `json:"nextExpectedRanges,omitempty"`
+}
+
+// CreateAttachmentUploadSession starts an upload session for attaching file
+// to a draft message. Upload the content with UploadAttachment.
+func (c *Client) CreateAttachmentUploadSession(ctx context.Context, messageID string, file *AttachmentFile) (*UploadSession, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("message ID is required")
+	}
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	body := map[string]interface{}{
+		"AttachmentItem": map[string]interface{}{
+			"attachmentType": "file",
+			"name":           file.Name,
+			"size":           file.Size,
+			"contentType":    contentType,
+		},
+	}
+	data, err := c.Post(ctx, fmt.Sprintf("/me/messages/%s/attachments/createUploadSession", messageID), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var session UploadSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal upload session: %w", err)
+	}
+	return &session, nil
+}
+
+// UploadAttachment uploads file's content to an upload session in chunks,
+// starting from the session's next expected range. If it fails part way,
+// calling it again with the same session resumes the upload.
+func (c *Client) UploadAttachment(ctx context.Context, session *UploadSession, file *AttachmentFile) error {
+	offset, err := nextUploadOffset(session.NextExpectedRanges)
+	if err != nil {
+		return err
+	}
+
+	for offset < file.Size {
+		end := min(offset+attachmentChunkSize, file.Size)
+		chunk := io.NewSectionReader(file.Content, offset, end-offset)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, session.UploadURL, chunk)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		// The upload URL carries its own token, and Outlook rejects an
+		// Authorization header on it
+		req.ContentLength = end - offset
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(io.NewSectionReader(file.Content, offset, end-offset)), nil
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, end-1, file.Size))
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("request failed: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return newResponseError(resp, respBody)
+		}
+
+		// Outlook answers 201 once the last chunk is in
+		if resp.StatusCode == http.StatusCreated {
+			session.NextExpectedRanges = nil
+			return nil
+		}
+		var progress UploadSession
+		if json.Unmarshal(respBody, &progress) == nil && len(progress.NextExpectedRanges) > 0 {
+			session.NextExpectedRanges = progress.NextExpectedRanges
+			if offset, err = nextUploadOffset(progress.NextExpectedRanges); err != nil {
+				return err
+			}
+			continue
+		}
+		offset = end
+		session.NextExpectedRanges = []string{fmt.Sprintf("%d-", offset)}
+	}
+	return nil
+}
+
+// nextUploadOffset returns where the first of an upload session's expected
+// ranges starts, or 0 if there are none yet
+func nextUploadOffset(ranges []string) (int64, error) {
+	if len(ranges) == 0 {
+		return 0, nil
+	}
+	start, _, _ := strings.Cut(ranges[0], "-")
+	offset, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid upload range %q", ranges[0])
+	}
+	return offset, nil
+}
@@ -0,0 +1,214 @@
+package libgo365
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListAttachments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/messages/msg1/attachments" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if sel := r.URL.Query().Get("$select"); sel == "" {
+			t.Error("Expected $select to leave out attachment content")
+		}
+		w.Write([]byte(`{"value":[
+			{"@odata.type":"#microsoft.graph.fileAttachment","id":"att1","name":"report.pdf","contentType":"application/pdf","size":2048},
+			{"@odata.type":"#microsoft.graph.itemAttachment","id":"att2","name":"Fwd: hello","size":512,"isInline":false}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	attachments, err := client.ListAttachments(context.Background(), "msg1")
+	if err != nil {
+		t.Fatalf("ListAttachments failed: %v", err)
+	}
+	if len(attachments) != 2 {
+		t.Fatalf("Expected 2 attachments, got %d", len(attachments))
+	}
+	if a := attachments[0]; a.ID != "att1" || a.Name != "report.pdf" || a.Size != 2048 || a.ODataType != FileAttachmentType {
+		t.Errorf("Unexpected first attachment: %+v", a)
+	}
+	if attachments[1].ODataType != ItemAttachmentType {
+		t.Errorf("Expected an item attachment, got %q", attachments[1].ODataType)
+	}
+
+	if _, err := client.ListAttachments(context.Background(), ""); err == nil {
+		t.Error("Expected an error without a message ID")
+	}
+}
+
+func TestGetAttachmentContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/messages/msg1/attachments/att1/$value" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte("%PDF-1.7"))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	data, err := client.GetAttachmentContent(context.Background(), "msg1", "att1")
+	if err != nil {
+		t.Fatalf("GetAttachmentContent failed: %v", err)
+	}
+	if string(data) != "%PDF-1.7" {
+		t.Errorf("Unexpected content %q", data)
+	}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/me/messages/draft1/attachments/att1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	if err := client.DeleteAttachment(context.Background(), "draft1", "att1"); err != nil {
+		t.Errorf("DeleteAttachment failed: %v", err)
+	}
+}
+
+func TestSendMailWithAttachments(t *testing.T) {
+	var sent SendMailRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/sendMail" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL, tokens: StaticToken("test-token")}
+	message := &Message{Subject: "Report", ToRecipients: []*Recipient{{EmailAddress: &EmailAddress{Address: "a@contoso.com"}}}}
+	content := []byte("quarter,amount\n")
+	files := []*AttachmentFile{{Name: "report.csv", ContentType: "text/csv", Size: int64(len(content)), Content: bytes.NewReader(content)}}
+
+	if err := client.SendMailWithAttachments(context.Background(), message, files, true); err != nil {
+		t.Fatalf("SendMailWithAttachments failed: %v", err)
+	}
+	if len(sent.Message.Attachments) != 1 {
+		t.Fatalf("Expected one inline attachment, got %+v", sent.Message)
+	}
+	if a := sent.Message.Attachments[0]; a.ODataType != FileAttachmentType || a.Name != "report.csv" || string(a.ContentBytes) != string(content) {
+		t.Errorf("Unexpected attachment %+v", a)
+	}
+	if len(message.Attachments) != 0 {
+		t.Error("Expected the caller's message left alone")
+	}
+}
+
+func TestSendMailWithLargeAttachment(t *testing.T) {
+	defer func(size int64) { attachmentChunkSize = size }(attachmentChunkSize)
+	attachmentChunkSize = 1 << 20
+
+	content := bytes.Repeat([]byte("x"), MaxInlineAttachmentSize+10)
+	var uploaded []byte
+	var ranges, steps []string
+	failOnce := true
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		steps = append(steps, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/me/messages":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "draft1", "isDraft": true}`))
+		case "/me/messages/draft1/attachments/createUploadSession":
+			var body struct {
+				AttachmentItem struct {
+					Name string `json:"name"`
+					Size int64  `json:"size"`
+				}
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.AttachmentItem.Name != "big.bin" || body.AttachmentItem.Size != int64(len(content)) {
+				t.Errorf("Unexpected attachment item %+v", body.AttachmentItem)
+			}
+			fmt.Fprintf(w, `{"uploadUrl": "%s/upload?authtoken=abc", "nextExpectedRanges": ["0-"]}`, server.URL)
+		case "/upload":
+			if r.Header.Get("Authorization") != "" {
+				t.Error("Expected no Authorization header on the upload URL")
+			}
+			// The second chunk fails the first time
+			if len(uploaded) == 1<<20 && failOnce {
+				failOnce = false
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			ranges = append(ranges, r.Header.Get("Content-Range"))
+			chunk, _ := io.ReadAll(r.Body)
+			uploaded = append(uploaded, chunk...)
+			if len(uploaded) == len(content) {
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			fmt.Fprintf(w, `{"nextExpectedRanges": ["%d-"]}`, len(uploaded))
+		case "/me/messages/draft1/send":
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL, tokens: StaticToken("test-token")}
+	ctx := context.Background()
+	file := &AttachmentFile{Name: "big.bin", Size: int64(len(content)), Content: bytes.NewReader(content)}
+
+	// An interrupted upload resumes from the session's next expected range
+	session, err := client.CreateAttachmentUploadSession(ctx, "draft1", file)
+	if err != nil {
+		t.Fatalf("CreateAttachmentUploadSession failed: %v", err)
+	}
+	if err := client.UploadAttachment(ctx, session, file); err == nil {
+		t.Fatal("Expected the failed chunk to stop the upload")
+	}
+	if len(session.NextExpectedRanges) != 1 || session.NextExpectedRanges[0] != "1048576-" {
+		t.Errorf("Expected the session to resume at 1 MiB, got %v", session.NextExpectedRanges)
+	}
+	if err := client.UploadAttachment(ctx, session, file); err != nil {
+		t.Fatalf("Resumed UploadAttachment failed: %v", err)
+	}
+	if !bytes.Equal(uploaded, content) || ranges[3] != fmt.Sprintf("bytes 3145728-%d/%d", len(content)-1, len(content)) {
+		t.Errorf("Unexpected upload of %d bytes in ranges %v", len(uploaded), ranges)
+	}
+
+	// Sending goes through a draft
+	uploaded, ranges, steps = nil, nil, nil
+	message := &Message{Subject: "Big", ToRecipients: []*Recipient{{EmailAddress: &EmailAddress{Address: "a@contoso.com"}}}}
+	if err := client.SendMailWithAttachments(ctx, message, []*AttachmentFile{file}, true); err != nil {
+		t.Fatalf("SendMailWithAttachments failed: %v", err)
+	}
+	want := []string{"POST /me/messages", "POST /me/messages/draft1/attachments/createUploadSession", "PUT /upload", "PUT /upload", "PUT /upload", "PUT /upload", "POST /me/messages/draft1/send"}
+	if strings.Join(steps, ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected requests %v", steps)
+	}
+}
@@ -3,73 +3,156 @@ package libgo365
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
 )
 
+// GraphDefaultScope asks for every application permission granted to the app,
+// the only scope client credentials can use
+const GraphDefaultScope = "https://graph.microsoft.com/.default"
+
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
 	TenantID string
 	ClientID string
 	Scopes   []string
+
+	// ClientSecret switches to app-only authentication: the app signs in as
+	// itself with the client credentials grant, with no user and no
+	// prompt, and Scopes is ignored in favor of GraphDefaultScope. /me
+	// isn't available; use /users/{id} instead.
+	ClientSecret string
+
+	// CertificatePath also selects app-only authentication, proving the
+	// app's identity with a certificate instead of a secret. It names a PEM
+	// or PFX file holding the certificate and its private key (see
+	// LoadCertificate), decrypted with CertificatePassword if set. It is
+	// used in preference to ClientSecret.
+	CertificatePath     string
+	CertificatePassword string
 }
 
-// TokenCache handles token persistence for MSAL
+// TokenStore persists the serialized MSAL token cache. Implement it to keep
+// tokens somewhere other than ~/.go365/msal_cache.bin, such as Redis, a
+// database or a Kubernetes secret, and pass it with WithTokenStore. The data
+// is opaque and contains refresh tokens, so store it securely.
+type TokenStore interface {
+	// Load returns the stored cache, or nil if nothing has been stored yet
+	Load(ctx context.Context) ([]byte, error)
+	// Save replaces the stored cache
+	Save(ctx context.Context, data []byte) error
+	// Delete removes the stored cache; it is called by Logout
+	Delete(ctx context.Context) error
+}
+
+// TokenCache is the default TokenStore, a file in ~/.go365
 type TokenCache struct {
 	cachePath string
 }
 
 // NewTokenCache creates a new token cache
 func NewTokenCache() (*TokenCache, error) {
-	homeDir, err := os.UserHomeDir()
+	configDir, err := tokenCacheDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
+	return &TokenCache{
+		cachePath: filepath.Join(configDir, "msal_cache.bin"),
+	}, nil
+}
 
-	configDir := filepath.Join(homeDir, ".go365")
-	if err := os.MkdirAll(configDir, 0700); err != nil {
-		return nil, fmt.Errorf("failed to create config directory: %w", err)
+// NewTenantTokenCache creates a token cache for one tenant, kept apart from
+// the default cache in ~/.go365/tenants, so that signing in to another
+// tenant doesn't replace the usual account
+func NewTenantTokenCache(tenant string) (*TokenCache, error) {
+	if tenant == "" {
+		return nil, fmt.Errorf("tenant is required")
+	}
+	configDir, err := tokenCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(configDir, "tenants")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create tenant cache directory: %w", err)
 	}
 
+	// Tenants are GUIDs or domain names; anything else mustn't reach the path
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(tenant))
 	return &TokenCache{
-		cachePath: filepath.Join(configDir, "msal_cache.bin"),
+		cachePath: filepath.Join(dir, name+".bin"),
 	}, nil
 }
 
-// Replace implements cache.ExportReplace.Replace
-func (tc *TokenCache) Replace(ctx context.Context, cache cache.Unmarshaler, hints cache.ReplaceHints) error {
-	data, err := os.ReadFile(tc.cachePath)
+// tokenCacheDir returns ~/.go365, creating it if needed
+func tokenCacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil // No cache to replace with
-		}
-		return fmt.Errorf("failed to read cache: %w", err)
+		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	if err := cache.Unmarshal(data); err != nil {
-		return fmt.Errorf("failed to unmarshal cache: %w", err)
+	configDir := filepath.Join(homeDir, ".go365")
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
 	}
+	return configDir, nil
+}
 
-	return nil
+// Path returns the cache file's location
+func (tc *TokenCache) Path() string {
+	return tc.cachePath
 }
 
-// Export implements cache.ExportReplace.Export
-func (tc *TokenCache) Export(ctx context.Context, cache cache.Marshaler, hints cache.ExportHints) error {
-	data, err := cache.Marshal()
+// Load implements TokenStore.Load
+func (tc *TokenCache) Load(ctx context.Context) ([]byte, error) {
+	data, err := os.ReadFile(tc.cachePath)
 	if err != nil {
-		return fmt.Errorf("failed to marshal cache: %w", err)
+		if os.IsNotExist(err) {
+			return nil, nil // No cache yet
+		}
+		return nil, fmt.Errorf("failed to read cache: %w", err)
 	}
+	return data, nil
+}
 
+// Save implements TokenStore.Save
+func (tc *TokenCache) Save(ctx context.Context, data []byte) error {
 	if err := os.WriteFile(tc.cachePath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write cache: %w", err)
 	}
-
 	return nil
 }
 
+// Delete implements TokenStore.Delete
+func (tc *TokenCache) Delete(ctx context.Context) error {
+	return tc.DeleteCache()
+}
+
+// Replace implements cache.ExportReplace.Replace
+func (tc *TokenCache) Replace(ctx context.Context, cache cache.Unmarshaler, hints cache.ReplaceHints) error {
+	return storeCache{tc}.Replace(ctx, cache, hints)
+}
+
+// Export implements cache.ExportReplace.Export
+func (tc *TokenCache) Export(ctx context.Context, cache cache.Marshaler, hints cache.ExportHints) error {
+	return storeCache{tc}.Export(ctx, cache, hints)
+}
+
 // DeleteCache removes the stored cache
 func (tc *TokenCache) DeleteCache() error {
 	if err := os.Remove(tc.cachePath); err != nil && !os.IsNotExist(err) {
@@ -78,82 +161,369 @@ func (tc *TokenCache) DeleteCache() error {
 	return nil
 }
 
+// storeCache adapts a TokenStore to MSAL's cache.ExportReplace
+type storeCache struct {
+	store TokenStore
+}
+
+func (sc storeCache) Replace(ctx context.Context, cache cache.Unmarshaler, hints cache.ReplaceHints) error {
+	data, err := sc.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil // No cache to replace with
+	}
+
+	if err := cache.Unmarshal(data); err != nil {
+		return fmt.Errorf("failed to unmarshal cache: %w", err)
+	}
+	return nil
+}
+
+func (sc storeCache) Export(ctx context.Context, cache cache.Marshaler, hints cache.ExportHints) error {
+	data, err := cache.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %w", err)
+	}
+	return sc.store.Save(ctx, data)
+}
+
+// AuthOption customizes an Authenticator
+type AuthOption func(*authOptions)
+
+type authOptions struct {
+	store      TokenStore
+	consent    io.Writer
+	httpClient *http.Client
+}
+
+// WithTokenStore keeps the token cache in store instead of
+// ~/.go365/msal_cache.bin
+func WithTokenStore(store TokenStore) AuthOption {
+	return func(o *authOptions) {
+		o.store = store
+	}
+}
+
+// WithHTTPClient sends sign-in requests through client, such as one whose
+// transport came from NewTransport, instead of http.DefaultClient.
+// Managed identity requests stay on the machine and ignore it.
+func WithHTTPClient(client *http.Client) AuthOption {
+	return func(o *authOptions) {
+		o.httpClient = client
+	}
+}
+
+// errAzureIdentityLogin is returned by the login methods of Azure identity
+// authenticators, which never sign in themselves
+var errAzureIdentityLogin = fmt.Errorf("Azure identity authentication uses existing credentials; sign in with 'az login' instead")
+
 // Authenticator handles MSAL authentication
 type Authenticator struct {
-	app        public.Client
-	scopes     []string
-	tokenCache *TokenCache
+	app    public.Client
+	scopes []string
+	store  TokenStore
+
+	// appClient is set for app-only authentication, when app is unused
+	appClient *confidential.Client
+	clientID  string
+	tenantID  string
+
+	// thumbprint identifies the certificate used for app-only
+	// authentication, if any
+	thumbprint string
+
+	// azCred is set for NewAzureIdentityAuthenticator, when neither MSAL
+	// client is used
+	azCred azcore.TokenCredential
+
+	// managedIdentity is set for NewManagedIdentityAuthenticator, whose
+	// azCred is a managed identity credential
+	managedIdentity bool
+
+	// consent receives device code instructions when a token needs scopes
+	// the user hasn't consented to; nil returns ConsentRequiredError
+	consent io.Writer
+
+	cached cachedToken
 }
 
-// NewAuthenticator creates a new authenticator
-func NewAuthenticator(cfg AuthConfig) (*Authenticator, error) {
-	tokenCache, err := NewTokenCache()
-	if err != nil {
-		return nil, err
+// NewAuthenticator creates a new authenticator. Tokens are cached in
+// ~/.go365/msal_cache.bin unless WithTokenStore is given.
+func NewAuthenticator(cfg AuthConfig, opts ...AuthOption) (*Authenticator, error) {
+	var o authOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.store == nil {
+		tokenCache, err := NewTokenCache()
+		if err != nil {
+			return nil, err
+		}
+		o.store = tokenCache
+	}
+	if cfg.ClientSecret != "" || cfg.CertificatePath != "" {
+		return newAppAuthenticator(cfg, &o)
 	}
 
 	// Create MSAL public client
-	app, err := public.New(cfg.ClientID,
+	publicOpts := []public.Option{
 		public.WithAuthority(fmt.Sprintf("https://login.microsoftonline.com/%s", cfg.TenantID)),
-		public.WithCache(tokenCache),
-	)
+		public.WithCache(storeCache{o.store}),
+	}
+	if o.httpClient != nil {
+		publicOpts = append(publicOpts, public.WithHTTPClient(o.httpClient))
+	}
+	app, err := public.New(cfg.ClientID, publicOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MSAL client: %w", err)
+	}
+
+	return &Authenticator{
+		app:     app,
+		scopes:  cfg.Scopes,
+		store:   o.store,
+		consent: o.consent,
+	}, nil
+}
+
+// newAppAuthenticator creates an authenticator for app-only authentication
+func newAppAuthenticator(cfg AuthConfig, o *authOptions) (*Authenticator, error) {
+	// Client credentials sign in to one tenant; there is no user to pick one
+	if cfg.TenantID == "" || slices.Contains([]string{"common", "organizations", "consumers"}, strings.ToLower(cfg.TenantID)) {
+		return nil, fmt.Errorf("app-only authentication needs a specific tenant ID, not %q", cfg.TenantID)
+	}
+
+	var cred confidential.Credential
+	var thumbprint string
+	if cfg.CertificatePath != "" {
+		certs, key, err := LoadCertificate(cfg.CertificatePath, cfg.CertificatePassword)
+		if err != nil {
+			return nil, err
+		}
+		if cred, err = confidential.NewCredFromCert(certs, key); err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+		thumbprint = CertificateThumbprint(certs[0])
+	} else {
+		var err error
+		if cred, err = confidential.NewCredFromSecret(cfg.ClientSecret); err != nil {
+			return nil, fmt.Errorf("invalid client secret: %w", err)
+		}
+	}
+
+	confidentialOpts := []confidential.Option{confidential.WithCache(storeCache{o.store})}
+	if o.httpClient != nil {
+		confidentialOpts = append(confidentialOpts, confidential.WithHTTPClient(o.httpClient))
+	}
+	app, err := confidential.New(fmt.Sprintf("https://login.microsoftonline.com/%s", cfg.TenantID), cfg.ClientID, cred, confidentialOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create MSAL client: %w", err)
 	}
 
 	return &Authenticator{
-		app:        app,
-		scopes:     cfg.Scopes,
-		tokenCache: tokenCache,
+		appClient:  &app,
+		scopes:     []string{GraphDefaultScope},
+		store:      o.store,
+		clientID:   cfg.ClientID,
+		tenantID:   cfg.TenantID,
+		thumbprint: thumbprint,
 	}, nil
 }
 
+// AppOnly reports whether the authenticator signs in as the app itself
+// rather than as a user
+func (a *Authenticator) AppOnly() bool {
+	return a.appClient != nil
+}
+
+// CertificateThumbprint returns the thumbprint of the certificate used for
+// app-only authentication, or "" when there is none
+func (a *Authenticator) CertificateThumbprint() string {
+	return a.thumbprint
+}
+
+// DeviceCode is what the user needs to finish a device code sign-in: the
+// code to enter at VerificationURL before ExpiresOn. Message says the same
+// in a sentence.
+type DeviceCode struct {
+	UserCode        string    `json:"userCode"`
+	VerificationURL string    `json:"verificationUrl"`
+	ExpiresOn       time.Time `json:"expiresOn"`
+	Message         string    `json:"message"`
+}
+
 // LoginWithDeviceCode performs device code authentication
 func (a *Authenticator) LoginWithDeviceCode(ctx context.Context) error {
+	return a.LoginWithDeviceCodeFunc(ctx, printDeviceCode(os.Stdout))
+}
+
+// LoginWithDeviceCodeFunc is LoginWithDeviceCode for callers that show the
+// device code themselves, such as a chat bot relaying it to the user. show
+// is called once the code is issued; sign-in then waits for the user and
+// fails if show returns an error.
+func (a *Authenticator) LoginWithDeviceCodeFunc(ctx context.Context, show func(*DeviceCode) error) error {
+	if a.AppOnly() {
+		return fmt.Errorf("app-only authentication has no user to sign in")
+	}
+	if a.AzureIdentity() {
+		return errAzureIdentityLogin
+	}
+
+	if _, err := a.deviceCodeFlow(ctx, a.scopes, show); err != nil {
+		return err
+	}
+
+	// Token must not hand out the previous account's token
+	a.cached.clear()
+	return nil
+}
+
+// printDeviceCode returns a show function for deviceCodeFlow that writes
+// the instructions to w
+func printDeviceCode(w io.Writer) func(*DeviceCode) error {
+	return func(code *DeviceCode) error {
+		// Display device code message with chili pepper emoji (m365 CLI style)
+		_, err := fmt.Fprintf(w, "🌶️  %s\n", code.Message)
+		return err
+	}
+}
+
+// deviceCodeFlow signs in for scopes with a device code, passing the
+// instructions to show
+func (a *Authenticator) deviceCodeFlow(ctx context.Context, scopes []string, show func(*DeviceCode) error) (public.AuthResult, error) {
 	// Start device code flow
-	deviceCode, err := a.app.AcquireTokenByDeviceCode(ctx, a.scopes)
+	deviceCode, err := a.app.AcquireTokenByDeviceCode(ctx, scopes)
 	if err != nil {
-		return fmt.Errorf("failed to initiate device code flow: %w", err)
+		return public.AuthResult{}, fmt.Errorf("failed to initiate device code flow: %w", err)
 	}
 
-	// Display device code message with chili pepper emoji (m365 CLI style)
-	fmt.Printf("🌶️  %s\n", deviceCode.Result.Message)
+	err = show(&DeviceCode{
+		UserCode:        deviceCode.Result.UserCode,
+		VerificationURL: deviceCode.Result.VerificationURL,
+		ExpiresOn:       deviceCode.Result.ExpiresOn,
+		Message:         deviceCode.Result.Message,
+	})
+	if err != nil {
+		return public.AuthResult{}, err
+	}
 
 	// Wait for user to complete authentication
-	_, err = deviceCode.AuthenticationResult(ctx)
+	result, err := deviceCode.AuthenticationResult(ctx)
 	if err != nil {
-		return fmt.Errorf("authentication failed: %w", err)
+		return public.AuthResult{}, fmt.Errorf("authentication failed: %w", err)
+	}
+	return result, nil
+}
+
+// LoginWithBrowser signs in through the system browser with the
+// authorization code flow and PKCE. MSAL listens on a localhost port for the
+// redirect, so the app registration needs the "Mobile and desktop
+// applications" redirect URI http://localhost. openURL opens the sign-in
+// page; nil uses MSAL's default browser launcher.
+func (a *Authenticator) LoginWithBrowser(ctx context.Context, openURL func(url string) error) error {
+	if a.AppOnly() {
+		return fmt.Errorf("app-only authentication has no user to sign in")
+	}
+	if a.AzureIdentity() {
+		return errAzureIdentityLogin
 	}
 
+	var opts []public.AcquireInteractiveOption
+	if openURL != nil {
+		opts = append(opts, public.WithOpenURL(openURL))
+	}
+	if _, err := a.app.AcquireTokenInteractive(ctx, a.scopes, opts...); err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	a.cached.clear()
 	return nil
 }
 
 // GetAccessToken retrieves a valid access token, using silent authentication if possible
 func (a *Authenticator) GetAccessToken(ctx context.Context) (string, error) {
+	token, _, err := a.acquireToken(ctx, a.scopes)
+	return token, err
+}
+
+// GetTokenForScopes gets a token for scopes other than the configured ones,
+// such as a narrower set for a script or another resource's. In app-only
+// and Azure identity modes the scope must be a resource's /.default scope.
+// The token isn't kept for Token.
+func (a *Authenticator) GetTokenForScopes(ctx context.Context, scopes []string) (*AccessToken, error) {
+	if len(scopes) == 0 {
+		scopes = a.scopes
+	}
+	token, expires, err := a.acquireToken(ctx, scopes)
+	if err != nil {
+		return nil, err
+	}
+	return &AccessToken{Token: token, ExpiresOn: expires}, nil
+}
+
+// Token implements TokenProvider. It returns the last token acquired until
+// it is close to expiring, then acquires a new one, so a Client built with
+// NewClientWithTokenProvider keeps working however long it runs.
+func (a *Authenticator) Token(ctx context.Context) (string, error) {
+	return a.cached.get(func() (string, time.Time, error) {
+		return a.acquireToken(ctx, a.scopes)
+	})
+}
+
+// acquireToken gets a token for scopes and its expiry from MSAL, which
+// refreshes it if needed
+func (a *Authenticator) acquireToken(ctx context.Context, scopes []string) (string, time.Time, error) {
+	if a.AzureIdentity() {
+		token, err := a.acquireAzureToken(ctx, scopes)
+		return token.Token, token.ExpiresOn, err
+	}
+	if a.AppOnly() {
+		// Cached app tokens are used until they expire
+		result, err := a.appClient.AcquireTokenByCredential(ctx, scopes)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to acquire app-only token: %w", err)
+		}
+		return result.AccessToken, result.ExpiresOn, nil
+	}
+
 	// Try to get all cached accounts
 	accounts, err := a.app.Accounts(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to get accounts: %w", err)
+		return "", time.Time{}, fmt.Errorf("failed to get accounts: %w", err)
 	}
 
 	if len(accounts) == 0 {
-		return "", fmt.Errorf("not authenticated: please login first")
+		return "", time.Time{}, fmt.Errorf("not authenticated: please login first")
 	}
 
 	// Use the first account (most recently used)
 	account := accounts[0]
 
 	// Try silent authentication first
-	result, err := a.app.AcquireTokenSilent(ctx, a.scopes, public.WithSilentAccount(account))
+	result, err := a.app.AcquireTokenSilent(ctx, scopes, public.WithSilentAccount(account))
+	if isConsentRequired(err) {
+		// The refresh token doesn't cover scopes added since sign-in
+		return a.requestConsent(ctx, scopes, err)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to acquire token silently: %w", err)
+		return "", time.Time{}, fmt.Errorf("failed to acquire token silently: %w", err)
 	}
 
-	return result.AccessToken, nil
+	return result.AccessToken, result.ExpiresOn, nil
 }
 
 // Logout removes all cached accounts
 func (a *Authenticator) Logout(ctx context.Context) error {
+	a.cached.clear()
+	if a.AzureIdentity() {
+		// The credentials belong to the Azure CLI or the environment
+		return nil
+	}
+	if a.AppOnly() {
+		return a.store.Delete(ctx)
+	}
+
 	// Get all accounts
 	accounts, err := a.app.Accounts(ctx)
 	if err != nil {
@@ -167,12 +537,24 @@ func (a *Authenticator) Logout(ctx context.Context) error {
 		}
 	}
 
-	// Delete the cache file
-	return a.tokenCache.DeleteCache()
+	// Delete the stored cache
+	return a.store.Delete(ctx)
 }
 
-// IsAuthenticated checks if a valid account exists
+// IsAuthenticated checks if a valid account exists. App-only authenticators
+// always can, as they need no sign-in; a bad secret shows up in
+// GetAccessToken. Azure identity authenticators check that a token can be
+// had.
 func (a *Authenticator) IsAuthenticated(ctx context.Context) bool {
+	if a.AppOnly() {
+		return true
+	}
+	if a.AzureIdentity() {
+		// The only way to know is to ask; Token keeps the result for the
+		// requests that follow
+		_, err := a.Token(ctx)
+		return err == nil
+	}
 	accounts, err := a.app.Accounts(ctx)
 	if err != nil {
 		return false
@@ -180,8 +562,20 @@ func (a *Authenticator) IsAuthenticated(ctx context.Context) bool {
 	return len(accounts) > 0
 }
 
-// GetUserInfo retrieves user information from the token
+// GetUserInfo retrieves user information from the token. For app-only
+// authentication it describes the app instead.
 func (a *Authenticator) GetUserInfo(ctx context.Context) (map[string]interface{}, error) {
+	if a.AppOnly() {
+		return map[string]interface{}{
+			"appId":    a.clientID,
+			"tenantId": a.tenantID,
+			"appOnly":  true,
+		}, nil
+	}
+	if a.AzureIdentity() {
+		return a.azureUserInfo(ctx)
+	}
+
 	accounts, err := a.app.Accounts(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get accounts: %w", err)
@@ -197,5 +591,6 @@ func (a *Authenticator) GetUserInfo(ctx context.Context) (map[string]interface{}
 		"homeAccountId":  account.HomeAccountID,
 		"environment":    account.Environment,
 		"localAccountId": account.LocalAccountID,
+		"tenantId":       account.Realm,
 	}, nil
 }
@@ -0,0 +1,202 @@
+package libgo365
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+)
+
+// memoryStore is a TokenStore kept in memory
+type memoryStore struct {
+	data                 []byte
+	loads, saves, delete int
+}
+
+func (m *memoryStore) Load(ctx context.Context) ([]byte, error) {
+	m.loads++
+	return m.data, nil
+}
+
+func (m *memoryStore) Save(ctx context.Context, data []byte) error {
+	m.saves++
+	m.data = data
+	return nil
+}
+
+func (m *memoryStore) Delete(ctx context.Context) error {
+	m.delete++
+	m.data = nil
+	return nil
+}
+
+type fakeCache struct {
+	data []byte
+}
+
+func (f *fakeCache) Marshal() ([]byte, error) { return f.data, nil }
+
+func (f *fakeCache) Unmarshal(data []byte) error {
+	f.data = data
+	return nil
+}
+
+func TestStoreCache(t *testing.T) {
+	store := &memoryStore{}
+	sc := storeCache{store}
+	ctx := context.Background()
+
+	// Nothing stored yet leaves the MSAL cache alone
+	loaded := &fakeCache{data: []byte("untouched")}
+	if err := sc.Replace(ctx, loaded, cache.ReplaceHints{}); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if string(loaded.data) != "untouched" {
+		t.Errorf("Expected an empty store to be skipped, got %q", loaded.data)
+	}
+
+	if err := sc.Export(ctx, &fakeCache{data: []byte(`{"AccessToken":{}}`)}, cache.ExportHints{}); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if err := sc.Replace(ctx, loaded, cache.ReplaceHints{}); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if string(loaded.data) != `{"AccessToken":{}}` {
+		t.Errorf("Expected the exported cache back, got %q", loaded.data)
+	}
+}
+
+func TestAuthenticatorUsesTokenStore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	store := &memoryStore{}
+	auth, err := NewAuthenticator(AuthConfig{
+		TenantID: "common",
+		ClientID: "00000000-0000-0000-0000-000000000000",
+	}, WithTokenStore(store))
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if auth.IsAuthenticated(ctx) {
+		t.Error("Expected no account in an empty store")
+	}
+	if store.loads == 0 {
+		t.Error("Expected the token store to be read")
+	}
+	if err := auth.Logout(ctx); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if store.delete != 1 {
+		t.Errorf("Expected Logout to delete the stored cache once, got %d", store.delete)
+	}
+
+	if _, err := os.Stat(filepath.Join(home, ".go365", "msal_cache.bin")); !os.IsNotExist(err) {
+		t.Errorf("Expected no cache file with a custom store, got %v", err)
+	}
+}
+
+func TestTokenCacheFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tc, err := NewTokenCache()
+	if err != nil {
+		t.Fatalf("NewTokenCache failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if data, err := tc.Load(ctx); err != nil || data != nil {
+		t.Errorf("Expected nothing stored yet, got %q, %v", data, err)
+	}
+	if err := tc.Save(ctx, []byte("tokens")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	info, err := os.Stat(tc.cachePath)
+	if err != nil {
+		t.Fatalf("Cache file not written: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected 0600, got %v", info.Mode().Perm())
+	}
+	if data, _ := tc.Load(ctx); string(data) != "tokens" {
+		t.Errorf("Expected tokens, got %q", data)
+	}
+	if err := tc.Delete(ctx); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := tc.Delete(ctx); err != nil {
+		t.Errorf("Deleting twice should not fail: %v", err)
+	}
+}
+
+func TestAppOnlyAuthenticator(t *testing.T) {
+	store := &memoryStore{data: []byte("cached")}
+	auth, err := NewAuthenticator(AuthConfig{
+		TenantID:     "contoso.onmicrosoft.com",
+		ClientID:     "00000000-0000-0000-0000-000000000000",
+		Scopes:       []string{"Mail.Read"},
+		ClientSecret: "secret",
+	}, WithTokenStore(store))
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if !auth.AppOnly() || !auth.IsAuthenticated(ctx) {
+		t.Error("Expected an app-only authenticator that needs no sign-in")
+	}
+	if len(auth.scopes) != 1 || auth.scopes[0] != GraphDefaultScope {
+		t.Errorf("Expected the .default scope, got %v", auth.scopes)
+	}
+	if err := auth.LoginWithDeviceCode(ctx); err == nil {
+		t.Error("Expected device code login to be refused")
+	}
+	if err := auth.LoginWithDeviceCodeFunc(ctx, func(*DeviceCode) error {
+		t.Error("Expected no device code for an app")
+		return nil
+	}); err == nil {
+		t.Error("Expected device code login to be refused")
+	}
+	if err := auth.LoginWithBrowser(ctx, nil); err == nil {
+		t.Error("Expected browser login to be refused")
+	}
+
+	info, err := auth.GetUserInfo(ctx)
+	if err != nil || info["appId"] != "00000000-0000-0000-0000-000000000000" || info["tenantId"] != "contoso.onmicrosoft.com" {
+		t.Errorf("Unexpected info %v (%v)", info, err)
+	}
+
+	if err := auth.Logout(ctx); err != nil || store.delete != 1 {
+		t.Errorf("Expected Logout to clear the store, got %v", err)
+	}
+}
+
+func TestAppOnlyNeedsTenant(t *testing.T) {
+	for _, tenant := range []string{"", "common", "Organizations"} {
+		_, err := NewAuthenticator(AuthConfig{
+			TenantID:     tenant,
+			ClientID:     "00000000-0000-0000-0000-000000000000",
+			ClientSecret: "secret",
+		}, WithTokenStore(&memoryStore{}))
+		if err == nil {
+			t.Errorf("Expected tenant %q to be rejected", tenant)
+		}
+	}
+}
+
+func TestPrintDeviceCode(t *testing.T) {
+	var buf bytes.Buffer
+	code := &DeviceCode{UserCode: "ABC123", VerificationURL: "https://microsoft.com/devicelogin", Message: "To sign in, enter the code ABC123"}
+	if err := printDeviceCode(&buf)(code); err != nil {
+		t.Fatalf("printDeviceCode failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "enter the code ABC123") {
+		t.Errorf("Expected the device code message, got %q", buf.String())
+	}
+}
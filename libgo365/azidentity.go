@@ -0,0 +1,117 @@
+package libgo365
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// NewAzureIdentityAuthenticator returns an authenticator that borrows
+// credentials already on the machine instead of signing in itself. It uses
+// azidentity's DefaultAzureCredential chain: the AZURE_* environment
+// variables, workload identity, managed identity, then the Azure CLI ('az
+// login') and Azure Developer CLI. cfg.TenantID picks the tenant for the
+// CLIs unless it is common or similar; ClientID and Scopes are not used, as
+// the token is issued to whichever app the credential belongs to. Of the
+// options, only WithHTTPClient applies.
+func NewAzureIdentityAuthenticator(cfg AuthConfig, opts ...AuthOption) (*Authenticator, error) {
+	var o authOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	tenantID := cfg.TenantID
+	if slices.Contains([]string{"common", "organizations", "consumers"}, strings.ToLower(tenantID)) {
+		tenantID = ""
+	}
+
+	credOpts := &azidentity.DefaultAzureCredentialOptions{TenantID: tenantID}
+	if o.httpClient != nil {
+		credOpts.Transport = o.httpClient
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(credOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+	return newCredentialAuthenticator(cred), nil
+}
+
+// NewManagedIdentityAuthenticator returns an authenticator for the managed
+// identity of the Azure VM, container app or function it runs on, with
+// tokens from the instance metadata service (IMDS) or the App Service
+// identity endpoint. clientID picks a user-assigned identity; empty means
+// the system-assigned one. Managed identities are apps, so tokens carry the
+// application permissions granted to the identity and there is no /me.
+func NewManagedIdentityAuthenticator(clientID string) (*Authenticator, error) {
+	opts := &azidentity.ManagedIdentityCredentialOptions{}
+	if clientID != "" {
+		opts.ID = azidentity.ClientID(clientID)
+	}
+	cred, err := azidentity.NewManagedIdentityCredential(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create managed identity credential: %w", err)
+	}
+	auth := newCredentialAuthenticator(cred)
+	auth.managedIdentity = true
+	auth.clientID = clientID
+	return auth, nil
+}
+
+// newCredentialAuthenticator wraps an azcore credential
+func newCredentialAuthenticator(cred azcore.TokenCredential) *Authenticator {
+	return &Authenticator{
+		azCred: cred,
+		scopes: []string{GraphDefaultScope},
+	}
+}
+
+// AzureIdentity reports whether tokens come from an azidentity credential
+// (see NewAzureIdentityAuthenticator)
+func (a *Authenticator) AzureIdentity() bool {
+	return a.azCred != nil
+}
+
+// ManagedIdentity reports whether tokens come from an Azure managed
+// identity (see NewManagedIdentityAuthenticator)
+func (a *Authenticator) ManagedIdentity() bool {
+	return a.managedIdentity
+}
+
+// acquireAzureToken gets a token for scopes from the azidentity credential
+func (a *Authenticator) acquireAzureToken(ctx context.Context, scopes []string) (azcore.AccessToken, error) {
+	token, err := a.azCred.GetToken(ctx, policy.TokenRequestOptions{Scopes: scopes})
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("failed to get a token from Azure credentials: %w", err)
+	}
+	return token, nil
+}
+
+// azureUserInfo describes the identity behind an azidentity token, read
+// from the token's claims since there is no MSAL account to ask
+func (a *Authenticator) azureUserInfo(ctx context.Context) (map[string]interface{}, error) {
+	token, err := a.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := ParseTokenClaims(token)
+	if err != nil {
+		return nil, err
+	}
+
+	info := map[string]interface{}{
+		"tenantId":       claims.TenantID,
+		"localAccountId": claims.ObjectID,
+		"appId":          claims.AppID,
+	}
+	if claims.Username != "" {
+		info["username"] = claims.Username
+	}
+	if claims.AppOnly() {
+		info["appOnly"] = true
+	}
+	return info, nil
+}
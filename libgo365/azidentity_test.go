@@ -0,0 +1,148 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// fakeCredential returns a JWT with the given claims, or err
+type fakeCredential struct {
+	claims map[string]interface{}
+	err    error
+	calls  int
+	scopes []string
+}
+
+func (f *fakeCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	f.calls++
+	f.scopes = opts.Scopes
+	if f.err != nil {
+		return azcore.AccessToken{}, f.err
+	}
+	payload, _ := json.Marshal(f.claims)
+	token := "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+	return azcore.AccessToken{Token: token, ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestAzureIdentityAuthenticator(t *testing.T) {
+	cred := &fakeCredential{claims: map[string]interface{}{
+		"upn": "alex@contoso.com",
+		"tid": "tenant-id",
+		"oid": "object-id",
+		"scp": "User.Read",
+	}}
+	auth := newCredentialAuthenticator(cred)
+	ctx := context.Background()
+
+	if !auth.AzureIdentity() || auth.AppOnly() {
+		t.Error("Expected an Azure identity authenticator")
+	}
+	if !auth.IsAuthenticated(ctx) {
+		t.Error("Expected a working credential to count as authenticated")
+	}
+	if len(cred.scopes) != 1 || cred.scopes[0] != GraphDefaultScope {
+		t.Errorf("Expected the .default scope, got %v", cred.scopes)
+	}
+
+	info, err := auth.GetUserInfo(ctx)
+	if err != nil {
+		t.Fatalf("GetUserInfo failed: %v", err)
+	}
+	if info["username"] != "alex@contoso.com" || info["tenantId"] != "tenant-id" || info["appOnly"] != nil {
+		t.Errorf("Unexpected info %v", info)
+	}
+	if cred.calls != 1 {
+		t.Errorf("Expected the token to be reused, got %d calls", cred.calls)
+	}
+
+	if err := auth.LoginWithDeviceCode(ctx); !errors.Is(err, errAzureIdentityLogin) {
+		t.Errorf("Expected device code login to be refused, got %v", err)
+	}
+	if err := auth.LoginWithBrowser(ctx, nil); !errors.Is(err, errAzureIdentityLogin) {
+		t.Errorf("Expected browser login to be refused, got %v", err)
+	}
+	if err := auth.Logout(ctx); err != nil {
+		t.Errorf("Logout failed: %v", err)
+	}
+}
+
+func TestGetTokenForScopes(t *testing.T) {
+	cred := &fakeCredential{claims: map[string]interface{}{"scp": "User.Read"}}
+	auth := newCredentialAuthenticator(cred)
+
+	token, err := auth.GetTokenForScopes(context.Background(), []string{"https://management.azure.com/.default"})
+	if err != nil {
+		t.Fatalf("GetTokenForScopes failed: %v", err)
+	}
+	if token.Token == "" || token.ExpiresOn.IsZero() {
+		t.Errorf("Expected a token with an expiry, got %+v", token)
+	}
+	if len(cred.scopes) != 1 || cred.scopes[0] != "https://management.azure.com/.default" {
+		t.Errorf("Expected the requested scope, got %v", cred.scopes)
+	}
+
+	if _, err := auth.GetTokenForScopes(context.Background(), nil); err != nil || cred.scopes[0] != GraphDefaultScope {
+		t.Errorf("Expected the default scope without scopes, got %v (%v)", cred.scopes, err)
+	}
+}
+
+func TestAzureIdentityManagedIdentity(t *testing.T) {
+	auth := newCredentialAuthenticator(&fakeCredential{claims: map[string]interface{}{
+		"appid": "app-id",
+		"tid":   "tenant-id",
+		"roles": []string{"Mail.Read"},
+	}})
+
+	info, err := auth.GetUserInfo(context.Background())
+	if err != nil {
+		t.Fatalf("GetUserInfo failed: %v", err)
+	}
+	if info["appOnly"] != true || info["appId"] != "app-id" || info["username"] != nil {
+		t.Errorf("Expected an app-only identity, got %v", info)
+	}
+}
+
+func TestAzureIdentityNoCredentials(t *testing.T) {
+	failure := errors.New("az: not logged in")
+	auth := newCredentialAuthenticator(&fakeCredential{err: failure})
+
+	if auth.IsAuthenticated(context.Background()) {
+		t.Error("Expected a failing credential to count as signed out")
+	}
+	if _, err := auth.GetAccessToken(context.Background()); !errors.Is(err, failure) {
+		t.Errorf("Expected the credential's error, got %v", err)
+	}
+}
+
+func TestManagedIdentityAuthenticator(t *testing.T) {
+	auth, err := NewManagedIdentityAuthenticator("identity-client-id")
+	if err != nil {
+		t.Fatalf("NewManagedIdentityAuthenticator failed: %v", err)
+	}
+	if !auth.ManagedIdentity() || !auth.AzureIdentity() || auth.AppOnly() {
+		t.Error("Expected a managed identity authenticator")
+	}
+	if auth.AddScopes("Mail.Send") {
+		t.Error("Expected a managed identity to ignore extra scopes")
+	}
+
+	// The identity's own client ID shows even when no token can be had
+	auth.azCred = &fakeCredential{err: errors.New("no IMDS endpoint")}
+	st := auth.Status(context.Background())
+	if st.Mode != AuthModeManagedIdentity || st.AppID != "identity-client-id" || st.Authenticated {
+		t.Errorf("Unexpected status %+v", st)
+	}
+
+	auth.azCred = &fakeCredential{claims: map[string]interface{}{"tid": "tenant-id", "appid": "token-app-id", "roles": []string{"Mail.Read"}}}
+	st = auth.Status(context.Background())
+	if !st.Authenticated || st.AccountType != AccountApp || st.AppID != "token-app-id" {
+		t.Errorf("Expected an app status from the token, got %+v", st)
+	}
+}
@@ -0,0 +1,811 @@
+package libgo365
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Backup kinds. Each is kept in its own directory with its own manifest.
+const (
+	BackupMail     = "mail"
+	BackupCalendar = "calendar"
+	BackupDrive    = "drive"
+)
+
+// BackupManifestFile is the manifest's name inside a backup directory
+const BackupManifestFile = "manifest.json"
+
+// backupPageSize is the page size asked of delta queries. Each page is
+// checkpointed, so this is also the most work a resumed backup repeats.
+const backupPageSize = "odata.maxpagesize=100"
+
+// maxSimpleUpload is the largest file Graph accepts in a single PUT
+const maxSimpleUpload = 250 << 20
+
+// BackupManifest records what a backup directory holds and where each delta
+// query left off, so an interrupted or later run only fetches what it
+// doesn't have yet
+type BackupManifest struct {
+	Kind    string    `json:"kind"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+
+	// Cursors maps each delta query (a mail folder ID, "calendar" or
+	// "drive") to the link to continue it from: a next link part-way
+	// through a run, or a delta link once it has caught up
+	Cursors map[string]string `json:"cursors"`
+
+	Items map[string]*BackupItem `json:"items"`
+}
+
+// BackupItem is one message, event, file or drive folder in a backup. Paths
+// are relative to the backup directory and use forward slashes.
+type BackupItem struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name,omitempty"`    // Subject or file name
+	File     string     `json:"file,omitempty"`    // The .eml, .ics or file; empty for drive folders
+	Data     string     `json:"data,omitempty"`    // Graph's JSON for an event, used to restore it
+	Folder   string     `json:"folder,omitempty"`  // Mail folder name
+	Folders  []string   `json:"folders,omitempty"` // Mail folder path, e.g. ["Inbox", "Receipts"]
+	Path     string     `json:"path,omitempty"`    // Drive path, e.g. "Documents/notes.txt"
+	IsFolder bool       `json:"isFolder,omitempty"`
+	Size     int64      `json:"size,omitempty"`
+	Modified *time.Time `json:"modified,omitempty"`
+}
+
+// BackupOptions configures a backup
+type BackupOptions struct {
+	Dir     string
+	Folders []string  // Mail folder IDs or well-known names; every folder in the mailbox if empty
+	Start   time.Time // Calendar window; ten years ago if zero
+	End     time.Time // Calendar window; two years ahead if zero
+
+	// Progress, if set, is called after each item is saved
+	Progress func(item *BackupItem)
+}
+
+// BackupStats counts what a backup or restore did
+type BackupStats struct {
+	Saved     int `json:"saved"`
+	Unchanged int `json:"unchanged"`
+	Removed   int `json:"removed"`
+}
+
+// LoadBackupManifest reads the manifest in dir, or starts a new one if the
+// directory has no backup yet
+func LoadBackupManifest(dir, kind string) (*BackupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, BackupManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		now := time.Now().UTC()
+		return &BackupManifest{
+			Kind:    kind,
+			Created: now,
+			Updated: now,
+			Cursors: map[string]string{},
+			Items:   map[string]*BackupItem{},
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup manifest: %w", err)
+	}
+
+	var m BackupManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse backup manifest: %w", err)
+	}
+	if m.Kind != kind {
+		return nil, fmt.Errorf("%s holds a %s backup, not %s", dir, m.Kind, kind)
+	}
+	if m.Cursors == nil {
+		m.Cursors = map[string]string{}
+	}
+	if m.Items == nil {
+		m.Items = map[string]*BackupItem{}
+	}
+	return &m, nil
+}
+
+// Save writes the manifest to dir. It is written to a temporary file first so
+// an interrupted save leaves the previous manifest intact.
+func (m *BackupManifest) Save(dir string) error {
+	m.Updated = time.Now().UTC()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal backup manifest: %w", err)
+	}
+	return writeBackupFile(dir, BackupManifestFile, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// sortedItems returns the items that have a file, in file order
+func (m *BackupManifest) sortedItems() []*BackupItem {
+	var items []*BackupItem
+	for _, item := range m.Items {
+		if item.File != "" {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].File < items[j].File })
+	return items
+}
+
+// remove drops an item and its files
+func (m *BackupManifest) remove(dir, id string) bool {
+	item, ok := m.Items[id]
+	if !ok {
+		return false
+	}
+	for _, file := range []string{item.File, item.Data} {
+		if file != "" {
+			os.Remove(filepath.Join(dir, filepath.FromSlash(file)))
+		}
+	}
+	if item.IsFolder && item.Path != "" {
+		// Only succeeds once the folder's files are gone, which Graph
+		// reports alongside it
+		os.Remove(filepath.Join(dir, "files", filepath.FromSlash(item.Path)))
+	}
+	delete(m.Items, id)
+	return true
+}
+
+// hasFile reports whether an item's file is still on disk
+func (m *BackupManifest) hasFile(dir, id string) bool {
+	item, ok := m.Items[id]
+	if !ok || item.File == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(item.File)))
+	return err == nil
+}
+
+// deltaItem is the part of a mail or calendar delta query item that says
+// whether it was removed
+type deltaItem struct {
+	ID      string          `json:"id"`
+	Removed json.RawMessage `json:"@removed,omitempty"`
+}
+
+// backupDelta runs the delta query for key from where the manifest left off,
+// or from start, and calls fn with each item. The manifest is saved after
+// every page, so a backup that stops part-way resumes at the page it was on.
+// If Graph has expired the saved delta link, the query starts over.
+func (c *Client) backupDelta(ctx context.Context, m *BackupManifest, dir, key, start string, fn func(item json.RawMessage) error) error {
+	client := c.uncached()
+	link := m.Cursors[key]
+	if link == "" {
+		link = start
+	}
+
+	for {
+		data, err := client.Get(ctx, client.relativePath(link), WithPrefer(backupPageSize))
+		if errors.Is(err, ErrGone) && link != start {
+			link = start
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		var page struct {
+			Value     []json.RawMessage `json:"value"`
+			NextLink  string            `json:"@odata.nextLink,omitempty"`
+			DeltaLink string            `json:"@odata.deltaLink,omitempty"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("failed to unmarshal delta page: %w", err)
+		}
+
+		for _, item := range page.Value {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+
+		if page.NextLink == "" {
+			m.Cursors[key] = page.DeltaLink
+		} else {
+			m.Cursors[key] = page.NextLink
+		}
+		if err := m.Save(dir); err != nil {
+			return err
+		}
+		if page.NextLink == "" {
+			return nil
+		}
+		link = page.NextLink
+	}
+}
+
+// backupFolder is a mail folder to back up and its path from the top of the
+// mailbox
+type backupFolder struct {
+	ID   string
+	Path []string
+}
+
+// backupMailFolders lists every folder in the mailbox, parents before their
+// subfolders
+func (c *Client) backupMailFolders(ctx context.Context, parentID string, parent []string) ([]*backupFolder, error) {
+	children, err := c.MailFolderIterator(parentID).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var folders []*backupFolder
+	for _, child := range children {
+		folder := &backupFolder{ID: child.ID, Path: append(slices.Clip(parent), child.DisplayName)}
+		folders = append(folders, folder)
+		if child.ChildFolderCount == 0 {
+			continue
+		}
+		sub, err := c.backupMailFolders(ctx, child.ID, folder.Path)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, sub...)
+	}
+	return folders, nil
+}
+
+// BackupMail saves each message in opts.Folders, or in every folder and
+// subfolder of the mailbox, as an .eml file, fetching only messages that are
+// new since the last backup into opts.Dir. Messages deleted from the mailbox
+// are deleted from the backup.
+func (c *Client) BackupMail(ctx context.Context, opts *BackupOptions) (*BackupStats, error) {
+	m, err := LoadBackupManifest(opts.Dir, BackupMail)
+	if err != nil {
+		return nil, err
+	}
+
+	var folders []*backupFolder
+	if len(opts.Folders) == 0 {
+		if folders, err = c.backupMailFolders(ctx, "", nil); err != nil {
+			return nil, fmt.Errorf("failed to list mail folders: %w", err)
+		}
+	}
+	for _, name := range opts.Folders {
+		folders = append(folders, &backupFolder{ID: name, Path: []string{name}})
+	}
+
+	stats := &BackupStats{}
+	for _, folder := range folders {
+		segments := make([]string, len(folder.Path))
+		for i, name := range folder.Path {
+			segments[i] = SafeFileName(name, "folder")
+		}
+		dirName := path.Join(segments...)
+		start := fmt.Sprintf("/me/mailFolders/%s/messages/delta?$select=id,subject,receivedDateTime", url.PathEscape(folder.ID))
+
+		err := c.backupDelta(ctx, m, opts.Dir, folder.ID, start, func(raw json.RawMessage) error {
+			var msg struct {
+				deltaItem
+				Subject          string     `json:"subject"`
+				ReceivedDateTime *time.Time `json:"receivedDateTime"`
+			}
+			if err := json.Unmarshal(raw, &msg); err != nil {
+				return fmt.Errorf("failed to unmarshal message: %w", err)
+			}
+
+			if msg.Removed != nil {
+				if m.remove(opts.Dir, msg.ID) {
+					stats.Removed++
+				}
+				return nil
+			}
+			// A message's MIME content never changes, only its flags
+			if m.hasFile(opts.Dir, msg.ID) {
+				stats.Unchanged++
+				return nil
+			}
+
+			item := &BackupItem{
+				ID:       msg.ID,
+				Name:     msg.Subject,
+				File:     path.Join(dirName, backupFileName(msg.ID)+".eml"),
+				Folder:   folder.Path[len(folder.Path)-1],
+				Folders:  folder.Path,
+				Modified: msg.ReceivedDateTime,
+			}
+			err := writeBackupFile(opts.Dir, item.File, func(w io.Writer) error {
+				n, err := c.DownloadMessageMIME(ctx, msg.ID, w)
+				item.Size = n
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save message %q: %w", msg.Subject, err)
+			}
+			return m.saved(item, stats, opts)
+		})
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// BackupCalendar saves the events between opts.Start and opts.End, each as an
+// .ics file and as Graph's JSON for restoring. Recurring events are saved
+// occurrence by occurrence.
+func (c *Client) BackupCalendar(ctx context.Context, opts *BackupOptions) (*BackupStats, error) {
+	m, err := LoadBackupManifest(opts.Dir, BackupCalendar)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	startTime, endTime := opts.Start, opts.End
+	if startTime.IsZero() {
+		startTime = now.AddDate(-10, 0, 0)
+	}
+	if endTime.IsZero() {
+		endTime = now.AddDate(2, 0, 0)
+	}
+	query := url.Values{}
+	query.Set("startDateTime", startTime.UTC().Format(time.RFC3339))
+	query.Set("endDateTime", endTime.UTC().Format(time.RFC3339))
+	start := "/me/calendarView/delta?" + query.Encode()
+
+	// A different window needs a fresh delta query
+	key := BackupCalendar + "?" + query.Encode()
+	for k := range m.Cursors {
+		if k != key {
+			delete(m.Cursors, k)
+		}
+	}
+
+	stats := &BackupStats{}
+	err = c.backupDelta(ctx, m, opts.Dir, key, start, func(raw json.RawMessage) error {
+		var removed deltaItem
+		if err := json.Unmarshal(raw, &removed); err != nil {
+			return fmt.Errorf("failed to unmarshal event: %w", err)
+		}
+		if removed.Removed != nil {
+			if m.remove(opts.Dir, removed.ID) {
+				stats.Removed++
+			}
+			return nil
+		}
+
+		var event Event
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal event: %w", err)
+		}
+
+		name := backupFileName(event.ID)
+		item := &BackupItem{
+			ID:   event.ID,
+			Name: event.Subject,
+			File: name + ".ics",
+			Data: name + ".json",
+			Size: int64(len(raw)),
+		}
+		err := writeBackupFile(opts.Dir, item.File, func(w io.Writer) error {
+			return WriteICS(w, &event)
+		})
+		if err == nil {
+			err = writeBackupFile(opts.Dir, item.Data, func(w io.Writer) error {
+				_, err := w.Write(raw)
+				return err
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save event %q: %w", event.Subject, err)
+		}
+		return m.saved(item, stats, opts)
+	})
+	return stats, err
+}
+
+// BackupDrive saves the files in the signed-in user's OneDrive under
+// files/ in opts.Dir, keeping the folder layout. Files that haven't changed
+// since the last backup are not downloaded again; moved and renamed ones are
+// moved locally.
+func (c *Client) BackupDrive(ctx context.Context, opts *BackupOptions) (*BackupStats, error) {
+	m, err := LoadBackupManifest(opts.Dir, BackupDrive)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &BackupStats{}
+	err = c.backupDelta(ctx, m, opts.Dir, BackupDrive, "/me/drive/root/delta", func(raw json.RawMessage) error {
+		var driveItem struct {
+			DriveItem
+			Deleted json.RawMessage `json:"deleted,omitempty"`
+			Root    json.RawMessage `json:"root,omitempty"`
+		}
+		if err := json.Unmarshal(raw, &driveItem); err != nil {
+			return fmt.Errorf("failed to unmarshal drive item: %w", err)
+		}
+		d := &driveItem.DriveItem
+
+		switch {
+		case driveItem.Deleted != nil:
+			if m.remove(opts.Dir, d.ID) {
+				stats.Removed++
+			}
+			return nil
+		case driveItem.Root != nil:
+			m.Items[d.ID] = &BackupItem{ID: d.ID, IsFolder: true}
+			return nil
+		}
+
+		itemPath, err := m.drivePath(d)
+		if err != nil {
+			return err
+		}
+		previous := m.Items[d.ID]
+
+		if d.IsFolder() {
+			if previous != nil && previous.Path != itemPath {
+				if err := m.moveDriveFolder(opts.Dir, previous.Path, itemPath); err != nil {
+					return err
+				}
+			}
+			m.Items[d.ID] = &BackupItem{ID: d.ID, Name: d.Name, Path: itemPath, IsFolder: true, Modified: d.LastModifiedDateTime}
+			return nil
+		}
+		if d.File == nil {
+			// Packages such as OneNote notebooks have no content to download
+			return nil
+		}
+
+		item := &BackupItem{
+			ID:       d.ID,
+			Name:     d.Name,
+			File:     "files/" + itemPath,
+			Path:     itemPath,
+			Size:     d.Size,
+			Modified: d.LastModifiedDateTime,
+		}
+		if previous != nil && m.hasFile(opts.Dir, d.ID) && sameTime(previous.Modified, d.LastModifiedDateTime) && previous.Size == d.Size {
+			if previous.File != item.File {
+				if err := moveBackupFile(opts.Dir, previous.File, item.File); err != nil {
+					return err
+				}
+			}
+			m.Items[d.ID] = item
+			stats.Unchanged++
+			return nil
+		}
+		if previous != nil && previous.File != item.File {
+			m.remove(opts.Dir, d.ID)
+		}
+
+		err = writeBackupFile(opts.Dir, item.File, func(w io.Writer) error {
+			_, err := c.Download(ctx, fmt.Sprintf("/me/drive/items/%s/content", url.PathEscape(d.ID)), w)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save %s: %w", itemPath, err)
+		}
+		return m.saved(item, stats, opts)
+	})
+	return stats, err
+}
+
+// saved records an item that has just been written
+func (m *BackupManifest) saved(item *BackupItem, stats *BackupStats, opts *BackupOptions) error {
+	m.Items[item.ID] = item
+	stats.Saved++
+	if opts.Progress != nil {
+		opts.Progress(item)
+	}
+	return nil
+}
+
+// drivePath returns an item's path within the drive, from its parent
+// folder's entry in the manifest. Delta queries list folders before their
+// contents, so the parent is normally known; if not, the parent reference's
+// path is used.
+func (m *BackupManifest) drivePath(d *DriveItem) (string, error) {
+	parent := ""
+	if ref := d.ParentReference; ref != nil {
+		if p, ok := m.Items[ref.ID]; ok {
+			parent = p.Path
+		} else if i := strings.Index(ref.Path, "root:"); i >= 0 {
+			parent = strings.Trim(ref.Path[i+len("root:"):], "/")
+		}
+	}
+
+	p := path.Join(parent, d.Name)
+	if !filepath.IsLocal(filepath.FromSlash(p)) {
+		return "", fmt.Errorf("drive item %q has an unsafe path %q", d.ID, p)
+	}
+	return p, nil
+}
+
+// moveDriveFolder moves a renamed or moved folder's files and updates the
+// paths of everything inside it
+func (m *BackupManifest) moveDriveFolder(dir, from, to string) error {
+	if err := moveBackupFile(dir, "files/"+from, "files/"+to); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, item := range m.Items {
+		if rest, ok := strings.CutPrefix(item.Path, from+"/"); ok {
+			item.Path = path.Join(to, rest)
+			if item.File != "" {
+				item.File = "files/" + item.Path
+			}
+		}
+	}
+	return nil
+}
+
+// RestoreOptions configures a restore
+type RestoreOptions struct {
+	Dir string
+
+	// Target is where restored items go: the name of a new mail folder
+	// (with a subfolder for each backed-up folder), a calendar ID, or a
+	// drive folder path. Mail and drive default to "Restored YYYY-MM-DD";
+	// calendar defaults to the main calendar.
+	Target string
+
+	// Progress, if set, is called after each item is restored
+	Progress func(item *BackupItem)
+}
+
+// defaultRestoreTarget names the folder a restore goes into when no target is given
+func defaultRestoreTarget(target string) string {
+	if target != "" {
+		return target
+	}
+	return "Restored " + time.Now().Format("2006-01-02")
+}
+
+// RestoreMail imports the messages in a mail backup into a new folder,
+// recreating the backed-up folder tree inside it. Graph imports MIME messages as
+// drafts, so restored messages can't be sent on as they are.
+func (c *Client) RestoreMail(ctx context.Context, opts *RestoreOptions) (*BackupStats, error) {
+	m, err := LoadBackupManifest(opts.Dir, BackupMail)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := c.CreateMailFolder(ctx, "", defaultRestoreTarget(opts.Target))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mail folder: %w", err)
+	}
+
+	stats := &BackupStats{}
+	folders := map[string]string{"": root.ID}
+	for _, item := range m.sortedItems() {
+		folderPath := item.Folders
+		if len(folderPath) == 0 {
+			// Backups from before folder paths were recorded
+			folderPath = []string{item.Folder}
+		}
+		folderID, err := c.restoreMailFolder(ctx, folders, folderPath)
+		if err != nil {
+			return stats, err
+		}
+
+		mime, err := os.ReadFile(filepath.Join(opts.Dir, filepath.FromSlash(item.File)))
+		if err != nil {
+			return stats, fmt.Errorf("failed to read %s: %w", item.File, err)
+		}
+		body := strings.NewReader(base64.StdEncoding.EncodeToString(mime))
+		if _, err := c.PostContent(ctx, fmt.Sprintf("/me/mailFolders/%s/messages", folderID), "text/plain", body); err != nil {
+			return stats, fmt.Errorf("failed to restore message %q: %w", item.Name, err)
+		}
+
+		stats.Saved++
+		if opts.Progress != nil {
+			opts.Progress(item)
+		}
+	}
+
+	return stats, nil
+}
+
+// restoreMailFolder returns the ID of the restored folder at folderPath,
+// creating it and any missing parents. folders maps the paths created so far,
+// joined with NUL, to their IDs.
+func (c *Client) restoreMailFolder(ctx context.Context, folders map[string]string, folderPath []string) (string, error) {
+	key := strings.Join(folderPath, "\x00")
+	if id, ok := folders[key]; ok {
+		return id, nil
+	}
+
+	parentID, err := c.restoreMailFolder(ctx, folders, folderPath[:len(folderPath)-1])
+	if err != nil {
+		return "", err
+	}
+	name := folderPath[len(folderPath)-1]
+	folder, err := c.CreateMailFolder(ctx, parentID, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to create mail folder %q: %w", strings.Join(folderPath, "/"), err)
+	}
+	folders[key] = folder.ID
+	return folder.ID, nil
+}
+
+// RestoreCalendar recreates the events in a calendar backup, MaxBatchSize at a
+// time, sending throttled ones again. Attendees are left off so restoring doesn't send invitations.
+func (c *Client) RestoreCalendar(ctx context.Context, opts *RestoreOptions) (*BackupStats, error) {
+	m, err := LoadBackupManifest(opts.Dir, BackupCalendar)
+	if err != nil {
+		return nil, err
+	}
+
+	eventsPath := "/me/events"
+	if opts.Target != "" {
+		eventsPath = fmt.Sprintf("/me/calendars/%s/events", opts.Target)
+	}
+
+	items := m.sortedItems()
+	requests := make([]*BatchRequest, 0, len(items))
+	for _, item := range items {
+		data, err := os.ReadFile(filepath.Join(opts.Dir, filepath.FromSlash(item.Data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", item.Data, err)
+		}
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", item.Data, err)
+		}
+		requests = append(requests, &BatchRequest{Method: "POST", URL: eventsPath, Body: restorableEvent(&event)})
+	}
+
+	responses, err := c.BatchWithRetry(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &BackupStats{}
+	var errs []error
+	for i, resp := range responses {
+		if err := resp.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore event %q: %w", items[i].Name, err))
+			continue
+		}
+		stats.Saved++
+		if opts.Progress != nil {
+			opts.Progress(items[i])
+		}
+	}
+	return stats, errors.Join(errs...)
+}
+
+// restorableEvent copies the fields of event that can be set on a new one
+func restorableEvent(event *Event) *Event {
+	return &Event{
+		Subject:     event.Subject,
+		Start:       event.Start,
+		End:         event.End,
+		IsAllDay:    event.IsAllDay,
+		Location:    event.Location,
+		Importance:  event.Importance,
+		Sensitivity: event.Sensitivity,
+		ShowAs:      event.ShowAs,
+		Body:        event.Body,
+	}
+}
+
+// RestoreDrive uploads the files in a drive backup into a folder of the
+// signed-in user's OneDrive, keeping the folder layout. Files over 250 MB
+// are too large for a single upload and are reported as errors.
+func (c *Client) RestoreDrive(ctx context.Context, opts *RestoreOptions) (*BackupStats, error) {
+	m, err := LoadBackupManifest(opts.Dir, BackupDrive)
+	if err != nil {
+		return nil, err
+	}
+	target := strings.Trim(defaultRestoreTarget(opts.Target), "/")
+
+	stats := &BackupStats{}
+	for _, item := range m.sortedItems() {
+		if item.Size > maxSimpleUpload {
+			return stats, fmt.Errorf("%s is too large to restore (over 250 MB)", item.Path)
+		}
+
+		f, err := os.Open(filepath.Join(opts.Dir, filepath.FromSlash(item.File)))
+		if err != nil {
+			return stats, fmt.Errorf("failed to read %s: %w", item.File, err)
+		}
+		uploadPath := fmt.Sprintf("/me/drive/root:/%s:/content", escapeDrivePath(path.Join(target, item.Path)))
+		_, err = c.PutContent(ctx, uploadPath, "application/octet-stream", f)
+		f.Close()
+		if err != nil {
+			return stats, fmt.Errorf("failed to restore %s: %w", item.Path, err)
+		}
+
+		stats.Saved++
+		if opts.Progress != nil {
+			opts.Progress(item)
+		}
+	}
+
+	return stats, nil
+}
+
+// escapeDrivePath escapes each segment of a drive path
+func escapeDrivePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// writeBackupFile writes a file in dir through a temporary file, so an
+// interrupted download never leaves a partial file under the real name
+func writeBackupFile(dir, name string, write func(w io.Writer) error) error {
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(target), ".go365-*.part")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), target)
+}
+
+// moveBackupFile moves a file or folder within dir
+func moveBackupFile(dir, from, to string) error {
+	target := filepath.Join(dir, filepath.FromSlash(to))
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return err
+	}
+	return os.Rename(filepath.Join(dir, filepath.FromSlash(from)), target)
+}
+
+// backupFileName is a short, stable file name for a Graph ID, which can be
+// too long or contain characters file systems reject
+func backupFileName(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SafeFileName makes a subject or folder name safe to use as a file name,
+// replacing characters file systems reject. fallback is used if nothing is
+// left.
+func SafeFileName(name, fallback string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// sameTime reports whether two optional times are equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,428 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// deltaServer serves a delta query as a list of rounds: each run of the
+// backup reads one round, whose pages it follows to a delta link
+type deltaServer struct {
+	mu      sync.Mutex
+	rounds  [][]string // JSON "value" arrays, one per page
+	content map[string]string
+	prefer  []string
+	posted  []*http.Request
+	bodies  []string
+
+	throttle int // $batch calls to answer with throttled sub-responses
+}
+
+func (s *deltaServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == "POST" || r.Method == "PUT" {
+		body, _ := io.ReadAll(r.Body)
+		s.posted = append(s.posted, r)
+		s.bodies = append(s.bodies, string(body))
+		switch {
+		case r.URL.Path == "/$batch":
+			var batch struct {
+				Requests []*BatchRequest `json:"requests"`
+			}
+			json.Unmarshal(body, &batch)
+			var responses []string
+			for _, req := range batch.Requests {
+				if s.throttle > 0 {
+					responses = append(responses, `{"id": "`+req.ID+`", "status": 429, "headers": {"Retry-After": "0"}}`)
+					continue
+				}
+				responses = append(responses, `{"id": "`+req.ID+`", "status": 201, "body": {}}`)
+			}
+			s.throttle--
+			w.Write([]byte(`{"responses": [` + strings.Join(responses, ",") + `]}`))
+		case strings.HasSuffix(r.URL.Path, "Folders"):
+			w.Write([]byte(fmt.Sprintf(`{"id": "folder-%d"}`, len(s.posted))))
+		default:
+			w.Write([]byte(`{}`))
+		}
+		return
+	}
+
+	if !strings.HasSuffix(r.URL.Path, "/delta") {
+		content, ok := s.content[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"code": "ErrorItemNotFound", "message": "Not found"}}`))
+			return
+		}
+		w.Write([]byte(content))
+		return
+	}
+
+	s.prefer = append(s.prefer, r.Header.Get("Prefer"))
+	round, page := 0, 0
+	fmt.Sscanf(r.URL.Query().Get("token"), "%d-%d", &round, &page)
+	if round >= len(s.rounds) {
+		w.Write([]byte(`{"value": [], "@odata.deltaLink": "` + s.link(round, 0) + `"}`))
+		return
+	}
+
+	pages := s.rounds[round]
+	resp := `{"value": ` + pages[page]
+	if page+1 < len(pages) {
+		resp += `, "@odata.nextLink": "` + s.link(round, page+1) + `"}`
+	} else {
+		resp += `, "@odata.deltaLink": "` + s.link(round+1, 0) + `"}`
+	}
+	w.Write([]byte(resp))
+}
+
+// link is an absolute next or delta link, as Graph returns them
+func (s *deltaServer) link(round, page int) string {
+	return fmt.Sprintf("%s/delta?token=%d-%d", GraphAPIBaseURL, round, page)
+}
+
+func newBackupClient(t *testing.T, s *deltaServer) *Client {
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+	return client
+}
+
+func TestBackupMail(t *testing.T) {
+	s := &deltaServer{
+		rounds: [][]string{
+			{
+				`[{"id": "m1", "subject": "First"}]`,
+				`[{"id": "m2", "subject": "Second"}]`,
+			},
+			{
+				`[{"id": "m1", "@removed": {"reason": "deleted"}}, {"id": "m2", "subject": "Second"}, {"id": "m3", "subject": "Third"}]`,
+			},
+		},
+		content: map[string]string{
+			"/me/messages/m1/$value": "Subject: First\r\n\r\none",
+			"/me/messages/m2/$value": "Subject: Second\r\n\r\ntwo",
+			"/me/messages/m3/$value": "Subject: Third\r\n\r\nthree",
+		},
+	}
+	client := newBackupClient(t, s)
+	dir := t.TempDir()
+	ctx := context.Background()
+	opts := &BackupOptions{Dir: dir, Folders: []string{"inbox"}}
+
+	stats, err := client.BackupMail(ctx, opts)
+	if err != nil {
+		t.Fatalf("BackupMail failed: %v", err)
+	}
+	if stats.Saved != 2 {
+		t.Errorf("Expected 2 messages saved, got %+v", stats)
+	}
+	if s.prefer[0] != backupPageSize {
+		t.Errorf("Expected a page size preference, got %q", s.prefer[0])
+	}
+
+	m, err := LoadBackupManifest(dir, BackupMail)
+	if err != nil {
+		t.Fatalf("LoadBackupManifest failed: %v", err)
+	}
+	m2 := m.Items["m2"]
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(m2.File)))
+	if err != nil || string(data) != "Subject: Second\r\n\r\ntwo" {
+		t.Fatalf("Unexpected EML %q (%v)", data, err)
+	}
+	if m2.Folder != "inbox" || m2.Size != int64(len(data)) || !strings.HasSuffix(m2.File, ".eml") {
+		t.Errorf("Unexpected item %+v", m2)
+	}
+	if m.Cursors["inbox"] != s.link(1, 0) {
+		t.Errorf("Expected the delta link to be saved, got %q", m.Cursors["inbox"])
+	}
+	m1File := filepath.Join(dir, filepath.FromSlash(m.Items["m1"].File))
+
+	// The next run only sees the changes
+	stats, err = client.BackupMail(ctx, opts)
+	if err != nil {
+		t.Fatalf("BackupMail failed: %v", err)
+	}
+	if stats.Saved != 1 || stats.Unchanged != 1 || stats.Removed != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if _, err := os.Stat(m1File); !os.IsNotExist(err) {
+		t.Errorf("Expected the deleted message's file to be removed, got %v", err)
+	}
+}
+
+func TestBackupMailFolderTree(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/me/mailFolders" && r.URL.Query().Get("$skiptoken") == "":
+			fmt.Fprintf(w, `{"value": [{"id": "f-inbox", "displayName": "Inbox", "childFolderCount": 1}],
+				"@odata.nextLink": "%s/me/mailFolders?$top=100&$skiptoken=2"}`, server.URL)
+		case r.URL.Path == "/me/mailFolders":
+			fmt.Fprint(w, `{"value": [{"id": "f-archive", "displayName": "Archive"}]}`)
+		case r.URL.Path == "/me/mailFolders/f-inbox/childFolders":
+			fmt.Fprint(w, `{"value": [{"id": "f-receipts", "displayName": "Receipts: 2024"}]}`)
+		case r.URL.Path == "/me/mailFolders/f-receipts/messages/delta":
+			fmt.Fprint(w, `{"value": [{"id": "m1", "subject": "Paid"}], "@odata.deltaLink": "https://graph.microsoft.com/v1.0/delta?token=1"}`)
+		case strings.HasSuffix(r.URL.Path, "/messages/delta"):
+			fmt.Fprint(w, `{"value": [], "@odata.deltaLink": "https://graph.microsoft.com/v1.0/delta?token=1"}`)
+		case r.URL.Path == "/me/messages/m1/$value":
+			fmt.Fprint(w, "Subject: Paid\r\n\r\nthanks")
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+	dir := t.TempDir()
+
+	if _, err := client.BackupMail(context.Background(), &BackupOptions{Dir: dir}); err != nil {
+		t.Fatalf("BackupMail failed: %v", err)
+	}
+
+	m, _ := LoadBackupManifest(dir, BackupMail)
+	for _, id := range []string{"f-inbox", "f-receipts", "f-archive"} {
+		if m.Cursors[id] == "" {
+			t.Errorf("Expected folder %s to be backed up, got cursors %v", id, m.Cursors)
+		}
+	}
+	item := m.Items["m1"]
+	if item == nil || strings.Join(item.Folders, "/") != "Inbox/Receipts: 2024" || !strings.HasPrefix(item.File, "Inbox/Receipts_ 2024/") {
+		t.Errorf("Expected the message saved under its folder path, got %+v", item)
+	}
+}
+
+func TestBackupResumesFromLastPage(t *testing.T) {
+	s := &deltaServer{
+		rounds: [][]string{{
+			`[{"id": "m1", "subject": "First"}]`,
+			`[{"id": "m2", "subject": "Second"}]`,
+		}},
+		content: map[string]string{"/me/messages/m1/$value": "one"},
+	}
+	client := newBackupClient(t, s)
+	dir := t.TempDir()
+	opts := &BackupOptions{Dir: dir, Folders: []string{"inbox"}}
+
+	// m2 can't be downloaded yet, so the backup stops on the second page
+	if _, err := client.BackupMail(context.Background(), opts); err == nil {
+		t.Fatal("Expected the backup to fail")
+	}
+
+	s.content["/me/messages/m2/$value"] = "two"
+	s.content["/me/messages/m1/$value"] = "changed"
+	stats, err := client.BackupMail(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("BackupMail failed: %v", err)
+	}
+	if stats.Saved != 1 {
+		t.Errorf("Expected only m2 to be fetched on resume, got %+v", stats)
+	}
+	m, _ := LoadBackupManifest(dir, BackupMail)
+	if len(m.Items) != 2 {
+		t.Errorf("Expected 2 items, got %d", len(m.Items))
+	}
+}
+
+func TestBackupDrive(t *testing.T) {
+	s := &deltaServer{
+		rounds: [][]string{
+			{`[
+				{"id": "root", "root": {}, "folder": {}},
+				{"id": "docs", "name": "Docs", "folder": {}, "parentReference": {"id": "root"}},
+				{"id": "f1", "name": "notes.txt", "size": 5, "file": {}, "parentReference": {"id": "docs"}, "lastModifiedDateTime": "2025-06-01T00:00:00Z"}
+			]`},
+			{`[
+				{"id": "docs", "name": "Documents", "folder": {}, "parentReference": {"id": "root"}},
+				{"id": "f1", "name": "notes.txt", "size": 5, "file": {}, "parentReference": {"id": "docs"}, "lastModifiedDateTime": "2025-06-01T00:00:00Z"},
+				{"id": "f2", "name": "old.txt", "deleted": {}}
+			]`},
+		},
+		content: map[string]string{"/me/drive/items/f1/content": "hello"},
+	}
+	client := newBackupClient(t, s)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	if _, err := client.BackupDrive(ctx, &BackupOptions{Dir: dir}); err != nil {
+		t.Fatalf("BackupDrive failed: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "files", "Docs", "notes.txt")); err != nil || string(data) != "hello" {
+		t.Fatalf("Unexpected file %q (%v)", data, err)
+	}
+
+	// Renaming the folder moves the file rather than downloading it again
+	delete(s.content, "/me/drive/items/f1/content")
+	stats, err := client.BackupDrive(ctx, &BackupOptions{Dir: dir})
+	if err != nil {
+		t.Fatalf("BackupDrive failed: %v", err)
+	}
+	if stats.Unchanged != 1 || stats.Saved != 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "files", "Documents", "notes.txt")); err != nil {
+		t.Errorf("Expected the file to move with its folder: %v", err)
+	}
+
+	m, _ := LoadBackupManifest(dir, BackupDrive)
+	if f1 := m.Items["f1"]; f1.Path != "Documents/notes.txt" || f1.File != "files/Documents/notes.txt" {
+		t.Errorf("Unexpected item %+v", f1)
+	}
+}
+
+func TestBackupDriveRejectsUnsafePaths(t *testing.T) {
+	s := &deltaServer{rounds: [][]string{{`[{"id": "f1", "name": "..", "file": {}, "parentReference": {"path": "/drive/root:"}}]`}}}
+	client := newBackupClient(t, s)
+
+	if _, err := client.BackupDrive(context.Background(), &BackupOptions{Dir: t.TempDir()}); err == nil || !strings.Contains(err.Error(), "unsafe path") {
+		t.Errorf("Expected an unsafe path error, got %v", err)
+	}
+}
+
+func TestBackupAndRestoreCalendar(t *testing.T) {
+	s := &deltaServer{rounds: [][]string{{`[{
+		"id": "e1",
+		"subject": "Sync",
+		"start": {"dateTime": "2025-06-02T09:00:00.0000000", "timeZone": "UTC"},
+		"end": {"dateTime": "2025-06-02T09:30:00.0000000", "timeZone": "UTC"},
+		"attendees": [{"emailAddress": {"address": "sam@example.com"}}],
+		"webLink": "https://outlook.office.com/e1"
+	}]`}}}
+	client := newBackupClient(t, s)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	if _, err := client.BackupCalendar(ctx, &BackupOptions{Dir: dir}); err != nil {
+		t.Fatalf("BackupCalendar failed: %v", err)
+	}
+	m, _ := LoadBackupManifest(dir, BackupCalendar)
+	ics, err := os.ReadFile(filepath.Join(dir, m.Items["e1"].File))
+	if err != nil || !strings.Contains(string(ics), "SUMMARY:Sync") {
+		t.Fatalf("Unexpected ICS %q (%v)", ics, err)
+	}
+
+	stats, err := client.RestoreCalendar(ctx, &RestoreOptions{Dir: dir, Target: "cal1"})
+	if err != nil {
+		t.Fatalf("RestoreCalendar failed: %v", err)
+	}
+	if stats.Saved != 1 || len(s.posted) != 1 {
+		t.Fatalf("Expected one event restored in one batch, got %+v and %d requests", stats, len(s.posted))
+	}
+	batch := s.bodies[0]
+	if !strings.Contains(batch, `"url":"/me/calendars/cal1/events"`) || !strings.Contains(batch, `"subject":"Sync"`) {
+		t.Errorf("Unexpected batch %s", batch)
+	}
+	if strings.Contains(batch, "sam@example.com") || strings.Contains(batch, "webLink") {
+		t.Errorf("Restored events should leave out attendees and read-only fields: %s", batch)
+	}
+}
+
+func TestRestoreCalendarRetriesThrottled(t *testing.T) {
+	shortenRetryDelay(t)
+	dir := t.TempDir()
+	m, _ := LoadBackupManifest(dir, BackupCalendar)
+	m.Items["e1"] = &BackupItem{ID: "e1", Name: "Sync", File: "e1.ics", Data: "e1.json"}
+	writeBackupFile(dir, "e1.json", func(w io.Writer) error {
+		_, err := w.Write([]byte(`{"id": "e1", "subject": "Sync"}`))
+		return err
+	})
+	if err := m.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	s := &deltaServer{throttle: 1}
+	client := newBackupClient(t, s)
+	stats, err := client.RestoreCalendar(context.Background(), &RestoreOptions{Dir: dir})
+	if err != nil {
+		t.Fatalf("RestoreCalendar failed: %v", err)
+	}
+	if stats.Saved != 1 || len(s.posted) != 2 {
+		t.Errorf("Expected the throttled event sent again, got %+v after %d requests", stats, len(s.posted))
+	}
+}
+
+func TestSafeFileName(t *testing.T) {
+	tests := map[string]string{
+		"Re: Q3 report?": "Re_ Q3 report_",
+		"a/b\\c":         "a_b_c",
+		" .hidden. ":     "hidden",
+		"\x01":           "_",
+		"..":             "folder",
+	}
+	for name, want := range tests {
+		if got := SafeFileName(name, "folder"); got != want {
+			t.Errorf("SafeFileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRestoreMail(t *testing.T) {
+	dir := t.TempDir()
+	m, _ := LoadBackupManifest(dir, BackupMail)
+	m.Items["m1"] = &BackupItem{ID: "m1", File: "Inbox/a.eml", Folder: "Inbox"}
+	m.Items["m2"] = &BackupItem{ID: "m2", File: "Inbox/Receipts/b.eml", Folder: "Receipts", Folders: []string{"Inbox", "Receipts"}}
+	for _, item := range m.Items {
+		writeBackupFile(dir, item.File, func(w io.Writer) error {
+			_, err := w.Write([]byte("Subject: " + item.ID))
+			return err
+		})
+	}
+	if err := m.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	s := &deltaServer{}
+	client := newBackupClient(t, s)
+	stats, err := client.RestoreMail(context.Background(), &RestoreOptions{Dir: dir, Target: "Old mail"})
+	if err != nil {
+		t.Fatalf("RestoreMail failed: %v", err)
+	}
+	if stats.Saved != 2 {
+		t.Errorf("Expected 2 messages restored, got %+v", stats)
+	}
+
+	// A folder for the restore, Inbox inside it and Receipts inside that,
+	// then the messages in their folders
+	if len(s.posted) != 5 || !strings.Contains(s.bodies[0], `"Old mail"`) || s.posted[1].URL.Path != "/me/mailFolders/folder-1/childFolders" ||
+		s.posted[2].URL.Path != "/me/mailFolders/folder-2/childFolders" || !strings.Contains(s.bodies[2], `"Receipts"`) {
+		t.Fatalf("Unexpected requests %v", s.bodies)
+	}
+	msg := s.posted[3]
+	if msg.Header.Get("Content-Type") != "text/plain" || msg.URL.Path != "/me/mailFolders/folder-3/messages" {
+		t.Errorf("Unexpected message request %s %s", msg.URL.Path, msg.Header.Get("Content-Type"))
+	}
+	if mime, _ := base64.StdEncoding.DecodeString(s.bodies[3]); string(mime) != "Subject: m2" {
+		t.Errorf("Expected base64 MIME, got %q", s.bodies[3])
+	}
+	if s.posted[4].URL.Path != "/me/mailFolders/folder-2/messages" {
+		t.Errorf("Expected the older backup's message restored to Inbox, got %s", s.posted[4].URL.Path)
+	}
+}
+
+func TestLoadBackupManifestWrongKind(t *testing.T) {
+	dir := t.TempDir()
+	m, _ := LoadBackupManifest(dir, BackupDrive)
+	m.Save(dir)
+
+	if _, err := LoadBackupManifest(dir, BackupMail); err == nil || !strings.Contains(err.Error(), "holds a drive backup") {
+		t.Errorf("Expected a wrong kind error, got %v", err)
+	}
+}
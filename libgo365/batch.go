@@ -0,0 +1,310 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxBatchSize is the most requests Graph accepts in one $batch call
+const MaxBatchSize = 20
+
+// BatchRequest is one request inside a JSON $batch call. URL is relative to the
+// API version, e.g. "/me/messages/{id}". DependsOn lists the IDs of requests
+// that must succeed first; Graph answers 424 Failed Dependency otherwise.
+type BatchRequest struct {
+	ID        string            `json:"id"`
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Body      interface{}       `json:"body,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	DependsOn []string          `json:"dependsOn,omitempty"`
+}
+
+// BatchBuilder collects requests for Client.Batch, numbering them so that
+// later requests can depend on earlier ones. The zero value is ready to use.
+type BatchBuilder struct {
+	requests []*BatchRequest
+}
+
+// Add appends a request that runs after the requests with the IDs in
+// dependsOn, and returns its ID
+func (b *BatchBuilder) Add(method, url string, body interface{}, dependsOn ...string) string {
+	id := strconv.Itoa(len(b.requests) + 1)
+	b.requests = append(b.requests, &BatchRequest{ID: id, Method: method, URL: url, Body: body, DependsOn: dependsOn})
+	return id
+}
+
+// Len returns how many requests have been added
+func (b *BatchBuilder) Len() int {
+	return len(b.requests)
+}
+
+// Requests returns the requests added so far, in order
+func (b *BatchBuilder) Requests() []*BatchRequest {
+	return b.requests
+}
+
+// BatchResponse is the outcome of one BatchRequest
+type BatchResponse struct {
+	ID      string            `json:"id"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// Err returns a GraphError for a failed response, or nil on success
+func (r *BatchResponse) Err() error {
+	if r.Status >= 200 && r.Status < 300 {
+		return nil
+	}
+	gerr := newGraphError(r.Status, r.Body)
+	if id := r.header("request-id"); id != "" {
+		gerr.RequestID = id
+	}
+	if id := r.header("client-request-id"); id != "" {
+		gerr.ClientRequestID = id
+	}
+	if date := r.header("Date"); date != "" {
+		gerr.Date = date
+	}
+	gerr.RetryAfter = r.RetryAfter()
+	return gerr
+}
+
+// header returns a response header, matching the name case-insensitively
+func (r *BatchResponse) header(name string) string {
+	for key, value := range r.Headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// Throttled reports whether Graph throttled or was too busy for the request,
+// in which case it can be retried later
+func (r *BatchResponse) Throttled() bool {
+	return r.Status == http.StatusTooManyRequests || r.Status == http.StatusServiceUnavailable
+}
+
+// RetryAfter returns how long Graph asked to wait before retrying
+func (r *BatchResponse) RetryAfter() time.Duration {
+	return ParseRetryAfter(r.header("Retry-After"))
+}
+
+// Batch sends requests through Graph's JSON $batch endpoint, MaxBatchSize at a
+// time, and returns the responses in request order. Requests linked by
+// DependsOn are kept in the same call, so no chain of them may be longer
+// than MaxBatchSize. A failed sub-request is reported in its response, not
+// as an error; the error is only for calls that failed as a whole.
+func (c *Client) Batch(ctx context.Context, requests []*BatchRequest) ([]*BatchResponse, error) {
+	// Number requests the caller didn't, and give bodies a content type
+	for i, req := range requests {
+		if req.ID == "" {
+			req.ID = strconv.Itoa(i + 1)
+		}
+		if req.Body != nil {
+			if req.Headers == nil {
+				req.Headers = map[string]string{}
+			}
+			if _, ok := req.Headers["Content-Type"]; !ok {
+				req.Headers["Content-Type"] = "application/json"
+			}
+		}
+	}
+	chunks, err := batchChunks(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*BatchResponse, len(requests))
+	for _, chunk := range chunks {
+
+		data, err := c.Post(ctx, "/$batch", map[string]interface{}{"requests": chunk})
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Responses []*BatchResponse `json:"responses"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal batch response: %w", err)
+		}
+
+		// Graph may answer in any order. Throttled sub-requests never reach
+		// the transport, so report them to the limiter and breaker here.
+		for _, resp := range result.Responses {
+			byID[resp.ID] = resp
+			if resp.Throttled() {
+				c.throttled(resp.RetryAfter())
+				c.recordStatus(resp.Status, resp.RetryAfter())
+			}
+		}
+		for _, req := range chunk {
+			resp, ok := byID[req.ID]
+			if !ok {
+				return nil, fmt.Errorf("batch response missing request %s", req.ID)
+			}
+			c.recordRequest(req.Method, req.URL, resp.Status, resp.header("request-id"), true)
+		}
+	}
+
+	responses := make([]*BatchResponse, len(requests))
+	for i, req := range requests {
+		responses[i] = byID[req.ID]
+	}
+	return responses, nil
+}
+
+// batchChunks splits requests into $batch calls of at most MaxBatchSize,
+// keeping each request in the same call as those it depends on and
+// otherwise preserving order
+func batchChunks(requests []*BatchRequest) ([][]*BatchRequest, error) {
+	// Union requests linked by DependsOn into groups
+	index := make(map[string]int, len(requests))
+	for i, req := range requests {
+		if _, dup := index[req.ID]; dup {
+			return nil, fmt.Errorf("duplicate batch request ID %s", req.ID)
+		}
+		index[req.ID] = i
+	}
+	parent := make([]int, len(requests))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	for i, req := range requests {
+		for _, dep := range req.DependsOn {
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("batch request %s depends on unknown request %s", req.ID, dep)
+			}
+			parent[root(i)] = root(j)
+		}
+	}
+
+	// Groups in order of their first request, each kept in order
+	var groups [][]*BatchRequest
+	groupOf := map[int]int{}
+	for i, req := range requests {
+		r := root(i)
+		g, ok := groupOf[r]
+		if !ok {
+			g = len(groups)
+			groupOf[r] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], req)
+	}
+
+	var chunks [][]*BatchRequest
+	var chunk []*BatchRequest
+	for _, group := range groups {
+		if len(group) > MaxBatchSize {
+			return nil, fmt.Errorf("batch request %s has %d dependent requests; at most %d fit in one call", group[0].ID, len(group), MaxBatchSize)
+		}
+		if len(chunk)+len(group) > MaxBatchSize {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+		chunk = append(chunk, group...)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// withoutFinished copies requests being sent again, dropping dependencies
+// on requests that aren't, which finished in an earlier call
+func withoutFinished(requests []*BatchRequest) []*BatchRequest {
+	ids := make(map[string]bool, len(requests))
+	for _, req := range requests {
+		ids[req.ID] = true
+	}
+	copies := make([]*BatchRequest, len(requests))
+	for i, req := range requests {
+		r := *req
+		r.DependsOn = slices.DeleteFunc(slices.Clone(req.DependsOn), func(id string) bool { return !ids[id] })
+		copies[i] = &r
+	}
+	return copies
+}
+
+// BatchWithRetry runs requests with Batch, sending throttled ones again
+// after Graph's Retry-After up to the client's retry limit and while its
+// retry budget lasts, and returns each request's final response in order
+func (c *Client) BatchWithRetry(ctx context.Context, requests []*BatchRequest) ([]*BatchResponse, error) {
+	final := make([]*BatchResponse, len(requests))
+	pending := make([]int, len(requests))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	for attempt := 0; len(pending) > 0; attempt++ {
+		batch := make([]*BatchRequest, len(pending))
+		for i, idx := range pending {
+			batch[i] = requests[idx]
+		}
+		if attempt > 0 {
+			batch = withoutFinished(batch)
+		}
+		responses, err := c.Batch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		var throttled []int
+		var wait time.Duration
+		for i, idx := range pending {
+			final[idx] = responses[i]
+			if responses[i].Throttled() && attempt < c.maxRetries && c.AllowRetry() {
+				throttled = append(throttled, idx)
+				wait = max(wait, responses[i].RetryAfter())
+			}
+		}
+		if len(throttled) == 0 {
+			break
+		}
+		if wait == 0 {
+			wait = backoff(attempt)
+		}
+		timer := time.NewTimer(min(wait, maxRetryWait))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		pending = throttled
+	}
+	return final, nil
+}
+
+// batchErrors runs requests with BatchWithRetry and returns each one's
+// error, nil on success
+func (c *Client) batchErrors(ctx context.Context, requests []*BatchRequest) ([]error, error) {
+	responses, err := c.BatchWithRetry(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]error, len(responses))
+	for i, resp := range responses {
+		errs[i] = resp.Err()
+	}
+	return errs, nil
+}
@@ -0,0 +1,200 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/$batch" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		calls++
+
+		var body struct {
+			Requests []*BatchRequest `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Requests) > MaxBatchSize {
+			t.Errorf("Batch of %d exceeds MaxBatchSize", len(body.Requests))
+		}
+
+		// Answer in reverse order; the odd-numbered IDs fail
+		var responses []*BatchResponse
+		for i := len(body.Requests) - 1; i >= 0; i-- {
+			req := body.Requests[i]
+			if req.Body != nil && req.Headers["Content-Type"] != "application/json" {
+				t.Errorf("Request %s with a body lacks Content-Type", req.ID)
+			}
+			n, _ := strconv.Atoi(req.ID)
+			status := 204
+			var respBody json.RawMessage
+			if n%2 == 1 {
+				status = 404
+				respBody = json.RawMessage(`{"error":{"code":"ErrorItemNotFound","message":"gone"}}`)
+			}
+			responses = append(responses, &BatchResponse{ID: req.ID, Status: status, Body: respBody})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	var requests []*BatchRequest
+	for i := 0; i < 25; i++ {
+		requests = append(requests, &BatchRequest{Method: "PATCH", URL: "/me/messages/x", Body: map[string]bool{"isRead": true}})
+	}
+
+	responses, err := client.Batch(context.Background(), requests)
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 25 requests in 2 calls, got %d", calls)
+	}
+	if len(responses) != 25 {
+		t.Fatalf("Expected 25 responses, got %d", len(responses))
+	}
+	for i, resp := range responses {
+		if resp.ID != strconv.Itoa(i+1) {
+			t.Errorf("Response %d out of order: ID %s", i, resp.ID)
+		}
+	}
+
+	if responses[0].Err() == nil || StatusCode(responses[0].Err()) != 404 {
+		t.Errorf("Expected 404 GraphError, got %v", responses[0].Err())
+	}
+	if responses[1].Err() != nil {
+		t.Errorf("Expected success, got %v", responses[1].Err())
+	}
+}
+
+func TestBatchKeepsDependenciesTogether(t *testing.T) {
+	var calls [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []*BatchRequest `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var ids []string
+		var responses []*BatchResponse
+		for _, req := range body.Requests {
+			ids = append(ids, req.ID)
+			responses = append(responses, &BatchResponse{ID: req.ID, Status: 201})
+		}
+		calls = append(calls, ids)
+		json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	// 15 independent requests, then a folder and 10 moves into it, which
+	// would straddle the first call's end if split naively
+	var b BatchBuilder
+	for i := 0; i < 15; i++ {
+		b.Add("PATCH", "/me/messages/m"+strconv.Itoa(i), map[string]bool{"isRead": true})
+	}
+	folder := b.Add("POST", "/me/mailFolders", map[string]string{"displayName": "Archive 2025"})
+	for i := 0; i < 10; i++ {
+		b.Add("POST", "/me/messages/x/move", map[string]string{"destinationId": "archive"}, folder)
+	}
+	if b.Len() != 26 || b.Requests()[16].DependsOn[0] != folder {
+		t.Fatalf("Unexpected builder requests: %d", b.Len())
+	}
+
+	responses, err := client.Batch(context.Background(), b.Requests())
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if len(calls) != 2 || len(calls[0]) != 15 || len(calls[1]) != 11 || calls[1][0] != folder {
+		t.Errorf("Expected the folder and its moves in the second call, got %v", calls)
+	}
+	for i, resp := range responses {
+		if resp.ID != strconv.Itoa(i+1) {
+			t.Errorf("Response %d out of order: ID %s", i, resp.ID)
+		}
+	}
+
+	if _, err := client.Batch(context.Background(), []*BatchRequest{{ID: "1", Method: "GET", URL: "/me", DependsOn: []string{"9"}}}); err == nil {
+		t.Error("Expected an unknown dependency to be rejected")
+	}
+	var chain BatchBuilder
+	prev := chain.Add("GET", "/me", nil)
+	for i := 0; i < MaxBatchSize; i++ {
+		prev = chain.Add("GET", "/me", nil, prev)
+	}
+	if _, err := client.Batch(context.Background(), chain.Requests()); err == nil {
+		t.Error("Expected a dependency chain longer than MaxBatchSize to be rejected")
+	}
+}
+
+func TestBatchWithRetry(t *testing.T) {
+	shortenRetryDelay(t)
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var body struct {
+			Requests []*BatchRequest `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		// The second request is throttled the first time it is sent
+		var responses []*BatchResponse
+		for _, req := range body.Requests {
+			resp := &BatchResponse{ID: req.ID, Status: 204}
+			if req.URL == "/me/messages/b" && calls == 1 {
+				resp.Status = 429
+				resp.Headers = map[string]string{"Retry-After": "0"}
+			}
+			responses = append(responses, resp)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+		maxRetries: 2,
+	}
+
+	requests := []*BatchRequest{
+		{Method: "DELETE", URL: "/me/messages/a"},
+		{Method: "DELETE", URL: "/me/messages/b"},
+	}
+	responses, err := client.BatchWithRetry(context.Background(), requests)
+	if err != nil {
+		t.Fatalf("BatchWithRetry failed: %v", err)
+	}
+	if calls != 2 || responses[0].Status != 204 || responses[1].Status != 204 {
+		t.Errorf("Expected the throttled request resent once, got %d calls and %d, %d", calls, responses[0].Status, responses[1].Status)
+	}
+
+	// Without retries the throttled response is returned as it is
+	calls = 0
+	client.maxRetries = 0
+	responses, err = client.BatchWithRetry(context.Background(), requests)
+	if err != nil {
+		t.Fatalf("BatchWithRetry failed: %v", err)
+	}
+	if calls != 1 || !responses[1].Throttled() {
+		t.Errorf("Expected one call and a throttled response, got %d calls and %d", calls, responses[1].Status)
+	}
+}
@@ -0,0 +1,162 @@
+package libgo365
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBreakerThreshold is how many throttled or failed responses in a
+	// row open a new client's circuit breaker
+	DefaultBreakerThreshold = 8
+
+	// DefaultRetryBudget is how many retries a new client allows in total
+	DefaultRetryBudget = 50
+
+	// breakerCooldown is the first pause once the breaker opens; it doubles
+	// each time the breaker opens again, up to maxBreakerCooldown
+	breakerCooldown    = 30 * time.Second
+	maxBreakerCooldown = 5 * time.Minute
+)
+
+// BackoffError is returned without contacting Graph while a client's circuit
+// breaker is open
+type BackoffError struct {
+	Until time.Time
+}
+
+func (e *BackoffError) Error() string {
+	return fmt.Sprintf("Graph keeps throttling or failing requests; backing off until %s", e.Until.Format("15:04:05"))
+}
+
+// Is makes a BackoffError match ErrThrottled: the caller should wait and
+// try again, as after a 429
+func (e *BackoffError) Is(target error) bool {
+	return target == ErrThrottled
+}
+
+// CircuitBreaker protects Graph, and the tenant's throttling quota, from
+// callers that keep going while every request is throttled or failing. After
+// threshold 429 or 5xx responses in a row it refuses requests for a cooldown
+// (or Retry-After, if longer), then lets them through again; one more failure
+// reopens it for twice as long. It also holds the retry budget that callers
+// retrying throttled requests spend from, so a run can't retry forever.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	retries   int // Retries left in the budget
+	failures  int // Throttled or failed responses since the last success
+	opened    int // Times opened since the last success
+	openUntil time.Time
+}
+
+// NewCircuitBreaker creates a breaker that opens after threshold failures in
+// a row and allows retryBudget retries in total. A threshold of zero never
+// opens.
+func NewCircuitBreaker(threshold, retryBudget int) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, retries: retryBudget}
+}
+
+// Allow returns a *BackoffError while the breaker is open
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if time.Now().Before(b.openUntil) {
+		return &BackoffError{Until: b.openUntil}
+	}
+	return nil
+}
+
+// Record counts a response toward opening the breaker. Any status other than
+// 429 or 5xx resets the count.
+func (b *CircuitBreaker) Record(status int, retryAfter time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !breakerFailure(status) {
+		b.failures = 0
+		b.opened = 0
+		return
+	}
+
+	b.failures++
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return
+	}
+
+	// Open, or reopen after the first request past a cooldown failed too
+	cooldown := min(breakerCooldown<<b.opened, maxBreakerCooldown)
+	until := time.Now().Add(max(cooldown, retryAfter))
+	if until.After(b.openUntil) {
+		b.openUntil = until
+	}
+	b.opened++
+	// Until a request succeeds, the next failure reopens it straight away
+	b.failures = b.threshold - 1
+}
+
+// Retry spends one retry from the budget, reporting false once it is used up
+func (b *CircuitBreaker) Retry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.retries <= 0 {
+		return false
+	}
+	b.retries--
+	return true
+}
+
+// breakerFailure reports whether a status counts against the breaker
+func breakerFailure(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// SetCircuitBreaker replaces the client's circuit breaker; nil turns it off
+func (c *Client) SetCircuitBreaker(b *CircuitBreaker) {
+	c.breaker = b
+}
+
+// AllowRetry spends one retry from the client's budget. Code that retries
+// throttled or failed requests should stop when it returns false.
+func (c *Client) AllowRetry() bool {
+	return c.breaker == nil || c.breaker.Retry()
+}
+
+// recordStatus tells the client's breaker, if any, about a response that
+// didn't come through the transport, such as a $batch sub-request
+func (c *Client) recordStatus(status int, retryAfter time.Duration) {
+	if c.breaker != nil {
+		c.breaker.Record(status, retryAfter)
+	}
+}
+
+// breakerTransport refuses requests while the client's circuit breaker is
+// open and reports each response to it
+type breakerTransport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	breaker := t.client.breaker
+	if breaker == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	if err := breaker.Allow(); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	breaker.Record(resp.StatusCode, ParseRetryAfter(resp.Header.Get("Retry-After")))
+	return resp, nil
+}
@@ -0,0 +1,134 @@
+package libgo365
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	b := NewCircuitBreaker(3, 0)
+
+	b.Record(http.StatusTooManyRequests, 0)
+	b.Record(http.StatusServiceUnavailable, 0)
+	b.Record(http.StatusOK, 0)
+	b.Record(http.StatusTooManyRequests, 0)
+	b.Record(http.StatusBadGateway, 0)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("A success should reset the count, got %v", err)
+	}
+
+	b.Record(http.StatusTooManyRequests, 0)
+	err := b.Allow()
+	var backoff *BackoffError
+	if !errors.As(err, &backoff) {
+		t.Fatalf("Expected a BackoffError after 3 failures in a row, got %v", err)
+	}
+	if wait := time.Until(backoff.Until); wait < breakerCooldown-time.Second || wait > breakerCooldown {
+		t.Errorf("Expected to back off for %v, got %v", breakerCooldown, wait)
+	}
+
+	// Once the cooldown is over, one more failure reopens it for longer
+	b.openUntil = time.Now().Add(-time.Second)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Expected requests through after the cooldown, got %v", err)
+	}
+	b.Record(http.StatusInternalServerError, 0)
+	if err := b.Allow(); !errors.As(err, &backoff) || time.Until(backoff.Until) < breakerCooldown {
+		t.Errorf("Expected a longer back-off after another failure, got %v", err)
+	}
+}
+
+func TestCircuitBreakerRetryAfter(t *testing.T) {
+	b := NewCircuitBreaker(1, 0)
+	b.Record(http.StatusTooManyRequests, 10*time.Minute)
+
+	var backoff *BackoffError
+	if err := b.Allow(); !errors.As(err, &backoff) || time.Until(backoff.Until) < 9*time.Minute {
+		t.Errorf("Expected Retry-After to lengthen the back-off, got %v", err)
+	}
+}
+
+func TestCircuitBreakerNotFoundIsNotAFailure(t *testing.T) {
+	b := NewCircuitBreaker(1, 0)
+	b.Record(http.StatusNotFound, 0)
+	if err := b.Allow(); err != nil {
+		t.Errorf("Expected a 404 not to open the breaker, got %v", err)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	client := NewClient(context.Background(), "test-token")
+	client.SetCircuitBreaker(NewCircuitBreaker(0, 2))
+
+	for i := 0; i < 2; i++ {
+		if !client.AllowRetry() {
+			t.Fatalf("Expected retry %d to be allowed", i+1)
+		}
+	}
+	if client.AllowRetry() {
+		t.Error("Expected the budget to be used up")
+	}
+
+	client.SetCircuitBreaker(nil)
+	if !client.AllowRetry() {
+		t.Error("Expected retries to be unlimited without a breaker")
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+	client.SetCircuitBreaker(NewCircuitBreaker(2, 0))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(context.Background(), "/me"); StatusCode(err) != http.StatusServiceUnavailable {
+			t.Fatalf("Expected a 503, got %v", err)
+		}
+	}
+
+	_, err := client.Get(context.Background(), "/me")
+	var backoff *BackoffError
+	if !errors.As(err, &backoff) {
+		t.Fatalf("Expected a BackoffError once the breaker opened, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected no request to reach Graph while backing off, got %d requests", requests)
+	}
+}
+
+func TestBatchOpensCircuitBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responses":[
+			{"id":"1","status":429,"headers":{"Retry-After":"0"}},
+			{"id":"2","status":429,"headers":{"Retry-After":"0"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+	client.SetCircuitBreaker(NewCircuitBreaker(2, 0))
+
+	if _, err := client.Batch(context.Background(), []*BatchRequest{
+		{Method: "DELETE", URL: "/me/messages/a"},
+		{Method: "DELETE", URL: "/me/messages/b"},
+	}); err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+
+	var backoff *BackoffError
+	if _, err := client.Get(context.Background(), "/me"); !errors.As(err, &backoff) {
+		t.Errorf("Expected throttled sub-requests to open the breaker, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package libgo365
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// BulkOp is one operation for a bulk run, usually read from a JSON line such
+// as {"op":"mail.move","id":"AAMk...","folder":"archive"}
+type BulkOp struct {
+	Op       string `json:"op"`
+	ID       string `json:"id"`
+	Folder   string `json:"folder,omitempty"`   // mail.move, mail.copy
+	Response string `json:"response,omitempty"` // calendar.respond: accept, decline, tentative
+	Comment  string `json:"comment,omitempty"`  // calendar.respond
+}
+
+// bulkOpKinds maps each operation to the fields it requires beyond id
+var bulkOpKinds = map[string][]string{
+	"mail.move":            {"folder"},
+	"mail.copy":            {"folder"},
+	"mail.delete":          nil,
+	"mail.permanentDelete": nil,
+	"mail.markRead":        nil,
+	"mail.markUnread":      nil,
+	"calendar.respond":     {"response"},
+	"calendar.delete":      nil,
+	"calendar.cancel":      nil,
+}
+
+// BulkOps lists the supported operation names
+func BulkOps() []string {
+	ops := make([]string, 0, len(bulkOpKinds))
+	for op := range bulkOpKinds {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// Validate checks that the operation is known and has the fields it needs
+func (op *BulkOp) Validate() error {
+	required, ok := bulkOpKinds[op.Op]
+	if !ok {
+		return fmt.Errorf("unknown op %q (supported: %s)", op.Op, strings.Join(BulkOps(), ", "))
+	}
+	if op.ID == "" {
+		return fmt.Errorf("%s requires id", op.Op)
+	}
+	for _, field := range required {
+		if (field == "folder" && op.Folder == "") || (field == "response" && op.Response == "") {
+			return fmt.Errorf("%s requires %s", op.Op, field)
+		}
+	}
+	if op.Op == "calendar.respond" {
+		if _, ok := eventResponseActions[op.Response]; !ok {
+			return fmt.Errorf("invalid response: %s (must be accept, decline, or tentative)", op.Response)
+		}
+	}
+	return nil
+}
+
+// Request translates the operation into a $batch sub-request
+func (op *BulkOp) Request() (*BatchRequest, error) {
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	id := url.PathEscape(op.ID)
+	switch op.Op {
+	case "mail.move", "mail.copy":
+		action := strings.TrimPrefix(op.Op, "mail.")
+		return &BatchRequest{
+			Method: "POST",
+			URL:    fmt.Sprintf("/me/messages/%s/%s", id, action),
+			Body:   map[string]string{"destinationId": op.Folder},
+		}, nil
+	case "mail.delete":
+		return &BatchRequest{Method: "DELETE", URL: "/me/messages/" + id}, nil
+	case "mail.permanentDelete":
+		return &BatchRequest{Method: "POST", URL: fmt.Sprintf("/me/messages/%s/permanentDelete", id)}, nil
+	case "mail.markRead", "mail.markUnread":
+		return &BatchRequest{
+			Method: "PATCH",
+			URL:    "/me/messages/" + id,
+			Body:   map[string]bool{"isRead": op.Op == "mail.markRead"},
+		}, nil
+	case "calendar.respond":
+		body := map[string]interface{}{"sendResponse": true}
+		if op.Comment != "" {
+			body["comment"] = op.Comment
+		}
+		return &BatchRequest{
+			Method: "POST",
+			URL:    fmt.Sprintf("/me/events/%s/%s", id, eventResponseActions[op.Response]),
+			Body:   body,
+		}, nil
+	case "calendar.delete":
+		return &BatchRequest{Method: "DELETE", URL: "/me/events/" + id}, nil
+	case "calendar.cancel":
+		body := map[string]interface{}{}
+		if op.Comment != "" {
+			body["comment"] = op.Comment
+		}
+		return &BatchRequest{Method: "POST", URL: fmt.Sprintf("/me/events/%s/cancel", id), Body: body}, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// runBulkOps runs ops with batchErrors: one request when there is a single
+// op, $batch calls otherwise. ops must be valid.
+func (c *Client) runBulkOps(ctx context.Context, ops []*BulkOp) ([]error, error) {
+	requests := make([]*BatchRequest, len(ops))
+	for i, op := range ops {
+		req, err := op.Request()
+		if err != nil {
+			return nil, err
+		}
+		requests[i] = req
+	}
+	if len(requests) == 1 {
+		// A lone request doesn't need the $batch envelope
+		req := requests[0]
+		var err error
+		if req.Method == "DELETE" {
+			err = c.Delete(ctx, req.URL)
+		} else {
+			_, err = c.doJSONRequest(ctx, req.Method, req.URL, req.Body)
+		}
+		return []error{err}, nil
+	}
+	return c.batchErrors(ctx, requests)
+}
+
+// RespondToEvents accepts, declines or tentatively accepts several
+// invitations in $batch calls, returning each one's error (nil on success)
+// in order. The error is for calls that failed as a whole.
+func (c *Client) RespondToEvents(ctx context.Context, eventIDs []string, response, message string) ([]error, error) {
+	ops := make([]*BulkOp, len(eventIDs))
+	for i, id := range eventIDs {
+		ops[i] = &BulkOp{Op: "calendar.respond", ID: id, Response: response, Comment: message}
+	}
+	return c.runBulkOps(ctx, ops)
+}
+
+// DeleteMessages deletes several messages in $batch calls, returning each
+// one's error (nil on success) in order. The error is for calls that failed
+// as a whole.
+func (c *Client) DeleteMessages(ctx context.Context, messageIDs []string) ([]error, error) {
+	return c.messageOps(ctx, "mail.delete", messageIDs, "")
+}
+
+// PermanentlyDeleteMessages is DeleteMessages for PermanentlyDeleteMessage
+func (c *Client) PermanentlyDeleteMessages(ctx context.Context, messageIDs []string) ([]error, error) {
+	return c.messageOps(ctx, "mail.permanentDelete", messageIDs, "")
+}
+
+// MoveMessages moves several messages to a folder in $batch calls, returning
+// each one's error as DeleteMessages does
+func (c *Client) MoveMessages(ctx context.Context, messageIDs []string, destinationID string) ([]error, error) {
+	if destinationID == "" {
+		return nil, fmt.Errorf("destination folder is required")
+	}
+	return c.messageOps(ctx, "mail.move", messageIDs, destinationID)
+}
+
+// CopyMessages copies several messages to a folder in $batch calls,
+// returning each one's error as DeleteMessages does
+func (c *Client) CopyMessages(ctx context.Context, messageIDs []string, destinationID string) ([]error, error) {
+	if destinationID == "" {
+		return nil, fmt.Errorf("destination folder is required")
+	}
+	return c.messageOps(ctx, "mail.copy", messageIDs, destinationID)
+}
+
+// messageOps runs the same operation on each message
+func (c *Client) messageOps(ctx context.Context, op string, messageIDs []string, folder string) ([]error, error) {
+	ops := make([]*BulkOp, len(messageIDs))
+	for i, id := range messageIDs {
+		ops[i] = &BulkOp{Op: op, ID: id, Folder: folder}
+	}
+	return c.runBulkOps(ctx, ops)
+}
@@ -0,0 +1,159 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBulkOpValidate(t *testing.T) {
+	tests := []struct {
+		op      BulkOp
+		wantErr string
+	}{
+		{BulkOp{Op: "mail.move", ID: "m1", Folder: "archive"}, ""},
+		{BulkOp{Op: "mail.move", ID: "m1"}, "requires folder"},
+		{BulkOp{Op: "mail.delete"}, "requires id"},
+		{BulkOp{Op: "calendar.respond", ID: "e1", Response: "maybe"}, "invalid response"},
+		{BulkOp{Op: "calendar.respond", ID: "e1", Response: "tentative"}, ""},
+		{BulkOp{Op: "mail.explode", ID: "m1"}, "unknown op"},
+	}
+
+	for _, tt := range tests {
+		err := tt.op.Validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%+v: unexpected error %v", tt.op, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%+v: expected error containing %q, got %v", tt.op, tt.wantErr, err)
+		}
+	}
+}
+
+func TestBulkOpRequest(t *testing.T) {
+	tests := []struct {
+		op     BulkOp
+		method string
+		url    string
+	}{
+		{BulkOp{Op: "mail.move", ID: "m1", Folder: "archive"}, "POST", "/me/messages/m1/move"},
+		{BulkOp{Op: "mail.copy", ID: "m1", Folder: "archive"}, "POST", "/me/messages/m1/copy"},
+		{BulkOp{Op: "mail.delete", ID: "m/1"}, "DELETE", "/me/messages/m%2F1"},
+		{BulkOp{Op: "mail.permanentDelete", ID: "m1"}, "POST", "/me/messages/m1/permanentDelete"},
+		{BulkOp{Op: "mail.markRead", ID: "m1"}, "PATCH", "/me/messages/m1"},
+		{BulkOp{Op: "calendar.respond", ID: "e1", Response: "tentative"}, "POST", "/me/events/e1/tentativelyAccept"},
+		{BulkOp{Op: "calendar.cancel", ID: "e1"}, "POST", "/me/events/e1/cancel"},
+	}
+
+	for _, tt := range tests {
+		req, err := tt.op.Request()
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.op.Op, err)
+			continue
+		}
+		if req.Method != tt.method || req.URL != tt.url {
+			t.Errorf("%s: got %s %s, want %s %s", tt.op.Op, req.Method, req.URL, tt.method, tt.url)
+		}
+	}
+
+	req, _ := (&BulkOp{Op: "mail.markUnread", ID: "m1"}).Request()
+	if body := req.Body.(map[string]bool); body["isRead"] {
+		t.Error("Expected markUnread to set isRead false")
+	}
+}
+
+func TestRespondToEvents(t *testing.T) {
+	shortenRetryDelay(t)
+	var batches, single int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/$batch" {
+			single++
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		batches++
+		var body struct {
+			Requests []*BatchRequest `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var responses []*BatchResponse
+		for _, req := range body.Requests {
+			status := http.StatusAccepted
+			switch {
+			case strings.Contains(req.URL, "/e2/"):
+				status = http.StatusNotFound
+			case strings.Contains(req.URL, "/e3/") && batches == 1:
+				status = http.StatusTooManyRequests
+			}
+			responses = append(responses, &BatchResponse{ID: req.ID, Status: status})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+
+	errs, err := client.RespondToEvents(context.Background(), []string{"e1", "e2", "e3"}, "accept", "")
+	if err != nil {
+		t.Fatalf("RespondToEvents failed: %v", err)
+	}
+	if errs[0] != nil || StatusCode(errs[1]) != http.StatusNotFound || errs[2] != nil {
+		t.Errorf("Expected e2 alone to fail once e3 was retried, got %v", errs)
+	}
+	if batches != 2 {
+		t.Errorf("Expected the throttled response sent again in a second batch, got %d", batches)
+	}
+
+	if _, err := client.RespondToEvents(context.Background(), []string{"e1"}, "accept", ""); err != nil || single != 1 {
+		t.Errorf("Expected a lone response sent without $batch, got %d (%v)", single, err)
+	}
+	if _, err := client.RespondToEvents(context.Background(), []string{"e1", "e2"}, "maybe", ""); err == nil {
+		t.Error("Expected an invalid response to be rejected")
+	}
+}
+
+func TestMoveAndCopyMessages(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []*BatchRequest `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var responses []*BatchResponse
+		for _, req := range body.Requests {
+			destination, _ := json.Marshal(req.Body)
+			got = append(got, req.Method+" "+req.URL+" "+string(destination))
+			responses = append(responses, &BatchResponse{ID: req.ID, Status: http.StatusCreated})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+	ctx := context.Background()
+
+	if errs, err := client.MoveMessages(ctx, []string{"m1", "m2"}, "archive"); err != nil || errs[0] != nil || errs[1] != nil {
+		t.Fatalf("MoveMessages failed: %v %v", errs, err)
+	}
+	if errs, err := client.CopyMessages(ctx, []string{"m3", "m4"}, "folder1"); err != nil || errs[0] != nil || errs[1] != nil {
+		t.Fatalf("CopyMessages failed: %v %v", errs, err)
+	}
+	want := []string{
+		`POST /me/messages/m1/move {"destinationId":"archive"}`,
+		`POST /me/messages/m2/move {"destinationId":"archive"}`,
+		`POST /me/messages/m3/copy {"destinationId":"folder1"}`,
+		`POST /me/messages/m4/copy {"destinationId":"folder1"}`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected batch requests:\n%s", strings.Join(got, "\n"))
+	}
+
+	if _, err := client.MoveMessages(ctx, []string{"m1"}, ""); err == nil {
+		t.Error("Expected an error without a destination")
+	}
+}
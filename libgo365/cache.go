@@ -0,0 +1,176 @@
+package libgo365
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// cacheFileExt marks response cache entries so Clear only removes those
+	cacheFileExt = ".cache"
+
+	// validatorsFileExt marks the ETag and Last-Modified kept beside an
+	// entry for revalidating it
+	validatorsFileExt = ".validators"
+
+	// maxCacheEntrySize keeps file downloads out of the cache
+	maxCacheEntrySize = 1 << 20
+)
+
+// ResponseCache is a read-through cache of successful GET responses, stored as
+// one file per URL. Any write through the client clears it, so a command never
+// reads back data from before its own change.
+type ResponseCache struct {
+	Dir string
+	TTL time.Duration
+
+	// Refresh skips cached entries but still stores fresh responses
+	Refresh bool
+
+	// Revalidate keeps entries whose response had an ETag or Last-Modified
+	// for this long after they expire. Rather than downloading them again,
+	// the client asks Graph with a conditional GET whether they changed, and
+	// reuses them if not. Zero turns this off.
+	Revalidate time.Duration
+}
+
+// validators are the headers a conditional GET sends back to ask whether a
+// cached response is still current
+type validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// NewResponseCache creates a cache in dir whose entries expire after ttl
+func NewResponseCache(dir string, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{Dir: dir, TTL: ttl}
+}
+
+// path returns the file holding key's entry
+func (rc *ResponseCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(rc.Dir, hex.EncodeToString(sum[:])+cacheFileExt)
+}
+
+// Get returns the cached body for key if it is younger than the TTL
+func (rc *ResponseCache) Get(key string) ([]byte, bool) {
+	if rc.Refresh || rc.TTL <= 0 {
+		return nil, false
+	}
+	path := rc.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if age := time.Since(info.ModTime()); age > rc.TTL {
+		if age > rc.TTL+rc.Revalidate {
+			rc.remove(path)
+		}
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Put stores body for key. Bodies over 1 MiB are not cached.
+func (rc *ResponseCache) Put(key string, body []byte) error {
+	return rc.putResponse(key, body, nil)
+}
+
+// putResponse stores body for key along with the validators in header, if
+// any, for revalidating it once it expires
+func (rc *ResponseCache) putResponse(key string, body []byte, header http.Header) error {
+	if rc.TTL <= 0 || len(body) > maxCacheEntrySize {
+		return nil
+	}
+	if err := os.MkdirAll(rc.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	path := rc.path(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0600); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	v := validators{ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified")}
+	if rc.Revalidate <= 0 || v == (validators{}) {
+		os.Remove(path + validatorsFileExt)
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path+validatorsFileExt, data, 0600)
+}
+
+// conditional returns the entry for key if it can be revalidated rather
+// than fetched again, and sets the conditional GET headers for it on header
+func (rc *ResponseCache) conditional(key string, header http.Header) []byte {
+	if rc.Revalidate <= 0 || rc.Refresh {
+		return nil
+	}
+	path := rc.path(key)
+	data, err := os.ReadFile(path + validatorsFileExt)
+	if err != nil {
+		return nil
+	}
+	var v validators
+	if json.Unmarshal(data, &v) != nil {
+		return nil
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	if v.ETag != "" {
+		header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		header.Set("If-Modified-Since", v.LastModified)
+	}
+	return body
+}
+
+// touch restarts key's TTL after Graph said its entry is still current
+func (rc *ResponseCache) touch(key string) {
+	now := time.Now()
+	os.Chtimes(rc.path(key), now, now)
+}
+
+// remove deletes an entry and its validators
+func (rc *ResponseCache) remove(path string) {
+	os.Remove(path)
+	os.Remove(path + validatorsFileExt)
+}
+
+// Clear removes every entry
+func (rc *ResponseCache) Clear() error {
+	entries, err := os.ReadDir(rc.Dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), cacheFileExt) || strings.HasSuffix(entry.Name(), validatorsFileExt) {
+			os.Remove(filepath.Join(rc.Dir, entry.Name()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestClientResponseCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"n":%d}`, requests)
+	}))
+	defer server.Close()
+
+	cache := NewResponseCache(t.TempDir(), time.Minute)
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+	client.SetCache(cache)
+	ctx := context.Background()
+
+	first, err := client.Get(ctx, "/me")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	second, _ := client.Get(ctx, "/me")
+	if string(first) != string(second) || requests != 1 {
+		t.Errorf("Expected second Get to be served from cache, got %s after %d requests", second, requests)
+	}
+
+	// Refresh bypasses the entry and replaces it
+	cache.Refresh = true
+	refreshed, _ := client.Get(ctx, "/me")
+	cache.Refresh = false
+	if string(refreshed) != `{"n":2}` {
+		t.Errorf("Expected refreshed response, got %s", refreshed)
+	}
+	if cached, _ := client.Get(ctx, "/me"); string(cached) != `{"n":2}` {
+		t.Errorf("Expected refreshed response to be cached, got %s", cached)
+	}
+
+	// Writes clear the cache
+	if _, err := client.Post(ctx, "/me/sendMail", map[string]string{}); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	if after, _ := client.Get(ctx, "/me"); string(after) != `{"n":4}` {
+		t.Errorf("Expected cache to be cleared by a write, got %s", after)
+	}
+}
+
+func TestResponseCacheExpiry(t *testing.T) {
+	cache := NewResponseCache(t.TempDir(), time.Minute)
+	if err := cache.Put("key", []byte("body")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, ok := cache.Get("key"); !ok {
+		t.Fatal("Expected fresh entry")
+	}
+
+	old := time.Now().Add(-2 * time.Minute)
+	os.Chtimes(cache.path("key"), old, old)
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Expected expired entry to be ignored")
+	}
+
+	// A zero TTL disables the cache
+	disabled := NewResponseCache(t.TempDir(), 0)
+	disabled.Put("key", []byte("body"))
+	if _, ok := disabled.Get("key"); ok {
+		t.Error("Expected no caching with zero TTL")
+	}
+}
+
+func TestResponseCacheRevalidate(t *testing.T) {
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `W/"1"`)
+		if r.Header.Get("If-None-Match") == `W/"1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"value":[{"id":"a"}]}`))
+	}))
+	defer server.Close()
+
+	cache := NewResponseCache(t.TempDir(), time.Minute)
+	cache.Revalidate = time.Hour
+	client := &Client{httpClient: server.Client(), baseURL: server.URL, tokens: StaticToken("test-token")}
+	client.SetCache(cache)
+	ctx := context.Background()
+
+	expire := func(path string) {
+		old := time.Now().Add(-2 * time.Minute)
+		os.Chtimes(cache.path(server.URL+path), old, old)
+	}
+
+	if _, err := client.Get(ctx, "/me/messages"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	expire("/me/messages")
+	body, err := client.Get(ctx, "/me/messages")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if string(body) != `{"value":[{"id":"a"}]}` || requests != 2 || notModified != 1 {
+		t.Errorf("Expected the expired entry revalidated, got %s after %d requests (%d not modified)", body, requests, notModified)
+	}
+
+	// A 304 restarts the TTL
+	client.Get(ctx, "/me/messages")
+	if requests != 2 {
+		t.Errorf("Expected the revalidated entry to be fresh again, got %d requests", requests)
+	}
+
+	// Streamed collections are revalidated too
+	expire("/me/messages")
+	var ids []string
+	_, err = client.GetStream(ctx, "/me/messages", func(item json.RawMessage) error {
+		ids = append(ids, string(item))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GetStream failed: %v", err)
+	}
+	if len(ids) != 1 || notModified != 2 {
+		t.Errorf("Expected the streamed entry revalidated, got %v (%d not modified)", ids, notModified)
+	}
+
+	// Entries are dropped once past the revalidation window
+	old := time.Now().Add(-2 * time.Hour)
+	path := cache.path(server.URL + "/me/messages")
+	os.Chtimes(path, old, old)
+	if _, ok := cache.Get(server.URL + "/me/messages"); ok {
+		t.Error("Expected the old entry to miss")
+	}
+	if _, err := os.Stat(path + validatorsFileExt); !os.IsNotExist(err) {
+		t.Errorf("Expected the old entry's validators removed, got %v", err)
+	}
+}
@@ -4,25 +4,36 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
+	"sync"
 )
 
 // Event represents a calendar event from Microsoft Graph
 type Event struct {
-	ID              string             `json:"id,omitempty"`
-	Subject         string             `json:"subject,omitempty"`
-	Start           *DateTimeTimeZone  `json:"start,omitempty"`
-	End             *DateTimeTimeZone  `json:"end,omitempty"`
-	IsAllDay        bool               `json:"isAllDay,omitempty"`
-	Location        *Location          `json:"location,omitempty"`
-	Organizer       *Recipient         `json:"organizer,omitempty"`
-	Attendees       []*Attendee        `json:"attendees,omitempty"`
-	ResponseStatus  *ResponseStatus    `json:"responseStatus,omitempty"`
-	Body            *ItemBody          `json:"body,omitempty"`
-	OnlineMeeting   *OnlineMeetingInfo `json:"onlineMeeting,omitempty"`
-	IsOnlineMeeting bool               `json:"isOnlineMeeting,omitempty"`
-	WebLink         string             `json:"webLink,omitempty"`
-	CalendarID      string             `json:"calendarId,omitempty"` // Populated when using AllCalendars
+	ID              string               `json:"id,omitempty"`
+	ICalUID         string               `json:"iCalUId,omitempty"`
+	Subject         string               `json:"subject,omitempty"`
+	Start           *DateTimeTimeZone    `json:"start,omitempty"`
+	End             *DateTimeTimeZone    `json:"end,omitempty"`
+	IsAllDay        bool                 `json:"isAllDay,omitempty"`
+	Location        *Location            `json:"location,omitempty"`
+	Organizer       *Recipient           `json:"organizer,omitempty"`
+	Attendees       []*Attendee          `json:"attendees,omitempty"`
+	ResponseStatus  *ResponseStatus      `json:"responseStatus,omitempty"`
+	Importance      Importance           `json:"importance,omitempty"`
+	Sensitivity     Sensitivity          `json:"sensitivity,omitempty"`
+	ShowAs          FreeBusyStatus       `json:"showAs,omitempty"`
+	Body            *ItemBody            `json:"body,omitempty"`
+	OnlineMeeting   *OnlineMeetingInfo   `json:"onlineMeeting,omitempty"`
+	IsOnlineMeeting bool                 `json:"isOnlineMeeting,omitempty"`
+	IsCancelled     bool                 `json:"isCancelled,omitempty"`
+	Type            EventType            `json:"type,omitempty"`
+	SeriesMasterID  string               `json:"seriesMasterId,omitempty"`
+	Recurrence      *PatternedRecurrence `json:"recurrence,omitempty"`
+	WebLink         string               `json:"webLink,omitempty"`
+	CalendarID      string               `json:"calendarId,omitempty"` // Populated when using AllCalendars
+
+	// ETag changes whenever the event does; see WithIfMatch
+	ETag string `json:"@odata.etag,omitempty"`
 }
 
 // DateTimeTimeZone represents a date/time with timezone from Graph API
@@ -40,13 +51,13 @@ type Location struct {
 type Attendee struct {
 	EmailAddress *EmailAddress   `json:"emailAddress,omitempty"`
 	Status       *ResponseStatus `json:"status,omitempty"`
-	Type         string          `json:"type,omitempty"` // required, optional, resource
+	Type         AttendeeType    `json:"type,omitempty"`
 }
 
 // ResponseStatus represents a response to a meeting
 type ResponseStatus struct {
-	Response string `json:"response,omitempty"` // none, organizer, accepted, tentativelyAccepted, declined
-	Time     string `json:"time,omitempty"`
+	Response ResponseType `json:"response,omitempty"`
+	Time     string       `json:"time,omitempty"`
 }
 
 // OnlineMeetingInfo represents online meeting details
@@ -67,9 +78,12 @@ type CalendarViewOptions struct {
 	EndDateTime   string // ISO 8601 format
 	CalendarID    string // Empty = default calendar
 	AllCalendars  bool   // Query all calendars
+	Concurrency   int    // Calendars queried at once with AllCalendars (default 1)
 	Top           int
 	PageToken     string
-	UserID        string // Email or user ID for accessing another user's calendar
+	UserID        string   // Email or user ID for accessing another user's calendar
+	Select        []string // Properties to return ($select); empty for all
+	TimeZone      string   // Zone for event times (see WithTimeZone); empty for UTC
 }
 
 // CalendarViewResponse represents the response from CalendarView with pagination info
@@ -85,8 +99,10 @@ type ListEventsOptions struct {
 	CalendarID string
 	Top        int
 	PageToken  string
-	Filter     string // OData filter expression
-	OrderBy    string // OData orderby expression (e.g., "start/dateTime")
+	Filter     string   // OData filter expression
+	OrderBy    string   // OData orderby expression (e.g., "start/dateTime")
+	Select     []string // Properties to return ($select); empty for all
+	TimeZone   string   // Zone for event times (see WithTimeZone); empty for UTC
 }
 
 // ListEventsResponse represents the response from ListEvents with pagination
@@ -128,8 +144,8 @@ type TimeSlot struct {
 
 // AttendeeAvailability represents an attendee's availability for a slot
 type AttendeeAvailability struct {
-	Attendee     *AttendeeBase `json:"attendee"`
-	Availability string        `json:"availability"` // free, tentative, busy, oof, unknown
+	Attendee     *AttendeeBase  `json:"attendee"`
+	Availability FreeBusyStatus `json:"availability"`
 }
 
 // AttendeeBase represents basic attendee info
@@ -145,7 +161,7 @@ type FindMeetingTimesResponse struct {
 
 // ScheduleItem represents a busy/free time block
 type ScheduleItem struct {
-	Status  string            `json:"status"` // busy, tentative, oof, free
+	Status  FreeBusyStatus    `json:"status"`
 	Start   *DateTimeTimeZone `json:"start"`
 	End     *DateTimeTimeZone `json:"end"`
 	Subject string            `json:"subject,omitempty"`
@@ -190,8 +206,89 @@ func (c *Client) CalendarView(ctx context.Context, opts *CalendarViewOptions) (*
 	return c.calendarViewSingle(ctx, opts)
 }
 
+// StreamCalendarView lists events like CalendarView, but calls fn with each
+// event as it is decoded instead of collecting them. With AllCalendars the
+// calendars are queried in full first, and their events then passed to fn.
+// The response carries the count and pagination details but no Events.
+func (c *Client) StreamCalendarView(ctx context.Context, opts *CalendarViewOptions, fn func(*Event) error) (*CalendarViewResponse, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("options are required")
+	}
+
+	if opts.AllCalendars {
+		resp, err := c.CalendarView(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range resp.Events {
+			if err := fn(event); err != nil {
+				return nil, err
+			}
+		}
+		resp.Events = nil
+		return resp, nil
+	}
+
+	if opts.StartDateTime == "" || opts.EndDateTime == "" {
+		return nil, fmt.Errorf("startDateTime and endDateTime are required")
+	}
+	return c.streamCalendarViewSingle(ctx, opts, fn)
+}
+
 // calendarViewSingle retrieves events from a single calendar
 func (c *Client) calendarViewSingle(ctx context.Context, opts *CalendarViewOptions) (*CalendarViewResponse, error) {
+	events := []*Event{}
+	resp, err := c.streamCalendarViewSingle(ctx, opts, func(event *Event) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Events = events
+	return resp, nil
+}
+
+// streamCalendarViewSingle streams events from a single calendar
+func (c *Client) streamCalendarViewSingle(ctx context.Context, opts *CalendarViewOptions, fn func(*Event) error) (*CalendarViewResponse, error) {
+	count := 0
+	nextLink, err := c.GetStream(ctx, calendarViewPath(opts), func(item json.RawMessage) error {
+		var event Event
+		if err := json.Unmarshal(item, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal events: %w", err)
+		}
+		count++
+		return fn(&event)
+	}, timeZoneOptions(opts.TimeZone)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CalendarViewResponse{
+		Count:         count,
+		HasMore:       nextLink != "",
+		NextPageToken: ExtractPageToken(nextLink),
+	}, nil
+}
+
+// CalendarViewIterator returns an iterator over every event in the calendar
+// view opts selects, with recurring events expanded. AllCalendars isn't
+// supported; iterate over each calendar's view instead.
+func (c *Client) CalendarViewIterator(opts *CalendarViewOptions) (*PageIterator[Event], error) {
+	if opts == nil {
+		return nil, fmt.Errorf("options are required")
+	}
+	if opts.StartDateTime == "" || opts.EndDateTime == "" {
+		return nil, fmt.Errorf("startDateTime and endDateTime are required")
+	}
+	if opts.AllCalendars {
+		return nil, fmt.Errorf("cannot iterate over all calendars at once")
+	}
+	return NewPageIterator[Event](c, calendarViewPath(opts), timeZoneOptions(opts.TimeZone)...), nil
+}
+
+// calendarViewPath builds the request path and query for a calendar view
+func calendarViewPath(opts *CalendarViewOptions) string {
 	var path string
 	if opts.UserID != "" {
 		if opts.CalendarID != "" {
@@ -207,37 +304,18 @@ func (c *Client) calendarViewSingle(ctx context.Context, opts *CalendarViewOptio
 		}
 	}
 
-	params := url.Values{}
-	params.Set("startDateTime", opts.StartDateTime)
-	params.Set("endDateTime", opts.EndDateTime)
-
-	if opts.Top > 0 {
-		params.Set("$top", fmt.Sprintf("%d", opts.Top))
-	}
+	query := NewQuery().
+		Param("startDateTime", opts.StartDateTime).
+		Param("endDateTime", opts.EndDateTime).
+		Top(opts.Top).
+		Select(opts.Select...)
 
 	if opts.PageToken != "" {
 		// PageToken contains the skip value
-		params.Set("$skip", opts.PageToken)
+		query.Param("$skip", opts.PageToken)
 	}
 
-	data, err := c.Get(ctx, path+"?"+params.Encode())
-	if err != nil {
-		return nil, err
-	}
-
-	var eventList EventList
-	if err := json.Unmarshal(data, &eventList); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal events: %w", err)
-	}
-
-	nextPageToken := ExtractPageToken(eventList.NextLink)
-
-	return &CalendarViewResponse{
-		Events:        eventList.Value,
-		Count:         len(eventList.Value),
-		HasMore:       eventList.NextLink != "",
-		NextPageToken: nextPageToken,
-	}, nil
+	return query.Path(path)
 }
 
 // calendarViewAllCalendars retrieves events from all user's calendars
@@ -248,29 +326,50 @@ func (c *Client) calendarViewAllCalendars(ctx context.Context, opts *CalendarVie
 		return nil, fmt.Errorf("failed to list calendars: %w", err)
 	}
 
-	var allEvents []*Event
-
-	// Query each calendar
-	for _, cal := range calendars {
-		calOpts := &CalendarViewOptions{
-			StartDateTime: opts.StartDateTime,
-			EndDateTime:   opts.EndDateTime,
-			CalendarID:    cal.ID,
-			Top:           opts.Top,
-		}
-
-		resp, err := c.calendarViewSingle(ctx, calOpts)
-		if err != nil {
-			// Log but continue with other calendars
-			continue
-		}
+	workers := opts.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	// Query calendars in parallel, keeping results in calendar order
+	results := make([][]*Event, len(calendars))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, cal := range calendars {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			calOpts := &CalendarViewOptions{
+				StartDateTime: opts.StartDateTime,
+				EndDateTime:   opts.EndDateTime,
+				CalendarID:    cal.ID,
+				Top:           opts.Top,
+				Select:        opts.Select,
+				TimeZone:      opts.TimeZone,
+			}
+
+			resp, err := c.calendarViewSingle(ctx, calOpts)
+			if err != nil {
+				// Log but continue with other calendars
+				c.log().Debug("skipping calendar", "calendar", cal.ID, "error", err)
+				return
+			}
+
+			// Add calendar ID to each event
+			for _, event := range resp.Events {
+				event.CalendarID = cal.ID
+			}
+			results[i] = resp.Events
+		}()
+	}
+	wg.Wait()
 
-		// Add calendar ID to each event
-		for _, event := range resp.Events {
-			event.CalendarID = cal.ID
-		}
-
-		allEvents = append(allEvents, resp.Events...)
+	var allEvents []*Event
+	for _, events := range results {
+		allEvents = append(allEvents, events...)
 	}
 
 	// Note: Pagination is not supported for all-calendars mode
@@ -297,19 +396,25 @@ func (c *Client) ListCalendars(ctx context.Context) ([]*Calendar, error) {
 	return calendarList.Value, nil
 }
 
+// CalendarIterator returns an iterator over all of the user's calendars
+func (c *Client) CalendarIterator() *PageIterator[Calendar] {
+	return NewPageIterator[Calendar](c, "/me/calendars")
+}
+
+// eventResponseActions maps calendar responses to their Graph actions
+var eventResponseActions = map[string]string{
+	"accept":    "accept",
+	"decline":   "decline",
+	"tentative": "tentativelyAccept",
+}
+
 // RespondToEvent responds to a calendar invitation (accept, decline, tentativelyAccept)
 func (c *Client) RespondToEvent(ctx context.Context, eventID, response, message string) error {
 	if eventID == "" {
 		return fmt.Errorf("event ID is required")
 	}
 
-	validResponses := map[string]string{
-		"accept":    "accept",
-		"decline":   "decline",
-		"tentative": "tentativelyAccept",
-	}
-
-	endpoint, ok := validResponses[response]
+	endpoint, ok := eventResponseActions[response]
 	if !ok {
 		return fmt.Errorf("invalid response: %s (must be accept, decline, or tentative)", response)
 	}
@@ -380,7 +485,7 @@ func (c *Client) FindMeetingTimes(ctx context.Context, opts *FindTimeOptions) (*
 	// Build request body
 	type attendeeType struct {
 		EmailAddress EmailAddress `json:"emailAddress"`
-		Type         string       `json:"type"`
+		Type         AttendeeType `json:"type"`
 	}
 	type timeConstraint struct {
 		ActivityDomain string `json:"activityDomain"`
@@ -405,7 +510,7 @@ func (c *Client) FindMeetingTimes(ctx context.Context, opts *FindTimeOptions) (*
 	for _, email := range opts.Attendees {
 		body.Attendees = append(body.Attendees, attendeeType{
 			EmailAddress: EmailAddress{Address: email},
-			Type:         "required",
+			Type:         AttendeeRequired,
 		})
 	}
 
@@ -468,35 +573,39 @@ func (c *Client) CreateEvent(ctx context.Context, event *Event, calendarID strin
 	return &created, nil
 }
 
-// ListEvents retrieves raw events (including series masters for recurring)
-func (c *Client) ListEvents(ctx context.Context, opts *ListEventsOptions) (*ListEventsResponse, error) {
-	path := "/me/events"
-	if opts != nil && opts.CalendarID != "" {
-		path = fmt.Sprintf("/me/calendars/%s/events", opts.CalendarID)
+// UpdateEvent changes the properties of an event set in changes and returns
+// the updated event. changes may be an *Event with only the fields to change
+// set, or a map for values an Event would omit, such as {"isAllDay": false}.
+// Updating a meeting you organize sends the attendees an update. WithIfMatch
+// makes it fail if the event changed since it was read.
+func (c *Client) UpdateEvent(ctx context.Context, eventID string, changes interface{}, opts ...RequestOption) (*Event, error) {
+	if eventID == "" {
+		return nil, fmt.Errorf("event ID is required")
+	}
+	if changes == nil {
+		return nil, fmt.Errorf("changes are required")
 	}
 
-	params := url.Values{}
-	if opts != nil {
-		if opts.Top > 0 {
-			params.Set("$top", fmt.Sprintf("%d", opts.Top))
-		}
-		if opts.PageToken != "" {
-			params.Set("$skip", opts.PageToken)
-		}
-		if opts.Filter != "" {
-			params.Set("$filter", opts.Filter)
-		}
-		if opts.OrderBy != "" {
-			params.Set("$orderby", opts.OrderBy)
-		}
+	data, err := c.Patch(ctx, fmt.Sprintf("/me/events/%s", eventID), changes, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	fullPath := path
-	if len(params) > 0 {
-		fullPath = path + "?" + params.Encode()
+	var updated Event
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal updated event: %w", err)
 	}
 
-	data, err := c.Get(ctx, fullPath)
+	return &updated, nil
+}
+
+// ListEvents retrieves raw events (including series masters for recurring)
+func (c *Client) ListEvents(ctx context.Context, opts *ListEventsOptions) (*ListEventsResponse, error) {
+	var reqOpts []RequestOption
+	if opts != nil {
+		reqOpts = timeZoneOptions(opts.TimeZone)
+	}
+	data, err := c.Get(ctx, eventsPath(opts), reqOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -516,11 +625,42 @@ func (c *Client) ListEvents(ctx context.Context, opts *ListEventsOptions) (*List
 	}, nil
 }
 
+// EventIterator returns an iterator over every event opts selects,
+// including series masters rather than their occurrences
+func (c *Client) EventIterator(opts *ListEventsOptions) *PageIterator[Event] {
+	var reqOpts []RequestOption
+	if opts != nil {
+		reqOpts = timeZoneOptions(opts.TimeZone)
+	}
+	return NewPageIterator[Event](c, eventsPath(opts), reqOpts...)
+}
+
+// eventsPath builds the request path and query for listing events
+func eventsPath(opts *ListEventsOptions) string {
+	path := "/me/events"
+	if opts != nil && opts.CalendarID != "" {
+		path = fmt.Sprintf("/me/calendars/%s/events", opts.CalendarID)
+	}
+
+	query := NewQuery()
+	if opts != nil {
+		query.Top(opts.Top).Filter(opts.Filter).Select(opts.Select...)
+		if opts.PageToken != "" {
+			query.Param("$skip", opts.PageToken)
+		}
+		if opts.OrderBy != "" {
+			query.OrderBy(opts.OrderBy)
+		}
+	}
+	return query.Path(path)
+}
+
 // GetEventOptions represents options for getting an event
 type GetEventOptions struct {
 	EventID    string
 	CalendarID string
 	UserID     string
+	TimeZone   string // Zone for event times (see WithTimeZone); empty for UTC
 }
 
 // GetEvent retrieves a specific event by ID
@@ -552,7 +692,7 @@ func (c *Client) GetEventWithOptions(ctx context.Context, opts *GetEventOptions)
 		}
 	}
 
-	data, err := c.Get(ctx, path)
+	data, err := c.Get(ctx, path, timeZoneOptions(opts.TimeZone)...)
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -75,9 +77,9 @@ func TestCalendarView(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -123,9 +125,9 @@ func TestCalendarViewWithCalendarID(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -160,9 +162,9 @@ func TestCalendarViewWithPagination(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -185,11 +187,79 @@ func TestCalendarViewWithPagination(t *testing.T) {
 	}
 }
 
+func TestCalendarViewWithSelect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sel := r.URL.Query().Get("$select"); sel != "id,subject,start,end" {
+			t.Errorf("Expected $select=id,subject,start,end, got %s", sel)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(EventList{Value: []*Event{}})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	opts := &CalendarViewOptions{
+		StartDateTime: "2025-01-15T00:00:00Z",
+		EndDateTime:   "2025-01-16T00:00:00Z",
+		Select:        []string{"id", "subject", "start", "end"},
+	}
+	if _, err := client.CalendarView(context.Background(), opts); err != nil {
+		t.Fatalf("CalendarView failed: %v", err)
+	}
+}
+
+func TestEventTimeZone(t *testing.T) {
+	var prefers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefers = append(prefers, r.Header.Get("Prefer"))
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/evt1") {
+			json.NewEncoder(w).Encode(Event{ID: "evt1"})
+			return
+		}
+		json.NewEncoder(w).Encode(EventList{Value: []*Event{}})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+	ctx := context.Background()
+
+	opts := &CalendarViewOptions{
+		StartDateTime: "2025-01-15T00:00:00Z",
+		EndDateTime:   "2025-01-16T00:00:00Z",
+		TimeZone:      "Pacific/Auckland",
+	}
+	if _, err := client.CalendarView(ctx, opts); err != nil {
+		t.Fatalf("CalendarView failed: %v", err)
+	}
+	if _, err := client.ListEvents(ctx, &ListEventsOptions{TimeZone: "New Zealand Standard Time"}); err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if _, err := client.GetEventWithOptions(ctx, &GetEventOptions{EventID: "evt1"}); err != nil {
+		t.Fatalf("GetEventWithOptions failed: %v", err)
+	}
+
+	want := []string{`outlook.timezone="Pacific/Auckland"`, `outlook.timezone="New Zealand Standard Time"`, ""}
+	if !slices.Equal(prefers, want) {
+		t.Errorf("Expected Prefer headers %q, got %q", want, prefers)
+	}
+}
+
 func TestCalendarViewMissingOptions(t *testing.T) {
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     "http://localhost",
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    "http://localhost",
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -258,9 +328,9 @@ func TestGetEvent(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -304,9 +374,9 @@ func TestGetEventWithCalendarID(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -318,9 +388,9 @@ func TestGetEventWithCalendarID(t *testing.T) {
 
 func TestGetEventEmptyID(t *testing.T) {
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     "http://localhost",
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    "http://localhost",
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -345,9 +415,9 @@ func TestRespondToEvent(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -389,9 +459,9 @@ func TestGetSchedule(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -443,9 +513,9 @@ func TestFindMeetingTimes(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -495,9 +565,9 @@ func TestCreateEvent(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -547,9 +617,9 @@ func TestListEvents(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -592,9 +662,9 @@ func TestListCalendars(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -611,3 +681,39 @@ func TestListCalendars(t *testing.T) {
 		t.Errorf("Expected name 'Calendar', got '%s'", calendars[0].Name)
 	}
 }
+
+func TestUpdateEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/me/events/evt1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["subject"] != "Moved" || len(body) != 2 {
+			t.Errorf("Expected subject and location only, got %v", body)
+		}
+		json.NewEncoder(w).Encode(Event{ID: "evt1", Subject: "Moved"})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	updated, err := client.UpdateEvent(context.Background(), "evt1", &Event{
+		Subject:  "Moved",
+		Location: &Location{DisplayName: "Room 2"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	if updated.ID != "evt1" || updated.Subject != "Moved" {
+		t.Errorf("Unexpected updated event %+v", updated)
+	}
+
+	if _, err := client.UpdateEvent(context.Background(), "", &Event{}); err == nil {
+		t.Error("Expected error for missing event ID")
+	}
+}
@@ -0,0 +1,191 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CallRecord represents a Teams or Skype for Business call or online meeting
+// record. Requires the CallRecords.Read.All application permission.
+type CallRecord struct {
+	ID                   string         `json:"id,omitempty"`
+	Version              int64          `json:"version,omitempty"`
+	Type                 string         `json:"type,omitempty"` // peerToPeer, groupCall, unknown
+	Modalities           []string       `json:"modalities,omitempty"`
+	StartDateTime        *time.Time     `json:"startDateTime,omitempty"`
+	EndDateTime          *time.Time     `json:"endDateTime,omitempty"`
+	LastModifiedDateTime *time.Time     `json:"lastModifiedDateTime,omitempty"`
+	JoinWebURL           string         `json:"joinWebUrl,omitempty"`
+	Organizer            *Identity      `json:"organizer,omitempty"`
+	Participants         []*Identity    `json:"participants,omitempty"`
+	Sessions             []*CallSession `json:"sessions,omitempty"`
+}
+
+// CallSession represents a communication between two endpoints within a call
+type CallSession struct {
+	ID            string           `json:"id,omitempty"`
+	Modalities    []string         `json:"modalities,omitempty"`
+	StartDateTime *time.Time       `json:"startDateTime,omitempty"`
+	EndDateTime   *time.Time       `json:"endDateTime,omitempty"`
+	Caller        *CallEndpoint    `json:"caller,omitempty"`
+	Callee        *CallEndpoint    `json:"callee,omitempty"`
+	FailureInfo   *CallFailureInfo `json:"failureInfo,omitempty"`
+	Segments      []*CallSegment   `json:"segments,omitempty"`
+}
+
+// CallSegment represents a hop within a session (e.g. client to media server)
+type CallSegment struct {
+	ID            string           `json:"id,omitempty"`
+	StartDateTime *time.Time       `json:"startDateTime,omitempty"`
+	EndDateTime   *time.Time       `json:"endDateTime,omitempty"`
+	Caller        *CallEndpoint    `json:"caller,omitempty"`
+	Callee        *CallEndpoint    `json:"callee,omitempty"`
+	FailureInfo   *CallFailureInfo `json:"failureInfo,omitempty"`
+	Media         []*CallMedia     `json:"media,omitempty"`
+}
+
+// CallEndpoint represents a participant endpoint (participantEndpoint or serviceEndpoint)
+type CallEndpoint struct {
+	ODataType string         `json:"@odata.type,omitempty"`
+	Identity  *Identity      `json:"identity,omitempty"`
+	UserAgent *CallUserAgent `json:"userAgent,omitempty"`
+}
+
+// Name returns the endpoint's identity name, or its kind for service endpoints
+func (e *CallEndpoint) Name() string {
+	if e.Identity != nil {
+		if name := e.Identity.Name(); name != "" {
+			return name
+		}
+	}
+	return strings.TrimPrefix(e.ODataType, "#microsoft.graph.callRecords.")
+}
+
+// CallUserAgent describes the client or service software of an endpoint
+type CallUserAgent struct {
+	HeaderValue        string `json:"headerValue,omitempty"`
+	ApplicationVersion string `json:"applicationVersion,omitempty"`
+	Platform           string `json:"platform,omitempty"`
+	ProductFamily      string `json:"productFamily,omitempty"`
+}
+
+// CallFailureInfo describes why a session or segment failed
+type CallFailureInfo struct {
+	Stage  string `json:"stage,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// CallMedia represents the media (audio, video, screen sharing) used in a segment
+type CallMedia struct {
+	Label   string             `json:"label,omitempty"`
+	Streams []*CallMediaStream `json:"streams,omitempty"`
+}
+
+// CallMediaStream holds quality metrics for one direction of a media stream.
+// Jitter and round trip times are ISO 8601 durations (e.g. PT0.015S).
+type CallMediaStream struct {
+	StreamID                    string   `json:"streamId,omitempty"`
+	StreamDirection             string   `json:"streamDirection,omitempty"` // callerToCallee, calleeToCaller
+	AverageJitter               string   `json:"averageJitter,omitempty"`
+	MaxJitter                   string   `json:"maxJitter,omitempty"`
+	AverageRoundTripTime        string   `json:"averageRoundTripTime,omitempty"`
+	MaxRoundTripTime            string   `json:"maxRoundTripTime,omitempty"`
+	AveragePacketLossRate       *float64 `json:"averagePacketLossRate,omitempty"`
+	MaxPacketLossRate           *float64 `json:"maxPacketLossRate,omitempty"`
+	PacketUtilization           int64    `json:"packetUtilization,omitempty"`
+	WasMediaBitrateInsufficient *bool    `json:"wasMediaBitrateInsufficient,omitempty"`
+}
+
+// CallRecordList represents a list of call records returned by Graph API
+type CallRecordList struct {
+	Value    []*CallRecord `json:"value"`
+	NextLink string        `json:"@odata.nextLink,omitempty"`
+}
+
+// ListCallRecordsOptions represents options for listing call records
+type ListCallRecordsOptions struct {
+	StartTime *time.Time // Only calls starting at or after this time
+	EndTime   *time.Time // Only calls starting before this time
+	PageToken string
+}
+
+// ListCallRecordsResponse represents the response from ListCallRecords with pagination info
+type ListCallRecordsResponse struct {
+	Records       []*CallRecord
+	Count         int
+	HasMore       bool
+	NextPageToken string
+}
+
+// ListCallRecords retrieves call records for the tenant
+func (c *Client) ListCallRecords(ctx context.Context, opts *ListCallRecordsOptions) (*ListCallRecordsResponse, error) {
+	params := url.Values{}
+	if opts != nil {
+		filters := []string{}
+		if opts.StartTime != nil {
+			filters = append(filters, fmt.Sprintf("startDateTime ge %s", opts.StartTime.UTC().Format(time.RFC3339)))
+		}
+		if opts.EndTime != nil {
+			filters = append(filters, fmt.Sprintf("startDateTime lt %s", opts.EndTime.UTC().Format(time.RFC3339)))
+		}
+		if len(filters) > 0 {
+			params.Set("$filter", strings.Join(filters, " and "))
+		}
+		if opts.PageToken != "" {
+			params.Set("$skiptoken", opts.PageToken)
+		}
+	}
+
+	path := "/communications/callRecords"
+	if len(params) > 0 {
+		path = path + "?" + params.Encode()
+	}
+
+	data, err := c.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var recordList CallRecordList
+	if err := json.Unmarshal(data, &recordList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal call records: %w", err)
+	}
+
+	return &ListCallRecordsResponse{
+		Records:       recordList.Value,
+		Count:         len(recordList.Value),
+		HasMore:       recordList.NextLink != "",
+		NextPageToken: ExtractPageToken(recordList.NextLink),
+	}, nil
+}
+
+// GetCallRecord retrieves a call record by ID. When withSessions is true, the sessions
+// and their segments (including media quality metrics) are expanded.
+func (c *Client) GetCallRecord(ctx context.Context, recordID string, withSessions bool) (*CallRecord, error) {
+	if recordID == "" {
+		return nil, fmt.Errorf("call record ID is required")
+	}
+
+	path := fmt.Sprintf("/communications/callRecords/%s", recordID)
+	if withSessions {
+		params := url.Values{}
+		params.Set("$expand", "sessions($expand=segments)")
+		path = path + "?" + params.Encode()
+	}
+
+	data, err := c.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var record CallRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal call record: %w", err)
+	}
+
+	return &record, nil
+}
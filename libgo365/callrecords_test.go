@@ -0,0 +1,101 @@
+package libgo365
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListCallRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/communications/callRecords" {
+			t.Errorf("Expected path /communications/callRecords, got %s", r.URL.Path)
+		}
+		want := "startDateTime ge 2024-03-01T00:00:00Z and startDateTime lt 2024-03-02T00:00:00Z"
+		if filter := r.URL.Query().Get("$filter"); filter != want {
+			t.Errorf("Expected filter %q, got %q", want, filter)
+		}
+		w.Write([]byte(`{
+			"value": [{
+				"id": "rec1",
+				"type": "groupCall",
+				"modalities": ["audio", "video"],
+				"startDateTime": "2024-03-01T09:00:00Z",
+				"endDateTime": "2024-03-01T09:30:00Z",
+				"organizer": {"user": {"id": "u1", "displayName": "Jane"}}
+			}],
+			"@odata.nextLink": "https://graph.microsoft.com/v1.0/communications/callRecords?$skiptoken=next1"
+		}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	resp, err := client.ListCallRecords(context.Background(), &ListCallRecordsOptions{StartTime: &start, EndTime: &end})
+	if err != nil {
+		t.Fatalf("ListCallRecords failed: %v", err)
+	}
+	if len(resp.Records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(resp.Records))
+	}
+	if resp.Records[0].Organizer.Name() != "Jane" {
+		t.Errorf("Expected organizer Jane, got %s", resp.Records[0].Organizer.Name())
+	}
+	if resp.NextPageToken != "next1" {
+		t.Errorf("Expected next page token 'next1', got '%s'", resp.NextPageToken)
+	}
+}
+
+func TestGetCallRecordWithSessions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/communications/callRecords/rec1" {
+			t.Errorf("Expected path /communications/callRecords/rec1, got %s", r.URL.Path)
+		}
+		if expand := r.URL.Query().Get("$expand"); expand != "sessions($expand=segments)" {
+			t.Errorf("Unexpected $expand: %s", expand)
+		}
+		w.Write([]byte(`{
+			"id": "rec1",
+			"sessions": [{
+				"id": "s1",
+				"caller": {"@odata.type": "#microsoft.graph.callRecords.participantEndpoint", "identity": {"user": {"displayName": "Jane"}}},
+				"callee": {"@odata.type": "#microsoft.graph.callRecords.serviceEndpoint"},
+				"segments": [{
+					"id": "seg1",
+					"media": [{"label": "main-audio", "streams": [{"streamDirection": "callerToCallee", "averageJitter": "PT0.015S", "averagePacketLossRate": 0.01}]}]
+				}]
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	record, err := client.GetCallRecord(context.Background(), "rec1", true)
+	if err != nil {
+		t.Fatalf("GetCallRecord failed: %v", err)
+	}
+	if len(record.Sessions) != 1 || len(record.Sessions[0].Segments) != 1 {
+		t.Fatalf("Expected 1 session with 1 segment, got %+v", record.Sessions)
+	}
+	session := record.Sessions[0]
+	if session.Caller.Name() != "Jane" || session.Callee.Name() != "serviceEndpoint" {
+		t.Errorf("Unexpected endpoints: %s -> %s", session.Caller.Name(), session.Callee.Name())
+	}
+	stream := session.Segments[0].Media[0].Streams[0]
+	if stream.AveragePacketLossRate == nil || *stream.AveragePacketLossRate != 0.01 {
+		t.Errorf("Unexpected packet loss rate: %v", stream.AveragePacketLossRate)
+	}
+}
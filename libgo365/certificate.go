@@ -0,0 +1,58 @@
+package libgo365
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// LoadCertificate reads a certificate and its private key from a PEM file or
+// a PFX (PKCS#12) file, as exported from Windows or Azure Key Vault. The
+// first certificate returned is the one the key belongs to. password
+// decrypts the key and may be empty. Expired certificates are refused, since
+// Microsoft Entra would only reject them with a less helpful error.
+func LoadCertificate(path, password string) ([]*x509.Certificate, crypto.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+
+	var certs []*x509.Certificate
+	var key crypto.PrivateKey
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		if certs, key, err = confidential.CertFromPEM(data, password); err != nil {
+			return nil, nil, fmt.Errorf("failed to load certificate %s: %w", path, err)
+		}
+	} else {
+		var cert *x509.Certificate
+		var chain []*x509.Certificate
+		if key, cert, chain, err = pkcs12.DecodeChain(data, password); err != nil {
+			return nil, nil, fmt.Errorf("failed to load certificate %s: %w", path, err)
+		}
+		certs = append([]*x509.Certificate{cert}, chain...)
+	}
+
+	if len(certs) == 0 || key == nil {
+		return nil, nil, fmt.Errorf("%s needs both a certificate and its private key", path)
+	}
+	if expiry := certs[0].NotAfter; time.Now().After(expiry) {
+		return nil, nil, fmt.Errorf("certificate %s expired on %s", path, expiry.Format("2006-01-02"))
+	}
+	return certs, key, nil
+}
+
+// CertificateThumbprint returns a certificate's SHA-1 thumbprint in the
+// form the Azure portal lists it under the app's certificates
+func CertificateThumbprint(cert *x509.Certificate) string {
+	sum := sha1.Sum(cert.Raw)
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
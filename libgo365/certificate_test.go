@@ -0,0 +1,144 @@
+package libgo365
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// testCertificate creates a self-signed certificate valid until notAfter
+func testCertificate(t *testing.T, notAfter time.Time) (*x509.Certificate, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "go365 test"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func writeTestPEM(t *testing.T, cert *x509.Certificate, key *rsa.PrivateKey) string {
+	t.Helper()
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})...)
+
+	path := filepath.Join(t.TempDir(), "app.pem")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadCertificatePEM(t *testing.T) {
+	cert, key := testCertificate(t, time.Now().Add(24*time.Hour))
+	path := writeTestPEM(t, cert, key)
+
+	certs, loadedKey, err := LoadCertificate(path, "")
+	if err != nil {
+		t.Fatalf("LoadCertificate failed: %v", err)
+	}
+	if len(certs) != 1 || !certs[0].Equal(cert) {
+		t.Errorf("Expected the test certificate, got %d certificates", len(certs))
+	}
+	if !key.Equal(loadedKey) {
+		t.Error("Expected the test key")
+	}
+}
+
+func TestLoadCertificatePFX(t *testing.T) {
+	cert, key := testCertificate(t, time.Now().Add(24*time.Hour))
+	pfx, err := pkcs12.Modern.Encode(key, cert, nil, "pfx-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "app.pfx")
+	if err := os.WriteFile(path, pfx, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	certs, loadedKey, err := LoadCertificate(path, "pfx-pass")
+	if err != nil {
+		t.Fatalf("LoadCertificate failed: %v", err)
+	}
+	if len(certs) != 1 || !certs[0].Equal(cert) || !key.Equal(loadedKey) {
+		t.Error("Expected the test certificate and key")
+	}
+
+	if _, _, err := LoadCertificate(path, "wrong"); err == nil {
+		t.Error("Expected a wrong password to fail")
+	}
+}
+
+func TestLoadCertificateExpired(t *testing.T) {
+	cert, key := testCertificate(t, time.Now().Add(-24*time.Hour))
+	path := writeTestPEM(t, cert, key)
+
+	_, _, err := LoadCertificate(path, "")
+	if err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expected an expired certificate error, got %v", err)
+	}
+}
+
+func TestCertificateThumbprint(t *testing.T) {
+	cert, _ := testCertificate(t, time.Now().Add(24*time.Hour))
+	sum := sha1.Sum(cert.Raw)
+	want := strings.ToUpper(hex.EncodeToString(sum[:]))
+	if got := CertificateThumbprint(cert); got != want || len(got) != 40 {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestCertificateAuthenticator(t *testing.T) {
+	cert, key := testCertificate(t, time.Now().Add(24*time.Hour))
+	auth, err := NewAuthenticator(AuthConfig{
+		TenantID:        "contoso.onmicrosoft.com",
+		ClientID:        "00000000-0000-0000-0000-000000000000",
+		CertificatePath: writeTestPEM(t, cert, key),
+	}, WithTokenStore(&memoryStore{}))
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	if !auth.AppOnly() {
+		t.Error("Expected a certificate to select app-only authentication")
+	}
+	if auth.CertificateThumbprint() != CertificateThumbprint(cert) {
+		t.Errorf("Expected thumbprint %s, got %s", CertificateThumbprint(cert), auth.CertificateThumbprint())
+	}
+
+	if _, err := NewAuthenticator(AuthConfig{
+		TenantID:        "contoso.onmicrosoft.com",
+		ClientID:        "00000000-0000-0000-0000-000000000000",
+		CertificatePath: filepath.Join(t.TempDir(), "missing.pem"),
+	}, WithTokenStore(&memoryStore{})); err == nil {
+		t.Error("Expected a missing certificate to fail")
+	}
+}
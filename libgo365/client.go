@@ -3,142 +3,374 @@ package libgo365
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 )
 
+// Graph API versions for SetAPIVersion
 const (
+	APIVersionV1   = "v1.0"
+	APIVersionBeta = "beta"
+)
+
+// APIVersions lists the Graph API versions a client can call
+var APIVersions = []string{APIVersionV1, APIVersionBeta}
+
+const (
+	// graphRootURL is the Graph endpoint without an API version
+	graphRootURL = "https://graph.microsoft.com"
+
 	// GraphAPIBaseURL is the base URL for Microsoft Graph API
-	GraphAPIBaseURL = "https://graph.microsoft.com/v1.0"
+	GraphAPIBaseURL = graphRootURL + "/" + APIVersionV1
+
+	// GraphBetaBaseURL is the base URL for the Microsoft Graph beta API
+	GraphBetaBaseURL = graphRootURL + "/" + APIVersionBeta
 )
 
+// GraphBaseURL returns the base URL for a Graph API version
+func GraphBaseURL(version string) string {
+	return graphRootURL + "/" + version
+}
+
 // Client is a Microsoft Graph API client
 type Client struct {
 	httpClient  *http.Client
+	transport   http.RoundTripper // Beneath the middleware; nil for http.DefaultTransport
+	middleware  []Middleware      // Added with Use
 	baseURL     string
-	accessToken string
+	tokens      TokenProvider
+	logger      *slog.Logger
+	cache       *ResponseCache
+	limiter     *RateLimiter
+	breaker     *CircuitBreaker
+	maxRetries  int
+	requestHook func(*RequestRecord)
 }
 
-// NewClient creates a new Microsoft Graph client
+// NewClient creates a new Microsoft Graph client that sends a fixed access
+// token. The token isn't refreshed, so long-running programs should use
+// NewClientWithTokenProvider.
 func NewClient(ctx context.Context, accessToken string) *Client {
-	return &Client{
-		httpClient:  &http.Client{},
-		baseURL:     GraphAPIBaseURL,
-		accessToken: accessToken,
+	return NewClientWithTokenProvider(ctx, StaticToken(accessToken))
+}
+
+// NewClientWithTokenProvider creates a new Microsoft Graph client that asks
+// tokens for an access token on every request
+func NewClientWithTokenProvider(ctx context.Context, tokens TokenProvider) *Client {
+	c := &Client{
+		baseURL:    GraphBaseURL(APIVersionV1),
+		tokens:     tokens,
+		breaker:    NewCircuitBreaker(DefaultBreakerThreshold, DefaultRetryBudget),
+		maxRetries: DefaultMaxRetries,
 	}
+	c.SetTransport(nil)
+	return c
 }
 
-// addAuthHeader adds the authorization header to a request
-func (c *Client) addAuthHeader(req *http.Request) {
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+// SetTransport sends the client's requests through rt, beneath its retries,
+// middleware, logging, compression, circuit breaker and rate limiting. Tests
+// use it to replay recorded responses. nil restores http.DefaultTransport.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.transport = rt
+	c.buildTransport()
 }
 
-// Get performs a GET request to the Microsoft Graph API
-func (c *Client) Get(ctx context.Context, path string) ([]byte, error) {
-	url := c.baseURL + path
+// SetCache makes GET requests read through cache; nil disables caching
+func (c *Client) SetCache(cache *ResponseCache) {
+	c.cache = cache
+}
+
+// uncached returns a copy of the client that bypasses the response cache, for
+// polling and other reads that must always be fresh
+func (c *Client) uncached() *Client {
+	u := *c
+	u.cache = nil
+	return &u
+}
+
+// invalidateCache drops cached responses after a request that may change data
+func (c *Client) invalidateCache() {
+	if c.cache != nil {
+		c.cache.Clear()
+	}
+}
+
+// SetAPIVersion points the client at a Graph API version: APIVersionV1, the
+// default, or APIVersionBeta for features not yet in v1.0. Beta APIs can
+// change without notice. Clients pointed somewhere other than Graph (such as
+// a test server) keep their base URL.
+func (c *Client) SetAPIVersion(version string) error {
+	if !slices.Contains(APIVersions, version) {
+		return fmt.Errorf("unsupported Graph API version %q (use v1.0 or beta)", version)
+	}
+	if c.onGraph() {
+		c.baseURL = GraphBaseURL(version)
+	}
+	return nil
+}
+
+// APIVersion returns the Graph API version the client calls, or "" if it is
+// pointed somewhere other than Graph
+func (c *Client) APIVersion() string {
+	if !c.onGraph() {
+		return ""
+	}
+	return strings.TrimPrefix(c.baseURL, graphRootURL+"/")
+}
+
+// onGraph reports whether the client calls one of Graph's versions
+func (c *Client) onGraph() bool {
+	return slices.ContainsFunc(APIVersions, func(version string) bool {
+		return c.baseURL == GraphBaseURL(version)
+	})
+}
+
+// beta returns a copy of the client that calls the beta endpoint, for the
+// few features only there
+func (c *Client) beta() *Client {
+	b := *c
+	b.SetAPIVersion(APIVersionBeta)
+	return &b
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// addAuthHeader adds the authorization header to a request
+func (c *Client) addAuthHeader(req *http.Request) error {
+	token, err := c.tokens.Token(req.Context())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to get access token: %w", err)
 	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
 
-	c.addAuthHeader(req)
+// Get performs a GET request to the Microsoft Graph API
+func (c *Client) Get(ctx context.Context, path string, opts ...RequestOption) ([]byte, error) {
+	o := newRequestOptions(opts)
+	url := c.requestURL(path, o)
+	cacheKey := o.cacheKey(url)
+
+	var cached []byte
+	if c.cache != nil {
+		if body, ok := c.cache.Get(cacheKey); ok {
+			c.log().Debug("graph cache hit", "url", url)
+			return body, nil
+		}
+		cached = c.cache.conditional(cacheKey, o.header)
+	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(ctx, "GET", url, "", nil, o)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		c.log().Debug("graph cache revalidated", "url", url)
+		c.cache.touch(cacheKey)
+		return cached, nil
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newResponseError(resp, body)
+	}
+
+	if c.cache != nil {
+		c.cache.putResponse(cacheKey, body, resp.Header)
 	}
 
 	return body, nil
 }
 
 // Post performs a POST request to the Microsoft Graph API
-func (c *Client) Post(ctx context.Context, path string, data interface{}) ([]byte, error) {
-	return c.doJSONRequest(ctx, "POST", path, data)
+func (c *Client) Post(ctx context.Context, path string, data interface{}, opts ...RequestOption) ([]byte, error) {
+	return c.doJSONRequest(ctx, "POST", path, data, opts...)
 }
 
 // Put performs a PUT request to the Microsoft Graph API
-func (c *Client) Put(ctx context.Context, path string, data interface{}) ([]byte, error) {
-	return c.doJSONRequest(ctx, "PUT", path, data)
+func (c *Client) Put(ctx context.Context, path string, data interface{}, opts ...RequestOption) ([]byte, error) {
+	return c.doJSONRequest(ctx, "PUT", path, data, opts...)
+}
+
+// Patch performs a PATCH request to the Microsoft Graph API
+func (c *Client) Patch(ctx context.Context, path string, data interface{}, opts ...RequestOption) ([]byte, error) {
+	return c.doJSONRequest(ctx, "PATCH", path, data, opts...)
 }
 
 // Delete performs a DELETE request to the Microsoft Graph API
-func (c *Client) Delete(ctx context.Context, path string) error {
-	url := c.baseURL + path
+func (c *Client) Delete(ctx context.Context, path string, opts ...RequestOption) error {
+	o := newRequestOptions(opts)
+	url := c.requestURL(path, o)
+	defer c.invalidateCache()
 
-	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	resp, err := c.send(ctx, "DELETE", url, "", nil, o)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
+	defer resp.Body.Close()
 
-	c.addAuthHeader(req)
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return newResponseError(resp, body)
+	}
 
-	resp, err := c.httpClient.Do(req)
+	return nil
+}
+
+// Do sends a request with any method to path, for endpoints the client has
+// no method for, and returns the response body. body is sent as is with
+// contentType, or pass nil for no body. GETs use the response cache like
+// Get. path may also be an absolute Graph URL, such as a nextLink, and is
+// then sent to that URL's API version.
+func (c *Client) Do(ctx context.Context, method, path, contentType string, body io.Reader, opts ...RequestOption) ([]byte, error) {
+	for _, version := range APIVersions {
+		if base := GraphBaseURL(version); strings.HasPrefix(path, base+"/") {
+			path = strings.TrimPrefix(path, base)
+			opts = append(opts, WithAPIVersion(version))
+		}
+	}
+	path = c.relativePath(path)
+
+	method = strings.ToUpper(method)
+	if method == http.MethodGet && body == nil {
+		return c.Get(ctx, path, opts...)
+	}
+	data, _, err := c.doRequest(ctx, method, path, contentType, body, opts)
+	return data, err
+}
+
+// Download streams the response body for path to w without holding it in
+// memory, for message MIME and file content. It bypasses the response cache
+// and returns the number of bytes written.
+func (c *Client) Download(ctx context.Context, path string, w io.Writer, opts ...RequestOption) (int64, error) {
+	o := newRequestOptions(opts)
+	url := c.requestURL(path, o)
+
+	resp, err := c.send(ctx, "GET", url, "", nil, o)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return 0, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return 0, newResponseError(resp, body)
 	}
 
-	return nil
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("failed to read response: %w", err)
+	}
+	return n, nil
 }
 
 // doJSONRequest performs a JSON request
-func (c *Client) doJSONRequest(ctx context.Context, method, path string, data interface{}) ([]byte, error) {
-	url := c.baseURL + path
-
-	var body io.Reader
-	if data != nil {
-		jsonData, err := json.Marshal(data)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal data: %w", err)
-		}
-		body = bytes.NewReader(jsonData)
+func (c *Client) doJSONRequest(ctx context.Context, method, path string, data interface{}, opts ...RequestOption) ([]byte, error) {
+	respBody, _, err := c.doJSONRequestWithHeaders(ctx, method, path, data, opts...)
+	return respBody, err
+}
+
+// doJSONRequestWithHeaders performs a JSON request and also returns the response headers.
+// Used by long-running operations where Graph returns a Location header to poll.
+func (c *Client) doJSONRequestWithHeaders(ctx context.Context, method, path string, data interface{}, opts ...RequestOption) ([]byte, http.Header, error) {
+	if data == nil {
+		return c.doRequest(ctx, method, path, "", nil, opts)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	jsonData, err := json.Marshal(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal data: %w", err)
 	}
+	return c.doRequest(ctx, method, path, "application/json", bytes.NewReader(jsonData), opts)
+}
 
-	c.addAuthHeader(req)
+// PostContent performs a POST request with a body that isn't JSON, such as
+// a MIME message
+func (c *Client) PostContent(ctx context.Context, path, contentType string, body io.Reader, opts ...RequestOption) ([]byte, error) {
+	respBody, _, err := c.doRequest(ctx, "POST", path, contentType, body, opts)
+	return respBody, err
+}
 
-	if data != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
+// PutContent performs a PUT request with a body that isn't JSON, such as a
+// file upload
+func (c *Client) PutContent(ctx context.Context, path, contentType string, body io.Reader, opts ...RequestOption) ([]byte, error) {
+	respBody, _, err := c.doRequest(ctx, "PUT", path, contentType, body, opts)
+	return respBody, err
+}
 
-	resp, err := c.httpClient.Do(req)
+// doRequest sends body with the given content type and returns the response
+// body and headers
+func (c *Client) doRequest(ctx context.Context, method, path, contentType string, body io.Reader, opts []RequestOption) ([]byte, http.Header, error) {
+	o := newRequestOptions(opts)
+	url := c.requestURL(path, o)
+	defer c.invalidateCache()
+
+	resp, err := c.send(ctx, method, url, contentType, body, o)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+		return nil, nil, newResponseError(resp, respBody)
+	}
+
+	return respBody, resp.Header, nil
+}
+
+// send makes a request with the access token, options and content type,
+// and sends it through the client's middleware
+func (c *Client) send(ctx context.Context, method, url, contentType string, body io.Reader, o *requestOptions) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	return respBody, nil
+	if err := c.addAuthHeader(req); err != nil {
+		return nil, err
+	}
+	o.apply(req)
+	if req.Header.Get("client-request-id") == "" {
+		// Graph logs it with the request, so a failure can be traced by
+		// Microsoft support even if no response came back. Retries resend
+		// the same ID.
+		req.Header.Set("client-request-id", newClientRequestID())
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed (client-request-id %s): %w", req.Header.Get("client-request-id"), err)
+	}
+	return resp, nil
+}
+
+// newClientRequestID returns a random (version 4) UUID for the
+// client-request-id header
+func newClientRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
 }
 
 // GetMe retrieves the current user's profile
@@ -0,0 +1,55 @@
+package libgo365
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	var gotMethod, gotPath, gotType, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotType = r.Method, r.URL.RequestURI(), r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL, tokens: StaticToken("test-token")}
+	ctx := context.Background()
+
+	data, err := client.Do(ctx, "post", "/me/sendMail", "application/json", strings.NewReader(`{"message": {}}`))
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if gotMethod != "POST" || gotPath != "/me/sendMail" || gotType != "application/json" || gotBody != `{"message": {}}` {
+		t.Errorf("Unexpected request %s %s (%s) %q", gotMethod, gotPath, gotType, gotBody)
+	}
+	if len(data) != 0 {
+		t.Errorf("Expected an empty body, got %q", data)
+	}
+
+	// Absolute links, such as a nextLink, are sent to the client's server
+	data, err = client.Do(ctx, "GET", server.URL+"/me/messages?$skiptoken=abc", "", nil)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if gotMethod != "GET" || gotPath != "/me/messages?$skiptoken=abc" || string(data) != `{"id": "1"}` {
+		t.Errorf("Unexpected request %s %s or response %q", gotMethod, gotPath, data)
+	}
+
+	if _, err := client.Do(ctx, "GET", GraphBetaBaseURL+"/me/messages", "", nil); err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if gotPath != "/me/messages" {
+		t.Errorf("Expected the Graph URL trimmed to its path, got %s", gotPath)
+	}
+}
@@ -0,0 +1,77 @@
+package libgo365
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGzipResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Expected Accept-Encoding: gzip, got %q", r.Header.Get("Accept-Encoding"))
+			w.Write([]byte(`{"id": "msg1", "subject": "Plain"}`))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(`{"id": "msg1", "subject": "Compressed"}`))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+
+	msg, err := client.GetMessage(context.Background(), "msg1")
+	if err != nil {
+		t.Fatalf("GetMessage failed: %v", err)
+	}
+	if msg.Subject != "Compressed" {
+		t.Errorf("Expected the decompressed subject, got %q", msg.Subject)
+	}
+}
+
+func TestGzipUncompressedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Servers may ignore Accept-Encoding
+		w.Write([]byte(`{"id": "msg1", "subject": "Plain"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+
+	msg, err := client.GetMessage(context.Background(), "msg1")
+	if err != nil {
+		t.Fatalf("GetMessage failed: %v", err)
+	}
+	if msg.Subject != "Plain" {
+		t.Errorf("Expected subject Plain, got %q", msg.Subject)
+	}
+}
+
+func TestGzipLeavesRangeRequests(t *testing.T) {
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("Range", "bytes=0-99")
+	resp, err := (&gzipTransport{base: http.DefaultTransport}).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if acceptEncoding == "gzip" {
+		t.Error("Range requests should not ask for gzip")
+	}
+	if req.Header.Get("Accept-Encoding") != "" {
+		t.Error("The caller's request should not be modified")
+	}
+}
@@ -5,19 +5,207 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigNames are the project-local config files searched for, in order
+// of preference, from the working directory upward.
+var ProjectConfigNames = []string{".go365.yaml", ".go365.yml", ".go365.json"}
+
+// Auth modes for Config.AuthMode
+const (
+	AuthModeDeviceCode      = "device-code"
+	AuthModeAppOnly         = "app-only"
+	AuthModeAzureIdentity   = "azidentity"
+	AuthModeManagedIdentity = "managed-identity"
 )
 
 // Config represents the application configuration
 type Config struct {
-	TenantID string   `json:"tenant_id,omitempty"`
-	ClientID string   `json:"client_id,omitempty"`
-	Scopes   []string `json:"scopes,omitempty"`
-	TimeZone string   `json:"timezone,omitempty"` // IANA timezone (e.g., "Pacific/Auckland")
+	TenantID string   `json:"tenant_id,omitempty" yaml:"tenant_id,omitempty"`
+	ClientID string   `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+	Scopes   []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	TimeZone string   `json:"timezone,omitempty" yaml:"timezone,omitempty"` // IANA timezone (e.g., "Pacific/Auckland")
+	Calendar string   `json:"calendar,omitempty" yaml:"calendar,omitempty"` // Default calendar ID
+	Output   string   `json:"output,omitempty" yaml:"output,omitempty"`     // Default output format (json, yaml, table)
+	Mailbox  string   `json:"mailbox,omitempty" yaml:"mailbox,omitempty"`   // Default mailbox (email or user ID) instead of /me
+
+	// ClientSecret is the app registration's secret. It is encrypted in
+	// config.json (see EncryptSecret) and decrypted by ConfigManager.Load.
+	ClientSecret string `json:"client_secret,omitempty" yaml:"client_secret,omitempty"`
+
+	// AuthMode is how go365 signs in: AuthModeDeviceCode (the default),
+	// AuthModeAppOnly, which uses CertPath or ClientSecret and needs no user,
+	// AuthModeAzureIdentity, which reuses Azure CLI or environment
+	// credentials, or AuthModeManagedIdentity, which uses the managed
+	// identity of the Azure resource go365 runs on
+	AuthMode string `json:"auth_mode,omitempty" yaml:"auth_mode,omitempty"`
+
+	// ManagedIdentityClientID selects a user-assigned managed identity;
+	// empty uses the system-assigned one
+	ManagedIdentityClientID string `json:"managed_identity_client_id,omitempty" yaml:"managed_identity_client_id,omitempty"`
+
+	// CertPath is a PEM or PFX certificate for app-only authentication,
+	// for tenants whose policy forbids client secrets. CertPassword
+	// decrypts its private key and is encrypted like ClientSecret.
+	CertPath     string `json:"cert_path,omitempty" yaml:"cert_path,omitempty"`
+	CertPassword string `json:"cert_password,omitempty" yaml:"cert_password,omitempty"`
+
+	// TimeFormat is "12h" or "24h" for human-readable times; empty follows
+	// the mailbox's Outlook setting
+	TimeFormat string `json:"time_format,omitempty" yaml:"time_format,omitempty"`
+
+	// Weekend lists the non-working weekdays by name (default Saturday and
+	// Sunday) and Holidays the non-working dates as YYYY-MM-DD, for
+	// business-day arithmetic such as "in 3 business days"
+	Weekend  []string `json:"weekend,omitempty" yaml:"weekend,omitempty"`
+	Holidays []string `json:"holidays,omitempty" yaml:"holidays,omitempty"`
+
+	// WeekStart names the first day of the week for "this week" and week
+	// grouping (default Monday)
+	WeekStart string `json:"week_start,omitempty" yaml:"week_start,omitempty"`
+
+	// DateOrder is "dmy" or "mdy" for reading numeric dates such as
+	// 03/04/2025; when empty, dates that could be read either way are errors
+	DateOrder string `json:"date_order,omitempty" yaml:"date_order,omitempty"`
+
+	// APIVersion is the Graph API version commands call, "v1.0" (the
+	// default) or "beta"
+	APIVersion string `json:"api_version,omitempty" yaml:"api_version,omitempty"`
+
+	// PluginHandshake is how plugins receive their context: "env" (default) or
+	// "stdin" to keep the access token out of the plugin's environment
+	PluginHandshake string `json:"plugin_handshake,omitempty" yaml:"plugin_handshake,omitempty"`
+
+	// Network settings for proxies and TLS inspection (see
+	// TransportOptions): the proxy URL, a PEM file of extra CA certificates,
+	// the minimum TLS version, and timeouts as durations such as "30s" for
+	// connecting and for Graph to start responding. Empty values use the
+	// defaults.
+	ProxyURL        string `json:"proxy_url,omitempty" yaml:"proxy_url,omitempty"`
+	CAFile          string `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`
+	MinTLSVersion   string `json:"min_tls_version,omitempty" yaml:"min_tls_version,omitempty"`
+	DialTimeout     string `json:"dial_timeout,omitempty" yaml:"dial_timeout,omitempty"`
+	ResponseTimeout string `json:"response_timeout,omitempty" yaml:"response_timeout,omitempty"`
+
+	// File logging under ~/.go365/logs: the level ("off", "info", "debug" or
+	// "trace"), the size in MB at which the log is rotated, and how many
+	// rotated files are kept. Zero values use the CLI's defaults.
+	LogLevel    string `json:"log_level,omitempty" yaml:"log_level,omitempty"`
+	LogMaxSize  int    `json:"log_max_size,omitempty" yaml:"log_max_size,omitempty"`
+	LogMaxFiles int    `json:"log_max_files,omitempty" yaml:"log_max_files,omitempty"`
+}
+
+// Merge overlays the non-empty fields of other onto c
+func (c *Config) Merge(other *Config) {
+	if other == nil {
+		return
+	}
+	if other.TenantID != "" {
+		c.TenantID = other.TenantID
+	}
+	if other.ClientID != "" {
+		c.ClientID = other.ClientID
+	}
+	if len(other.Scopes) > 0 {
+		c.Scopes = other.Scopes
+	}
+	if other.TimeZone != "" {
+		c.TimeZone = other.TimeZone
+	}
+	if other.Calendar != "" {
+		c.Calendar = other.Calendar
+	}
+	if other.Output != "" {
+		c.Output = other.Output
+	}
+	if other.Mailbox != "" {
+		c.Mailbox = other.Mailbox
+	}
+	if other.ClientSecret != "" {
+		c.ClientSecret = other.ClientSecret
+	}
+	if other.AuthMode != "" {
+		c.AuthMode = other.AuthMode
+	}
+	if other.ManagedIdentityClientID != "" {
+		c.ManagedIdentityClientID = other.ManagedIdentityClientID
+	}
+	if other.CertPath != "" {
+		c.CertPath = other.CertPath
+	}
+	if other.CertPassword != "" {
+		c.CertPassword = other.CertPassword
+	}
+	if other.TimeFormat != "" {
+		c.TimeFormat = other.TimeFormat
+	}
+	if len(other.Weekend) > 0 {
+		c.Weekend = other.Weekend
+	}
+	if len(other.Holidays) > 0 {
+		c.Holidays = other.Holidays
+	}
+	if other.WeekStart != "" {
+		c.WeekStart = other.WeekStart
+	}
+	if other.DateOrder != "" {
+		c.DateOrder = other.DateOrder
+	}
+	if other.APIVersion != "" {
+		c.APIVersion = other.APIVersion
+	}
+	if other.PluginHandshake != "" {
+		c.PluginHandshake = other.PluginHandshake
+	}
+	if other.ProxyURL != "" {
+		c.ProxyURL = other.ProxyURL
+	}
+	if other.CAFile != "" {
+		c.CAFile = other.CAFile
+	}
+	if other.MinTLSVersion != "" {
+		c.MinTLSVersion = other.MinTLSVersion
+	}
+	if other.DialTimeout != "" {
+		c.DialTimeout = other.DialTimeout
+	}
+	if other.ResponseTimeout != "" {
+		c.ResponseTimeout = other.ResponseTimeout
+	}
+	if other.LogLevel != "" {
+		c.LogLevel = other.LogLevel
+	}
+	if other.LogMaxSize != 0 {
+		c.LogMaxSize = other.LogMaxSize
+	}
+	if other.LogMaxFiles != 0 {
+		c.LogMaxFiles = other.LogMaxFiles
+	}
+}
+
+// clearUserOnly drops the settings a project config may not make: how
+// requests reach Graph (proxy, CA, TLS and timeouts) and which credentials
+// sign in. Anyone who can commit to a repository can write its config, and
+// these would let them see or steer every request.
+func (c *Config) clearUserOnly() {
+	c.ProxyURL = ""
+	c.CAFile = ""
+	c.MinTLSVersion = ""
+	c.DialTimeout = ""
+	c.ResponseTimeout = ""
+	c.ClientSecret = ""
+	c.CertPath = ""
+	c.CertPassword = ""
 }
 
 // ConfigManager handles configuration persistence
 type ConfigManager struct {
-	configPath string
+	configPath  string
+	projectPath string // Project-local override, empty if none was found
+	override    *Config
 }
 
 // NewConfigManager creates a new configuration manager
@@ -32,14 +220,91 @@ func NewConfigManager() (*ConfigManager, error) {
 		return nil, fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	return &ConfigManager{
+	cm := &ConfigManager{
 		configPath: filepath.Join(configDir, "config.json"),
-	}, nil
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		cm.projectPath = FindProjectConfig(wd)
+	}
+
+	return cm, nil
+}
+
+// FindProjectConfig looks for a project config file in dir and each of its
+// parents, returning the first path found or "" if there is none.
+func FindProjectConfig(dir string) string {
+	for {
+		for _, name := range ProjectConfigNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig reads a project config file. Files ending in .json are
+// parsed as JSON, anything else as YAML.
+func LoadProjectConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project config: %w", err)
+	}
+
+	var config Config
+	if strings.HasSuffix(path, ".json") {
+		err = json.Unmarshal(data, &config)
+	} else {
+		err = yaml.Unmarshal(data, &config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse project config %s: %w", path, err)
+	}
+
+	return &config, nil
+}
+
+// ProjectConfigPath returns the project config file that overrides the user
+// configuration, or "" if none applies
+func (cm *ConfigManager) ProjectConfigPath() string {
+	return cm.projectPath
+}
+
+// Dir returns the configuration directory (~/.go365)
+func (cm *ConfigManager) Dir() string {
+	return filepath.Dir(cm.configPath)
+}
+
+// secrets returns the fields that are kept encrypted on disk, by name
+func (c *Config) secrets() map[string]*string {
+	return map[string]*string{
+		"client secret":        &c.ClientSecret,
+		"certificate password": &c.CertPassword,
+	}
 }
 
-// Save saves the configuration to disk
+// Save saves the configuration to disk, encrypting the client secret and
+// certificate password
 func (cm *ConfigManager) Save(config *Config) error {
-	data, err := json.MarshalIndent(config, "", "  ")
+	stored := *config
+	for name, secret := range stored.secrets() {
+		if *secret != "" && !IsEncrypted(*secret) {
+			encrypted, err := EncryptSecret(*secret)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt %s: %w", name, err)
+			}
+			*secret = encrypted
+		}
+	}
+
+	data, err := json.MarshalIndent(&stored, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -51,8 +316,51 @@ func (cm *ConfigManager) Save(config *Config) error {
 	return nil
 }
 
-// Load loads the configuration from disk
+// Load loads the user configuration with any project config applied on top
+// and the secrets decrypted. Network and credential settings are only taken
+// from the user configuration and SetOverride, never a project config.
 func (cm *ConfigManager) Load() (*Config, error) {
+	config, err := cm.LoadGlobal()
+	if err != nil {
+		return nil, err
+	}
+	for name, secret := range config.secrets() {
+		if *secret, err = DecryptSecret(*secret); err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", name, err)
+		}
+	}
+
+	if cm.projectPath != "" {
+		project, err := LoadProjectConfig(cm.projectPath)
+		if err != nil {
+			return nil, err
+		}
+		project.clearUserOnly()
+		for name, secret := range project.secrets() {
+			if *secret, err = DecryptSecret(*secret); err != nil {
+				return nil, fmt.Errorf("failed to decrypt %s in %s: %w", name, cm.projectPath, err)
+			}
+		}
+		config.Merge(project)
+	}
+	if cm.override != nil {
+		config.Merge(cm.override)
+	}
+
+	return config, nil
+}
+
+// SetOverride merges override over the configuration Load returns, for
+// settings that apply to a single run, such as a command-line flag. It is
+// never saved: LoadGlobal ignores it.
+func (cm *ConfigManager) SetOverride(override *Config) {
+	cm.override = override
+}
+
+// LoadGlobal loads the user configuration from disk, ignoring project config.
+// Use it when the result will be saved back; the client secret stays
+// encrypted.
+func (cm *ConfigManager) LoadGlobal() (*Config, error) {
 	data, err := os.ReadFile(cm.configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -76,5 +384,16 @@ func (cm *ConfigManager) Load() (*Config, error) {
 		config.Scopes = []string{"https://graph.microsoft.com/.default"}
 	}
 
+	// A secret written into config.json by hand is encrypted in place; if
+	// that fails the plaintext still works for this run
+	for _, secret := range config.secrets() {
+		if *secret != "" && !IsEncrypted(*secret) {
+			if encrypted, err := EncryptSecret(*secret); err == nil {
+				*secret = encrypted
+				_ = cm.Save(&config)
+			}
+		}
+	}
+
 	return &config, nil
 }
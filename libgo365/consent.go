@@ -0,0 +1,106 @@
+package libgo365
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ConsentRequiredError reports that a token needs permissions the
+// signed-in user hasn't consented to. Signing in again with the scopes (or
+// WithIncrementalConsent) asks for them.
+type ConsentRequiredError struct {
+	Scopes []string
+	Err    error
+}
+
+func (e *ConsentRequiredError) Error() string {
+	return fmt.Sprintf("consent is needed for %s", strings.Join(e.Scopes, " "))
+}
+
+func (e *ConsentRequiredError) Unwrap() error {
+	return e.Err
+}
+
+// WithIncrementalConsent makes the authenticator ask for consent when a
+// token needs scopes the user hasn't granted yet, by running the device code
+// flow with its instructions written to w, instead of returning
+// ConsentRequiredError. Only use it when someone is there to respond.
+func WithIncrementalConsent(w io.Writer) AuthOption {
+	return func(o *authOptions) {
+		o.consent = w
+	}
+}
+
+// AddScopes adds to the scopes requested for tokens, such as those a
+// particular operation needs, and reports whether the authenticator requests
+// scopes one by one. If it uses a /.default scope, or is app-only, it
+// already has everything granted to the app and nothing changes. Scopes
+// covered by a broader one already requested (Mail.Read by Mail.ReadWrite)
+// are skipped. Call it before requesting tokens.
+func (a *Authenticator) AddScopes(scopes ...string) bool {
+	if a.AppOnly() || a.AzureIdentity() || slices.ContainsFunc(a.scopes, isDefaultScope) {
+		return false
+	}
+
+	added := false
+	for _, scope := range scopes {
+		if !scopeCovered(a.scopes, scope) {
+			// Clip so the caller's Scopes slice is never written to
+			a.scopes = append(slices.Clip(a.scopes), scope)
+			added = true
+		}
+	}
+	if added {
+		a.cached.clear()
+	}
+	return true
+}
+
+// requestConsent asks the user to consent to scopes if
+// WithIncrementalConsent was given, returning the new token
+func (a *Authenticator) requestConsent(ctx context.Context, scopes []string, err error) (string, time.Time, error) {
+	if a.consent == nil {
+		return "", time.Time{}, &ConsentRequiredError{Scopes: scopes, Err: err}
+	}
+
+	fmt.Fprintf(a.consent, "go365 needs your consent for more permissions (%s).\n", strings.Join(scopes, " "))
+	result, err := a.deviceCodeFlow(ctx, scopes, printDeviceCode(a.consent))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return result.AccessToken, result.ExpiresOn, nil
+}
+
+// isConsentRequired reports whether a silent token request failed because
+// the user hasn't consented to a scope (AADSTS65001)
+func isConsentRequired(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "AADSTS65001") || strings.Contains(msg, "consent_required")
+}
+
+// isDefaultScope reports whether scope is a resource's /.default scope
+func isDefaultScope(scope string) bool {
+	return strings.HasSuffix(scope, "/.default") || scope == ".default"
+}
+
+// scopeCovered reports whether have already grants scope, exactly or
+// through the ReadWrite version of a Read scope
+func scopeCovered(have []string, scope string) bool {
+	for _, h := range have {
+		if strings.EqualFold(h, scope) {
+			return true
+		}
+		if strings.Contains(scope, ".Read") && !strings.Contains(scope, ".ReadWrite") &&
+			strings.EqualFold(h, strings.Replace(scope, ".Read", ".ReadWrite", 1)) {
+			return true
+		}
+	}
+	return false
+}
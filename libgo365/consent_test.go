@@ -0,0 +1,99 @@
+package libgo365
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestAddScopes(t *testing.T) {
+	configured := make([]string, 2, 4)
+	copy(configured, []string{"User.Read", "Mail.ReadWrite"})
+	auth, err := NewAuthenticator(AuthConfig{
+		TenantID: "common",
+		ClientID: "00000000-0000-0000-0000-000000000000",
+		Scopes:   configured,
+	}, WithTokenStore(&memoryStore{}))
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+
+	if !auth.AddScopes("Mail.Read", "mail.readwrite", "Mail.Send") {
+		t.Error("Expected explicit scopes to accept more")
+	}
+	if want := []string{"User.Read", "Mail.ReadWrite", "Mail.Send"}; !slices.Equal(auth.scopes, want) {
+		t.Errorf("Expected %v, got %v", want, auth.scopes)
+	}
+	if configured[:3][2] != "" {
+		t.Error("AddScopes wrote into the configured Scopes slice")
+	}
+}
+
+func TestAddScopesDefault(t *testing.T) {
+	auth, err := NewAuthenticator(AuthConfig{
+		TenantID: "common",
+		ClientID: "00000000-0000-0000-0000-000000000000",
+		Scopes:   []string{GraphDefaultScope},
+	}, WithTokenStore(&memoryStore{}))
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	if auth.AddScopes("Mail.Send") || len(auth.scopes) != 1 {
+		t.Errorf("Expected .default to be left alone, got %v", auth.scopes)
+	}
+
+	app, err := NewAuthenticator(AuthConfig{
+		TenantID:     "contoso.onmicrosoft.com",
+		ClientID:     "00000000-0000-0000-0000-000000000000",
+		ClientSecret: "secret",
+	}, WithTokenStore(&memoryStore{}))
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	if app.AddScopes("Mail.Send") {
+		t.Error("Expected app-only authentication to ignore scopes")
+	}
+}
+
+func TestRequestConsent(t *testing.T) {
+	silentErr := errors.New("invalid_grant: AADSTS65001: The user or administrator has not consented to use the application")
+	if !isConsentRequired(silentErr) || isConsentRequired(errors.New("AADSTS50076: MFA required")) || isConsentRequired(nil) {
+		t.Error("isConsentRequired misclassified an error")
+	}
+
+	auth := &Authenticator{}
+	_, _, err := auth.requestConsent(context.Background(), []string{"User.Read", "Mail.Send"}, silentErr)
+	var consentErr *ConsentRequiredError
+	if !errors.As(err, &consentErr) || !errors.Is(err, silentErr) {
+		t.Fatalf("Expected ConsentRequiredError wrapping the MSAL error, got %v", err)
+	}
+	if err.Error() != "consent is needed for User.Read Mail.Send" {
+		t.Errorf("Unexpected message %q", err)
+	}
+
+	var o authOptions
+	WithIncrementalConsent(io.Discard)(&o)
+	if o.consent != io.Discard {
+		t.Error("Expected WithIncrementalConsent to set the prompt writer")
+	}
+}
+
+func TestScopeCovered(t *testing.T) {
+	for _, tt := range []struct {
+		have  []string
+		scope string
+		want  bool
+	}{
+		{[]string{"Mail.Read"}, "mail.read", true},
+		{[]string{"Mail.ReadWrite"}, "Mail.Read", true},
+		{[]string{"Mail.Read"}, "Mail.ReadWrite", false},
+		{[]string{"Calendars.ReadWrite"}, "Mail.Read", false},
+		{[]string{"Files.ReadWrite.All"}, "Files.Read.All", true},
+	} {
+		if got := scopeCovered(tt.have, tt.scope); got != tt.want {
+			t.Errorf("scopeCovered(%v, %s) = %v, want %v", tt.have, tt.scope, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DirectoryObject represents a generic Azure AD directory object (user, group,
+// service principal, contact, ...). ODataType identifies which kind it is.
+type DirectoryObject struct {
+	ODataType         string `json:"@odata.type,omitempty"` // e.g. #microsoft.graph.user
+	ID                string `json:"id,omitempty"`
+	DisplayName       string `json:"displayName,omitempty"`
+	UserPrincipalName string `json:"userPrincipalName,omitempty"` // users only
+	Mail              string `json:"mail,omitempty"`
+	AppID             string `json:"appId,omitempty"` // service principals only
+}
+
+// Kind returns the short object type (user, group, servicePrincipal, ...)
+func (o *DirectoryObject) Kind() string {
+	return strings.TrimPrefix(o.ODataType, "#microsoft.graph.")
+}
+
+// DirectoryObjectList represents a list of directory objects returned by Graph API
+type DirectoryObjectList struct {
+	Value    []*DirectoryObject `json:"value"`
+	NextLink string             `json:"@odata.nextLink,omitempty"`
+}
+
+// User represents a user's profile
+type User struct {
+	ID                string `json:"id,omitempty"`
+	DisplayName       string `json:"displayName,omitempty"`
+	UserPrincipalName string `json:"userPrincipalName,omitempty"`
+	Mail              string `json:"mail,omitempty"`
+	GivenName         string `json:"givenName,omitempty"`
+	Surname           string `json:"surname,omitempty"`
+	JobTitle          string `json:"jobTitle,omitempty"`
+	Department        string `json:"department,omitempty"`
+	OfficeLocation    string `json:"officeLocation,omitempty"`
+	MobilePhone       string `json:"mobilePhone,omitempty"`
+}
+
+// GetMyProfile retrieves the signed-in user's profile
+func (c *Client) GetMyProfile(ctx context.Context) (*User, error) {
+	data, err := c.Get(ctx, "/me?$select=id,displayName,userPrincipalName,mail,givenName,surname,jobTitle,department,officeLocation,mobilePhone")
+	if err != nil {
+		return nil, err
+	}
+
+	var user User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// HasMyPhoto reports whether the signed-in user has a profile photo
+func (c *Client) HasMyPhoto(ctx context.Context) (bool, error) {
+	_, err := c.Get(ctx, "/me/photo")
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return false, err
+}
+
+// Organization represents the signed-in tenant
+type Organization struct {
+	ID          string `json:"id,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// GetOrganization retrieves the tenant the client is signed in to. Personal
+// Microsoft accounts have none and get an error.
+func (c *Client) GetOrganization(ctx context.Context) (*Organization, error) {
+	data, err := c.Get(ctx, "/organization?$select=id,displayName")
+	if err != nil {
+		return nil, err
+	}
+
+	var orgs struct {
+		Value []*Organization `json:"value"`
+	}
+	if err := json.Unmarshal(data, &orgs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal organization: %w", err)
+	}
+	if len(orgs.Value) == 0 {
+		return nil, fmt.Errorf("no organization found")
+	}
+
+	return orgs.Value[0], nil
+}
+
+// DirectoryRole represents an activated Azure AD directory role
+type DirectoryRole struct {
+	ID             string `json:"id,omitempty"`
+	DisplayName    string `json:"displayName,omitempty"`
+	Description    string `json:"description,omitempty"`
+	RoleTemplateID string `json:"roleTemplateId,omitempty"`
+}
+
+// DirectoryRoleList represents a list of directory roles returned by Graph API
+type DirectoryRoleList struct {
+	Value []*DirectoryRole `json:"value"`
+}
+
+// ListDirectoryRoles retrieves the directory roles activated in the tenant
+func (c *Client) ListDirectoryRoles(ctx context.Context) ([]*DirectoryRole, error) {
+	data, err := c.Get(ctx, "/directoryRoles")
+	if err != nil {
+		return nil, err
+	}
+
+	var roleList DirectoryRoleList
+	if err := json.Unmarshal(data, &roleList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal directory roles: %w", err)
+	}
+
+	return roleList.Value, nil
+}
+
+// FindDirectoryRole looks up an activated role by ID, role template ID, or display name
+// (case-insensitive)
+func (c *Client) FindDirectoryRole(ctx context.Context, nameOrID string) (*DirectoryRole, error) {
+	roles, err := c.ListDirectoryRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range roles {
+		if role.ID == nameOrID || role.RoleTemplateID == nameOrID || strings.EqualFold(role.DisplayName, nameOrID) {
+			return role, nil
+		}
+	}
+
+	return nil, fmt.Errorf("directory role %q not found (roles without members are not activated)", nameOrID)
+}
+
+// ListDirectoryRoleMembers retrieves the members (users, groups, service principals) of a role
+func (c *Client) ListDirectoryRoleMembers(ctx context.Context, roleID string) ([]*DirectoryObject, error) {
+	if roleID == "" {
+		return nil, fmt.Errorf("role ID is required")
+	}
+
+	data, err := c.Get(ctx, fmt.Sprintf("/directoryRoles/%s/members", roleID))
+	if err != nil {
+		return nil, err
+	}
+
+	var memberList DirectoryObjectList
+	if err := json.Unmarshal(data, &memberList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal role members: %w", err)
+	}
+
+	return memberList.Value, nil
+}
@@ -0,0 +1,155 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListDirectoryRoles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/directoryRoles" {
+			t.Errorf("Expected path /directoryRoles, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"value": [
+			{"id": "role1", "displayName": "Global Administrator", "roleTemplateId": "62e90394-69f5-4237-9190-012177145e10"},
+			{"id": "role2", "displayName": "User Administrator", "roleTemplateId": "fe930be7-5e62-47db-91af-98c3a49a38b1"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	roles, err := client.ListDirectoryRoles(context.Background())
+	if err != nil {
+		t.Fatalf("ListDirectoryRoles failed: %v", err)
+	}
+	if len(roles) != 2 {
+		t.Fatalf("Expected 2 roles, got %d", len(roles))
+	}
+
+	tests := []struct {
+		input  string
+		wantID string
+	}{
+		{"role2", "role2"},
+		{"global administrator", "role1"},
+		{"fe930be7-5e62-47db-91af-98c3a49a38b1", "role2"},
+	}
+	for _, tt := range tests {
+		role, err := client.FindDirectoryRole(context.Background(), tt.input)
+		if err != nil {
+			t.Errorf("FindDirectoryRole(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if role.ID != tt.wantID {
+			t.Errorf("FindDirectoryRole(%q) = %s, want %s", tt.input, role.ID, tt.wantID)
+		}
+	}
+
+	if _, err := client.FindDirectoryRole(context.Background(), "Nonexistent Role"); err == nil {
+		t.Error("Expected error for unknown role")
+	}
+}
+
+func TestListDirectoryRoleMembers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/directoryRoles/role1/members" {
+			t.Errorf("Expected path /directoryRoles/role1/members, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"value": [
+			{"@odata.type": "#microsoft.graph.user", "id": "u1", "displayName": "Jane", "userPrincipalName": "jane@example.com"},
+			{"@odata.type": "#microsoft.graph.servicePrincipal", "id": "sp1", "displayName": "Automation", "appId": "app1"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	members, err := client.ListDirectoryRoleMembers(context.Background(), "role1")
+	if err != nil {
+		t.Fatalf("ListDirectoryRoleMembers failed: %v", err)
+	}
+	if len(members) != 2 {
+		t.Fatalf("Expected 2 members, got %d", len(members))
+	}
+	if members[0].Kind() != "user" || members[0].UserPrincipalName != "jane@example.com" {
+		t.Errorf("Unexpected first member: %+v", members[0])
+	}
+	if members[1].Kind() != "servicePrincipal" {
+		t.Errorf("Expected servicePrincipal, got %s", members[1].Kind())
+	}
+}
+
+func TestGetMyProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me":
+			if !strings.Contains(r.URL.Query().Get("$select"), "jobTitle") {
+				t.Errorf("Expected jobTitle in $select, got %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(User{ID: "u1", DisplayName: "Jane Doe", UserPrincipalName: "jane@example.com", JobTitle: "Engineer"})
+		case "/me/photo":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"ImageNotFound"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	user, err := client.GetMyProfile(context.Background())
+	if err != nil {
+		t.Fatalf("GetMyProfile failed: %v", err)
+	}
+	if user.JobTitle != "Engineer" || user.UserPrincipalName != "jane@example.com" {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+
+	hasPhoto, err := client.HasMyPhoto(context.Background())
+	if err != nil {
+		t.Fatalf("HasMyPhoto failed: %v", err)
+	}
+	if hasPhoto {
+		t.Error("Expected no photo for 404")
+	}
+}
+
+func TestGetOrganization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/organization" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"value":[{"id":"tenant-id","displayName":"Contoso"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	org, err := client.GetOrganization(context.Background())
+	if err != nil {
+		t.Fatalf("GetOrganization failed: %v", err)
+	}
+	if org.ID != "tenant-id" || org.DisplayName != "Contoso" {
+		t.Errorf("Unexpected organization: %+v", org)
+	}
+}
@@ -14,7 +14,7 @@ import (
 type Drive struct {
 	ID        string      `json:"id,omitempty"`
 	Name      string      `json:"name,omitempty"`
-	DriveType string      `json:"driveType,omitempty"` // personal, business, documentLibrary
+	DriveType DriveType   `json:"driveType,omitempty"`
 	Owner     *Identity   `json:"owner,omitempty"`
 	Quota     *DriveQuota `json:"quota,omitempty"`
 	WebURL    string      `json:"webUrl,omitempty"`
@@ -30,7 +30,25 @@ type DriveQuota struct {
 
 // Identity represents an identity (user, application, etc.)
 type Identity struct {
-	User *IdentityUser `json:"user,omitempty"`
+	User        *IdentityUser `json:"user,omitempty"`
+	Application *IdentityUser `json:"application,omitempty"`
+	Phone       *IdentityUser `json:"phone,omitempty"`
+}
+
+// Name returns the display name of the user, application, or phone in the set
+func (i *Identity) Name() string {
+	for _, id := range []*IdentityUser{i.User, i.Application, i.Phone} {
+		if id == nil {
+			continue
+		}
+		if id.DisplayName != "" {
+			return id.DisplayName
+		}
+		if id.ID != "" {
+			return id.ID
+		}
+	}
+	return ""
 }
 
 // IdentityUser represents a user identity
@@ -52,6 +70,9 @@ type DriveItem struct {
 	File                 *FileFacet     `json:"file,omitempty"`
 	ParentReference      *ItemReference `json:"parentReference,omitempty"`
 	DownloadURL          string         `json:"@microsoft.graph.downloadUrl,omitempty"`
+
+	// ETag changes whenever the item or its content does; see WithIfMatch
+	ETag string `json:"eTag,omitempty"`
 }
 
 // IsFolder returns true if the item is a folder
@@ -78,10 +99,10 @@ type Hashes struct {
 
 // ItemReference contains information about a parent item
 type ItemReference struct {
-	DriveID   string `json:"driveId,omitempty"`
-	DriveType string `json:"driveType,omitempty"`
-	ID        string `json:"id,omitempty"`
-	Path      string `json:"path,omitempty"`
+	DriveID   string    `json:"driveId,omitempty"`
+	DriveType DriveType `json:"driveType,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Path      string    `json:"path,omitempty"`
 }
 
 // DriveItemList represents a list of drive items from Graph API
@@ -99,6 +120,7 @@ type ListItemsOptions struct {
 	Top       int
 	PageToken string
 	OrderBy   string
+	Select    []string // Properties to return ($select); empty for all
 }
 
 // ListItemsResponse represents the response from ListItems
@@ -166,38 +188,7 @@ func isItemID(pathOrID string) bool {
 
 // ListItems retrieves items in a folder
 func (c *Client) ListItems(ctx context.Context, pathOrID string, opts *ListItemsOptions) (*ListItemsResponse, error) {
-	basePath := c.buildDrivePath(opts)
-
-	var path string
-	if pathOrID == "/" || pathOrID == "" {
-		path = basePath + "/root/children"
-	} else if isItemID(pathOrID) {
-		path = basePath + fmt.Sprintf("/items/%s/children", pathOrID)
-	} else {
-		// Path-based access: /drive/root:/path:/children
-		cleanPath := strings.Trim(pathOrID, "/")
-		path = basePath + fmt.Sprintf("/root:/%s:/children", cleanPath)
-	}
-
-	params := url.Values{}
-	if opts != nil {
-		if opts.Top > 0 {
-			params.Set("$top", fmt.Sprintf("%d", opts.Top))
-		}
-		if opts.PageToken != "" {
-			params.Set("$skiptoken", opts.PageToken)
-		}
-		if opts.OrderBy != "" {
-			params.Set("$orderby", opts.OrderBy)
-		}
-	}
-
-	fullPath := path
-	if len(params) > 0 {
-		fullPath = path + "?" + params.Encode()
-	}
-
-	data, err := c.Get(ctx, fullPath)
+	data, err := c.Get(ctx, c.childrenPath(pathOrID, opts))
 	if err != nil {
 		return nil, err
 	}
@@ -217,6 +208,37 @@ func (c *Client) ListItems(ctx context.Context, pathOrID string, opts *ListItems
 	}, nil
 }
 
+// DriveItemIterator returns an iterator over every item in a folder, given
+// as for ListItems
+func (c *Client) DriveItemIterator(pathOrID string, opts *ListItemsOptions) *PageIterator[DriveItem] {
+	return NewPageIterator[DriveItem](c, c.childrenPath(pathOrID, opts))
+}
+
+// childrenPath builds the request path and query for listing a folder
+func (c *Client) childrenPath(pathOrID string, opts *ListItemsOptions) string {
+	basePath := c.buildDrivePath(opts)
+
+	var path string
+	if pathOrID == "/" || pathOrID == "" {
+		path = basePath + "/root/children"
+	} else if isItemID(pathOrID) {
+		path = basePath + fmt.Sprintf("/items/%s/children", pathOrID)
+	} else {
+		// Path-based access: /drive/root:/path:/children
+		cleanPath := strings.Trim(pathOrID, "/")
+		path = basePath + fmt.Sprintf("/root:/%s:/children", cleanPath)
+	}
+
+	query := NewQuery()
+	if opts != nil {
+		query.Top(opts.Top).SkipToken(opts.PageToken).Select(opts.Select...)
+		if opts.OrderBy != "" {
+			query.OrderBy(opts.OrderBy)
+		}
+	}
+	return query.Path(path)
+}
+
 // GetItemOptions represents options for getting an item
 type GetItemOptions struct {
 	UserID  string
@@ -291,25 +313,16 @@ func (c *Client) DownloadItem(ctx context.Context, pathOrID string, w io.Writer,
 func (c *Client) SearchItems(ctx context.Context, query string, opts *ListItemsOptions) (*ListItemsResponse, error) {
 	basePath := c.buildDrivePath(opts)
 
-	// URL encode the query for the path
-	path := basePath + fmt.Sprintf("/root/search(q='%s')", url.PathEscape(query))
+	// The search text is an OData string in the path, so a quote in it
+	// needs doubling before the path is escaped
+	path := basePath + fmt.Sprintf("/root/search(q=%s)", url.PathEscape(ODataLiteral(query)))
 
-	params := url.Values{}
+	params := NewQuery()
 	if opts != nil {
-		if opts.Top > 0 {
-			params.Set("$top", fmt.Sprintf("%d", opts.Top))
-		}
-		if opts.PageToken != "" {
-			params.Set("$skiptoken", opts.PageToken)
-		}
-	}
-
-	fullPath := path
-	if len(params) > 0 {
-		fullPath = path + "?" + params.Encode()
+		params.Top(opts.Top).SkipToken(opts.PageToken).Select(opts.Select...)
 	}
 
-	data, err := c.Get(ctx, fullPath)
+	data, err := c.Get(ctx, params.Path(path))
 	if err != nil {
 		return nil, err
 	}
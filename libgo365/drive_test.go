@@ -34,9 +34,9 @@ func TestGetDrive(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  server.Client(),
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -71,9 +71,9 @@ func TestListItems(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  server.Client(),
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -106,9 +106,9 @@ func TestListItemsWithPath(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  server.Client(),
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -118,6 +118,30 @@ func TestListItemsWithPath(t *testing.T) {
 	}
 }
 
+func TestListItemsSelect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sel := r.URL.Query().Get("$select"); sel != "id,name" {
+			t.Errorf("Expected $select=id,name, got %s", sel)
+		}
+
+		items := DriveItemList{Value: []*DriveItem{}}
+		json.NewEncoder(w).Encode(items)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	ctx := context.Background()
+	_, err := client.ListItems(ctx, "/Documents", &ListItemsOptions{Select: []string{"id", "name"}})
+	if err != nil {
+		t.Fatalf("ListItems failed: %v", err)
+	}
+}
+
 func TestGetItem(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		expectedPath := "/me/drive/root:/Documents/report.pdf:"
@@ -136,9 +160,9 @@ func TestGetItem(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  server.Client(),
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -165,9 +189,9 @@ func TestGetItemByID(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  server.Client(),
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -194,9 +218,9 @@ func TestDownloadItem(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  server.Client(),
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -229,9 +253,9 @@ func TestSearchItems(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  server.Client(),
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -0,0 +1,165 @@
+package libgo365
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Importance is a message or event's importance
+type Importance string
+
+const (
+	ImportanceLow    Importance = "low"
+	ImportanceNormal Importance = "normal"
+	ImportanceHigh   Importance = "high"
+)
+
+var importances = []string{"low", "normal", "high"}
+
+// Valid reports whether i is a value Graph accepts
+func (i Importance) Valid() bool { return slices.Contains(importances, string(i)) }
+
+// ParseImportance parses an importance, ignoring case
+func ParseImportance(s string) (Importance, error) {
+	v, err := parseEnum("importance", s, importances)
+	return Importance(v), err
+}
+
+// ResponseType is an attendee's response to a meeting
+type ResponseType string
+
+const (
+	ResponseNone                ResponseType = "none"
+	ResponseOrganizer           ResponseType = "organizer"
+	ResponseTentativelyAccepted ResponseType = "tentativelyAccepted"
+	ResponseAccepted            ResponseType = "accepted"
+	ResponseDeclined            ResponseType = "declined"
+	ResponseNotResponded        ResponseType = "notResponded"
+)
+
+var responseTypes = []string{"none", "organizer", "tentativelyAccepted", "accepted", "declined", "notResponded"}
+
+// Valid reports whether r is a value Graph returns
+func (r ResponseType) Valid() bool { return slices.Contains(responseTypes, string(r)) }
+
+// ParseResponseType parses a response type, ignoring case
+func ParseResponseType(s string) (ResponseType, error) {
+	v, err := parseEnum("response", s, responseTypes)
+	return ResponseType(v), err
+}
+
+// FreeBusyStatus is how time shows on a calendar: an event's showAs, a
+// schedule item's status or an attendee's availability
+type FreeBusyStatus string
+
+const (
+	FreeBusyFree             FreeBusyStatus = "free"
+	FreeBusyTentative        FreeBusyStatus = "tentative"
+	FreeBusyBusy             FreeBusyStatus = "busy"
+	FreeBusyOOF              FreeBusyStatus = "oof"
+	FreeBusyWorkingElsewhere FreeBusyStatus = "workingElsewhere"
+	FreeBusyUnknown          FreeBusyStatus = "unknown"
+)
+
+var freeBusyStatuses = []string{"free", "tentative", "busy", "oof", "workingElsewhere", "unknown"}
+
+// Valid reports whether s is a value Graph accepts
+func (s FreeBusyStatus) Valid() bool { return slices.Contains(freeBusyStatuses, string(s)) }
+
+// ParseFreeBusyStatus parses a free/busy status, ignoring case
+func ParseFreeBusyStatus(s string) (FreeBusyStatus, error) {
+	v, err := parseEnum("free/busy status", s, freeBusyStatuses)
+	return FreeBusyStatus(v), err
+}
+
+// Sensitivity is an event or message's privacy level
+type Sensitivity string
+
+const (
+	SensitivityNormal       Sensitivity = "normal"
+	SensitivityPersonal     Sensitivity = "personal"
+	SensitivityPrivate      Sensitivity = "private"
+	SensitivityConfidential Sensitivity = "confidential"
+)
+
+var sensitivities = []string{"normal", "personal", "private", "confidential"}
+
+// Valid reports whether s is a value Graph accepts
+func (s Sensitivity) Valid() bool { return slices.Contains(sensitivities, string(s)) }
+
+// ParseSensitivity parses a sensitivity, ignoring case
+func ParseSensitivity(s string) (Sensitivity, error) {
+	v, err := parseEnum("sensitivity", s, sensitivities)
+	return Sensitivity(v), err
+}
+
+// AttendeeType is whether an attendee is required
+type AttendeeType string
+
+const (
+	AttendeeRequired AttendeeType = "required"
+	AttendeeOptional AttendeeType = "optional"
+	AttendeeResource AttendeeType = "resource"
+)
+
+var attendeeTypes = []string{"required", "optional", "resource"}
+
+// Valid reports whether t is a value Graph accepts
+func (t AttendeeType) Valid() bool { return slices.Contains(attendeeTypes, string(t)) }
+
+// ParseAttendeeType parses an attendee type, ignoring case
+func ParseAttendeeType(s string) (AttendeeType, error) {
+	v, err := parseEnum("attendee type", s, attendeeTypes)
+	return AttendeeType(v), err
+}
+
+// BodyType is the format of an ItemBody's content
+type BodyType string
+
+const (
+	BodyText BodyType = "text"
+	BodyHTML BodyType = "html"
+)
+
+var bodyTypes = []string{"text", "html"}
+
+// Valid reports whether t is a value Graph accepts
+func (t BodyType) Valid() bool { return slices.Contains(bodyTypes, string(t)) }
+
+// ParseBodyType parses a body type, ignoring case
+func ParseBodyType(s string) (BodyType, error) {
+	v, err := parseEnum("body type", s, bodyTypes)
+	return BodyType(v), err
+}
+
+// DriveType is the kind of a OneDrive or SharePoint drive
+type DriveType string
+
+const (
+	DrivePersonal        DriveType = "personal"
+	DriveBusiness        DriveType = "business"
+	DriveDocumentLibrary DriveType = "documentLibrary"
+)
+
+var driveTypes = []string{"personal", "business", "documentLibrary"}
+
+// Valid reports whether t is a value Graph returns
+func (t DriveType) Valid() bool { return slices.Contains(driveTypes, string(t)) }
+
+// ParseDriveType parses a drive type, ignoring case
+func ParseDriveType(s string) (DriveType, error) {
+	v, err := parseEnum("drive type", s, driveTypes)
+	return DriveType(v), err
+}
+
+// parseEnum returns the value matching s ignoring case, in the spelling Graph
+// uses, or an error naming the allowed values
+func parseEnum(kind, s string, values []string) (string, error) {
+	for _, v := range values {
+		if strings.EqualFold(s, v) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("invalid %s %q (must be one of %s)", kind, s, strings.Join(values, ", "))
+}
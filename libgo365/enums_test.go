@@ -0,0 +1,59 @@
+package libgo365
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseEnums(t *testing.T) {
+	if got, err := ParseImportance("HIGH"); err != nil || got != ImportanceHigh {
+		t.Errorf("ParseImportance(HIGH) = %q, %v", got, err)
+	}
+	if got, err := ParseFreeBusyStatus("workingelsewhere"); err != nil || got != FreeBusyWorkingElsewhere {
+		t.Errorf("ParseFreeBusyStatus(workingelsewhere) = %q, %v", got, err)
+	}
+	if got, err := ParseBodyType("HTML"); err != nil || got != BodyHTML {
+		t.Errorf("ParseBodyType(HTML) = %q, %v", got, err)
+	}
+
+	_, err := ParseSensitivity("secret")
+	if err == nil || !strings.Contains(err.Error(), "normal, personal, private, confidential") {
+		t.Errorf("Expected an error listing the allowed values, got %v", err)
+	}
+	if _, err := ParseDriveType(""); err == nil {
+		t.Error("Expected an error for an empty drive type")
+	}
+}
+
+func TestEnumValid(t *testing.T) {
+	if !ResponseTentativelyAccepted.Valid() || ResponseType("tentativelyaccepted").Valid() {
+		t.Error("Valid should accept only Graph's exact spelling")
+	}
+	if !AttendeeOptional.Valid() || AttendeeType("").Valid() {
+		t.Error("Unexpected AttendeeType validity")
+	}
+	if !DriveDocumentLibrary.Valid() || Importance("urgent").Valid() {
+		t.Error("Unexpected validity")
+	}
+}
+
+func TestEventEnumFields(t *testing.T) {
+	var event Event
+	data := `{"showAs": "oof", "sensitivity": "private", "importance": "high", "responseStatus": {"response": "accepted"}}`
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if event.ShowAs != FreeBusyOOF || event.Sensitivity != SensitivityPrivate || event.Importance != ImportanceHigh {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	if event.ResponseStatus.Response != ResponseAccepted {
+		t.Errorf("Expected accepted, got %q", event.ResponseStatus.Response)
+	}
+
+	// Unset fields stay out of requests
+	out, _ := json.Marshal(&Event{Subject: "Sync"})
+	if string(out) != `{"subject":"Sync"}` {
+		t.Errorf("Unexpected JSON %s", out)
+	}
+}
@@ -0,0 +1,143 @@
+package libgo365
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for the kinds of Graph failure callers commonly handle.
+// A *GraphError matches the one for its status with errors.Is, so callers
+// can write errors.Is(err, libgo365.ErrNotFound) instead of comparing
+// status codes.
+var (
+	ErrBadRequest         = errors.New("bad request")         // 400
+	ErrUnauthorized       = errors.New("unauthorized")        // 401: the token was rejected
+	ErrForbidden          = errors.New("forbidden")           // 403: missing permission or consent
+	ErrNotFound           = errors.New("not found")           // 404
+	ErrConflict           = errors.New("conflict")            // 409
+	ErrGone               = errors.New("gone")                // 410: such as an expired delta link
+	ErrPreconditionFailed = errors.New("precondition failed") // 412: the ETag no longer matches
+	ErrThrottled          = errors.New("throttled")           // 429
+	ErrServiceUnavailable = errors.New("service unavailable") // 500 and above
+)
+
+// statusErrors maps statuses to their sentinels; 5xx are handled apart
+var statusErrors = map[int]error{
+	http.StatusBadRequest:         ErrBadRequest,
+	http.StatusUnauthorized:       ErrUnauthorized,
+	http.StatusForbidden:          ErrForbidden,
+	http.StatusNotFound:           ErrNotFound,
+	http.StatusConflict:           ErrConflict,
+	http.StatusGone:               ErrGone,
+	http.StatusPreconditionFailed: ErrPreconditionFailed,
+	http.StatusTooManyRequests:    ErrThrottled,
+}
+
+// GraphError is returned when Microsoft Graph responds with a non-success status
+type GraphError struct {
+	StatusCode int
+	Code       string // Graph error code (e.g., "ErrorItemNotFound"), if the body had one
+	Message    string // Graph error message, if the body had one
+	Body       string // Raw response body
+
+	// InnerCode is the more specific code some services nest in
+	// innerError, such as "ErrorInvalidIdMalformed" under "BadRequest"
+	InnerCode       string
+	RequestID       string        // Graph's request-id, for Microsoft support
+	ClientRequestID string        // client-request-id the client sent, echoed back by Graph
+	Date            string        // When Graph failed the request, from its Date header
+	RetryAfter      time.Duration // From Retry-After on throttled responses, or 0
+}
+
+// Error keeps the long-standing "API request failed with status ..." format,
+// followed by the IDs and date a Microsoft support ticket needs
+func (e *GraphError) Error() string {
+	msg := fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+
+	var details []string
+	if e.RequestID != "" {
+		details = append(details, "request-id: "+e.RequestID)
+	}
+	if e.ClientRequestID != "" {
+		details = append(details, "client-request-id: "+e.ClientRequestID)
+	}
+	if e.Date != "" {
+		details = append(details, "date: "+e.Date)
+	}
+	if len(details) > 0 {
+		msg += " (" + strings.Join(details, ", ") + ")"
+	}
+	return msg
+}
+
+// Is matches the sentinel error for the response's status, such as
+// ErrNotFound for a 404
+func (e *GraphError) Is(target error) bool {
+	if e.StatusCode >= 500 {
+		return target == ErrServiceUnavailable
+	}
+	sentinel, ok := statusErrors[e.StatusCode]
+	return ok && target == sentinel
+}
+
+// newGraphError builds a GraphError from a response, decoding Graph's
+// {"error": {"code": ..., "message": ...}} envelope when present
+func newGraphError(statusCode int, body []byte) *GraphError {
+	gerr := &GraphError{StatusCode: statusCode, Body: string(body)}
+
+	var envelope struct {
+		Error struct {
+			Code       string `json:"code"`
+			Message    string `json:"message"`
+			InnerError struct {
+				Code            string `json:"code"`
+				RequestID       string `json:"request-id"`
+				ClientRequestID string `json:"client-request-id"`
+				Date            string `json:"date"`
+			} `json:"innerError"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &envelope) == nil {
+		gerr.Code = envelope.Error.Code
+		gerr.Message = envelope.Error.Message
+		gerr.InnerCode = envelope.Error.InnerError.Code
+		gerr.RequestID = envelope.Error.InnerError.RequestID
+		gerr.ClientRequestID = envelope.Error.InnerError.ClientRequestID
+		gerr.Date = envelope.Error.InnerError.Date
+	}
+
+	return gerr
+}
+
+// newResponseError builds a GraphError from a failed response, adding the
+// request IDs, date and Retry-After from its headers
+func newResponseError(resp *http.Response, body []byte) *GraphError {
+	gerr := newGraphError(resp.StatusCode, body)
+	if id := resp.Header.Get("request-id"); id != "" {
+		gerr.RequestID = id
+	}
+	if id := resp.Header.Get("client-request-id"); id != "" {
+		gerr.ClientRequestID = id
+	} else if gerr.ClientRequestID == "" && resp.Request != nil {
+		gerr.ClientRequestID = resp.Request.Header.Get("client-request-id")
+	}
+	if date := resp.Header.Get("Date"); date != "" {
+		gerr.Date = date
+	}
+	gerr.RetryAfter = ParseRetryAfter(resp.Header.Get("Retry-After"))
+	return gerr
+}
+
+// StatusCode returns the HTTP status of the Graph error wrapped in err, or 0 if
+// err did not come from a Graph response
+func StatusCode(err error) int {
+	var gerr *GraphError
+	if errors.As(err, &gerr) {
+		return gerr.StatusCode
+	}
+	return 0
+}
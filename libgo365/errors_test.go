@@ -0,0 +1,185 @@
+package libgo365
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGraphError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":"ErrorItemNotFound","message":"The specified object was not found in the store."}}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	_, err := client.GetMessage(context.Background(), "missing")
+	if err == nil {
+		t.Fatal("Expected error for 404 response")
+	}
+
+	var gerr *GraphError
+	if !errors.As(err, &gerr) {
+		t.Fatalf("Expected *GraphError, got %T", err)
+	}
+	if gerr.Code != "ErrorItemNotFound" || gerr.Message == "" {
+		t.Errorf("Expected decoded Graph error, got %+v", gerr)
+	}
+	if !strings.HasPrefix(err.Error(), "API request failed with status 404") {
+		t.Errorf("Unexpected error text: %s", err)
+	}
+
+	wrapped := fmt.Errorf("failed to get message: %w", err)
+	if StatusCode(wrapped) != http.StatusNotFound {
+		t.Errorf("Expected status 404 through wrapping, got %d", StatusCode(wrapped))
+	}
+	if StatusCode(errors.New("other")) != 0 {
+		t.Error("Expected 0 for non-Graph errors")
+	}
+}
+
+func TestGraphErrorNonJSONBody(t *testing.T) {
+	gerr := newGraphError(http.StatusBadGateway, []byte("<html>Bad Gateway</html>"))
+	if gerr.Code != "" || gerr.Body != "<html>Bad Gateway</html>" {
+		t.Errorf("Unexpected error: %+v", gerr)
+	}
+}
+
+func TestGraphErrorRequestDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("request-id", "header-id")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":{"code":"TooManyRequests","message":"Slow down","innerError":{"request-id":"body-id"}}}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	_, err := client.Get(context.Background(), "/me")
+	var gerr *GraphError
+	if !errors.As(err, &gerr) {
+		t.Fatalf("Expected *GraphError, got %v", err)
+	}
+	if gerr.RequestID != "header-id" {
+		t.Errorf("Expected the header request ID to win, got %q", gerr.RequestID)
+	}
+	if gerr.RetryAfter != 30*time.Second {
+		t.Errorf("Expected Retry-After of 30s, got %v", gerr.RetryAfter)
+	}
+
+	// Batch sub-responses often only carry the ID in the body
+	batched := (&BatchResponse{Status: http.StatusNotFound, Body: []byte(`{"error":{"code":"ErrorItemNotFound","innerError":{"request-id":"body-id"}}}`)}).Err()
+	if !errors.As(batched, &gerr) || gerr.RequestID != "body-id" {
+		t.Errorf("Expected the request ID from the body, got %+v", batched)
+	}
+}
+
+func TestGraphErrorIs(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, ErrBadRequest},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusGone, ErrGone},
+		{http.StatusPreconditionFailed, ErrPreconditionFailed},
+		{http.StatusTooManyRequests, ErrThrottled},
+		{http.StatusInternalServerError, ErrServiceUnavailable},
+		{http.StatusServiceUnavailable, ErrServiceUnavailable},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("failed: %w", newGraphError(tt.status, nil))
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Expected %d to match %v", tt.status, tt.want)
+		}
+		if tt.want != ErrNotFound && errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected %d not to match ErrNotFound", tt.status)
+		}
+	}
+
+	if errors.Is(newGraphError(http.StatusTeapot, nil), ErrBadRequest) {
+		t.Error("Expected an unmapped status to match no sentinel")
+	}
+	if !errors.Is(&BackoffError{Until: time.Now()}, ErrThrottled) {
+		t.Error("Expected a BackoffError to match ErrThrottled")
+	}
+}
+
+func TestGraphErrorInnerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("client-request-id", "client-1")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"code":"BadRequest","message":"Id is malformed.","innerError":{"code":"ErrorInvalidIdMalformed","request-id":"req-1"}}}`)
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL, tokens: StaticToken("test-token")}
+	_, err := client.GetMessage(context.Background(), "bad")
+
+	var gerr *GraphError
+	if !errors.As(err, &gerr) {
+		t.Fatalf("Expected *GraphError, got %v", err)
+	}
+	if gerr.InnerCode != "ErrorInvalidIdMalformed" || gerr.RequestID != "req-1" || gerr.ClientRequestID != "client-1" {
+		t.Errorf("Unexpected error details %+v", gerr)
+	}
+	if !errors.Is(err, ErrBadRequest) {
+		t.Error("Expected the error to match ErrBadRequest")
+	}
+}
+
+func TestClientRequestID(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get("client-request-id"))
+		w.Header().Set("request-id", "req-1")
+		w.Header().Set("Date", "Tue, 20 Jan 2026 01:02:03 GMT")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL, tokens: StaticToken("test-token")}
+	client.Get(context.Background(), "/me/messages/a")
+	_, err := client.Get(context.Background(), "/me/messages/b")
+
+	if len(sent) != 2 || len(sent[0]) != 36 || sent[0] == sent[1] {
+		t.Fatalf("Expected a new UUID per request, got %q", sent)
+	}
+	var gerr *GraphError
+	if !errors.As(err, &gerr) {
+		t.Fatalf("Expected *GraphError, got %v", err)
+	}
+	// Graph didn't echo the ID, so it comes from the request
+	if gerr.ClientRequestID != sent[1] || gerr.Date != "Tue, 20 Jan 2026 01:02:03 GMT" {
+		t.Errorf("Unexpected error details %+v", gerr)
+	}
+	want := "(request-id: req-1, client-request-id: " + sent[1] + ", date: Tue, 20 Jan 2026 01:02:03 GMT)"
+	if !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("Expected the IDs in the message, got %q", err.Error())
+	}
+
+	// A caller's own ID is kept
+	client.Get(context.Background(), "/me/messages/c", WithHeader("client-request-id", "mine"))
+	if sent[2] != "mine" {
+		t.Errorf("Expected the caller's client-request-id, got %q", sent[2])
+	}
+}
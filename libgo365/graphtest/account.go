@@ -0,0 +1,274 @@
+package graphtest
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/njt/go365/libgo365"
+)
+
+// Account is a user in the fake tenant with their mailbox, calendar and
+// OneDrive. Changes made through Graph requests show up in its methods, so
+// tests can check what their code did.
+type Account struct {
+	User *libgo365.User
+
+	s             *Server
+	folders       []*libgo365.MailFolder
+	messages      []*libgo365.Message
+	messageFolder map[string]string                 // Message ID to folder ID
+	attachments   map[string][]*libgo365.Attachment // By message ID
+	calendars     []*libgo365.Calendar
+	events        []*libgo365.Event
+	eventCalendar map[string]string // Event ID to calendar ID
+	items         []*libgo365.DriveItem
+	content       map[string][]byte // File content by item ID
+}
+
+// wellKnownFolders are the mail folders every mailbox has. Their IDs are
+// the well-known names, so either works in requests.
+var wellKnownFolders = []struct{ id, name string }{
+	{"inbox", "Inbox"},
+	{"drafts", "Drafts"},
+	{"sentitems", "Sent Items"},
+	{"deleteditems", "Deleted Items"},
+	{"archive", "Archive"},
+	{"junkemail", "Junk Email"},
+}
+
+// DefaultCalendarID is the ID of every account's default calendar
+const DefaultCalendarID = "calendar"
+
+// RootID is the ID of every account's OneDrive root folder
+const RootID = "root"
+
+// AddUser adds a user with an empty mailbox, calendar and OneDrive, giving
+// it an ID if it has none
+func (s *Server) AddUser(user *libgo365.User) *Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addUser(user)
+}
+
+func (s *Server) addUser(user *libgo365.User) *Account {
+	if user.ID == "" {
+		user.ID = s.newID("user")
+	}
+	a := &Account{
+		User:          user,
+		s:             s,
+		messageFolder: map[string]string{},
+		attachments:   map[string][]*libgo365.Attachment{},
+		eventCalendar: map[string]string{},
+		content:       map[string][]byte{},
+	}
+	for _, f := range wellKnownFolders {
+		a.folders = append(a.folders, &libgo365.MailFolder{ID: f.id, DisplayName: f.name})
+	}
+	a.calendars = append(a.calendars, &libgo365.Calendar{
+		ID:    DefaultCalendarID,
+		Name:  "Calendar",
+		Owner: &libgo365.EmailAddress{Name: user.DisplayName, Address: user.Mail},
+	})
+	a.items = append(a.items, &libgo365.DriveItem{
+		ID:     RootID,
+		Name:   "root",
+		Folder: &libgo365.FolderFacet{},
+		ETag:   s.newETag(),
+	})
+	s.accounts = append(s.accounts, a)
+	return a
+}
+
+// Me returns the signed-in user's account, which /me requests use
+func (s *Server) Me() *Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.me
+}
+
+// SetMe makes a the signed-in user
+func (s *Server) SetMe(a *Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.me = a
+}
+
+// Account returns the account with the given ID, user principal name or
+// mail address, or nil
+func (s *Server) Account(idOrName string) *Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.account(idOrName)
+}
+
+func (s *Server) account(idOrName string) *Account {
+	for _, a := range s.accounts {
+		if a.User.ID == idOrName || strings.EqualFold(a.User.UserPrincipalName, idOrName) || strings.EqualFold(a.User.Mail, idOrName) {
+			return a
+		}
+	}
+	return nil
+}
+
+// AddMailFolder adds a top-level mail folder and returns it
+func (a *Account) AddMailFolder(name string) *libgo365.MailFolder {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	folder := &libgo365.MailFolder{ID: a.s.newID("folder"), DisplayName: name}
+	a.folders = append(a.folders, folder)
+	return folder
+}
+
+// AddMessage puts a message in a folder, filling in its ID, ETag and
+// received time if they are unset, and returns it. Its Attachments are
+// kept apart, as Graph keeps them, and listed through the attachments
+// endpoints.
+func (a *Account) AddMessage(folderID string, msg *libgo365.Message) *libgo365.Message {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	return a.addMessage(folderID, msg)
+}
+
+func (a *Account) addMessage(folderID string, msg *libgo365.Message) *libgo365.Message {
+	if msg.ID == "" {
+		msg.ID = a.s.newID("message")
+	}
+	if msg.ConversationID == "" {
+		msg.ConversationID = a.s.newID("conversation")
+	}
+	if msg.ReceivedDateTime == nil {
+		now := time.Now().UTC().Truncate(time.Second)
+		msg.ReceivedDateTime = &now
+	}
+	if len(msg.Attachments) > 0 {
+		for _, attachment := range msg.Attachments {
+			a.addAttachment(msg, attachment)
+		}
+		msg.Attachments = nil
+	}
+	msg.ETag = a.s.newETag()
+	// Newest first, as Graph lists them
+	a.messages = append([]*libgo365.Message{msg}, a.messages...)
+	a.messageFolder[msg.ID] = folderID
+	return msg
+}
+
+// Messages returns the messages in a folder, or in the whole mailbox if
+// folderID is empty
+func (a *Account) Messages(folderID string) []*libgo365.Message {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	return a.folderMessages(folderID)
+}
+
+// Attachments returns a message's attachments
+func (a *Account) Attachments(messageID string) []*libgo365.Attachment {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	return slices.Clone(a.attachments[messageID])
+}
+
+func (a *Account) folderMessages(folderID string) []*libgo365.Message {
+	var messages []*libgo365.Message
+	for _, msg := range a.messages {
+		if folderID == "" || a.messageFolder[msg.ID] == folderID {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// AddEvent puts an event in a calendar, filling in its ID, ETag and
+// organizer if they are unset, and returns it
+func (a *Account) AddEvent(calendarID string, event *libgo365.Event) *libgo365.Event {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	return a.addEvent(calendarID, event)
+}
+
+func (a *Account) addEvent(calendarID string, event *libgo365.Event) *libgo365.Event {
+	if event.ID == "" {
+		event.ID = a.s.newID("event")
+	}
+	if event.Organizer == nil {
+		event.Organizer = &libgo365.Recipient{EmailAddress: &libgo365.EmailAddress{Name: a.User.DisplayName, Address: a.User.Mail}}
+	}
+	if event.Type == "" {
+		event.Type = libgo365.EventSingleInstance
+	}
+	event.ETag = a.s.newETag()
+	a.events = append(a.events, event)
+	a.eventCalendar[event.ID] = calendarID
+	return event
+}
+
+// Events returns the events in a calendar, or in all of them if calendarID
+// is empty
+func (a *Account) Events(calendarID string) []*libgo365.Event {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	return a.calendarEvents(calendarID)
+}
+
+func (a *Account) calendarEvents(calendarID string) []*libgo365.Event {
+	var events []*libgo365.Event
+	for _, event := range a.events {
+		if calendarID == "" || a.eventCalendar[event.ID] == calendarID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// AddFile stores a file in the OneDrive folder at path ("/" for the root),
+// creating the folders on the way, and returns it
+func (a *Account) AddFile(path, name string, content []byte) *libgo365.DriveItem {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	parent := a.mkdirAll(path)
+	return a.putFile(parent, name, content)
+}
+
+// AddFolder creates the OneDrive folder at path and any above it, and
+// returns it
+func (a *Account) AddFolder(path string) *libgo365.DriveItem {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	return a.mkdirAll(path)
+}
+
+// File returns the content of the file at path, and whether it exists
+func (a *Account) File(path string) ([]byte, bool) {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	item := a.itemByPath(path)
+	if item == nil || item.File == nil {
+		return nil, false
+	}
+	return a.content[item.ID], true
+}
+
+// serve answers a request for the account; segments is the path after
+// /me or /users/{id}
+func (a *Account) serve(r *request, segments []string) bool {
+	if len(segments) == 0 {
+		if r.Method != http.MethodGet {
+			return false
+		}
+		r.writeJSON(http.StatusOK, a.User)
+		return true
+	}
+
+	switch segments[0] {
+	case "messages", "mailFolders", "sendMail":
+		return a.serveMail(r, segments)
+	case "events", "calendar", "calendars", "calendarView":
+		return a.serveCalendar(r, segments)
+	case "drive":
+		return a.serveDrive(r, strings.TrimPrefix(strings.Join(segments, "/"), "drive"))
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package graphtest
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/njt/go365/libgo365"
+)
+
+// serveCalendar answers calendars, events and calendarView requests, with
+// /calendar and /calendars/{id} prefixes
+func (a *Account) serveCalendar(r *request, segments []string) bool {
+	calendarID := ""
+	switch {
+	case segments[0] == "calendars" && len(segments) == 1:
+		if r.Method != http.MethodGet {
+			return false
+		}
+		r.writeCollection(toAny(a.calendars))
+		return true
+	case segments[0] == "calendar" && len(segments) == 1:
+		segments = []string{"calendars", DefaultCalendarID}
+		fallthrough
+	case segments[0] == "calendars" && len(segments) == 2:
+		if r.Method != http.MethodGet {
+			return false
+		}
+		i := slices.IndexFunc(a.calendars, func(c *libgo365.Calendar) bool { return c.ID == segments[1] })
+		if i < 0 {
+			notFound(r.w)
+			return true
+		}
+		r.writeJSON(http.StatusOK, a.calendars[i])
+		return true
+	case segments[0] == "calendar":
+		calendarID, segments = DefaultCalendarID, segments[1:]
+	case segments[0] == "calendars":
+		calendarID, segments = segments[1], segments[2:]
+		if !slices.ContainsFunc(a.calendars, func(c *libgo365.Calendar) bool { return c.ID == calendarID }) {
+			notFound(r.w)
+			return true
+		}
+	}
+
+	switch {
+	case segments[0] == "calendarView" && len(segments) == 1 && r.Method == http.MethodGet:
+		a.calendarView(r, calendarID)
+		return true
+
+	case segments[0] == "events" && len(segments) == 1 && r.Method == http.MethodGet:
+		r.writeCollection(toAny(a.calendarEvents(calendarID)))
+		return true
+
+	case segments[0] == "events" && len(segments) == 1 && r.Method == http.MethodPost:
+		var event libgo365.Event
+		if !r.decode(&event) {
+			return true
+		}
+		if event.Start == nil || event.End == nil {
+			writeError(r.w, http.StatusBadRequest, "ErrorInvalidRequest", "Your request can't be completed. An event needs a start and an end.")
+			return true
+		}
+		event.ID = ""
+		if calendarID == "" {
+			calendarID = DefaultCalendarID
+		}
+		r.writeJSON(http.StatusCreated, a.addEvent(calendarID, &event))
+		return true
+
+	case segments[0] == "events" && len(segments) >= 2:
+		return a.serveEvent(r, segments[1], segments[2:])
+	}
+	return false
+}
+
+// serveEvent answers requests for one event and its responses
+func (a *Account) serveEvent(r *request, id string, action []string) bool {
+	i := slices.IndexFunc(a.events, func(e *libgo365.Event) bool { return e.ID == id })
+	if i < 0 {
+		notFound(r.w)
+		return true
+	}
+	event := a.events[i]
+
+	switch {
+	case len(action) == 0 && r.Method == http.MethodGet:
+		r.writeJSON(http.StatusOK, event)
+	case len(action) == 0 && r.Method == http.MethodPatch:
+		if !r.matches(event.ETag) || !r.patch(event) {
+			return true
+		}
+		event.ID = id
+		event.ETag = a.s.newETag()
+		r.writeJSON(http.StatusOK, event)
+	case len(action) == 0 && r.Method == http.MethodDelete:
+		if !r.matches(event.ETag) {
+			return true
+		}
+		a.events = slices.Delete(a.events, i, i+1)
+		delete(a.eventCalendar, id)
+		r.w.WriteHeader(http.StatusNoContent)
+	case len(action) == 1 && r.Method == http.MethodPost && responses[action[0]] != "":
+		event.ResponseStatus = &libgo365.ResponseStatus{
+			Response: responses[action[0]],
+			Time:     time.Now().UTC().Format(time.RFC3339),
+		}
+		event.ETag = a.s.newETag()
+		r.w.WriteHeader(http.StatusAccepted)
+	case len(action) == 1 && action[0] == "cancel" && r.Method == http.MethodPost:
+		event.IsCancelled = true
+		event.ETag = a.s.newETag()
+		r.w.WriteHeader(http.StatusAccepted)
+	default:
+		return false
+	}
+	return true
+}
+
+// responses maps the event response actions to the status they record
+var responses = map[string]libgo365.ResponseType{
+	"accept":            libgo365.ResponseAccepted,
+	"tentativelyAccept": libgo365.ResponseTentativelyAccepted,
+	"decline":           libgo365.ResponseDeclined,
+}
+
+// calendarView answers calendarView with the events overlapping the
+// requested window, in start order
+func (a *Account) calendarView(r *request, calendarID string) {
+	query := r.URL.Query()
+	start, err1 := parseViewTime(query.Get("startDateTime"))
+	end, err2 := parseViewTime(query.Get("endDateTime"))
+	if err1 != nil || err2 != nil {
+		writeError(r.w, http.StatusBadRequest, "ErrorInvalidParameter",
+			"This request requires a time window specified by the query string parameters StartDateTime and EndDateTime.")
+		return
+	}
+
+	var events []*libgo365.Event
+	for _, event := range a.calendarEvents(calendarID) {
+		eventStart, eventEnd := eventTime(event.Start), eventTime(event.End)
+		if eventStart.Before(end) && eventEnd.After(start) {
+			events = append(events, event)
+		}
+	}
+	slices.SortStableFunc(events, func(x, y *libgo365.Event) int {
+		return eventTime(x.Start).Compare(eventTime(y.Start))
+	})
+	r.writeCollection(toAny(events))
+}
+
+// parseViewTime parses a calendarView bound, which may omit the zone
+func parseViewTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02T15:04:05", s)
+}
+
+// eventTime converts an event's start or end to a time, reading it in its
+// zone when that is an IANA name and in UTC otherwise
+func eventTime(dt *libgo365.DateTimeTimeZone) time.Time {
+	if dt == nil {
+		return time.Time{}
+	}
+	loc := time.UTC
+	if dt.TimeZone != "" && !strings.EqualFold(dt.TimeZone, "UTC") {
+		if l, err := time.LoadLocation(dt.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	value := dt.DateTime
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value = value[:i]
+	}
+	t, _ := time.ParseInLocation("2006-01-02T15:04:05", value, loc)
+	return t
+}
@@ -0,0 +1,228 @@
+package graphtest
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/njt/go365/libgo365"
+)
+
+// serveDrive answers /drive requests; rest is the path after /drive, such
+// as /root:/Documents/a.txt:/content
+func (a *Account) serveDrive(r *request, rest string) bool {
+	if rest == "" {
+		if r.Method != http.MethodGet {
+			return false
+		}
+		r.writeJSON(http.StatusOK, &libgo365.Drive{
+			ID:        "drive-" + a.User.ID,
+			Name:      "OneDrive",
+			DriveType: libgo365.DriveBusiness,
+			Owner:     &libgo365.Identity{User: &libgo365.IdentityUser{ID: a.User.ID, DisplayName: a.User.DisplayName}},
+		})
+		return true
+	}
+
+	// Find the item the request addresses and what it asks of it
+	var item *libgo365.DriveItem
+	var itemPath, action string
+	switch {
+	case strings.HasPrefix(rest, "/root:"):
+		itemPath, action, _ = strings.Cut(strings.TrimPrefix(rest, "/root:"), ":")
+		item = a.itemByPath(itemPath)
+	case rest == "/root" || strings.HasPrefix(rest, "/root/"):
+		item, action = a.item(RootID), strings.TrimPrefix(rest, "/root")
+	case strings.HasPrefix(rest, "/items/"):
+		id, tail, _ := strings.Cut(strings.TrimPrefix(rest, "/items/"), "/")
+		action = "/" + tail
+		if before, name, ok := strings.Cut(id, ":"); ok {
+			// items/{parent}:/name:/content
+			parent := a.item(before)
+			if parent == nil {
+				notFound(r.w)
+				return true
+			}
+			name, action, _ = strings.Cut(name, ":")
+			itemPath = path.Join(a.pathOf(parent), name)
+			item = a.itemByPath(itemPath)
+			break
+		}
+		item = a.item(id)
+	default:
+		return false
+	}
+	action = strings.TrimSuffix(action, "/")
+
+	if action == "/content" && r.Method == http.MethodPut {
+		if item == nil && itemPath == "" {
+			notFound(r.w)
+			return true
+		}
+		if item != nil && !r.matches(item.ETag) {
+			return true
+		}
+		if item == nil {
+			parent := a.mkdirAll(path.Dir(itemPath))
+			item = a.putFile(parent, path.Base(itemPath), r.body)
+			r.writeJSON(http.StatusCreated, item)
+			return true
+		}
+		item = a.putFile(a.item(item.ParentReference.ID), item.Name, r.body)
+		r.writeJSON(http.StatusOK, item)
+		return true
+	}
+
+	if item == nil {
+		writeError(r.w, http.StatusNotFound, "itemNotFound", "The resource could not be found.")
+		return true
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		r.writeJSON(http.StatusOK, item)
+	case action == "" && r.Method == http.MethodDelete && item.ID != RootID:
+		if !r.matches(item.ETag) {
+			return true
+		}
+		a.deleteItem(item)
+		r.w.WriteHeader(http.StatusNoContent)
+	case action == "/children" && r.Method == http.MethodGet:
+		r.writeCollection(toAny(a.children(item)))
+	case action == "/content" && r.Method == http.MethodGet && item.File != nil:
+		r.w.Header().Set("Content-Type", item.File.MimeType)
+		r.w.Write(a.content[item.ID])
+	default:
+		return false
+	}
+	return true
+}
+
+// item returns the drive item with the given ID
+func (a *Account) item(id string) *libgo365.DriveItem {
+	for _, item := range a.items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// children returns the items directly inside folder
+func (a *Account) children(folder *libgo365.DriveItem) []*libgo365.DriveItem {
+	var children []*libgo365.DriveItem
+	for _, item := range a.items {
+		if item.ParentReference != nil && item.ParentReference.ID == folder.ID {
+			children = append(children, item)
+		}
+	}
+	return children
+}
+
+// itemByPath finds the item at a path from the root, matching names
+// case-insensitively as OneDrive does
+func (a *Account) itemByPath(p string) *libgo365.DriveItem {
+	item := a.item(RootID)
+	for _, name := range strings.Split(strings.Trim(p, "/"), "/") {
+		if name == "" {
+			continue
+		}
+		children := a.children(item)
+		i := slices.IndexFunc(children, func(c *libgo365.DriveItem) bool { return strings.EqualFold(c.Name, name) })
+		if i < 0 {
+			return nil
+		}
+		item = children[i]
+	}
+	return item
+}
+
+// pathOf returns an item's path from the root
+func (a *Account) pathOf(item *libgo365.DriveItem) string {
+	if item.ID == RootID {
+		return "/"
+	}
+	return path.Join("/", strings.TrimPrefix(item.ParentReference.Path, "/drive/root:"), item.Name)
+}
+
+// mkdirAll returns the folder at p, creating it and any folders above it
+func (a *Account) mkdirAll(p string) *libgo365.DriveItem {
+	folder := a.item(RootID)
+	for _, name := range strings.Split(strings.Trim(p, "/"), "/") {
+		if name == "" {
+			continue
+		}
+		children := a.children(folder)
+		if i := slices.IndexFunc(children, func(c *libgo365.DriveItem) bool { return strings.EqualFold(c.Name, name) }); i >= 0 {
+			folder = children[i]
+			continue
+		}
+		created := a.newItem(folder, name)
+		created.Folder = &libgo365.FolderFacet{}
+		folder = created
+	}
+	return folder
+}
+
+// putFile creates or replaces the file called name in parent
+func (a *Account) putFile(parent *libgo365.DriveItem, name string, content []byte) *libgo365.DriveItem {
+	children := a.children(parent)
+	var item *libgo365.DriveItem
+	if i := slices.IndexFunc(children, func(c *libgo365.DriveItem) bool { return strings.EqualFold(c.Name, name) }); i >= 0 {
+		item = children[i]
+		now := time.Now().UTC().Truncate(time.Second)
+		item.LastModifiedDateTime = &now
+		item.ETag = a.s.newETag()
+	} else {
+		item = a.newItem(parent, name)
+	}
+
+	mimeType := mime.TypeByExtension(path.Ext(name))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	item.File = &libgo365.FileFacet{MimeType: mimeType}
+	item.Size = int64(len(content))
+	a.content[item.ID] = append([]byte(nil), content...)
+	return item
+}
+
+// newItem adds an empty item called name to parent
+func (a *Account) newItem(parent *libgo365.DriveItem, name string) *libgo365.DriveItem {
+	now := time.Now().UTC().Truncate(time.Second)
+	item := &libgo365.DriveItem{
+		ID:                   a.s.newID("item"),
+		Name:                 name,
+		CreatedDateTime:      &now,
+		LastModifiedDateTime: &now,
+		ParentReference: &libgo365.ItemReference{
+			DriveID:   "drive-" + a.User.ID,
+			DriveType: libgo365.DriveBusiness,
+			ID:        parent.ID,
+			Path:      strings.TrimSuffix("/drive/root:"+a.pathOf(parent), "/"),
+		},
+		ETag: a.s.newETag(),
+	}
+	item.WebURL = fmt.Sprintf("https://contoso-my.sharepoint.com/personal/%s/Documents%s", item.ParentReference.DriveID, path.Join(a.pathOf(parent), name))
+	if parent.Folder != nil {
+		parent.Folder.ChildCount++
+	}
+	a.items = append(a.items, item)
+	return item
+}
+
+// deleteItem removes an item and everything inside it
+func (a *Account) deleteItem(item *libgo365.DriveItem) {
+	for _, child := range a.children(item) {
+		a.deleteItem(child)
+	}
+	if parent := a.item(item.ParentReference.ID); parent != nil && parent.Folder != nil {
+		parent.Folder.ChildCount--
+	}
+	a.items = slices.DeleteFunc(a.items, func(i *libgo365.DriveItem) bool { return i.ID == item.ID })
+	delete(a.content, item.ID)
+}
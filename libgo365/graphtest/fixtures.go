@@ -0,0 +1,121 @@
+package graphtest
+
+import (
+	"time"
+
+	"github.com/njt/go365/libgo365"
+)
+
+// Canned users. Me is Adele; the others are there to look up, share with
+// and send mail to.
+var (
+	Adele = libgo365.User{
+		ID:                "87d349ed-44d7-43e1-9a83-5f2406dee5bd",
+		DisplayName:       "Adele Vance",
+		UserPrincipalName: "AdeleV@contoso.com",
+		Mail:              "AdeleV@contoso.com",
+		GivenName:         "Adele",
+		Surname:           "Vance",
+		JobTitle:          "Retail Manager",
+		Department:        "Retail",
+		OfficeLocation:    "18/2111",
+	}
+	Alex = libgo365.User{
+		ID:                "5bde3e51-d13b-4db1-9948-fe4b109d11a7",
+		DisplayName:       "Alex Wilber",
+		UserPrincipalName: "AlexW@contoso.com",
+		Mail:              "AlexW@contoso.com",
+		GivenName:         "Alex",
+		Surname:           "Wilber",
+		JobTitle:          "Marketing Assistant",
+		Department:        "Marketing",
+	}
+	Megan = libgo365.User{
+		ID:                "48d31887-5fad-4d73-a9f5-3c356e68a038",
+		DisplayName:       "Megan Bowen",
+		UserPrincipalName: "MeganB@contoso.com",
+		Mail:              "MeganB@contoso.com",
+		GivenName:         "Megan",
+		Surname:           "Bowen",
+		JobTitle:          "Marketing Manager",
+		Department:        "Marketing",
+	}
+)
+
+// seed creates the canned tenant: Adele, Alex and Megan, with a few
+// messages in Adele's inbox, two events on her calendar on 20 January 2026
+// and a Documents folder in her OneDrive
+func (s *Server) seed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Copies, so tests changing an account don't change the package's users
+	adele, alex, megan := Adele, Alex, Megan
+	s.me = s.addUser(&adele)
+	s.addUser(&alex)
+	s.addUser(&megan)
+
+	from := func(u libgo365.User) *libgo365.Recipient {
+		return &libgo365.Recipient{EmailAddress: &libgo365.EmailAddress{Name: u.DisplayName, Address: u.Mail}}
+	}
+	to := []*libgo365.Recipient{from(Adele)}
+	at := func(value string) *time.Time {
+		t, _ := time.Parse(time.RFC3339, value)
+		return &t
+	}
+
+	// Added oldest first, so they list newest first
+	s.me.addMessage("inbox", &libgo365.Message{
+		Subject:          "Welcome to Contoso",
+		Body:             &libgo365.ItemBody{ContentType: "text", Content: "Glad to have you on the team."},
+		BodyPreview:      "Glad to have you on the team.",
+		From:             from(Megan),
+		ToRecipients:     to,
+		ReceivedDateTime: at("2026-01-19T21:00:00Z"),
+		IsRead:           true,
+		Importance:       libgo365.ImportanceNormal,
+	})
+	s.me.addMessage("inbox", &libgo365.Message{
+		Subject:          "Q1 marketing plan",
+		Body:             &libgo365.ItemBody{ContentType: "html", Content: "<p>Draft plan attached for review.</p>"},
+		BodyPreview:      "Draft plan attached for review.",
+		From:             from(Alex),
+		ToRecipients:     to,
+		CcRecipients:     []*libgo365.Recipient{from(Megan)},
+		ReceivedDateTime: at("2026-01-19T23:15:00Z"),
+		Importance:       libgo365.ImportanceHigh,
+	})
+	s.me.addMessage("inbox", &libgo365.Message{
+		Subject:          "Lunch on Tuesday?",
+		Body:             &libgo365.ItemBody{ContentType: "text", Content: "Are you free for lunch on Tuesday?"},
+		BodyPreview:      "Are you free for lunch on Tuesday?",
+		From:             from(Megan),
+		ToRecipients:     to,
+		ReceivedDateTime: at("2026-01-20T01:30:00Z"),
+		Importance:       libgo365.ImportanceNormal,
+	})
+
+	s.me.addEvent(DefaultCalendarID, &libgo365.Event{
+		Subject:   "Marketing sync",
+		Start:     &libgo365.DateTimeTimeZone{DateTime: "2026-01-20T21:00:00.0000000", TimeZone: "UTC"},
+		End:       &libgo365.DateTimeTimeZone{DateTime: "2026-01-20T21:30:00.0000000", TimeZone: "UTC"},
+		Location:  &libgo365.Location{DisplayName: "Conf Room Rainier"},
+		Organizer: from(Megan),
+		Attendees: []*libgo365.Attendee{
+			{EmailAddress: from(Adele).EmailAddress, Type: libgo365.AttendeeRequired},
+			{EmailAddress: from(Alex).EmailAddress, Type: libgo365.AttendeeOptional},
+		},
+		ResponseStatus: &libgo365.ResponseStatus{Response: libgo365.ResponseNotResponded},
+		ShowAs:         libgo365.FreeBusyBusy,
+	})
+	s.me.addEvent(DefaultCalendarID, &libgo365.Event{
+		Subject:        "Focus time",
+		Start:          &libgo365.DateTimeTimeZone{DateTime: "2026-01-20T23:00:00.0000000", TimeZone: "UTC"},
+		End:            &libgo365.DateTimeTimeZone{DateTime: "2026-01-21T01:00:00.0000000", TimeZone: "UTC"},
+		ResponseStatus: &libgo365.ResponseStatus{Response: libgo365.ResponseOrganizer},
+		ShowAs:         libgo365.FreeBusyBusy,
+	})
+
+	s.me.putFile(s.me.mkdirAll("/Documents"), "Welcome.txt", []byte("Welcome to OneDrive.\n"))
+	s.me.putFile(s.me.mkdirAll("/Documents"), "Budget.csv", []byte("quarter,amount\nQ1,1000\n"))
+}
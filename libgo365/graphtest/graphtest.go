@@ -0,0 +1,362 @@
+// Package graphtest runs a fake Microsoft Graph server for testing code that
+// uses libgo365, without credentials or a real tenant. It serves canned
+// users, a mailbox, a calendar and a OneDrive that tests can add to, pages
+// collections with @odata.nextLink, and can simulate throttling:
+//
+//	srv := graphtest.NewServer(t)
+//	srv.Me().AddMessage("inbox", &libgo365.Message{Subject: "Hello"})
+//	client := srv.Client()
+//	msgs, err := client.ListMessages(ctx, &libgo365.ListMessagesOptions{FolderID: "inbox"})
+//
+// The server understands the requests libgo365 sends for mail, calendar,
+// drive and user lookups, on /me and /users/{id}, in both API versions.
+// $top, $skip, $skiptoken, $select and $count are honored; $filter,
+// $orderby and $search are accepted but ignored, and recurring events are
+// not expanded. Anything else gets a 400 naming the unsupported request.
+package graphtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/njt/go365/libgo365"
+)
+
+// DefaultPageSize is how many items a collection returns when the request
+// has no $top, as Graph does for messages
+const DefaultPageSize = 10
+
+// graphHost is where nextLinks point, so clients treat them as Graph URLs
+const graphHost = "https://graph.microsoft.com"
+
+// Server is a fake Graph server. Its methods are safe to call while
+// requests are being served.
+type Server struct {
+	// URL is the server's base URL, such as http://127.0.0.1:1234
+	URL string
+
+	// PageSize overrides DefaultPageSize when set
+	PageSize int
+
+	srv *httptest.Server
+
+	mu         sync.Mutex
+	accounts   []*Account
+	me         *Account
+	nextID     int
+	throttled  int
+	retryAfter time.Duration
+	requests   []Request
+}
+
+// Request is a request the server received, for assertions
+type Request struct {
+	Method string
+	Path   string     // Without the API version, e.g. /me/messages
+	Query  url.Values // Query parameters
+	Header http.Header
+	Body   []byte
+}
+
+// NewServer starts a fake Graph server with the canned tenant (see Me) and
+// closes it when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	s.seed()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a libgo365 client whose requests go to the server. It
+// retries throttled requests like a real client; call SetMaxRetries(0) on it
+// to see the 429s from Throttle.
+func (s *Server) Client() *libgo365.Client {
+	client := libgo365.NewClient(context.Background(), "graphtest-token")
+	client.SetTransport(s.Transport())
+	return client
+}
+
+// Transport returns a transport that sends requests for graph.microsoft.com
+// to the server, for clients built some other way
+func (s *Server) Transport() http.RoundTripper {
+	target, _ := url.Parse(s.URL)
+	return &redirectTransport{target: target, base: s.srv.Client().Transport}
+}
+
+// redirectTransport rewrites the scheme and host of every request
+type redirectTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return t.base.RoundTrip(req)
+}
+
+// Throttle makes the next n requests fail with 429 Too Many Requests and a
+// Retry-After of retryAfter, rounded up to whole seconds; zero sends none
+func (s *Server) Throttle(n int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.throttled = n
+	s.retryAfter = retryAfter
+}
+
+// Requests returns the requests received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// newID returns an ID that is unique within the server
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%d", prefix, s.nextID)
+}
+
+// newETag returns a fresh weak ETag
+func (s *Server) newETag() string {
+	s.nextID++
+	return fmt.Sprintf(`W/"%d"`, s.nextID)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	path := r.URL.Path
+	for _, version := range libgo365.APIVersions {
+		path = strings.TrimPrefix(path, "/"+version)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	w.Header().Set("request-id", fmt.Sprintf("graphtest-%d", s.nextID))
+	if id := r.Header.Get("client-request-id"); id != "" {
+		w.Header().Set("client-request-id", id)
+	}
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeError(w, http.StatusUnauthorized, "InvalidAuthenticationToken", "Access token is empty.")
+		return
+	}
+	if s.throttled > 0 {
+		s.throttled--
+		if s.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int((s.retryAfter+time.Second-1)/time.Second)))
+		}
+		writeError(w, http.StatusTooManyRequests, "TooManyRequests", "Too many requests.")
+		return
+	}
+
+	req := &request{Request: r, w: w, s: s, body: body, path: path}
+	if !s.route(req) {
+		writeError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("graphtest: unsupported request %s %s", r.Method, path))
+	}
+}
+
+// request is a request being served
+type request struct {
+	*http.Request
+	w    http.ResponseWriter
+	s    *Server
+	body []byte
+	path string // Without the API version
+}
+
+// route finds the account the request is for and serves it, returning
+// false if the server doesn't handle the path
+func (s *Server) route(r *request) bool {
+	segments := strings.Split(strings.Trim(r.path, "/"), "/")
+
+	switch {
+	case segments[0] == "me":
+		return s.me.serve(r, segments[1:])
+	case segments[0] == "users" && len(segments) == 1:
+		if r.Method != http.MethodGet {
+			return false
+		}
+		users := make([]any, len(s.accounts))
+		for i, a := range s.accounts {
+			users[i] = a.User
+		}
+		r.writeCollection(users)
+		return true
+	case segments[0] == "users":
+		a := s.account(segments[1])
+		if a == nil {
+			writeError(r.w, http.StatusNotFound, "Request_ResourceNotFound",
+				fmt.Sprintf("Resource '%s' does not exist or one of its queried reference-property objects are not present.", segments[1]))
+			return true
+		}
+		return a.serve(r, segments[2:])
+	}
+	return false
+}
+
+// writeJSON writes v as the response body
+func (r *request) writeJSON(status int, v any) {
+	r.w.Header().Set("Content-Type", "application/json")
+	r.w.WriteHeader(status)
+	json.NewEncoder(r.w).Encode(r.selected(v))
+}
+
+// writeCollection writes a page of items as Graph does, with a nextLink
+// when there are more
+func (r *request) writeCollection(items []any) {
+	query := r.URL.Query()
+	top := r.s.PageSize
+	if top <= 0 {
+		top = DefaultPageSize
+	}
+	if n, err := strconv.Atoi(query.Get("$top")); err == nil && n > 0 {
+		top = n
+	}
+	skip, _ := strconv.Atoi(query.Get("$skip"))
+	if token := query.Get("$skiptoken"); token != "" {
+		skip, _ = strconv.Atoi(token)
+	}
+	skip = min(max(skip, 0), len(items))
+	end := min(skip+top, len(items))
+
+	page := make([]any, 0, end-skip)
+	for _, item := range items[skip:end] {
+		page = append(page, r.selected(item))
+	}
+	resp := map[string]any{"value": page}
+	if query.Get("$count") == "true" {
+		resp["@odata.count"] = len(items)
+	}
+	if end < len(items) {
+		query.Del("$skip")
+		query.Set("$skiptoken", strconv.Itoa(end))
+		resp["@odata.nextLink"] = graphHost + r.URL.Path + "?" + query.Encode()
+	}
+
+	r.w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(r.w).Encode(resp)
+}
+
+// selected trims v to the request's $select properties, keeping the id and
+// ETag as Graph does
+func (r *request) selected(v any) any {
+	fields := r.URL.Query().Get("$select")
+	if fields == "" || v == nil {
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var all map[string]json.RawMessage
+	if json.Unmarshal(data, &all) != nil {
+		return v
+	}
+
+	trimmed := map[string]json.RawMessage{}
+	for _, name := range append(strings.Split(fields, ","), "id", "@odata.etag", "eTag") {
+		if value, ok := all[strings.TrimSpace(name)]; ok {
+			trimmed[strings.TrimSpace(name)] = value
+		}
+	}
+	return trimmed
+}
+
+// decode reads the request body into v
+func (r *request) decode(v any) bool {
+	if err := json.Unmarshal(r.body, v); err != nil {
+		writeError(r.w, http.StatusBadRequest, "BadRequest", "Unable to read JSON request payload: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// matches checks the request's If-Match against etag, answering 412 if it
+// doesn't match
+func (r *request) matches(etag string) bool {
+	want := r.Header.Get("If-Match")
+	if want == "" || want == "*" || want == etag {
+		return true
+	}
+	writeError(r.w, http.StatusPreconditionFailed, "ErrorIrresolvableConflict",
+		"The send or update operation could not be performed because the change key passed in the request does not match the current change key for the item.")
+	return false
+}
+
+// patch applies a JSON merge of the request body onto v, a pointer to a
+// libgo365 struct
+func (r *request) patch(v any) bool {
+	current, err := json.Marshal(v)
+	if err != nil {
+		writeError(r.w, http.StatusInternalServerError, "InternalServerError", err.Error())
+		return false
+	}
+	var fields map[string]json.RawMessage
+	json.Unmarshal(current, &fields)
+	var changes map[string]json.RawMessage
+	if !r.decode(&changes) {
+		return false
+	}
+	for name, value := range changes {
+		fields[name] = value
+	}
+
+	merged, _ := json.Marshal(fields)
+	if err := json.NewDecoder(bytes.NewReader(merged)).Decode(v); err != nil {
+		writeError(r.w, http.StatusBadRequest, "BadRequest", "Invalid property value: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeError writes Graph's error envelope
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": message,
+			"innerError": map[string]string{
+				"request-id": w.Header().Get("request-id"),
+				"date":       time.Now().UTC().Format("2006-01-02T15:04:05"),
+			},
+		},
+	})
+}
+
+// notFound writes the 404 Graph gives for a missing item
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "ErrorItemNotFound", "The specified object was not found in the store.")
+}
@@ -0,0 +1,311 @@
+package graphtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/njt/go365/libgo365"
+)
+
+func TestMail(t *testing.T) {
+	srv := NewServer(t)
+	srv.PageSize = 2
+	client := srv.Client()
+	ctx := context.Background()
+
+	me, err := client.GetMyProfile(ctx)
+	if err != nil {
+		t.Fatalf("GetMyProfile failed: %v", err)
+	}
+	if me.Mail != Adele.Mail {
+		t.Errorf("Expected to be signed in as %s, got %+v", Adele.Mail, me)
+	}
+
+	added := srv.Me().AddMessage("inbox", &libgo365.Message{Subject: "Added by the test"})
+
+	// Paged with nextLinks, newest first
+	all, err := client.MessageIterator(&libgo365.ListMessagesOptions{FolderID: "inbox", Top: 2}).All(ctx)
+	if err != nil {
+		t.Fatalf("MessageIterator failed: %v", err)
+	}
+	if len(all) != 4 || all[0].Subject != "Added by the test" || all[3].Subject != "Welcome to Contoso" {
+		t.Errorf("Unexpected inbox %v", subjects(all))
+	}
+
+	resp, err := client.ListMessagesWithPagination(ctx, &libgo365.ListMessagesOptions{FolderID: "inbox", Top: 3, Select: []string{"subject"}})
+	if err != nil {
+		t.Fatalf("ListMessagesWithPagination failed: %v", err)
+	}
+	if !resp.HasMore || resp.NextPageToken != "3" || resp.Messages[0].From != nil || resp.Messages[0].ID == "" {
+		t.Errorf("Unexpected page %+v", resp)
+	}
+
+	// Updates need the current ETag when one is given
+	msg, _ := client.GetMessage(ctx, added.ID)
+	if _, err := client.UpdateMessage(ctx, added.ID, map[string]any{"isRead": true}, libgo365.WithIfMatch(msg.ETag)); err != nil {
+		t.Fatalf("UpdateMessage failed: %v", err)
+	}
+	_, err = client.UpdateMessage(ctx, added.ID, map[string]any{"isRead": false}, libgo365.WithIfMatch(msg.ETag))
+	if !errors.Is(err, libgo365.ErrPreconditionFailed) {
+		t.Errorf("Expected a stale ETag to fail, got %v", err)
+	}
+	if !srv.Me().Messages("inbox")[0].IsRead {
+		t.Error("Expected the message marked read")
+	}
+
+	if _, err := client.GetMessage(ctx, "missing"); !errors.Is(err, libgo365.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// MIME content is made up from the message
+	var eml bytes.Buffer
+	if _, err := client.DownloadMessageMIME(ctx, added.ID, &eml); err != nil {
+		t.Fatalf("DownloadMessageMIME failed: %v", err)
+	}
+	if !strings.Contains(eml.String(), "Subject: Added by the test\r\n") {
+		t.Errorf("Unexpected MIME content %q", eml.String())
+	}
+
+	if err := client.PermanentlyDeleteMessage(ctx, added.ID); err != nil {
+		t.Fatalf("PermanentlyDeleteMessage failed: %v", err)
+	}
+	if len(srv.Me().Messages("")) != 3 {
+		t.Errorf("Expected the message gone from every folder, got %v", subjects(srv.Me().Messages("")))
+	}
+}
+
+func TestSendMail(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+
+	err := client.SendMail(context.Background(), &libgo365.Message{
+		Subject:      "Launch",
+		Body:         &libgo365.ItemBody{ContentType: "text", Content: "We're live."},
+		ToRecipients: []*libgo365.Recipient{{EmailAddress: &libgo365.EmailAddress{Address: Alex.Mail}}},
+	}, true)
+	if err != nil {
+		t.Fatalf("SendMail failed: %v", err)
+	}
+
+	sent := srv.Me().Messages("sentitems")
+	if len(sent) != 1 || sent[0].Subject != "Launch" {
+		t.Errorf("Expected the message in Sent Items, got %v", subjects(sent))
+	}
+	received := srv.Account(Alex.UserPrincipalName).Messages("inbox")
+	if len(received) != 1 || received[0].IsRead || received[0].From.EmailAddress.Address != Adele.Mail {
+		t.Errorf("Expected the message delivered to Alex, got %+v", received)
+	}
+
+	requests := srv.Requests()
+	last := requests[len(requests)-1]
+	if last.Method != "POST" || last.Path != "/me/sendMail" || !strings.Contains(string(last.Body), `"Launch"`) {
+		t.Errorf("Unexpected recorded request %s %s %s", last.Method, last.Path, last.Body)
+	}
+}
+
+func TestReply(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	// Q1 marketing plan, from Alex and copied to Megan
+	original := srv.Me().Messages("inbox")[1]
+	draft, err := client.CreateReply(ctx, original.ID, true)
+	if err != nil {
+		t.Fatalf("CreateReply failed: %v", err)
+	}
+	if draft.Subject != "RE: Q1 marketing plan" || draft.ConversationID != original.ConversationID ||
+		len(draft.ToRecipients) != 1 || len(draft.CcRecipients) != 1 ||
+		!strings.Contains(draft.Body.Content, "Draft plan attached for review.") {
+		t.Errorf("Unexpected draft %+v", draft)
+	}
+
+	body := &libgo365.ItemBody{ContentType: "text", Content: "Looks good."}
+	if _, err := client.UpdateMessage(ctx, draft.ID, &libgo365.Message{Body: body}); err != nil {
+		t.Fatalf("UpdateMessage failed: %v", err)
+	}
+	if err := client.SendDraft(ctx, draft.ID); err != nil {
+		t.Fatalf("SendDraft failed: %v", err)
+	}
+
+	for _, user := range []libgo365.User{Alex, Megan} {
+		received := srv.Account(user.Mail).Messages("inbox")
+		if len(received) != 1 || received[0].ConversationID != original.ConversationID || received[0].Body.Content != "Looks good." {
+			t.Errorf("Expected the reply delivered to %s in the same conversation, got %+v", user.DisplayName, received)
+		}
+	}
+	if sent := srv.Me().Messages("sentitems"); len(sent) != 1 || sent[0].ID != draft.ID {
+		t.Errorf("Expected the draft moved to Sent Items, got %v", subjects(sent))
+	}
+}
+
+func TestForward(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+
+	original := srv.Me().Messages("inbox")[0]
+	to := []*libgo365.Recipient{
+		{EmailAddress: &libgo365.EmailAddress{Address: Alex.Mail}},
+		{EmailAddress: &libgo365.EmailAddress{Address: Megan.Mail}},
+	}
+	if err := client.ForwardMessage(context.Background(), original.ID, "FYI", to); err != nil {
+		t.Fatalf("ForwardMessage failed: %v", err)
+	}
+
+	for _, user := range []libgo365.User{Alex, Megan} {
+		received := srv.Account(user.Mail).Messages("inbox")
+		if len(received) != 1 || received[0].Subject != "FW: Lunch on Tuesday?" ||
+			!strings.Contains(received[0].Body.Content, "FYI") || !strings.Contains(received[0].Body.Content, "free for lunch") {
+			t.Errorf("Expected the forward delivered to %s, got %+v", user.DisplayName, received)
+		}
+	}
+}
+
+func TestDrafts(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	draft, err := client.CreateDraft(ctx, &libgo365.Message{
+		Subject:      "Budget",
+		ToRecipients: []*libgo365.Recipient{{EmailAddress: &libgo365.EmailAddress{Address: Alex.Mail}}},
+	})
+	if err != nil {
+		t.Fatalf("CreateDraft failed: %v", err)
+	}
+	for _, name := range []string{"budget.csv", "old.csv"} {
+		file := &libgo365.AttachmentFile{Name: name, ContentType: "text/csv", Size: 7, Content: strings.NewReader("Q1,100\n")}
+		if err := client.AddAttachment(ctx, draft.ID, file); err != nil {
+			t.Fatalf("AddAttachment failed: %v", err)
+		}
+	}
+	attachments, err := client.ListAttachments(ctx, draft.ID)
+	if err != nil || len(attachments) != 2 {
+		t.Fatalf("Expected two attachments, got %v, %v", attachments, err)
+	}
+	if err := client.DeleteAttachment(ctx, draft.ID, attachments[1].ID); err != nil {
+		t.Fatalf("DeleteAttachment failed: %v", err)
+	}
+	body := &libgo365.ItemBody{ContentType: "text", Content: "Figures attached."}
+	if _, err := client.UpdateDraft(ctx, draft.ID, &libgo365.Message{Subject: "Q1 budget", Body: body}); err != nil {
+		t.Fatalf("UpdateDraft failed: %v", err)
+	}
+	if err := client.SendDraft(ctx, draft.ID); err != nil {
+		t.Fatalf("SendDraft failed: %v", err)
+	}
+
+	received := srv.Account(Alex.Mail).Messages("inbox")
+	if len(received) != 1 || received[0].Subject != "Q1 budget" || !received[0].HasAttachments {
+		t.Fatalf("Expected the draft delivered to Alex, got %+v", received)
+	}
+	delivered := srv.Account(Alex.Mail).Attachments(received[0].ID)
+	if len(delivered) != 1 || delivered[0].Name != "budget.csv" || string(delivered[0].ContentBytes) != "Q1,100\n" {
+		t.Errorf("Expected budget.csv delivered, got %+v", delivered)
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	created, err := client.CreateEvent(ctx, &libgo365.Event{
+		Subject: "Planning",
+		Start:   &libgo365.DateTimeTimeZone{DateTime: "2026-01-21T10:00:00", TimeZone: "Pacific/Auckland"},
+		End:     &libgo365.DateTimeTimeZone{DateTime: "2026-01-21T11:00:00", TimeZone: "Pacific/Auckland"},
+	}, "")
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+
+	// 10am in Auckland is 9pm UTC the day before, after the seeded meeting
+	resp, err := client.CalendarView(ctx, &libgo365.CalendarViewOptions{
+		StartDateTime: "2026-01-20T00:00:00Z",
+		EndDateTime:   "2026-01-21T00:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf("CalendarView failed: %v", err)
+	}
+	var got []string
+	for _, event := range resp.Events {
+		got = append(got, event.Subject)
+	}
+	if strings.Join(got, ",") != "Marketing sync,Planning,Focus time" {
+		t.Errorf("Unexpected calendar view %v", got)
+	}
+
+	if err := client.RespondToEvent(ctx, created.ID, "accept", ""); err != nil {
+		t.Fatalf("RespondToEvent failed: %v", err)
+	}
+	if status := srv.Me().Events(DefaultCalendarID)[2].ResponseStatus; status == nil || status.Response != libgo365.ResponseAccepted {
+		t.Errorf("Expected the event accepted, got %+v", status)
+	}
+}
+
+func TestDrive(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	items, err := client.ListItems(ctx, "/Documents", nil)
+	if err != nil {
+		t.Fatalf("ListItems failed: %v", err)
+	}
+	if items.Count != 2 || items.Items[0].Name != "Welcome.txt" || items.Items[0].File == nil {
+		t.Errorf("Unexpected items %+v", items.Items)
+	}
+
+	var buf bytes.Buffer
+	if err := client.DownloadItem(ctx, "/Documents/Welcome.txt", &buf, nil); err != nil {
+		t.Fatalf("DownloadItem failed: %v", err)
+	}
+	if buf.String() != "Welcome to OneDrive.\n" {
+		t.Errorf("Unexpected content %q", buf.String())
+	}
+
+	_, err = client.PutContent(ctx, "/me/drive/root:/Reports/2026/q1.txt:/content", "text/plain", strings.NewReader("up"))
+	if err != nil {
+		t.Fatalf("PutContent failed: %v", err)
+	}
+	if content, ok := srv.Me().File("/reports/2026/Q1.txt"); !ok || string(content) != "up" {
+		t.Errorf("Expected the uploaded file, got %q", content)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+	client.SetMaxRetries(0)
+
+	srv.Throttle(1, 1500*time.Millisecond)
+	_, err := client.GetMyProfile(context.Background())
+	var gerr *libgo365.GraphError
+	if !errors.As(err, &gerr) || !errors.Is(err, libgo365.ErrThrottled) || gerr.RetryAfter != 2*time.Second {
+		t.Fatalf("Expected a throttled error with Retry-After, got %v", err)
+	}
+
+	if _, err := client.GetMyProfile(context.Background()); err != nil {
+		t.Errorf("Expected the next request to succeed, got %v", err)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	srv := NewServer(t)
+	_, err := srv.Client().Get(context.Background(), "/me/onenote/notebooks")
+	var gerr *libgo365.GraphError
+	if !errors.As(err, &gerr) || gerr.StatusCode != 400 || !strings.Contains(gerr.Message, "/me/onenote/notebooks") {
+		t.Errorf("Expected a 400 naming the request, got %v", err)
+	}
+}
+
+func subjects(messages []*libgo365.Message) []string {
+	var s []string
+	for _, msg := range messages {
+		s = append(s, msg.Subject)
+	}
+	return s
+}
@@ -0,0 +1,430 @@
+package graphtest
+
+import (
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/njt/go365/libgo365"
+)
+
+// serveMail answers messages, mailFolders and sendMail requests
+func (a *Account) serveMail(r *request, segments []string) bool {
+	switch {
+	case segments[0] == "sendMail" && len(segments) == 1 && r.Method == http.MethodPost:
+		a.sendMail(r)
+		return true
+
+	case segments[0] == "mailFolders" && len(segments) == 1 && r.Method == http.MethodGet:
+		folders := make([]any, len(a.folders))
+		for i, f := range a.folders {
+			f.UnreadItemCount, f.TotalItemCount = 0, 0
+			for _, msg := range a.folderMessages(f.ID) {
+				f.TotalItemCount++
+				if !msg.IsRead {
+					f.UnreadItemCount++
+				}
+			}
+			folders[i] = f
+		}
+		r.writeCollection(folders)
+		return true
+
+	case segments[0] == "mailFolders" && len(segments) == 2 && r.Method == http.MethodGet:
+		folder := a.folder(segments[1])
+		if folder == nil {
+			notFound(r.w)
+			return true
+		}
+		r.writeJSON(http.StatusOK, folder)
+		return true
+
+	case segments[0] == "mailFolders" && len(segments) == 3 && segments[2] == "messages" && r.Method == http.MethodGet:
+		folder := a.folder(segments[1])
+		if folder == nil {
+			notFound(r.w)
+			return true
+		}
+		r.writeCollection(toAny(a.folderMessages(folder.ID)))
+		return true
+
+	case segments[0] == "messages" && len(segments) == 1 && r.Method == http.MethodGet:
+		r.writeCollection(toAny(a.messages))
+		return true
+
+	case segments[0] == "messages" && len(segments) == 1 && r.Method == http.MethodPost:
+		// Creates a draft
+		var msg libgo365.Message
+		if !r.decode(&msg) {
+			return true
+		}
+		msg.ID, msg.IsDraft, msg.IsRead = "", true, true
+		r.writeJSON(http.StatusCreated, a.addMessage("drafts", &msg))
+		return true
+
+	case segments[0] == "messages" && len(segments) >= 2:
+		return a.serveMessage(r, segments[1], segments[2:])
+	}
+	return false
+}
+
+// serveMessage answers requests for one message and its actions
+func (a *Account) serveMessage(r *request, id string, action []string) bool {
+	i := slices.IndexFunc(a.messages, func(m *libgo365.Message) bool { return m.ID == id })
+	if i < 0 {
+		notFound(r.w)
+		return true
+	}
+	msg := a.messages[i]
+
+	switch {
+	case len(action) == 0 && r.Method == http.MethodGet:
+		r.writeJSON(http.StatusOK, msg)
+	case len(action) == 0 && r.Method == http.MethodPatch:
+		if !r.matches(msg.ETag) || !r.patch(msg) {
+			return true
+		}
+		msg.ID = id
+		msg.ETag = a.s.newETag()
+		r.writeJSON(http.StatusOK, msg)
+	case len(action) == 0 && r.Method == http.MethodDelete:
+		if !r.matches(msg.ETag) {
+			return true
+		}
+		if a.messageFolder[id] == "deleteditems" {
+			a.messages = slices.Delete(a.messages, i, i+1)
+			delete(a.messageFolder, id)
+		} else {
+			a.messageFolder[id] = "deleteditems"
+		}
+		r.w.WriteHeader(http.StatusNoContent)
+	case len(action) == 1 && (action[0] == "move" || action[0] == "copy") && r.Method == http.MethodPost:
+		var req struct {
+			DestinationID string `json:"destinationId"`
+		}
+		if !r.decode(&req) {
+			return true
+		}
+		folder := a.folder(req.DestinationID)
+		if folder == nil {
+			notFound(r.w)
+			return true
+		}
+		if action[0] == "copy" {
+			copied := *msg
+			copied.ID = ""
+			r.writeJSON(http.StatusCreated, a.addMessage(folder.ID, &copied))
+			return true
+		}
+		a.messageFolder[id] = folder.ID
+		msg.ETag = a.s.newETag()
+		r.writeJSON(http.StatusCreated, msg)
+	case len(action) == 1 && action[0] == "permanentDelete" && r.Method == http.MethodPost:
+		a.messages = slices.Delete(a.messages, i, i+1)
+		delete(a.messageFolder, id)
+		delete(a.attachments, id)
+		r.w.WriteHeader(http.StatusNoContent)
+	case len(action) == 1 && action[0] == "$value" && r.Method == http.MethodGet:
+		r.w.Header().Set("Content-Type", "text/plain")
+		r.w.Write(mimeContent(msg))
+	case len(action) >= 1 && action[0] == "attachments":
+		return a.serveAttachments(r, msg, action[1:])
+	case len(action) == 1 && (action[0] == "createReply" || action[0] == "createReplyAll") && r.Method == http.MethodPost:
+		r.writeJSON(http.StatusCreated, a.addMessage("drafts", a.reply(msg, action[0] == "createReplyAll")))
+	case len(action) == 1 && (action[0] == "forward" || action[0] == "createForward") && r.Method == http.MethodPost:
+		var req struct {
+			Comment      string                `json:"comment"`
+			ToRecipients []*libgo365.Recipient `json:"toRecipients"`
+		}
+		if !r.decode(&req) {
+			return true
+		}
+		draft := a.forward(msg, req.Comment, req.ToRecipients)
+		draft.Attachments = cloneAttachments(a.attachments[msg.ID])
+		if action[0] == "createForward" {
+			r.writeJSON(http.StatusCreated, a.addMessage("drafts", draft))
+			return true
+		}
+		if len(req.ToRecipients) == 0 {
+			writeError(r.w, http.StatusBadRequest, "ErrorInvalidRecipients", "At least one recipient isn't valid.")
+			return true
+		}
+		draft.IsDraft = false
+		a.addMessage("sentitems", draft)
+		a.deliver(draft)
+		r.w.WriteHeader(http.StatusAccepted)
+	case len(action) == 1 && action[0] == "send" && r.Method == http.MethodPost && msg.IsDraft:
+		msg.IsDraft = false
+		a.deliver(msg)
+		r.w.WriteHeader(http.StatusAccepted)
+	default:
+		return false
+	}
+	return true
+}
+
+// sendMail answers /sendMail, filing the message in Sent Items unless the
+// request said not to
+func (a *Account) sendMail(r *request) {
+	var req struct {
+		Message         *libgo365.Message `json:"message"`
+		SaveToSentItems *bool             `json:"saveToSentItems"`
+	}
+	if !r.decode(&req) {
+		return
+	}
+	if req.Message == nil || len(req.Message.ToRecipients)+len(req.Message.CcRecipients)+len(req.Message.BccRecipients) == 0 {
+		writeError(r.w, http.StatusBadRequest, "ErrorInvalidRecipients", "At least one recipient isn't valid.")
+		return
+	}
+
+	msg := req.Message
+	msg.ID = ""
+	if req.SaveToSentItems == nil || *req.SaveToSentItems {
+		a.addMessage("sentitems", msg)
+	}
+	a.deliver(msg)
+	r.w.WriteHeader(http.StatusAccepted)
+}
+
+// deliver marks msg sent and puts a copy in the inbox of each recipient
+// in the tenant
+func (a *Account) deliver(msg *libgo365.Message) {
+	now := time.Now().UTC().Truncate(time.Second)
+	msg.SentDateTime = &now
+	msg.IsRead = true
+	msg.From = &libgo365.Recipient{EmailAddress: &libgo365.EmailAddress{Name: a.User.DisplayName, Address: a.User.Mail}}
+	if a.messageFolder[msg.ID] == "drafts" {
+		a.messageFolder[msg.ID] = "sentitems"
+	}
+
+	// A message sent without saving it to Sent Items still carries its own
+	attachments := msg.Attachments
+	if msg.ID != "" {
+		attachments = a.attachments[msg.ID]
+	}
+
+	recipients := slices.Concat(msg.ToRecipients, msg.CcRecipients, msg.BccRecipients)
+	for _, to := range recipients {
+		if to.EmailAddress == nil {
+			continue
+		}
+		if recipient := a.s.account(to.EmailAddress.Address); recipient != nil {
+			received := *msg
+			received.ID, received.IsRead, received.ReceivedDateTime = "", false, &now
+			received.Attachments = cloneAttachments(attachments)
+			recipient.addMessage("inbox", &received)
+		}
+	}
+}
+
+// serveAttachments answers requests for a message's attachments; rest is
+// the path after /attachments
+func (a *Account) serveAttachments(r *request, msg *libgo365.Message, rest []string) bool {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			r.writeCollection(toAny(a.attachments[msg.ID]))
+		case http.MethodPost:
+			var attachment libgo365.Attachment
+			if !r.decode(&attachment) {
+				return true
+			}
+			if !msg.IsDraft {
+				writeError(r.w, http.StatusForbidden, "ErrorAccessDenied", "Attachments can only be added to drafts.")
+				return true
+			}
+			r.writeJSON(http.StatusCreated, a.addAttachment(msg, &attachment))
+		default:
+			return false
+		}
+		return true
+	}
+
+	attachments := a.attachments[msg.ID]
+	i := slices.IndexFunc(attachments, func(att *libgo365.Attachment) bool { return att.ID == rest[0] })
+	if i < 0 {
+		notFound(r.w)
+		return true
+	}
+	switch {
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		r.writeJSON(http.StatusOK, attachments[i])
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		a.attachments[msg.ID] = slices.Delete(attachments, i, i+1)
+		msg.HasAttachments = len(a.attachments[msg.ID]) > 0
+		msg.ETag = a.s.newETag()
+		r.w.WriteHeader(http.StatusNoContent)
+	case len(rest) == 2 && rest[1] == "$value" && r.Method == http.MethodGet:
+		r.w.Header().Set("Content-Type", attachments[i].ContentType)
+		r.w.Write(attachments[i].ContentBytes)
+	default:
+		return false
+	}
+	return true
+}
+
+// addAttachment files attachment with msg, as a file attachment unless it
+// says otherwise
+func (a *Account) addAttachment(msg *libgo365.Message, attachment *libgo365.Attachment) *libgo365.Attachment {
+	now := time.Now().UTC().Truncate(time.Second)
+	attachment.ID = a.s.newID("attachment")
+	attachment.LastModifiedDateTime = &now
+	if attachment.ODataType == "" {
+		attachment.ODataType = libgo365.FileAttachmentType
+	}
+	if attachment.Size == 0 {
+		attachment.Size = int64(len(attachment.ContentBytes))
+	}
+	a.attachments[msg.ID] = append(a.attachments[msg.ID], attachment)
+	msg.HasAttachments = true
+	msg.ETag = a.s.newETag()
+	return attachment
+}
+
+// cloneAttachments copies attachments for another message, which gives
+// them new IDs
+func cloneAttachments(attachments []*libgo365.Attachment) []*libgo365.Attachment {
+	var clones []*libgo365.Attachment
+	for _, attachment := range attachments {
+		clone := *attachment
+		clone.ID = ""
+		clones = append(clones, &clone)
+	}
+	return clones
+}
+
+// reply drafts a reply to msg in the same conversation, addressed to its
+// sender and, with all, its other recipients, and quoting it as Outlook does
+func (a *Account) reply(msg *libgo365.Message, all bool) *libgo365.Message {
+	notMe := func(r *libgo365.Recipient) bool {
+		return r.EmailAddress == nil || strings.EqualFold(r.EmailAddress.Address, a.User.Mail)
+	}
+	draft := &libgo365.Message{
+		Subject:        msg.Subject,
+		ConversationID: msg.ConversationID,
+		IsDraft:        true,
+		IsRead:         true,
+	}
+	if !strings.HasPrefix(strings.ToUpper(draft.Subject), "RE:") {
+		draft.Subject = "RE: " + draft.Subject
+	}
+	if msg.From != nil {
+		draft.ToRecipients = []*libgo365.Recipient{msg.From}
+	}
+	if all {
+		draft.ToRecipients = slices.DeleteFunc(slices.Concat(draft.ToRecipients, msg.ToRecipients), notMe)
+		draft.CcRecipients = slices.DeleteFunc(slices.Clone(msg.CcRecipients), notMe)
+	}
+	draft.Body = quote(msg, "")
+	return draft
+}
+
+// forward drafts a forward of msg to the given recipients, with comment
+// above the quoted original
+func (a *Account) forward(msg *libgo365.Message, comment string, to []*libgo365.Recipient) *libgo365.Message {
+	draft := &libgo365.Message{
+		Subject:        msg.Subject,
+		ConversationID: msg.ConversationID,
+		ToRecipients:   to,
+		HasAttachments: msg.HasAttachments,
+		Body:           quote(msg, comment),
+		IsDraft:        true,
+		IsRead:         true,
+	}
+	if !strings.HasPrefix(strings.ToUpper(draft.Subject), "FW:") {
+		draft.Subject = "FW: " + draft.Subject
+	}
+	return draft
+}
+
+// quote returns an HTML body with comment above msg, quoted as Outlook
+// quotes replies and forwards
+func quote(msg *libgo365.Message, comment string) *libgo365.ItemBody {
+	var quoted strings.Builder
+	quoted.WriteString("<html><body>")
+	if comment != "" {
+		fmt.Fprintf(&quoted, "<div>%s</div>", html.EscapeString(comment))
+	}
+	quoted.WriteString(`<hr><div id="divRplyFwdMsg">`)
+	if msg.From != nil && msg.From.EmailAddress != nil {
+		fmt.Fprintf(&quoted, "<b>From:</b> %s<br>", html.EscapeString(msg.From.EmailAddress.Name))
+	}
+	fmt.Fprintf(&quoted, "<b>Subject:</b> %s</div>", html.EscapeString(msg.Subject))
+	if msg.Body != nil {
+		if strings.EqualFold(msg.Body.ContentType, "html") {
+			quoted.WriteString(msg.Body.Content)
+		} else {
+			fmt.Fprintf(&quoted, "<div>%s</div>", html.EscapeString(msg.Body.Content))
+		}
+	}
+	quoted.WriteString("</body></html>")
+	return &libgo365.ItemBody{ContentType: "html", Content: quoted.String()}
+}
+
+// mimeContent renders msg as a single-part MIME message, without its
+// attachments, in place of the content Exchange would have kept
+func mimeContent(msg *libgo365.Message) []byte {
+	addresses := func(recipients []*libgo365.Recipient) string {
+		var list []string
+		for _, r := range recipients {
+			if r != nil && r.EmailAddress != nil {
+				list = append(list, (&mail.Address{Name: r.EmailAddress.Name, Address: r.EmailAddress.Address}).String())
+			}
+		}
+		return strings.Join(list, ", ")
+	}
+
+	var b strings.Builder
+	if msg.From != nil {
+		fmt.Fprintf(&b, "From: %s\r\n", addresses([]*libgo365.Recipient{msg.From}))
+	}
+	if to := addresses(msg.ToRecipients); to != "" {
+		fmt.Fprintf(&b, "To: %s\r\n", to)
+	}
+	if cc := addresses(msg.CcRecipients); cc != "" {
+		fmt.Fprintf(&b, "Cc: %s\r\n", cc)
+	}
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	if msg.ReceivedDateTime != nil {
+		fmt.Fprintf(&b, "Date: %s\r\n", msg.ReceivedDateTime.Format(time.RFC1123Z))
+	}
+	if msg.InternetMessageID != "" {
+		fmt.Fprintf(&b, "Message-ID: %s\r\n", msg.InternetMessageID)
+	}
+	contentType, content := "text/plain", ""
+	if msg.Body != nil {
+		content = msg.Body.Content
+		if strings.EqualFold(msg.Body.ContentType, "html") {
+			contentType = "text/html"
+		}
+	}
+	fmt.Fprintf(&b, "MIME-Version: 1.0\r\nContent-Type: %s; charset=utf-8\r\n\r\n", contentType)
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// folder returns the mail folder with the given ID or well-known name
+func (a *Account) folder(id string) *libgo365.MailFolder {
+	for _, f := range a.folders {
+		if f.ID == id {
+			return f
+		}
+	}
+	return nil
+}
+
+// toAny converts items for writeCollection
+func toAny[T any](items []*T) []any {
+	values := make([]any, len(items))
+	for i, item := range items {
+		values[i] = item
+	}
+	return values
+}
@@ -0,0 +1,103 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Group represents a Microsoft 365 group, security group, or distribution list
+type Group struct {
+	ID              string   `json:"id,omitempty"`
+	DisplayName     string   `json:"displayName,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	Mail            string   `json:"mail,omitempty"`
+	MailEnabled     bool     `json:"mailEnabled"`
+	SecurityEnabled bool     `json:"securityEnabled"`
+	GroupTypes      []string `json:"groupTypes,omitempty"` // "Unified" for Microsoft 365 groups
+}
+
+// IsDistributionList reports whether the group is a classic distribution list
+// (mail-enabled, not a security group, not a Microsoft 365 group)
+func (g *Group) IsDistributionList() bool {
+	if !g.MailEnabled || g.SecurityEnabled {
+		return false
+	}
+	for _, t := range g.GroupTypes {
+		if strings.EqualFold(t, "Unified") {
+			return false
+		}
+	}
+	return true
+}
+
+// GroupList represents a list of groups returned by Graph API
+type GroupList struct {
+	Value []*Group `json:"value"`
+}
+
+// FindGroup looks up a group by email address or object ID
+func (c *Client) FindGroup(ctx context.Context, mailOrID string) (*Group, error) {
+	if mailOrID == "" {
+		return nil, fmt.Errorf("group email or ID is required")
+	}
+
+	if !strings.Contains(mailOrID, "@") {
+		data, err := c.Get(ctx, fmt.Sprintf("/groups/%s", mailOrID))
+		if err != nil {
+			return nil, err
+		}
+
+		var group Group
+		if err := json.Unmarshal(data, &group); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal group: %w", err)
+		}
+		return &group, nil
+	}
+
+	params := url.Values{}
+	params.Set("$filter", fmt.Sprintf("mail eq '%s'", escapeODataString(mailOrID)))
+
+	data, err := c.Get(ctx, "/groups?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var groupList GroupList
+	if err := json.Unmarshal(data, &groupList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal groups: %w", err)
+	}
+
+	if len(groupList.Value) == 0 {
+		return nil, fmt.Errorf("group %q not found", mailOrID)
+	}
+
+	return groupList.Value[0], nil
+}
+
+// ListGroupTransitiveMembers retrieves all members of a group, including members of
+// nested groups. Nested groups themselves are included in the result; all pages are fetched.
+func (c *Client) ListGroupTransitiveMembers(ctx context.Context, groupID string) ([]*DirectoryObject, error) {
+	if groupID == "" {
+		return nil, fmt.Errorf("group ID is required")
+	}
+
+	params := url.Values{}
+	params.Set("$select", "id,displayName,mail,userPrincipalName")
+
+	var members []*DirectoryObject
+	err := c.GetPages(ctx, fmt.Sprintf("/groups/%s/transitiveMembers?%s", groupID, params.Encode()), func(data []byte) error {
+		var memberList DirectoryObjectList
+		if err := json.Unmarshal(data, &memberList); err != nil {
+			return fmt.Errorf("failed to unmarshal group members: %w", err)
+		}
+		members = append(members, memberList.Value...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,84 @@
+package libgo365
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFindGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/groups":
+			if filter := r.URL.Query().Get("$filter"); filter != "mail eq 'dl-engineering@example.com'" {
+				t.Errorf("Unexpected filter: %s", filter)
+			}
+			w.Write([]byte(`{"value": [{"id": "g1", "displayName": "Engineering", "mail": "dl-engineering@example.com", "mailEnabled": true, "securityEnabled": false, "groupTypes": []}]}`))
+		case "/groups/g2":
+			w.Write([]byte(`{"id": "g2", "displayName": "Project X", "mailEnabled": true, "groupTypes": ["Unified"]}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	group, err := client.FindGroup(context.Background(), "dl-engineering@example.com")
+	if err != nil {
+		t.Fatalf("FindGroup failed: %v", err)
+	}
+	if group.ID != "g1" || !group.IsDistributionList() {
+		t.Errorf("Expected distribution list g1, got %+v", group)
+	}
+
+	group, err = client.FindGroup(context.Background(), "g2")
+	if err != nil {
+		t.Fatalf("FindGroup by ID failed: %v", err)
+	}
+	if group.IsDistributionList() {
+		t.Error("Expected Microsoft 365 group not to be a distribution list")
+	}
+}
+
+func TestListGroupTransitiveMembers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/groups/g1/transitiveMembers" {
+			t.Errorf("Expected path /groups/g1/transitiveMembers, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("$skiptoken") == "" {
+			w.Write([]byte(`{
+				"value": [
+					{"@odata.type": "#microsoft.graph.user", "id": "u1", "displayName": "Jane", "mail": "jane@example.com"},
+					{"@odata.type": "#microsoft.graph.group", "id": "g2", "displayName": "Nested"}
+				],
+				"@odata.nextLink": "https://graph.microsoft.com/v1.0/groups/g1/transitiveMembers?$skiptoken=page2"
+			}`))
+			return
+		}
+		w.Write([]byte(`{"value": [{"@odata.type": "#microsoft.graph.orgContact", "id": "c1", "displayName": "Partner", "mail": "partner@other.com"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	members, err := client.ListGroupTransitiveMembers(context.Background(), "g1")
+	if err != nil {
+		t.Fatalf("ListGroupTransitiveMembers failed: %v", err)
+	}
+	if len(members) != 3 {
+		t.Fatalf("Expected 3 members across pages, got %d", len(members))
+	}
+	if members[2].Kind() != "orgContact" {
+		t.Errorf("Expected orgContact, got %s", members[2].Kind())
+	}
+}
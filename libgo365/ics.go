@@ -0,0 +1,144 @@
+package libgo365
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// graphDateTime is the layout of DateTimeTimeZone.DateTime
+const graphDateTime = "2006-01-02T15:04:05.9999999"
+
+// WriteICS writes events as an iCalendar (RFC 5545) file that other
+// calendar apps can import. Times are written in UTC. HTML bodies go in
+// X-ALT-DESC, as Outlook does.
+func WriteICS(w io.Writer, events ...*Event) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeICSLine(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//go365//EN")
+	for _, e := range events {
+		line("BEGIN", "VEVENT")
+
+		uid := e.ICalUID
+		if uid == "" {
+			uid = e.ID
+		}
+		line("UID", escapeICSText(uid))
+		line("DTSTAMP", time.Now().UTC().Format("20060102T150405Z"))
+
+		for _, t := range []struct {
+			name string
+			dt   *DateTimeTimeZone
+		}{{"DTSTART", e.Start}, {"DTEND", e.End}} {
+			if value, ok := icsTime(t.dt, e.IsAllDay); ok {
+				if e.IsAllDay {
+					line(t.name+";VALUE=DATE", value)
+				} else {
+					line(t.name, value)
+				}
+			}
+		}
+
+		if e.Subject != "" {
+			line("SUMMARY", escapeICSText(e.Subject))
+		}
+		if e.Location != nil && e.Location.DisplayName != "" {
+			line("LOCATION", escapeICSText(e.Location.DisplayName))
+		}
+		if e.Body != nil && e.Body.Content != "" {
+			if strings.EqualFold(e.Body.ContentType, string(BodyHTML)) {
+				line("X-ALT-DESC;FMTTYPE=text/html", escapeICSText(e.Body.Content))
+			} else {
+				line("DESCRIPTION", escapeICSText(e.Body.Content))
+			}
+		}
+		if e.Organizer != nil && e.Organizer.EmailAddress != nil {
+			line("ORGANIZER;CN="+icsParam(e.Organizer.EmailAddress.Name), "mailto:"+e.Organizer.EmailAddress.Address)
+		}
+		for _, a := range e.Attendees {
+			if a.EmailAddress == nil {
+				continue
+			}
+			role := "REQ-PARTICIPANT"
+			if a.Type == AttendeeOptional {
+				role = "OPT-PARTICIPANT"
+			}
+			line("ATTENDEE;ROLE="+role+";CN="+icsParam(a.EmailAddress.Name), "mailto:"+a.EmailAddress.Address)
+		}
+		if e.IsCancelled {
+			line("STATUS", "CANCELLED")
+		}
+		if e.ShowAs == FreeBusyFree {
+			line("TRANSP", "TRANSPARENT")
+		}
+		switch e.Sensitivity {
+		case SensitivityPrivate, SensitivityPersonal:
+			line("CLASS", "PRIVATE")
+		case SensitivityConfidential:
+			line("CLASS", "CONFIDENTIAL")
+		}
+
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+
+	return bw.Flush()
+}
+
+// icsTime formats a Graph date/time as an iCalendar UTC time, or a date for
+// all-day events, which have no time zone to convert
+func icsTime(dt *DateTimeTimeZone, allDay bool) (string, bool) {
+	if dt == nil || len(dt.DateTime) < 10 {
+		return "", false
+	}
+	if allDay {
+		return strings.ReplaceAll(dt.DateTime[:10], "-", ""), true
+	}
+
+	loc := time.UTC
+	if dt.TimeZone != "" {
+		var err error
+		if loc, err = LoadLocation(dt.TimeZone); err != nil {
+			return "", false
+		}
+	}
+	t, err := time.ParseInLocation(graphDateTime, dt.DateTime, loc)
+	if err != nil {
+		return "", false
+	}
+	return t.UTC().Format("20060102T150405Z"), true
+}
+
+// escapeICSText escapes a TEXT value
+func escapeICSText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// icsParam quotes a parameter value, which can't contain quotes itself
+func icsParam(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+}
+
+// writeICSLine writes a content line, folded to 75 octets with CRLF endings
+// as RFC 5545 requires. Lines are only split between UTF-8 characters.
+func writeICSLine(w *bufio.Writer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		fmt.Fprintf(w, "%s\r\n ", s[:cut])
+		s = s[cut:]
+		// The leading space of a continuation line counts towards its length
+		limit = 74
+	}
+	fmt.Fprintf(w, "%s\r\n", s)
+}
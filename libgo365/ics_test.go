@@ -0,0 +1,93 @@
+package libgo365
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteICS(t *testing.T) {
+	event := &Event{
+		ID:          "AAMkEvent",
+		ICalUID:     "040000008200E00074C5B7101A82E008",
+		Subject:     "Planning, Q3; budget",
+		Start:       &DateTimeTimeZone{DateTime: "2025-06-02T09:00:00.0000000", TimeZone: "Pacific/Auckland"},
+		End:         &DateTimeTimeZone{DateTime: "2025-06-02T10:00:00.0000000", TimeZone: "UTC"},
+		Location:    &Location{DisplayName: "Room 1"},
+		Body:        &ItemBody{ContentType: "text", Content: "Line one\nLine two"},
+		Organizer:   &Recipient{EmailAddress: &EmailAddress{Name: "Alex", Address: "alex@example.com"}},
+		Attendees:   []*Attendee{{EmailAddress: &EmailAddress{Name: "Sam", Address: "sam@example.com"}, Type: AttendeeOptional}},
+		Sensitivity: SensitivityPrivate,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, event); err != nil {
+		t.Fatalf("WriteICS failed: %v", err)
+	}
+	ics := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:040000008200E00074C5B7101A82E008\r\n",
+		"DTSTART:20250601T210000Z\r\n",
+		"DTEND:20250602T100000Z\r\n",
+		`SUMMARY:Planning\, Q3\; budget` + "\r\n",
+		`DESCRIPTION:Line one\nLine two` + "\r\n",
+		`ORGANIZER;CN="Alex":mailto:alex@example.com` + "\r\n",
+		`ATTENDEE;ROLE=OPT-PARTICIPANT;CN="Sam":mailto:sam@example.com` + "\r\n",
+		"CLASS:PRIVATE\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected %q in:\n%s", want, ics)
+		}
+	}
+}
+
+func TestWriteICSAllDay(t *testing.T) {
+	event := &Event{
+		ID:       "AAMkHoliday",
+		Subject:  "Holiday",
+		IsAllDay: true,
+		Start:    &DateTimeTimeZone{DateTime: "2025-12-25T00:00:00.0000000", TimeZone: "UTC"},
+		End:      &DateTimeTimeZone{DateTime: "2025-12-26T00:00:00.0000000", TimeZone: "UTC"},
+		ShowAs:   FreeBusyFree,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, event); err != nil {
+		t.Fatalf("WriteICS failed: %v", err)
+	}
+	for _, want := range []string{"DTSTART;VALUE=DATE:20251225\r\n", "DTEND;VALUE=DATE:20251226\r\n", "TRANSP:TRANSPARENT\r\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteICSLineFolding(t *testing.T) {
+	event := &Event{ID: "AAMk", Body: &ItemBody{ContentType: "html", Content: strings.Repeat("<p>é</p>", 40)}}
+
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, event); err != nil {
+		t.Fatalf("WriteICS failed: %v", err)
+	}
+
+	folded := 0
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line longer than 75 octets: %q", line)
+		}
+		if strings.HasPrefix(line, " ") {
+			folded++
+		}
+	}
+	if folded == 0 {
+		t.Error("Expected the HTML body to be folded")
+	}
+
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	if !strings.Contains(unfolded, "X-ALT-DESC;FMTTYPE=text/html:"+strings.Repeat("<p>é</p>", 40)+"\r\n") {
+		t.Errorf("Folding changed the content:\n%s", unfolded)
+	}
+}
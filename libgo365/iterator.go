@@ -0,0 +1,72 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrStopIteration can be returned by a PageIterator callback to stop early
+// without Iterate returning an error
+var ErrStopIteration = errors.New("stop iteration")
+
+// PageIterator walks every item of a Graph collection, following
+// @odata.nextLink from page to page until the last one. Pages come from
+// GetPages, so the next page is already being fetched while the caller
+// handles the items of this one.
+type PageIterator[T any] struct {
+	client *Client
+	path   string
+	opts   []RequestOption
+}
+
+// NewPageIterator returns an iterator over the collection at path, such as
+// "/me/contacts?$top=100". opts apply to every page.
+func NewPageIterator[T any](c *Client, path string, opts ...RequestOption) *PageIterator[T] {
+	return &PageIterator[T]{client: c, path: path, opts: opts}
+}
+
+// Iterate calls fn with each item in order. It stops at the first error from
+// Graph or fn, or when ctx is cancelled, and returns that error; returning
+// ErrStopIteration from fn stops it with no error.
+func (it *PageIterator[T]) Iterate(ctx context.Context, fn func(*T) error) error {
+	err := it.client.GetPages(ctx, it.path, func(data []byte) error {
+		var page struct {
+			Value []json.RawMessage `json:"value"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("failed to unmarshal page: %w", err)
+		}
+		for _, raw := range page.Value {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var item T
+			if err := json.Unmarshal(raw, &item); err != nil {
+				return fmt.Errorf("failed to unmarshal item: %w", err)
+			}
+			if err := fn(&item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, it.opts...)
+	if errors.Is(err, ErrStopIteration) {
+		return nil
+	}
+	return err
+}
+
+// All collects every item. Prefer Iterate for collections that may be large.
+func (it *PageIterator[T]) All(ctx context.Context) ([]*T, error) {
+	items := []*T{}
+	err := it.Iterate(ctx, func(item *T) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
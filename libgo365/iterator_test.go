@@ -0,0 +1,122 @@
+package libgo365
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type iteratorItem struct {
+	ID string `json:"id"`
+}
+
+func TestPageIterator(t *testing.T) {
+	server := pagedServer(3, nil)
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	items, err := NewPageIterator[iteratorItem](client, "/items").All(context.Background())
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	if strings.Join(ids, ",") != "item1,item2,item3" {
+		t.Errorf("Expected items from all 3 pages in order, got %v", ids)
+	}
+}
+
+func TestPageIteratorStops(t *testing.T) {
+	requested := make(chan int, 10)
+	server := pagedServer(10, requested)
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+	it := NewPageIterator[iteratorItem](client, "/items")
+
+	calls := 0
+	err := it.Iterate(context.Background(), func(item *iteratorItem) error {
+		calls++
+		if calls == 2 {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	// The third page may have been requested ahead, but no further
+	if err != nil || calls != 2 || len(requested) < 2 || len(requested) > 3 {
+		t.Errorf("Expected to stop cleanly after 2 pages, got %v after %d items and %d requests", err, calls, len(requested))
+	}
+
+	stop := errors.New("stop")
+	if err := it.Iterate(context.Background(), func(item *iteratorItem) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("Expected the callback's error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = it.Iterate(ctx, func(item *iteratorItem) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCollectionIterators(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"value": [{"id": "1"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+	ctx := context.Background()
+
+	messages, err := client.MessageIterator(&ListMessagesOptions{FolderID: "inbox"}).All(ctx)
+	if err != nil || len(messages) != 1 || messages[0].ID != "1" {
+		t.Errorf("Unexpected messages %v (%v)", messages, err)
+	}
+	if _, err := client.EventIterator(&ListEventsOptions{CalendarID: "cal"}).All(ctx); err != nil {
+		t.Errorf("EventIterator failed: %v", err)
+	}
+	if _, err := client.DriveItemIterator("/Documents", nil).All(ctx); err != nil {
+		t.Errorf("DriveItemIterator failed: %v", err)
+	}
+	if _, err := client.CalendarIterator().All(ctx); err != nil {
+		t.Errorf("CalendarIterator failed: %v", err)
+	}
+	view, err := client.CalendarViewIterator(&CalendarViewOptions{StartDateTime: "2025-01-01T00:00:00", EndDateTime: "2025-02-01T00:00:00"})
+	if err != nil {
+		t.Fatalf("CalendarViewIterator failed: %v", err)
+	}
+	if _, err := view.All(ctx); err != nil {
+		t.Errorf("Iterating the calendar view failed: %v", err)
+	}
+	if _, err := client.CalendarViewIterator(&CalendarViewOptions{StartDateTime: "a", EndDateTime: "b", AllCalendars: true}); err == nil {
+		t.Error("Expected AllCalendars to be rejected")
+	}
+
+	want := []string{"/me/mailFolders/inbox/messages", "/me/calendars/cal/events", "/me/drive/root:/Documents:/children", "/me/calendars", "/me/calendarView"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("Requested %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,67 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SensitivityLabel represents an information protection sensitivity label
+type SensitivityLabel struct {
+	ID             string            `json:"id,omitempty"`
+	Name           string            `json:"name,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	Color          string            `json:"color,omitempty"`
+	Sensitivity    int               `json:"sensitivity,omitempty"` // Higher is more sensitive
+	Tooltip        string            `json:"tooltip,omitempty"`
+	IsActive       bool              `json:"isActive,omitempty"`
+	IsAppliable    bool              `json:"isAppliable,omitempty"`
+	HasProtection  bool              `json:"hasProtection,omitempty"`
+	ContentFormats []string          `json:"contentFormats,omitempty"` // file, email, site, unifiedGroup, ...
+	Parent         *SensitivityLabel `json:"parent,omitempty"`
+}
+
+// SupportsFormat returns true if the label can be applied to the given content format
+// (e.g., "file" or "email"). Labels that don't advertise formats are assumed to apply everywhere.
+func (l *SensitivityLabel) SupportsFormat(format string) bool {
+	if len(l.ContentFormats) == 0 {
+		return true
+	}
+	for _, f := range l.ContentFormats {
+		if strings.EqualFold(f, format) {
+			return true
+		}
+	}
+	return false
+}
+
+// SensitivityLabelList represents a list of sensitivity labels returned by Graph API
+type SensitivityLabelList struct {
+	Value []*SensitivityLabel `json:"value"`
+}
+
+// ListSensitivityLabelsOptions represents options for listing sensitivity labels
+type ListSensitivityLabelsOptions struct {
+	UserID string // Labels available to another user (default: current user)
+}
+
+// ListSensitivityLabels retrieves the sensitivity labels available to the user
+func (c *Client) ListSensitivityLabels(ctx context.Context, opts *ListSensitivityLabelsOptions) ([]*SensitivityLabel, error) {
+	path := "/me/security/informationProtection/sensitivityLabels"
+	if opts != nil && opts.UserID != "" {
+		path = fmt.Sprintf("/users/%s/security/informationProtection/sensitivityLabels", opts.UserID)
+	}
+
+	data, err := c.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var labelList SensitivityLabelList
+	if err := json.Unmarshal(data, &labelList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sensitivity labels: %w", err)
+	}
+
+	return labelList.Value, nil
+}
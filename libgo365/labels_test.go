@@ -0,0 +1,64 @@
+package libgo365
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListSensitivityLabels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/security/informationProtection/sensitivityLabels" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"value": [
+			{"id": "l1", "name": "Public", "sensitivity": 0, "isActive": true, "isAppliable": true, "contentFormats": ["file", "email"]},
+			{"id": "l2", "name": "Confidential", "sensitivity": 2, "isActive": true, "hasProtection": true, "contentFormats": ["file"]}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	labels, err := client.ListSensitivityLabels(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListSensitivityLabels failed: %v", err)
+	}
+	if len(labels) != 2 {
+		t.Fatalf("Expected 2 labels, got %d", len(labels))
+	}
+	if !labels[0].SupportsFormat("email") {
+		t.Error("Expected Public to support email")
+	}
+	if labels[1].SupportsFormat("EMAIL") {
+		t.Error("Expected Confidential not to support email")
+	}
+	if !labels[1].HasProtection {
+		t.Error("Expected Confidential to have protection")
+	}
+}
+
+func TestListSensitivityLabelsForUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/jane@example.com/security/informationProtection/sensitivityLabels" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"value": []}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	if _, err := client.ListSensitivityLabels(context.Background(), &ListSensitivityLabelsOptions{UserID: "jane@example.com"}); err != nil {
+		t.Fatalf("ListSensitivityLabels failed: %v", err)
+	}
+}
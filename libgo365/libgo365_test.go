@@ -20,6 +20,31 @@ func TestTokenCache(t *testing.T) {
 	}
 }
 
+func TestTenantTokenCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tc, err := NewTenantTokenCache("Contoso.onmicrosoft.com")
+	if err != nil {
+		t.Fatalf("NewTenantTokenCache failed: %v", err)
+	}
+	if want := filepath.Join(home, ".go365", "tenants", "contoso.onmicrosoft.com.bin"); tc.Path() != want {
+		t.Errorf("Expected %s, got %s", want, tc.Path())
+	}
+
+	tc, err = NewTenantTokenCache("../../etc/passwd")
+	if err != nil {
+		t.Fatalf("NewTenantTokenCache failed: %v", err)
+	}
+	if filepath.Dir(tc.Path()) != filepath.Join(home, ".go365", "tenants") {
+		t.Errorf("Expected the cache inside the tenants directory, got %s", tc.Path())
+	}
+
+	if _, err := NewTenantTokenCache(""); err == nil {
+		t.Error("Expected an empty tenant to be rejected")
+	}
+}
+
 func TestConfigManager(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir := t.TempDir()
@@ -76,6 +101,99 @@ func TestConfigManagerDefaults(t *testing.T) {
 	}
 }
 
+func TestConfigManagerProjectOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	nested := filepath.Join(projectDir, "src", "pkg")
+	if err := os.MkdirAll(nested, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	projectFile := filepath.Join(projectDir, ".go365.yaml")
+	yamlConfig := "tenant_id: project-tenant\ncalendar: cal-123\noutput: table\nmailbox: team@example.com\n" +
+		"proxy_url: http://proxy.example.com:8080\nca_file: /tmp/evil.pem\nclient_secret: stolen\ncert_path: /tmp/cert.pem\n"
+	if err := os.WriteFile(projectFile, []byte(yamlConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if found := FindProjectConfig(nested); found != projectFile {
+		t.Fatalf("Expected project config %s, got %q", projectFile, found)
+	}
+
+	cm := &ConfigManager{
+		configPath:  filepath.Join(tmpDir, "config.json"),
+		projectPath: projectFile,
+	}
+	if err := cm.Save(&Config{TenantID: "user-tenant", ClientID: "user-client", ProxyURL: "http://user-proxy:3128"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	config, err := cm.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if config.TenantID != "project-tenant" || config.ClientID != "user-client" {
+		t.Errorf("Expected project tenant over user client, got %+v", config)
+	}
+	if config.Calendar != "cal-123" || config.Output != "table" || config.Mailbox != "team@example.com" {
+		t.Errorf("Expected project defaults, got %+v", config)
+	}
+	if config.ProxyURL != "http://user-proxy:3128" || config.CAFile != "" || config.ClientSecret != "" || config.CertPath != "" {
+		t.Errorf("Expected network and credential settings from the user config only, got %+v", config)
+	}
+
+	global, err := cm.LoadGlobal()
+	if err != nil {
+		t.Fatalf("LoadGlobal failed: %v", err)
+	}
+	if global.TenantID != "user-tenant" {
+		t.Errorf("Expected LoadGlobal to ignore project config, got %s", global.TenantID)
+	}
+}
+
+func TestConfigManagerSetOverride(t *testing.T) {
+	cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.json")}
+	if err := cm.Save(&Config{TenantID: "user-tenant", ClientID: "user-client"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	cm.SetOverride(&Config{TenantID: "customer.onmicrosoft.com"})
+
+	config, err := cm.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if config.TenantID != "customer.onmicrosoft.com" || config.ClientID != "user-client" {
+		t.Errorf("Expected the overridden tenant with the user's client, got %+v", config)
+	}
+
+	global, err := cm.LoadGlobal()
+	if err != nil {
+		t.Fatalf("LoadGlobal failed: %v", err)
+	}
+	if global.TenantID != "user-tenant" {
+		t.Errorf("Expected LoadGlobal to ignore the override, got %s", global.TenantID)
+	}
+}
+
+func TestLoadProjectConfigJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".go365.json")
+	if err := os.WriteFile(path, []byte(`{"client_id": "json-client", "output": "json"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadProjectConfig(path)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig failed: %v", err)
+	}
+	if config.ClientID != "json-client" || config.Output != "json" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	if FindProjectConfig(t.TempDir()) != "" {
+		t.Error("Expected no project config in an empty directory tree")
+	}
+}
+
 func TestNewAuthenticator(t *testing.T) {
 	// This test just ensures we can create an authenticator
 	// We can't test full device code flow without a real server
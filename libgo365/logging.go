@@ -0,0 +1,186 @@
+package libgo365
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LevelTrace is the slog level used for full request and response dumps. It
+// sits below slog.LevelDebug, where request summaries are logged, so that a
+// handler at the default Info level logs nothing from this package.
+const LevelTrace = slog.LevelDebug - 4
+
+// maxLoggedBody is the most body bytes included in a trace dump
+const maxLoggedBody = 4096
+
+// redactedHeaders are replaced with "[REDACTED]" in trace dumps
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// secretFields matches JSON string fields whose values are redacted from dumps
+var secretFields = regexp.MustCompile(`("(?i:access_token|refresh_token|id_token|client_secret|password)"\s*:\s*)"[^"]*"`)
+
+// SetLogger sets the logger for request logging. Without one, the client logs
+// to slog.Default().
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+// RequestRecord describes a completed Graph request, for SetRequestHook
+type RequestRecord struct {
+	Method    string
+	Path      string // Relative to the API version, without the query
+	Status    int    // 0 when no response was received
+	RequestID string
+	Batched   bool // Sent inside a $batch call, which is also recorded itself
+}
+
+// SetRequestHook calls fn after every Graph request, including each
+// sub-request of a $batch call. fn may be called from several goroutines.
+func (c *Client) SetRequestHook(fn func(*RequestRecord)) {
+	c.requestHook = fn
+}
+
+// recordRequest passes a request to the hook, if one is set
+func (c *Client) recordRequest(method, rawURL string, status int, requestID string, batched bool) {
+	if c.requestHook == nil {
+		return
+	}
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+	for _, version := range []string{"/v1.0", "/beta"} {
+		if strings.HasPrefix(path, version+"/") {
+			path = strings.TrimPrefix(path, version)
+			break
+		}
+	}
+	c.requestHook(&RequestRecord{Method: method, Path: path, Status: status, RequestID: requestID, Batched: batched})
+}
+
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return slog.Default()
+}
+
+// loggingTransport logs a summary of each request at debug level and, at
+// trace level, redacted headers and bodies
+type loggingTransport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	logger := t.client.log()
+	tracing := logger.Enabled(ctx, LevelTrace)
+
+	if tracing {
+		logger.Log(ctx, LevelTrace, "graph request",
+			"method", req.Method,
+			"url", t.loggedURL(req.URL),
+			"headers", redactHeaders(req.Header),
+			"body", requestBody(req))
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		logger.DebugContext(ctx, "graph request failed",
+			"method", req.Method, "url", t.loggedURL(req.URL), "duration", elapsed, "error", err)
+		t.client.recordRequest(req.Method, req.URL.String(), 0, "", false)
+		return nil, err
+	}
+
+	logger.DebugContext(ctx, "graph request",
+		"method", req.Method, "url", t.loggedURL(req.URL), "status", resp.StatusCode, "duration", elapsed,
+		"request_id", resp.Header.Get("request-id"), "client_request_id", req.Header.Get("client-request-id"))
+	t.client.recordRequest(req.Method, req.URL.String(), resp.StatusCode, resp.Header.Get("request-id"), false)
+
+	if tracing {
+		logger.Log(ctx, LevelTrace, "graph response",
+			"status", resp.StatusCode,
+			"headers", redactHeaders(resp.Header),
+			"body", responseBody(resp))
+	}
+
+	return resp, nil
+}
+
+// loggedURL returns u as it is logged. Pre-authenticated URLs outside Graph,
+// such as attachment upload sessions and OneDrive download redirects, carry
+// a token in their query, so the query is redacted for any other host.
+func (t *loggingTransport) loggedURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	if base, err := url.Parse(t.client.baseURL); err == nil && base.Host == u.Host {
+		return u.String()
+	}
+	redacted := *u
+	redacted.RawQuery = "[REDACTED]"
+	return redacted.String()
+}
+
+// redactHeaders returns the headers as a map with credentials hidden
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name := range h {
+		out[name] = h.Get(name)
+	}
+	for _, name := range redactedHeaders {
+		if _, ok := out[name]; ok {
+			out[name] = "[REDACTED]"
+		}
+	}
+	return out
+}
+
+// requestBody returns a redacted copy of the request body without consuming it
+func requestBody(req *http.Request) string {
+	if req.Body == nil || req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(io.LimitReader(body, maxLoggedBody+1))
+	return redactBody(data)
+}
+
+// responseBody reads the response body for logging and puts it back
+func responseBody(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	return redactBody(data)
+}
+
+func redactBody(data []byte) string {
+	truncated := len(data) > maxLoggedBody
+	if truncated {
+		data = data[:maxLoggedBody]
+	}
+	s := secretFields.ReplaceAllString(string(data), `$1"[REDACTED]"`)
+	if truncated {
+		s += "…"
+	}
+	return s
+}
@@ -0,0 +1,156 @@
+package libgo365
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newLoggingTestClient(t *testing.T, handler http.HandlerFunc, level slog.Level) (*Client, *bytes.Buffer) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	var buf bytes.Buffer
+	client := NewClient(context.Background(), "secret-token")
+	client.baseURL = server.URL
+	client.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: level})))
+	return client, &buf
+}
+
+func TestRequestLoggingSummary(t *testing.T) {
+	client, buf := newLoggingTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("request-id", "req-123")
+		w.Write([]byte(`{"id":"msg1"}`))
+	}, slog.LevelDebug)
+
+	if _, err := client.GetMessage(context.Background(), "msg1"); err != nil {
+		t.Fatalf("GetMessage failed: %v", err)
+	}
+
+	logged := buf.String()
+	if !strings.Contains(logged, "method=GET") || !strings.Contains(logged, "status=200") {
+		t.Errorf("Expected request summary, got %s", logged)
+	}
+	if !strings.Contains(logged, "request_id=req-123") {
+		t.Errorf("Expected Graph's request ID in the summary, got %s", logged)
+	}
+	if strings.Contains(logged, "headers") {
+		t.Errorf("Expected no dump at debug level, got %s", logged)
+	}
+}
+
+func TestRequestLoggingTrace(t *testing.T) {
+	client, buf := newLoggingTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"msg1","access_token":"leaked"}`))
+	}, LevelTrace)
+
+	message, err := client.MoveMessage(context.Background(), "msg1", "archive")
+	if err != nil {
+		t.Fatalf("MoveMessage failed: %v", err)
+	}
+	if message.ID != "msg1" {
+		t.Errorf("Expected response body to survive logging, got %+v", message)
+	}
+
+	logged := buf.String()
+	if strings.Contains(logged, "secret-token") || strings.Contains(logged, "leaked") {
+		t.Errorf("Expected credentials redacted, got %s", logged)
+	}
+	if !strings.Contains(logged, "destinationId") || !strings.Contains(logged, "[REDACTED]") {
+		t.Errorf("Expected redacted request/response dump, got %s", logged)
+	}
+}
+
+func TestRequestLoggingRedactsOtherHostsQueries(t *testing.T) {
+	client, buf := newLoggingTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"value":[]}`))
+	}, LevelTrace)
+	upload := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upload.Close()
+
+	if _, err := client.Get(context.Background(), "/me/messages?$top=5"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	resp, err := client.httpClient.Get(upload.URL + "/upload?authtoken=upload-secret")
+	if err != nil {
+		t.Fatalf("Upload request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	logged := buf.String()
+	if strings.Contains(logged, "upload-secret") {
+		t.Errorf("Expected the upload URL's token redacted, got %s", logged)
+	}
+	if !strings.Contains(logged, "/upload?[REDACTED]") || !strings.Contains(logged, "top=5") {
+		t.Errorf("Expected Graph queries kept and others redacted, got %s", logged)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	headers := redactHeaders(http.Header{
+		"Authorization":       {"Bearer secret-token"},
+		"Proxy-Authorization": {"Basic c2VjcmV0"},
+		"Client-Request-Id":   {"client-1"},
+	})
+	if headers["Authorization"] != "[REDACTED]" || headers["Proxy-Authorization"] != "[REDACTED]" {
+		t.Errorf("Expected credentials redacted, got %v", headers)
+	}
+	if headers["Client-Request-Id"] != "client-1" {
+		t.Errorf("Expected other headers kept, got %v", headers)
+	}
+}
+
+func TestRequestLoggingQuietByDefault(t *testing.T) {
+	client, buf := newLoggingTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}, slog.LevelInfo)
+
+	client.GetMessage(context.Background(), "msg1")
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing logged at info level, got %s", buf.String())
+	}
+}
+
+func TestRequestHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1.0/$batch" {
+			w.Write([]byte(`{"responses":[{"id":"1","status":204,"headers":{"Request-Id":"sub-1"}}]}`))
+			return
+		}
+		w.Header().Set("request-id", "req-1")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "secret-token")
+	client.baseURL = server.URL + "/v1.0"
+
+	var records []*RequestRecord
+	client.SetRequestHook(func(r *RequestRecord) { records = append(records, r) })
+
+	if err := client.DeleteMessage(context.Background(), "msg1"); err != nil {
+		t.Fatalf("DeleteMessage failed: %v", err)
+	}
+	if _, err := client.Batch(context.Background(), []*BatchRequest{{Method: "DELETE", URL: "/me/messages/msg2?x=1"}}); err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+
+	want := []RequestRecord{
+		{Method: "DELETE", Path: "/me/messages/msg1", Status: 204, RequestID: "req-1"},
+		{Method: "POST", Path: "/$batch", Status: 200},
+		{Method: "DELETE", Path: "/me/messages/msg2", Status: 204, RequestID: "sub-1", Batched: true},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("Expected %d records, got %d", len(want), len(records))
+	}
+	for i, r := range records {
+		if *r != want[i] {
+			t.Errorf("Record %d = %+v, want %+v", i, *r, want[i])
+		}
+	}
+}
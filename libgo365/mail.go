@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -26,12 +28,61 @@ type Message struct {
 	ReceivedDateTime     *time.Time   `json:"receivedDateTime,omitempty"`
 	SentDateTime         *time.Time   `json:"sentDateTime,omitempty"`
 	HasAttachments       bool         `json:"hasAttachments,omitempty"`
-	Importance           string       `json:"importance,omitempty"`
+	Importance           Importance   `json:"importance,omitempty"`
 	IsRead               bool         `json:"isRead,omitempty"`
 	IsDraft              bool         `json:"isDraft,omitempty"`
 	ConversationID       string       `json:"conversationId,omitempty"`
 	InternetMessageID    string       `json:"internetMessageId,omitempty"`
 	WebLink              string       `json:"webLink,omitempty"`
+
+	// Flag is the follow-up flag; see FlagMessage
+	Flag *FollowupFlag `json:"flag,omitempty"`
+
+	// Attachments are sent inline with the message; see SendMailWithAttachments
+	// for files too big for that
+	Attachments []*Attachment `json:"attachments,omitempty"`
+
+	// ETag changes whenever the message does; pass it to WithIfMatch so an
+	// update fails instead of overwriting someone else's change
+	ETag string `json:"@odata.etag,omitempty"`
+
+	// SingleValueExtendedProperties carries MAPI properties that Graph has no
+	// field for, such as the deferred send time set by DeferSend
+	SingleValueExtendedProperties []*SingleValueExtendedProperty `json:"singleValueExtendedProperties,omitempty"`
+}
+
+// Follow-up flag states
+const (
+	FlagStatusNotFlagged = "notFlagged"
+	FlagStatusFlagged    = "flagged"
+	FlagStatusComplete   = "complete"
+)
+
+// FollowupFlag is a message's follow-up flag
+type FollowupFlag struct {
+	FlagStatus    string            `json:"flagStatus,omitempty"`
+	StartDateTime *DateTimeTimeZone `json:"startDateTime,omitempty"`
+	DueDateTime   *DateTimeTimeZone `json:"dueDateTime,omitempty"`
+}
+
+// SingleValueExtendedProperty is a MAPI property addressed by its Graph ID,
+// e.g. "SystemTime 0x3FEF"
+type SingleValueExtendedProperty struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+}
+
+// deferredSendTimeProperty is PidTagDeferredSendTime, which holds a sent
+// message in the Outbox until the given time
+const deferredSendTimeProperty = "SystemTime 0x3FEF"
+
+// DeferSend makes Exchange hold the message until at instead of sending it
+// straight away (scheduled send)
+func (m *Message) DeferSend(at time.Time) {
+	m.SingleValueExtendedProperties = append(m.SingleValueExtendedProperties, &SingleValueExtendedProperty{
+		ID:    deferredSendTimeProperty,
+		Value: at.UTC().Format(time.RFC3339),
+	})
 }
 
 // ItemBody represents the body of an item
@@ -64,6 +115,22 @@ type MessageList struct {
 	Count    int        `json:"@odata.count,omitempty"`
 }
 
+// MailFolder represents a mail folder in the user's mailbox
+type MailFolder struct {
+	ID               string `json:"id,omitempty"`
+	DisplayName      string `json:"displayName,omitempty"`
+	ParentFolderID   string `json:"parentFolderId,omitempty"`
+	ChildFolderCount int    `json:"childFolderCount,omitempty"`
+	UnreadItemCount  int    `json:"unreadItemCount,omitempty"`
+	TotalItemCount   int    `json:"totalItemCount,omitempty"`
+}
+
+// MailFolderList represents a list of mail folders returned by Graph API
+type MailFolderList struct {
+	Value    []*MailFolder `json:"value"`
+	NextLink string        `json:"@odata.nextLink,omitempty"`
+}
+
 // ListMessagesOptions represents options for listing messages
 type ListMessagesOptions struct {
 	FolderID  string
@@ -74,6 +141,8 @@ type ListMessagesOptions struct {
 	OrderBy   string
 	StartTime *time.Time
 	EndTime   *time.Time
+	UserID    string   // Email or user ID for reading another mailbox
+	Select    []string // Properties to return ($select); empty for all
 }
 
 // ListMessagesResponse represents the response from ListMessages with pagination info
@@ -120,77 +189,160 @@ func (c *Client) ListMessages(ctx context.Context, opts *ListMessagesOptions) ([
 
 // ListMessagesWithPagination retrieves messages with pagination information
 func (c *Client) ListMessagesWithPagination(ctx context.Context, opts *ListMessagesOptions) (*ListMessagesResponse, error) {
-	path := "/me/messages"
+	messages := []*Message{}
+	resp, err := c.StreamMessages(ctx, opts, func(msg *Message) error {
+		messages = append(messages, msg)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Messages = messages
+	return resp, nil
+}
+
+// StreamMessages lists messages like ListMessagesWithPagination, but calls fn
+// with each message as it is decoded instead of collecting them, so callers
+// can start on the first messages before the page has downloaded. The
+// response carries the count and pagination details but no Messages.
+func (c *Client) StreamMessages(ctx context.Context, opts *ListMessagesOptions, fn func(*Message) error) (*ListMessagesResponse, error) {
+	count := 0
+	nextLink, err := c.GetStream(ctx, messagesPath(opts), func(item json.RawMessage) error {
+		var msg Message
+		if err := json.Unmarshal(item, &msg); err != nil {
+			return fmt.Errorf("failed to unmarshal messages: %w", err)
+		}
+		count++
+		return fn(&msg)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListMessagesResponse{
+		Count:         count,
+		HasMore:       nextLink != "",
+		NextPageToken: ExtractPageToken(nextLink),
+	}, nil
+}
+
+// MessageIterator returns an iterator over every message opts selects,
+// starting from opts.PageToken if it is set
+func (c *Client) MessageIterator(opts *ListMessagesOptions) *PageIterator[Message] {
+	return NewPageIterator[Message](c, messagesPath(opts))
+}
+
+// messagesPath builds the request path and query for listing messages
+func messagesPath(opts *ListMessagesOptions) string {
+	mailbox := "/me"
+	if opts != nil && opts.UserID != "" {
+		mailbox = fmt.Sprintf("/users/%s", opts.UserID)
+	}
+
+	path := mailbox + "/messages"
 	if opts != nil && opts.FolderID != "" {
-		path = fmt.Sprintf("/me/mailFolders/%s/messages", opts.FolderID)
+		path = fmt.Sprintf("%s/mailFolders/%s/messages", mailbox, opts.FolderID)
 	}
 
-	// Build query parameters
-	params := url.Values{}
-	params.Set("$top", fmt.Sprintf("%d", DefaultMessageLimit))
-	params.Set("$count", "true") // Request count for pagination info
+	query := NewQuery().Top(DefaultMessageLimit).Count(true) // Count for pagination info
 
 	if opts != nil {
 		if opts.Top > 0 {
-			params.Set("$top", fmt.Sprintf("%d", opts.Top))
+			query.Top(opts.Top)
 		}
 
 		// Handle pagination: PageToken takes precedence over Skip
 		if opts.PageToken != "" {
 			// Check if it's a skiptoken (contains non-numeric characters) or a skip value
-			if _, err := fmt.Sscanf(opts.PageToken, "%d", new(int)); err == nil {
-				// It's a numeric skip value
-				params.Set("$skip", opts.PageToken)
+			if skip, err := strconv.Atoi(opts.PageToken); err == nil {
+				query.Skip(skip)
 			} else {
-				// It's a skiptoken
-				params.Set("$skiptoken", opts.PageToken)
+				query.SkipToken(opts.PageToken)
 			}
 		} else if opts.Skip > 0 {
-			params.Set("$skip", fmt.Sprintf("%d", opts.Skip))
+			query.Skip(opts.Skip)
 		}
 
-		filters := []string{}
 		if opts.StartTime != nil {
-			filters = append(filters, fmt.Sprintf("receivedDateTime ge %s", opts.StartTime.Format(time.RFC3339)))
+			query.Filterf("receivedDateTime ge %v", *opts.StartTime)
 		}
 		if opts.EndTime != nil {
-			filters = append(filters, fmt.Sprintf("receivedDateTime lt %s", opts.EndTime.Format(time.RFC3339)))
+			query.Filterf("receivedDateTime lt %v", *opts.EndTime)
 		}
-		if opts.Filter != "" {
-			filters = append(filters, opts.Filter)
+		query.Filter(opts.Filter)
+		if opts.OrderBy != "" {
+			query.OrderBy(opts.OrderBy)
 		}
+		query.Select(opts.Select...)
+	}
 
-		if len(filters) > 0 {
-			filterStr := filters[0]
-			for i := 1; i < len(filters); i++ {
-				filterStr += " and " + filters[i]
-			}
-			params.Set("$filter", filterStr)
-		}
+	return query.Path(path)
+}
 
-		if opts.OrderBy != "" {
-			params.Set("$orderby", opts.OrderBy)
-		}
+// ListMailFolders retrieves the top-level mail folders in the user's mailbox
+func (c *Client) ListMailFolders(ctx context.Context) ([]*MailFolder, error) {
+	data, err := c.Get(ctx, "/me/mailFolders?$top=100")
+	if err != nil {
+		return nil, err
+	}
+
+	var folderList MailFolderList
+	if err := json.Unmarshal(data, &folderList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mail folders: %w", err)
 	}
 
-	data, err := c.Get(ctx, path+"?"+params.Encode())
+	return folderList.Value, nil
+}
+
+// MailFolderIterator returns an iterator over the folders inside parentID,
+// or the top-level folders if it is empty
+func (c *Client) MailFolderIterator(parentID string) *PageIterator[MailFolder] {
+	path := "/me/mailFolders?$top=100"
+	if parentID != "" {
+		path = fmt.Sprintf("/me/mailFolders/%s/childFolders?$top=100", url.PathEscape(parentID))
+	}
+	return NewPageIterator[MailFolder](c, path)
+}
+
+// GetMailFolder retrieves a mail folder by ID or well-known name, such as
+// "archive", which is how to find the ID of a well-known folder
+func (c *Client) GetMailFolder(ctx context.Context, folderID string) (*MailFolder, error) {
+	if folderID == "" {
+		return nil, fmt.Errorf("folder ID is required")
+	}
+
+	data, err := c.Get(ctx, fmt.Sprintf("/me/mailFolders/%s", folderID))
 	if err != nil {
 		return nil, err
 	}
 
-	var messageList MessageList
-	if err := json.Unmarshal(data, &messageList); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal messages: %w", err)
+	var folder MailFolder
+	if err := json.Unmarshal(data, &folder); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mail folder: %w", err)
 	}
 
-	nextPageToken := ExtractPageToken(messageList.NextLink)
+	return &folder, nil
+}
 
-	return &ListMessagesResponse{
-		Messages:      messageList.Value,
-		Count:         len(messageList.Value),
-		HasMore:       messageList.NextLink != "",
-		NextPageToken: nextPageToken,
-	}, nil
+// CreateMailFolder creates a folder, inside parentID if it is set or at the
+// top of the mailbox otherwise
+func (c *Client) CreateMailFolder(ctx context.Context, parentID, name string) (*MailFolder, error) {
+	path := "/me/mailFolders"
+	if parentID != "" {
+		path = fmt.Sprintf("/me/mailFolders/%s/childFolders", parentID)
+	}
+
+	data, err := c.Post(ctx, path, map[string]string{"displayName": name})
+	if err != nil {
+		return nil, err
+	}
+
+	var folder MailFolder
+	if err := json.Unmarshal(data, &folder); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mail folder: %w", err)
+	}
+
+	return &folder, nil
 }
 
 // GetMessage retrieves a specific message by ID
@@ -212,6 +364,17 @@ func (c *Client) GetMessage(ctx context.Context, messageID string) (*Message, er
 	return &message, nil
 }
 
+// DownloadMessageMIME streams a message's MIME content, the RFC 5322 form
+// other mail clients read as a .eml file, to w and returns the number of
+// bytes written
+func (c *Client) DownloadMessageMIME(ctx context.Context, messageID string, w io.Writer) (int64, error) {
+	if messageID == "" {
+		return 0, fmt.Errorf("message ID is required")
+	}
+
+	return c.Download(ctx, fmt.Sprintf("/me/messages/%s/$value", url.PathEscape(messageID)), w)
+}
+
 // SendMail sends an email message
 func (c *Client) SendMail(ctx context.Context, message *Message, saveToSentItems bool) error {
 	if message == nil {
@@ -234,3 +397,337 @@ func (c *Client) SendMail(ctx context.Context, message *Message, saveToSentItems
 	_, err := c.Post(ctx, "/me/sendMail", sendRequest)
 	return err
 }
+
+// DeleteMessage deletes a message. Graph moves it to Deleted Items.
+// WithIfMatch makes it fail if the message changed since it was read.
+func (c *Client) DeleteMessage(ctx context.Context, messageID string, opts ...RequestOption) error {
+	if messageID == "" {
+		return fmt.Errorf("message ID is required")
+	}
+
+	return c.Delete(ctx, fmt.Sprintf("/me/messages/%s", messageID), opts...)
+}
+
+// PermanentlyDeleteMessage deletes a message outright instead of moving it
+// to Deleted Items. It can't be recovered from the mailbox afterwards.
+func (c *Client) PermanentlyDeleteMessage(ctx context.Context, messageID string) error {
+	if messageID == "" {
+		return fmt.Errorf("message ID is required")
+	}
+
+	_, err := c.Post(ctx, fmt.Sprintf("/me/messages/%s/permanentDelete", messageID), nil)
+	return err
+}
+
+// FlagMessage sets a message's follow-up flag. Graph requires a start date
+// whenever a due date is given.
+func (c *Client) FlagMessage(ctx context.Context, messageID string, flag *FollowupFlag, opts ...RequestOption) error {
+	if messageID == "" {
+		return fmt.Errorf("message ID is required")
+	}
+	if flag == nil {
+		return fmt.Errorf("flag is required")
+	}
+
+	body := map[string]interface{}{
+		"flag": flag,
+	}
+
+	_, err := c.UpdateMessage(ctx, messageID, body, opts...)
+	return err
+}
+
+// UpdateMessage changes the properties of a message set in changes and
+// returns the updated message. changes may be a *Message, which only sends
+// the fields that are set, or a map for values a Message would omit, such
+// as {"isRead": false}. WithIfMatch makes it fail if the message changed
+// since it was read.
+func (c *Client) UpdateMessage(ctx context.Context, messageID string, changes interface{}, opts ...RequestOption) (*Message, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("message ID is required")
+	}
+	if changes == nil {
+		return nil, fmt.Errorf("changes are required")
+	}
+
+	data, err := c.Patch(ctx, fmt.Sprintf("/me/messages/%s", messageID), changes, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var message Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+
+	return &message, nil
+}
+
+// MoveMessage moves a message to another folder. destinationID may be a folder
+// ID or a well-known folder name such as "archive" or "deleteditems".
+func (c *Client) MoveMessage(ctx context.Context, messageID, destinationID string) (*Message, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("message ID is required")
+	}
+	if destinationID == "" {
+		return nil, fmt.Errorf("destination folder is required")
+	}
+
+	body := map[string]interface{}{
+		"destinationId": destinationID,
+	}
+
+	data, err := c.Post(ctx, fmt.Sprintf("/me/messages/%s/move", messageID), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var message Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+
+	return &message, nil
+}
+
+// CopyMessage copies a message to another folder and returns the copy.
+// destinationID is as for MoveMessage.
+func (c *Client) CopyMessage(ctx context.Context, messageID, destinationID string) (*Message, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("message ID is required")
+	}
+	if destinationID == "" {
+		return nil, fmt.Errorf("destination folder is required")
+	}
+
+	body := map[string]interface{}{
+		"destinationId": destinationID,
+	}
+
+	data, err := c.Post(ctx, fmt.Sprintf("/me/messages/%s/copy", messageID), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var message Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+
+	return &message, nil
+}
+
+// ReplyToMessage replies to the sender of a message with a comment. With
+// replyAll, all original recipients receive the reply.
+func (c *Client) ReplyToMessage(ctx context.Context, messageID, comment string, replyAll bool) error {
+	if messageID == "" {
+		return fmt.Errorf("message ID is required")
+	}
+
+	action := "reply"
+	if replyAll {
+		action = "replyAll"
+	}
+
+	body := map[string]interface{}{
+		"comment": comment,
+	}
+
+	_, err := c.Post(ctx, fmt.Sprintf("/me/messages/%s/%s", messageID, action), body)
+	return err
+}
+
+// CreateReply saves a draft reply to a message, or a reply to all its
+// recipients with replyAll. Exchange fills in the recipients, the "RE:"
+// subject and the headers that thread the reply, and quotes the original
+// in an HTML body. Change the draft with UpdateMessage and send it with
+// SendDraft.
+func (c *Client) CreateReply(ctx context.Context, messageID string, replyAll bool) (*Message, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("message ID is required")
+	}
+
+	action := "createReply"
+	if replyAll {
+		action = "createReplyAll"
+	}
+
+	data, err := c.Post(ctx, fmt.Sprintf("/me/messages/%s/%s", messageID, action), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var draft Message
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal draft: %w", err)
+	}
+
+	return &draft, nil
+}
+
+// ForwardMessage forwards a message, with its attachments, to toRecipients.
+// comment goes above the forwarded original.
+func (c *Client) ForwardMessage(ctx context.Context, messageID, comment string, toRecipients []*Recipient) error {
+	if messageID == "" {
+		return fmt.Errorf("message ID is required")
+	}
+	if len(toRecipients) == 0 {
+		return fmt.Errorf("at least one recipient is required")
+	}
+
+	body := map[string]interface{}{
+		"comment":      comment,
+		"toRecipients": toRecipients,
+	}
+
+	_, err := c.Post(ctx, fmt.Sprintf("/me/messages/%s/forward", messageID), body)
+	return err
+}
+
+// CreateForward saves a draft forward of a message, attachments included,
+// for changes such as dropping attachments before it is sent with
+// SendDraft. toRecipients may be left empty and set on the draft later.
+func (c *Client) CreateForward(ctx context.Context, messageID, comment string, toRecipients []*Recipient) (*Message, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("message ID is required")
+	}
+
+	body := map[string]interface{}{
+		"comment": comment,
+	}
+	if len(toRecipients) > 0 {
+		body["toRecipients"] = toRecipients
+	}
+
+	data, err := c.Post(ctx, fmt.Sprintf("/me/messages/%s/createForward", messageID), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var draft Message
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal draft: %w", err)
+	}
+
+	return &draft, nil
+}
+
+// CreateDraft saves message in Drafts and returns it with its ID, so it can
+// be built up with AddAttachment and UpdateDraft before SendDraft sends it
+func (c *Client) CreateDraft(ctx context.Context, message *Message) (*Message, error) {
+	if message == nil {
+		return nil, fmt.Errorf("message is required")
+	}
+
+	data, err := c.Post(ctx, "/me/messages", message)
+	if err != nil {
+		return nil, err
+	}
+
+	var draft Message
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal draft: %w", err)
+	}
+
+	return &draft, nil
+}
+
+// UpdateDraft changes a draft's subject, recipients, body or other
+// properties. Unlike on a sent or received message, Graph lets all of them
+// change until the draft is sent. changes is as for UpdateMessage: a
+// *Message for the fields to set, or a map to clear a list, as in
+// {"ccRecipients": []}.
+func (c *Client) UpdateDraft(ctx context.Context, draftID string, changes interface{}, opts ...RequestOption) (*Message, error) {
+	if draftID == "" {
+		return nil, fmt.Errorf("draft ID is required")
+	}
+
+	return c.UpdateMessage(ctx, draftID, changes, opts...)
+}
+
+// SendDraft sends a draft message, which is then saved to Sent Items
+func (c *Client) SendDraft(ctx context.Context, messageID string) error {
+	if messageID == "" {
+		return fmt.Errorf("message ID is required")
+	}
+
+	_, err := c.Post(ctx, fmt.Sprintf("/me/messages/%s/send", messageID), nil)
+	return err
+}
+
+// MessageDelta is one round of a mail folder delta query
+type MessageDelta struct {
+	Messages  []*Message
+	DeltaLink string // Pass to the next MessagesDelta call to get only later changes
+}
+
+// messageDeltaPage is a page of a delta query response
+type messageDeltaPage struct {
+	Value     []*Message `json:"value"`
+	NextLink  string     `json:"@odata.nextLink,omitempty"`
+	DeltaLink string     `json:"@odata.deltaLink,omitempty"`
+}
+
+// MessagesDelta returns messages created in a folder since deltaLink was
+// issued. With an empty deltaLink it starts tracking from now, returning only
+// messages received after since (pass the zero time for the whole folder).
+func (c *Client) MessagesDelta(ctx context.Context, folderID, deltaLink string, since time.Time) (*MessageDelta, error) {
+	if folderID == "" {
+		folderID = "inbox"
+	}
+
+	path := deltaLink
+	if path == "" {
+		query := NewQuery().
+			Param("changeType", "created").
+			Select("id", "subject", "from", "receivedDateTime", "bodyPreview", "importance", "isRead", "webLink")
+		if !since.IsZero() {
+			query.Filterf("receivedDateTime ge %v", since)
+		}
+		path = query.Path(fmt.Sprintf("/me/mailFolders/%s/messages/delta", folderID))
+	}
+
+	// Delta and next links are each only good once, so they bypass the cache
+	result := &MessageDelta{}
+	err := c.uncached().GetPages(ctx, path, func(data []byte) error {
+		var page messageDeltaPage
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("failed to unmarshal message delta: %w", err)
+		}
+
+		result.Messages = append(result.Messages, page.Value...)
+		result.DeltaLink = page.DeltaLink
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListUnreadMentions returns unread messages that @mention the signed-in user,
+// newest first. Mentions are only exposed on the Graph beta endpoint.
+func (c *Client) ListUnreadMentions(ctx context.Context, top int) ([]*Message, error) {
+	if top <= 0 {
+		top = DefaultMessageLimit
+	}
+
+	query := NewQuery().
+		Filter("mentionsPreview/isMentioned eq true").
+		Filter("isRead eq false").
+		Select("id", "subject", "from", "receivedDateTime", "bodyPreview", "importance", "isRead", "webLink").
+		Top(top)
+
+	data, err := c.beta().Get(ctx, query.Path("/me/messages"))
+	if err != nil {
+		return nil, err
+	}
+
+	var messageList MessageList
+	if err := json.Unmarshal(data, &messageList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal messages: %w", err)
+	}
+
+	return messageList.Value, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -69,9 +70,9 @@ func TestListMessages(t *testing.T) {
 
 	// Create client with mock server
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -116,9 +117,9 @@ func TestListMessagesWithFolder(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -155,9 +156,9 @@ func TestListMessagesWithTimeFilter(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -172,6 +173,90 @@ func TestListMessagesWithTimeFilter(t *testing.T) {
 	}
 }
 
+func TestListMailFolders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/mailFolders" {
+			t.Errorf("Expected path /me/mailFolders, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(MailFolderList{
+			Value: []*MailFolder{
+				{ID: "f1", DisplayName: "Inbox", UnreadItemCount: 3},
+				{ID: "f2", DisplayName: "Projects"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	folders, err := client.ListMailFolders(context.Background())
+	if err != nil {
+		t.Fatalf("ListMailFolders failed: %v", err)
+	}
+	if len(folders) != 2 || folders[0].UnreadItemCount != 3 {
+		t.Errorf("Unexpected folders: %+v", folders)
+	}
+}
+
+func TestGetMailFolder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/mailFolders/archive" {
+			t.Errorf("Expected path /me/mailFolders/archive, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(MailFolder{ID: "AAMkArchive", DisplayName: "Archive"})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	folder, err := client.GetMailFolder(context.Background(), "archive")
+	if err != nil {
+		t.Fatalf("GetMailFolder failed: %v", err)
+	}
+	if folder.ID != "AAMkArchive" {
+		t.Errorf("Unexpected folder %+v", folder)
+	}
+}
+
+func TestDownloadMessageMIME(t *testing.T) {
+	mime := "Subject: Hello\r\nFrom: alice@example.com\r\n\r\nHi there\r\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/messages/msg-1/$value" {
+			t.Errorf("Expected path /me/messages/msg-1/$value, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, mime)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	var buf strings.Builder
+	n, err := client.DownloadMessageMIME(context.Background(), "msg-1", &buf)
+	if err != nil {
+		t.Fatalf("DownloadMessageMIME failed: %v", err)
+	}
+	if buf.String() != mime || n != int64(len(mime)) {
+		t.Errorf("Unexpected content (%d bytes): %q", n, buf.String())
+	}
+
+	if _, err := client.DownloadMessageMIME(context.Background(), "", &buf); err == nil {
+		t.Error("Expected an error for an empty message ID")
+	}
+}
+
 func TestGetMessage(t *testing.T) {
 	messageID := "test-message-id"
 
@@ -214,9 +299,9 @@ func TestGetMessage(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -241,9 +326,9 @@ func TestGetMessage(t *testing.T) {
 
 func TestGetMessageEmptyID(t *testing.T) {
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     "http://localhost",
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    "http://localhost",
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -298,9 +383,9 @@ func TestSendMail(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -328,9 +413,9 @@ func TestSendMail(t *testing.T) {
 
 func TestSendMailNilMessage(t *testing.T) {
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     "http://localhost",
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    "http://localhost",
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -343,9 +428,9 @@ func TestSendMailNilMessage(t *testing.T) {
 
 func TestSendMailEmptySubject(t *testing.T) {
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     "http://localhost",
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    "http://localhost",
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -365,9 +450,9 @@ func TestSendMailEmptySubject(t *testing.T) {
 
 func TestSendMailNoRecipients(t *testing.T) {
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     "http://localhost",
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    "http://localhost",
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -403,9 +488,9 @@ func TestSendMailWithCcAndBcc(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -502,9 +587,9 @@ func TestListMessagesWithPagination(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -546,9 +631,9 @@ func TestListMessagesWithPaginationNoMore(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -584,9 +669,9 @@ func TestListMessagesWithSkipOption(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -600,6 +685,29 @@ func TestListMessagesWithSkipOption(t *testing.T) {
 	}
 }
 
+func TestListMessagesWithSelect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sel := r.URL.Query().Get("$select"); sel != "id,subject,from" {
+			t.Errorf("Expected $select=id,subject,from, got %s", sel)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(MessageList{Value: []*Message{}})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	opts := &ListMessagesOptions{Select: []string{"id", "subject", "from"}}
+	if _, err := client.ListMessagesWithPagination(context.Background(), opts); err != nil {
+		t.Fatalf("ListMessagesWithPagination failed: %v", err)
+	}
+}
+
 func TestListMessagesWithPageToken(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		skiptokenParam := r.URL.Query().Get("$skiptoken")
@@ -617,9 +725,9 @@ func TestListMessagesWithPageToken(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -657,9 +765,9 @@ func TestListMessagesPageTokenTakesPrecedence(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		httpClient:  &http.Client{},
-		baseURL:     server.URL,
-		accessToken: "test-token",
+		httpClient: &http.Client{},
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
 	}
 
 	ctx := context.Background()
@@ -673,3 +781,466 @@ func TestListMessagesPageTokenTakesPrecedence(t *testing.T) {
 		t.Fatalf("ListMessagesWithPagination failed: %v", err)
 	}
 }
+
+func TestDeleteMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/me/messages/msg1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	if err := client.DeleteMessage(context.Background(), "msg1"); err != nil {
+		t.Fatalf("DeleteMessage failed: %v", err)
+	}
+	if err := client.DeleteMessage(context.Background(), ""); err == nil {
+		t.Error("Expected error for missing message ID")
+	}
+}
+
+func TestMoveMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/me/messages/msg1/move" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["destinationId"] != "archive" {
+			t.Errorf("Expected destinationId archive, got %q", body["destinationId"])
+		}
+		json.NewEncoder(w).Encode(Message{ID: "msg1-moved"})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	moved, err := client.MoveMessage(context.Background(), "msg1", "archive")
+	if err != nil {
+		t.Fatalf("MoveMessage failed: %v", err)
+	}
+	if moved.ID != "msg1-moved" {
+		t.Errorf("Expected moved message ID, got %s", moved.ID)
+	}
+
+	if _, err := client.MoveMessage(context.Background(), "msg1", ""); err == nil {
+		t.Error("Expected error for missing destination")
+	}
+}
+
+func TestCopyMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/me/messages/msg1/copy" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["destinationId"] != "folder1" {
+			t.Errorf("Expected destinationId folder1, got %q", body["destinationId"])
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Message{ID: "msg2"})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	copied, err := client.CopyMessage(context.Background(), "msg1", "folder1")
+	if err != nil {
+		t.Fatalf("CopyMessage failed: %v", err)
+	}
+	if copied.ID != "msg2" {
+		t.Errorf("Expected the copy's ID, got %s", copied.ID)
+	}
+}
+
+func TestPermanentlyDeleteMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/me/messages/msg1/permanentDelete" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	if err := client.PermanentlyDeleteMessage(context.Background(), "msg1"); err != nil {
+		t.Errorf("PermanentlyDeleteMessage failed: %v", err)
+	}
+}
+
+func TestFlagMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/me/messages/msg1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Flag FollowupFlag `json:"flag"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Flag.FlagStatus != FlagStatusFlagged {
+			t.Errorf("Expected flagged status, got %q", body.Flag.FlagStatus)
+		}
+		if body.Flag.DueDateTime == nil || body.Flag.DueDateTime.DateTime != "2025-01-20T00:00:00" {
+			t.Errorf("Expected due date, got %+v", body.Flag.DueDateTime)
+		}
+		json.NewEncoder(w).Encode(Message{ID: "msg1"})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	flag := &FollowupFlag{
+		FlagStatus:    FlagStatusFlagged,
+		StartDateTime: &DateTimeTimeZone{DateTime: "2025-01-17T00:00:00", TimeZone: "UTC"},
+		DueDateTime:   &DateTimeTimeZone{DateTime: "2025-01-20T00:00:00", TimeZone: "UTC"},
+	}
+	if err := client.FlagMessage(context.Background(), "msg1", flag); err != nil {
+		t.Fatalf("FlagMessage failed: %v", err)
+	}
+
+	if err := client.FlagMessage(context.Background(), "", flag); err == nil {
+		t.Error("Expected error for missing message ID")
+	}
+}
+
+func TestDeferSend(t *testing.T) {
+	message := &Message{Subject: "Later"}
+	message.DeferSend(time.Date(2025, 1, 20, 9, 0, 0, 0, time.FixedZone("NZDT", 13*3600)))
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"singleValueExtendedProperties":[{"id":"SystemTime 0x3FEF","value":"2025-01-19T20:00:00Z"}]`
+	if !strings.Contains(string(data), want) {
+		t.Errorf("Expected deferred send property in %s", data)
+	}
+}
+
+func TestReplyToMessage(t *testing.T) {
+	tests := []struct {
+		replyAll bool
+		wantPath string
+	}{
+		{false, "/me/messages/msg1/reply"},
+		{true, "/me/messages/msg1/replyAll"},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != tt.wantPath {
+				t.Errorf("Expected path %s, got %s", tt.wantPath, r.URL.Path)
+			}
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["comment"] != "Thanks!" {
+				t.Errorf("Expected comment, got %q", body["comment"])
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}))
+
+		client := &Client{
+			httpClient: server.Client(),
+			baseURL:    server.URL,
+			tokens:     StaticToken("test-token"),
+		}
+
+		if err := client.ReplyToMessage(context.Background(), "msg1", "Thanks!", tt.replyAll); err != nil {
+			t.Errorf("ReplyToMessage failed: %v", err)
+		}
+		server.Close()
+	}
+}
+
+func TestCreateReply(t *testing.T) {
+	tests := []struct {
+		replyAll bool
+		wantPath string
+	}{
+		{false, "/me/messages/msg1/createReply"},
+		{true, "/me/messages/msg1/createReplyAll"},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != tt.wantPath {
+				t.Errorf("Expected POST %s, got %s %s", tt.wantPath, r.Method, r.URL.Path)
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"draft1","subject":"RE: Plans","isDraft":true,"conversationId":"conv1"}`)
+		}))
+
+		client := &Client{
+			httpClient: server.Client(),
+			baseURL:    server.URL,
+			tokens:     StaticToken("test-token"),
+		}
+
+		draft, err := client.CreateReply(context.Background(), "msg1", tt.replyAll)
+		if err != nil {
+			t.Fatalf("CreateReply failed: %v", err)
+		}
+		if draft.ID != "draft1" || !draft.IsDraft || draft.ConversationID != "conv1" {
+			t.Errorf("Unexpected draft %+v", draft)
+		}
+		server.Close()
+	}
+}
+
+func TestForwardMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Comment      string       `json:"comment"`
+			ToRecipients []*Recipient `json:"toRecipients"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.URL.Path {
+		case "/me/messages/msg1/forward":
+			if body.Comment != "FYI" || len(body.ToRecipients) != 2 || body.ToRecipients[1].EmailAddress.Address != "b@contoso.com" {
+				t.Errorf("Unexpected forward %+v", body)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		case "/me/messages/msg1/createForward":
+			if body.Comment != "FYI" || len(body.ToRecipients) != 2 {
+				t.Errorf("Unexpected createForward %+v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"draft1","subject":"FW: Plans","isDraft":true}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+	to := []*Recipient{
+		{EmailAddress: &EmailAddress{Address: "a@contoso.com"}},
+		{EmailAddress: &EmailAddress{Address: "b@contoso.com"}},
+	}
+
+	if err := client.ForwardMessage(context.Background(), "msg1", "FYI", to); err != nil {
+		t.Errorf("ForwardMessage failed: %v", err)
+	}
+	if err := client.ForwardMessage(context.Background(), "msg1", "FYI", nil); err == nil {
+		t.Error("Expected an error without recipients")
+	}
+
+	draft, err := client.CreateForward(context.Background(), "msg1", "FYI", to)
+	if err != nil {
+		t.Fatalf("CreateForward failed: %v", err)
+	}
+	if draft.ID != "draft1" || draft.Subject != "FW: Plans" {
+		t.Errorf("Unexpected draft %+v", draft)
+	}
+}
+
+func TestCreateAndUpdateDraft(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/me/messages":
+			if body["subject"] != "Plans" {
+				t.Errorf("Unexpected draft %v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"draft1","subject":"Plans","isDraft":true}`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/me/messages/draft1":
+			if cc, ok := body["ccRecipients"].([]interface{}); !ok || len(cc) != 0 || body["subject"] != nil {
+				t.Errorf("Expected only ccRecipients cleared, got %v", body)
+			}
+			fmt.Fprint(w, `{"id":"draft1","subject":"Plans","isDraft":true}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+	ctx := context.Background()
+
+	draft, err := client.CreateDraft(ctx, &Message{Subject: "Plans"})
+	if err != nil {
+		t.Fatalf("CreateDraft failed: %v", err)
+	}
+	if draft.ID != "draft1" || !draft.IsDraft {
+		t.Errorf("Unexpected draft %+v", draft)
+	}
+
+	if _, err := client.UpdateDraft(ctx, draft.ID, map[string]interface{}{"ccRecipients": []*Recipient{}}); err != nil {
+		t.Errorf("UpdateDraft failed: %v", err)
+	}
+	if _, err := client.UpdateDraft(ctx, "", &Message{Subject: "x"}); err == nil {
+		t.Error("Expected an error without a draft ID")
+	}
+}
+
+func TestSendDraft(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/me/messages/draft1/send" {
+			t.Errorf("Expected POST /me/messages/draft1/send, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	if err := client.SendDraft(context.Background(), "draft1"); err != nil {
+		t.Errorf("SendDraft failed: %v", err)
+	}
+	if err := client.SendDraft(context.Background(), ""); err == nil {
+		t.Error("Expected an error without a message ID")
+	}
+}
+
+func TestMessagesDelta(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Query().Get("$deltatoken") == "abc":
+			fmt.Fprint(w, `{"value":[{"id":"m3"}],"@odata.deltaLink":"next"}`)
+		case r.URL.Query().Get("page") == "2":
+			fmt.Fprintf(w, `{"value":[{"id":"m2"}],"@odata.deltaLink":"%s/me/mailFolders/inbox/messages/delta?$deltatoken=abc"}`, server.URL)
+		case r.URL.Path == "/me/mailFolders/inbox/messages/delta":
+			if r.URL.Query().Get("changeType") != "created" {
+				t.Errorf("Expected changeType=created, got %q", r.URL.RawQuery)
+			}
+			if filter := r.URL.Query().Get("$filter"); filter != "receivedDateTime ge 2026-01-02T03:04:05Z" {
+				t.Errorf("Unexpected filter %q", filter)
+			}
+			fmt.Fprintf(w, `{"value":[{"id":"m1"}],"@odata.nextLink":"%s/me/mailFolders/inbox/messages/delta?page=2"}`, server.URL)
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	delta, err := client.MessagesDelta(context.Background(), "", "", since)
+	if err != nil {
+		t.Fatalf("MessagesDelta failed: %v", err)
+	}
+	if len(delta.Messages) != 2 || delta.Messages[1].ID != "m2" {
+		t.Errorf("Expected both pages of messages, got %d", len(delta.Messages))
+	}
+
+	delta, err = client.MessagesDelta(context.Background(), "inbox", delta.DeltaLink, time.Time{})
+	if err != nil {
+		t.Fatalf("MessagesDelta with delta link failed: %v", err)
+	}
+	if len(delta.Messages) != 1 || delta.Messages[0].ID != "m3" || delta.DeltaLink != "next" {
+		t.Errorf("Unexpected delta round: %+v", delta)
+	}
+}
+
+func TestListUnreadMentions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if filter := r.URL.Query().Get("$filter"); filter != "mentionsPreview/isMentioned eq true and isRead eq false" {
+			t.Errorf("Unexpected filter %q", filter)
+		}
+		json.NewEncoder(w).Encode(MessageList{Value: []*Message{{ID: "m1", Subject: "@you"}}})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	messages, err := client.ListUnreadMentions(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("ListUnreadMentions failed: %v", err)
+	}
+	if len(messages) != 1 || messages[0].ID != "m1" {
+		t.Errorf("Unexpected messages: %v", messages)
+	}
+
+	if got := (&Client{baseURL: GraphAPIBaseURL}).beta().baseURL; got != GraphBetaBaseURL {
+		t.Errorf("Expected beta base URL, got %s", got)
+	}
+}
+
+func TestUpdateMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/me/messages/msg1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("If-Match"); got != `W/"CQAAABYAAAB"` {
+			t.Errorf("Expected If-Match, got %q", got)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["isRead"] != false || len(body) != 1 {
+			t.Errorf("Expected only isRead false, got %v", body)
+		}
+		json.NewEncoder(w).Encode(Message{ID: "msg1", Subject: "Hello", ETag: `W/"CQAAABYAAAC"`})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	updated, err := client.UpdateMessage(context.Background(), "msg1", map[string]interface{}{"isRead": false}, WithIfMatch(`W/"CQAAABYAAAB"`))
+	if err != nil {
+		t.Fatalf("UpdateMessage failed: %v", err)
+	}
+	if updated.Subject != "Hello" || updated.ETag != `W/"CQAAABYAAAC"` {
+		t.Errorf("Unexpected updated message %+v", updated)
+	}
+
+	if _, err := client.UpdateMessage(context.Background(), "", &Message{}); err == nil {
+		t.Error("Expected error for missing message ID")
+	}
+	if _, err := client.UpdateMessage(context.Background(), "msg1", nil); err == nil {
+		t.Error("Expected error for missing changes")
+	}
+}
@@ -0,0 +1,178 @@
+package libgo365
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MboxWriter writes messages to an mbox file in the mboxrd form (RFC 4155):
+// each message starts with a "From " line, lines that would be mistaken for
+// one are quoted with ">", and line endings are LF
+type MboxWriter struct {
+	w *bufio.Writer
+}
+
+// NewMboxWriter returns a writer that appends messages to w. Call Flush when
+// done.
+func NewMboxWriter(w io.Writer) *MboxWriter {
+	return &MboxWriter{w: bufio.NewWriter(w)}
+}
+
+// WriteMessage appends a message's MIME content. sender and received make
+// up its "From " line; an empty sender is written as MAILER-DAEMON.
+func (m *MboxWriter) WriteMessage(sender string, received time.Time, content []byte) error {
+	if sender == "" || strings.ContainsAny(sender, " \t\r\n") {
+		sender = "MAILER-DAEMON"
+	}
+	fmt.Fprintf(m.w, "From %s %s\n", sender, received.UTC().Format(time.ANSIC))
+
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	content = bytes.TrimSuffix(content, []byte("\n"))
+	for line := range bytes.SplitSeq(content, []byte("\n")) {
+		if bytes.HasPrefix(bytes.TrimLeft(line, ">"), []byte("From ")) {
+			m.w.WriteByte('>')
+		}
+		m.w.Write(line)
+		m.w.WriteByte('\n')
+	}
+	_, err := m.w.WriteString("\n")
+	return err
+}
+
+// Flush writes any buffered messages to the underlying writer
+func (m *MboxWriter) Flush() error {
+	return m.w.Flush()
+}
+
+// MboxExportOptions controls ExportMailFolderMbox
+type MboxExportOptions struct {
+	Concurrency int // Messages downloaded at once (default 1)
+
+	// Progress, if set, is called after each message is written with the
+	// number written so far
+	Progress func(exported int)
+}
+
+// mboxJob is a message being downloaded for an mbox export; done is closed
+// once content or err is set
+type mboxJob struct {
+	msg     *Message
+	content []byte
+	err     error
+	done    chan struct{}
+}
+
+// ExportMailFolderMbox writes every message in a folder, oldest first, to w
+// as an mbox file and returns how many were written. Messages are downloaded
+// opts.Concurrency at a time but written in order, with at most a few held
+// in memory at once. The export stops at the first message that fails.
+func (c *Client) ExportMailFolderMbox(ctx context.Context, folderID string, w io.Writer, opts *MboxExportOptions) (int, error) {
+	if folderID == "" {
+		return 0, fmt.Errorf("folder ID is required")
+	}
+	if opts == nil {
+		opts = &MboxExportOptions{}
+	}
+	workers := max(opts.Concurrency, 1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// queue holds the jobs in folder order, so it bounds how far downloads
+	// run ahead of writing
+	jobs := make(chan *mboxJob)
+	queue := make(chan *mboxJob, workers*2)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				var buf bytes.Buffer
+				if _, err := c.DownloadMessageMIME(ctx, job.msg.ID, &buf); err != nil {
+					job.err = fmt.Errorf("failed to download message %q: %w", job.msg.Subject, err)
+				}
+				job.content = buf.Bytes()
+				close(job.done)
+			}
+		}()
+	}
+
+	var listErr error
+	go func() {
+		defer close(queue)
+		defer close(jobs)
+		it := c.MessageIterator(&ListMessagesOptions{
+			FolderID: folderID,
+			OrderBy:  "receivedDateTime asc",
+			Select:   []string{"id", "subject", "from", "receivedDateTime"},
+		})
+		listErr = it.Iterate(ctx, func(msg *Message) error {
+			job := &mboxJob{msg: msg, done: make(chan struct{})}
+			select {
+			case queue <- job:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return nil
+		})
+	}()
+
+	mbox := NewMboxWriter(w)
+	exported := 0
+	var err error
+	for job := range queue {
+		select {
+		case <-job.done:
+		case <-ctx.Done():
+		}
+		if err != nil {
+			continue // Draining after a failure
+		}
+		if err = ctx.Err(); err != nil {
+			cancel()
+			continue
+		}
+		if err = job.err; err != nil {
+			cancel()
+			continue
+		}
+
+		sender := ""
+		if job.msg.From != nil && job.msg.From.EmailAddress != nil {
+			sender = job.msg.From.EmailAddress.Address
+		}
+		received := time.Unix(0, 0)
+		if job.msg.ReceivedDateTime != nil {
+			received = *job.msg.ReceivedDateTime
+		}
+		if err = mbox.WriteMessage(sender, received, job.content); err != nil {
+			cancel()
+			continue
+		}
+		exported++
+		if opts.Progress != nil {
+			opts.Progress(exported)
+		}
+	}
+	wg.Wait()
+
+	if err == nil && listErr != nil {
+		err = fmt.Errorf("failed to list messages: %w", listErr)
+	}
+	if flushErr := mbox.Flush(); err == nil {
+		err = flushErr
+	}
+	return exported, err
+}
@@ -0,0 +1,124 @@
+package libgo365
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMboxWriter(t *testing.T) {
+	var out strings.Builder
+	mbox := NewMboxWriter(&out)
+
+	received := time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC)
+	content := "Subject: Hi\r\n\r\nFrom here on\r\n>From quoted\r\nFromage\r\n"
+	if err := mbox.WriteMessage("alice@example.com", received, []byte(content)); err != nil {
+		t.Fatalf("WriteMessage failed: %v", err)
+	}
+	if err := mbox.WriteMessage("", received, []byte("Subject: No sender")); err != nil {
+		t.Fatalf("WriteMessage failed: %v", err)
+	}
+	if err := mbox.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	want := "From alice@example.com Tue Mar  5 09:30:00 2024\n" +
+		"Subject: Hi\n\n>From here on\n>>From quoted\nFromage\n\n" +
+		"From MAILER-DAEMON Tue Mar  5 09:30:00 2024\n" +
+		"Subject: No sender\n\n"
+	if out.String() != want {
+		t.Errorf("Unexpected mbox\n%q\nwant\n%q", out.String(), want)
+	}
+}
+
+func TestExportMailFolderMbox(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me/mailFolders/f1/messages":
+			if r.URL.Query().Get("$orderby") != "receivedDateTime asc" {
+				t.Errorf("Expected oldest first, got %q", r.URL.Query().Get("$orderby"))
+			}
+			if r.URL.Query().Get("$skiptoken") == "" {
+				fmt.Fprintf(w, `{"value":[{"id":"m1","from":{"emailAddress":{"address":"a@example.com"}},"receivedDateTime":"2024-01-01T00:00:00Z"},
+					{"id":"m2"}],"@odata.nextLink":"%s/me/mailFolders/f1/messages?$orderby=receivedDateTime+asc&$skiptoken=p2"}`, server.URL)
+				return
+			}
+			fmt.Fprint(w, `{"value":[{"id":"m3"}]}`)
+		case "/me/messages/m1/$value":
+			// The first message arrives last; it must still be written first
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprint(w, "Subject: one\r\n\r\nBody\r\n")
+		case "/me/messages/m2/$value":
+			fmt.Fprint(w, "Subject: two\r\n\r\nBody\r\n")
+		case "/me/messages/m3/$value":
+			fmt.Fprint(w, "Subject: three\r\n\r\nBody\r\n")
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	var out strings.Builder
+	var progress []int
+	n, err := client.ExportMailFolderMbox(context.Background(), "f1", &out, &MboxExportOptions{
+		Concurrency: 3,
+		Progress:    func(exported int) { progress = append(progress, exported) },
+	})
+	if err != nil {
+		t.Fatalf("ExportMailFolderMbox failed: %v", err)
+	}
+	if n != 3 || len(progress) != 3 || progress[2] != 3 {
+		t.Errorf("Expected 3 messages exported, got %d (progress %v)", n, progress)
+	}
+
+	mbox := out.String()
+	if !strings.HasPrefix(mbox, "From a@example.com Mon Jan  1 00:00:00 2024\nSubject: one\n") {
+		t.Errorf("Unexpected first message in\n%s", mbox)
+	}
+	one, two, three := strings.Index(mbox, "Subject: one"), strings.Index(mbox, "Subject: two"), strings.Index(mbox, "Subject: three")
+	if one > two || two > three || strings.Count(mbox, "\nFrom MAILER-DAEMON ") != 2 {
+		t.Errorf("Expected the messages in folder order in\n%s", mbox)
+	}
+}
+
+func TestExportMailFolderMboxFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me/mailFolders/f1/messages":
+			fmt.Fprint(w, `{"value":[{"id":"m1"},{"id":"m2","subject":"Gone"},{"id":"m3"}]}`)
+		case "/me/messages/m2/$value":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"ErrorItemNotFound","message":"Not found"}}`)
+		default:
+			fmt.Fprint(w, "Subject: ok\r\n\r\nBody\r\n")
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	var out strings.Builder
+	n, err := client.ExportMailFolderMbox(context.Background(), "f1", &out, &MboxExportOptions{Concurrency: 2})
+	if err == nil || !strings.Contains(err.Error(), `"Gone"`) {
+		t.Errorf("Expected the failed message reported, got %v", err)
+	}
+	if n != 1 || strings.Count(out.String(), "Subject: ok") != 1 {
+		t.Errorf("Expected only the message before the failure written, got %d:\n%s", n, out.String())
+	}
+}
@@ -0,0 +1,61 @@
+package libgo365
+
+import "net/http"
+
+// Middleware wraps the RoundTripper a client sends its requests through, to
+// log, measure, decorate or fake them. It returns a RoundTripper that does
+// its work and then calls next, or answers by itself without calling next.
+// The returned RoundTripper must be safe for concurrent use.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to an http.RoundTripper, for writing
+// Middleware
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Use adds middleware to every request the client sends; the first added
+// runs first. Middleware sees each attempt at a request, retries included,
+// with its Authorization header set. It runs before the client's logging,
+// compression, circuit breaker and rate limiting, so a response it fakes
+// skips them.
+func (c *Client) Use(mw ...Middleware) {
+	c.middleware = append(c.middleware, mw...)
+	c.buildTransport()
+}
+
+// buildTransport chains the client's own middleware and the caller's around
+// its transport: retries outermost, then the caller's middleware, logging,
+// compression, the circuit breaker and rate limiting
+func (c *Client) buildTransport() {
+	chain := []Middleware{func(next http.RoundTripper) http.RoundTripper {
+		return &retryTransport{base: next, client: c}
+	}}
+	chain = append(chain, c.middleware...)
+	chain = append(chain,
+		func(next http.RoundTripper) http.RoundTripper {
+			return &loggingTransport{base: next, client: c}
+		},
+		func(next http.RoundTripper) http.RoundTripper {
+			return &gzipTransport{base: next}
+		},
+		func(next http.RoundTripper) http.RoundTripper {
+			return &breakerTransport{base: next, client: c}
+		},
+		func(next http.RoundTripper) http.RoundTripper {
+			return &limitTransport{base: next, client: c}
+		},
+	)
+
+	rt := c.transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		rt = chain[i](rt)
+	}
+	c.httpClient = &http.Client{Transport: rt}
+}
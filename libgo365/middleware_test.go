@@ -0,0 +1,64 @@
+package libgo365
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// tagMiddleware records name on calls before passing the request on
+func tagMiddleware(name string, calls *[]string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			*calls = append(*calls, name+" "+req.Header.Get("Authorization"))
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func TestClientMiddleware(t *testing.T) {
+	shortenRetryDelay(t)
+	server, bodies := flakyServer(t, http.StatusServiceUnavailable, 1, "")
+	client := retryClient(server)
+
+	var calls []string
+	client.Use(tagMiddleware("first", &calls), tagMiddleware("second", &calls))
+	if _, err := client.Get(context.Background(), "/me"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	// Each attempt goes through both, in the order added, with the token
+	want := "first Bearer test-token,second Bearer test-token,first Bearer test-token,second Bearer test-token"
+	if strings.Join(calls, ",") != want || len(*bodies) != 2 {
+		t.Errorf("Expected both middleware on both attempts, got %v", calls)
+	}
+
+	// The transport set afterwards stays beneath the middleware
+	calls = nil
+	client.SetTransport(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`{"id":"fake"}`))}, nil
+	}))
+	body, err := client.Get(context.Background(), "/me/messages")
+	if err != nil || string(body) != `{"id":"fake"}` || len(calls) != 2 || len(*bodies) != 2 {
+		t.Errorf("Expected the fake transport beneath the middleware, got %s (%v) after %v", body, err, calls)
+	}
+}
+
+func TestClientMiddlewareFakesResponses(t *testing.T) {
+	client := NewClient(context.Background(), "test-token")
+	client.Use(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/v1.0/me/messages/1" || req.Method != http.MethodDelete {
+				t.Errorf("Unexpected request %s %s", req.Method, req.URL)
+			}
+			return &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`{"error":{"code":"ErrorItemNotFound"}}`))}, nil
+		})
+	})
+
+	err := client.Delete(context.Background(), "/me/messages/1")
+	if StatusCode(err) != http.StatusNotFound {
+		t.Errorf("Expected the faked 404, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package libgo365
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// RequestOption customizes a single request, such as adding a header or a
+// query parameter, without changing the client
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	header     http.Header
+	query      url.Values
+	apiVersion string
+}
+
+// WithHeader sets a request header
+func WithHeader(name, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set(name, value)
+	}
+}
+
+// WithQueryParam sets a query parameter, replacing any value already in the
+// path
+func WithQueryParam(name, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.query.Set(name, value)
+	}
+}
+
+// WithPrefer adds a preference to the Prefer header, e.g.
+// `outlook.timezone="Pacific/Auckland"` or "return=minimal". It can be given
+// more than once.
+func WithPrefer(preference string) RequestOption {
+	return func(o *requestOptions) {
+		if existing := o.header.Get("Prefer"); existing != "" {
+			preference = existing + ", " + preference
+		}
+		o.header.Set("Prefer", preference)
+	}
+}
+
+// WithTimeZone asks Graph to give event times in tz, an IANA or Windows
+// zone name such as "Pacific/Auckland", instead of UTC
+func WithTimeZone(tz string) RequestOption {
+	return WithPrefer(fmt.Sprintf("outlook.timezone=%q", tz))
+}
+
+// timeZoneOptions returns WithTimeZone(tz), or nothing if tz is empty
+func timeZoneOptions(tz string) []RequestOption {
+	if tz == "" {
+		return nil
+	}
+	return []RequestOption{WithTimeZone(tz)}
+}
+
+// WithIfMatch makes a Patch, Put or Delete apply only if the item still has
+// etag, the ETag field of the copy that was read. If someone changed it
+// since, Graph refuses with 412 and the error matches ErrPreconditionFailed,
+// rather than the change overwriting theirs.
+func WithIfMatch(etag string) RequestOption {
+	return WithHeader("If-Match", etag)
+}
+
+// WithAPIVersion sends the request to another Graph version, "v1.0" or
+// "beta". Clients pointed somewhere other than Graph (such as a test server)
+// ignore it.
+func WithAPIVersion(version string) RequestOption {
+	return func(o *requestOptions) {
+		o.apiVersion = version
+	}
+}
+
+// newRequestOptions applies opts in order
+func newRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{header: http.Header{}, query: url.Values{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// requestURL returns the full URL for path with the options applied
+func (c *Client) requestURL(path string, o *requestOptions) string {
+	base := c.baseURL
+	if o.apiVersion != "" && c.onGraph() {
+		base = GraphBaseURL(o.apiVersion)
+	}
+	if len(o.query) == 0 {
+		return base + path
+	}
+
+	path, rawQuery, _ := strings.Cut(path, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		query = url.Values{}
+	}
+	for name, values := range o.query {
+		query[name] = values
+	}
+	return base + path + "?" + query.Encode()
+}
+
+// apply sets the option headers on req
+func (o *requestOptions) apply(req *http.Request) {
+	for name, values := range o.header {
+		req.Header[name] = values
+	}
+}
+
+// cacheKey is the URL, plus any option headers since they can change the
+// response (a Prefer time zone, for example)
+func (o *requestOptions) cacheKey(url string) string {
+	if len(o.header) == 0 {
+		return url
+	}
+
+	names := make([]string, 0, len(o.header))
+	for name := range o.header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(url)
+	for _, name := range names {
+		b.WriteString("\n" + name + ": " + strings.Join(o.header[name], ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package libgo365
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestOptions(t *testing.T) {
+	var gotPrefer, gotHeader, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPrefer = r.Header.Get("Prefer")
+		gotHeader = r.Header.Get("ConsistencyLevel")
+		gotQuery = r.URL.RawQuery
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+
+	_, err := client.Get(context.Background(), "/me/events?$top=5",
+		WithPrefer(`outlook.timezone="Pacific/Auckland"`),
+		WithPrefer("odata.maxpagesize=5"),
+		WithHeader("ConsistencyLevel", "eventual"),
+		WithQueryParam("$top", "10"),
+		WithQueryParam("$count", "true"),
+	)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if gotPrefer != `outlook.timezone="Pacific/Auckland", odata.maxpagesize=5` {
+		t.Errorf("Unexpected Prefer header %q", gotPrefer)
+	}
+	if gotHeader != "eventual" {
+		t.Errorf("Unexpected ConsistencyLevel header %q", gotHeader)
+	}
+	if gotQuery != "%24count=true&%24top=10" {
+		t.Errorf("Unexpected query %q", gotQuery)
+	}
+
+	// Options don't stick to the client
+	if _, err := client.Post(context.Background(), "/me/events", map[string]string{}); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	if gotPrefer != "" || gotQuery != "" {
+		t.Errorf("Options leaked into a later request: %q %q", gotPrefer, gotQuery)
+	}
+}
+
+func TestWithAPIVersion(t *testing.T) {
+	client := NewClient(context.Background(), "test-token")
+	o := newRequestOptions([]RequestOption{WithAPIVersion("beta")})
+
+	if got := client.requestURL("/me", o); got != GraphBetaBaseURL+"/me" {
+		t.Errorf("Expected the beta endpoint, got %q", got)
+	}
+	if got := client.beta().requestURL("/me", newRequestOptions([]RequestOption{WithAPIVersion("v1.0")})); got != GraphAPIBaseURL+"/me" {
+		t.Errorf("Expected the v1.0 endpoint, got %q", got)
+	}
+
+	// Test servers are left alone
+	client.baseURL = "http://127.0.0.1:8080"
+	if got := client.requestURL("/me", o); got != "http://127.0.0.1:8080/me" {
+		t.Errorf("Expected the test server, got %q", got)
+	}
+}
+
+func TestSetAPIVersion(t *testing.T) {
+	client := NewClient(context.Background(), "test-token")
+	if client.APIVersion() != APIVersionV1 {
+		t.Errorf("Expected new clients to call v1.0, got %q", client.APIVersion())
+	}
+
+	if err := client.SetAPIVersion(APIVersionBeta); err != nil {
+		t.Fatalf("SetAPIVersion failed: %v", err)
+	}
+	if got := client.requestURL("/me/messages", newRequestOptions(nil)); got != GraphBetaBaseURL+"/me/messages" {
+		t.Errorf("Expected the beta endpoint, got %q", got)
+	}
+	if client.APIVersion() != APIVersionBeta {
+		t.Errorf("Expected beta, got %q", client.APIVersion())
+	}
+	if err := client.SetAPIVersion("v2.0"); err == nil || client.APIVersion() != APIVersionBeta {
+		t.Errorf("Expected an unknown version to be rejected, got %v", err)
+	}
+
+	// Test servers are left alone
+	client.baseURL = "http://127.0.0.1:8080"
+	if err := client.SetAPIVersion(APIVersionV1); err != nil || client.baseURL != "http://127.0.0.1:8080" || client.APIVersion() != "" {
+		t.Errorf("Expected the test server to be kept, got %q (%v)", client.baseURL, err)
+	}
+}
+
+func TestRequestOptionsCacheKey(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"value": []}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+	client.SetCache(NewResponseCache(t.TempDir(), time.Minute))
+
+	ctx := context.Background()
+	client.Get(ctx, "/me/events")
+	client.Get(ctx, "/me/events")
+	client.Get(ctx, "/me/events", WithPrefer(`outlook.timezone="UTC"`))
+	client.Get(ctx, "/me/events", WithPrefer(`outlook.timezone="UTC"`))
+	if requests != 2 {
+		t.Errorf("Expected 2 requests (one per Prefer), got %d", requests)
+	}
+}
+
+func TestWithIfMatch(t *testing.T) {
+	const etag = `W/"CQAAABYAAAB"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"id": "msg1", "@odata.etag": "W/\"CQAAABYAAAB\""}`))
+			return
+		}
+		if r.Header.Get("If-Match") != etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte(`{"error": {"code": "ErrorIrresolvableConflict", "message": "The change key passed in the request does not match the current change key for the item."}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL, tokens: StaticToken("test-token")}
+	ctx := context.Background()
+
+	msg, err := client.GetMessage(ctx, "msg1")
+	if err != nil {
+		t.Fatalf("GetMessage failed: %v", err)
+	}
+	if msg.ETag != etag {
+		t.Fatalf("Expected ETag %s, got %q", etag, msg.ETag)
+	}
+
+	if err := client.DeleteMessage(ctx, "msg1", WithIfMatch(msg.ETag)); err != nil {
+		t.Errorf("Expected the delete to succeed, got %v", err)
+	}
+	err = client.FlagMessage(ctx, "msg1", &FollowupFlag{FlagStatus: FlagStatusFlagged}, WithIfMatch(`W/"stale"`))
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed for a stale ETag, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// OrgContact represents an organizational contact in the directory (GAL-only contacts
+// such as external partners or synced shared mailboxes)
+type OrgContact struct {
+	ID             string                   `json:"id,omitempty"`
+	DisplayName    string                   `json:"displayName,omitempty"`
+	GivenName      string                   `json:"givenName,omitempty"`
+	Surname        string                   `json:"surname,omitempty"`
+	Mail           string                   `json:"mail,omitempty"`
+	MailNickname   string                   `json:"mailNickname,omitempty"`
+	CompanyName    string                   `json:"companyName,omitempty"`
+	Department     string                   `json:"department,omitempty"`
+	JobTitle       string                   `json:"jobTitle,omitempty"`
+	ProxyAddresses []string                 `json:"proxyAddresses,omitempty"`
+	Phones         []*Phone                 `json:"phones,omitempty"`
+	Addresses      []*PhysicalOfficeAddress `json:"addresses,omitempty"`
+}
+
+// Phone represents a phone number
+type Phone struct {
+	Type   string `json:"type,omitempty"` // business, mobile, businessFax, ...
+	Number string `json:"number,omitempty"`
+}
+
+// PhysicalOfficeAddress represents an office address of a contact
+type PhysicalOfficeAddress struct {
+	Street          string `json:"street,omitempty"`
+	City            string `json:"city,omitempty"`
+	State           string `json:"state,omitempty"`
+	PostalCode      string `json:"postalCode,omitempty"`
+	CountryOrRegion string `json:"countryOrRegion,omitempty"`
+	OfficeLocation  string `json:"officeLocation,omitempty"`
+}
+
+// OrgContactList represents a list of org contacts returned by Graph API
+type OrgContactList struct {
+	Value    []*OrgContact `json:"value"`
+	NextLink string        `json:"@odata.nextLink,omitempty"`
+}
+
+// ListOrgContactsOptions represents options for listing org contacts
+type ListOrgContactsOptions struct {
+	Query     string // Prefix match on display name, given name, surname, or mail
+	Top       int
+	PageToken string
+}
+
+// ListOrgContactsResponse represents the response from ListOrgContacts with pagination info
+type ListOrgContactsResponse struct {
+	Contacts      []*OrgContact
+	Count         int
+	HasMore       bool
+	NextPageToken string
+}
+
+// ListOrgContacts retrieves organizational contacts from the directory
+func (c *Client) ListOrgContacts(ctx context.Context, opts *ListOrgContactsOptions) (*ListOrgContactsResponse, error) {
+	params := url.Values{}
+	if opts != nil {
+		if opts.Query != "" {
+			q := escapeODataString(opts.Query)
+			filters := []string{}
+			for _, prop := range []string{"displayName", "givenName", "surname", "mail"} {
+				filters = append(filters, fmt.Sprintf("startswith(%s,'%s')", prop, q))
+			}
+			params.Set("$filter", strings.Join(filters, " or "))
+		}
+		if opts.Top > 0 {
+			params.Set("$top", fmt.Sprintf("%d", opts.Top))
+		}
+		if opts.PageToken != "" {
+			params.Set("$skiptoken", opts.PageToken)
+		}
+	}
+
+	path := "/contacts"
+	if len(params) > 0 {
+		path = path + "?" + params.Encode()
+	}
+
+	data, err := c.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var contactList OrgContactList
+	if err := json.Unmarshal(data, &contactList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal org contacts: %w", err)
+	}
+
+	return &ListOrgContactsResponse{
+		Contacts:      contactList.Value,
+		Count:         len(contactList.Value),
+		HasMore:       contactList.NextLink != "",
+		NextPageToken: ExtractPageToken(contactList.NextLink),
+	}, nil
+}
+
+// GetOrgContact retrieves an organizational contact by ID
+func (c *Client) GetOrgContact(ctx context.Context, contactID string) (*OrgContact, error) {
+	if contactID == "" {
+		return nil, fmt.Errorf("contact ID is required")
+	}
+
+	data, err := c.Get(ctx, fmt.Sprintf("/contacts/%s", contactID))
+	if err != nil {
+		return nil, err
+	}
+
+	var contact OrgContact
+	if err := json.Unmarshal(data, &contact); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal org contact: %w", err)
+	}
+
+	return &contact, nil
+}
@@ -0,0 +1,70 @@
+package libgo365
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListOrgContacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/contacts" {
+			t.Errorf("Expected path /contacts, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("$filter") != "" {
+			t.Errorf("Expected no filter, got %s", r.URL.Query().Get("$filter"))
+		}
+		w.Write([]byte(`{
+			"value": [{"id": "c1", "displayName": "Partner Support", "mail": "support@partner.com", "companyName": "Partner Ltd"}],
+			"@odata.nextLink": "https://graph.microsoft.com/v1.0/contacts?$skiptoken=next1"
+		}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	resp, err := client.ListOrgContacts(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListOrgContacts failed: %v", err)
+	}
+	if len(resp.Contacts) != 1 || resp.Contacts[0].CompanyName != "Partner Ltd" {
+		t.Errorf("Unexpected contacts: %+v", resp.Contacts)
+	}
+	if resp.NextPageToken != "next1" {
+		t.Errorf("Expected next page token 'next1', got '%s'", resp.NextPageToken)
+	}
+}
+
+func TestListOrgContactsSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("$filter")
+		if !strings.Contains(filter, "startswith(displayName,'o''brien')") {
+			t.Errorf("Expected escaped displayName filter, got %s", filter)
+		}
+		if !strings.Contains(filter, "startswith(mail,'o''brien')") {
+			t.Errorf("Expected mail filter, got %s", filter)
+		}
+		w.Write([]byte(`{"value": []}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	resp, err := client.ListOrgContacts(context.Background(), &ListOrgContactsOptions{Query: "o'brien"})
+	if err != nil {
+		t.Fatalf("ListOrgContacts failed: %v", err)
+	}
+	if resp.HasMore {
+		t.Error("Expected no more results")
+	}
+}
@@ -0,0 +1,85 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// pageLink is the part of a collection page that says where the next one is
+type pageLink struct {
+	NextLink string `json:"@odata.nextLink,omitempty"`
+}
+
+// fetchedPage is a page fetched ahead of the caller
+type fetchedPage struct {
+	data     []byte
+	nextLink string
+	err      error
+}
+
+// GetPages fetches path and every page after it, following @odata.nextLink,
+// and calls fn with each raw page in order. While fn works on one page the
+// next is already being fetched, never more than one page ahead, so long
+// exports spend less time waiting on Graph. If fn returns an error or ctx is
+// cancelled, the read-ahead request is cancelled and GetPages returns the
+// error. opts apply to every page.
+func (c *Client) GetPages(ctx context.Context, path string, fn func(page []byte) error, opts ...RequestOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	page := c.fetchPage(ctx, path, opts)
+	for {
+		if page.err != nil {
+			return page.err
+		}
+
+		var next chan fetchedPage
+		if page.nextLink != "" {
+			// Buffered so the fetch finishes even if we stop early
+			next = make(chan fetchedPage, 1)
+			go func(link string) {
+				next <- c.fetchPage(ctx, link, opts)
+			}(page.nextLink)
+		}
+
+		if err := fn(page.data); err != nil {
+			return err
+		}
+		if next == nil {
+			return nil
+		}
+
+		select {
+		case page = <-next:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// fetchPage gets one page
+func (c *Client) fetchPage(ctx context.Context, path string, opts []RequestOption) fetchedPage {
+	data, err := c.Get(ctx, c.relativePath(path), opts...)
+	if err != nil {
+		return fetchedPage{err: err}
+	}
+
+	var link pageLink
+	if err := json.Unmarshal(data, &link); err != nil {
+		return fetchedPage{err: fmt.Errorf("failed to unmarshal page: %w", err)}
+	}
+	return fetchedPage{data: data, nextLink: link.NextLink}
+}
+
+// relativePath trims the base URL off a next or delta link, which Graph
+// returns as an absolute URL
+func (c *Client) relativePath(link string) string {
+	for _, base := range []string{c.baseURL, GraphAPIBaseURL, GraphBetaBaseURL} {
+		if strings.HasPrefix(link, base+"/") {
+			return strings.TrimPrefix(link, base)
+		}
+	}
+	return link
+}
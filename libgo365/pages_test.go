@@ -0,0 +1,152 @@
+package libgo365
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pagedServer serves /items in pages 1 to last, reporting each request on
+// requested
+func pagedServer(last int, requested chan<- int) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if requested != nil {
+			requested <- page
+		}
+		next := ""
+		if page < last {
+			next = fmt.Sprintf(`, "@odata.nextLink": "%s/items?page=%d"`, server.URL, page+1)
+		}
+		fmt.Fprintf(w, `{"value": [{"id": "item%d"}]%s}`, page, next)
+	}))
+	return server
+}
+
+func TestGetPages(t *testing.T) {
+	server := pagedServer(3, nil)
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	var pages []string
+	err := client.GetPages(context.Background(), "/items", func(page []byte) error {
+		pages = append(pages, string(page))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GetPages failed: %v", err)
+	}
+	if len(pages) != 3 {
+		t.Fatalf("Expected 3 pages, got %d", len(pages))
+	}
+	for i, page := range pages {
+		if want := fmt.Sprintf(`"item%d"`, i+1); !strings.Contains(page, want) {
+			t.Errorf("Page %d = %s, want it to contain %s", i+1, page, want)
+		}
+	}
+}
+
+func TestGetPagesReadsAhead(t *testing.T) {
+	requested := make(chan int, 10)
+	server := pagedServer(2, requested)
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	calls := 0
+	err := client.GetPages(context.Background(), "/items", func(page []byte) error {
+		calls++
+		if calls > 1 {
+			return nil
+		}
+		<-requested // Page 1
+		// Page 2 should be requested while page 1 is still being handled
+		select {
+		case page := <-requested:
+			if page != 2 {
+				t.Errorf("Expected page 2 to be fetched ahead, got page %d", page)
+			}
+		case <-time.After(2 * time.Second):
+			t.Error("Page 2 was not fetched while page 1 was being handled")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GetPages failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 pages, got %d", calls)
+	}
+}
+
+func TestGetPagesStopsOnError(t *testing.T) {
+	requested := make(chan int, 10)
+	server := pagedServer(10, requested)
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := client.GetPages(context.Background(), "/items", func(page []byte) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected the callback's error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+
+	// At most the first page and one page ahead
+	time.Sleep(50 * time.Millisecond)
+	if n := len(requested); n > 2 {
+		t.Errorf("Expected at most 2 requests, got %d", n)
+	}
+}
+
+func TestGetPagesHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": "ResourceNotFound", "message": "Not found"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	err := client.GetPages(context.Background(), "/items", func(page []byte) error {
+		t.Error("Callback should not be called")
+		return nil
+	})
+	if StatusCode(err) != http.StatusNotFound {
+		t.Errorf("Expected a 404 error, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// PluginProtocolVersion is the plugin protocol go365 speaks. Version 1 passed
+// nothing but arguments; version 2 hands plugins a PluginContext.
+const PluginProtocolVersion = 2
+
+// Environment variables go365 sets when running a go365-* plugin
+const (
+	EnvPluginProtocol  = "GO365_PLUGIN_PROTOCOL"
+	EnvPluginHandshake = "GO365_PLUGIN_HANDSHAKE" // "stdin" when the context is sent on stdin instead
+	EnvAccessToken     = "GO365_ACCESS_TOKEN"
+	EnvGraphURL        = "GO365_GRAPH_URL"
+	EnvProfile         = "GO365_PROFILE"
+	EnvOutput          = "GO365_OUTPUT"
+	EnvNoColor         = "GO365_NO_COLOR"
+)
+
+// HandshakeStdin is the handshake mode that writes the context as one JSON line
+// on the plugin's stdin, keeping the access token out of its environment
+const HandshakeStdin = "stdin"
+
+// PluginContext is what go365 passes to plugins so they can call Graph with
+// the user's session instead of running their own login
+type PluginContext struct {
+	Protocol    int    `json:"protocol"`
+	AccessToken string `json:"accessToken,omitempty"` // Empty when the user is not logged in
+	GraphURL    string `json:"graphUrl"`
+	Profile     string `json:"profile,omitempty"` // "default", or the project config file in effect
+	Output      string `json:"output,omitempty"`  // Preferred output format (json, yaml, table), empty for text
+	NoColor     bool   `json:"noColor,omitempty"`
+}
+
+// Environ returns the context as environment variables. With includeToken
+// false the access token is left out.
+func (pc *PluginContext) Environ(includeToken bool) []string {
+	env := []string{
+		EnvPluginProtocol + "=" + strconv.Itoa(pc.Protocol),
+		EnvGraphURL + "=" + pc.GraphURL,
+		EnvProfile + "=" + pc.Profile,
+		EnvOutput + "=" + pc.Output,
+	}
+	if includeToken && pc.AccessToken != "" {
+		env = append(env, EnvAccessToken+"="+pc.AccessToken)
+	}
+	if pc.NoColor {
+		env = append(env, EnvNoColor+"=1")
+	}
+	return env
+}
+
+// WriteHandshake writes the context as a single JSON line
+func (pc *PluginContext) WriteHandshake(w io.Writer) error {
+	data, err := json.Marshal(pc)
+	if err != nil {
+		return fmt.Errorf("failed to encode plugin handshake: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// NewClient creates a Graph client from the context's token and base URL
+func (pc *PluginContext) NewClient(ctx context.Context) *Client {
+	c := NewClient(ctx, pc.AccessToken)
+	if pc.GraphURL != "" {
+		c.baseURL = pc.GraphURL
+	}
+	return c
+}
+
+// ReadPluginHandshake reads a context written by WriteHandshake. It reads one
+// byte at a time so nothing after the handshake line is consumed.
+func ReadPluginHandshake(r io.Reader) (*PluginContext, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			line = append(line, buf[0])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plugin handshake: %w", err)
+		}
+	}
+
+	var pc PluginContext
+	if err := json.Unmarshal(line, &pc); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin handshake: %w", err)
+	}
+	return &pc, nil
+}
+
+// ErrNotPlugin is returned by LoadPluginContext when the process was not
+// started by go365
+var ErrNotPlugin = errors.New("not running as a go365 plugin")
+
+// LoadPluginContext returns the context go365 passed to this plugin, reading
+// the stdin handshake if that mode was used
+func LoadPluginContext() (*PluginContext, error) {
+	protocol, err := strconv.Atoi(os.Getenv(EnvPluginProtocol))
+	if err != nil || protocol < 2 {
+		return nil, ErrNotPlugin
+	}
+	if os.Getenv(EnvPluginHandshake) == HandshakeStdin {
+		return ReadPluginHandshake(os.Stdin)
+	}
+
+	return &PluginContext{
+		Protocol:    protocol,
+		AccessToken: os.Getenv(EnvAccessToken),
+		GraphURL:    os.Getenv(EnvGraphURL),
+		Profile:     os.Getenv(EnvProfile),
+		Output:      os.Getenv(EnvOutput),
+		NoColor:     os.Getenv(EnvNoColor) != "",
+	}, nil
+}
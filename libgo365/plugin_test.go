@@ -0,0 +1,88 @@
+package libgo365
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPluginHandshakeRoundTrip(t *testing.T) {
+	pc := &PluginContext{
+		Protocol:    PluginProtocolVersion,
+		AccessToken: "token",
+		GraphURL:    GraphAPIBaseURL,
+		Profile:     "default",
+		Output:      "json",
+		NoColor:     true,
+	}
+
+	var buf bytes.Buffer
+	if err := pc.WriteHandshake(&buf); err != nil {
+		t.Fatalf("WriteHandshake failed: %v", err)
+	}
+	buf.WriteString("payload for the plugin\n")
+
+	got, err := ReadPluginHandshake(&buf)
+	if err != nil {
+		t.Fatalf("ReadPluginHandshake failed: %v", err)
+	}
+	if *got != *pc {
+		t.Errorf("Expected %+v, got %+v", pc, got)
+	}
+
+	// Input after the handshake line must be left for the plugin
+	rest, _ := io.ReadAll(&buf)
+	if string(rest) != "payload for the plugin\n" {
+		t.Errorf("Handshake consumed plugin input, left %q", rest)
+	}
+}
+
+func TestLoadPluginContextFromEnv(t *testing.T) {
+	t.Setenv(EnvPluginProtocol, "")
+	if _, err := LoadPluginContext(); err != ErrNotPlugin {
+		t.Errorf("Expected ErrNotPlugin without %s, got %v", EnvPluginProtocol, err)
+	}
+
+	pc := &PluginContext{Protocol: 2, AccessToken: "token", GraphURL: "https://example.test", Profile: "default", Output: "yaml"}
+	for _, kv := range pc.Environ(true) {
+		name, value, _ := strings.Cut(kv, "=")
+		t.Setenv(name, value)
+	}
+	t.Setenv(EnvPluginHandshake, "")
+
+	got, err := LoadPluginContext()
+	if err != nil {
+		t.Fatalf("LoadPluginContext failed: %v", err)
+	}
+	if *got != *pc {
+		t.Errorf("Expected %+v, got %+v", pc, got)
+	}
+}
+
+func TestPluginEnvironOmitsToken(t *testing.T) {
+	pc := &PluginContext{Protocol: 2, AccessToken: "secret-token", GraphURL: GraphAPIBaseURL}
+	for _, kv := range pc.Environ(false) {
+		if strings.Contains(kv, "secret-token") {
+			t.Errorf("Token leaked into environment: %s", kv)
+		}
+	}
+}
+
+func TestPluginContextNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer plugin-token" {
+			t.Errorf("Expected plugin token, got %q", got)
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	pc := &PluginContext{Protocol: 2, AccessToken: "plugin-token", GraphURL: server.URL}
+	if _, err := pc.NewClient(context.Background()).Get(context.Background(), "/me"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package libgo365
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query builds the OData query string of a Graph request: $select,
+// $filter, $orderby, $expand, $top, $search and the rest. Values given to
+// Filterf and Search are quoted and escaped, so text from users can't change
+// the meaning of the query. Methods return the query for chaining:
+//
+//	q := libgo365.NewQuery().
+//		Select("id", "subject").
+//		Filterf("from/emailAddress/address eq %v", addr).
+//		OrderBy("receivedDateTime desc").
+//		Top(25)
+//	data, err := client.Get(ctx, q.Path("/me/messages"))
+type Query struct {
+	selects []string
+	expands []string
+	filters []string
+	orderBy []string
+	search  string
+	top     int
+	skip    int
+	token   string
+	count   bool
+	params  url.Values
+}
+
+// NewQuery returns an empty query
+func NewQuery() *Query {
+	return &Query{params: url.Values{}}
+}
+
+// Select adds properties to return; by default Graph returns all of them
+func (q *Query) Select(fields ...string) *Query {
+	q.selects = append(q.selects, fields...)
+	return q
+}
+
+// Expand adds navigation properties to return inline, such as
+// "attachments" or "sessions($expand=segments)"
+func (q *Query) Expand(fields ...string) *Query {
+	q.expands = append(q.expands, fields...)
+	return q
+}
+
+// Filter adds a filter expression, which must already be valid OData; all
+// the filters added must match. Prefer Filterf for expressions with values.
+func (q *Query) Filter(expr string) *Query {
+	if expr != "" {
+		q.filters = append(q.filters, expr)
+	}
+	return q
+}
+
+// Filterf adds a filter expression like Filter, formatting each arg as an
+// OData literal (see ODataLiteral) before substituting it, so use %v or %s
+// for them. Filterf("mail eq %v", addr) quotes addr and doubles any quote
+// in it.
+func (q *Query) Filterf(format string, args ...any) *Query {
+	literals := make([]any, len(args))
+	for i, arg := range args {
+		literals[i] = ODataLiteral(arg)
+	}
+	return q.Filter(fmt.Sprintf(format, literals...))
+}
+
+// OrderBy adds sort keys, each a property optionally followed by "desc",
+// such as "receivedDateTime desc"
+func (q *Query) OrderBy(fields ...string) *Query {
+	q.orderBy = append(q.orderBy, fields...)
+	return q
+}
+
+// Search sets $search to terms, quoted as Graph requires. Graph doesn't
+// allow $search with $filter or $orderby on messages.
+func (q *Query) Search(terms string) *Query {
+	q.search = terms
+	return q
+}
+
+// Top sets the page size; zero leaves it to Graph
+func (q *Query) Top(n int) *Query {
+	q.top = n
+	return q
+}
+
+// Skip sets how many items to skip, for collections paged by offset
+func (q *Query) Skip(n int) *Query {
+	q.skip = n
+	return q
+}
+
+// SkipToken sets the $skiptoken from a previous page's nextLink
+func (q *Query) SkipToken(token string) *Query {
+	q.token = token
+	return q
+}
+
+// Count asks Graph to include the total number of matching items
+func (q *Query) Count(count bool) *Query {
+	q.count = count
+	return q
+}
+
+// Param sets any other query parameter, such as calendarView's
+// startDateTime, replacing an earlier value
+func (q *Query) Param(name, value string) *Query {
+	q.params.Set(name, value)
+	return q
+}
+
+// Values returns the query's parameters
+func (q *Query) Values() url.Values {
+	values := url.Values{}
+	for name, v := range q.params {
+		values[name] = append([]string(nil), v...)
+	}
+	if len(q.selects) > 0 {
+		values.Set("$select", strings.Join(q.selects, ","))
+	}
+	if len(q.expands) > 0 {
+		values.Set("$expand", strings.Join(q.expands, ","))
+	}
+	if len(q.filters) > 0 {
+		values.Set("$filter", joinFilters(q.filters))
+	}
+	if len(q.orderBy) > 0 {
+		values.Set("$orderby", strings.Join(q.orderBy, ","))
+	}
+	if q.search != "" {
+		values.Set("$search", quoteSearch(q.search))
+	}
+	if q.top > 0 {
+		values.Set("$top", strconv.Itoa(q.top))
+	}
+	if q.skip > 0 {
+		values.Set("$skip", strconv.Itoa(q.skip))
+	}
+	if q.token != "" {
+		values.Set("$skiptoken", q.token)
+	}
+	if q.count {
+		values.Set("$count", "true")
+	}
+	return values
+}
+
+// Encode returns the escaped query string, without a leading "?"
+func (q *Query) Encode() string {
+	return q.Values().Encode()
+}
+
+// Path appends the query string to path, or returns path alone if the query
+// is empty
+func (q *Query) Path(path string) string {
+	if encoded := q.Encode(); encoded != "" {
+		return path + "?" + encoded
+	}
+	return path
+}
+
+// WithQuery sets the query's parameters on a request, replacing any of the
+// same name already in the path
+func WithQuery(q *Query) RequestOption {
+	return func(o *requestOptions) {
+		for name, values := range q.Values() {
+			o.query[name] = values
+		}
+	}
+}
+
+// ODataLiteral formats v as an OData literal for a $filter: strings (and
+// string types such as Importance) quoted with embedded quotes doubled,
+// times in UTC as RFC 3339, numbers and booleans as they are, and nil as
+// null
+func ODataLiteral(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "'" + escapeODataString(v) + "'"
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return "null"
+		}
+		return v.UTC().Format(time.RFC3339)
+	case fmt.Stringer:
+		return "'" + escapeODataString(v.String()) + "'"
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return "'" + escapeODataString(rv.String()) + "'"
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v)
+	}
+	return "'" + escapeODataString(fmt.Sprint(v)) + "'"
+}
+
+// escapeODataString escapes a value for use inside a single-quoted OData string literal
+func escapeODataString(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+// joinFilters ands the filters together, bracketing any that contain an
+// "or" so it can't swallow its neighbors
+func joinFilters(filters []string) string {
+	if len(filters) == 1 {
+		return filters[0]
+	}
+	parts := make([]string, len(filters))
+	for i, f := range filters {
+		parts[i] = f
+		if strings.Contains(strings.ToLower(f), " or ") {
+			parts[i] = "(" + f + ")"
+		}
+	}
+	return strings.Join(parts, " and ")
+}
+
+// quoteSearch puts $search terms in the double quotes Graph expects,
+// escaping quotes and backslashes inside them
+func quoteSearch(terms string) string {
+	terms = strings.ReplaceAll(terms, `\`, `\\`)
+	return `"` + strings.ReplaceAll(terms, `"`, `\"`) + `"`
+}
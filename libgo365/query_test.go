@@ -0,0 +1,102 @@
+package libgo365
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	q := NewQuery().
+		Select("id", "subject").
+		Select("from").
+		Filterf("from/emailAddress/address eq %v", "o'brien@contoso.com").
+		Filter("isRead eq false or importance eq 'high'").
+		OrderBy("receivedDateTime desc").
+		Expand("attachments").
+		Top(25).
+		Count(true).
+		Param("changeType", "created")
+
+	values := q.Values()
+	want := map[string]string{
+		"$select":    "id,subject,from",
+		"$filter":    "from/emailAddress/address eq 'o''brien@contoso.com' and (isRead eq false or importance eq 'high')",
+		"$orderby":   "receivedDateTime desc",
+		"$expand":    "attachments",
+		"$top":       "25",
+		"$count":     "true",
+		"changeType": "created",
+	}
+	for name, value := range want {
+		if got := values.Get(name); got != value {
+			t.Errorf("Expected %s=%q, got %q", name, value, got)
+		}
+	}
+	if len(values) != len(want) {
+		t.Errorf("Expected %d parameters, got %v", len(want), values)
+	}
+
+	// A single filter isn't bracketed, and empty filters are dropped
+	if got := NewQuery().Filter("").Filter("a eq 1 or b eq 2").Values().Get("$filter"); got != "a eq 1 or b eq 2" {
+		t.Errorf("Unexpected single filter %q", got)
+	}
+
+	if got := NewQuery().Path("/me/messages"); got != "/me/messages" {
+		t.Errorf("Expected an empty query to leave the path alone, got %q", got)
+	}
+	if got := NewQuery().Top(5).SkipToken("abc").Path("/me/messages"); got != "/me/messages?%24skiptoken=abc&%24top=5" {
+		t.Errorf("Unexpected path %q", got)
+	}
+}
+
+func TestQuerySearch(t *testing.T) {
+	got := NewQuery().Search(`budget "Q3" \ draft`).Values().Get("$search")
+	if want := `"budget \"Q3\" \\ draft"`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestODataLiteral(t *testing.T) {
+	when := time.Date(2026, 1, 2, 16, 4, 5, 0, time.FixedZone("NZDT", 13*3600))
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"it's", "'it''s'"},
+		{ImportanceHigh, "'high'"},
+		{when, "2026-01-02T03:04:05Z"},
+		{&when, "2026-01-02T03:04:05Z"},
+		{(*time.Time)(nil), "null"},
+		{nil, "null"},
+		{true, "true"},
+		{42, "42"},
+		{1.5, "1.5"},
+	}
+	for _, tt := range tests {
+		if got := ODataLiteral(tt.value); got != tt.want {
+			t.Errorf("ODataLiteral(%#v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWithQuery(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Write([]byte(`{"value": []}`))
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL, tokens: StaticToken("test-token")}
+	_, err := client.Get(context.Background(), "/me/contacts?$top=5",
+		WithQuery(NewQuery().Top(10).Filterf("surname eq %v", "Smith")))
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if gotQuery != "%24filter=surname+eq+%27Smith%27&%24top=10" {
+		t.Errorf("Unexpected query %q", gotQuery)
+	}
+}
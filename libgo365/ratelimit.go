@@ -0,0 +1,180 @@
+package libgo365
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultThrottleBackoff is how long to pause after a 429 without Retry-After
+	defaultThrottleBackoff = 5 * time.Second
+
+	// throttledStartRate is the rate an unlimited limiter drops to after a 429
+	throttledStartRate = 8.0
+
+	// minThrottledRate is the slowest the limiter backs off to, in requests/s
+	minThrottledRate = 0.5
+
+	// recoveryStep is how much the rate grows back after each success
+	recoveryStep = 0.25
+
+	// unlimitedRecoveryRate is where an originally unlimited limiter stops
+	// pacing again
+	unlimitedRecoveryRate = 32.0
+)
+
+// RateLimiter paces requests shared by everything using a client. It spaces
+// requests to at most the configured rate, pauses everyone when Graph throttles
+// (honoring Retry-After), halves the rate, and then recovers it gradually.
+type RateLimiter struct {
+	mu          sync.Mutex
+	maxRate     float64 // Configured requests per second, 0 for unlimited
+	rate        float64 // Current requests per second, 0 for unlimited
+	next        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter creates a limiter allowing maxRPS requests per second; zero
+// means unlimited until Graph starts throttling
+func NewRateLimiter(maxRPS float64) *RateLimiter {
+	if maxRPS < 0 {
+		maxRPS = 0
+	}
+	return &RateLimiter{maxRate: maxRPS, rate: maxRPS}
+}
+
+// Wait blocks until the next request may start
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	start := now
+	if l.next.After(start) {
+		start = l.next
+	}
+	if l.pausedUntil.After(start) {
+		start = l.pausedUntil
+	}
+	if l.rate > 0 {
+		l.next = start.Add(time.Duration(float64(time.Second) / l.rate))
+	}
+	l.mu.Unlock()
+
+	delay := start.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Throttled records a 429 or 503: every caller pauses for retryAfter (or a
+// default) and the rate is halved
+func (l *RateLimiter) Throttled(retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = defaultThrottleBackoff
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(retryAfter); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	if l.rate == 0 {
+		l.rate = throttledStartRate
+	} else {
+		l.rate = max(l.rate/2, minThrottledRate)
+	}
+}
+
+// Succeeded records a successful request, letting the rate recover
+func (l *RateLimiter) Succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate == 0 || l.rate == l.maxRate {
+		return
+	}
+	l.rate += recoveryStep
+	switch {
+	case l.maxRate > 0 && l.rate > l.maxRate:
+		l.rate = l.maxRate
+	case l.maxRate == 0 && l.rate >= unlimitedRecoveryRate:
+		l.rate = 0
+	}
+}
+
+// Rate returns the current requests per second, 0 when unlimited
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// SetRateLimiter paces the client's requests through l; nil removes pacing
+func (c *Client) SetRateLimiter(l *RateLimiter) {
+	c.limiter = l
+}
+
+// throttled tells the client's limiter, if any, that Graph throttled a request
+func (c *Client) throttled(retryAfter time.Duration) {
+	if c.limiter != nil {
+		c.limiter.Throttled(retryAfter)
+	}
+}
+
+// ParseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date. It returns zero when the header is missing or invalid.
+func ParseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// limitTransport waits on the client's limiter before each request and
+// reports throttling responses back to it
+type limitTransport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limiter := t.client.limiter
+	if limiter == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	if err := limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		limiter.Throttled(ParseRetryAfter(resp.Header.Get("Retry-After")))
+	default:
+		limiter.Succeeded()
+	}
+	return resp, nil
+}
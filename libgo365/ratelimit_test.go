@@ -0,0 +1,173 @@
+package libgo365
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterPacing(t *testing.T) {
+	limiter := NewRateLimiter(50)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	// Five requests at 50/s need four 20ms gaps
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("Five waits took %v, want at least 80ms", elapsed)
+	}
+}
+
+func TestRateLimiterThrottled(t *testing.T) {
+	limiter := NewRateLimiter(0)
+	if limiter.Rate() != 0 {
+		t.Fatalf("Expected unlimited rate, got %v", limiter.Rate())
+	}
+
+	limiter.Throttled(50 * time.Millisecond)
+	if limiter.Rate() != throttledStartRate {
+		t.Errorf("Expected rate %v after throttling, got %v", throttledStartRate, limiter.Rate())
+	}
+
+	start := time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Wait returned after %v, want the Retry-After pause", elapsed)
+	}
+
+	limiter.Throttled(time.Millisecond)
+	if limiter.Rate() != throttledStartRate/2 {
+		t.Errorf("Expected rate halved to %v, got %v", throttledStartRate/2, limiter.Rate())
+	}
+
+	for i := 0; i < 1000; i++ {
+		limiter.Succeeded()
+	}
+	if limiter.Rate() != 0 {
+		t.Errorf("Expected unlimited rate after recovery, got %v", limiter.Rate())
+	}
+}
+
+func TestRateLimiterRecoversToMax(t *testing.T) {
+	limiter := NewRateLimiter(2)
+	for i := 0; i < 10; i++ {
+		limiter.Throttled(time.Millisecond)
+	}
+	if limiter.Rate() != minThrottledRate {
+		t.Errorf("Expected rate floor %v, got %v", minThrottledRate, limiter.Rate())
+	}
+
+	for i := 0; i < 100; i++ {
+		limiter.Succeeded()
+	}
+	if limiter.Rate() != 2 {
+		t.Errorf("Expected rate back at 2, got %v", limiter.Rate())
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	limiter := NewRateLimiter(0)
+	limiter.Throttled(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"", 0, 0},
+		{"bogus", 0, 0},
+		{"-3", 0, 0},
+		{"7", 7 * time.Second, 7 * time.Second},
+		{time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat), 28 * time.Second, 30 * time.Second},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+	}
+
+	for _, tt := range tests {
+		got := ParseRetryAfter(tt.value)
+		if got < tt.min || got > tt.max {
+			t.Errorf("ParseRetryAfter(%q) = %v, want between %v and %v", tt.value, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/busy" {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"code":"TooManyRequests","message":"slow down"}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+	limiter := NewRateLimiter(0)
+	client.SetRateLimiter(limiter)
+	// One 429, not a run of retried ones
+	client.SetMaxRetries(0)
+
+	if _, err := client.Get(context.Background(), "/ok"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if limiter.Rate() != 0 {
+		t.Errorf("Expected rate unchanged after success, got %v", limiter.Rate())
+	}
+
+	if _, err := client.Get(context.Background(), "/busy"); err == nil {
+		t.Fatal("Expected an error for a 429")
+	}
+	if limiter.Rate() != throttledStartRate {
+		t.Errorf("Expected limiter to slow down after a 429, got rate %v", limiter.Rate())
+	}
+}
+
+func TestBatchReportsThrottling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responses":[
+			{"id":"1","status":204},
+			{"id":"2","status":429,"headers":{"Retry-After":"0"},"body":{"error":{"code":"TooManyRequests","message":"slow down"}}}
+		]}`))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(0)
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+		limiter:    limiter,
+	}
+
+	responses, err := client.Batch(context.Background(), []*BatchRequest{
+		{Method: "DELETE", URL: "/me/messages/a"},
+		{Method: "DELETE", URL: "/me/messages/b"},
+	})
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if responses[0].Throttled() || !responses[1].Throttled() {
+		t.Errorf("Expected only the second response to be throttled")
+	}
+	if limiter.Rate() != throttledStartRate {
+		t.Errorf("Expected limiter to slow down after a throttled sub-request, got rate %v", limiter.Rate())
+	}
+}
@@ -0,0 +1,129 @@
+package libgo365
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventType says whether an event stands alone or belongs to a series
+type EventType string
+
+const (
+	EventSingleInstance EventType = "singleInstance"
+	EventOccurrence     EventType = "occurrence"
+	EventException      EventType = "exception"
+	EventSeriesMaster   EventType = "seriesMaster"
+)
+
+// RecurrencePatternType is how often a series repeats
+type RecurrencePatternType string
+
+const (
+	RecurrenceDaily           RecurrencePatternType = "daily"
+	RecurrenceWeekly          RecurrencePatternType = "weekly"
+	RecurrenceAbsoluteMonthly RecurrencePatternType = "absoluteMonthly"
+	RecurrenceRelativeMonthly RecurrencePatternType = "relativeMonthly"
+	RecurrenceAbsoluteYearly  RecurrencePatternType = "absoluteYearly"
+	RecurrenceRelativeYearly  RecurrencePatternType = "relativeYearly"
+)
+
+// RecurrenceRangeType is how a series ends
+type RecurrenceRangeType string
+
+const (
+	RecurrenceEndDate  RecurrenceRangeType = "endDate"
+	RecurrenceNoEnd    RecurrenceRangeType = "noEnd"
+	RecurrenceNumbered RecurrenceRangeType = "numbered"
+)
+
+// PatternedRecurrence is a series' recurrence, set on its series master
+type PatternedRecurrence struct {
+	Pattern *RecurrencePattern `json:"pattern,omitempty"`
+	Range   *RecurrenceRange   `json:"range,omitempty"`
+}
+
+// RecurrencePattern is how often a series repeats. Which fields apply
+// depends on Type: DaysOfWeek for weekly and relative patterns, DayOfMonth
+// for absolute ones, Month for yearly ones and Index for relative ones.
+type RecurrencePattern struct {
+	Type           RecurrencePatternType `json:"type,omitempty"`
+	Interval       int                   `json:"interval,omitempty"`
+	DaysOfWeek     []string              `json:"daysOfWeek,omitempty"`     // sunday ... saturday
+	DayOfMonth     int                   `json:"dayOfMonth,omitempty"`     // 1-31
+	Month          int                   `json:"month,omitempty"`          // 1-12
+	FirstDayOfWeek string                `json:"firstDayOfWeek,omitempty"` // For weekly patterns with an interval
+	Index          string                `json:"index,omitempty"`          // first, second, third, fourth, last
+}
+
+// RecurrenceRange is when a series starts and ends. Dates are YYYY-MM-DD.
+type RecurrenceRange struct {
+	Type                RecurrenceRangeType `json:"type,omitempty"`
+	StartDate           string              `json:"startDate,omitempty"`
+	EndDate             string              `json:"endDate,omitempty"` // For endDate ranges
+	NumberOfOccurrences int                 `json:"numberOfOccurrences,omitempty"`
+	RecurrenceTimeZone  string              `json:"recurrenceTimeZone,omitempty"`
+}
+
+// String describes the recurrence for people, e.g. "Every 2 weeks on
+// Monday, Wednesday until 2025-12-19" or "Monthly on the last Friday, 6 times"
+func (r *PatternedRecurrence) String() string {
+	if r == nil || r.Pattern == nil {
+		return ""
+	}
+	p := r.Pattern
+
+	var s string
+	switch p.Type {
+	case RecurrenceDaily:
+		s = every(p.Interval, "Daily", "days")
+	case RecurrenceWeekly:
+		s = every(p.Interval, "Weekly", "weeks") + " on " + weekdayList(p.DaysOfWeek)
+	case RecurrenceAbsoluteMonthly:
+		s = every(p.Interval, "Monthly", "months") + fmt.Sprintf(" on day %d", p.DayOfMonth)
+	case RecurrenceRelativeMonthly:
+		s = every(p.Interval, "Monthly", "months") + " on the " + p.Index + " " + weekdayList(p.DaysOfWeek)
+	case RecurrenceAbsoluteYearly:
+		s = every(p.Interval, "Yearly", "years") + fmt.Sprintf(" on %s %d", monthName(p.Month), p.DayOfMonth)
+	case RecurrenceRelativeYearly:
+		s = every(p.Interval, "Yearly", "years") + " on the " + p.Index + " " + weekdayList(p.DaysOfWeek) + " of " + monthName(p.Month)
+	default:
+		s = "Repeats (" + string(p.Type) + ")"
+	}
+
+	if r.Range != nil {
+		switch r.Range.Type {
+		case RecurrenceEndDate:
+			s += " until " + r.Range.EndDate
+		case RecurrenceNumbered:
+			s += fmt.Sprintf(", %d times", r.Range.NumberOfOccurrences)
+		}
+	}
+	return s
+}
+
+// every is "Weekly" for an interval of one and "Every 2 weeks" otherwise
+func every(interval int, single, plural string) string {
+	if interval <= 1 {
+		return single
+	}
+	return fmt.Sprintf("Every %d %s", interval, plural)
+}
+
+// weekdayList capitalizes Graph's day names: "Monday, Wednesday"
+func weekdayList(days []string) string {
+	names := make([]string, len(days))
+	for i, day := range days {
+		if day != "" {
+			names[i] = strings.ToUpper(day[:1]) + day[1:]
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func monthName(month int) string {
+	if month < 1 || month > 12 {
+		return fmt.Sprintf("month %d", month)
+	}
+	return time.Month(month).String()
+}
@@ -0,0 +1,106 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const seriesMasterJSON = `{
+	"id": "AAMkSeries",
+	"subject": "Team sync",
+	"type": "seriesMaster",
+	"recurrence": {
+		"pattern": {"type": "weekly", "interval": 2, "daysOfWeek": ["monday", "wednesday"], "firstDayOfWeek": "sunday", "index": "first"},
+		"range": {"type": "endDate", "startDate": "2025-06-02", "endDate": "2025-12-19", "recurrenceTimeZone": "Pacific/Auckland", "numberOfOccurrences": 0}
+	}
+}`
+
+func TestListEventsRecurrence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"value": [` + seriesMasterJSON + `]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+
+	resp, err := client.ListEvents(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	event := resp.Events[0]
+	if event.Type != EventSeriesMaster {
+		t.Errorf("Expected a series master, got %q", event.Type)
+	}
+	r := event.Recurrence
+	if r == nil || r.Pattern == nil || r.Range == nil {
+		t.Fatalf("Recurrence was dropped: %+v", r)
+	}
+	if r.Pattern.Type != RecurrenceWeekly || r.Pattern.Interval != 2 || len(r.Pattern.DaysOfWeek) != 2 {
+		t.Errorf("Unexpected pattern %+v", r.Pattern)
+	}
+	if r.Range.Type != RecurrenceEndDate || r.Range.EndDate != "2025-12-19" || r.Range.RecurrenceTimeZone != "Pacific/Auckland" {
+		t.Errorf("Unexpected range %+v", r.Range)
+	}
+
+	// Marshalling it again keeps the recurrence Graph needs to update the series
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var again Event
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if again.Recurrence.String() != r.String() || again.Recurrence.Range.StartDate != "2025-06-02" {
+		t.Errorf("Recurrence did not round-trip: %s", data)
+	}
+}
+
+func TestRecurrenceString(t *testing.T) {
+	tests := []struct {
+		recurrence *PatternedRecurrence
+		want       string
+	}{
+		{nil, ""},
+		{
+			&PatternedRecurrence{Pattern: &RecurrencePattern{Type: RecurrenceDaily, Interval: 1}, Range: &RecurrenceRange{Type: RecurrenceNoEnd}},
+			"Daily",
+		},
+		{
+			&PatternedRecurrence{
+				Pattern: &RecurrencePattern{Type: RecurrenceWeekly, Interval: 2, DaysOfWeek: []string{"monday", "wednesday"}},
+				Range:   &RecurrenceRange{Type: RecurrenceEndDate, EndDate: "2025-12-19"},
+			},
+			"Every 2 weeks on Monday, Wednesday until 2025-12-19",
+		},
+		{
+			&PatternedRecurrence{
+				Pattern: &RecurrencePattern{Type: RecurrenceRelativeMonthly, Interval: 1, DaysOfWeek: []string{"friday"}, Index: "last"},
+				Range:   &RecurrenceRange{Type: RecurrenceNumbered, NumberOfOccurrences: 6},
+			},
+			"Monthly on the last Friday, 6 times",
+		},
+		{
+			&PatternedRecurrence{Pattern: &RecurrencePattern{Type: RecurrenceAbsoluteMonthly, Interval: 3, DayOfMonth: 15}},
+			"Every 3 months on day 15",
+		},
+		{
+			&PatternedRecurrence{Pattern: &RecurrencePattern{Type: RecurrenceAbsoluteYearly, Interval: 1, Month: 3, DayOfMonth: 14}},
+			"Yearly on March 14",
+		},
+		{
+			&PatternedRecurrence{Pattern: &RecurrencePattern{Type: RecurrenceRelativeYearly, Interval: 1, Month: 11, DaysOfWeek: []string{"thursday"}, Index: "fourth"}},
+			"Yearly on the fourth Thursday of November",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.recurrence.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
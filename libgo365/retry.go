@@ -0,0 +1,110 @@
+package libgo365
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// retryBaseDelay is the wait before the first retry when Graph doesn't send
+// Retry-After; it doubles for each further retry. Tests shorten it.
+var retryBaseDelay = time.Second
+
+const (
+	// DefaultMaxRetries is how many times a new client retries a throttled
+	// or transiently failing request
+	DefaultMaxRetries = 3
+
+	// maxRetryWait is the longest a client waits before a retry. A longer
+	// Retry-After is returned to the caller rather than slept through.
+	maxRetryWait = 2 * time.Minute
+)
+
+// idempotentMethods can be retried after a gateway or server error, which
+// might have come after Graph acted on the request
+var idempotentMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete}
+
+// SetMaxRetries sets how many times the client retries a request that Graph
+// throttled (429) or that failed with 502, 503 or 504; 0 turns retries off.
+// Retries honor Retry-After, otherwise backing off exponentially with
+// jitter, and spend from the circuit breaker's retry budget.
+func (c *Client) SetMaxRetries(n int) {
+	c.maxRetries = max(n, 0)
+}
+
+// retryTransport retries throttled and transiently failing requests
+type retryTransport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if attempt >= t.client.maxRetries || !retryable(req, resp.StatusCode) {
+			return resp, nil
+		}
+
+		wait := ParseRetryAfter(resp.Header.Get("Retry-After"))
+		if wait == 0 {
+			wait = backoff(attempt)
+		}
+		if wait > maxRetryWait || !t.client.AllowRetry() {
+			return resp, nil
+		}
+
+		// The retry needs a fresh copy of the body
+		next := req
+		if req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			next = req.Clone(req.Context())
+			next.Body = body
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		t.client.log().DebugContext(req.Context(), "graph request retry",
+			"method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "wait", wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		req = next
+	}
+}
+
+// retryable reports whether a response with status is worth retrying. A 429
+// means Graph didn't act on the request, so any method can be retried;
+// after a server error only idempotent ones can. Bodies that can't be
+// replayed rule a retry out.
+func retryable(req *http.Request, status int) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return slices.Contains(idempotentMethods, req.Method)
+	}
+	return false
+}
+
+// backoff returns the wait before retry attempt+1: retryBaseDelay doubled
+// for each earlier retry, with jitter so that concurrent callers spread out
+func backoff(attempt int) time.Duration {
+	d := retryBaseDelay << attempt
+	return d/2 + rand.N(d/2)
+}
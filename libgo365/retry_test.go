@@ -0,0 +1,127 @@
+package libgo365
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with status, then succeeds
+func flakyServer(t *testing.T, status, failures int, retryAfter string) (*httptest.Server, *[]string) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":{"code":"TooManyRequests","message":"slow down"}}`))
+			return
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func retryClient(server *httptest.Server) *Client {
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+	return client
+}
+
+func shortenRetryDelay(t *testing.T) {
+	delay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = delay })
+}
+
+func TestClientRetriesThrottledRequests(t *testing.T) {
+	shortenRetryDelay(t)
+	server, bodies := flakyServer(t, http.StatusTooManyRequests, 2, "")
+
+	if _, err := retryClient(server).Post(context.Background(), "/me/sendMail", map[string]string{"subject": "hi"}); err != nil {
+		t.Fatalf("Expected the retried POST to succeed, got %v", err)
+	}
+	if len(*bodies) != 3 {
+		t.Fatalf("Expected three attempts, got %d", len(*bodies))
+	}
+	if (*bodies)[2] != (*bodies)[0] || (*bodies)[0] == "" {
+		t.Errorf("Expected the body resent unchanged, got %q", *bodies)
+	}
+}
+
+func TestClientRetriesServerErrorsOnlyWhenIdempotent(t *testing.T) {
+	shortenRetryDelay(t)
+
+	server, bodies := flakyServer(t, http.StatusServiceUnavailable, 1, "")
+	if _, err := retryClient(server).Get(context.Background(), "/me"); err != nil {
+		t.Fatalf("Expected the retried GET to succeed, got %v", err)
+	}
+	if len(*bodies) != 2 {
+		t.Errorf("Expected two GET attempts, got %d", len(*bodies))
+	}
+
+	server, bodies = flakyServer(t, http.StatusServiceUnavailable, 1, "")
+	if _, err := retryClient(server).Post(context.Background(), "/me/events", map[string]string{}); err == nil {
+		t.Error("Expected the POST's 503 to be returned")
+	}
+	if len(*bodies) != 1 {
+		t.Errorf("Expected a POST not to be retried after a 503, got %d attempts", len(*bodies))
+	}
+}
+
+func TestClientRetryLimits(t *testing.T) {
+	shortenRetryDelay(t)
+
+	server, bodies := flakyServer(t, http.StatusTooManyRequests, 10, "")
+	client := retryClient(server)
+	if _, err := client.Get(context.Background(), "/me"); err == nil {
+		t.Error("Expected an error once retries run out")
+	}
+	if len(*bodies) != DefaultMaxRetries+1 {
+		t.Errorf("Expected %d attempts, got %d", DefaultMaxRetries+1, len(*bodies))
+	}
+
+	server, bodies = flakyServer(t, http.StatusTooManyRequests, 10, "")
+	client = retryClient(server)
+	client.SetMaxRetries(0)
+	client.Get(context.Background(), "/me")
+	if len(*bodies) != 1 {
+		t.Errorf("Expected no retries when disabled, got %d attempts", len(*bodies))
+	}
+
+	// Waiting longer than maxRetryWait is left to the caller
+	server, bodies = flakyServer(t, http.StatusTooManyRequests, 10, "600")
+	retryClient(server).Get(context.Background(), "/me")
+	if len(*bodies) != 1 {
+		t.Errorf("Expected a long Retry-After not to be waited out, got %d attempts", len(*bodies))
+	}
+
+	server, bodies = flakyServer(t, http.StatusTooManyRequests, 10, "")
+	client = retryClient(server)
+	client.SetCircuitBreaker(NewCircuitBreaker(0, 1))
+	client.Get(context.Background(), "/me")
+	if len(*bodies) != 2 {
+		t.Errorf("Expected the retry budget to allow one retry, got %d attempts", len(*bodies))
+	}
+}
+
+func TestClientRetryHonorsCancellation(t *testing.T) {
+	server, bodies := flakyServer(t, http.StatusTooManyRequests, 10, "60")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := retryClient(server).Get(ctx, "/me"); err == nil {
+		t.Error("Expected the cancellation to end the wait")
+	}
+	if time.Since(start) > 5*time.Second || len(*bodies) != 1 {
+		t.Errorf("Expected one attempt and a prompt return, got %d in %s", len(*bodies), time.Since(start))
+	}
+}
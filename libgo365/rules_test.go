@@ -0,0 +1,130 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListMessageRules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/mailFolders/inbox/messageRules" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"value":[{"id":"r1","displayName":"Newsletters","sequence":2,"isEnabled":true,
+			"conditions":{"senderContains":["news@"]},"actions":{"moveToFolder":"f1","stopProcessingRules":true}}]}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	rules, err := client.ListMessageRules(context.Background())
+	if err != nil {
+		t.Fatalf("ListMessageRules failed: %v", err)
+	}
+	if len(rules) != 1 || rules[0].DisplayName != "Newsletters" || !rules[0].IsEnabled ||
+		rules[0].Conditions.SenderContains[0] != "news@" || rules[0].Actions.MoveToFolder != "f1" {
+		t.Errorf("Unexpected rules %+v", rules)
+	}
+}
+
+func TestCreateMessageRule(t *testing.T) {
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"value":[{"id":"r1","sequence":1},{"id":"r2","sequence":5}]}`)
+		case http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"r3","displayName":"Receipts","sequence":6,"isEnabled":false}`)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	rule := &MessageRule{
+		DisplayName: "Receipts",
+		Conditions:  &MessageRulePredicates{SubjectContains: []string{"receipt"}},
+		Actions:     &MessageRuleActions{MarkAsRead: true},
+	}
+	got, err := client.CreateMessageRule(context.Background(), rule)
+	if err != nil {
+		t.Fatalf("CreateMessageRule failed: %v", err)
+	}
+	if got.ID != "r3" {
+		t.Errorf("Unexpected rule %+v", got)
+	}
+	// Placed after the last rule, and sent disabled rather than left out
+	if created["sequence"] != float64(6) || created["isEnabled"] != false {
+		t.Errorf("Unexpected request body %v", created)
+	}
+	if rule.Sequence != 0 {
+		t.Error("Expected the caller's rule left unchanged")
+	}
+
+	if _, err := client.CreateMessageRule(context.Background(), &MessageRule{DisplayName: "No actions"}); err == nil {
+		t.Error("Expected an error for a rule without actions")
+	}
+}
+
+func TestSetMessageRuleEnabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/me/mailFolders/inbox/messageRules/r1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["isEnabled"] != false || len(body) != 1 {
+			t.Errorf("Expected only isEnabled false, got %v", body)
+		}
+		fmt.Fprint(w, `{"id":"r1","isEnabled":false}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	rule, err := client.SetMessageRuleEnabled(context.Background(), "r1", false)
+	if err != nil {
+		t.Fatalf("SetMessageRuleEnabled failed: %v", err)
+	}
+	if rule.IsEnabled {
+		t.Error("Expected the rule disabled")
+	}
+}
+
+func TestDeleteMessageRule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/me/mailFolders/inbox/messageRules/r1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	if err := client.DeleteMessageRule(context.Background(), "r1"); err != nil {
+		t.Errorf("DeleteMessageRule failed: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package libgo365
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// encryptedPrefix marks a config value encrypted with EncryptSecret
+const encryptedPrefix = "enc:v1:"
+
+const (
+	keyringService = "go365"
+	keyringUser    = "config-key"
+)
+
+// SecretKeyEnv names the environment variable that supplies the config
+// encryption key on machines without an OS keyring (CI, containers). Any
+// passphrase works; it is hashed to a 256-bit key.
+const SecretKeyEnv = "GO365_CONFIG_KEY"
+
+// IsEncrypted reports whether a config value was produced by EncryptSecret
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// EncryptSecret encrypts value with AES-256-GCM under the config key,
+// creating the key in the OS keyring on first use
+func EncryptSecret(value string) (string, error) {
+	key, err := secretKey(true)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret reverses EncryptSecret. Values without the encrypted prefix
+// are returned unchanged.
+func DecryptSecret(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted value: %w", err)
+	}
+
+	key, err := secretKey(false)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("encrypted value is truncated")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value (was the config key changed?): %w", err)
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// secretKey returns the config encryption key from GO365_CONFIG_KEY or the
+// OS keyring. With create set, a missing keyring entry is generated.
+func secretKey(create bool) ([]byte, error) {
+	if passphrase := os.Getenv(SecretKeyEnv); passphrase != "" {
+		sum := sha256.Sum256([]byte(passphrase))
+		return sum[:], nil
+	}
+
+	stored, err := keyring.Get(keyringService, keyringUser)
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(stored)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("config key in the OS keyring is invalid")
+		}
+		return key, nil
+	}
+	if !errors.Is(err, keyring.ErrNotFound) {
+		return nil, fmt.Errorf("OS keyring unavailable (set %s instead): %w", SecretKeyEnv, err)
+	}
+	if !create {
+		return nil, fmt.Errorf("config key not found in the OS keyring; set the secret again with 'go365 config set --client-secret'")
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate config key: %w", err)
+	}
+	if err := keyring.Set(keyringService, keyringUser, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("failed to store config key in the OS keyring (set %s instead): %w", SecretKeyEnv, err)
+	}
+	return key, nil
+}
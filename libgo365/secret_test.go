@@ -0,0 +1,107 @@
+package libgo365
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestEncryptSecret(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv(SecretKeyEnv, "")
+
+	encrypted, err := EncryptSecret("s3cret")
+	if err != nil {
+		t.Fatalf("EncryptSecret failed: %v", err)
+	}
+	if !IsEncrypted(encrypted) || strings.Contains(encrypted, "s3cret") {
+		t.Fatalf("value not encrypted: %q", encrypted)
+	}
+
+	plain, err := DecryptSecret(encrypted)
+	if err != nil {
+		t.Fatalf("DecryptSecret failed: %v", err)
+	}
+	if plain != "s3cret" {
+		t.Errorf("expected s3cret, got %q", plain)
+	}
+
+	// Plaintext passes through unchanged
+	if plain, err := DecryptSecret("plain"); err != nil || plain != "plain" {
+		t.Errorf("expected plain passthrough, got %q, %v", plain, err)
+	}
+}
+
+func TestDecryptSecretWrongKey(t *testing.T) {
+	t.Setenv(SecretKeyEnv, "first")
+	encrypted, err := EncryptSecret("s3cret")
+	if err != nil {
+		t.Fatalf("EncryptSecret failed: %v", err)
+	}
+
+	t.Setenv(SecretKeyEnv, "second")
+	if _, err := DecryptSecret(encrypted); err == nil {
+		t.Error("expected an error decrypting with a different key")
+	}
+}
+
+func TestConfigManagerClientSecret(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv(SecretKeyEnv, "")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	cm := &ConfigManager{configPath: path}
+
+	if err := cm.Save(&Config{ClientSecret: "s3cret", CertPassword: "pfx-pass"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") || strings.Contains(string(data), "pfx-pass") || !strings.Contains(string(data), encryptedPrefix) {
+		t.Errorf("secret stored in plaintext: %s", data)
+	}
+
+	config, err := cm.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if config.ClientSecret != "s3cret" {
+		t.Errorf("expected decrypted secret, got %q", config.ClientSecret)
+	}
+	if config.CertPassword != "pfx-pass" {
+		t.Errorf("expected decrypted certificate password, got %q", config.CertPassword)
+	}
+}
+
+func TestConfigManagerMigratesPlaintextSecret(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv(SecretKeyEnv, "")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"client_secret": "s3cret"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cm := &ConfigManager{configPath: path}
+
+	config, err := cm.LoadGlobal()
+	if err != nil {
+		t.Fatalf("LoadGlobal failed: %v", err)
+	}
+	if !IsEncrypted(config.ClientSecret) {
+		t.Errorf("expected LoadGlobal to keep the secret encrypted, got %q", config.ClientSecret)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("plaintext secret was not encrypted on load: %s", data)
+	}
+}
@@ -0,0 +1,347 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Schedule represents a team's Shifts schedule
+type Schedule struct {
+	ID                        string `json:"id,omitempty"`
+	Enabled                   bool   `json:"enabled,omitempty"`
+	TimeZone                  string `json:"timeZone,omitempty"`
+	ProvisionStatus           string `json:"provisionStatus,omitempty"` // notStarted, running, completed, failed
+	OpenShiftsEnabled         bool   `json:"openShiftsEnabled,omitempty"`
+	SwapShiftsRequestsEnabled bool   `json:"swapShiftsRequestsEnabled,omitempty"`
+	TimeOffRequestsEnabled    bool   `json:"timeOffRequestsEnabled,omitempty"`
+}
+
+// Shift represents an assigned shift in a schedule
+type Shift struct {
+	ID                string     `json:"id,omitempty"`
+	UserID            string     `json:"userId,omitempty"`
+	SchedulingGroupID string     `json:"schedulingGroupId,omitempty"`
+	SharedShift       *ShiftItem `json:"sharedShift,omitempty"` // Version visible to the team
+	DraftShift        *ShiftItem `json:"draftShift,omitempty"`  // Unpublished changes
+}
+
+// ShiftItem represents the details of a shift
+type ShiftItem struct {
+	DisplayName   string           `json:"displayName,omitempty"`
+	Notes         string           `json:"notes,omitempty"`
+	StartDateTime *time.Time       `json:"startDateTime,omitempty"`
+	EndDateTime   *time.Time       `json:"endDateTime,omitempty"`
+	Theme         string           `json:"theme,omitempty"` // white, blue, green, purple, pink, yellow, gray, ...
+	Activities    []*ShiftActivity `json:"activities,omitempty"`
+}
+
+// ShiftActivity represents a block of work within a shift (e.g., a break)
+type ShiftActivity struct {
+	Code          string     `json:"code,omitempty"`
+	DisplayName   string     `json:"displayName,omitempty"`
+	IsPaid        bool       `json:"isPaid,omitempty"`
+	StartDateTime *time.Time `json:"startDateTime,omitempty"`
+	EndDateTime   *time.Time `json:"endDateTime,omitempty"`
+	Theme         string     `json:"theme,omitempty"`
+}
+
+// OpenShift represents an unassigned shift that team members can request
+type OpenShift struct {
+	ID                string         `json:"id,omitempty"`
+	SchedulingGroupID string         `json:"schedulingGroupId,omitempty"`
+	SharedOpenShift   *OpenShiftItem `json:"sharedOpenShift,omitempty"`
+	DraftOpenShift    *OpenShiftItem `json:"draftOpenShift,omitempty"`
+}
+
+// OpenShiftItem represents the details of an open shift
+type OpenShiftItem struct {
+	ShiftItem
+	OpenSlotCount int `json:"openSlotCount,omitempty"`
+}
+
+// TimeOff represents a block of time off for a user
+type TimeOff struct {
+	ID            string       `json:"id,omitempty"`
+	UserID        string       `json:"userId,omitempty"`
+	SharedTimeOff *TimeOffItem `json:"sharedTimeOff,omitempty"`
+	DraftTimeOff  *TimeOffItem `json:"draftTimeOff,omitempty"`
+}
+
+// TimeOffItem represents the details of a time off entry
+type TimeOffItem struct {
+	TimeOffReasonID string     `json:"timeOffReasonId,omitempty"`
+	StartDateTime   *time.Time `json:"startDateTime,omitempty"`
+	EndDateTime     *time.Time `json:"endDateTime,omitempty"`
+	Theme           string     `json:"theme,omitempty"`
+}
+
+// ListScheduleItemsOptions represents options for listing shifts, open shifts, or time off
+type ListScheduleItemsOptions struct {
+	StartTime *time.Time // Only items starting at or after this time
+	EndTime   *time.Time // Only items ending at or before this time
+	Top       int
+	PageToken string
+}
+
+// ListShiftsResponse represents the response from ListShifts with pagination info
+type ListShiftsResponse struct {
+	Shifts        []*Shift
+	Count         int
+	HasMore       bool
+	NextPageToken string
+}
+
+// ListOpenShiftsResponse represents the response from ListOpenShifts with pagination info
+type ListOpenShiftsResponse struct {
+	OpenShifts    []*OpenShift
+	Count         int
+	HasMore       bool
+	NextPageToken string
+}
+
+// ListTimesOffResponse represents the response from ListTimesOff with pagination info
+type ListTimesOffResponse struct {
+	TimesOff      []*TimeOff
+	Count         int
+	HasMore       bool
+	NextPageToken string
+}
+
+// GetTeamSchedule retrieves the Shifts schedule for a team
+func (c *Client) GetTeamSchedule(ctx context.Context, teamID string) (*Schedule, error) {
+	if teamID == "" {
+		return nil, fmt.Errorf("team ID is required")
+	}
+
+	data, err := c.Get(ctx, fmt.Sprintf("/teams/%s/schedule", teamID))
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule Schedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schedule: %w", err)
+	}
+
+	return &schedule, nil
+}
+
+// listScheduleItems fetches one page of a schedule collection (shifts, openShifts, timesOff).
+// sharedProp is the name of the shared item property used for date filtering.
+func (c *Client) listScheduleItems(ctx context.Context, teamID, collection, sharedProp string, opts *ListScheduleItemsOptions) ([]byte, error) {
+	if teamID == "" {
+		return nil, fmt.Errorf("team ID is required")
+	}
+
+	path := fmt.Sprintf("/teams/%s/schedule/%s", teamID, collection)
+
+	params := url.Values{}
+	if opts != nil {
+		filters := []string{}
+		if opts.StartTime != nil {
+			filters = append(filters, fmt.Sprintf("%s/startDateTime ge %s", sharedProp, opts.StartTime.UTC().Format(time.RFC3339)))
+		}
+		if opts.EndTime != nil {
+			filters = append(filters, fmt.Sprintf("%s/endDateTime le %s", sharedProp, opts.EndTime.UTC().Format(time.RFC3339)))
+		}
+		if len(filters) > 0 {
+			params.Set("$filter", strings.Join(filters, " and "))
+		}
+		if opts.Top > 0 {
+			params.Set("$top", fmt.Sprintf("%d", opts.Top))
+		}
+		if opts.PageToken != "" {
+			params.Set("$skiptoken", opts.PageToken)
+		}
+	}
+
+	fullPath := path
+	if len(params) > 0 {
+		fullPath = path + "?" + params.Encode()
+	}
+
+	return c.Get(ctx, fullPath)
+}
+
+// ListShifts retrieves shifts from a team's schedule
+func (c *Client) ListShifts(ctx context.Context, teamID string, opts *ListScheduleItemsOptions) (*ListShiftsResponse, error) {
+	data, err := c.listScheduleItems(ctx, teamID, "shifts", "sharedShift", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Value    []*Shift `json:"value"`
+		NextLink string   `json:"@odata.nextLink,omitempty"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal shifts: %w", err)
+	}
+
+	return &ListShiftsResponse{
+		Shifts:        list.Value,
+		Count:         len(list.Value),
+		HasMore:       list.NextLink != "",
+		NextPageToken: ExtractPageToken(list.NextLink),
+	}, nil
+}
+
+// ListOpenShifts retrieves open shifts from a team's schedule
+func (c *Client) ListOpenShifts(ctx context.Context, teamID string, opts *ListScheduleItemsOptions) (*ListOpenShiftsResponse, error) {
+	data, err := c.listScheduleItems(ctx, teamID, "openShifts", "sharedOpenShift", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Value    []*OpenShift `json:"value"`
+		NextLink string       `json:"@odata.nextLink,omitempty"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal open shifts: %w", err)
+	}
+
+	return &ListOpenShiftsResponse{
+		OpenShifts:    list.Value,
+		Count:         len(list.Value),
+		HasMore:       list.NextLink != "",
+		NextPageToken: ExtractPageToken(list.NextLink),
+	}, nil
+}
+
+// ListTimesOff retrieves time off entries from a team's schedule
+func (c *Client) ListTimesOff(ctx context.Context, teamID string, opts *ListScheduleItemsOptions) (*ListTimesOffResponse, error) {
+	data, err := c.listScheduleItems(ctx, teamID, "timesOff", "sharedTimeOff", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Value    []*TimeOff `json:"value"`
+		NextLink string     `json:"@odata.nextLink,omitempty"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal time off: %w", err)
+	}
+
+	return &ListTimesOffResponse{
+		TimesOff:      list.Value,
+		Count:         len(list.Value),
+		HasMore:       list.NextLink != "",
+		NextPageToken: ExtractPageToken(list.NextLink),
+	}, nil
+}
+
+// saveScheduleItem creates (POST) an item when id is empty, or replaces it (PUT) otherwise
+func (c *Client) saveScheduleItem(ctx context.Context, teamID, collection, id string, item interface{}) ([]byte, error) {
+	if teamID == "" {
+		return nil, fmt.Errorf("team ID is required")
+	}
+
+	path := fmt.Sprintf("/teams/%s/schedule/%s", teamID, collection)
+	if id == "" {
+		return c.Post(ctx, path, item)
+	}
+	return c.Put(ctx, path+"/"+id, item)
+}
+
+// deleteScheduleItem deletes an item from a schedule collection
+func (c *Client) deleteScheduleItem(ctx context.Context, teamID, collection, id string) error {
+	if teamID == "" {
+		return fmt.Errorf("team ID is required")
+	}
+	if id == "" {
+		return fmt.Errorf("item ID is required")
+	}
+
+	return c.Delete(ctx, fmt.Sprintf("/teams/%s/schedule/%s/%s", teamID, collection, id))
+}
+
+// SaveShift creates a shift, or replaces it if shift.ID is set
+func (c *Client) SaveShift(ctx context.Context, teamID string, shift *Shift) (*Shift, error) {
+	if shift == nil {
+		return nil, fmt.Errorf("shift is required")
+	}
+
+	data, err := c.saveScheduleItem(ctx, teamID, "shifts", shift.ID, shift)
+	if err != nil {
+		return nil, err
+	}
+
+	// PUT may return 204 No Content
+	if len(data) == 0 {
+		return shift, nil
+	}
+
+	var saved Shift
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal shift: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// DeleteShift deletes a shift from a team's schedule
+func (c *Client) DeleteShift(ctx context.Context, teamID, shiftID string) error {
+	return c.deleteScheduleItem(ctx, teamID, "shifts", shiftID)
+}
+
+// SaveOpenShift creates an open shift, or replaces it if openShift.ID is set
+func (c *Client) SaveOpenShift(ctx context.Context, teamID string, openShift *OpenShift) (*OpenShift, error) {
+	if openShift == nil {
+		return nil, fmt.Errorf("open shift is required")
+	}
+
+	data, err := c.saveScheduleItem(ctx, teamID, "openShifts", openShift.ID, openShift)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return openShift, nil
+	}
+
+	var saved OpenShift
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal open shift: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// DeleteOpenShift deletes an open shift from a team's schedule
+func (c *Client) DeleteOpenShift(ctx context.Context, teamID, openShiftID string) error {
+	return c.deleteScheduleItem(ctx, teamID, "openShifts", openShiftID)
+}
+
+// SaveTimeOff creates a time off entry, or replaces it if timeOff.ID is set
+func (c *Client) SaveTimeOff(ctx context.Context, teamID string, timeOff *TimeOff) (*TimeOff, error) {
+	if timeOff == nil {
+		return nil, fmt.Errorf("time off is required")
+	}
+
+	data, err := c.saveScheduleItem(ctx, teamID, "timesOff", timeOff.ID, timeOff)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return timeOff, nil
+	}
+
+	var saved TimeOff
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal time off: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// DeleteTimeOff deletes a time off entry from a team's schedule
+func (c *Client) DeleteTimeOff(ctx context.Context, teamID, timeOffID string) error {
+	return c.deleteScheduleItem(ctx, teamID, "timesOff", timeOffID)
+}
@@ -0,0 +1,195 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetTeamSchedule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/teams/team123/schedule" {
+			t.Errorf("Expected path /teams/team123/schedule, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(Schedule{ID: "team123", Enabled: true, TimeZone: "Pacific/Auckland"})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	schedule, err := client.GetTeamSchedule(context.Background(), "team123")
+	if err != nil {
+		t.Fatalf("GetTeamSchedule failed: %v", err)
+	}
+	if !schedule.Enabled || schedule.TimeZone != "Pacific/Auckland" {
+		t.Errorf("Unexpected schedule: %+v", schedule)
+	}
+}
+
+func TestListShifts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/teams/team123/schedule/shifts" {
+			t.Errorf("Expected path /teams/team123/schedule/shifts, got %s", r.URL.Path)
+		}
+
+		filter := r.URL.Query().Get("$filter")
+		if !strings.Contains(filter, "sharedShift/startDateTime ge 2025-01-13T00:00:00Z") {
+			t.Errorf("Expected start filter, got %s", filter)
+		}
+		if !strings.Contains(filter, "sharedShift/endDateTime le 2025-01-20T00:00:00Z") {
+			t.Errorf("Expected end filter, got %s", filter)
+		}
+
+		w.Write([]byte(`{
+			"value": [
+				{"id": "shift1", "userId": "user1", "sharedShift": {"displayName": "Morning", "startDateTime": "2025-01-13T08:00:00Z", "endDateTime": "2025-01-13T16:00:00Z"}}
+			],
+			"@odata.nextLink": "https://graph.microsoft.com/v1.0/teams/team123/schedule/shifts?$skiptoken=abc"
+		}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	start := time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+	resp, err := client.ListShifts(context.Background(), "team123", &ListScheduleItemsOptions{
+		StartTime: &start,
+		EndTime:   &end,
+	})
+	if err != nil {
+		t.Fatalf("ListShifts failed: %v", err)
+	}
+
+	if len(resp.Shifts) != 1 {
+		t.Fatalf("Expected 1 shift, got %d", len(resp.Shifts))
+	}
+	if resp.Shifts[0].SharedShift.DisplayName != "Morning" {
+		t.Errorf("Expected 'Morning', got '%s'", resp.Shifts[0].SharedShift.DisplayName)
+	}
+	if !resp.HasMore || resp.NextPageToken != "abc" {
+		t.Errorf("Expected next page token 'abc', got '%s'", resp.NextPageToken)
+	}
+}
+
+func TestListOpenShifts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/teams/team123/schedule/openShifts" {
+			t.Errorf("Expected path /teams/team123/schedule/openShifts, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"value": [{"id": "open1", "sharedOpenShift": {"displayName": "Weekend", "openSlotCount": 3}}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	resp, err := client.ListOpenShifts(context.Background(), "team123", nil)
+	if err != nil {
+		t.Fatalf("ListOpenShifts failed: %v", err)
+	}
+	if len(resp.OpenShifts) != 1 {
+		t.Fatalf("Expected 1 open shift, got %d", len(resp.OpenShifts))
+	}
+	item := resp.OpenShifts[0].SharedOpenShift
+	if item.DisplayName != "Weekend" || item.OpenSlotCount != 3 {
+		t.Errorf("Unexpected open shift: %+v", item)
+	}
+}
+
+func TestSaveShift(t *testing.T) {
+	tests := []struct {
+		name         string
+		shift        *Shift
+		expectMethod string
+		expectPath   string
+	}{
+		{
+			name:         "create",
+			shift:        &Shift{UserID: "user1", SharedShift: &ShiftItem{DisplayName: "Late"}},
+			expectMethod: "POST",
+			expectPath:   "/teams/team123/schedule/shifts",
+		},
+		{
+			name:         "update",
+			shift:        &Shift{ID: "shift1", UserID: "user1", SharedShift: &ShiftItem{DisplayName: "Late"}},
+			expectMethod: "PUT",
+			expectPath:   "/teams/team123/schedule/shifts/shift1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.expectMethod {
+					t.Errorf("Expected %s, got %s", tt.expectMethod, r.Method)
+				}
+				if r.URL.Path != tt.expectPath {
+					t.Errorf("Expected path %s, got %s", tt.expectPath, r.URL.Path)
+				}
+				var shift Shift
+				json.NewDecoder(r.Body).Decode(&shift)
+				if shift.ID == "" {
+					shift.ID = "new-shift"
+				}
+				json.NewEncoder(w).Encode(shift)
+			}))
+			defer server.Close()
+
+			client := &Client{
+				httpClient: server.Client(),
+				baseURL:    server.URL,
+				tokens:     StaticToken("test-token"),
+			}
+
+			saved, err := client.SaveShift(context.Background(), "team123", tt.shift)
+			if err != nil {
+				t.Fatalf("SaveShift failed: %v", err)
+			}
+			if saved.ID == "" {
+				t.Error("Expected saved shift to have an ID")
+			}
+		})
+	}
+}
+
+func TestDeleteTimeOff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Expected DELETE, got %s", r.Method)
+		}
+		if r.URL.Path != "/teams/team123/schedule/timesOff/off1" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	if err := client.DeleteTimeOff(context.Background(), "team123", "off1"); err != nil {
+		t.Fatalf("DeleteTimeOff failed: %v", err)
+	}
+	if err := client.DeleteTimeOff(context.Background(), "team123", ""); err == nil {
+		t.Error("Expected error for empty ID")
+	}
+}
@@ -0,0 +1,118 @@
+package libgo365
+
+import (
+	"cmp"
+	"context"
+	"strings"
+	"time"
+)
+
+// Account types for AuthStatus.AccountType
+const (
+	AccountWorkOrSchool = "work or school"
+	AccountPersonal     = "personal"
+	AccountApp          = "app"
+)
+
+// consumersTenantID is the tenant every personal Microsoft account signs in to
+const consumersTenantID = "9188040d-6c67-4c5b-b112-36a304b66dad"
+
+// clouds maps sign-in hosts to the national cloud they belong to
+var clouds = map[string]string{
+	"login.microsoftonline.com":        "global",
+	"login.windows.net":                "global",
+	"login.microsoftonline.us":         "usgov",
+	"login.chinacloudapi.cn":           "china",
+	"login.partner.microsoftonline.cn": "china",
+}
+
+// AuthStatus describes the account an Authenticator signs in as and its
+// current token. It comes from the token cache and the token's claims, so
+// it needs no Graph call.
+type AuthStatus struct {
+	Authenticated bool      `json:"authenticated"`
+	Mode          string    `json:"mode"` // An AuthMode constant
+	AccountType   string    `json:"accountType,omitempty"`
+	Username      string    `json:"username,omitempty"`
+	AppID         string    `json:"appId,omitempty"`
+	AppName       string    `json:"appName,omitempty"`
+	TenantID      string    `json:"tenantId,omitempty"`
+	Environment   string    `json:"environment,omitempty"` // Sign-in host, such as login.microsoftonline.com
+	Cloud         string    `json:"cloud,omitempty"`       // global, usgov or china
+	ExpiresOn     time.Time `json:"expiresOn,omitzero"`
+	Scopes        []string  `json:"scopes,omitempty"`
+	Roles         []string  `json:"roles,omitempty"`
+	TokenCache    string    `json:"tokenCache,omitempty"` // File holding the token cache, if it is one
+	Error         string    `json:"error,omitempty"`      // Why no token could be had
+}
+
+// Status reports who the authenticator is signed in as, getting a token if
+// it doesn't have a current one. Failing to get one is reported in Error
+// rather than returned.
+func (a *Authenticator) Status(ctx context.Context) *AuthStatus {
+	st := &AuthStatus{Mode: AuthModeDeviceCode}
+	if tc, ok := a.store.(*TokenCache); ok {
+		st.TokenCache = tc.Path()
+	}
+
+	switch {
+	case a.ManagedIdentity():
+		st.Mode = AuthModeManagedIdentity
+		st.AppID = a.clientID
+	case a.AzureIdentity():
+		st.Mode = AuthModeAzureIdentity
+	case a.AppOnly():
+		st.Mode = AuthModeAppOnly
+		st.AccountType = AccountApp
+		st.AppID = a.clientID
+		st.TenantID = a.tenantID
+		st.Environment = "login.microsoftonline.com"
+	default:
+		accounts, err := a.app.Accounts(ctx)
+		if err != nil {
+			st.Error = err.Error()
+			return st
+		}
+		if len(accounts) == 0 {
+			return st
+		}
+		st.Username = accounts[0].PreferredUsername
+		st.TenantID = accounts[0].Realm
+		st.Environment = accounts[0].Environment
+	}
+
+	token, err := a.Token(ctx)
+	if err != nil {
+		st.Error = err.Error()
+		return st
+	}
+	st.Authenticated = true
+	st.ExpiresOn = a.cached.expiresOn()
+
+	// Personal account tokens are opaque, leaving what the cache knows
+	if claims, err := ParseTokenClaims(token); err == nil {
+		st.Scopes = claims.Scopes
+		st.Roles = claims.Roles
+		st.AppID = cmp.Or(claims.AppID, st.AppID)
+		st.AppName = claims.AppName
+		if st.Username == "" {
+			st.Username = claims.Username
+		}
+		if st.TenantID == "" {
+			st.TenantID = claims.TenantID
+		}
+		if claims.AppOnly() {
+			st.AccountType = AccountApp
+		}
+	}
+
+	if st.AccountType == "" {
+		if st.TenantID == consumersTenantID {
+			st.AccountType = AccountPersonal
+		} else {
+			st.AccountType = AccountWorkOrSchool
+		}
+	}
+	st.Cloud = clouds[strings.ToLower(st.Environment)]
+	return st
+}
@@ -0,0 +1,63 @@
+package libgo365
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStatusAzureIdentity(t *testing.T) {
+	auth := newCredentialAuthenticator(&fakeCredential{claims: map[string]interface{}{
+		"upn":             "alex@contoso.com",
+		"tid":             "tenant-id",
+		"appid":           "app-id",
+		"app_displayname": "Azure CLI",
+		"scp":             "Mail.Read User.Read",
+	}})
+
+	st := auth.Status(context.Background())
+	if !st.Authenticated || st.Mode != AuthModeAzureIdentity || st.Error != "" {
+		t.Fatalf("Expected an authenticated azidentity status, got %+v", st)
+	}
+	if st.Username != "alex@contoso.com" || st.TenantID != "tenant-id" || st.AppName != "Azure CLI" {
+		t.Errorf("Expected the token's claims, got %+v", st)
+	}
+	if st.AccountType != AccountWorkOrSchool || len(st.Scopes) != 2 {
+		t.Errorf("Expected a work account with two scopes, got %s %v", st.AccountType, st.Scopes)
+	}
+	if st.ExpiresOn.IsZero() {
+		t.Error("Expected the token's expiry")
+	}
+}
+
+func TestStatusAccountType(t *testing.T) {
+	personal := newCredentialAuthenticator(&fakeCredential{claims: map[string]interface{}{"tid": consumersTenantID, "scp": "User.Read"}})
+	if st := personal.Status(context.Background()); st.AccountType != AccountPersonal {
+		t.Errorf("Expected a personal account, got %q", st.AccountType)
+	}
+
+	app := newCredentialAuthenticator(&fakeCredential{claims: map[string]interface{}{"tid": "tenant-id", "roles": []string{"Mail.Read"}}})
+	if st := app.Status(context.Background()); st.AccountType != AccountApp || len(st.Roles) != 1 {
+		t.Errorf("Expected an app with one role, got %q %v", st.AccountType, st.Roles)
+	}
+}
+
+func TestStatusNotAuthenticated(t *testing.T) {
+	failing := newCredentialAuthenticator(&fakeCredential{err: errors.New("az login required")})
+	st := failing.Status(context.Background())
+	if st.Authenticated || st.Error == "" {
+		t.Errorf("Expected the credential error, got %+v", st)
+	}
+
+	auth, err := NewAuthenticator(AuthConfig{
+		TenantID: "common",
+		ClientID: "00000000-0000-0000-0000-000000000000",
+	}, WithTokenStore(&memoryStore{}))
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	st = auth.Status(context.Background())
+	if st.Authenticated || st.Mode != AuthModeDeviceCode || st.Error != "" || st.TokenCache != "" {
+		t.Errorf("Expected a signed-out device code status, got %+v", st)
+	}
+}
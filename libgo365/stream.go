@@ -0,0 +1,129 @@
+package libgo365
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// GetStream performs a GET request for a Graph collection and calls fn with
+// each element of its "value" array as soon as it has been decoded, rather
+// than after the whole page has downloaded. It returns the page's
+// @odata.nextLink, or "" on the last page. Responses are cached like Get.
+func (c *Client) GetStream(ctx context.Context, path string, fn func(item json.RawMessage) error, opts ...RequestOption) (string, error) {
+	o := newRequestOptions(opts)
+	url := c.requestURL(path, o)
+	cacheKey := o.cacheKey(url)
+
+	var cached []byte
+	if c.cache != nil {
+		if body, ok := c.cache.Get(cacheKey); ok {
+			c.log().Debug("graph cache hit", "url", url)
+			return decodeCollection(bytes.NewReader(body), fn)
+		}
+		cached = c.cache.conditional(cacheKey, o.header)
+	}
+
+	resp, err := c.send(ctx, "GET", url, "", nil, o)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		c.log().Debug("graph cache revalidated", "url", url)
+		c.cache.touch(cacheKey)
+		return decodeCollection(bytes.NewReader(cached), fn)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("failed to read response: %w", err)
+		}
+		return "", newResponseError(resp, body)
+	}
+
+	// Keep a copy for the cache as the body is decoded
+	var body io.Reader = resp.Body
+	var copied bytes.Buffer
+	if c.cache != nil {
+		body = io.TeeReader(resp.Body, &copied)
+	}
+
+	nextLink, err := decodeCollection(body, fn)
+	if err != nil {
+		return "", err
+	}
+
+	if c.cache != nil {
+		c.cache.putResponse(cacheKey, copied.Bytes(), resp.Header)
+	}
+	return nextLink, nil
+}
+
+// decodeCollection reads a collection response, calling fn for each element
+// of "value" in order, and returns its @odata.nextLink. Other properties are
+// skipped.
+func decodeCollection(r io.Reader, fn func(item json.RawMessage) error) (string, error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return "", err
+	}
+
+	nextLink := ""
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("failed to decode collection: %w", err)
+		}
+
+		switch tok {
+		case "value":
+			if err := expectDelim(dec, '['); err != nil {
+				return "", err
+			}
+			for dec.More() {
+				var item json.RawMessage
+				if err := dec.Decode(&item); err != nil {
+					return "", fmt.Errorf("failed to decode collection: %w", err)
+				}
+				if err := fn(item); err != nil {
+					return "", err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return "", err
+			}
+		case "@odata.nextLink":
+			if err := dec.Decode(&nextLink); err != nil {
+				return "", fmt.Errorf("failed to decode collection: %w", err)
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return "", fmt.Errorf("failed to decode collection: %w", err)
+			}
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return "", err
+	}
+	return nextLink, nil
+}
+
+// expectDelim reads the next token and checks that it is delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode collection: %w", err)
+	}
+	if tok != delim {
+		return fmt.Errorf("failed to decode collection: expected %v, got %v", delim, tok)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeCollection(t *testing.T) {
+	body := `{
+		"@odata.context": "https://graph.microsoft.com/v1.0/$metadata#users('me')/messages",
+		"@odata.count": 2,
+		"value": [{"id": "msg1", "nested": {"value": [1]}}, {"id": "msg2"}],
+		"@odata.nextLink": "https://graph.microsoft.com/v1.0/me/messages?$skip=2"
+	}`
+
+	var ids []string
+	nextLink, err := decodeCollection(strings.NewReader(body), func(item json.RawMessage) error {
+		var msg Message
+		if err := json.Unmarshal(item, &msg); err != nil {
+			return err
+		}
+		ids = append(ids, msg.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("decodeCollection failed: %v", err)
+	}
+	if strings.Join(ids, ",") != "msg1,msg2" {
+		t.Errorf("Expected msg1,msg2, got %v", ids)
+	}
+	if nextLink != "https://graph.microsoft.com/v1.0/me/messages?$skip=2" {
+		t.Errorf("Unexpected nextLink %q", nextLink)
+	}
+}
+
+func TestDecodeCollectionErrors(t *testing.T) {
+	noop := func(json.RawMessage) error { return nil }
+	for _, body := range []string{`[]`, `{"value": {}}`, `{"value": [{"id": "msg1"}`, `not json`} {
+		if _, err := decodeCollection(strings.NewReader(body), noop); err == nil {
+			t.Errorf("decodeCollection(%s) should fail", body)
+		}
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	_, err := decodeCollection(strings.NewReader(`{"value": [{}, {}, {}]}`), func(json.RawMessage) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Expected to stop after the first item with its error, got %v after %d calls", err, calls)
+	}
+}
+
+func TestStreamMessagesBeforePageEnds(t *testing.T) {
+	firstSeen := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"value": [{"id": "msg1", "subject": "First"},`))
+		w.(http.Flusher).Flush()
+		// Finish the page only once the caller has the first message
+		select {
+		case <-firstSeen:
+		case <-time.After(2 * time.Second):
+			t.Error("The first message was not delivered before the page ended")
+		}
+		w.Write([]byte(`{"id": "msg2", "subject": "Second"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+
+	var subjects []string
+	resp, err := client.StreamMessages(context.Background(), nil, func(msg *Message) error {
+		if len(subjects) == 0 {
+			close(firstSeen)
+		}
+		subjects = append(subjects, msg.Subject)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamMessages failed: %v", err)
+	}
+	if strings.Join(subjects, ",") != "First,Second" {
+		t.Errorf("Expected First,Second, got %v", subjects)
+	}
+	if resp.Count != 2 || resp.HasMore || resp.Messages != nil {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+}
+
+func TestGetStreamCaches(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"value": [{"id": "event1"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+	client.SetCache(NewResponseCache(t.TempDir(), time.Minute))
+
+	opts := &CalendarViewOptions{StartDateTime: "2025-01-15T00:00:00Z", EndDateTime: "2025-01-16T00:00:00Z"}
+	for i := 0; i < 2; i++ {
+		var ids []string
+		_, err := client.StreamCalendarView(context.Background(), opts, func(event *Event) error {
+			ids = append(ids, event.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamCalendarView failed: %v", err)
+		}
+		if len(ids) != 1 || ids[0] != "event1" {
+			t.Errorf("Expected event1, got %v", ids)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the second listing to come from the cache, got %d requests", requests)
+	}
+}
+
+func TestGetStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": "ErrorAccessDenied", "message": "Access is denied."}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), "test-token")
+	client.baseURL = server.URL
+
+	_, err := client.GetStream(context.Background(), "/me/messages", func(json.RawMessage) error { return nil })
+	if StatusCode(err) != http.StatusForbidden {
+		t.Errorf("Expected a 403 error, got %v", err)
+	}
+}
@@ -0,0 +1,450 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTeamsPollInterval is how often async team operations are polled
+	DefaultTeamsPollInterval = 5 * time.Second
+)
+
+// Team represents a Microsoft Teams team
+type Team struct {
+	ID          string `json:"id,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+	Visibility  string `json:"visibility,omitempty"` // private, public
+	IsArchived  bool   `json:"isArchived,omitempty"`
+	WebURL      string `json:"webUrl,omitempty"`
+}
+
+// TeamList represents a list of teams returned by Graph API
+type TeamList struct {
+	Value    []*Team `json:"value"`
+	NextLink string  `json:"@odata.nextLink,omitempty"`
+}
+
+// TeamMember represents a conversation member of a team
+type TeamMember struct {
+	ID          string   `json:"id,omitempty"` // Membership ID (not the user ID)
+	DisplayName string   `json:"displayName,omitempty"`
+	Roles       []string `json:"roles,omitempty"` // owner, guest; empty for members
+	UserID      string   `json:"userId,omitempty"`
+	Email       string   `json:"email,omitempty"`
+}
+
+// TeamMemberList represents a list of team members returned by Graph API
+type TeamMemberList struct {
+	Value    []*TeamMember `json:"value"`
+	NextLink string        `json:"@odata.nextLink,omitempty"`
+}
+
+// TeamsAsyncOperation represents a long-running Teams operation (create, archive, etc.)
+type TeamsAsyncOperation struct {
+	ID               string                    `json:"id,omitempty"`
+	OperationType    string                    `json:"operationType,omitempty"` // createTeam, archiveTeam, unarchiveTeam, ...
+	Status           string                    `json:"status,omitempty"`        // notStarted, inProgress, succeeded, failed
+	TargetResourceID string                    `json:"targetResourceId,omitempty"`
+	CreatedDateTime  *time.Time                `json:"createdDateTime,omitempty"`
+	Error            *TeamsAsyncOperationError `json:"error,omitempty"`
+
+	// Location is the operation path to poll, taken from the Location header
+	Location string `json:"-"`
+}
+
+// TeamsAsyncOperationError represents the error of a failed Teams operation
+type TeamsAsyncOperationError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// IsDone returns true if the operation has finished (successfully or not)
+func (op *TeamsAsyncOperation) IsDone() bool {
+	return op.Status == "succeeded" || op.Status == "failed"
+}
+
+// CreateTeamOptions represents options for creating a team
+type CreateTeamOptions struct {
+	DisplayName string
+	Description string
+	Template    string // Template ID (default: standard)
+	Visibility  string // private or public
+}
+
+// ListTeamsOptions represents options for listing teams
+type ListTeamsOptions struct {
+	UserID string // Empty = teams the current user has joined
+}
+
+// AddTeamMemberOptions represents options for adding a member to a team
+type AddTeamMemberOptions struct {
+	UserID string // User ID or UPN
+	Owner  bool   // Add with the owner role
+}
+
+// teamIDFromLocation matches the team ID in a Location/Content-Location header
+// such as /teams('00000000-0000-0000-0000-000000000000')/operations('...')
+var teamIDFromLocation = regexp.MustCompile(`teams\('([^']+)'\)`)
+
+// ListTeams retrieves the teams the user is a member of
+func (c *Client) ListTeams(ctx context.Context, opts *ListTeamsOptions) ([]*Team, error) {
+	path := "/me/joinedTeams"
+	if opts != nil && opts.UserID != "" {
+		path = fmt.Sprintf("/users/%s/joinedTeams", opts.UserID)
+	}
+
+	data, err := c.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var teamList TeamList
+	if err := json.Unmarshal(data, &teamList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal teams: %w", err)
+	}
+
+	return teamList.Value, nil
+}
+
+// GetTeam retrieves a team by ID
+func (c *Client) GetTeam(ctx context.Context, teamID string) (*Team, error) {
+	if teamID == "" {
+		return nil, fmt.Errorf("team ID is required")
+	}
+
+	data, err := c.Get(ctx, fmt.Sprintf("/teams/%s", teamID))
+	if err != nil {
+		return nil, err
+	}
+
+	var team Team
+	if err := json.Unmarshal(data, &team); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal team: %w", err)
+	}
+
+	return &team, nil
+}
+
+// CreateTeam starts provisioning a new team. Team creation is asynchronous:
+// the returned operation should be polled with WaitForTeamsOperation.
+func (c *Client) CreateTeam(ctx context.Context, opts *CreateTeamOptions) (*TeamsAsyncOperation, error) {
+	if opts == nil || opts.DisplayName == "" {
+		return nil, fmt.Errorf("team display name is required")
+	}
+
+	template := opts.Template
+	if template == "" {
+		template = "standard"
+	}
+
+	body := map[string]interface{}{
+		"template@odata.bind": fmt.Sprintf("%s/teamsTemplates('%s')", GraphAPIBaseURL, template),
+		"displayName":         opts.DisplayName,
+	}
+	if opts.Description != "" {
+		body["description"] = opts.Description
+	}
+	if opts.Visibility != "" {
+		body["visibility"] = opts.Visibility
+	}
+
+	_, headers, err := c.doJSONRequestWithHeaders(ctx, "POST", "/teams", body)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTeamsAsyncOperation(headers.Get("Location"), headers.Get("Content-Location")), nil
+}
+
+// ArchiveTeam archives a team. Archiving is asynchronous; the returned
+// operation can be polled with WaitForTeamsOperation.
+func (c *Client) ArchiveTeam(ctx context.Context, teamID string, readOnlySite bool) (*TeamsAsyncOperation, error) {
+	if teamID == "" {
+		return nil, fmt.Errorf("team ID is required")
+	}
+
+	body := map[string]interface{}{
+		"shouldSetSpoSiteReadOnlyForMembers": readOnlySite,
+	}
+
+	_, headers, err := c.doJSONRequestWithHeaders(ctx, "POST", fmt.Sprintf("/teams/%s/archive", teamID), body)
+	if err != nil {
+		return nil, err
+	}
+
+	op := newTeamsAsyncOperation(headers.Get("Location"), "")
+	op.TargetResourceID = teamID
+	return op, nil
+}
+
+// UnarchiveTeam restores an archived team. Unarchiving is asynchronous; the
+// returned operation can be polled with WaitForTeamsOperation.
+func (c *Client) UnarchiveTeam(ctx context.Context, teamID string) (*TeamsAsyncOperation, error) {
+	if teamID == "" {
+		return nil, fmt.Errorf("team ID is required")
+	}
+
+	_, headers, err := c.doJSONRequestWithHeaders(ctx, "POST", fmt.Sprintf("/teams/%s/unarchive", teamID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	op := newTeamsAsyncOperation(headers.Get("Location"), "")
+	op.TargetResourceID = teamID
+	return op, nil
+}
+
+// newTeamsAsyncOperation builds a pending operation from response headers
+func newTeamsAsyncOperation(location, contentLocation string) *TeamsAsyncOperation {
+	op := &TeamsAsyncOperation{
+		Status:   "notStarted",
+		Location: location,
+	}
+	for _, l := range []string{contentLocation, location} {
+		if m := teamIDFromLocation.FindStringSubmatch(l); m != nil {
+			op.TargetResourceID = m[1]
+			break
+		}
+	}
+	return op
+}
+
+// GetTeamsOperation retrieves the current state of an async Teams operation
+func (c *Client) GetTeamsOperation(ctx context.Context, location string) (*TeamsAsyncOperation, error) {
+	if location == "" {
+		return nil, fmt.Errorf("operation location is required")
+	}
+
+	// Location may be absolute or relative to the Graph base URL
+	path := strings.TrimPrefix(location, GraphAPIBaseURL)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	// Polled repeatedly, so never served from the cache
+	data, err := c.uncached().Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var op TeamsAsyncOperation
+	if err := json.Unmarshal(data, &op); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal operation: %w", err)
+	}
+	op.Location = location
+
+	return &op, nil
+}
+
+// WaitForTeamsOperation polls an async Teams operation until it succeeds, fails,
+// or the context is cancelled. An interval of zero uses DefaultTeamsPollInterval.
+func (c *Client) WaitForTeamsOperation(ctx context.Context, op *TeamsAsyncOperation, interval time.Duration) (*TeamsAsyncOperation, error) {
+	if op == nil || op.Location == "" {
+		return nil, fmt.Errorf("operation location is required")
+	}
+	if interval <= 0 {
+		interval = DefaultTeamsPollInterval
+	}
+
+	for {
+		current, err := c.GetTeamsOperation(ctx, op.Location)
+		if err != nil {
+			return nil, err
+		}
+		if current.TargetResourceID == "" {
+			current.TargetResourceID = op.TargetResourceID
+		}
+
+		if current.IsDone() {
+			if current.Status == "failed" {
+				msg := "unknown error"
+				if current.Error != nil && current.Error.Message != "" {
+					msg = current.Error.Message
+				}
+				return current, fmt.Errorf("teams operation %s failed: %s", current.OperationType, msg)
+			}
+			return current, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return current, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// ListTeamMembers retrieves the members of a team
+func (c *Client) ListTeamMembers(ctx context.Context, teamID string) ([]*TeamMember, error) {
+	if teamID == "" {
+		return nil, fmt.Errorf("team ID is required")
+	}
+
+	data, err := c.Get(ctx, fmt.Sprintf("/teams/%s/members", teamID))
+	if err != nil {
+		return nil, err
+	}
+
+	var memberList TeamMemberList
+	if err := json.Unmarshal(data, &memberList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal members: %w", err)
+	}
+
+	return memberList.Value, nil
+}
+
+// AddTeamMember adds a user to a team
+func (c *Client) AddTeamMember(ctx context.Context, teamID string, opts *AddTeamMemberOptions) (*TeamMember, error) {
+	if teamID == "" {
+		return nil, fmt.Errorf("team ID is required")
+	}
+	if opts == nil || opts.UserID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	roles := []string{}
+	if opts.Owner {
+		roles = append(roles, "owner")
+	}
+
+	body := map[string]interface{}{
+		"@odata.type":     "#microsoft.graph.aadUserConversationMember",
+		"roles":           roles,
+		"user@odata.bind": fmt.Sprintf("%s/users('%s')", GraphAPIBaseURL, opts.UserID),
+	}
+
+	data, err := c.Post(ctx, fmt.Sprintf("/teams/%s/members", teamID), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var member TeamMember
+	if err := json.Unmarshal(data, &member); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal member: %w", err)
+	}
+
+	return &member, nil
+}
+
+// RemoveTeamMember removes a member from a team by membership ID
+func (c *Client) RemoveTeamMember(ctx context.Context, teamID, membershipID string) error {
+	if teamID == "" {
+		return fmt.Errorf("team ID is required")
+	}
+	if membershipID == "" {
+		return fmt.Errorf("membership ID is required")
+	}
+
+	return c.Delete(ctx, fmt.Sprintf("/teams/%s/members/%s", teamID, membershipID))
+}
+
+// FindTeamMember looks up a team membership by user ID, email, or membership ID
+func (c *Client) FindTeamMember(ctx context.Context, teamID, user string) (*TeamMember, error) {
+	members, err := c.ListTeamMembers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range members {
+		if m.ID == user || m.UserID == user || strings.EqualFold(m.Email, user) {
+			return m, nil
+		}
+	}
+
+	return nil, fmt.Errorf("user %s is not a member of team %s", user, teamID)
+}
+
+// TeamsAppInstallation represents an app installed in a team
+type TeamsAppInstallation struct {
+	ID                 string              `json:"id,omitempty"` // Installation ID (not the app ID)
+	TeamsAppDefinition *TeamsAppDefinition `json:"teamsAppDefinition,omitempty"`
+}
+
+// TeamsAppDefinition represents a version of a Teams app
+type TeamsAppDefinition struct {
+	ID          string `json:"id,omitempty"`
+	TeamsAppID  string `json:"teamsAppId,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// TeamsAppInstallationList represents a list of app installations returned by Graph API
+type TeamsAppInstallationList struct {
+	Value    []*TeamsAppInstallation `json:"value"`
+	NextLink string                  `json:"@odata.nextLink,omitempty"`
+}
+
+// ListTeamApps retrieves the apps installed in a team, with their app definitions
+func (c *Client) ListTeamApps(ctx context.Context, teamID string) ([]*TeamsAppInstallation, error) {
+	if teamID == "" {
+		return nil, fmt.Errorf("team ID is required")
+	}
+
+	data, err := c.Get(ctx, fmt.Sprintf("/teams/%s/installedApps?$expand=teamsAppDefinition", teamID))
+	if err != nil {
+		return nil, err
+	}
+
+	var appList TeamsAppInstallationList
+	if err := json.Unmarshal(data, &appList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal installed apps: %w", err)
+	}
+
+	return appList.Value, nil
+}
+
+// InstallTeamApp installs an app from the app catalog into a team
+func (c *Client) InstallTeamApp(ctx context.Context, teamID, appID string) error {
+	if teamID == "" {
+		return fmt.Errorf("team ID is required")
+	}
+	if appID == "" {
+		return fmt.Errorf("app ID is required")
+	}
+
+	body := map[string]interface{}{
+		"teamsApp@odata.bind": fmt.Sprintf("%s/appCatalogs/teamsApps/%s", GraphAPIBaseURL, appID),
+	}
+
+	_, err := c.Post(ctx, fmt.Sprintf("/teams/%s/installedApps", teamID), body)
+	return err
+}
+
+// RemoveTeamApp uninstalls an app from a team by installation ID
+func (c *Client) RemoveTeamApp(ctx context.Context, teamID, installationID string) error {
+	if teamID == "" {
+		return fmt.Errorf("team ID is required")
+	}
+	if installationID == "" {
+		return fmt.Errorf("installation ID is required")
+	}
+
+	return c.Delete(ctx, fmt.Sprintf("/teams/%s/installedApps/%s", teamID, installationID))
+}
+
+// FindTeamApp looks up an app installation by app ID, installation ID, or display name
+// (case-insensitive)
+func (c *Client) FindTeamApp(ctx context.Context, teamID, app string) (*TeamsAppInstallation, error) {
+	apps, err := c.ListTeamApps(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range apps {
+		if a.ID == app {
+			return a, nil
+		}
+		if def := a.TeamsAppDefinition; def != nil && (def.TeamsAppID == app || strings.EqualFold(def.DisplayName, app)) {
+			return a, nil
+		}
+	}
+
+	return nil, fmt.Errorf("app %s is not installed in team %s", app, teamID)
+}
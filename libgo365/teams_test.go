@@ -0,0 +1,300 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListTeams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/joinedTeams" {
+			t.Errorf("Expected path /me/joinedTeams, got %s", r.URL.Path)
+		}
+
+		json.NewEncoder(w).Encode(TeamList{
+			Value: []*Team{
+				{ID: "team1", DisplayName: "Engineering"},
+				{ID: "team2", DisplayName: "Marketing", IsArchived: true},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	teams, err := client.ListTeams(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTeams failed: %v", err)
+	}
+
+	if len(teams) != 2 {
+		t.Fatalf("Expected 2 teams, got %d", len(teams))
+	}
+	if teams[0].DisplayName != "Engineering" {
+		t.Errorf("Expected 'Engineering', got '%s'", teams[0].DisplayName)
+	}
+	if !teams[1].IsArchived {
+		t.Error("Expected second team to be archived")
+	}
+}
+
+func TestCreateTeam(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/teams" {
+			t.Errorf("Expected path /teams, got %s", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+		if body["displayName"] != "Project X" {
+			t.Errorf("Expected displayName 'Project X', got %v", body["displayName"])
+		}
+		bind, _ := body["template@odata.bind"].(string)
+		if !strings.HasSuffix(bind, "teamsTemplates('standard')") {
+			t.Errorf("Expected standard template binding, got %s", bind)
+		}
+
+		w.Header().Set("Location", "/teams('team123')/operations('op456')")
+		w.Header().Set("Content-Location", "/teams('team123')")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	op, err := client.CreateTeam(context.Background(), &CreateTeamOptions{DisplayName: "Project X"})
+	if err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	if op.TargetResourceID != "team123" {
+		t.Errorf("Expected target team123, got %s", op.TargetResourceID)
+	}
+	if op.Location != "/teams('team123')/operations('op456')" {
+		t.Errorf("Unexpected location: %s", op.Location)
+	}
+}
+
+func TestCreateTeamMissingName(t *testing.T) {
+	client := &Client{
+		httpClient: &http.Client{},
+		baseURL:    "http://localhost",
+		tokens:     StaticToken("test-token"),
+	}
+
+	if _, err := client.CreateTeam(context.Background(), &CreateTeamOptions{}); err == nil {
+		t.Error("Expected error for missing display name")
+	}
+}
+
+func TestWaitForTeamsOperation(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "inProgress"
+		if polls >= 2 {
+			status = "succeeded"
+		}
+		json.NewEncoder(w).Encode(TeamsAsyncOperation{
+			ID:               "op456",
+			OperationType:    "createTeam",
+			Status:           status,
+			TargetResourceID: "team123",
+		})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	op := &TeamsAsyncOperation{Location: "/teams('team123')/operations('op456')"}
+	done, err := client.WaitForTeamsOperation(context.Background(), op, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForTeamsOperation failed: %v", err)
+	}
+
+	if done.Status != "succeeded" {
+		t.Errorf("Expected succeeded, got %s", done.Status)
+	}
+	if polls != 2 {
+		t.Errorf("Expected 2 polls, got %d", polls)
+	}
+}
+
+func TestWaitForTeamsOperationFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(TeamsAsyncOperation{
+			OperationType: "archiveTeam",
+			Status:        "failed",
+			Error:         &TeamsAsyncOperationError{Code: "Conflict", Message: "already archived"},
+		})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	op := &TeamsAsyncOperation{Location: "/teams('team123')/operations('op456')"}
+	_, err := client.WaitForTeamsOperation(context.Background(), op, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "already archived") {
+		t.Errorf("Expected failure error, got %v", err)
+	}
+}
+
+func TestAddTeamMember(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/teams/team123/members" {
+			t.Errorf("Expected path /teams/team123/members, got %s", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["user@odata.bind"] != GraphAPIBaseURL+"/users('jane@example.com')" {
+			t.Errorf("Unexpected user binding: %v", body["user@odata.bind"])
+		}
+		roles, _ := body["roles"].([]interface{})
+		if len(roles) != 1 || roles[0] != "owner" {
+			t.Errorf("Expected owner role, got %v", body["roles"])
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(TeamMember{ID: "member1", DisplayName: "Jane", Roles: []string{"owner"}})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	member, err := client.AddTeamMember(context.Background(), "team123", &AddTeamMemberOptions{
+		UserID: "jane@example.com",
+		Owner:  true,
+	})
+	if err != nil {
+		t.Fatalf("AddTeamMember failed: %v", err)
+	}
+	if member.ID != "member1" {
+		t.Errorf("Expected member1, got %s", member.ID)
+	}
+}
+
+func TestFindTeamMember(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(TeamMemberList{
+			Value: []*TeamMember{
+				{ID: "member1", UserID: "user1", Email: "jane@example.com"},
+				{ID: "member2", UserID: "user2", Email: "bob@example.com"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	member, err := client.FindTeamMember(context.Background(), "team123", "BOB@example.com")
+	if err != nil {
+		t.Fatalf("FindTeamMember failed: %v", err)
+	}
+	if member.ID != "member2" {
+		t.Errorf("Expected member2, got %s", member.ID)
+	}
+
+	if _, err := client.FindTeamMember(context.Background(), "team123", "nobody@example.com"); err == nil {
+		t.Error("Expected error for non-member")
+	}
+}
+
+func TestTeamApps(t *testing.T) {
+	installed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/teams/team123/installedApps" {
+			t.Errorf("Expected path /teams/team123/installedApps, got %s", r.URL.Path)
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("$expand") != "teamsAppDefinition" {
+				t.Errorf("Expected teamsAppDefinition expansion, got %s", r.URL.Query().Get("$expand"))
+			}
+			w.Write([]byte(`{"value": [
+				{"id": "inst1", "teamsAppDefinition": {"teamsAppId": "app1", "displayName": "Planner", "version": "1.0"}},
+				{"id": "inst2", "teamsAppDefinition": {"teamsAppId": "app2", "displayName": "Wiki", "version": "2.1"}}
+			]}`))
+		case http.MethodPost:
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["teamsApp@odata.bind"] != GraphAPIBaseURL+"/appCatalogs/teamsApps/app3" {
+				t.Errorf("Unexpected app binding: %v", body["teamsApp@odata.bind"])
+			}
+			installed = true
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		tokens:     StaticToken("test-token"),
+	}
+
+	tests := []struct {
+		input  string
+		wantID string
+	}{
+		{"inst1", "inst1"},
+		{"app2", "inst2"},
+		{"planner", "inst1"},
+	}
+	for _, tt := range tests {
+		app, err := client.FindTeamApp(context.Background(), "team123", tt.input)
+		if err != nil {
+			t.Errorf("FindTeamApp(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if app.ID != tt.wantID {
+			t.Errorf("FindTeamApp(%q) = %s, want %s", tt.input, app.ID, tt.wantID)
+		}
+	}
+
+	if _, err := client.FindTeamApp(context.Background(), "team123", "app9"); err == nil {
+		t.Error("Expected error for app that is not installed")
+	}
+
+	if err := client.InstallTeamApp(context.Background(), "team123", "app3"); err != nil {
+		t.Fatalf("InstallTeamApp failed: %v", err)
+	}
+	if !installed {
+		t.Error("Expected install request")
+	}
+}
@@ -0,0 +1,82 @@
+package libgo365
+
+import (
+	"fmt"
+	"time"
+)
+
+// windowsZones maps the Windows time zone names Graph often reports (in
+// mailboxSettings and event start/end) to IANA names, covering the zones in
+// common use. See the CLDR windowsZones table for the full list.
+var windowsZones = map[string]string{
+	"UTC":                             "UTC",
+	"GMT Standard Time":               "Europe/London",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"Romance Standard Time":           "Europe/Paris",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"FLE Standard Time":               "Europe/Kiev",
+	"GTB Standard Time":               "Europe/Bucharest",
+	"Russian Standard Time":           "Europe/Moscow",
+	"Turkey Standard Time":            "Europe/Istanbul",
+	"Israel Standard Time":            "Asia/Jerusalem",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"Egypt Standard Time":             "Africa/Cairo",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"Arab Standard Time":              "Asia/Riyadh",
+	"Iran Standard Time":              "Asia/Tehran",
+	"Pakistan Standard Time":          "Asia/Karachi",
+	"India Standard Time":             "Asia/Kolkata",
+	"Nepal Standard Time":             "Asia/Kathmandu",
+	"Bangladesh Standard Time":        "Asia/Dhaka",
+	"SE Asia Standard Time":           "Asia/Bangkok",
+	"China Standard Time":             "Asia/Shanghai",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"Taipei Standard Time":            "Asia/Taipei",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"Korea Standard Time":             "Asia/Seoul",
+	"W. Australia Standard Time":      "Australia/Perth",
+	"Cen. Australia Standard Time":    "Australia/Adelaide",
+	"AUS Central Standard Time":       "Australia/Darwin",
+	"E. Australia Standard Time":      "Australia/Brisbane",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"Tasmania Standard Time":          "Australia/Hobart",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+	"Fiji Standard Time":              "Pacific/Fiji",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Mountain Standard Time":          "America/Denver",
+	"Central Standard Time":           "America/Chicago",
+	"Canada Central Standard Time":    "America/Regina",
+	"Central America Standard Time":   "America/Guatemala",
+	"Central Standard Time (Mexico)":  "America/Mexico_City",
+	"Eastern Standard Time":           "America/New_York",
+	"US Eastern Standard Time":        "America/Indianapolis",
+	"SA Pacific Standard Time":        "America/Bogota",
+	"Atlantic Standard Time":          "America/Halifax",
+	"Newfoundland Standard Time":      "America/St_Johns",
+	"SA Western Standard Time":        "America/La_Paz",
+	"Pacific SA Standard Time":        "America/Santiago",
+	"Argentina Standard Time":         "America/Buenos_Aires",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"Venezuela Standard Time":         "America/Caracas",
+	"Morocco Standard Time":           "Africa/Casablanca",
+	"W. Central Africa Standard Time": "Africa/Lagos",
+	"E. Africa Standard Time":         "Africa/Nairobi",
+}
+
+// LoadLocation loads a time zone given as an IANA name or as one of the
+// Windows names Graph returns for mailboxes configured in Outlook
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc, nil
+	}
+	if iana, ok := windowsZones[name]; ok {
+		return time.LoadLocation(iana)
+	}
+	return nil, fmt.Errorf("unknown time zone %q", name)
+}
@@ -0,0 +1,30 @@
+package libgo365
+
+import "testing"
+
+func TestLoadLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Pacific/Auckland", "Pacific/Auckland"},
+		{"UTC", "UTC"},
+		{"New Zealand Standard Time", "Pacific/Auckland"},
+		{"Pacific Standard Time", "America/Los_Angeles"},
+	}
+
+	for _, tt := range tests {
+		loc, err := LoadLocation(tt.name)
+		if err != nil {
+			t.Errorf("LoadLocation(%q) failed: %v", tt.name, err)
+			continue
+		}
+		if loc.String() != tt.want {
+			t.Errorf("LoadLocation(%q) = %s, want %s", tt.name, loc, tt.want)
+		}
+	}
+
+	if _, err := LoadLocation("Nowhere Standard Time"); err == nil {
+		t.Error("Expected an error for an unknown zone")
+	}
+}
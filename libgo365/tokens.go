@@ -0,0 +1,148 @@
+package libgo365
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenRefreshMargin is how long before expiry a cached token is replaced,
+// so that a request never leaves with a token about to run out
+const tokenRefreshMargin = 5 * time.Minute
+
+// TokenProvider supplies the access token for each request a Client sends.
+// Implementations must be safe for concurrent use.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenProvider that always returns the same token, for
+// short-lived programs and tokens obtained elsewhere
+type StaticToken string
+
+// Token returns the token
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// AccessToken is an access token and when it expires
+type AccessToken struct {
+	Token     string    `json:"token"`
+	ExpiresOn time.Time `json:"expiresOn"`
+}
+
+// TokenClaims summarizes what an access token says about who it was issued
+// to and what it allows
+type TokenClaims struct {
+	Audience  string    `json:"audience,omitempty"`
+	TenantID  string    `json:"tenantId,omitempty"`
+	ObjectID  string    `json:"objectId,omitempty"`
+	AppID     string    `json:"appId,omitempty"`
+	AppName   string    `json:"appName,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	Scopes    []string  `json:"scopes,omitempty"` // Delegated permissions (scp)
+	Roles     []string  `json:"roles,omitempty"`  // Application permissions or directory roles
+	IssuedAt  time.Time `json:"issuedAt,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+// AppOnly reports whether the token was issued to an app acting as itself,
+// which has roles but no delegated scopes
+func (c *TokenClaims) AppOnly() bool {
+	return len(c.Scopes) == 0
+}
+
+// ParseTokenClaims decodes the claims of a JWT access token without
+// verifying it; Graph does that, and the claims are only for display.
+// Tokens for personal Microsoft accounts are opaque and return an error.
+func ParseTokenClaims(token string) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("access token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode access token: %w", err)
+	}
+
+	var raw struct {
+		Aud               string   `json:"aud"`
+		Tid               string   `json:"tid"`
+		Oid               string   `json:"oid"`
+		AppID             string   `json:"appid"`
+		Azp               string   `json:"azp"`
+		AppDisplayName    string   `json:"app_displayname"`
+		UPN               string   `json:"upn"`
+		UniqueName        string   `json:"unique_name"`
+		PreferredUsername string   `json:"preferred_username"`
+		Scp               string   `json:"scp"`
+		Roles             []string `json:"roles"`
+		Iat               int64    `json:"iat"`
+		Exp               int64    `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode access token: %w", err)
+	}
+
+	claims := &TokenClaims{
+		Audience: raw.Aud,
+		TenantID: raw.Tid,
+		ObjectID: raw.Oid,
+		AppID:    cmp.Or(raw.AppID, raw.Azp),
+		AppName:  raw.AppDisplayName,
+		Username: cmp.Or(raw.UPN, raw.UniqueName, raw.PreferredUsername),
+		Scopes:   strings.Fields(raw.Scp),
+		Roles:    raw.Roles,
+	}
+	if raw.Iat != 0 {
+		claims.IssuedAt = time.Unix(raw.Iat, 0)
+	}
+	if raw.Exp != 0 {
+		claims.ExpiresAt = time.Unix(raw.Exp, 0)
+	}
+	return claims, nil
+}
+
+// cachedToken holds the last token an Authenticator acquired, so that
+// requests don't each go through MSAL and the token store
+type cachedToken struct {
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// get returns the cached token, calling acquire for a new one when there is
+// none or it expires within tokenRefreshMargin
+func (c *cachedToken) get(acquire func() (string, time.Time, error)) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Until(c.expires) > tokenRefreshMargin {
+		return c.token, nil
+	}
+	token, expires, err := acquire()
+	if err != nil {
+		return "", err
+	}
+	c.token, c.expires = token, expires
+	return token, nil
+}
+
+// expiresOn returns when the cached token expires, or zero if there is none
+func (c *cachedToken) expiresOn() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.expires
+}
+
+// clear forgets the cached token
+func (c *cachedToken) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token, c.expires = "", time.Time{}
+}
@@ -0,0 +1,132 @@
+package libgo365
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// countingTokens hands out a new token on every call
+type countingTokens struct {
+	calls int
+	err   error
+}
+
+func (c *countingTokens) Token(ctx context.Context) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	c.calls++
+	return fmt.Sprintf("token-%d", c.calls), nil
+}
+
+func TestClientAsksTokenProviderPerRequest(t *testing.T) {
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tokens := &countingTokens{}
+	client := NewClientWithTokenProvider(context.Background(), tokens)
+	client.baseURL = server.URL
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(context.Background(), "/me"); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+	}
+	if len(seen) != 2 || seen[0] != "Bearer token-1" || seen[1] != "Bearer token-2" {
+		t.Errorf("Expected a fresh token per request, got %v", seen)
+	}
+}
+
+func TestClientTokenProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Request sent without a token")
+	}))
+	defer server.Close()
+
+	failure := errors.New("refresh token expired")
+	client := NewClientWithTokenProvider(context.Background(), &countingTokens{err: failure})
+	client.baseURL = server.URL
+
+	if _, err := client.Get(context.Background(), "/me"); !errors.Is(err, failure) {
+		t.Errorf("Expected the provider's error from Get, got %v", err)
+	}
+	if _, err := client.Post(context.Background(), "/me/sendMail", map[string]string{}); !errors.Is(err, failure) {
+		t.Errorf("Expected the provider's error from Post, got %v", err)
+	}
+	if err := client.Delete(context.Background(), "/me/messages/1"); !errors.Is(err, failure) {
+		t.Errorf("Expected the provider's error from Delete, got %v", err)
+	}
+}
+
+func TestCachedToken(t *testing.T) {
+	var c cachedToken
+	acquired := 0
+	acquire := func(expires time.Time) func() (string, time.Time, error) {
+		return func() (string, time.Time, error) {
+			acquired++
+			return fmt.Sprintf("token-%d", acquired), expires, nil
+		}
+	}
+
+	later := time.Now().Add(time.Hour)
+	if token, _ := c.get(acquire(later)); token != "token-1" {
+		t.Errorf("Expected token-1, got %s", token)
+	}
+	if token, _ := c.get(acquire(later)); token != "token-1" {
+		t.Errorf("Expected the cached token-1, got %s", token)
+	}
+
+	// A token inside the refresh margin is replaced
+	c.expires = time.Now().Add(time.Minute)
+	if token, _ := c.get(acquire(later)); token != "token-2" {
+		t.Errorf("Expected token-2 near expiry, got %s", token)
+	}
+
+	c.clear()
+	if token, _ := c.get(acquire(later)); token != "token-3" {
+		t.Errorf("Expected token-3 after clear, got %s", token)
+	}
+
+	failure := errors.New("offline")
+	c.clear()
+	if _, err := c.get(func() (string, time.Time, error) { return "", time.Time{}, failure }); !errors.Is(err, failure) {
+		t.Errorf("Expected the acquire error, got %v", err)
+	}
+}
+
+func TestParseTokenClaims(t *testing.T) {
+	payload := `{"aud":"https://graph.microsoft.com","tid":"tenant-id","oid":"object-id","appid":"app-id",` +
+		`"app_displayname":"go365","upn":"alex@contoso.com","scp":"Mail.Read User.Read","iat":1750000000,"exp":1750003600}`
+	claims, err := ParseTokenClaims("eyJ0eXAiOiJKV1QifQ." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig")
+	if err != nil {
+		t.Fatalf("ParseTokenClaims failed: %v", err)
+	}
+	if claims.Username != "alex@contoso.com" || claims.TenantID != "tenant-id" || claims.AppID != "app-id" || claims.AppName != "go365" {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+	if len(claims.Scopes) != 2 || claims.Scopes[1] != "User.Read" || claims.AppOnly() {
+		t.Errorf("Expected two delegated scopes, got %v", claims.Scopes)
+	}
+	if claims.ExpiresAt.Sub(claims.IssuedAt) != time.Hour {
+		t.Errorf("Expected an hour-long token, got %s to %s", claims.IssuedAt, claims.ExpiresAt)
+	}
+
+	app, err := ParseTokenClaims("e30." + base64.RawURLEncoding.EncodeToString([]byte(`{"azp":"app-id","roles":["Mail.Read"]}`)) + ".")
+	if err != nil || !app.AppOnly() || app.AppID != "app-id" || len(app.Roles) != 1 {
+		t.Errorf("Expected app-only claims, got %+v (%v)", app, err)
+	}
+
+	if _, err := ParseTokenClaims("EwBwA8l6BAAU-opaque"); err == nil {
+		t.Error("Expected an opaque token to be rejected")
+	}
+}
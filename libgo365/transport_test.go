@@ -0,0 +1,86 @@
+package libgo365
+
+import (
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewTransportProxy(t *testing.T) {
+	transport, err := NewTransport(&TransportOptions{ProxyURL: "http://proxy.contoso.com:8080", ResponseTimeout: time.Minute})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	req, _ := http.NewRequest("GET", GraphAPIBaseURL+"/me", nil)
+	proxy, err := transport.Proxy(req)
+	if err != nil || proxy == nil || proxy.Host != "proxy.contoso.com:8080" {
+		t.Errorf("Expected requests to go through the proxy, got %v (%v)", proxy, err)
+	}
+	if transport.ResponseHeaderTimeout != time.Minute {
+		t.Errorf("Expected a one minute response timeout, got %s", transport.ResponseHeaderTimeout)
+	}
+
+	for _, opts := range []*TransportOptions{{ProxyURL: "proxy"}, {MinTLSVersion: "1.1"}, {CAFile: "missing.pem"}} {
+		if _, err := NewTransport(opts); err == nil {
+			t.Errorf("Expected %+v to be rejected", opts)
+		}
+	}
+}
+
+func TestNewTransportCAFile(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // The untrusted handshake below fails
+	server.StartTLS()
+	defer server.Close()
+
+	// The test server's certificate stands in for a TLS-inspecting proxy's
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	plain, _ := NewTransport(nil)
+	if _, err := (&http.Client{Transport: plain}).Get(server.URL); err == nil {
+		t.Fatal("Expected the test certificate to be untrusted by default")
+	}
+
+	transport, err := NewTransport(&TransportOptions{CAFile: caFile, MinTLSVersion: "1.2"})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected the CA file to be trusted, got %v", err)
+	}
+	resp.Body.Close()
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, []byte("not a certificate"), 0600)
+	if _, err := NewTransport(&TransportOptions{CAFile: empty}); err == nil {
+		t.Error("Expected a file without certificates to be rejected")
+	}
+}
+
+func TestConfigTransportOptions(t *testing.T) {
+	config := &Config{ProxyURL: "http://proxy:3128", DialTimeout: "10s", ResponseTimeout: "2m"}
+	opts, err := config.TransportOptions()
+	if err != nil {
+		t.Fatalf("TransportOptions failed: %v", err)
+	}
+	if opts.ProxyURL != "http://proxy:3128" || opts.DialTimeout != 10*time.Second || opts.ResponseTimeout != 2*time.Minute {
+		t.Errorf("Unexpected options %+v", opts)
+	}
+
+	if _, err := (&Config{DialTimeout: "ten seconds"}).TransportOptions(); err == nil {
+		t.Error("Expected an invalid timeout to be rejected")
+	}
+}